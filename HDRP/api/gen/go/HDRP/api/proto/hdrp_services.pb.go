@@ -932,14 +932,16 @@ const file_HDRP_api_proto_hdrp_services_proto_rawDesc = "" +
 	"\x06report\x18\x01 \x01(\tR\x06report\x12!\n" +
 	"\fartifact_uri\x18\x02 \x01(\tR\vartifactUri2g\n" +
 	"\x10PrincipalService\x12S\n" +
-	"\x0eDecomposeQuery\x12\x1b.hdrp.services.QueryRequest\x1a$.hdrp.services.DecompositionResponse2`\n" +
+	"\x0eDecomposeQuery\x12\x1b.hdrp.services.QueryRequest\x1a$.hdrp.services.DecompositionResponse2\xb5\x01\n" +
 	"\x11ResearcherService\x12K\n" +
-	"\bResearch\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse2V\n" +
+	"\bResearch\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse\x12S\n" +
+	"\x0eResearchStream\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse0\x012V\n" +
 	"\rCriticService\x12E\n" +
-	"\x06Verify\x12\x1c.hdrp.services.VerifyRequest\x1a\x1d.hdrp.services.VerifyResponse2g\n" +
+	"\x06Verify\x12\x1c.hdrp.services.VerifyRequest\x1a\x1d.hdrp.services.VerifyResponse2\xc2\x01\n" +
 	"\x12SynthesizerService\x12Q\n" +
 	"\n" +
-	"Synthesize\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponseB*Z(github.com/deepdag/hdrp/api/gen/servicesb\x06proto3"
+	"Synthesize\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse\x12Y\n" +
+	"\x10SynthesizeStream\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse0\x01B*Z(github.com/deepdag/hdrp/api/gen/servicesb\x06proto3"
 
 var (
 	file_HDRP_api_proto_hdrp_services_proto_rawDescOnce sync.Once
@@ -990,14 +992,18 @@ var file_HDRP_api_proto_hdrp_services_proto_depIdxs = []int32{
 	17, // 12: hdrp.services.SynthesizeRequest.context:type_name -> hdrp.services.SynthesizeRequest.ContextEntry
 	0,  // 13: hdrp.services.PrincipalService.DecomposeQuery:input_type -> hdrp.services.QueryRequest
 	5,  // 14: hdrp.services.ResearcherService.Research:input_type -> hdrp.services.ResearchRequest
-	8,  // 15: hdrp.services.CriticService.Verify:input_type -> hdrp.services.VerifyRequest
-	11, // 16: hdrp.services.SynthesizerService.Synthesize:input_type -> hdrp.services.SynthesizeRequest
-	1,  // 17: hdrp.services.PrincipalService.DecomposeQuery:output_type -> hdrp.services.DecompositionResponse
-	6,  // 18: hdrp.services.ResearcherService.Research:output_type -> hdrp.services.ResearchResponse
-	9,  // 19: hdrp.services.CriticService.Verify:output_type -> hdrp.services.VerifyResponse
-	12, // 20: hdrp.services.SynthesizerService.Synthesize:output_type -> hdrp.services.SynthesizeResponse
-	17, // [17:21] is the sub-list for method output_type
-	13, // [13:17] is the sub-list for method input_type
+	5,  // 15: hdrp.services.ResearcherService.ResearchStream:input_type -> hdrp.services.ResearchRequest
+	8,  // 16: hdrp.services.CriticService.Verify:input_type -> hdrp.services.VerifyRequest
+	11, // 17: hdrp.services.SynthesizerService.Synthesize:input_type -> hdrp.services.SynthesizeRequest
+	11, // 18: hdrp.services.SynthesizerService.SynthesizeStream:input_type -> hdrp.services.SynthesizeRequest
+	1,  // 19: hdrp.services.PrincipalService.DecomposeQuery:output_type -> hdrp.services.DecompositionResponse
+	6,  // 20: hdrp.services.ResearcherService.Research:output_type -> hdrp.services.ResearchResponse
+	6,  // 21: hdrp.services.ResearcherService.ResearchStream:output_type -> hdrp.services.ResearchResponse
+	9,  // 22: hdrp.services.CriticService.Verify:output_type -> hdrp.services.VerifyResponse
+	12, // 23: hdrp.services.SynthesizerService.Synthesize:output_type -> hdrp.services.SynthesizeResponse
+	12, // 24: hdrp.services.SynthesizerService.SynthesizeStream:output_type -> hdrp.services.SynthesizeResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
 	13, // [13:13] is the sub-list for extension type_name
 	13, // [13:13] is the sub-list for extension extendee
 	0,  // [0:13] is the sub-list for field type_name
@@ -121,7 +121,8 @@ var PrincipalService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	ResearcherService_Research_FullMethodName = "/hdrp.services.ResearcherService/Research"
+	ResearcherService_Research_FullMethodName       = "/hdrp.services.ResearcherService/Research"
+	ResearcherService_ResearchStream_FullMethodName = "/hdrp.services.ResearcherService/ResearchStream"
 )
 
 // ResearcherServiceClient is the client API for ResearcherService service.
@@ -129,6 +130,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ResearcherServiceClient interface {
 	Research(ctx context.Context, in *ResearchRequest, opts ...grpc.CallOption) (*ResearchResponse, error)
+	ResearchStream(ctx context.Context, in *ResearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ResearchResponse], error)
 }
 
 type researcherServiceClient struct {
@@ -149,11 +151,31 @@ func (c *researcherServiceClient) Research(ctx context.Context, in *ResearchRequ
 	return out, nil
 }
 
+func (c *researcherServiceClient) ResearchStream(ctx context.Context, in *ResearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ResearchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ResearcherService_ServiceDesc.Streams[0], ResearcherService_ResearchStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ResearchRequest, ResearchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ResearcherService_ResearchStreamClient = grpc.ServerStreamingClient[ResearchResponse]
+
 // ResearcherServiceServer is the server API for ResearcherService service.
 // All implementations must embed UnimplementedResearcherServiceServer
 // for forward compatibility.
 type ResearcherServiceServer interface {
 	Research(context.Context, *ResearchRequest) (*ResearchResponse, error)
+	ResearchStream(*ResearchRequest, grpc.ServerStreamingServer[ResearchResponse]) error
 	mustEmbedUnimplementedResearcherServiceServer()
 }
 
@@ -167,6 +189,9 @@ type UnimplementedResearcherServiceServer struct{}
 func (UnimplementedResearcherServiceServer) Research(context.Context, *ResearchRequest) (*ResearchResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Research not implemented")
 }
+func (UnimplementedResearcherServiceServer) ResearchStream(*ResearchRequest, grpc.ServerStreamingServer[ResearchResponse]) error {
+	return status.Error(codes.Unimplemented, "method ResearchStream not implemented")
+}
 func (UnimplementedResearcherServiceServer) mustEmbedUnimplementedResearcherServiceServer() {}
 func (UnimplementedResearcherServiceServer) testEmbeddedByValue()                           {}
 
@@ -206,6 +231,17 @@ func _ResearcherService_Research_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResearcherService_ResearchStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ResearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ResearcherServiceServer).ResearchStream(m, &grpc.GenericServerStream[ResearchRequest, ResearchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ResearcherService_ResearchStreamServer = grpc.ServerStreamingServer[ResearchResponse]
+
 // ResearcherService_ServiceDesc is the grpc.ServiceDesc for ResearcherService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -218,7 +254,13 @@ var ResearcherService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ResearcherService_Research_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ResearchStream",
+			Handler:       _ResearcherService_ResearchStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "HDRP/api/proto/hdrp_services.proto",
 }
 
@@ -325,7 +367,8 @@ var CriticService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	SynthesizerService_Synthesize_FullMethodName = "/hdrp.services.SynthesizerService/Synthesize"
+	SynthesizerService_Synthesize_FullMethodName       = "/hdrp.services.SynthesizerService/Synthesize"
+	SynthesizerService_SynthesizeStream_FullMethodName = "/hdrp.services.SynthesizerService/SynthesizeStream"
 )
 
 // SynthesizerServiceClient is the client API for SynthesizerService service.
@@ -333,6 +376,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SynthesizerServiceClient interface {
 	Synthesize(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (*SynthesizeResponse, error)
+	SynthesizeStream(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SynthesizeResponse], error)
 }
 
 type synthesizerServiceClient struct {
@@ -353,11 +397,31 @@ func (c *synthesizerServiceClient) Synthesize(ctx context.Context, in *Synthesiz
 	return out, nil
 }
 
+func (c *synthesizerServiceClient) SynthesizeStream(ctx context.Context, in *SynthesizeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SynthesizeResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SynthesizerService_ServiceDesc.Streams[0], SynthesizerService_SynthesizeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SynthesizeRequest, SynthesizeResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SynthesizerService_SynthesizeStreamClient = grpc.ServerStreamingClient[SynthesizeResponse]
+
 // SynthesizerServiceServer is the server API for SynthesizerService service.
 // All implementations must embed UnimplementedSynthesizerServiceServer
 // for forward compatibility.
 type SynthesizerServiceServer interface {
 	Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error)
+	SynthesizeStream(*SynthesizeRequest, grpc.ServerStreamingServer[SynthesizeResponse]) error
 	mustEmbedUnimplementedSynthesizerServiceServer()
 }
 
@@ -371,6 +435,9 @@ type UnimplementedSynthesizerServiceServer struct{}
 func (UnimplementedSynthesizerServiceServer) Synthesize(context.Context, *SynthesizeRequest) (*SynthesizeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Synthesize not implemented")
 }
+func (UnimplementedSynthesizerServiceServer) SynthesizeStream(*SynthesizeRequest, grpc.ServerStreamingServer[SynthesizeResponse]) error {
+	return status.Error(codes.Unimplemented, "method SynthesizeStream not implemented")
+}
 func (UnimplementedSynthesizerServiceServer) mustEmbedUnimplementedSynthesizerServiceServer() {}
 func (UnimplementedSynthesizerServiceServer) testEmbeddedByValue()                            {}
 
@@ -410,6 +477,17 @@ func _SynthesizerService_Synthesize_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SynthesizerService_SynthesizeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SynthesizeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SynthesizerServiceServer).SynthesizeStream(m, &grpc.GenericServerStream[SynthesizeRequest, SynthesizeResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SynthesizerService_SynthesizeStreamServer = grpc.ServerStreamingServer[SynthesizeResponse]
+
 // SynthesizerService_ServiceDesc is the grpc.ServiceDesc for SynthesizerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -422,6 +500,12 @@ var SynthesizerService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _SynthesizerService_Synthesize_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SynthesizeStream",
+			Handler:       _SynthesizerService_SynthesizeStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "HDRP/api/proto/hdrp_services.proto",
 }
@@ -943,19 +943,21 @@ const file_hdrp_services_proto_rawDesc = "" +
 	"\fartifact_uri\x18\x02 \x01(\tR\vartifactUri2\xf3\x01\n" +
 	"\x10PrincipalService\x12\xde\x01\n" +
 	"\x0eDecomposeQuery\x12\x1b.hdrp.services.QueryRequest\x1a$.hdrp.services.DecompositionResponse\"\x88\x01\x92Am\n" +
-	"\tPrincipal\x12\x18Decompose Query into DAG\x1aFBreaks down a research query into a Directed Acyclic Graph of subtasks\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/decompose2\xd2\x01\n" +
+	"\tPrincipal\x12\x18Decompose Query into DAG\x1aFBreaks down a research query into a Directed Acyclic Graph of subtasks\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/decompose2\xa7\x02\n" +
 	"\x11ResearcherService\x12\xbc\x01\n" +
 	"\bResearch\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse\"o\x92AU\n" +
 	"\n" +
-	"Researcher\x12\x10Conduct Research\x1a5Extracts atomic claims from sources for a given query\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/research2\xc7\x01\n" +
+	"Researcher\x12\x10Conduct Research\x1a5Extracts atomic claims from sources for a given query\x82\xd3\xe4\x93\x02\x11:\x01*\"\f/v1/research\x12S\n" +
+	"\x0eResearchStream\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse0\x012\xc7\x01\n" +
 	"\rCriticService\x12\xb5\x01\n" +
 	"\x06Verify\x12\x1c.hdrp.services.VerifyRequest\x1a\x1d.hdrp.services.VerifyResponse\"n\x92AV\n" +
 	"\x06Critic\x12\rVerify Claims\x1a=Validates and critiques atomic claims extracted from research\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
-	"/v1/verify2\xde\x01\n" +
+	"/v1/verify2\xb9\x02\n" +
 	"\x12SynthesizerService\x12\xc7\x01\n" +
 	"\n" +
 	"Synthesize\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse\"t\x92AX\n" +
-	"\vSynthesizer\x12\x11Synthesize Report\x1a6Generates a final research report from verified claims\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/synthesizeB\x9a\x02\x92A{\x12Q\n" +
+	"\vSynthesizer\x12\x11Synthesize Report\x1a6Generates a final research report from verified claims\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/synthesize\x12Y\n" +
+	"\x10SynthesizeStream\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse0\x01B\x9a\x02\x92A{\x12Q\n" +
 	"\x11HDRP Services API\x127Hierarchical Deep Research Pipeline - Microservices API2\x031.0*\x02\x01\x022\x10application/json:\x10application/json\n" +
 	"\x11com.hdrp.servicesB\x11HdrpServicesProtoP\x01Z\x1fgithub.com/deepdag/hdrp/api/gen\xa2\x02\x03HSX\xaa\x02\rHdrp.Services\xca\x02\rHdrp\\Services\xe2\x02\x19Hdrp\\Services\\GPBMetadata\xea\x02\x0eHdrp::Servicesb\x06proto3"
 
@@ -1008,14 +1010,18 @@ var file_hdrp_services_proto_depIdxs = []int32{
 	17, // 12: hdrp.services.SynthesizeRequest.context:type_name -> hdrp.services.SynthesizeRequest.ContextEntry
 	0,  // 13: hdrp.services.PrincipalService.DecomposeQuery:input_type -> hdrp.services.QueryRequest
 	5,  // 14: hdrp.services.ResearcherService.Research:input_type -> hdrp.services.ResearchRequest
-	8,  // 15: hdrp.services.CriticService.Verify:input_type -> hdrp.services.VerifyRequest
-	11, // 16: hdrp.services.SynthesizerService.Synthesize:input_type -> hdrp.services.SynthesizeRequest
-	1,  // 17: hdrp.services.PrincipalService.DecomposeQuery:output_type -> hdrp.services.DecompositionResponse
-	6,  // 18: hdrp.services.ResearcherService.Research:output_type -> hdrp.services.ResearchResponse
-	9,  // 19: hdrp.services.CriticService.Verify:output_type -> hdrp.services.VerifyResponse
-	12, // 20: hdrp.services.SynthesizerService.Synthesize:output_type -> hdrp.services.SynthesizeResponse
-	17, // [17:21] is the sub-list for method output_type
-	13, // [13:17] is the sub-list for method input_type
+	5,  // 15: hdrp.services.ResearcherService.ResearchStream:input_type -> hdrp.services.ResearchRequest
+	8,  // 16: hdrp.services.CriticService.Verify:input_type -> hdrp.services.VerifyRequest
+	11, // 17: hdrp.services.SynthesizerService.Synthesize:input_type -> hdrp.services.SynthesizeRequest
+	11, // 18: hdrp.services.SynthesizerService.SynthesizeStream:input_type -> hdrp.services.SynthesizeRequest
+	1,  // 19: hdrp.services.PrincipalService.DecomposeQuery:output_type -> hdrp.services.DecompositionResponse
+	6,  // 20: hdrp.services.ResearcherService.Research:output_type -> hdrp.services.ResearchResponse
+	6,  // 21: hdrp.services.ResearcherService.ResearchStream:output_type -> hdrp.services.ResearchResponse
+	9,  // 22: hdrp.services.CriticService.Verify:output_type -> hdrp.services.VerifyResponse
+	12, // 23: hdrp.services.SynthesizerService.Synthesize:output_type -> hdrp.services.SynthesizeResponse
+	12, // 24: hdrp.services.SynthesizerService.SynthesizeStream:output_type -> hdrp.services.SynthesizeResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
 	13, // [13:13] is the sub-list for extension type_name
 	13, // [13:13] is the sub-list for extension extendee
 	0,  // [0:13] is the sub-list for field type_name
@@ -89,6 +89,29 @@ func local_request_ResearcherService_Research_0(ctx context.Context, marshaler r
 	return msg, metadata, err
 }
 
+func request_ResearcherService_ResearchStream_0(ctx context.Context, marshaler runtime.Marshaler, client ResearcherServiceClient, req *http.Request, pathParams map[string]string) (ResearcherService_ResearchStreamClient, runtime.ServerMetadata, error) {
+	var (
+		protoReq ResearchRequest
+		metadata runtime.ServerMetadata
+	)
+	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}
+	stream, err := client.ResearchStream(ctx, &protoReq)
+	if err != nil {
+		return nil, metadata, err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return nil, metadata, err
+	}
+	metadata.HeaderMD = header
+	return stream, metadata, nil
+}
+
 func request_CriticService_Verify_0(ctx context.Context, marshaler runtime.Marshaler, client CriticServiceClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
 	var (
 		protoReq VerifyRequest
@@ -143,6 +166,29 @@ func local_request_SynthesizerService_Synthesize_0(ctx context.Context, marshale
 	return msg, metadata, err
 }
 
+func request_SynthesizerService_SynthesizeStream_0(ctx context.Context, marshaler runtime.Marshaler, client SynthesizerServiceClient, req *http.Request, pathParams map[string]string) (SynthesizerService_SynthesizeStreamClient, runtime.ServerMetadata, error) {
+	var (
+		protoReq SynthesizeRequest
+		metadata runtime.ServerMetadata
+	)
+	if err := marshaler.NewDecoder(req.Body).Decode(&protoReq); err != nil && !errors.Is(err, io.EOF) {
+		return nil, metadata, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+	}
+	stream, err := client.SynthesizeStream(ctx, &protoReq)
+	if err != nil {
+		return nil, metadata, err
+	}
+	header, err := stream.Header()
+	if err != nil {
+		return nil, metadata, err
+	}
+	metadata.HeaderMD = header
+	return stream, metadata, nil
+}
+
 // RegisterPrincipalServiceHandlerServer registers the http handlers for service PrincipalService to "mux".
 // UnaryRPC     :call PrincipalServiceServer directly.
 // StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
@@ -200,6 +246,13 @@ func RegisterResearcherServiceHandlerServer(ctx context.Context, mux *runtime.Se
 		forward_ResearcherService_Research_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
 
+	mux.Handle(http.MethodPost, pattern_ResearcherService_ResearchStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+		return
+	})
+
 	return nil
 }
 
@@ -260,6 +313,13 @@ func RegisterSynthesizerServiceHandlerServer(ctx context.Context, mux *runtime.S
 		forward_SynthesizerService_Synthesize_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
 
+	mux.Handle(http.MethodPost, pattern_SynthesizerService_SynthesizeStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		err := status.Error(codes.Unimplemented, "streaming calls are not yet supported in the in-process transport")
+		_, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+		return
+	})
+
 	return nil
 }
 
@@ -380,15 +440,34 @@ func RegisterResearcherServiceHandlerClient(ctx context.Context, mux *runtime.Se
 		}
 		forward_ResearcherService_Research_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodPost, pattern_ResearcherService_ResearchStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/hdrp.services.ResearcherService/ResearchStream", runtime.WithHTTPPathPattern("/hdrp.services.ResearcherService/ResearchStream"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_ResearcherService_ResearchStream_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_ResearcherService_ResearchStream_0(annotatedContext, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+	})
 	return nil
 }
 
 var (
-	pattern_ResearcherService_Research_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "research"}, ""))
+	pattern_ResearcherService_Research_0       = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "research"}, ""))
+	pattern_ResearcherService_ResearchStream_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"hdrp.services.ResearcherService", "ResearchStream"}, ""))
 )
 
 var (
-	forward_ResearcherService_Research_0 = runtime.ForwardResponseMessage
+	forward_ResearcherService_Research_0       = runtime.ForwardResponseMessage
+	forward_ResearcherService_ResearchStream_0 = runtime.ForwardResponseStream
 )
 
 // RegisterCriticServiceHandlerFromEndpoint is same as RegisterCriticServiceHandler but
@@ -508,13 +587,32 @@ func RegisterSynthesizerServiceHandlerClient(ctx context.Context, mux *runtime.S
 		}
 		forward_SynthesizerService_Synthesize_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
 	})
+	mux.Handle(http.MethodPost, pattern_SynthesizerService_SynthesizeStream_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/hdrp.services.SynthesizerService/SynthesizeStream", runtime.WithHTTPPathPattern("/hdrp.services.SynthesizerService/SynthesizeStream"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_SynthesizerService_SynthesizeStream_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_SynthesizerService_SynthesizeStream_0(annotatedContext, mux, outboundMarshaler, w, req, func() (proto.Message, error) { return resp.Recv() }, mux.GetForwardResponseOptions()...)
+	})
 	return nil
 }
 
 var (
-	pattern_SynthesizerService_Synthesize_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "synthesize"}, ""))
+	pattern_SynthesizerService_Synthesize_0       = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "synthesize"}, ""))
+	pattern_SynthesizerService_SynthesizeStream_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"hdrp.services.SynthesizerService", "SynthesizeStream"}, ""))
 )
 
 var (
-	forward_SynthesizerService_Synthesize_0 = runtime.ForwardResponseMessage
+	forward_SynthesizerService_Synthesize_0       = runtime.ForwardResponseMessage
+	forward_SynthesizerService_SynthesizeStream_0 = runtime.ForwardResponseStream
 )
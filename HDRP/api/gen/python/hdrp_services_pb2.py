@@ -27,7 +27,7 @@ from google.api import annotations_pb2 as google_dot_api_dot_annotations__pb2
 from protoc_gen_openapiv2.options import annotations_pb2 as protoc__gen__openapiv2_dot_options_dot_annotations__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13hdrp_services.proto\x12\rhdrp.services\x1a\x1b\x62uf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xd6\x01\n\x0cQueryRequest\x12#\n\x05query\x18\x01 \x01(\tB\r\xbaH\nr\x05\x10\x01\x18\xf4\x03\xc8\x01\x01R\x05query\x12\x42\n\x07\x63ontext\x18\x02 \x03(\x0b\x32(.hdrp.services.QueryRequest.ContextEntryR\x07\x63ontext\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x1a:\n\x0c\x43ontextEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"_\n\x15\x44\x65\x63ompositionResponse\x12*\n\x05graph\x18\x01 \x01(\x0b\x32\x14.hdrp.services.GraphR\x05graph\x12\x1a\n\x08subtasks\x18\x02 \x03(\tR\x08subtasks\"\xea\x01\n\x05Graph\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12)\n\x05nodes\x18\x02 \x03(\x0b\x32\x13.hdrp.services.NodeR\x05nodes\x12)\n\x05\x65\x64ges\x18\x03 \x03(\x0b\x32\x13.hdrp.services.EdgeR\x05\x65\x64ges\x12>\n\x08metadata\x18\x04 \x03(\x0b\x32\".hdrp.services.Graph.MetadataEntryR\x08metadata\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\xf5\x01\n\x04Node\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n\x04type\x18\x02 \x01(\tR\x04type\x12\x37\n\x06\x63onfig\x18\x03 \x03(\x0b\x32\x1f.hdrp.services.Node.ConfigEntryR\x06\x63onfig\x12\x16\n\x06status\x18\x04 \x01(\tR\x06status\x12\'\n\x0frelevance_score\x18\x05 \x01(\x01R\x0erelevanceScore\x12\x14\n\x05\x64\x65pth\x18\x06 \x01(\x05R\x05\x64\x65pth\x1a\x39\n\x0b\x43onfigEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"*\n\x04\x45\x64ge\x12\x12\n\x04\x66rom\x18\x01 \x01(\tR\x04\x66rom\x12\x0e\n\x02to\x18\x02 \x01(\tR\x02to\"\x8a\x02\n\x0fResearchRequest\x12#\n\x05query\x18\x01 \x01(\tB\r\xbaH\nr\x05\x10\x01\x18\xf4\x03\xc8\x01\x01R\x05query\x12\x30\n\x0esource_node_id\x18\x02 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x0csourceNodeId\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x12\x42\n\x06\x63onfig\x18\x04 \x03(\x0b\x32*.hdrp.services.ResearchRequest.ConfigEntryR\x06\x63onfig\x1a\x39\n\x0b\x43onfigEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"k\n\x10ResearchResponse\x12\x32\n\x06\x63laims\x18\x01 \x03(\x0b\x32\x1a.hdrp.services.AtomicClaimR\x06\x63laims\x12#\n\rtotal_sources\x18\x02 \x01(\x05R\x0ctotalSources\"\x90\x02\n\x0b\x41tomicClaim\x12%\n\tstatement\x18\x01 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\tstatement\x12\x1d\n\nsource_url\x18\x02 \x01(\tR\tsourceUrl\x12!\n\x0csupport_text\x18\x03 \x01(\tR\x0bsupportText\x12-\n\x0esource_node_id\x18\x04 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\x0csourceNodeId\x12\x1c\n\ttimestamp\x18\x05 \x01(\tR\ttimestamp\x12!\n\x0csource_title\x18\x06 \x01(\tR\x0bsourceTitle\x12(\n\x0bsource_rank\x18\x07 \x01(\x05\x42\x07\xbaH\x04\x1a\x02(\x00R\nsourceRank\"\x8d\x01\n\rVerifyRequest\x12<\n\x06\x63laims\x18\x01 \x03(\x0b\x32\x1a.hdrp.services.AtomicClaimB\x08\xbaH\x05\x92\x01\x02\x08\x01R\x06\x63laims\x12\x1b\n\x04task\x18\x02 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\x04task\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\"\x97\x01\n\x0eVerifyResponse\x12\x37\n\x07results\x18\x01 \x03(\x0b\x32\x1d.hdrp.services.CritiqueResultR\x07results\x12%\n\x0everified_count\x18\x02 \x01(\x05R\rverifiedCount\x12%\n\x0erejected_count\x18\x03 \x01(\x05R\rrejectedCount\"\xb4\x01\n\x0e\x43ritiqueResult\x12\x30\n\x05\x63laim\x18\x01 \x01(\x0b\x32\x1a.hdrp.services.AtomicClaimR\x05\x63laim\x12\x19\n\x08is_valid\x18\x02 \x01(\x08R\x07isValid\x12\x1c\n\treasoning\x18\x03 \x01(\tR\treasoning\x12\x37\n\nconfidence\x18\x04 \x01(\x01\x42\x17\xbaH\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00\xf0?)\x00\x00\x00\x00\x00\x00\x00\x00R\nconfidence\"\x97\x02\n\x11SynthesizeRequest\x12Z\n\x14verification_results\x18\x01 \x03(\x0b\x32\x1d.hdrp.services.CritiqueResultB\x08\xbaH\x05\x92\x01\x02\x08\x01R\x13verificationResults\x12G\n\x07\x63ontext\x18\x02 \x03(\x0b\x32-.hdrp.services.SynthesizeRequest.ContextEntryR\x07\x63ontext\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x1a:\n\x0c\x43ontextEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"O\n\x12SynthesizeResponse\x12\x16\n\x06report\x18\x01 \x01(\tR\x06report\x12!\n\x0c\x61rtifact_uri\x18\x02 \x01(\tR\x0b\x61rtifactUri2\xf3\x01\n\x10PrincipalService\x12\xde\x01\n\x0e\x44\x65\x63omposeQuery\x12\x1b.hdrp.services.QueryRequest\x1a$.hdrp.services.DecompositionResponse\"\x88\x01\x92\x41m\n\tPrincipal\x12\x18\x44\x65\x63ompose Query into DAG\x1a\x46\x42reaks down a research query into a Directed Acyclic Graph of subtasks\x82\xd3\xe4\x93\x02\x12\"\r/v1/decompose:\x01*2\xd2\x01\n\x11ResearcherService\x12\xbc\x01\n\x08Research\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse\"o\x92\x41U\n\nResearcher\x12\x10\x43onduct Research\x1a\x35\x45xtracts atomic claims from sources for a given query\x82\xd3\xe4\x93\x02\x11\"\x0c/v1/research:\x01*2\xc7\x01\n\rCriticService\x12\xb5\x01\n\x06Verify\x12\x1c.hdrp.services.VerifyRequest\x1a\x1d.hdrp.services.VerifyResponse\"n\x92\x41V\n\x06\x43ritic\x12\rVerify Claims\x1a=Validates and critiques atomic claims extracted from research\x82\xd3\xe4\x93\x02\x0f\"\n/v1/verify:\x01*2\xde\x01\n\x12SynthesizerService\x12\xc7\x01\n\nSynthesize\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse\"t\x92\x41X\n\x0bSynthesizer\x12\x11Synthesize Report\x1a\x36Generates a final research report from verified claims\x82\xd3\xe4\x93\x02\x13\"\x0e/v1/synthesize:\x01*B\x9a\x02\n\x11\x63om.hdrp.servicesB\x11HdrpServicesProtoP\x01Z\x1fgithub.com/deepdag/hdrp/api/gen\xa2\x02\x03HSX\xaa\x02\rHdrp.Services\xca\x02\rHdrp\\Services\xe2\x02\x19Hdrp\\Services\\GPBMetadata\xea\x02\x0eHdrp::Services\x92\x41{\x12Q\n\x11HDRP Services API\x12\x37Hierarchical Deep Research Pipeline - Microservices API2\x03\x31.0*\x02\x01\x02\x32\x10\x61pplication/json:\x10\x61pplication/jsonb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x13hdrp_services.proto\x12\rhdrp.services\x1a\x1b\x62uf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xd6\x01\n\x0cQueryRequest\x12#\n\x05query\x18\x01 \x01(\tB\r\xbaH\nr\x05\x10\x01\x18\xf4\x03\xc8\x01\x01R\x05query\x12\x42\n\x07\x63ontext\x18\x02 \x03(\x0b\x32(.hdrp.services.QueryRequest.ContextEntryR\x07\x63ontext\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x1a:\n\x0c\x43ontextEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"_\n\x15\x44\x65\x63ompositionResponse\x12*\n\x05graph\x18\x01 \x01(\x0b\x32\x14.hdrp.services.GraphR\x05graph\x12\x1a\n\x08subtasks\x18\x02 \x03(\tR\x08subtasks\"\xea\x01\n\x05Graph\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12)\n\x05nodes\x18\x02 \x03(\x0b\x32\x13.hdrp.services.NodeR\x05nodes\x12)\n\x05\x65\x64ges\x18\x03 \x03(\x0b\x32\x13.hdrp.services.EdgeR\x05\x65\x64ges\x12>\n\x08metadata\x18\x04 \x03(\x0b\x32\".hdrp.services.Graph.MetadataEntryR\x08metadata\x1a;\n\rMetadataEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"\xf5\x01\n\x04Node\x12\x0e\n\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n\x04type\x18\x02 \x01(\tR\x04type\x12\x37\n\x06\x63onfig\x18\x03 \x03(\x0b\x32\x1f.hdrp.services.Node.ConfigEntryR\x06\x63onfig\x12\x16\n\x06status\x18\x04 \x01(\tR\x06status\x12\'\n\x0frelevance_score\x18\x05 \x01(\x01R\x0erelevanceScore\x12\x14\n\x05\x64\x65pth\x18\x06 \x01(\x05R\x05\x64\x65pth\x1a\x39\n\x0b\x43onfigEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"*\n\x04\x45\x64ge\x12\x12\n\x04\x66rom\x18\x01 \x01(\tR\x04\x66rom\x12\x0e\n\x02to\x18\x02 \x01(\tR\x02to\"\x8a\x02\n\x0fResearchRequest\x12#\n\x05query\x18\x01 \x01(\tB\r\xbaH\nr\x05\x10\x01\x18\xf4\x03\xc8\x01\x01R\x05query\x12\x30\n\x0esource_node_id\x18\x02 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x0csourceNodeId\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x12\x42\n\x06\x63onfig\x18\x04 \x03(\x0b\x32*.hdrp.services.ResearchRequest.ConfigEntryR\x06\x63onfig\x1a\x39\n\x0b\x43onfigEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"k\n\x10ResearchResponse\x12\x32\n\x06\x63laims\x18\x01 \x03(\x0b\x32\x1a.hdrp.services.AtomicClaimR\x06\x63laims\x12#\n\rtotal_sources\x18\x02 \x01(\x05R\x0ctotalSources\"\x90\x02\n\x0b\x41tomicClaim\x12%\n\tstatement\x18\x01 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\tstatement\x12\x1d\n\nsource_url\x18\x02 \x01(\tR\tsourceUrl\x12!\n\x0csupport_text\x18\x03 \x01(\tR\x0bsupportText\x12-\n\x0esource_node_id\x18\x04 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\x0csourceNodeId\x12\x1c\n\ttimestamp\x18\x05 \x01(\tR\ttimestamp\x12!\n\x0csource_title\x18\x06 \x01(\tR\x0bsourceTitle\x12(\n\x0bsource_rank\x18\x07 \x01(\x05\x42\x07\xbaH\x04\x1a\x02(\x00R\nsourceRank\"\x8d\x01\n\rVerifyRequest\x12<\n\x06\x63laims\x18\x01 \x03(\x0b\x32\x1a.hdrp.services.AtomicClaimB\x08\xbaH\x05\x92\x01\x02\x08\x01R\x06\x63laims\x12\x1b\n\x04task\x18\x02 \x01(\tB\x07\xbaH\x04r\x02\x10\x01R\x04task\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\"\x97\x01\n\x0eVerifyResponse\x12\x37\n\x07results\x18\x01 \x03(\x0b\x32\x1d.hdrp.services.CritiqueResultR\x07results\x12%\n\x0everified_count\x18\x02 \x01(\x05R\rverifiedCount\x12%\n\x0erejected_count\x18\x03 \x01(\x05R\rrejectedCount\"\xb4\x01\n\x0e\x43ritiqueResult\x12\x30\n\x05\x63laim\x18\x01 \x01(\x0b\x32\x1a.hdrp.services.AtomicClaimR\x05\x63laim\x12\x19\n\x08is_valid\x18\x02 \x01(\x08R\x07isValid\x12\x1c\n\treasoning\x18\x03 \x01(\tR\treasoning\x12\x37\n\nconfidence\x18\x04 \x01(\x01\x42\x17\xbaH\x14\x12\x12\x19\x00\x00\x00\x00\x00\x00\xf0?)\x00\x00\x00\x00\x00\x00\x00\x00R\nconfidence\"\x97\x02\n\x11SynthesizeRequest\x12Z\n\x14verification_results\x18\x01 \x03(\x0b\x32\x1d.hdrp.services.CritiqueResultB\x08\xbaH\x05\x92\x01\x02\x08\x01R\x13verificationResults\x12G\n\x07\x63ontext\x18\x02 \x03(\x0b\x32-.hdrp.services.SynthesizeRequest.ContextEntryR\x07\x63ontext\x12!\n\x06run_id\x18\x03 \x01(\tB\n\xbaH\x07r\x02\x10\x01\xc8\x01\x01R\x05runId\x1a:\n\x0c\x43ontextEntry\x12\x10\n\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n\x05value\x18\x02 \x01(\tR\x05value:\x02\x38\x01\"O\n\x12SynthesizeResponse\x12\x16\n\x06report\x18\x01 \x01(\tR\x06report\x12!\n\x0c\x61rtifact_uri\x18\x02 \x01(\tR\x0b\x61rtifactUri2\xf3\x01\n\x10PrincipalService\x12\xde\x01\n\x0e\x44\x65\x63omposeQuery\x12\x1b.hdrp.services.QueryRequest\x1a$.hdrp.services.DecompositionResponse\"\x88\x01\x92\x41m\n\tPrincipal\x12\x18\x44\x65\x63ompose Query into DAG\x1a\x46\x42reaks down a research query into a Directed Acyclic Graph of subtasks\x82\xd3\xe4\x93\x02\x12\"\r/v1/decompose:\x01*2\xa7\x02\n\x11ResearcherService\x12\xbc\x01\n\x08Research\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse\"o\x92\x41U\n\nResearcher\x12\x10\x43onduct Research\x1a\x35\x45xtracts atomic claims from sources for a given query\x82\xd3\xe4\x93\x02\x11\"\x0c/v1/research:\x01*\x12S\n\x0eResearchStream\x12\x1e.hdrp.services.ResearchRequest\x1a\x1f.hdrp.services.ResearchResponse0\x01\x32\xc7\x01\n\rCriticService\x12\xb5\x01\n\x06Verify\x12\x1c.hdrp.services.VerifyRequest\x1a\x1d.hdrp.services.VerifyResponse\"n\x92\x41V\n\x06\x43ritic\x12\rVerify Claims\x1a=Validates and critiques atomic claims extracted from research\x82\xd3\xe4\x93\x02\x0f\"\n/v1/verify:\x01*2\xb9\x02\n\x12SynthesizerService\x12\xc7\x01\n\nSynthesize\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse\"t\x92\x41X\n\x0bSynthesizer\x12\x11Synthesize Report\x1a\x36Generates a final research report from verified claims\x82\xd3\xe4\x93\x02\x13\"\x0e/v1/synthesize:\x01*\x12Y\n\x10SynthesizeStream\x12 .hdrp.services.SynthesizeRequest\x1a!.hdrp.services.SynthesizeResponse0\x01\x42\x9a\x02\n\x11\x63om.hdrp.servicesB\x11HdrpServicesProtoP\x01Z\x1fgithub.com/deepdag/hdrp/api/gen\xa2\x02\x03HSX\xaa\x02\rHdrp.Services\xca\x02\rHdrp\\Services\xe2\x02\x19Hdrp\\Services\\GPBMetadata\xea\x02\x0eHdrp::Services\x92\x41{\x12Q\n\x11HDRP Services API\x12\x37Hierarchical Deep Research Pipeline - Microservices API2\x03\x31.0*\x02\x01\x02\x32\x10\x61pplication/json:\x10\x61pplication/jsonb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -120,9 +120,9 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['_PRINCIPALSERVICE']._serialized_start=2486
   _globals['_PRINCIPALSERVICE']._serialized_end=2729
   _globals['_RESEARCHERSERVICE']._serialized_start=2732
-  _globals['_RESEARCHERSERVICE']._serialized_end=2942
-  _globals['_CRITICSERVICE']._serialized_start=2945
-  _globals['_CRITICSERVICE']._serialized_end=3144
-  _globals['_SYNTHESIZERSERVICE']._serialized_start=3147
-  _globals['_SYNTHESIZERSERVICE']._serialized_end=3369
+  _globals['_RESEARCHERSERVICE']._serialized_end=3027
+  _globals['_CRITICSERVICE']._serialized_start=3030
+  _globals['_CRITICSERVICE']._serialized_end=3229
+  _globals['_SYNTHESIZERSERVICE']._serialized_start=3232
+  _globals['_SYNTHESIZERSERVICE']._serialized_end=3545
 # @@protoc_insertion_point(module_scope)
@@ -107,6 +107,11 @@ class ResearcherServiceStub(object):
                 request_serializer=hdrp__services__pb2.ResearchRequest.SerializeToString,
                 response_deserializer=hdrp__services__pb2.ResearchResponse.FromString,
                 _registered_method=True)
+        self.ResearchStream = channel.unary_stream(
+                '/hdrp.services.ResearcherService/ResearchStream',
+                request_serializer=hdrp__services__pb2.ResearchRequest.SerializeToString,
+                response_deserializer=hdrp__services__pb2.ResearchResponse.FromString,
+                _registered_method=True)
 
 
 class ResearcherServiceServicer(object):
@@ -122,6 +127,14 @@ class ResearcherServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ResearchStream(self, request, context):
+        """Streams claims in batches as they are extracted. Each response carries
+        the next batch in claims.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_ResearcherServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -130,6 +143,11 @@ def add_ResearcherServiceServicer_to_server(servicer, server):
                     request_deserializer=hdrp__services__pb2.ResearchRequest.FromString,
                     response_serializer=hdrp__services__pb2.ResearchResponse.SerializeToString,
             ),
+            'ResearchStream': grpc.unary_stream_rpc_method_handler(
+                    servicer.ResearchStream,
+                    request_deserializer=hdrp__services__pb2.ResearchRequest.FromString,
+                    response_serializer=hdrp__services__pb2.ResearchResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'hdrp.services.ResearcherService', rpc_method_handlers)
@@ -172,6 +190,33 @@ class ResearcherService(object):
             metadata,
             _registered_method=True)
 
+    @staticmethod
+    def ResearchStream(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(
+            request,
+            target,
+            '/hdrp.services.ResearcherService/ResearchStream',
+            hdrp__services__pb2.ResearchRequest.SerializeToString,
+            hdrp__services__pb2.ResearchResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
+
 
 class CriticServiceStub(object):
     """============================================================================
@@ -275,6 +320,11 @@ class SynthesizerServiceStub(object):
                 request_serializer=hdrp__services__pb2.SynthesizeRequest.SerializeToString,
                 response_deserializer=hdrp__services__pb2.SynthesizeResponse.FromString,
                 _registered_method=True)
+        self.SynthesizeStream = channel.unary_stream(
+                '/hdrp.services.SynthesizerService/SynthesizeStream',
+                request_serializer=hdrp__services__pb2.SynthesizeRequest.SerializeToString,
+                response_deserializer=hdrp__services__pb2.SynthesizeResponse.FromString,
+                _registered_method=True)
 
 
 class SynthesizerServiceServicer(object):
@@ -290,6 +340,14 @@ class SynthesizerServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def SynthesizeStream(self, request, context):
+        """Streams the report as it is generated. Each response carries the next
+        chunk of the report; artifact_uri may be set on any of them.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_SynthesizerServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -298,6 +356,11 @@ def add_SynthesizerServiceServicer_to_server(servicer, server):
                     request_deserializer=hdrp__services__pb2.SynthesizeRequest.FromString,
                     response_serializer=hdrp__services__pb2.SynthesizeResponse.SerializeToString,
             ),
+            'SynthesizeStream': grpc.unary_stream_rpc_method_handler(
+                    servicer.SynthesizeStream,
+                    request_deserializer=hdrp__services__pb2.SynthesizeRequest.FromString,
+                    response_serializer=hdrp__services__pb2.SynthesizeResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'hdrp.services.SynthesizerService', rpc_method_handlers)
@@ -339,3 +402,30 @@ class SynthesizerService(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def SynthesizeStream(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(
+            request,
+            target,
+            '/hdrp.services.SynthesizerService/SynthesizeStream',
+            hdrp__services__pb2.SynthesizeRequest.SerializeToString,
+            hdrp__services__pb2.SynthesizeResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
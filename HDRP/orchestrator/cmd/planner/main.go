@@ -6,6 +6,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"hdrp/internal/dag"
 	"hdrp/internal/generator"
@@ -16,6 +20,10 @@ import (
 func main() {
 	queryPtr := flag.String("query", "", "The research query or objective")
 	jsonPtr := flag.Bool("json", false, "Output only the final structured JSON")
+	dotPtr := flag.Bool("dot", false, "Output only the plan as a Graphviz DOT digraph")
+	maxDepthPtr := flag.Int("max-depth", 0, "Maximum graph depth in layers (default: 3)")
+	verifyPtr := flag.Bool("verify-determinism", false, "Regenerate the plan and fail unless every graph is identical")
+	blueprintsPtr := flag.String("blueprints", "", "Directory of YAML blueprints to use instead of the built-in ones")
 	flag.Parse()
 
 	if *queryPtr == "" {
@@ -23,69 +31,127 @@ func main() {
 		os.Exit(1)
 	}
 
+	// DOT output goes to stdout on its own so it can be piped into dot
+	quiet := *jsonPtr || *dotPtr
+
 	runID := logger.GenerateRunID()
 	// Initialize logger (writes to ../../logs/<runID>.jsonl)
 	if err := logger.InitLogger(runID); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
 		os.Exit(1)
 	}
-	defer logger.Close()
+	defer closeLogger()
+
+	// Flush buffered events if the planner is interrupted mid-run
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		exit(130)
+	}()
 
 	ctx := context.Background()
 	logger.LogEvent(ctx, runID, "cli", "startup", map[string]string{"query": *queryPtr})
 
 	// 1. Parse Intent
-	if !*jsonPtr {
+	if !quiet {
 		fmt.Println("--> Parsing Intent...")
 	}
 	parser := intent.NewBasicParser()
 	objective, err := parser.Parse(*queryPtr)
 	if err != nil {
-		logger.LogEvent(ctx, runID, "cli", "error", map[string]string{"phase": "intent", "error": err.Error()})
+		logger.LogEventLevel(ctx, logger.LevelError, runID, "cli", "error", map[string]string{"phase": "intent", "error": err.Error()})
 		fmt.Fprintf(os.Stderr, "Error parsing intent: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 	
-	if !*jsonPtr {
+	if !quiet {
 		fmt.Printf("    Identified Intent: %s\n", objective.Type)
 		fmt.Printf("    Constraints: %v\n", objective.Constraints)
 	}
 
 	// 2. Generate Plan (DAG)
-	if !*jsonPtr {
+	if !quiet {
 		fmt.Println("--> Generating Execution Graph...")
 	}
-	gen := generator.NewTemplateGenerator()
+	gen, err := generator.NewTemplateGeneratorFromDir(*blueprintsPtr)
+	if err != nil {
+		logger.LogEventLevel(ctx, logger.LevelError, runID, "cli", "error", map[string]string{"phase": "generation", "error": err.Error()})
+		fmt.Fprintf(os.Stderr, "Error loading blueprints: %v\n", err)
+		exit(1)
+	}
 	graph, err := gen.Generate(objective)
 	if err != nil {
-		logger.LogEvent(ctx, runID, "cli", "error", map[string]string{"phase": "generation", "error": err.Error()})
+		logger.LogEventLevel(ctx, logger.LevelError, runID, "cli", "error", map[string]string{"phase": "generation", "error": err.Error()})
 		fmt.Fprintf(os.Stderr, "Error generating graph: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+
+	if *verifyPtr {
+		if err := generator.VerifyDeterminism(gen, objective, generator.DefaultDeterminismRuns); err != nil {
+			logger.LogEventLevel(ctx, logger.LevelError, runID, "cli", "error", map[string]string{"phase": "determinism", "error": err.Error()})
+			fmt.Fprintf(os.Stderr, "Determinism check failed: %v\n", err)
+			exit(1)
+		}
+		if !quiet {
+			fmt.Printf("    Generation is deterministic across %d regenerations\n", generator.DefaultDeterminismRuns)
+		}
 	}
 
 	// 3. Validate
+	graph.MaxDepth = *maxDepthPtr
 	if err := graph.Validate(); err != nil {
 		fmt.Fprintf(os.Stderr, "Generated graph is invalid: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+	if !quiet {
+		path, err := graph.CriticalPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing critical path: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("    Critical path (%d nodes): %s\n", len(path), strings.Join(path, " -> "))
 	}
 
 	// 4. Log Plan
 	dag.LogGraphPlan(ctx, runID, graph)
-	if !*jsonPtr {
+	if !quiet {
 		fmt.Println("--> Plan Logged.")
 	}
 
-	// 5. Output JSON to Stdout for user inspection
-	if !*jsonPtr {
+	// 5. Output the plan to Stdout for user inspection
+	if *dotPtr {
+		if err := dag.WriteDOT(os.Stdout, graph); err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering graph: %v\n", err)
+			exit(1)
+		}
+		return
+	}
+	if !quiet {
 		fmt.Println("\n=== FINAL PLAN (JSON) ===")
 	}
 	enc := json.NewEncoder(os.Stdout)
-	if !*jsonPtr {
+	if !quiet {
 		enc.SetIndent("", "  ")
 	}
 	enc.Encode(graph)
 	
-	if !*jsonPtr {
+	if !quiet {
 		fmt.Printf("\nCheck logs at HDRP/logs/%s.jsonl\n", runID)
 	}
 }
+
+// closeLogger flushes buffered log events, waiting at most two seconds.
+func closeLogger() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := logger.CloseContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush logs: %v\n", err)
+	}
+}
+
+// exit flushes logs before terminating, since os.Exit skips deferred calls.
+func exit(code int) {
+	closeLogger()
+	os.Exit(code)
+}
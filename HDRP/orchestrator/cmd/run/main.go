@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"hdrp/internal/bootstrap"
+	"hdrp/internal/clients"
+	"hdrp/internal/config"
+	"hdrp/internal/dag"
+	"hdrp/internal/decomposer"
+	"hdrp/internal/executor"
+	"hdrp/internal/sink"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"github.com/google/uuid"
+)
+
+func main() {
+	query := flag.String("query", "", "The research query to execute")
+	sinkSpec := flag.String("sink", "stdout", "Report destination: stdout, file:<path>, or an http(s) URL")
+	configPath := flag.String("config", "", "Path to config file (default: ../config/config.yaml)")
+	provider := flag.String("decomposer", "", "Override planning.decomposer: principal or local")
+	runID := flag.String("run-id", "", "Run ID (default: random UUID)")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Maximum time for the whole run")
+	persistResults := flag.Bool("persist-results", false, "Persist node results so a resumed run keeps them, even if recovery.persist_node_results is off")
+	flag.Parse()
+
+	if *query == "" {
+		fmt.Fprintln(os.Stderr, "Please provide a query using -query=\"...\"")
+		os.Exit(1)
+	}
+	if *runID == "" {
+		*runID = uuid.New().String()
+	}
+
+	if err := run(*query, *runID, *sinkSpec, *configPath, *provider, *persistResults, *timeout); err != nil {
+		log.Printf("[Run] %v", err)
+		os.Exit(1)
+	}
+}
+
+// run wires up clients, decomposer, and executor from config and executes a
+// single query, delivering the report to the sink described by sinkSpec.
+func run(query, runID, sinkSpec, configPath, provider string, persistResults bool, timeout time.Duration) error {
+	out, err := sink.New(sinkSpec)
+	if err != nil {
+		return fmt.Errorf("invalid sink: %w", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if provider != "" {
+		cfg.Planning.Decomposer = provider
+	}
+	if persistResults {
+		cfg.Recovery.PersistNodeResults = true
+	}
+
+	svcClients, err := clients.NewServiceClients(bootstrap.ServiceConfig(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to initialize service clients: %w", err)
+	}
+	defer svcClients.Close()
+	if cfg.Services.Connection.WaitForReady {
+		timeout := time.Duration(cfg.Services.Connection.WarmUpSeconds) * time.Second
+		if timeout <= 0 {
+			timeout = clients.DefaultReadyTimeout
+		}
+		readyCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		if err := svcClients.WaitForReady(readyCtx); err != nil {
+			log.Printf("[Run] Warning: services not ready, continuing anyway: %v", err)
+		}
+		cancel()
+	}
+
+	decomp, err := decomposer.New(cfg.Planning.Decomposer, svcClients.Principal, cfg.Planning.BlueprintsDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize decomposer: %w", err)
+	}
+
+	exec, err := bootstrap.NewExecutor(cfg, svcClients)
+	if err != nil {
+		return err
+	}
+	defer exec.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return runQuery(ctx, decomp, exec, out, query, runID)
+}
+
+// runQuery decomposes and executes a query, then delivers the final report
+// to out, or the aggregated critic results if the graph has no synthesizer.
+// Failed runs are delivered and then returned as errors.
+func runQuery(
+	ctx context.Context,
+	decomp decomposer.Decomposer,
+	exec *executor.DAGExecutor,
+	out sink.Sink,
+	query string,
+	runID string,
+) error {
+	log.Printf("[Run] Executing query='%s', run_id=%s", query, runID)
+
+	graph, err := decomp.Decompose(ctx, &decomposer.Request{Query: query, RunID: runID})
+	if err != nil {
+		return fmt.Errorf("query decomposition failed: %w", err)
+	}
+	log.Printf("[Run] Graph created with %d nodes, %d edges", len(graph.Nodes), len(graph.Edges))
+
+	// Runs from the command line belong to no tenant, so the global node
+	// type allowlist applies whatever the planner put in the metadata
+	delete(graph.Metadata, dag.MetadataTenant)
+
+	result, err := exec.Execute(ctx, graph, runID)
+	if err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	// Graphs without a synthesizer succeed with aggregated critic results
+	// instead of a report
+	content := result.FinalReport
+	if content == "" {
+		content = formatVerificationResults(query, result.VerificationResults)
+	}
+
+	report := &sink.Report{
+		RunID:        runID,
+		Query:        query,
+		Success:      result.Success,
+		Content:      content,
+		ArtifactURI:  result.ArtifactURI,
+		ErrorMessage: result.ErrorMessage,
+	}
+	if err := out.Write(ctx, report); err != nil {
+		return fmt.Errorf("failed to deliver report: %w", err)
+	}
+
+	log.Printf("[Run] Report delivered: run_id=%s, success=%v", runID, result.Success)
+	if !result.Success {
+		return fmt.Errorf("run %s failed: %s", runID, result.ErrorMessage)
+	}
+	return nil
+}
+
+// formatVerificationResults renders aggregated critic results as a markdown
+// list, one claim per line.
+func formatVerificationResults(query string, results []*pb.CritiqueResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HDRP Verification Results: %s\n", query)
+	if len(results) == 0 {
+		b.WriteString("\nNo claims were verified.\n")
+		return b.String()
+	}
+	b.WriteString("\n")
+	for _, r := range results {
+		verdict := "verified"
+		if !r.IsValid {
+			verdict = "rejected"
+		}
+		fmt.Fprintf(&b, "- [%s] %s", verdict, r.Claim.GetStatement())
+		if r.Reasoning != "" {
+			fmt.Fprintf(&b, " (%s)", r.Reasoning)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
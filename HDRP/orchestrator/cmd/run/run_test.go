@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/decomposer"
+	"hdrp/internal/executor"
+	"hdrp/internal/retry"
+	"hdrp/internal/sink"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+type fakeResearcher struct{}
+
+func (fakeResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+type fakeCritic struct{}
+
+func (fakeCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	for _, claim := range req.Claims {
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: true})
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: int32(len(results))}, nil
+}
+
+type fakeSynthesizer struct{}
+
+func (fakeSynthesizer) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
+	return &pb.SynthesizeResponse{Report: "# " + req.Context["report_title"]}, nil
+}
+
+// staticDecomposer returns a fixed researcher -> critic -> synthesizer graph.
+type staticDecomposer struct{}
+
+func (staticDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	return &dag.Graph{
+		ID:     req.RunID,
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher", Type: "researcher", Config: map[string]string{"query": req.Query}, Status: dag.StatusCreated},
+			{ID: "critic", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+			{ID: "synthesizer", Type: "synthesizer", Config: map[string]string{"query": req.Query}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{
+			{From: "researcher", To: "critic"},
+			{From: "critic", To: "synthesizer"},
+		},
+	}, nil
+}
+
+// verifyOnlyDecomposer returns a researcher -> critic graph with no
+// synthesizer.
+type verifyOnlyDecomposer struct{}
+
+func (verifyOnlyDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	return &dag.Graph{
+		ID:     req.RunID,
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher", Type: "researcher", Config: map[string]string{"query": req.Query}, Status: dag.StatusCreated},
+			{ID: "critic", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{{From: "researcher", To: "critic"}},
+	}, nil
+}
+
+// tenantDecomposer returns staticDecomposer's graph claiming a tenant.
+type tenantDecomposer struct{ tenant string }
+
+func (d tenantDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	graph, _ := staticDecomposer{}.Decompose(ctx, req)
+	graph.Metadata = map[string]string{dag.MetadataTenant: d.tenant}
+	return graph, nil
+}
+
+func newTestExecutor(t *testing.T) *executor.DAGExecutor {
+	t.Helper()
+	exec := executor.NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  fakeResearcher{},
+		Critic:      fakeCritic{},
+		Synthesizer: fakeSynthesizer{},
+	}, 2)
+	exec.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 0})
+	t.Cleanup(func() { exec.Close() })
+	return exec
+}
+
+func TestRunQuery_FileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.md")
+
+	err := runQuery(context.Background(), staticDecomposer{}, newTestExecutor(t), sink.NewFileSink(path), "solid-state batteries", "run-file-sink")
+	if err != nil {
+		t.Fatalf("runQuery() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if want := "# HDRP Research Report: solid-state batteries"; string(data) != want {
+		t.Errorf("Report = %q, want %q", data, want)
+	}
+}
+
+func TestRunQuery_NoSynthesizer(t *testing.T) {
+	var out bytes.Buffer
+
+	err := runQuery(context.Background(), verifyOnlyDecomposer{}, newTestExecutor(t), sink.NewWriterSink(&out), "solid-state batteries", "run-no-synthesizer")
+	if err != nil {
+		t.Fatalf("runQuery() error = %v", err)
+	}
+
+	want := "# HDRP Verification Results: solid-state batteries\n\n- [verified] claim\n\n"
+	if out.String() != want {
+		t.Errorf("Output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestRunQuery_NodeTypeAllowlist(t *testing.T) {
+	exec := newTestExecutor(t)
+	exec.SetNodeTypeAllowlist(&dag.NodeTypeAllowlist{
+		Global:  []string{"researcher", "critic"},
+		Tenants: map[string][]string{"open": {"researcher", "critic", "synthesizer"}},
+	})
+	var out bytes.Buffer
+
+	// The planner's tenant doesn't count, so the global list applies
+	err := runQuery(context.Background(), tenantDecomposer{tenant: "open"}, exec, sink.NewWriterSink(&out), "q", "run-allowlist")
+	if !errors.Is(err, dag.ErrNodeTypeNotAllowed) {
+		t.Fatalf("runQuery() error = %v, want ErrNodeTypeNotAllowed", err)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/executor"
+	"hdrp/internal/metrics"
+)
+
+// DefaultFinishedRunRetention is how many finished async runs the server
+// keeps for polling before discarding the oldest.
+const DefaultFinishedRunRetention = 1000
+
+// RunStatusPaused is the status GET /runs/{id} reports for a paused run.
+const RunStatusPaused = "PAUSED"
+
+// AcceptedResponse is returned by POST /execute?async=true.
+type AcceptedResponse struct {
+	RunID     string `json:"run_id"`
+	StatusURL string `json:"status_url"` // Poll for progress and the final report
+}
+
+// RunStatusResponse is returned by GET /runs/{id}.
+type RunStatusResponse struct {
+	RunID     string `json:"run_id"`
+	GraphID   string `json:"graph_id,omitempty"`
+	Status    string `json:"status"` // Graph status, or PAUSED for a paused run
+	Done      bool   `json:"done"`
+	Paused    bool   `json:"paused,omitempty"`
+	Nodes     int    `json:"nodes"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// Node count per phase (e.g. BACKOFF) while the run executes
+	Phases map[string]int `json:"phases,omitempty"`
+
+	// Set once the run is done
+	Success         bool   `json:"success,omitempty"`
+	Report          string `json:"report,omitempty"`
+	ArtifactURI     string `json:"artifact_uri,omitempty"`
+	ErrorMessage    string `json:"error_message,omitempty"`
+	ReportTruncated bool   `json:"report_truncated,omitempty"`
+}
+
+// RunResultResponse is returned by GET /runs/{id}/result.
+type RunResultResponse struct {
+	RunID           string    `json:"run_id"`
+	GraphID         string    `json:"graph_id"`
+	Status          string    `json:"status"` // Final graph status
+	Success         bool      `json:"success"`
+	PartialSuccess  bool      `json:"partial_success,omitempty"`
+	Report          string    `json:"report,omitempty"`
+	ArtifactURI     string    `json:"artifact_uri,omitempty"`
+	ReportTruncated bool      `json:"report_truncated,omitempty"`
+	ErrorMessage    string    `json:"error_message,omitempty"`
+	SucceededNodes  int       `json:"succeeded_nodes"`
+	FailedNodes     int       `json:"failed_nodes"`
+	CompletedAt     time.Time `json:"completed_at"`
+}
+
+// asyncRun is a run started with POST /execute?async=true.
+type asyncRun struct {
+	graph     *dag.Graph
+	done      bool
+	succeeded int
+	failed    int
+	response  ExecuteResponse
+}
+
+// RunResults holds the state of async runs so clients can poll them. Runs
+// are kept while executing and for a bounded number of runs after they
+// finish. It is safe for concurrent use.
+type RunResults struct {
+	mu          sync.RWMutex
+	runs        map[string]*asyncRun
+	finished    []string // Finished run IDs, oldest first
+	maxFinished int
+}
+
+// NewRunResults creates a store keeping at most maxFinished finished runs.
+// maxFinished <= 0 uses DefaultFinishedRunRetention.
+func NewRunResults(maxFinished int) *RunResults {
+	if maxFinished <= 0 {
+		maxFinished = DefaultFinishedRunRetention
+	}
+	return &RunResults{
+		runs:        make(map[string]*asyncRun),
+		maxFinished: maxFinished,
+	}
+}
+
+// Start records an async run as executing graph, replacing any finished
+// run with the same ID.
+func (r *RunResults) Start(runID string, graph *dag.Graph) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, id := range r.finished {
+		if id == runID {
+			r.finished = append(r.finished[:i], r.finished[i+1:]...)
+			break
+		}
+	}
+	r.runs[runID] = &asyncRun{graph: graph}
+}
+
+// Finish records a run's final response and node counts, discarding the
+// oldest finished run if the store is full.
+func (r *RunResults) Finish(runID string, resp ExecuteResponse, succeeded, failed int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, ok := r.runs[runID]
+	if !ok {
+		return
+	}
+	run.done = true
+	run.response = resp
+	run.succeeded = succeeded
+	run.failed = failed
+
+	r.finished = append(r.finished, runID)
+	for len(r.finished) > r.maxFinished {
+		delete(r.runs, r.finished[0])
+		r.finished = r.finished[1:]
+	}
+}
+
+// Status returns the current state of an async run.
+func (r *RunResults) Status(runID string) (RunStatusResponse, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	run, ok := r.runs[runID]
+	if !ok {
+		return RunStatusResponse{}, false
+	}
+
+	status := RunStatusResponse{
+		RunID:   runID,
+		GraphID: run.graph.ID,
+		Status:  string(run.graph.Status),
+		Done:    run.done,
+		Nodes:   len(run.graph.Nodes),
+	}
+	if !run.done {
+		// The executor may be updating statuses, so count a snapshot
+		for _, n := range run.graph.NodeSnapshot() {
+			switch n.Status {
+			case dag.StatusSucceeded:
+				status.Succeeded++
+			case dag.StatusFailed:
+				status.Failed++
+			}
+			if n.Phase != "" {
+				if status.Phases == nil {
+					status.Phases = make(map[string]int)
+				}
+				status.Phases[n.Phase]++
+			}
+		}
+		return status, true
+	}
+
+	status.Succeeded = run.succeeded
+	status.Failed = run.failed
+	status.Success = run.response.Success
+	status.Report = run.response.Report
+	status.ArtifactURI = run.response.ArtifactURI
+	status.ErrorMessage = run.response.ErrorMessage
+	status.ReportTruncated = run.response.ReportTruncated
+	return status, true
+}
+
+// handleRunStatus reports the state of a run started with
+// POST /execute?async=true, or of a synchronous run still executing.
+func (s *Server) handleRunStatus(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	status, ok := s.results.Status(runID)
+	if !ok {
+		progress, active := s.runs.Lookup(runID)
+		if !active {
+			http.Error(w, "run not found", http.StatusNotFound)
+			return
+		}
+		status = RunStatusResponse{
+			RunID:     runID,
+			GraphID:   progress.GraphID,
+			Status:    string(dag.StatusRunning),
+			Nodes:     progress.Nodes,
+			Succeeded: progress.Statuses[string(dag.StatusSucceeded)],
+			Failed:    progress.Statuses[string(dag.StatusFailed)],
+			Phases:    progress.Phases,
+		}
+	}
+	if !status.Done && s.executor.IsPaused(runID) {
+		status.Status = RunStatusPaused
+		status.Paused = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// handleRunResult returns the persisted outcome of a finished run. Unlike
+// GET /runs/{id} it reads from storage, so results survive restarts and
+// outlive the in-memory retention of async runs.
+func (s *Server) handleRunResult(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	result, err := s.executor.LoadRunResult(runID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "no result for run", http.StatusNotFound)
+		return
+	case errors.Is(err, executor.ErrRunResultsUnsupported):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to load result of run %s: %v", runID, err)
+		http.Error(w, fmt.Sprintf("failed to load run result: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RunResultResponse{
+		RunID:           result.RunID,
+		GraphID:         result.GraphID,
+		Status:          result.Status,
+		Success:         result.Success,
+		PartialSuccess:  result.PartialSuccess,
+		Report:          result.FinalReport,
+		ArtifactURI:     result.ArtifactURI,
+		ReportTruncated: result.ReportTruncated,
+		ErrorMessage:    result.ErrorMessage,
+		SucceededNodes:  result.SucceededNodes,
+		FailedNodes:     result.FailedNodes,
+		CompletedAt:     result.CompletedAt,
+	})
+}
+
+// asyncRunContext returns the context an async run executes under: detached
+// from the request so the run outlives the response, and bounded by
+// http.async_timeout_seconds rather than executeTimeout.
+func (s *Server) asyncRunContext() (context.Context, context.CancelFunc) {
+	if s.httpConfig.AsyncTimeoutSeconds > 0 {
+		return context.WithTimeout(context.Background(), time.Duration(s.httpConfig.AsyncTimeoutSeconds)*time.Second)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// runAsync executes a registered graph in the background under ctx,
+// recording its outcome in s.results. cancel is called when the run ends.
+func (s *Server) runAsync(ctx context.Context, cancel context.CancelFunc, graph *dag.Graph, runID string, req ExecuteRequest) {
+	s.results.Start(runID, graph)
+
+	go func() {
+		defer cancel()
+		defer s.runs.Deregister(runID)
+
+		result, err := s.executor.ExecuteWithOptions(ctx, graph, runID, runOptions(req))
+		if err != nil {
+			metrics.RecordRequestOutcome(phaseExecution, serverFailureOutcome(err))
+			serverLog.Errorf("Async execution of run %s failed: %v", runID, err)
+			_, resp := executionErrorResponse(runID, err)
+			s.results.Finish(runID, resp, 0, 0)
+			return
+		}
+
+		recordExecutionResult(result)
+		s.results.Finish(runID, resultResponse(runID, result), len(result.SucceededNodes), len(result.FailedNodes))
+		serverLog.Infof("Async run completed: run_id=%s, success=%v", runID, result.Success)
+	}()
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hdrp/internal/dag"
+)
+
+func getRunStatus(t *testing.T, s *Server, runID string) (int, RunStatusResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/runs/"+runID, nil)
+	req.SetPathValue("id", runID)
+	rec := httptest.NewRecorder()
+	s.handleRunStatus(rec, req)
+
+	var status RunStatusResponse
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatalf("Failed to decode status: %v", err)
+		}
+	}
+	return rec.Code, status
+}
+
+func TestHandleExecute_Async(t *testing.T) {
+	researcher := &gatedResearcher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = researcher
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-async"})
+	rec := httptest.NewRecorder()
+	s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute?async=true", bytes.NewReader(body)))
+
+	// The response arrives while the researcher is still blocked
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var accepted AcceptedResponse
+	if err := json.NewDecoder(rec.Body).Decode(&accepted); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if accepted.RunID != "run-async" || accepted.StatusURL != "/runs/run-async" {
+		t.Errorf("Unexpected accepted response: %+v", accepted)
+	}
+
+	<-researcher.started
+	code, status := getRunStatus(t, s, "run-async")
+	if code != http.StatusOK || status.Done || status.Nodes != 1 {
+		t.Errorf("Status while running = %d %+v", code, status)
+	}
+
+	close(researcher.release)
+	deadline := time.Now().Add(5 * time.Second)
+	for !status.Done {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the async run to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, status = getRunStatus(t, s, "run-async")
+	}
+	if !status.Success || status.Succeeded != 1 || status.Failed != 0 {
+		t.Errorf("Final status = %+v", status)
+	}
+	if status.Status != string(dag.StatusSucceeded) {
+		t.Errorf("Graph status = %s, want %s", status.Status, dag.StatusSucceeded)
+	}
+	if n := s.runs.Len(); n != 0 {
+		t.Errorf("Expected the run to be deregistered, %d still active", n)
+	}
+
+	if code, _ := getRunStatus(t, s, "run-missing"); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown run, got %d", code)
+	}
+}
+
+// TestHandleExecute_AsyncOutlivesExecuteTimeout verifies async runs aren't
+// cut off at the synchronous request timeout.
+func TestHandleExecute_AsyncOutlivesExecuteTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { executeTimeout = timeout }(executeTimeout)
+	executeTimeout = 50 * time.Millisecond
+
+	researcher := &gatedResearcher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = researcher
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-async-long"})
+	rec := httptest.NewRecorder()
+	s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute?async=true", bytes.NewReader(body)))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	<-researcher.started
+	time.Sleep(4 * executeTimeout)
+	close(researcher.release)
+
+	var status RunStatusResponse
+	deadline := time.Now().Add(5 * time.Second)
+	for !status.Done {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the async run to finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, status = getRunStatus(t, s, "run-async-long")
+	}
+	if !status.Success {
+		t.Errorf("Run outliving executeTimeout failed: %+v", status)
+	}
+}
+
+func TestRunResults_Retention(t *testing.T) {
+	results := NewRunResults(2)
+	for _, id := range []string{"a", "b", "c"} {
+		results.Start(id, &dag.Graph{ID: "graph-" + id})
+		results.Finish(id, ExecuteResponse{RunID: id, Success: true}, 1, 0)
+	}
+
+	if _, ok := results.Status("a"); ok {
+		t.Error("Expected the oldest finished run to be discarded")
+	}
+	for _, id := range []string{"b", "c"} {
+		status, ok := results.Status(id)
+		if !ok || !status.Done || !status.Success {
+			t.Errorf("Status(%s) = %+v, %v", id, status, ok)
+		}
+	}
+
+	// Running runs are never discarded
+	results.Start("running", &dag.Graph{ID: "graph-running"})
+	results.Start("d", &dag.Graph{ID: "graph-d"})
+	results.Finish("d", ExecuteResponse{RunID: "d"}, 0, 1)
+	if _, ok := results.Status("running"); !ok {
+		t.Error("Expected the running run to be kept")
+	}
+}
+
+func TestHandleRunResult(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", t.TempDir()+"/results.db")
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-result"})
+	rec := httptest.NewRecorder()
+	s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var executed ExecuteResponse
+	json.NewDecoder(rec.Body).Decode(&executed)
+
+	getResult := func(runID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/runs/"+runID+"/result", nil)
+		req.SetPathValue("id", runID)
+		rec := httptest.NewRecorder()
+		s.handleRunResult(rec, req)
+		return rec
+	}
+
+	// The result is read back from storage after the response has gone
+	rec = getResult("run-result")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result RunResultResponse
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if !result.Success || result.Status != string(dag.StatusSucceeded) || result.SucceededNodes != 1 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.GraphID != "graph-run-result" || result.Report != executed.Report || result.CompletedAt.IsZero() {
+		t.Errorf("Stored result doesn't match the execution: %+v vs %+v", result, executed)
+	}
+
+	if rec := getResult("run-missing"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a run without a result, got %d", rec.Code)
+	}
+}
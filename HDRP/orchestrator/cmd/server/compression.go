@@ -0,0 +1,146 @@
+package main
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strings"
+
+	"hdrp/internal/config"
+)
+
+// DefaultCompressionMinBytes is the smallest response gzipped when the
+// config doesn't set one.
+const DefaultCompressionMinBytes = 1024
+
+// compressibleTypes are the content types worth gzipping: API responses
+// and reports.
+var compressibleTypes = map[string]bool{
+	"application/json": true,
+	"text/plain":       true,
+	"text/markdown":    true,
+}
+
+// withCompression gzips responses for clients that accept it. The run
+// event stream is passed through untouched, since it must flush each event
+// as it happens.
+func withCompression(next http.Handler, cfg config.CompressionConfig) http.Handler {
+	if !cfg.Enabled {
+		return next
+	}
+	minBytes := cfg.MinBytes
+	if minBytes <= 0 {
+		minBytes = DefaultCompressionMinBytes
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/events") || !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		gw := &gzipResponseWriter{ResponseWriter: w, minBytes: minBytes, status: http.StatusOK}
+		defer gw.Close()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip reports whether the request's Accept-Encoding allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter holds back the start of a response until it knows
+// whether compressing it pays off: the body must reach minBytes and have a
+// compressible content type that isn't already encoded.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minBytes int
+	status   int
+	buf      []byte
+	started  bool         // Headers sent and the compression decision made
+	gz       *gzip.Writer // Nil when the response is sent as is
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if !w.started {
+		w.status = status
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.minBytes {
+			return len(p), nil
+		}
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// start sends the headers, choosing whether to compress, then the buffered
+// body.
+func (w *gzipResponseWriter) start() error {
+	w.started = true
+	header := w.Header()
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if len(w.buf) >= w.minBytes && compressibleTypes[mediaType] && header.Get("Content-Encoding") == "" {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.gz != nil {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush sends what has been written so far, compressed if the response is
+// being compressed.
+func (w *gzipResponseWriter) Flush() {
+	if !w.started {
+		w.start()
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close completes the response.
+func (w *gzipResponseWriter) Close() error {
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	if w.gz != nil {
+		return w.gz.Close()
+	}
+	return nil
+}
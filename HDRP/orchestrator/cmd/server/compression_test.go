@@ -0,0 +1,65 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hdrp/internal/config"
+)
+
+func TestWithCompression(t *testing.T) {
+	report := strings.Repeat("Solid-state batteries use a solid electrolyte. ", 200)
+	handler := withCompression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/small" {
+			json.NewEncoder(w).Encode(ExecuteResponse{RunID: "run-small", Success: true})
+			return
+		}
+		json.NewEncoder(w).Encode(ExecuteResponse{RunID: "run-large", Success: true, Report: report})
+	}), config.CompressionConfig{Enabled: true})
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("/runs/run-large/result", "deflate, gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Expected a gzip-encoded response, got headers %v", rec.Header())
+	}
+	if rec.Body.Len() >= len(report) {
+		t.Errorf("Compressed body is %d bytes, report alone is %d", rec.Body.Len(), len(report))
+	}
+	gz, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("Body is not gzip: %v", err)
+	}
+	body, _ := io.ReadAll(gz)
+	var resp ExecuteResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Report != report {
+		t.Errorf("Decompressed body doesn't round-trip: %v", err)
+	}
+
+	if rec := get("/runs/run-large/result", ""); rec.Header().Get("Content-Encoding") != "" || !strings.Contains(rec.Body.String(), report) {
+		t.Error("Expected an uncompressed response without Accept-Encoding")
+	}
+	if rec := get("/runs/run-large/result", "gzip;q=0"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected an uncompressed response when gzip is refused")
+	}
+	if rec := get("/small", "gzip"); rec.Header().Get("Content-Encoding") != "" || !strings.Contains(rec.Body.String(), "run-small") {
+		t.Error("Expected a small response to be sent uncompressed")
+	}
+	if rec := get("/runs/run-large/events", "gzip"); rec.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected the event stream to be left uncompressed")
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"hdrp/internal/apierrors"
+	"hdrp/internal/dag"
+	"hdrp/internal/executor"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Phases and outcomes recorded for execute requests.
+const (
+	phaseDecomposition = "decomposition"
+	phaseExecution     = "execution"
+
+	outcomeSuccess         = "success"
+	outcomeFailed          = "failed" // The run finished without succeeding
+	outcomeError           = "error"
+	outcomeTimeout         = "timeout"
+	outcomeClientCancelled = "client_cancelled"
+	outcomeCancelled       = "cancelled" // Stopped via POST /runs/{id}/cancel
+)
+
+// failureOutcome classifies why a phase of an execute request stopped
+// early. The request context is only cancelled when the client goes away,
+// which tells a disconnect apart from the server's own deadline.
+func failureOutcome(r *http.Request, err error) string {
+	if errors.Is(r.Context().Err(), context.Canceled) {
+		return outcomeClientCancelled
+	}
+	return serverFailureOutcome(err)
+}
+
+// serverFailureOutcome classifies a failure that can't be a client
+// disconnect, such as an async run's.
+func serverFailureOutcome(err error) string {
+	if errors.Is(err, context.Canceled) {
+		return outcomeCancelled
+	}
+	if errors.Is(err, context.DeadlineExceeded) || status.Code(err) == codes.DeadlineExceeded {
+		return outcomeTimeout
+	}
+	return outcomeError
+}
+
+// MapGRPCErrorToHTTP converts a failed backend call into the HTTP status and
+// response body returned to the client.
+func MapGRPCErrorToHTTP(err error, runID string) (int, ExecuteResponse) {
+	code, msg := apierrors.Describe(err)
+	if _, ok := status.FromError(err); !ok {
+		msg = fmt.Sprintf("Query decomposition failed: %v", err)
+	}
+	return code, ExecuteResponse{
+		RunID:        runID,
+		Success:      false,
+		ErrorMessage: msg,
+	}
+}
+
+// StatusFromExecutionError picks the HTTP status for an error returned by
+// the executor before or during a run.
+func StatusFromExecutionError(err error) int {
+	switch {
+	case errors.Is(err, dag.ErrNodeTypeNotAllowed):
+		return http.StatusForbidden
+	case errors.Is(err, executor.ErrExecutionCancelled) && errors.Is(err, context.Canceled):
+		// Cancelled on request rather than timed out
+		return http.StatusConflict
+	default:
+		return apierrors.StatusFromError(err)
+	}
+}
+
+// writeErrorResponse sends a failed ExecuteResponse with the given status.
+func writeErrorResponse(w http.ResponseWriter, code int, resp ExecuteResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"hdrp/internal/executor"
+)
+
+// eventBufferSize bounds the per-subscriber queue; slow clients drop events.
+const eventBufferSize = 64
+
+// EventHub fans executor events out to per-run SSE subscribers.
+type EventHub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan executor.Event]struct{}
+}
+
+// NewEventHub creates an empty event hub.
+func NewEventHub() *EventHub {
+	return &EventHub{
+		subscribers: make(map[string]map[chan executor.Event]struct{}),
+	}
+}
+
+// Subscribe registers a listener for events of the given run.
+// The returned function unregisters the listener and closes its channel.
+func (h *EventHub) Subscribe(runID string) (<-chan executor.Event, func()) {
+	ch := make(chan executor.Event, eventBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[runID] == nil {
+		h.subscribers[runID] = make(map[chan executor.Event]struct{})
+	}
+	h.subscribers[runID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[runID], ch)
+			if len(h.subscribers[runID]) == 0 {
+				delete(h.subscribers, runID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers an event to all subscribers of its run without blocking.
+func (h *EventHub) Publish(evt executor.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[evt.RunID] {
+		select {
+		case ch <- evt:
+		default:
+			serverLog.Warnf("Dropping %s event for slow subscriber on run %s", evt.Type, evt.RunID)
+		}
+	}
+}
+
+// handleRunEvents streams execution events for a run as Server-Sent Events.
+func (s *Server) handleRunEvents(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	if runID == "" {
+		http.Error(w, "run id is required", http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := s.events.Subscribe(runID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case evt := <-events:
+			data, err := json.Marshal(evt)
+			if err != nil {
+				serverLog.Errorf("Failed to encode event: %v", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Type, data)
+			flusher.Flush()
+		}
+	}
+}
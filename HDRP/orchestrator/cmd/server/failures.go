@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hdrp/internal/executor"
+)
+
+// DeadLetterResponse is a node that gave up, as listed by
+// GET /runs/{id}/failures.
+type DeadLetterResponse struct {
+	NodeID    string            `json:"node_id"`
+	NodeType  string            `json:"node_type"`
+	Config    map[string]string `json:"config,omitempty"`
+	LastError string            `json:"last_error"`
+	FailedAt  time.Time         `json:"failed_at"`
+}
+
+// RunFailuresResponse is returned by GET /runs/{id}/failures.
+type RunFailuresResponse struct {
+	RunID    string               `json:"run_id"`
+	GraphID  string               `json:"graph_id"`
+	Failures []DeadLetterResponse `json:"failures"` // Oldest first
+}
+
+// handleRunFailures lists the nodes of a run that failed permanently or ran
+// out of retries, from the dead-letter table.
+func (s *Server) handleRunFailures(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+
+	failures, err := s.executor.RunFailures(runID)
+	switch {
+	case errors.Is(err, executor.ErrRunNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, executor.ErrRunFailuresUnsupported):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to load failures of run %s: %v", runID, err)
+		http.Error(w, fmt.Sprintf("failed to load run failures: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resp := RunFailuresResponse{
+		RunID:    failures.RunID,
+		GraphID:  failures.GraphID,
+		Failures: make([]DeadLetterResponse, 0, len(failures.DeadLetters)),
+	}
+	for _, letter := range failures.DeadLetters {
+		resp.Failures = append(resp.Failures, DeadLetterResponse{
+			NodeID:    letter.NodeID,
+			NodeType:  letter.NodeType,
+			Config:    letter.Config,
+			LastError: letter.LastError,
+			FailedAt:  letter.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		serverLog.Errorf("Failed to encode run failures: %v", err)
+	}
+}
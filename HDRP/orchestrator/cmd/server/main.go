@@ -3,27 +3,29 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"net/http"
 	_ "net/http/pprof"  // Enable pprof profiling endpoints
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"hdrp/internal/bootstrap"
 	"hdrp/internal/clients"
 	"hdrp/internal/config"
 	"hdrp/internal/dag"
+	"hdrp/internal/decomposer"
 	"hdrp/internal/executor"
+	"hdrp/internal/logger"
 	"hdrp/internal/metrics"
-
-	pb "github.com/deepdag/hdrp/api/gen/services"
+	"hdrp/internal/retry"
 
 	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 // ExecuteRequest is the HTTP payload for query execution.
@@ -32,8 +34,22 @@ type ExecuteRequest struct {
 	RunID    string            `json:"run_id,omitempty"`
 	Context  map[string]string `json:"context,omitempty"`
 	Provider string            `json:"provider,omitempty"`
+
+	// Optional per-run overrides, bounded by server configuration
+	MaxRetries            *int `json:"max_retries,omitempty"`
+	IgnoreCircuitBreakers bool `json:"ignore_circuit_breakers,omitempty"`
+	Deterministic         bool `json:"deterministic,omitempty"`
+	Priority              *int `json:"priority,omitempty"` // Higher runs get shared worker slots first
 }
 
+// executeTimeout bounds decomposition and execution of one synchronous
+// /execute request. Async runs are bounded by http.async_timeout_seconds.
+// A variable so tests can shorten it.
+var executeTimeout = 5 * time.Minute
+
+// serverLog writes the server's leveled JSONL logs.
+var serverLog = logger.New("server")
+
 // ExecuteResponse contains the execution result and generated report.
 type ExecuteResponse struct {
 	RunID        string `json:"run_id"`
@@ -41,40 +57,90 @@ type ExecuteResponse struct {
 	Report       string `json:"report,omitempty"`
 	ArtifactURI  string `json:"artifact_uri,omitempty"`
 	ErrorMessage string `json:"error_message,omitempty"`
+	// ReportTruncated means Report is a preview; the full report is at ArtifactURI
+	ReportTruncated bool `json:"report_truncated,omitempty"`
 }
 
 type Server struct {
-	clients  *clients.ServiceClients
-	executor *executor.DAGExecutor
-	port     int
+	clients    *clients.ServiceClients
+	decomposer decomposer.Decomposer
+	executor   *executor.DAGExecutor
+	events     *EventHub
+	runs       *RunRegistry
+	results    *RunResults // Async runs, for polling
+	recovery   config.RecoveryConfig
+	httpConfig config.HTTPConfig
+	port       int
 }
 
 func NewServer(cfg *config.Config, port int) (*Server, error) {
 	// Use addresses from centralized config
-	svcConfig := clients.DefaultServiceConfig()
-	svcConfig.PrincipalAddr = cfg.Services.Principal.Address
-	svcConfig.ResearcherAddr = cfg.Services.Researcher.Address
-	svcConfig.CriticAddr = cfg.Services.Critic.Address
-	svcConfig.SynthesizerAddr = cfg.Services.Synthesizer.Address
+	svcConfig := bootstrap.ServiceConfig(cfg)
 
-	log.Printf("Connecting to services: Principal=%s, Researcher=%s, Critic=%s, Synthesizer=%s",
+	serverLog.Infof("Connecting to services: Principal=%s, Researcher=%s, Critic=%s, Synthesizer=%s",
 		svcConfig.PrincipalAddr, svcConfig.ResearcherAddr, svcConfig.CriticAddr, svcConfig.SynthesizerAddr)
 
 	clients, err := clients.NewServiceClients(svcConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize service clients: %w", err)
 	}
+	warmUp := time.Duration(cfg.Services.Connection.WarmUpSeconds) * time.Second
+	if cfg.Services.Connection.WaitForReady {
+		warmUpClients(clients, warmUp)
+	} else if warmUp > 0 {
+		go warmUpClients(clients, warmUp)
+	}
 
-	// Use max workers from config
-	exec := executor.NewDAGExecutor(clients, cfg.Concurrency.MaxWorkers)
+	exec, err := bootstrap.NewExecutor(cfg, clients)
+	if err != nil {
+		clients.Close()
+		return nil, err
+	}
+
+	// Stream executor events (e.g. node heartbeats) to SSE subscribers
+	events := NewEventHub()
+	exec.SetEventHandler(events.Publish)
+
+	provider := cfg.Planning.Decomposer
+	if provider == "" {
+		provider = decomposer.ProviderPrincipal
+	}
+	decomp, err := decomposer.New(provider, clients.Principal, cfg.Planning.BlueprintsDir)
+	if err != nil {
+		exec.Close()
+		clients.Close()
+		return nil, fmt.Errorf("failed to initialize decomposer: %w", err)
+	}
+	serverLog.Infof("Using %s decomposer", provider)
 
 	return &Server{
-		clients:  clients,
-		executor: exec,
-		port:     port,
+		clients:    clients,
+		decomposer: decomp,
+		executor:   exec,
+		recovery:   cfg.Recovery,
+		httpConfig: cfg.HTTP,
+		events:     events,
+		runs:       NewRunRegistry(cfg.Concurrency.MaxActiveRuns),
+		results:    NewRunResults(DefaultFinishedRunRetention),
+		port:       port,
 	}, nil
 }
 
+// warmUpClients connects to the services and health-checks them so the
+// first request doesn't wait on connection setup. timeout <= 0 uses
+// clients.DefaultReadyTimeout. Services that aren't ready only get a warning.
+func warmUpClients(svcClients *clients.ServiceClients, timeout time.Duration) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err := svcClients.WaitForReady(ctx); err != nil {
+		serverLog.Infof("Warm-up incomplete, remaining services connect on first use: %v", err)
+	}
+}
+
 func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -97,118 +163,278 @@ func (s *Server) handleExecute(w http.ResponseWriter, r *http.Request) {
 	if runID == "" {
 		runID = uuid.New().String()
 	}
+	tenant, ok := s.requestTenant(r)
+	if !ok {
+		writeUnauthorized(w, runID)
+		return
+	}
 
-	log.Printf("[Server] Received execute request: query='%s', run_id=%s", req.Query, runID)
+	serverLog.Infof("Received execute request: query='%s', run_id=%s", req.Query, runID)
 
-	// Step 1: Decompose query using Principal service
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	// Step 1: Decompose query using the configured decomposer
+	ctx, cancel := context.WithTimeout(r.Context(), executeTimeout)
 	defer cancel()
 
-	decompReq := &pb.QueryRequest{
+	graph, err := s.decomposer.Decompose(ctx, &decomposer.Request{
 		Query:   req.Query,
 		Context: req.Context,
-		RunId:   runID,
-	}
-
-	decompResp, err := s.clients.Principal.DecomposeQuery(ctx, decompReq)
+		RunID:   runID,
+	})
 	if err != nil {
-		// Extract gRPC status code and convert to HTTP status
-		if st, ok := status.FromError(err); ok {
-			switch st.Code() {
-			case codes.InvalidArgument:
-				log.Printf("[Server] Invalid argument: %v", st.Message())
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusBadRequest)
-				json.NewEncoder(w).Encode(ExecuteResponse{
-					RunID:        runID,
-					Success:      false,
-					ErrorMessage: fmt.Sprintf("Invalid query: %s", st.Message()),
-				})
-				return
-			case codes.DeadlineExceeded:
-				log.Printf("[Server] Deadline exceeded: %v", st.Message())
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusGatewayTimeout)
-				json.NewEncoder(w).Encode(ExecuteResponse{
-					RunID:        runID,
-					Success:      false,
-					ErrorMessage: fmt.Sprintf("Request timed out: %s", st.Message()),
-				})
-				return
-			default:
-				log.Printf("[Server] gRPC error: %v", st.Message())
-				s.sendErrorResponse(w, runID, fmt.Sprintf("Service error: %s", st.Message()))
-				return
-			}
+		outcome := failureOutcome(r, err)
+		metrics.RecordRequestOutcome(phaseDecomposition, outcome)
+		if outcome == outcomeClientCancelled {
+			serverLog.Infof("Client disconnected during decomposition of run %s", runID)
+			return
 		}
-		log.Printf("[Server] Principal decomposition failed: %v", err)
-		s.sendErrorResponse(w, runID, fmt.Sprintf("Query decomposition failed: %v", err))
+		serverLog.Errorf("Query decomposition failed: %v", err)
+		code, resp := MapGRPCErrorToHTTP(err, runID)
+		writeErrorResponse(w, code, resp)
 		return
 	}
 
-	// Convert protobuf Graph to internal dag.Graph
-	graph := convertProtoGraph(decompResp.Graph)
+	serverLog.Infof("Graph created with %d nodes, %d edges", len(graph.Nodes), len(graph.Edges))
+	applyRequestContext(graph, req.Context, tenant)
+
+	// Step 2: Execute the DAG, tracked in the registry while it runs. Async
+	// runs are detached from the request so they outlive the response.
+	async, _ := strconv.ParseBool(r.URL.Query().Get("async"))
+	var cancelRun context.CancelFunc
+	if async {
+		ctx, cancelRun = s.asyncRunContext()
+	} else {
+		ctx, cancelRun = context.WithCancel(ctx)
+	}
+	if err := s.runs.Register(runID, graph, cancelRun, time.Now()); err != nil {
+		cancelRun()
+		serverLog.Warnf("Run %s not started: %v", runID, err)
+		code := http.StatusConflict
+		if errors.Is(err, ErrTooManyRuns) {
+			code = http.StatusTooManyRequests
+		}
+		writeErrorResponse(w, code, ExecuteResponse{
+			RunID:        runID,
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("Run not started: %v", err),
+		})
+		return
+	}
 
-	log.Printf("[Server] Graph created with %d nodes, %d edges", len(graph.Nodes), len(graph.Edges))
+	if async {
+		s.runAsync(ctx, cancelRun, graph, runID, req)
+		serverLog.Infof("Run %s accepted for async execution", runID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(AcceptedResponse{RunID: runID, StatusURL: "/runs/" + runID})
+		return
+	}
+	defer cancelRun()
+	defer s.runs.Deregister(runID)
 
-	// Step 2: Execute the DAG
-	result, err := s.executor.Execute(ctx, graph, runID)
+	result, err := s.executor.ExecuteWithOptions(ctx, graph, runID, runOptions(req))
 	if err != nil {
-		log.Printf("[Server] Execution failed: %v", err)
-		s.sendErrorResponse(w, runID, fmt.Sprintf("Execution failed: %v", err))
+		outcome := failureOutcome(r, err)
+		metrics.RecordRequestOutcome(phaseExecution, outcome)
+		if outcome == outcomeClientCancelled {
+			serverLog.Infof("Client disconnected during execution of run %s", runID)
+			return
+		}
+		serverLog.Errorf("Execution failed: %v", err)
+		code, resp := executionErrorResponse(runID, err)
+		writeErrorResponse(w, code, resp)
 		return
 	}
+	recordExecutionResult(result)
 
 	// Step 3: Return response
-	resp := ExecuteResponse{
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resultResponse(runID, result)); err != nil {
+		serverLog.Errorf("Failed to encode response: %v", err)
+	}
+
+	serverLog.Infof("Request completed: run_id=%s, success=%v", runID, result.Success)
+}
+
+// runOptions maps a request's per-run overrides to executor options.
+func runOptions(req ExecuteRequest) executor.RunOptions {
+	return executor.RunOptions{
+		MaxAttempts:           req.MaxRetries,
+		IgnoreCircuitBreakers: req.IgnoreCircuitBreakers,
+		Deterministic:         req.Deterministic,
+		Priority:              req.Priority,
+	}
+}
+
+// executionErrorResponse builds the status and body for a run the executor
+// could not complete.
+func executionErrorResponse(runID string, err error) (int, ExecuteResponse) {
+	msg := fmt.Sprintf("Execution failed: %v", err)
+	if errors.Is(err, dag.ErrNodeTypeNotAllowed) {
+		msg = fmt.Sprintf("Plan rejected: %v", err)
+	} else if serverFailureOutcome(err) == outcomeCancelled {
+		msg = "Run cancelled"
+	}
+	return StatusFromExecutionError(err), ExecuteResponse{
 		RunID:        runID,
-		Success:      result.Success,
-		Report:       result.FinalReport,
-		ArtifactURI:  result.ArtifactURI,
-		ErrorMessage: result.ErrorMessage,
+		Success:      false,
+		ErrorMessage: msg,
 	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Printf("[Server] Failed to encode response: %v", err)
+// resultResponse builds the response for a finished run.
+func resultResponse(runID string, result *executor.ExecutionResult) ExecuteResponse {
+	return ExecuteResponse{
+		RunID:           runID,
+		Success:         result.Success,
+		Report:          result.FinalReport,
+		ArtifactURI:     result.ArtifactURI,
+		ErrorMessage:    result.ErrorMessage,
+		ReportTruncated: result.ReportTruncated,
 	}
+}
 
-	log.Printf("[Server] Request completed: run_id=%s, success=%v", runID, result.Success)
+// recordExecutionResult counts a finished run's outcome.
+func recordExecutionResult(result *executor.ExecutionResult) {
+	if result.Success {
+		metrics.RecordRequestOutcome(phaseExecution, outcomeSuccess)
+	} else {
+		metrics.RecordRequestOutcome(phaseExecution, outcomeFailed)
+	}
 }
 
-func (s *Server) sendErrorResponse(w http.ResponseWriter, runID string, errMsg string) {
-	resp := ExecuteResponse{
+// applyRequestContext copies request context the executor acts on into the
+// graph metadata: config.* entries become defaults for every node's config.
+// The tenant, which decides which node types the plan may contain, is the
+// one the request authenticated as; a tenant in the context is ignored.
+func applyRequestContext(graph *dag.Graph, reqContext map[string]string, tenant string) {
+	if graph.Metadata == nil {
+		graph.Metadata = make(map[string]string)
+	}
+	for k, v := range reqContext {
+		if strings.HasPrefix(k, dag.MetadataConfigPrefix) {
+			graph.Metadata[k] = v
+		}
+	}
+	if tenant != "" {
+		graph.Metadata[dag.MetadataTenant] = tenant
+	} else {
+		delete(graph.Metadata, dag.MetadataTenant)
+	}
+}
+
+// writeUnauthorized rejects a request whose API key belongs to no tenant.
+func writeUnauthorized(w http.ResponseWriter, runID string) {
+	writeErrorResponse(w, http.StatusUnauthorized, ExecuteResponse{
 		RunID:        runID,
 		Success:      false,
-		ErrorMessage: errMsg,
+		ErrorMessage: "Invalid API key",
+	})
+}
+
+// recoverAbandonedGraphs resumes graphs left RUNNING by a crashed instance,
+// throttled by the configured recovery concurrency.
+func (s *Server) recoverAbandonedGraphs(ctx context.Context) {
+	opts := executor.RecoveryOptions{
+		Concurrency: s.recovery.Concurrency,
+		LeaseTTL:    time.Duration(s.recovery.LeaseSeconds) * time.Second,
+	}
+	count, err := s.executor.RecoverAbandonedGraphs(ctx, opts, s.resumeGraph)
+	if err != nil {
+		serverLog.Errorf("Graph recovery failed after %d graphs: %v", count, err)
+		return
+	}
+	serverLog.Infof("Recovered %d abandoned graphs", count)
+}
+
+// resumeGraph resumes a recovered graph, tracking it in the run registry.
+func (s *Server) resumeGraph(ctx context.Context, graph *dag.Graph) error {
+	runID := graph.Metadata["run_id"]
+	if runID == "" {
+		runID = graph.ID
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := s.runs.Register(runID, graph, cancel, time.Now()); err != nil {
+		return fmt.Errorf("failed to register resumed run: %w", err)
+	}
+	defer s.runs.Deregister(runID)
+
+	_, err := s.executor.ResumeGraph(ctx, graph)
+	return err
+}
+
+// HealthResponse is returned by /health. Services is only populated with ?detail=true.
+type HealthResponse struct {
+	Status   string                `json:"status"`
+	Services []retry.ServiceHealth `json:"services,omitempty"`
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	resp := HealthResponse{Status: "healthy"}
+	if detail, _ := strconv.ParseBool(r.URL.Query().Get("detail")); detail {
+		// Rolling success ratio per node type across recent runs
+		resp.Services = s.executor.ServiceHealth().AllHealth()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
 	json.NewEncoder(w).Encode(resp)
 }
 
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+// handleIntegrity reports inconsistencies in persisted DAG state without modifying it.
+func (s *Server) handleIntegrity(w http.ResponseWriter, r *http.Request) {
+	report, err := s.executor.CheckStorageIntegrity()
+	if errors.Is(err, executor.ErrIntegrityUnsupported) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		serverLog.Errorf("Integrity check failed: %v", err)
+		http.Error(w, fmt.Sprintf("integrity check failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	if !report.OK() {
+		serverLog.Warnf("Integrity check found %d issues", len(report.Issues))
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
+	json.NewEncoder(w).Encode(report)
 }
 
 func (s *Server) Start() error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/execute", s.handleExecute)
 	mux.HandleFunc("/health", s.handleHealth)
+	mux.HandleFunc("POST /plan", s.handlePlan)
+	mux.HandleFunc("GET /runs/active", s.handleActiveRuns)
+	mux.HandleFunc("GET /runs/{id}", s.handleRunStatus)
+	mux.HandleFunc("GET /runs/{id}/result", s.handleRunResult)
+	mux.HandleFunc("POST /runs/{id}/cancel", s.handleCancelRun)
+	mux.HandleFunc("POST /runs/{id}/pause", s.handlePauseRun)
+	mux.HandleFunc("POST /runs/{id}/resume", s.handleResumeRun)
+	mux.HandleFunc("GET /runs/{id}/events", s.handleRunEvents)
+	mux.HandleFunc("GET /runs/{id}/timeline", s.handleRunTimeline)
+	mux.HandleFunc("GET /runs/{id}/failures", s.handleRunFailures)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /admin/integrity", s.handleIntegrity)
+	mux.HandleFunc("POST /debug/replay-node", s.handleReplayNode)
 	// Expose Prometheus metrics endpoint
 	mux.Handle("/metrics", metrics.GetMetricsHandler())
 
 	addr := fmt.Sprintf(":%d", s.port)
 	server := &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: withCompression(mux, s.httpConfig.Compression),
 	}
 
-	log.Printf("Orchestrator server starting on %s", addr)
-	log.Printf("Metrics available at http://localhost%s/metrics", addr)
-	log.Printf("Profiling endpoints available at http://localhost%s/debug/pprof/", addr)
+	if s.recovery.Enabled {
+		go s.recoverAbandonedGraphs(context.Background())
+	}
+
+	serverLog.Infof("Orchestrator server starting on %s", addr)
+	serverLog.Infof("Metrics available at http://localhost%s/metrics", addr)
+	serverLog.Infof("Profiling endpoints available at http://localhost%s/debug/pprof/", addr)
 
 	// Graceful shutdown
 	go func() {
@@ -216,56 +442,26 @@ func (s *Server) Start() error {
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 		<-sigChan
 
-		log.Println("Shutting down orchestrator server...")
+		serverLog.Infof("Shutting down orchestrator server...")
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
 		if err := server.Shutdown(ctx); err != nil {
-			log.Printf("Server shutdown error: %v", err)
+			serverLog.Errorf("Server shutdown error: %v", err)
 		}
 
 		s.clients.Close()
 		
 		// Shutdown tracing
 		if err := metrics.ShutdownTracing(); err != nil {
-			log.Printf("Tracing shutdown error: %v", err)
+			serverLog.Errorf("Tracing shutdown error: %v", err)
 		}
 	}()
 
 	return server.ListenAndServe()
 }
 
-func convertProtoGraph(pbGraph *pb.Graph) *dag.Graph {
-	nodes := make([]dag.Node, len(pbGraph.Nodes))
-	for i, pbNode := range pbGraph.Nodes {
-		nodes[i] = dag.Node{
-			ID:             pbNode.Id,
-			Type:           pbNode.Type,
-			Config:         pbNode.Config,
-			Status:         dag.Status(pbNode.Status),
-			RelevanceScore: pbNode.RelevanceScore,
-			Depth:          int(pbNode.Depth),
-		}
-	}
-
-	edges := make([]dag.Edge, len(pbGraph.Edges))
-	for i, pbEdge := range pbGraph.Edges {
-		edges[i] = dag.Edge{
-			From: pbEdge.From,
-			To:   pbEdge.To,
-		}
-	}
-
-	return &dag.Graph{
-		ID:       pbGraph.Id,
-		Nodes:    nodes,
-		Edges:    edges,
-		Status:   dag.StatusCreated,
-		Metadata: pbGraph.Metadata,
-	}
-}
-
 func main() {
 	port := flag.Int("port", 50055, "Orchestrator server port")
 	configPath := flag.String("config", "", "Path to config file (default: ../config/config.yaml)")
@@ -276,26 +472,26 @@ func main() {
 	// Load configuration
 	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		serverLog.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	log.Printf("Loaded configuration for environment: %s", cfg.Environment)
+	serverLog.Infof("Loaded configuration for environment: %s", cfg.Environment)
 
 	// Initialize tracing if enabled
 	if *enableTracing {
 		if err := metrics.InitTracing("hdrp-orchestrator", *otlpEndpoint); err != nil {
-			log.Printf("Warning: Failed to initialize tracing: %v", err)
+			serverLog.Warnf("Failed to initialize tracing: %v", err)
 		} else {
-			log.Printf("OpenTelemetry tracing initialized with endpoint: %s", *otlpEndpoint)
+			serverLog.Infof("OpenTelemetry tracing initialized with endpoint: %s", *otlpEndpoint)
 		}
 	}
 
 	server, err := NewServer(cfg, *port)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		serverLog.Fatalf("Failed to create server: %v", err)
 	}
 
 	if err := server.Start(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server error: %v", err)
+		serverLog.Fatalf("Server error: %v", err)
 	}
 }
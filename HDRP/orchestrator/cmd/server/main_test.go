@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain runs the server tests from a scratch directory so executor
+// checkpoints and the SQLite store stay out of the source tree.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "server-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to chdir to temp dir: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
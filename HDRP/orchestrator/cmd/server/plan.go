@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/decomposer"
+	"hdrp/internal/executor"
+)
+
+// PlanResponse is returned by /plan: the graph a query decomposes into and
+// what running it is expected to take.
+type PlanResponse struct {
+	RunID    string             `json:"run_id"`
+	Graph    *dag.Graph         `json:"graph"`
+	Estimate *executor.Estimate `json:"estimate"`
+}
+
+// handlePlan decomposes a query and estimates its cost and duration without
+// executing it. It accepts the same body as /execute.
+func (s *Server) handlePlan(w http.ResponseWriter, r *http.Request) {
+	var req ExecuteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "Query is required", http.StatusBadRequest)
+		return
+	}
+
+	runID := req.RunID
+	if runID == "" {
+		runID = uuid.New().String()
+	}
+	tenant, ok := s.requestTenant(r)
+	if !ok {
+		writeUnauthorized(w, runID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	graph, err := s.decomposer.Decompose(ctx, &decomposer.Request{
+		Query:   req.Query,
+		Context: req.Context,
+		RunID:   runID,
+	})
+	if err != nil {
+		serverLog.Errorf("Query decomposition failed: %v", err)
+		code, resp := MapGRPCErrorToHTTP(err, runID)
+		writeErrorResponse(w, code, resp)
+		return
+	}
+	applyRequestContext(graph, req.Context, tenant)
+	graph.ApplyConfigDefaults()
+
+	estimate, err := s.executor.Estimate(graph)
+	if err != nil {
+		writeErrorResponse(w, http.StatusUnprocessableEntity, ExecuteResponse{
+			RunID:        runID,
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("Plan rejected: %v", err),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(PlanResponse{RunID: runID, Graph: graph, Estimate: estimate}); err != nil {
+		serverLog.Errorf("Failed to encode plan: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hdrp/internal/executor"
+)
+
+// ReplayNodeRequest names the node /debug/replay-node re-executes.
+type ReplayNodeRequest struct {
+	RunID  string `json:"run_id"`
+	NodeID string `json:"node_id"`
+}
+
+// handleReplayNode re-executes one node of a stored run from its persisted
+// config and parent results, returning the captured requests, responses and
+// error classification. The stored run is left unchanged.
+func (s *Server) handleReplayNode(w http.ResponseWriter, r *http.Request) {
+	var req ReplayNodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.RunID == "" || req.NodeID == "" {
+		http.Error(w, "run_id and node_id are required", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Minute)
+	defer cancel()
+
+	diag, err := s.executor.ReplayNode(ctx, req.RunID, req.NodeID)
+	switch {
+	case errors.Is(err, executor.ErrRunNotFound), errors.Is(err, executor.ErrNodeNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, executor.ErrReplayUnsupported):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to replay node %s of run %s: %v", req.NodeID, req.RunID, err)
+		http.Error(w, fmt.Sprintf("replay failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diag); err != nil {
+		serverLog.Errorf("Failed to encode replay diagnostic: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/executor"
+)
+
+var (
+	// ErrRunExists is returned when registering a run ID that is already active.
+	ErrRunExists = errors.New("run is already active")
+	// ErrTooManyRuns is returned when the registry is at its active run limit.
+	ErrTooManyRuns = errors.New("too many active runs")
+)
+
+// activeRun is a run the server is currently executing.
+type activeRun struct {
+	runID     string
+	graph     *dag.Graph
+	cancel    context.CancelFunc
+	startTime time.Time
+}
+
+// RunProgress summarizes an active run for introspection.
+type RunProgress struct {
+	RunID     string         `json:"run_id"`
+	GraphID   string         `json:"graph_id"`
+	StartedAt time.Time      `json:"started_at"`
+	ElapsedMs int64          `json:"elapsed_ms"`
+	Nodes     int            `json:"nodes"`
+	Completed int            `json:"completed"`        // Nodes in a terminal status
+	Statuses  map[string]int `json:"statuses"`         // Node count per status
+	Phases    map[string]int `json:"phases,omitempty"` // Node count per phase, for nodes with one
+}
+
+// RunRegistry tracks the runs this server is executing so they can be
+// listed, inspected and cancelled. It is safe for concurrent use.
+type RunRegistry struct {
+	mu        sync.RWMutex
+	runs      map[string]*activeRun
+	maxActive int // <= 0 means unlimited
+}
+
+// NewRunRegistry creates a registry admitting at most maxActive concurrent
+// runs. maxActive <= 0 means unlimited.
+func NewRunRegistry(maxActive int) *RunRegistry {
+	return &RunRegistry{
+		runs:      make(map[string]*activeRun),
+		maxActive: maxActive,
+	}
+}
+
+// Register records a run as active. cancel stops the run's execution.
+func (r *RunRegistry) Register(runID string, graph *dag.Graph, cancel context.CancelFunc, startTime time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.runs[runID]; ok {
+		return fmt.Errorf("%w: %s", ErrRunExists, runID)
+	}
+	if r.maxActive > 0 && len(r.runs) >= r.maxActive {
+		return fmt.Errorf("%w: limit is %d", ErrTooManyRuns, r.maxActive)
+	}
+	r.runs[runID] = &activeRun{runID: runID, graph: graph, cancel: cancel, startTime: startTime}
+	return nil
+}
+
+// Deregister removes a run once it has finished. Unknown IDs are ignored.
+func (r *RunRegistry) Deregister(runID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.runs, runID)
+}
+
+// Cancel stops an active run's execution. Returns false if the run is not
+// active.
+func (r *RunRegistry) Cancel(runID string) bool {
+	r.mu.RLock()
+	run, ok := r.runs[runID]
+	r.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	run.cancel()
+	return true
+}
+
+// Lookup returns the progress of an active run.
+func (r *RunRegistry) Lookup(runID string) (RunProgress, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	run, ok := r.runs[runID]
+	if !ok {
+		return RunProgress{}, false
+	}
+	return run.progress(time.Now()), true
+}
+
+// List returns the progress of every active run, oldest first.
+func (r *RunRegistry) List() []RunProgress {
+	r.mu.RLock()
+	now := time.Now()
+	runs := make([]RunProgress, 0, len(r.runs))
+	for _, run := range r.runs {
+		runs = append(runs, run.progress(now))
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(runs, func(i, j int) bool {
+		if !runs[i].StartedAt.Equal(runs[j].StartedAt) {
+			return runs[i].StartedAt.Before(runs[j].StartedAt)
+		}
+		return runs[i].RunID < runs[j].RunID
+	})
+	return runs
+}
+
+// Len returns the number of active runs.
+func (r *RunRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.runs)
+}
+
+// progress summarizes node statuses, from a snapshot since the executor
+// may be updating them.
+func (run *activeRun) progress(now time.Time) RunProgress {
+	p := RunProgress{
+		RunID:     run.runID,
+		GraphID:   run.graph.ID,
+		StartedAt: run.startTime,
+		ElapsedMs: now.Sub(run.startTime).Milliseconds(),
+		Nodes:     len(run.graph.Nodes),
+		Statuses:  make(map[string]int),
+	}
+	for _, n := range run.graph.NodeSnapshot() {
+		p.Statuses[string(n.Status)]++
+		if n.Phase != "" {
+			if p.Phases == nil {
+				p.Phases = make(map[string]int)
+			}
+			p.Phases[n.Phase]++
+		}
+		switch n.Status {
+		case dag.StatusSucceeded, dag.StatusFailed, dag.StatusCancelled, dag.StatusSkipped:
+			p.Completed++
+		}
+	}
+	return p
+}
+
+// ActiveRunsResponse is returned by GET /runs/active.
+type ActiveRunsResponse struct {
+	Runs []RunProgress `json:"runs"`
+}
+
+// handleActiveRuns lists the runs currently executing on this server.
+func (s *Server) handleActiveRuns(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ActiveRunsResponse{Runs: s.runs.List()})
+}
+
+// CancelRunResponse is returned by POST /runs/{id}/cancel.
+type CancelRunResponse struct {
+	RunID     string `json:"run_id"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+// handleCancelRun stops an active run. The run's unfinished nodes end up
+// CANCELLED; its /execute response, or its status for async runs, reports
+// the cancellation.
+func (s *Server) handleCancelRun(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	if !s.runs.Cancel(runID) {
+		http.Error(w, "run not found or already finished", http.StatusNotFound)
+		return
+	}
+	serverLog.Infof("Cancellation requested for run %s", runID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(CancelRunResponse{RunID: runID, Cancelled: true})
+}
+
+// PauseRunResponse is returned by POST /runs/{id}/pause and /resume.
+type PauseRunResponse struct {
+	RunID  string `json:"run_id"`
+	Paused bool   `json:"paused"`
+}
+
+// handlePauseRun stops an active run from scheduling new nodes. Nodes
+// already running finish; the run waits, still cancellable, until resumed.
+func (s *Server) handlePauseRun(w http.ResponseWriter, r *http.Request) {
+	s.setRunPaused(w, r.PathValue("id"), true)
+}
+
+// handleResumeRun lets a paused run schedule nodes again.
+func (s *Server) handleResumeRun(w http.ResponseWriter, r *http.Request) {
+	s.setRunPaused(w, r.PathValue("id"), false)
+}
+
+func (s *Server) setRunPaused(w http.ResponseWriter, runID string, pause bool) {
+	var err error
+	if pause {
+		err = s.executor.Pause(runID)
+	} else {
+		err = s.executor.Resume(runID)
+	}
+	switch {
+	case errors.Is(err, executor.ErrRunNotFound):
+		http.Error(w, "run not found or already finished", http.StatusNotFound)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to pause or resume run %s: %v", runID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(PauseRunResponse{RunID: runID, Paused: pause})
+}
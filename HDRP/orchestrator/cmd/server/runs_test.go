@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+func registryTestGraph(id string) *dag.Graph {
+	return &dag.Graph{
+		ID: id,
+		Nodes: []dag.Node{
+			{ID: "a", Status: dag.StatusSucceeded},
+			{ID: "b", Status: dag.StatusRunning, Phase: dag.PhaseBackoff},
+			{ID: "c", Status: dag.StatusBlocked},
+		},
+	}
+}
+
+func TestRunRegistry_RegisterLookup(t *testing.T) {
+	registry := NewRunRegistry(2)
+	start := time.Now().Add(-time.Second)
+
+	if err := registry.Register("run-1", registryTestGraph("graph-1"), func() {}, start); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register("run-1", registryTestGraph("graph-1"), func() {}, start); !errors.Is(err, ErrRunExists) {
+		t.Errorf("Duplicate Register() error = %v, want ErrRunExists", err)
+	}
+	if err := registry.Register("run-2", registryTestGraph("graph-2"), func() {}, start.Add(time.Millisecond)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := registry.Register("run-3", registryTestGraph("graph-3"), func() {}, start); !errors.Is(err, ErrTooManyRuns) {
+		t.Errorf("Register() over limit error = %v, want ErrTooManyRuns", err)
+	}
+
+	progress, ok := registry.Lookup("run-1")
+	if !ok {
+		t.Fatal("Expected run-1 to be registered")
+	}
+	if progress.GraphID != "graph-1" || progress.Nodes != 3 || progress.Completed != 1 {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+	if progress.Statuses["RUNNING"] != 1 || progress.ElapsedMs < 1000 {
+		t.Errorf("Unexpected progress: %+v", progress)
+	}
+	if len(progress.Phases) != 1 || progress.Phases[dag.PhaseBackoff] != 1 {
+		t.Errorf("Phases = %v, want one node in BACKOFF", progress.Phases)
+	}
+
+	if runs := registry.List(); len(runs) != 2 || runs[0].RunID != "run-1" || runs[1].RunID != "run-2" {
+		t.Errorf("List() = %+v, want run-1 then run-2", runs)
+	}
+
+	registry.Deregister("run-1")
+	if _, ok := registry.Lookup("run-1"); ok {
+		t.Error("Expected run-1 to be gone after Deregister")
+	}
+	if err := registry.Register("run-3", registryTestGraph("graph-3"), func() {}, start); err != nil {
+		t.Errorf("Register() after Deregister freed a slot: %v", err)
+	}
+}
+
+func TestRunRegistry_ConcurrentAccess(t *testing.T) {
+	registry := NewRunRegistry(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			runID := fmt.Sprintf("run-%d", i)
+			if err := registry.Register(runID, registryTestGraph("graph"), func() {}, time.Now()); err != nil {
+				t.Errorf("Register(%s) error = %v", runID, err)
+				return
+			}
+			registry.List()
+			if _, ok := registry.Lookup(runID); !ok {
+				t.Errorf("Lookup(%s) missed a registered run", runID)
+			}
+			registry.Deregister(runID)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := registry.Len(); n != 0 {
+		t.Errorf("Expected empty registry, got %d runs", n)
+	}
+}
+
+// gatedResearcher blocks until released, signalling when a call starts.
+type gatedResearcher struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *gatedResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	g.started <- struct{}{}
+	select {
+	case <-g.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func TestHandleActiveRuns(t *testing.T) {
+	researcher := &gatedResearcher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = researcher
+
+	done := make(chan int)
+	go func() {
+		body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-active"})
+		rec := httptest.NewRecorder()
+		s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+		done <- rec.Code
+	}()
+	<-researcher.started
+
+	rec := httptest.NewRecorder()
+	s.handleActiveRuns(rec, httptest.NewRequest(http.MethodGet, "/runs/active", nil))
+	var resp ActiveRunsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Runs) != 1 || resp.Runs[0].RunID != "run-active" {
+		t.Fatalf("Expected run-active to be listed, got %+v", resp.Runs)
+	}
+	if resp.Runs[0].Nodes != 1 || resp.Runs[0].Completed != 0 {
+		t.Errorf("Unexpected progress: %+v", resp.Runs[0])
+	}
+
+	close(researcher.release)
+	if code := <-done; code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if n := s.runs.Len(); n != 0 {
+		t.Errorf("Expected the run to be deregistered on completion, %d still active", n)
+	}
+}
+
+func TestHandleCancelRun(t *testing.T) {
+	researcher := &gatedResearcher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = researcher
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-cancel"})
+		rec := httptest.NewRecorder()
+		s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+		done <- rec
+	}()
+	<-researcher.started
+
+	cancelRun := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/runs/run-cancel/cancel", nil)
+		req.SetPathValue("id", "run-cancel")
+		rec := httptest.NewRecorder()
+		s.handleCancelRun(rec, req)
+		return rec.Code
+	}
+	if code := cancelRun(); code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", code)
+	}
+
+	rec := <-done
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 for the cancelled run, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp ExecuteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Success || resp.ErrorMessage != "Run cancelled" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+
+	if code := cancelRun(); code != http.StatusNotFound {
+		t.Errorf("Expected 404 cancelling a finished run, got %d", code)
+	}
+}
+
+func TestHandlePauseRun(t *testing.T) {
+	researcher := &gatedResearcher{started: make(chan struct{}, 1), release: make(chan struct{})}
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = researcher
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-pause"})
+		rec := httptest.NewRecorder()
+		s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+		done <- rec
+	}()
+	<-researcher.started
+
+	call := func(handler http.HandlerFunc, method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.SetPathValue("id", "run-pause")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+	if rec := call(s.handlePauseRun, http.MethodPost, "/runs/run-pause/pause"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 pausing, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var status RunStatusResponse
+	rec := call(s.handleRunStatus, http.MethodGet, "/runs/run-pause")
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("Failed to decode status: %v", err)
+	}
+	if status.Status != RunStatusPaused || !status.Paused {
+		t.Errorf("Expected a PAUSED status, got %+v", status)
+	}
+
+	if rec := call(s.handleResumeRun, http.MethodPost, "/runs/run-pause/resume"); rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 resuming, got %d: %s", rec.Code, rec.Body.String())
+	}
+	close(researcher.release)
+	if rec := <-done; rec.Code != http.StatusOK {
+		t.Fatalf("Expected the resumed run to succeed, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec := call(s.handlePauseRun, http.MethodPost, "/runs/run-pause/pause"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 pausing a finished run, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,577 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/config"
+	"hdrp/internal/dag"
+	"hdrp/internal/decomposer"
+	"hdrp/internal/executor"
+	"hdrp/internal/generator"
+	"hdrp/internal/intent"
+	"hdrp/internal/retry"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeResearcher struct{}
+
+func (fakeResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+type fakeCritic struct{}
+
+func (fakeCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	return &pb.VerifyResponse{VerifiedCount: int32(len(req.Claims))}, nil
+}
+
+type fakeSynthesizer struct{}
+
+func (fakeSynthesizer) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
+	return &pb.SynthesizeResponse{Report: "report"}, nil
+}
+
+// newTestServer builds a Server with fake backends and no Principal client.
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	svcClients := &clients.ServiceClients{
+		Researcher:  fakeResearcher{},
+		Critic:      fakeCritic{},
+		Synthesizer: fakeSynthesizer{},
+	}
+	exec := executor.NewDAGExecutor(svcClients, 2)
+	exec.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 0})
+	t.Cleanup(func() { exec.Close() })
+
+	return &Server{
+		clients:    svcClients,
+		decomposer: decomposer.NewLocalDecomposer(intent.NewBasicParser(), generator.NewTemplateGenerator()),
+		executor:   exec,
+		events:     NewEventHub(),
+		runs:       NewRunRegistry(0),
+		results:    NewRunResults(0),
+	}
+}
+
+func TestHandleExecute_LocalDecomposer(t *testing.T) {
+	s := newTestServer(t)
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "Research solid-state batteries", RunID: "run-local"})
+	req := httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	s.handleExecute(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200 without a Principal backend, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp ExecuteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.RunID != "run-local" {
+		t.Errorf("Expected run_id run-local, got %q", resp.RunID)
+	}
+	if strings.Contains(resp.ErrorMessage, "decomposition") {
+		t.Errorf("Local decomposition should not fail: %s", resp.ErrorMessage)
+	}
+}
+
+// flakyResearcher always fails with a transient error and counts calls.
+type flakyResearcher struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (f *flakyResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.count++
+	return nil, status.Error(codes.Unavailable, "researcher down")
+}
+
+func (f *flakyResearcher) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.count
+}
+
+// singleResearcherDecomposer plans a graph with one researcher node.
+type singleResearcherDecomposer struct{}
+
+func (singleResearcherDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	return &dag.Graph{
+		ID:     "graph-" + req.RunID,
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher", Type: "researcher", Config: map[string]string{"query": req.Query}, Status: dag.StatusCreated},
+		},
+	}, nil
+}
+
+func TestHandleExecute_RetryOverride(t *testing.T) {
+	researcher := &flakyResearcher{}
+	exec := executor.NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      fakeCritic{},
+		Synthesizer: fakeSynthesizer{},
+	}, 2)
+	t.Cleanup(func() { exec.Close() })
+	exec.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+
+	s := &Server{decomposer: singleResearcherDecomposer{}, executor: exec, events: NewEventHub(), runs: NewRunRegistry(0)}
+
+	maxRetries := 0
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-override", MaxRetries: &maxRetries})
+	rec := httptest.NewRecorder()
+	s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := researcher.calls(); got != 1 {
+		t.Errorf("Expected a single attempt with max_retries=0, got %d", got)
+	}
+}
+
+// errorDecomposer fails every request with a fixed error.
+type errorDecomposer struct{ err error }
+
+func (d errorDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	return nil, d.err
+}
+
+func TestHandleExecute_DecomposeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantMsg  string
+	}{
+		{"Invalid query", status.Error(codes.InvalidArgument, "empty"), http.StatusBadRequest, "Invalid query: empty"},
+		{"Timeout", status.Error(codes.DeadlineExceeded, "slow"), http.StatusGatewayTimeout, "Request timed out: slow"},
+		{"Principal down", status.Error(codes.Unavailable, "down"), http.StatusServiceUnavailable, "Service error: down"},
+		{"Local failure", errors.New("no template"), http.StatusInternalServerError, "Query decomposition failed: no template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{decomposer: errorDecomposer{err: tt.err}, executor: newTestServer(t).executor, events: NewEventHub(), runs: NewRunRegistry(0)}
+
+			body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-decompose-error"})
+			rec := httptest.NewRecorder()
+			s.handleExecute(rec, httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d", tt.wantCode, rec.Code)
+			}
+			var resp ExecuteResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.ErrorMessage != tt.wantMsg || resp.RunID != "run-decompose-error" {
+				t.Errorf("Unexpected response: %+v", resp)
+			}
+		})
+	}
+}
+
+// blockingDecomposer blocks until the request context ends.
+type blockingDecomposer struct{ started chan struct{} }
+
+func (d blockingDecomposer) Decompose(ctx context.Context, req *decomposer.Request) (*dag.Graph, error) {
+	close(d.started)
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+// requestOutcomeCount reads hdrp_execute_requests_total for a phase and status.
+func requestOutcomeCount(t *testing.T, phase, outcome string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != "hdrp_execute_requests_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["phase"] == phase && labels["status"] == outcome {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestHandleExecute_ClientDisconnectDuringDecomposition(t *testing.T) {
+	started := make(chan struct{})
+	s := &Server{decomposer: blockingDecomposer{started: started}, executor: newTestServer(t).executor, events: NewEventHub(), runs: NewRunRegistry(0)}
+	cancelledBefore := requestOutcomeCount(t, phaseDecomposition, outcomeClientCancelled)
+	errorsBefore := requestOutcomeCount(t, phaseDecomposition, outcomeError)
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-disconnect"})
+	req := httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.handleExecute(rec, req)
+	}()
+	<-started
+	disconnect()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handleExecute did not return after the client disconnected")
+	}
+
+	if got := requestOutcomeCount(t, phaseDecomposition, outcomeClientCancelled); got != cancelledBefore+1 {
+		t.Errorf("Expected the disconnect to be counted as client_cancelled, count went %v -> %v", cancelledBefore, got)
+	}
+	if got := requestOutcomeCount(t, phaseDecomposition, outcomeError); got != errorsBefore {
+		t.Errorf("Disconnect was counted as an error, count went %v -> %v", errorsBefore, got)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected no response for a disconnected client, got %q", rec.Body.String())
+	}
+}
+
+func TestFailureOutcome(t *testing.T) {
+	live := httptest.NewRequest(http.MethodPost, "/execute", nil)
+	if got := failureOutcome(live, status.Error(codes.DeadlineExceeded, "slow")); got != outcomeTimeout {
+		t.Errorf("Deadline: got %s, want %s", got, outcomeTimeout)
+	}
+	if got := failureOutcome(live, errors.New("boom")); got != outcomeError {
+		t.Errorf("Error: got %s, want %s", got, outcomeError)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gone := live.WithContext(ctx)
+	if got := failureOutcome(gone, status.Error(codes.Canceled, "canceled")); got != outcomeClientCancelled {
+		t.Errorf("Disconnect: got %s, want %s", got, outcomeClientCancelled)
+	}
+}
+
+func TestHandleExecute_NodeTypeAllowlist(t *testing.T) {
+	s := &Server{decomposer: singleResearcherDecomposer{}, executor: newTestServer(t).executor, events: NewEventHub(), runs: NewRunRegistry(0)}
+	s.httpConfig.TenantKeys = map[string]string{"restricted": "restricted-key", "open": "open-key"}
+	s.executor.SetNodeTypeAllowlist(&dag.NodeTypeAllowlist{
+		Global:  []string{"critic"},
+		Tenants: map[string][]string{"restricted": {}, "open": {"researcher"}},
+	})
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		tenant   string // Claimed in the request context, which must not count
+		wantCode int
+	}{
+		{name: "No key uses global list", wantCode: http.StatusForbidden},
+		{name: "Key selects tenant list", apiKey: "open-key", wantCode: http.StatusOK},
+		{name: "Empty tenant list allows nothing", apiKey: "restricted-key", wantCode: http.StatusForbidden},
+		{name: "Context tenant ignored", tenant: "open", wantCode: http.StatusForbidden},
+		{name: "Context cannot widen a key's tenant", apiKey: "restricted-key", tenant: "open", wantCode: http.StatusForbidden},
+		{name: "Unknown key", apiKey: "wrong-key", wantCode: http.StatusUnauthorized},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(ExecuteRequest{
+				Query:   "q",
+				RunID:   fmt.Sprintf("run-allowlist-%d", i),
+				Context: map[string]string{dag.MetadataTenant: tt.tenant},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body))
+			if tt.apiKey != "" {
+				req.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			s.handleExecute(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleIntegrity(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "integrity.db"))
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.handleIntegrity(rec, httptest.NewRequest(http.MethodGet, "/admin/integrity", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var report storage.IntegrityReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("Expected no issues on a fresh database, got %+v", report.Issues)
+	}
+}
+
+func TestHandleReplayNode_Errors(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "replay.db"))
+	s := newTestServer(t)
+
+	tests := []struct {
+		body     string
+		wantCode int
+	}{
+		{`{"run_id": "run-1"}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+		{`{"run_id": "run-unknown", "node_id": "n1"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.handleReplayNode(rec, httptest.NewRequest(http.MethodPost, "/debug/replay-node", strings.NewReader(tt.body)))
+		if rec.Code != tt.wantCode {
+			t.Errorf("Body %s: expected %d, got %d: %s", tt.body, tt.wantCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestHandleHealth_Detail(t *testing.T) {
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-health"})
+	s.handleExecute(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+	tests := []struct {
+		target       string
+		wantServices int
+	}{
+		{target: "/health", wantServices: 0},
+		{target: "/health?detail=true", wantServices: 1},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.handleHealth(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		var resp HealthResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", tt.target, err)
+		}
+		if resp.Status != "healthy" {
+			t.Errorf("%s: expected status healthy, got %q", tt.target, resp.Status)
+		}
+		if len(resp.Services) != tt.wantServices {
+			t.Fatalf("%s: expected %d services, got %+v", tt.target, tt.wantServices, resp.Services)
+		}
+		for _, got := range resp.Services {
+			if got.ServiceType != "researcher" || got.Samples != 1 || got.SuccessRatio != 1 {
+				t.Errorf("%s: unexpected researcher health: %+v", tt.target, got)
+			}
+		}
+	}
+}
+
+func TestHandleRunTimeline(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "timeline.db"))
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-timeline"})
+	s.handleExecute(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+	tests := []struct {
+		runID    string
+		format   string
+		wantCode int
+		wantKey  string
+	}{
+		{runID: "run-timeline", format: "", wantCode: http.StatusOK, wantKey: "spans"},
+		{runID: "run-timeline", format: "chrome", wantCode: http.StatusOK, wantKey: "traceEvents"},
+		{runID: "run-timeline", format: "csv", wantCode: http.StatusBadRequest},
+		{runID: "run-missing", format: "json", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/runs/"+tt.runID+"/timeline?format="+tt.format, nil)
+		req.SetPathValue("id", tt.runID)
+		rec := httptest.NewRecorder()
+		s.handleRunTimeline(rec, req)
+
+		if rec.Code != tt.wantCode {
+			t.Errorf("%s/%s: expected %d, got %d: %s", tt.runID, tt.format, tt.wantCode, rec.Code, rec.Body.String())
+			continue
+		}
+		if tt.wantKey == "" {
+			continue
+		}
+		var resp map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", tt.format, err)
+		}
+		if _, ok := resp[tt.wantKey]; !ok {
+			t.Errorf("%s: expected %q in response, got %s", tt.format, tt.wantKey, rec.Body.String())
+		}
+	}
+}
+
+// TestHandleRunFailures verifies a node that runs out of retries is listed
+// with its config and last error.
+func TestHandleRunFailures(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "failures.db"))
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.clients.Researcher = &flakyResearcher{}
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-failures"})
+	s.handleExecute(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+	req := httptest.NewRequest(http.MethodGet, "/runs/run-failures/failures", nil)
+	req.SetPathValue("id", "run-failures")
+	rec := httptest.NewRecorder()
+	s.handleRunFailures(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp RunFailuresResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.GraphID != "graph-run-failures" || len(resp.Failures) != 1 {
+		t.Fatalf("Expected one failure in graph-run-failures, got %+v", resp)
+	}
+	failure := resp.Failures[0]
+	if failure.NodeID != "researcher" || failure.NodeType != "researcher" || failure.Config["query"] != "q" {
+		t.Errorf("Unexpected failed node: %+v", failure)
+	}
+	if !strings.Contains(failure.LastError, "researcher down") {
+		t.Errorf("LastError = %q, want the researcher's error", failure.LastError)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/runs/run-missing/failures", nil)
+	req.SetPathValue("id", "run-missing")
+	rec = httptest.NewRecorder()
+	s.handleRunFailures(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown run, got %d", rec.Code)
+	}
+}
+
+func TestHandleStats(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "stats.db"))
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-stats"})
+	s.handleExecute(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/execute", bytes.NewReader(body)))
+
+	rec := httptest.NewRecorder()
+	s.handleStats(rec, httptest.NewRequest(http.MethodGet, "/stats?window=1h", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp StatsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.TotalRuns != 1 || resp.RunsByStatus["SUCCEEDED"] != 1 || resp.AvgNodes != 1 || resp.Window != "1h0m0s" {
+		t.Errorf("Unexpected stats: %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleStats(rec, httptest.NewRequest(http.MethodGet, "/stats?window=soon", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid window, got %d", rec.Code)
+	}
+}
+
+func TestHandlePlan(t *testing.T) {
+	s := newTestServer(t)
+	s.decomposer = singleResearcherDecomposer{}
+	s.executor.SetEstimateDefaults(executor.EstimateDefaults{
+		Latencies: map[string]time.Duration{"researcher": 2 * time.Second},
+		Costs:     map[string]float64{"researcher": 1.5},
+	})
+
+	body, _ := json.Marshal(ExecuteRequest{Query: "q", RunID: "run-plan"})
+	rec := httptest.NewRecorder()
+	s.handlePlan(rec, httptest.NewRequest(http.MethodPost, "/plan", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp PlanResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.RunID != "run-plan" || resp.Graph == nil || len(resp.Graph.Nodes) != 1 {
+		t.Fatalf("Unexpected plan: %+v", resp)
+	}
+	if resp.Estimate.TotalCost != 1.5 || resp.Estimate.ProjectedDurationMs != 2000 {
+		t.Errorf("Unexpected estimate: %+v", resp.Estimate)
+	}
+}
+
+func TestNewServer_LazyConnectStartsWithBackendsDown(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "lazy.db"))
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	down := lis.Addr().String()
+	lis.Close()
+
+	cfg := &config.Config{}
+	cfg.Services.Principal.Address = down
+	cfg.Services.Researcher.Address = down
+	cfg.Services.Critic.Address = down
+	cfg.Services.Synthesizer.Address = down
+	cfg.Services.Connection = config.Connection{WaitForReady: true, WarmUpSeconds: 1}
+
+	start := time.Now()
+	s, err := NewServer(cfg, 0)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	t.Cleanup(func() {
+		s.clients.Close()
+		s.executor.Close()
+	})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("NewServer took %v with backends down", elapsed)
+	}
+
+	rec := httptest.NewRecorder()
+	s.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected /health to be ready, got %d", rec.Code)
+	}
+}
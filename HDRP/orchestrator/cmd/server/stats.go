@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"hdrp/internal/executor"
+)
+
+// StatsResponse aggregates finished runs for a status dashboard
+type StatsResponse struct {
+	Window             string         `json:"window,omitempty"` // Empty when covering every run
+	Since              *time.Time     `json:"since,omitempty"`
+	TotalRuns          int            `json:"total_runs"`
+	RunsByStatus       map[string]int `json:"runs_by_status"`
+	FailedRuns         int            `json:"failed_runs"`
+	FailureRate        float64        `json:"failure_rate"`
+	AvgNodes           float64        `json:"avg_nodes"`
+	AvgDurationSeconds float64        `json:"avg_duration_seconds"`
+}
+
+// handleStats reports run counts by status, failure rate and average size
+// and duration of the runs that finished within ?window= (a Go duration
+// such as 24h), or of every stored run without one.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	var window time.Duration
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, fmt.Sprintf("invalid window %q (want a positive duration such as 24h)", raw), http.StatusBadRequest)
+			return
+		}
+		window = parsed
+	}
+
+	stats, err := s.executor.RunStats(window)
+	switch {
+	case errors.Is(err, executor.ErrRunStatsUnsupported):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to aggregate run stats: %v", err)
+		http.Error(w, fmt.Sprintf("failed to aggregate run stats: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	resp := StatsResponse{
+		TotalRuns:          stats.Total,
+		RunsByStatus:       stats.ByStatus,
+		FailedRuns:         stats.Failed,
+		FailureRate:        stats.FailureRate,
+		AvgNodes:           stats.AvgNodes,
+		AvgDurationSeconds: stats.AvgDuration.Seconds(),
+	}
+	if window > 0 {
+		resp.Window = window.String()
+		resp.Since = &stats.Since
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		serverLog.Errorf("Failed to encode run stats: %v", err)
+	}
+}
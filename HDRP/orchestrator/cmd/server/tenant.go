@@ -0,0 +1,25 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// apiKeyHeader carries the API key a request authenticates its tenant with.
+const apiKeyHeader = "X-API-Key"
+
+// requestTenant returns the tenant whose API key (see
+// config.HTTPConfig.TenantKeys) a request carries, or "" for requests
+// without a key. ok is false if the key belongs to no tenant.
+func (s *Server) requestTenant(r *http.Request) (tenant string, ok bool) {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" {
+		return "", true
+	}
+	for name, tenantKey := range s.httpConfig.TenantKeys {
+		if tenantKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(tenantKey)) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"hdrp/internal/executor"
+)
+
+// handleRunTimeline exports a run's node timeline as JSON (default) or in the
+// Chrome trace event format with ?format=chrome.
+func (s *Server) handleRunTimeline(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("id")
+	if runID == "" {
+		http.Error(w, "run id is required", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "chrome" {
+		http.Error(w, fmt.Sprintf("unsupported format %q (want json or chrome)", format), http.StatusBadRequest)
+		return
+	}
+
+	timeline, err := s.executor.Timeline(runID)
+	switch {
+	case errors.Is(err, executor.ErrRunNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, executor.ErrTimelineUnsupported):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		serverLog.Errorf("Failed to build timeline for run %s: %v", runID, err)
+		http.Error(w, fmt.Sprintf("failed to build timeline: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if format == "chrome" {
+		err = timeline.WriteChromeTrace(w)
+	} else {
+		err = json.NewEncoder(w).Encode(timeline)
+	}
+	if err != nil {
+		serverLog.Errorf("Failed to encode timeline: %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package apierrors maps errors from backend services and the executor to
+// HTTP statuses, so every API handler reports failures the same way.
+package apierrors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// StatusClientClosedRequest is reported when the client went away before
+// the request finished. It is not a standard status; nginx uses the same code.
+const StatusClientClosedRequest = 499
+
+// HTTPStatusFromCode returns the HTTP status conventionally used for a gRPC code.
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return StatusClientClosedRequest
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		// Unknown, Internal, DataLoss
+		return http.StatusInternalServerError
+	}
+}
+
+// Describe returns the HTTP status and a client-facing message for a failed
+// backend call. Errors without a gRPC status map to 500 with their text.
+func Describe(err error) (int, string) {
+	if st, ok := status.FromError(err); ok && err != nil {
+		code := HTTPStatusFromCode(st.Code())
+		switch st.Code() {
+		case codes.InvalidArgument:
+			return code, "Invalid query: " + st.Message()
+		case codes.DeadlineExceeded:
+			return code, "Request timed out: " + st.Message()
+		default:
+			return code, "Service error: " + st.Message()
+		}
+	}
+	if err == nil {
+		return http.StatusOK, ""
+	}
+	return http.StatusInternalServerError, err.Error()
+}
+
+// StatusFromError picks an HTTP status for an error that may wrap a gRPC
+// status or a context error. Sentinels from other packages are resolved by
+// the caller before falling back to this.
+func StatusFromError(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusClientClosedRequest
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		return HTTPStatusFromCode(grpcErr.GRPCStatus().Code())
+	}
+	return http.StatusInternalServerError
+}
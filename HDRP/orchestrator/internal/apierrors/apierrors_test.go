@@ -0,0 +1,87 @@
+package apierrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHTTPStatusFromCode(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.OK, http.StatusOK},
+		{codes.Canceled, StatusClientClosedRequest},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.FailedPrecondition, http.StatusPreconditionFailed},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.Internal, http.StatusInternalServerError},
+		{codes.Unknown, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			if got := HTTPStatusFromCode(tt.code); got != tt.want {
+				t.Errorf("HTTPStatusFromCode(%s) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantMsg  string
+	}{
+		{"Invalid argument", status.Error(codes.InvalidArgument, "empty query"), http.StatusBadRequest, "Invalid query: empty query"},
+		{"Deadline", status.Error(codes.DeadlineExceeded, "too slow"), http.StatusGatewayTimeout, "Request timed out: too slow"},
+		{"Unavailable", status.Error(codes.Unavailable, "down"), http.StatusServiceUnavailable, "Service error: down"},
+		{"Plain error", errors.New("boom"), http.StatusInternalServerError, "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, msg := Describe(tt.err)
+			if code != tt.wantCode || msg != tt.wantMsg {
+				t.Errorf("Describe() = (%d, %q), want (%d, %q)", code, msg, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestStatusFromError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"Nil", nil, http.StatusOK},
+		{"Wrapped gRPC status", fmt.Errorf("researcher RPC failed: %w", status.Error(codes.NotFound, "gone")), http.StatusNotFound},
+		{"Context deadline", fmt.Errorf("execution cancelled: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"Context cancelled", fmt.Errorf("execution cancelled: %w", context.Canceled), StatusClientClosedRequest},
+		{"Plain error", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatusFromError(tt.err); got != tt.want {
+				t.Errorf("StatusFromError() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
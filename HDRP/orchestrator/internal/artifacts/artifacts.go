@@ -0,0 +1,73 @@
+package artifacts
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Store holds run outputs too large to keep in memory, such as oversized
+// reports.
+type Store interface {
+	// Create opens a new artifact for writing. The returned URI identifies
+	// the artifact once the writer has been closed successfully.
+	Create(name string) (w io.WriteCloser, uri string, err error)
+
+	// Delete removes the artifact at uri, such as one left incomplete by a
+	// failed run.
+	Delete(uri string) error
+}
+
+// FileStore keeps artifacts as files under a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve artifact directory: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return &FileStore{dir: abs}, nil
+}
+
+// Create opens dir/name for writing and returns its file:// URI. Path
+// separators in name are replaced so artifacts stay inside the store.
+func (s *FileStore) Create(name string) (io.WriteCloser, string, error) {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return nil, "", fmt.Errorf("invalid artifact name %q", name)
+	}
+
+	path := filepath.Join(s.dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create artifact: %w", err)
+	}
+	uri := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	return f, uri, nil
+}
+
+// Delete removes the file behind a URI returned by Create. URIs outside the
+// store are rejected.
+func (s *FileStore) Delete(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return fmt.Errorf("invalid artifact URI %q", uri)
+	}
+	path := filepath.FromSlash(u.Path)
+	if filepath.Dir(path) != s.dir {
+		return fmt.Errorf("artifact %q is not in this store", uri)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete artifact: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore_Create(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	w, uri, err := store.Create("run-1/report.md")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := w.Write([]byte("full report")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	path := filepath.Join(dir, "run-1_report.md")
+	if want := "file://" + filepath.ToSlash(path); uri != want {
+		t.Errorf("URI = %s, want %s", uri, want)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "full report" {
+		t.Errorf("Artifact content = %q, %v", data, err)
+	}
+
+	if _, _, err := store.Create(".."); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("Expected invalid name error, got %v", err)
+	}
+}
+
+func TestFileStore_Delete(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	w, uri, err := store.Create("partial.md")
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	w.Close()
+	if err := store.Delete(uri); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "partial.md")); !os.IsNotExist(err) {
+		t.Errorf("Artifact still exists after Delete: %v", err)
+	}
+
+	outside := "file://" + filepath.ToSlash(filepath.Join(t.TempDir(), "other.md"))
+	if err := store.Delete(outside); err == nil || !strings.Contains(err.Error(), "not in this store") {
+		t.Errorf("Expected error deleting outside the store, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package bootstrap builds the orchestrator's service clients and executor
+// from configuration, so the server and the command line runner are wired
+// the same way.
+package bootstrap
+
+import (
+	"fmt"
+	"time"
+
+	"hdrp/internal/artifacts"
+	"hdrp/internal/clients"
+	"hdrp/internal/concurrency"
+	"hdrp/internal/config"
+	"hdrp/internal/dag"
+	"hdrp/internal/executor"
+	"hdrp/internal/publish"
+	"hdrp/internal/retry"
+	"hdrp/internal/storage"
+)
+
+// ServiceConfig returns the addresses of the backend services and their
+// fallback providers.
+func ServiceConfig(cfg *config.Config) *clients.ServiceConfig {
+	svcConfig := clients.DefaultServiceConfig()
+	svcConfig.PrincipalAddr = cfg.Services.Principal.Address
+	svcConfig.ResearcherAddr = cfg.Services.Researcher.Address
+	svcConfig.CriticAddr = cfg.Services.Critic.Address
+	svcConfig.SynthesizerAddr = cfg.Services.Synthesizer.Address
+	svcConfig.Fallbacks = make(map[string][]clients.ProviderAddr)
+	for service, providers := range cfg.Services.Fallbacks {
+		for _, p := range providers {
+			svcConfig.Fallbacks[service] = append(svcConfig.Fallbacks[service], clients.ProviderAddr{Name: p.Name, Addr: p.Address})
+		}
+	}
+	return svcConfig
+}
+
+// NewExecutor creates an executor calling svcClients, configured from cfg.
+// On error nothing it created is left running; svcClients stay open.
+func NewExecutor(cfg *config.Config, svcClients *clients.ServiceClients) (*executor.DAGExecutor, error) {
+	exec := executor.NewDAGExecutor(svcClients, cfg.Concurrency.MaxWorkers)
+	if err := configureExecutor(exec, cfg); err != nil {
+		exec.Close()
+		return nil, err
+	}
+	return exec, nil
+}
+
+// configureExecutor applies every executor setting in cfg.
+func configureExecutor(exec *executor.DAGExecutor, cfg *config.Config) error {
+	if cfg.Concurrency.Timeouts.HeartbeatSeconds > 0 {
+		exec.SetHeartbeatInterval(time.Duration(cfg.Concurrency.Timeouts.HeartbeatSeconds) * time.Second)
+	}
+	exec.SetGlobalWorkerLimit(cfg.Concurrency.GlobalWorkers, cfg.Concurrency.PriorityAging)
+	exec.SetRateLimits(concurrency.NewConfig(cfg))
+
+	// Retries and circuit breakers
+	exec.SetRetryUpstream(cfg.Retry.Upstream)
+	exec.SetMaxConcurrentRetries(cfg.Retry.MaxConcurrent)
+	exec.SetMaxCircuitBreakers(cfg.Retry.MaxBreakers)
+	exec.SetBreakerSaveInterval(time.Duration(cfg.Retry.BreakerSaveSeconds) * time.Second)
+	exec.RegisterBreakerMetrics()
+	for nodeType, svc := range cfg.Concurrency.Retries {
+		policy := retry.DefaultPolicy()
+		policy.MaxAttempts = svc.MaxAttempts
+		exec.SetNodeRetryPolicy(nodeType, policy)
+	}
+	requeueCodes, err := executor.ParseRequeueCodes(cfg.Retry.RequeueCodes)
+	if err != nil {
+		return fmt.Errorf("invalid retry config: %w", err)
+	}
+	exec.SetRequeuePolicy(executor.RequeuePolicy{
+		Codes:    requeueCodes,
+		Cooldown: time.Duration(cfg.Retry.RequeueCooldownMs) * time.Millisecond,
+	})
+	maxRunAttempts := cfg.Retry.MaxOverrideAttempts
+	if maxRunAttempts <= 0 {
+		maxRunAttempts = executor.DefaultMaxRunAttempts
+	}
+	exec.SetRunOverrideLimits(maxRunAttempts, cfg.Retry.AllowBreakerBypass)
+
+	// Execution policies
+	unknownPolicy, err := executor.ParseUnknownTypePolicy(cfg.Execution.UnknownNodeTypes)
+	if err != nil {
+		return fmt.Errorf("invalid execution config: %w", err)
+	}
+	exec.SetUnknownTypePolicy(unknownPolicy)
+	emptyPolicy, err := executor.ParseEmptyResultPolicy(cfg.Execution.EmptyResults)
+	if err != nil {
+		return fmt.Errorf("invalid execution config: %w", err)
+	}
+	exec.SetEmptyResultPolicy(emptyPolicy)
+	failedParentPolicy, err := dag.ParseFailedParentPolicy(cfg.Execution.FailedParents)
+	if err != nil {
+		return fmt.Errorf("invalid execution config: %w", err)
+	}
+	exec.SetFailedParentPolicy(failedParentPolicy)
+	exec.SetDeterministicMode(cfg.Execution.Deterministic)
+	exec.SetDefaultMaxDepth(cfg.Execution.MaxDepth)
+	if cfg.Execution.ResultCacheSize > 0 {
+		exec.SetResultCache(executor.NewLRUResultCache(cfg.Execution.ResultCacheSize))
+	}
+	exec.SetCriticBatching(executor.CriticBatching{
+		BatchSize:    cfg.Execution.Critic.BatchSize,
+		Concurrency:  cfg.Execution.Critic.BatchConcurrency,
+		AllowPartial: cfg.Execution.Critic.AllowPartial,
+	})
+	exec.SetExecutionBudget(executor.ExecutionBudget{
+		MaxDuration: time.Duration(cfg.Execution.Budget.MaxSeconds) * time.Second,
+		MaxAttempts: cfg.Execution.Budget.MaxAttempts,
+		MaxCost:     cfg.Execution.Budget.MaxCost,
+	})
+	estimateLatencies := make(map[string]time.Duration, len(cfg.Execution.Estimate.LatencyMs))
+	for nodeType, ms := range cfg.Execution.Estimate.LatencyMs {
+		estimateLatencies[nodeType] = time.Duration(ms) * time.Millisecond
+	}
+	exec.SetEstimateDefaults(executor.EstimateDefaults{
+		Latencies: estimateLatencies,
+		Costs:     cfg.Execution.Estimate.NodeCosts,
+	})
+	exec.SetDepthBoost(dag.DepthBoost{
+		PerLevel: cfg.Execution.DepthBoostPerLevel,
+		Max:      cfg.Execution.DepthBoostMax,
+	})
+	exec.SetNodeTypeAllowlist(&dag.NodeTypeAllowlist{
+		Global:  cfg.Execution.AllowedNodeTypes,
+		Tenants: cfg.Execution.TenantNodeTypes,
+	})
+
+	// Storage
+	exec.SetAsyncPersistence(cfg.Storage.AsyncQueueSize)
+	exec.SetSnapshotInterval(time.Duration(cfg.Storage.SnapshotIntervalSeconds) * time.Second)
+	exec.SetGraphRetention(time.Duration(cfg.Storage.RetentionHours)*time.Hour,
+		time.Duration(cfg.Storage.RetentionCleanupMinutes)*time.Minute)
+	exec.SetStorageOpTimeout(time.Duration(cfg.Storage.OpTimeoutSeconds) * time.Second)
+	if err := exec.SetWALSequenceMode(cfg.Storage.WALSequences); err != nil {
+		return fmt.Errorf("invalid storage config: %w", err)
+	}
+	if cfg.Storage.Artifacts.MaxReportBytes > 0 && cfg.Storage.Artifacts.Directory != "" {
+		store, err := artifacts.NewFileStore(cfg.Storage.Artifacts.Directory)
+		if err != nil {
+			return fmt.Errorf("failed to initialize artifact store: %w", err)
+		}
+		exec.SetReportLimit(cfg.Storage.Artifacts.MaxReportBytes, store)
+	}
+	if cfg.Storage.Snapshots.Directory != "" {
+		snapshots, err := storage.NewFileSnapshotStore(cfg.Storage.Snapshots.Directory)
+		if err != nil {
+			return fmt.Errorf("failed to initialize snapshot store: %w", err)
+		}
+		exec.SetSnapshotStore(snapshots, cfg.Storage.Snapshots.MinBytes)
+	}
+
+	publisher, err := publish.New(publish.Config{
+		Backend:    cfg.Events.Publisher,
+		URL:        cfg.Events.URL,
+		Subject:    cfg.Events.Subject,
+		QueueSize:  cfg.Events.QueueSize,
+		Workers:    cfg.Events.Workers,
+		Timeout:    time.Duration(cfg.Events.TimeoutSeconds) * time.Second,
+		MaxRetries: cfg.Events.MaxRetries,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid events config: %w", err)
+	}
+	exec.SetEventPublisher(publisher)
+
+	// Recovery
+	exec.SetQuarantineThreshold(cfg.Recovery.QuarantineAfter)
+	exec.SetRestoreRetryMetrics(cfg.Recovery.RestoreRetryMetrics)
+	exec.SetPersistNodeResults(cfg.Recovery.PersistNodeResults)
+	return nil
+}
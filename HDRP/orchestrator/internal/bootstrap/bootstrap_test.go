@@ -0,0 +1,72 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/config"
+	"hdrp/internal/dag"
+)
+
+func TestNewExecutor_AppliesConfig(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "bootstrap.db"))
+	cfg := &config.Config{}
+	cfg.Execution.AllowedNodeTypes = []string{"critic"}
+
+	exec, err := NewExecutor(cfg, &clients.ServiceClients{})
+	if err != nil {
+		t.Fatalf("NewExecutor() error = %v", err)
+	}
+	defer exec.Close()
+
+	graph := &dag.Graph{
+		ID:     "graph-bootstrap",
+		Status: dag.StatusCreated,
+		Nodes:  []dag.Node{{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated}},
+	}
+	if _, err := exec.Execute(context.Background(), graph, "run-bootstrap"); !errors.Is(err, dag.ErrNodeTypeNotAllowed) {
+		t.Errorf("Execute() error = %v, want ErrNodeTypeNotAllowed from the configured allowlist", err)
+	}
+}
+
+func TestNewExecutor_InvalidConfig(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "bootstrap.db"))
+	tests := []struct {
+		name   string
+		modify func(cfg *config.Config)
+	}{
+		{"Unknown node type policy", func(cfg *config.Config) { cfg.Execution.UnknownNodeTypes = "sometimes" }},
+		{"Failed parent policy", func(cfg *config.Config) { cfg.Execution.FailedParents = "retry" }},
+		{"WAL sequence mode", func(cfg *config.Config) { cfg.Storage.WALSequences = "redis" }},
+		{"Event publisher", func(cfg *config.Config) { cfg.Events.Publisher = "carrier-pigeon" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			tt.modify(cfg)
+			if exec, err := NewExecutor(cfg, &clients.ServiceClients{}); err == nil {
+				exec.Close()
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestServiceConfig(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Services.Researcher.Address = "researcher:50051"
+	cfg.Services.Fallbacks = map[string][]config.FallbackProvider{
+		"researcher": {{Name: "backup", Address: "backup:50051"}},
+	}
+
+	svcConfig := ServiceConfig(cfg)
+	if svcConfig.ResearcherAddr != "researcher:50051" {
+		t.Errorf("ResearcherAddr = %q", svcConfig.ResearcherAddr)
+	}
+	if fallbacks := svcConfig.Fallbacks["researcher"]; len(fallbacks) != 1 || fallbacks[0].Addr != "backup:50051" {
+		t.Errorf("Fallbacks = %+v", svcConfig.Fallbacks)
+	}
+}
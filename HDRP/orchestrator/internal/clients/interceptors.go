@@ -0,0 +1,162 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"hdrp/internal/metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tracePropagator writes the caller's span into outgoing metadata as a W3C
+// traceparent header, so the services can continue the trace.
+var tracePropagator = propagation.TraceContext{}
+
+// unaryInterceptors returns the interceptors every connection to service
+// (researcher, critic, ...) calls through: tracing first, so the metrics
+// interceptor's timing falls inside the RPC's span.
+func unaryInterceptors(service string) []grpc.UnaryClientInterceptor {
+	return []grpc.UnaryClientInterceptor{
+		tracingInterceptor(service),
+		metricsInterceptor(service),
+	}
+}
+
+// metricsInterceptor records the latency of each unary call to service and,
+// for failed calls, its gRPC status code.
+func metricsInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if isHealthCheck(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		name := methodName(method)
+		metrics.RecordRPCLatency(service, name, time.Since(start).Seconds(), err == nil)
+		if err != nil {
+			metrics.RecordRPCError(service, name, status.Code(err).String())
+		}
+		return err
+	}
+}
+
+// tracingInterceptor runs each unary call to service in a client span and
+// injects that span into the request metadata.
+func tracingInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if isHealthCheck(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		ctx, span := startRPCSpan(ctx, service, method)
+		defer span.End()
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		endRPCSpan(ctx, err)
+		return err
+	}
+}
+
+// streamTracingInterceptor runs each stream to service in a client span
+// like tracingInterceptor's, ending it once the stream does. Streams are
+// timed by their callers, so there is no stream metrics interceptor.
+func streamTracingInterceptor(service string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, span := startRPCSpan(ctx, service, method)
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			endRPCSpan(ctx, err)
+			span.End()
+			return nil, err
+		}
+		return &tracedStream{ClientStream: stream, ctx: ctx}, nil
+	}
+}
+
+// tracedStream ends its span when RecvMsg first fails, io.EOF included.
+type tracedStream struct {
+	grpc.ClientStream
+	ctx  context.Context
+	once sync.Once
+}
+
+func (s *tracedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if err == io.EOF {
+				endRPCSpan(s.ctx, nil)
+			} else {
+				endRPCSpan(s.ctx, err)
+			}
+			trace.SpanFromContext(s.ctx).End()
+		})
+	}
+	return err
+}
+
+// startRPCSpan starts a client span for a call to method of service and
+// injects it into the outgoing metadata.
+func startRPCSpan(ctx context.Context, service, method string) (context.Context, trace.Span) {
+	ctx, span := metrics.StartSpan(ctx, strings.TrimPrefix(method, "/"),
+		attribute.String("rpc.system", "grpc"),
+		attribute.String("rpc.service", service),
+		attribute.String("rpc.method", methodName(method)),
+	)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracePropagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// endRPCSpan records the outcome of a call on its span.
+func endRPCSpan(ctx context.Context, err error) {
+	metrics.AddSpanAttributes(ctx, attribute.String("rpc.grpc.status_code", status.Code(err).String()))
+	metrics.RecordSpanError(ctx, err)
+}
+
+// isHealthCheck reports whether method belongs to the standard gRPC health
+// service, whose readiness probes aren't service traffic.
+func isHealthCheck(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// methodName returns the method of a full gRPC method name such as
+// "/hdrp.services.ResearcherService/Research".
+func methodName(fullMethod string) string {
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[i+1:]
+	}
+	return fullMethod
+}
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry propagation API.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"hdrp/internal/metrics"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"github.com/prometheus/client_golang/prometheus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// traceparentResearcher records the traceparent header of each request and
+// fails queries of "fail".
+type traceparentResearcher struct {
+	pb.UnimplementedResearcherServiceServer
+	mu           sync.Mutex
+	traceparents []string
+}
+
+func (r *traceparentResearcher) Research(ctx context.Context, req *pb.ResearchRequest) (*pb.ResearchResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	r.mu.Lock()
+	r.traceparents = append(r.traceparents, strings.Join(md.Get("traceparent"), ","))
+	r.mu.Unlock()
+	if req.Query == "fail" {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	return &pb.ResearchResponse{}, nil
+}
+
+// ResearchStream sends one batch per claim statement in the query, split
+// on spaces.
+func (r *traceparentResearcher) ResearchStream(req *pb.ResearchRequest, stream grpc.ServerStreamingServer[pb.ResearchResponse]) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	r.mu.Lock()
+	r.traceparents = append(r.traceparents, strings.Join(md.Get("traceparent"), ","))
+	r.mu.Unlock()
+	for _, statement := range strings.Fields(req.Query) {
+		if err := stream.Send(&pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: statement}}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rpcErrorCount reads hdrp_rpc_errors_total for the given labels.
+func rpcErrorCount(t *testing.T, service, method, code string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather metrics: %v", err)
+	}
+	want := map[string]string{"service": service, "method": method, "code": code}
+	for _, family := range families {
+		if family.GetName() != "hdrp_rpc_errors_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := 0
+			for _, label := range m.GetLabel() {
+				if want[label.GetName()] == label.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(want) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+// TestUnaryInterceptors verifies calls through NewServiceClients run in a
+// client span whose context reaches the service, and that failures are
+// counted by gRPC code.
+func TestUnaryInterceptors(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	researcher := &traceparentResearcher{}
+	pb.RegisterResearcherServiceServer(server, researcher)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	recorder := tracetest.NewSpanRecorder()
+	metrics.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { metrics.SetTracerProvider(nil) })
+
+	addr := lis.Addr().String()
+	clients, err := NewServiceClients(&ServiceConfig{
+		PrincipalAddr:   addr,
+		ResearcherAddr:  addr,
+		CriticAddr:      addr,
+		SynthesizerAddr: addr,
+	})
+	if err != nil {
+		t.Fatalf("NewServiceClients failed: %v", err)
+	}
+	t.Cleanup(func() { _ = clients.Close() })
+
+	failuresBefore := rpcErrorCount(t, "researcher", "Research", "Unavailable")
+	ctx, parent := metrics.StartSpan(context.Background(), "test.parent")
+	if _, err := clients.Researcher.Research(ctx, &pb.ResearchRequest{Query: "ok"}); err != nil {
+		t.Fatalf("Research failed: %v", err)
+	}
+	if _, err := clients.Researcher.Research(ctx, &pb.ResearchRequest{Query: "fail"}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("Research error = %v, want Unavailable", err)
+	}
+	parent.End()
+
+	var rpcSpans []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "hdrp.services.ResearcherService/Research" {
+			rpcSpans = append(rpcSpans, s)
+		}
+	}
+	if len(rpcSpans) != 2 {
+		t.Fatalf("Got %d RPC spans, want 2", len(rpcSpans))
+	}
+	traceID := parent.SpanContext().TraceID().String()
+	for i, s := range rpcSpans {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("RPC span %d is not a child of the caller's span", i+1)
+		}
+		want := traceID + "-" + s.SpanContext().SpanID().String()
+		if !strings.Contains(researcher.traceparents[i], want) {
+			t.Errorf("Request %d traceparent = %q, want it to carry %s", i+1, researcher.traceparents[i], want)
+		}
+	}
+
+	if got := rpcErrorCount(t, "researcher", "Research", "Unavailable"); got != failuresBefore+1 {
+		t.Errorf("hdrp_rpc_errors_total = %v, want %v", got, failuresBefore+1)
+	}
+}
@@ -2,22 +2,31 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync"
 	"time"
 
 	pb "github.com/deepdag/hdrp/api/gen/services"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 // ServiceClients manages gRPC connections to Python microservices.
 type ServiceClients struct {
 	Principal   pb.PrincipalServiceClient
-	Researcher  pb.ResearcherServiceClient
+	Researcher  ResearcherClient
 	Critic      pb.CriticServiceClient
-	Synthesizer pb.SynthesizerServiceClient
+	Synthesizer SynthesizerClient
+
+	// Secondary providers by service (researcher, critic, synthesizer),
+	// tried in order when the primary is unavailable
+	Fallbacks map[string][]Provider
 
 	principalConn   *grpc.ClientConn
 	researcherConn  *grpc.ClientConn
@@ -25,12 +34,41 @@ type ServiceClients struct {
 	synthesizerConn *grpc.ClientConn
 }
 
+// ResearcherClient calls the researcher service's unary RPC. Clients
+// connected by NewServiceClients also implement StreamingResearcher.
+type ResearcherClient interface {
+	Research(ctx context.Context, in *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error)
+}
+
+// SynthesizerClient calls the synthesizer service's unary RPC. Clients
+// connected by NewServiceClients also implement StreamingSynthesizer.
+type SynthesizerClient interface {
+	Synthesize(ctx context.Context, in *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error)
+}
+
 // ServiceConfig specifies service network addresses.
 type ServiceConfig struct {
 	PrincipalAddr   string
 	ResearcherAddr  string
 	CriticAddr      string
 	SynthesizerAddr string
+
+	// Fallbacks lists secondary providers by service: researcher, critic or
+	// synthesizer.
+	Fallbacks map[string][]ProviderAddr
+}
+
+// ProviderAddr names a secondary provider of a service.
+type ProviderAddr struct {
+	Name string
+	Addr string
+}
+
+// Provider is a secondary provider of a service. Only that service's client
+// is set in Clients.
+type Provider struct {
+	Name    string
+	Clients *ServiceClients
 }
 
 // DefaultServiceConfig returns localhost addresses for all services.
@@ -43,7 +81,11 @@ func DefaultServiceConfig() *ServiceConfig {
 	}
 }
 
-// NewServiceClients establishes gRPC connections to all Python services.
+// NewServiceClients creates gRPC connections to all Python services. It
+// doesn't wait for the services to come up, so they may start in any order:
+// connections are made on first use and re-established by gRPC after
+// failures. Call WaitForReady to check the services ahead of the first
+// request.
 func NewServiceClients(config *ServiceConfig) (*ServiceClients, error) {
 	if config == nil {
 		config = DefaultServiceConfig()
@@ -51,22 +93,22 @@ func NewServiceClients(config *ServiceConfig) (*ServiceClients, error) {
 
 	clients := &ServiceClients{}
 
-	principalConn, err := dialWithRetry(config.PrincipalAddr, "Principal")
+	principalConn, err := dial(config.PrincipalAddr, "Principal", "principal")
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Principal service: %w", err)
 	}
 	clients.principalConn = principalConn
 	clients.Principal = pb.NewPrincipalServiceClient(principalConn)
 
-	researcherConn, err := dialWithRetry(config.ResearcherAddr, "Researcher")
+	researcherConn, err := dial(config.ResearcherAddr, "Researcher", "researcher")
 	if err != nil {
 		clients.Close()
 		return nil, fmt.Errorf("failed to connect to Researcher service: %w", err)
 	}
 	clients.researcherConn = researcherConn
-	clients.Researcher = pb.NewResearcherServiceClient(researcherConn)
+	clients.Researcher = researcherClient{pb.NewResearcherServiceClient(researcherConn)}
 
-	criticConn, err := dialWithRetry(config.CriticAddr, "Critic")
+	criticConn, err := dial(config.CriticAddr, "Critic", "critic")
 	if err != nil {
 		clients.Close()
 		return nil, fmt.Errorf("failed to connect to Critic service: %w", err)
@@ -74,49 +116,134 @@ func NewServiceClients(config *ServiceConfig) (*ServiceClients, error) {
 	clients.criticConn = criticConn
 	clients.Critic = pb.NewCriticServiceClient(criticConn)
 
-	synthesizerConn, err := dialWithRetry(config.SynthesizerAddr, "Synthesizer")
+	synthesizerConn, err := dial(config.SynthesizerAddr, "Synthesizer", "synthesizer")
 	if err != nil {
 		clients.Close()
 		return nil, fmt.Errorf("failed to connect to Synthesizer service: %w", err)
 	}
 	clients.synthesizerConn = synthesizerConn
-	clients.Synthesizer = pb.NewSynthesizerServiceClient(synthesizerConn)
+	clients.Synthesizer = synthesizerClient{pb.NewSynthesizerServiceClient(synthesizerConn)}
 
-	log.Printf("Successfully connected to all services")
+	for service, addrs := range config.Fallbacks {
+		for _, addr := range addrs {
+			provider, err := dialProvider(service, addr)
+			if err != nil {
+				clients.Close()
+				return nil, err
+			}
+			if clients.Fallbacks == nil {
+				clients.Fallbacks = make(map[string][]Provider)
+			}
+			clients.Fallbacks[service] = append(clients.Fallbacks[service], provider)
+		}
+	}
+
+	log.Printf("Created lazy connections to all services")
 	return clients, nil
 }
 
-// dialWithRetry establishes a gRPC connection with exponential backoff.
-func dialWithRetry(addr string, serviceName string) (*grpc.ClientConn, error) {
-	const maxRetries = 3
-	const retryDelay = 2 * time.Second
+// dial creates a gRPC connection without waiting for the service. The
+// connection is established on first use and re-established by gRPC after
+// failures. Calls are traced, and unary calls recorded in metrics, under
+// service.
+func dial(addr, serviceName, service string) (*grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(unaryInterceptors(service)...),
+		grpc.WithChainStreamInterceptor(streamTracingInterceptor(service)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s service address %s: %w", serviceName, addr, err)
+	}
+	return conn, nil
+}
 
-	var conn *grpc.ClientConn
-	var err error
+// dialProvider lazily connects to a secondary provider of service.
+func dialProvider(service string, addr ProviderAddr) (Provider, error) {
+	name := fmt.Sprintf("%s fallback %q", service, addr.Name)
+	conn, err := dial(addr.Addr, name, service)
+	if err != nil {
+		return Provider{}, err
+	}
 
-	for i := 0; i < maxRetries; i++ {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	clients := &ServiceClients{}
+	switch service {
+	case "researcher":
+		clients.researcherConn = conn
+		clients.Researcher = researcherClient{pb.NewResearcherServiceClient(conn)}
+	case "critic":
+		clients.criticConn = conn
+		clients.Critic = pb.NewCriticServiceClient(conn)
+	case "synthesizer":
+		clients.synthesizerConn = conn
+		clients.Synthesizer = synthesizerClient{pb.NewSynthesizerServiceClient(conn)}
+	default:
+		conn.Close()
+		return Provider{}, fmt.Errorf("unsupported fallback service %q", service)
+	}
+	log.Printf("Created lazy connection to %s at %s", name, addr.Addr)
+	return Provider{Name: addr.Name, Clients: clients}, nil
+}
 
-		conn, err = grpc.DialContext(
-			ctx,
-			addr,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithBlock(),
-		)
+// DefaultReadyTimeout bounds WaitForReady when ctx has no deadline.
+const DefaultReadyTimeout = 30 * time.Second
 
-		if err == nil {
-			log.Printf("Connected to %s service at %s", serviceName, addr)
-			return conn, nil
-		}
+// WaitForReady connects to every service and health-checks it, waiting until
+// all of them are serving or ctx is done (DefaultReadyTimeout if ctx has no
+// deadline). Services without the standard gRPC health service count as
+// ready once connected. The error names every service that isn't ready;
+// gRPC keeps retrying those in the background.
+func (c *ServiceClients) WaitForReady(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultReadyTimeout)
+		defer cancel()
+	}
 
-		log.Printf("Failed to connect to %s service (attempt %d/%d): %v", serviceName, i+1, maxRetries, err)
-		if i < maxRetries-1 {
-			time.Sleep(retryDelay)
+	conns := []struct {
+		name string
+		conn *grpc.ClientConn
+	}{
+		{"Principal", c.principalConn},
+		{"Researcher", c.researcherConn},
+		{"Critic", c.criticConn},
+		{"Synthesizer", c.synthesizerConn},
+	}
+	errs := make([]error, len(conns))
+	var wg sync.WaitGroup
+	for i, svc := range conns {
+		if svc.conn == nil {
+			continue
 		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = checkHealth(ctx, svc.conn, svc.name)
+		}()
 	}
+	wg.Wait()
 
-	return nil, fmt.Errorf("failed to connect to %s service at %s after %d attempts: %w", serviceName, addr, maxRetries, err)
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	log.Printf("All services ready")
+	return nil
+}
+
+// checkHealth waits for conn to connect and asks its health service whether
+// the server is serving.
+func checkHealth(ctx context.Context, conn *grpc.ClientConn, serviceName string) error {
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s service not ready (%s): %w", serviceName, conn.GetState(), err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%s service not ready: health status %s", serviceName, resp.Status)
+	}
+	return nil
 }
 
 // Close terminates all gRPC connections.
@@ -147,6 +274,14 @@ func (c *ServiceClients) Close() error {
 		}
 	}
 
+	for _, providers := range c.Fallbacks {
+		for _, provider := range providers {
+			if err := provider.Clients.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close fallback %q: %w", provider.Name, err))
+			}
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("errors closing connections: %v", errs)
 	}
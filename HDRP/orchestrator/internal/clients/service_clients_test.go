@@ -1,10 +1,15 @@
 package clients
 
 import (
+	"context"
 	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func startTestServer(t *testing.T) (string, func()) {
@@ -24,15 +29,50 @@ func startTestServer(t *testing.T) (string, func()) {
 	return lis.Addr().String(), stop
 }
 
-func TestDialWithRetrySuccess(t *testing.T) {
-	addr, stop := startTestServer(t)
+func TestWaitForReadyHealthCheck(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	// A service without the health service counts as ready once connected
+	plainAddr, stop := startTestServer(t)
 	t.Cleanup(stop)
 
-	conn, err := dialWithRetry(addr, "Test")
+	addr := lis.Addr().String()
+	clients, err := NewServiceClients(&ServiceConfig{
+		PrincipalAddr:   plainAddr,
+		ResearcherAddr:  addr,
+		CriticAddr:      plainAddr,
+		SynthesizerAddr: plainAddr,
+	})
 	if err != nil {
-		t.Fatalf("dialWithRetry failed: %v", err)
+		t.Fatalf("NewServiceClients failed: %v", err)
+	}
+	t.Cleanup(func() { _ = clients.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = clients.WaitForReady(ctx)
+	if err == nil || !strings.Contains(err.Error(), "Researcher") {
+		t.Fatalf("Expected the not-serving Researcher to be reported, got %v", err)
+	}
+	if strings.Contains(err.Error(), "Critic") {
+		t.Errorf("Critic without a health service reported as not ready: %v", err)
+	}
+
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	if err := clients.WaitForReady(ctx); err != nil {
+		t.Fatalf("WaitForReady failed once every service was serving: %v", err)
 	}
-	_ = conn.Close()
 }
 
 func TestNewServiceClientsSuccess(t *testing.T) {
@@ -60,3 +100,58 @@ func TestNewServiceClientsSuccess(t *testing.T) {
 		t.Fatalf("Close failed: %v", err)
 	}
 }
+
+// reserveAddr returns a local address with nothing listening on it yet.
+func reserveAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	_ = lis.Close()
+	return addr
+}
+
+func TestNewServiceClientsLazyWaitForReady(t *testing.T) {
+	addr := reserveAddr(t)
+	cfg := &ServiceConfig{
+		PrincipalAddr:   addr,
+		ResearcherAddr:  addr,
+		CriticAddr:      addr,
+		SynthesizerAddr: addr,
+	}
+
+	start := time.Now()
+	clients, err := NewServiceClients(cfg)
+	if err != nil {
+		t.Fatalf("NewServiceClients failed: %v", err)
+	}
+	t.Cleanup(func() { _ = clients.Close() })
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("NewServiceClients blocked for %v with the backend down", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := clients.WaitForReady(ctx); err == nil {
+		t.Fatal("Expected WaitForReady to time out while the backend is down")
+	}
+
+	// The backend comes up late; gRPC reconnects in the background
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := clients.WaitForReady(ctx); err != nil {
+		t.Fatalf("WaitForReady failed after the backend came up: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"context"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+
+	"google.golang.org/grpc"
+)
+
+// SynthesizeStream yields a report in pieces. Each response carries the next
+// chunk of the report in Report; ArtifactURI may be set on any of them. Recv
+// returns io.EOF once the report is complete.
+type SynthesizeStream interface {
+	Recv() (*pb.SynthesizeResponse, error)
+}
+
+// StreamingSynthesizer is implemented by synthesizer clients whose service
+// can stream the report as it is generated. Clients without it are called
+// through the unary Synthesize RPC.
+type StreamingSynthesizer interface {
+	SynthesizeStream(ctx context.Context, in *pb.SynthesizeRequest, opts ...grpc.CallOption) (SynthesizeStream, error)
+}
+
+// ResearchStream yields a researcher's claims in batches as they are found.
+// Each response carries the next batch in Claims. Recv returns io.EOF once
+// research is complete.
+type ResearchStream interface {
+	Recv() (*pb.ResearchResponse, error)
+}
+
+// StreamingResearcher is implemented by researcher clients whose service
+// can stream claims as they are extracted. Clients without it are called
+// through the unary Research RPC.
+type StreamingResearcher interface {
+	ResearchStream(ctx context.Context, in *pb.ResearchRequest, opts ...grpc.CallOption) (ResearchStream, error)
+}
+
+// researcherClient exposes the generated client's ResearchStream RPC as a
+// StreamingResearcher.
+type researcherClient struct {
+	pb.ResearcherServiceClient
+}
+
+func (c researcherClient) ResearchStream(ctx context.Context, in *pb.ResearchRequest, opts ...grpc.CallOption) (ResearchStream, error) {
+	stream, err := c.ResearcherServiceClient.ResearchStream(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// synthesizerClient exposes the generated client's SynthesizeStream RPC as
+// a StreamingSynthesizer.
+type synthesizerClient struct {
+	pb.SynthesizerServiceClient
+}
+
+func (c synthesizerClient) SynthesizeStream(ctx context.Context, in *pb.SynthesizeRequest, opts ...grpc.CallOption) (SynthesizeStream, error) {
+	stream, err := c.SynthesizerServiceClient.SynthesizeStream(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
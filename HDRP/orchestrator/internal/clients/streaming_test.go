@@ -0,0 +1,159 @@
+package clients
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"hdrp/internal/metrics"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+)
+
+// chunkSynthesizer streams each verification result's reasoning as one
+// chunk of the report.
+type chunkSynthesizer struct {
+	pb.UnimplementedSynthesizerServiceServer
+}
+
+func (chunkSynthesizer) SynthesizeStream(req *pb.SynthesizeRequest, stream grpc.ServerStreamingServer[pb.SynthesizeResponse]) error {
+	for _, result := range req.VerificationResults {
+		if err := stream.Send(&pb.SynthesizeResponse{Report: result.Reasoning}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestResearchStream verifies the researcher client from NewServiceClients
+// streams claims in order, in a client span whose context reaches the
+// service and which ends with the stream.
+func TestResearchStream(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	researcher := &traceparentResearcher{}
+	pb.RegisterResearcherServiceServer(server, researcher)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	recorder := tracetest.NewSpanRecorder()
+	metrics.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { metrics.SetTracerProvider(nil) })
+
+	addr := lis.Addr().String()
+	clients, err := NewServiceClients(&ServiceConfig{
+		PrincipalAddr:   addr,
+		ResearcherAddr:  addr,
+		CriticAddr:      addr,
+		SynthesizerAddr: addr,
+	})
+	if err != nil {
+		t.Fatalf("NewServiceClients failed: %v", err)
+	}
+	t.Cleanup(func() { _ = clients.Close() })
+
+	streamer, ok := clients.Researcher.(StreamingResearcher)
+	if !ok {
+		t.Fatal("Researcher client does not implement StreamingResearcher")
+	}
+	ctx, parent := metrics.StartSpan(context.Background(), "test.parent")
+	stream, err := streamer.ResearchStream(ctx, &pb.ResearchRequest{Query: "first second third"})
+	if err != nil {
+		t.Fatalf("ResearchStream failed: %v", err)
+	}
+	var statements []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		for _, claim := range resp.Claims {
+			statements = append(statements, claim.Statement)
+		}
+	}
+	parent.End()
+
+	if got := strings.Join(statements, " "); got != "first second third" {
+		t.Errorf("Streamed claims = %q, want %q", got, "first second third")
+	}
+
+	var rpcSpan sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() == "hdrp.services.ResearcherService/ResearchStream" {
+			rpcSpan = s
+		}
+	}
+	if rpcSpan == nil {
+		t.Fatal("No ended span for the stream")
+	}
+	if rpcSpan.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("Stream span is not a child of the caller's span")
+	}
+	want := parent.SpanContext().TraceID().String() + "-" + rpcSpan.SpanContext().SpanID().String()
+	if len(researcher.traceparents) != 1 || !strings.Contains(researcher.traceparents[0], want) {
+		t.Errorf("Traceparents = %q, want one carrying %s", researcher.traceparents, want)
+	}
+}
+
+func TestSynthesizeStream(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterSynthesizerServiceServer(server, chunkSynthesizer{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	addr := lis.Addr().String()
+	clients, err := NewServiceClients(&ServiceConfig{
+		PrincipalAddr:   addr,
+		ResearcherAddr:  addr,
+		CriticAddr:      addr,
+		SynthesizerAddr: addr,
+	})
+	if err != nil {
+		t.Fatalf("NewServiceClients failed: %v", err)
+	}
+	t.Cleanup(func() { _ = clients.Close() })
+
+	streamer, ok := clients.Synthesizer.(StreamingSynthesizer)
+	if !ok {
+		t.Fatal("Synthesizer client does not implement StreamingSynthesizer")
+	}
+	stream, err := streamer.SynthesizeStream(context.Background(), &pb.SynthesizeRequest{
+		VerificationResults: []*pb.CritiqueResult{{Reasoning: "# Report\n"}, {Reasoning: "Body"}},
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeStream failed: %v", err)
+	}
+	var report strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+		report.WriteString(chunk.Report)
+	}
+	if report.String() != "# Report\nBody" {
+		t.Errorf("Streamed report = %q, want %q", report.String(), "# Report\nBody")
+	}
+}
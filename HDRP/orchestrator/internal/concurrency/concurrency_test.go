@@ -59,6 +59,12 @@ func TestWorkerPool(t *testing.T) {
 		taskCount := 20
 		done := make(chan bool, taskCount)
 
+		// Consume results so workers never block on a full result queue
+		go func() {
+			for range wp.Results() {
+			}
+		}()
+
 		for i := 0; i < taskCount; i++ {
 			task := Task{
 				ID: string(rune('A' + i)),
@@ -103,6 +109,135 @@ func TestWorkerPool(t *testing.T) {
 			t.Errorf("Not enough concurrency: %d", maxConcurrent)
 		}
 	})
+
+	t.Run("Resize", func(t *testing.T) {
+		wp := NewWorkerPool(2)
+		if err := wp.Start(); err != nil {
+			t.Fatalf("Failed to start worker pool: %v", err)
+		}
+		defer wp.Shutdown()
+
+		go func() {
+			for range wp.Results() {
+			}
+		}()
+
+		var mu sync.Mutex
+		concurrent := 0
+		maxConcurrent := 0
+		started := make(chan bool, 10)
+		finished := make(chan bool, 10)
+		release := make(chan struct{})
+		blocking := func(ctx context.Context) error {
+			mu.Lock()
+			concurrent++
+			if concurrent > maxConcurrent {
+				maxConcurrent = concurrent
+			}
+			mu.Unlock()
+			started <- true
+			<-release
+			mu.Lock()
+			concurrent--
+			mu.Unlock()
+			finished <- true
+			return nil
+		}
+
+		// Resize repeatedly while tasks are in flight
+		taskCount := 40
+		done := make(chan bool, taskCount)
+		resized := make(chan bool)
+		go func() {
+			for _, n := range []int{5, 1, 3, 8, 0, 2} {
+				wp.Resize(n)
+				time.Sleep(2 * time.Millisecond)
+			}
+			resized <- true
+		}()
+		for i := 0; i < taskCount; i++ {
+			task := Task{ID: string(rune('A' + i)), Execute: func(ctx context.Context) error {
+				time.Sleep(time.Millisecond)
+				done <- true
+				return nil
+			}}
+			if err := wp.Submit(task); err != nil {
+				t.Fatalf("Failed to submit task: %v", err)
+			}
+		}
+		timeout := time.After(5 * time.Second)
+		for i := 0; i < taskCount; i++ {
+			select {
+			case <-done:
+			case <-timeout:
+				t.Fatal("Timeout waiting for tasks during resizing")
+			}
+		}
+		<-resized
+		if wp.Size() != 2 {
+			t.Errorf("Size() = %d, want 2", wp.Size())
+		}
+
+		// Growing lets more tasks run at once
+		wp.Resize(3)
+		for i := 0; i < 3; i++ {
+			if err := wp.Submit(Task{ID: "grow", Execute: blocking}); err != nil {
+				t.Fatalf("Failed to submit task: %v", err)
+			}
+		}
+		for i := 0; i < 3; i++ {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Only %d of 3 tasks started after growing the pool", i)
+			}
+		}
+
+		// Shrinking lets in-flight tasks finish, then runs one at a time
+		wp.Resize(1)
+		close(release)
+		for i := 0; i < 3; i++ {
+			<-finished
+		}
+		mu.Lock()
+		maxConcurrent = 0
+		mu.Unlock()
+		for i := 0; i < 4; i++ {
+			if err := wp.Submit(Task{ID: "shrink", Execute: blocking}); err != nil {
+				t.Fatalf("Failed to submit task: %v", err)
+			}
+		}
+		for i := 0; i < 4; i++ {
+			select {
+			case <-finished:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Timeout waiting for tasks after shrinking the pool")
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if maxConcurrent > 1 {
+			t.Errorf("Max concurrent workers after shrinking = %d, want 1", maxConcurrent)
+		}
+	})
+
+	t.Run("WorkerIDsNotReused", func(t *testing.T) {
+		wp := NewWorkerPool(2)
+		if err := wp.Start(); err != nil {
+			t.Fatalf("Failed to start worker pool: %v", err)
+		}
+		defer wp.Shutdown()
+
+		// Shrinking retires worker 1; growing again must not reuse its ID
+		wp.Resize(1)
+		wp.Resize(2)
+		wp.mu.Lock()
+		defer wp.mu.Unlock()
+		if wp.nextID != 3 {
+			t.Errorf("Spawned workers up to ID %d, want 3 distinct IDs", wp.nextID)
+		}
+	})
 }
 
 func TestRateLimiter(t *testing.T) {
@@ -174,6 +309,26 @@ func TestRateLimiter(t *testing.T) {
 			t.Errorf("Expected 20 acquisitions, got %d", count)
 		}
 	})
+
+	t.Run("Throttle Cooldown", func(t *testing.T) {
+		rl := NewRateLimiter(2)
+		rl.Throttle(50 * time.Millisecond)
+
+		if rl.TryAcquire() {
+			t.Error("Should not acquire during cooldown")
+		}
+
+		start := time.Now()
+		if err := rl.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+		if waited := time.Since(start); waited < 40*time.Millisecond {
+			t.Errorf("Acquire returned after %v, expected to wait out the cooldown", waited)
+		}
+		if rl.CooldownRemaining() != 0 {
+			t.Error("Cooldown should be over")
+		}
+	})
 }
 
 func TestTopologicalSorter(t *testing.T) {
@@ -323,3 +478,89 @@ func TestInMemoryLock(t *testing.T) {
 		}
 	})
 }
+
+func TestPrioritySemaphore(t *testing.T) {
+	// enqueue starts an Acquire and waits until it is queued
+	enqueue := func(t *testing.T, s *PrioritySemaphore, priority int, name string, granted chan<- string) {
+		t.Helper()
+		waiting := s.Waiting()
+		go func() {
+			if err := s.Acquire(context.Background(), priority); err == nil {
+				granted <- name
+			}
+		}()
+		deadline := time.Now().Add(time.Second)
+		for s.Waiting() == waiting {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s never queued", name)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("Higher Priority First", func(t *testing.T) {
+		s := NewPrioritySemaphore(1, 0)
+		if err := s.Acquire(context.Background(), 0); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+
+		granted := make(chan string, 3)
+		enqueue(t, s, 0, "low", granted)
+		enqueue(t, s, 10, "high", granted)
+		enqueue(t, s, 0, "low2", granted)
+
+		for _, want := range []string{"high", "low", "low2"} {
+			s.Release()
+			if got := <-granted; got != want {
+				t.Errorf("Expected %s to get the slot, got %s", want, got)
+			}
+		}
+	})
+
+	t.Run("Aging Prevents Starvation", func(t *testing.T) {
+		s := NewPrioritySemaphore(1, 1)
+		now := time.Now()
+		var clockMu sync.Mutex
+		s.now = func() time.Time {
+			clockMu.Lock()
+			defer clockMu.Unlock()
+			return now
+		}
+		if err := s.Acquire(context.Background(), 0); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+
+		granted := make(chan string, 2)
+		enqueue(t, s, 0, "old", granted)
+		clockMu.Lock()
+		now = now.Add(20 * time.Second)
+		clockMu.Unlock()
+		enqueue(t, s, 10, "new", granted)
+
+		s.Release()
+		if got := <-granted; got != "old" {
+			t.Errorf("Expected the aged waiter to get the slot, got %s", got)
+		}
+	})
+
+	t.Run("Cancelled Waiter", func(t *testing.T) {
+		s := NewPrioritySemaphore(1, 0)
+		if err := s.Acquire(context.Background(), 0); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := s.Acquire(ctx, 5); err == nil {
+			t.Fatal("Expected error from cancelled context")
+		}
+		if s.Waiting() != 0 {
+			t.Errorf("Cancelled waiter should leave the queue, %d waiting", s.Waiting())
+		}
+
+		s.Release()
+		if s.InUse() != 0 {
+			t.Errorf("Expected no slots in use, got %d", s.InUse())
+		}
+	})
+}
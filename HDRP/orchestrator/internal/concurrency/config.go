@@ -12,6 +12,12 @@ type Config struct {
 	ResearcherRateLimit   int
 	CriticRateLimit       int
 	SynthesizerRateLimit  int
+	// Requests per second per service; > 0 limits by rate instead of the
+	// concurrent call limit above
+	ResearcherQPS         float64
+	CriticQPS             float64
+	SynthesizerQPS        float64
+	RateLimitBurst        int // Requests a QPS limiter allows at once; 0 means ceil(qps)
 	LockProvider          string
 	EtcdEndpoints         string
 	RedisAddr             string
@@ -29,6 +35,10 @@ func NewConfig(cfg *config.Config) *Config {
 		ResearcherRateLimit:  cfg.Concurrency.RateLimits.Researcher,
 		CriticRateLimit:      cfg.Concurrency.RateLimits.Critic,
 		SynthesizerRateLimit: cfg.Concurrency.RateLimits.Synthesizer,
+		ResearcherQPS:        cfg.Concurrency.RateLimits.ResearcherQPS,
+		CriticQPS:            cfg.Concurrency.RateLimits.CriticQPS,
+		SynthesizerQPS:       cfg.Concurrency.RateLimits.SynthesizerQPS,
+		RateLimitBurst:       cfg.Concurrency.RateLimits.Burst,
 		LockProvider:         cfg.Concurrency.Lock.Provider,
 		EtcdEndpoints:        cfg.Concurrency.Lock.Etcd.Endpoints,
 		RedisAddr:            cfg.Concurrency.Lock.Redis.Address,
@@ -0,0 +1,129 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PrioritySemaphore hands out a fixed number of slots, granting freed slots
+// to the waiter with the highest effective priority rather than the one that
+// asked first. A waiter's effective priority is its priority plus agingRate
+// for every second it has waited, so low-priority waiters are eventually
+// served even while higher-priority work keeps arriving. Waiters with equal
+// effective priority are served in arrival order.
+type PrioritySemaphore struct {
+	mu        sync.Mutex
+	capacity  int
+	inUse     int
+	agingRate float64 // Priority gained per second of waiting
+	waiters   []*semaphoreWaiter
+	nextSeq   uint64
+	now       func() time.Time
+}
+
+type semaphoreWaiter struct {
+	priority float64
+	enqueued time.Time
+	seq      uint64
+	ready    chan struct{} // Closed when the slot is handed over
+}
+
+// NewPrioritySemaphore creates a semaphore with capacity slots whose waiters
+// gain agingRate priority per second. capacity <= 0 is treated as 1 and a
+// negative agingRate as 0 (no aging).
+func NewPrioritySemaphore(capacity int, agingRate float64) *PrioritySemaphore {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	if agingRate < 0 {
+		agingRate = 0
+	}
+	return &PrioritySemaphore{capacity: capacity, agingRate: agingRate, now: time.Now}
+}
+
+// Acquire blocks until a slot is granted to the caller or the context is
+// cancelled. Higher priorities are served first.
+func (s *PrioritySemaphore) Acquire(ctx context.Context, priority int) error {
+	s.mu.Lock()
+	if s.inUse < s.capacity && len(s.waiters) == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semaphoreWaiter{
+		priority: float64(priority),
+		enqueued: s.now(),
+		seq:      s.nextSeq,
+		ready:    make(chan struct{}),
+	}
+	s.nextSeq++
+	s.waiters = append(s.waiters, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, other := range s.waiters {
+			if other == w {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				return fmt.Errorf("priority slot acquire cancelled: %w", ctx.Err())
+			}
+		}
+		// The slot was handed over as the context ended; pass it on
+		s.releaseLocked()
+		return fmt.Errorf("priority slot acquire cancelled: %w", ctx.Err())
+	}
+}
+
+// Release frees a slot taken by Acquire, handing it to the most urgent waiter.
+func (s *PrioritySemaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked()
+}
+
+func (s *PrioritySemaphore) releaseLocked() {
+	if len(s.waiters) == 0 {
+		if s.inUse > 0 {
+			s.inUse--
+		}
+		return
+	}
+
+	now := s.now()
+	best := 0
+	bestScore := s.effectivePriority(s.waiters[0], now)
+	for i := 1; i < len(s.waiters); i++ {
+		score := s.effectivePriority(s.waiters[i], now)
+		if score > bestScore || (score == bestScore && s.waiters[i].seq < s.waiters[best].seq) {
+			best, bestScore = i, score
+		}
+	}
+	w := s.waiters[best]
+	s.waiters = append(s.waiters[:best], s.waiters[best+1:]...)
+	close(w.ready)
+}
+
+// effectivePriority is a waiter's priority after aging.
+func (s *PrioritySemaphore) effectivePriority(w *semaphoreWaiter, now time.Time) float64 {
+	return w.priority + s.agingRate*now.Sub(w.enqueued).Seconds()
+}
+
+// InUse returns the number of slots currently held.
+func (s *PrioritySemaphore) InUse() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inUse
+}
+
+// Waiting returns the number of callers blocked in Acquire.
+func (s *PrioritySemaphore) Waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiters)
+}
@@ -3,15 +3,27 @@ package concurrency
 import (
 	"context"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 )
 
-// RateLimiter implements a token bucket rate limiter for controlling concurrency.
+// RateLimiter limits calls to a service in one of two modes. Created with
+// NewRateLimiter it caps concurrent calls: each Acquire holds a token until
+// Release. Created with NewRateLimiterQPS it caps the request rate: tokens
+// refill at a fixed rate up to a burst, Acquire consumes one and Release is a
+// no-op.
 type RateLimiter struct {
 	maxConcurrent int
 	tokens        chan struct{}
 	mu            sync.Mutex
+	cooldownUntil time.Time // No tokens are handed out before this time
+
+	// Token bucket state, used when qps > 0
+	qps        float64
+	burst      int
+	available  float64
+	lastRefill time.Time
 }
 
 // NewRateLimiter creates a rate limiter with the specified maximum concurrent operations.
@@ -33,9 +45,43 @@ func NewRateLimiter(maxConcurrent int) *RateLimiter {
 	return rl
 }
 
-// Acquire blocks until a token is available or context is cancelled.
-// Returns an error if the context is cancelled before a token is acquired.
+// NewRateLimiterQPS creates a rate limiter allowing qps requests per second
+// on average, with bursts of up to burst requests. A burst <= 0 means
+// ceil(qps), so a full second's worth of requests may go at once.
+func NewRateLimiterQPS(qps float64, burst int) *RateLimiter {
+	if qps <= 0 {
+		qps = 1
+	}
+	if burst <= 0 {
+		burst = int(math.Ceil(qps))
+	}
+	return &RateLimiter{
+		qps:        qps,
+		burst:      burst,
+		available:  float64(burst),
+		lastRefill: time.Now(),
+	}
+}
+
+// Acquire blocks until any cooldown has passed and a token is available, or
+// the context is cancelled. Returns an error if the context is cancelled
+// before a token is acquired.
 func (rl *RateLimiter) Acquire(ctx context.Context) error {
+	for wait := rl.CooldownRemaining(); wait > 0; wait = rl.CooldownRemaining() {
+		if err := sleepCtx(ctx, wait); err != nil {
+			return err
+		}
+	}
+
+	if rl.qps > 0 {
+		for wait := rl.take(); wait > 0; wait = rl.take() {
+			if err := sleepCtx(ctx, wait); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	select {
 	case <-rl.tokens:
 		return nil
@@ -44,9 +90,43 @@ func (rl *RateLimiter) Acquire(ctx context.Context) error {
 	}
 }
 
+// sleepCtx waits for d or until ctx is cancelled.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("rate limiter acquire cancelled: %w", ctx.Err())
+	}
+}
+
+// take consumes a bucket token if one is available, returning 0. Otherwise
+// it returns how long until the next token is due.
+func (rl *RateLimiter) take() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	rl.available = math.Min(float64(rl.burst), rl.available+now.Sub(rl.lastRefill).Seconds()*rl.qps)
+	rl.lastRefill = now
+	if rl.available >= 1 {
+		rl.available--
+		return 0
+	}
+	return time.Duration((1 - rl.available) / rl.qps * float64(time.Second))
+}
+
 // TryAcquire attempts to acquire a token without blocking.
 // Returns true if a token was acquired, false otherwise.
 func (rl *RateLimiter) TryAcquire() bool {
+	if rl.CooldownRemaining() > 0 {
+		return false
+	}
+	if rl.qps > 0 {
+		return rl.take() == 0
+	}
 	select {
 	case <-rl.tokens:
 		return true
@@ -62,8 +142,12 @@ func (rl *RateLimiter) AcquireWithTimeout(timeout time.Duration) error {
 	return rl.Acquire(ctx)
 }
 
-// Release returns a token to the bucket, allowing another operation to proceed.
+// Release returns a token to the bucket, allowing another operation to
+// proceed. In QPS mode tokens refill over time instead, so it does nothing.
 func (rl *RateLimiter) Release() {
+	if rl.qps > 0 {
+		return
+	}
 	select {
 	case rl.tokens <- struct{}{}:
 	default:
@@ -72,8 +156,34 @@ func (rl *RateLimiter) Release() {
 	}
 }
 
+// Throttle stops new acquisitions for d, e.g. when the backend asks
+// callers to back off. Overlapping cooldowns extend to the latest end.
+func (rl *RateLimiter) Throttle(d time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if until := time.Now().Add(d); until.After(rl.cooldownUntil) {
+		rl.cooldownUntil = until
+	}
+}
+
+// CooldownRemaining returns how long until acquisitions resume after Throttle.
+func (rl *RateLimiter) CooldownRemaining() time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	if wait := time.Until(rl.cooldownUntil); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
 // Available returns the number of available tokens.
 func (rl *RateLimiter) Available() int {
+	if rl.qps > 0 {
+		rl.mu.Lock()
+		defer rl.mu.Unlock()
+		elapsed := time.Since(rl.lastRefill).Seconds()
+		return int(math.Min(float64(rl.burst), rl.available+elapsed*rl.qps))
+	}
 	return len(rl.tokens)
 }
 
@@ -89,13 +199,29 @@ func NewRateLimiterManager(config *Config) *RateLimiterManager {
 		limiters: make(map[string]*RateLimiter),
 	}
 
-	manager.limiters["researcher"] = NewRateLimiter(config.ResearcherRateLimit)
-	manager.limiters["critic"] = NewRateLimiter(config.CriticRateLimit)
-	manager.limiters["synthesizer"] = NewRateLimiter(config.SynthesizerRateLimit)
-
+	manager.Configure(config)
 	return manager
 }
 
+// Configure replaces the researcher, critic and synthesizer limiters from
+// config. A service with a QPS set is limited by request rate; otherwise by
+// concurrent calls.
+func (m *RateLimiterManager) Configure(config *Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limiters["researcher"] = newServiceLimiter(config.ResearcherRateLimit, config.ResearcherQPS, config.RateLimitBurst)
+	m.limiters["critic"] = newServiceLimiter(config.CriticRateLimit, config.CriticQPS, config.RateLimitBurst)
+	m.limiters["synthesizer"] = newServiceLimiter(config.SynthesizerRateLimit, config.SynthesizerQPS, config.RateLimitBurst)
+}
+
+func newServiceLimiter(maxConcurrent int, qps float64, burst int) *RateLimiter {
+	if qps > 0 {
+		return NewRateLimiterQPS(qps, burst)
+	}
+	return NewRateLimiter(maxConcurrent)
+}
+
 // GetLimiter returns the rate limiter for a specific service type.
 func (m *RateLimiterManager) GetLimiter(serviceType string) *RateLimiter {
 	m.mu.RLock()
@@ -116,3 +242,12 @@ func (m *RateLimiterManager) SetLimiter(serviceType string, maxConcurrent int) {
 
 	m.limiters[serviceType] = NewRateLimiter(maxConcurrent)
 }
+
+// SetQPSLimiter sets a service type's limiter to allow qps requests per
+// second with bursts of up to burst.
+func (m *RateLimiterManager) SetQPSLimiter(serviceType string, qps float64, burst int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limiters[serviceType] = NewRateLimiterQPS(qps, burst)
+}
@@ -0,0 +1,85 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterQPS(t *testing.T) {
+	rl := NewRateLimiterQPS(20, 2)
+
+	// The full burst is available at once
+	if !rl.TryAcquire() || !rl.TryAcquire() {
+		t.Fatal("Expected the burst of 2 to be available immediately")
+	}
+	if rl.TryAcquire() {
+		t.Fatal("Expected the bucket to be empty after the burst")
+	}
+
+	// Release doesn't return tokens; only time does
+	rl.Release()
+	if rl.TryAcquire() {
+		t.Fatal("Expected Release to leave the bucket empty")
+	}
+
+	// At 20 QPS the next token is due within 50ms
+	start := time.Now()
+	if err := rl.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond || waited > time.Second {
+		t.Errorf("Acquire waited %v, want about 50ms", waited)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := rl.Acquire(ctx); err == nil {
+		t.Error("Expected Acquire to fail once the context expires")
+	}
+}
+
+func TestRateLimiterQPS_Rate(t *testing.T) {
+	rl := NewRateLimiterQPS(100, 1)
+
+	// Ten calls at 100 QPS take at least 90ms after the first
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := rl.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("10 acquisitions took %v, want them spread over about 90ms", elapsed)
+	}
+}
+
+func TestRateLimiterManager_Modes(t *testing.T) {
+	manager := NewRateLimiterManager(&Config{
+		ResearcherRateLimit:  1,
+		CriticRateLimit:      1,
+		SynthesizerRateLimit: 1,
+		CriticQPS:            1000,
+		RateLimitBurst:       3,
+	})
+
+	// Concurrency mode: the single slot stays taken until released
+	researcher := manager.GetLimiter("researcher")
+	if !researcher.TryAcquire() || researcher.TryAcquire() {
+		t.Error("Expected the researcher to allow exactly 1 concurrent call")
+	}
+
+	// QPS mode: the burst is available regardless of the concurrency limit
+	critic := manager.GetLimiter("critic")
+	for i := 0; i < 3; i++ {
+		if !critic.TryAcquire() {
+			t.Fatalf("Expected critic token %d of the burst", i+1)
+		}
+	}
+
+	manager.SetQPSLimiter("synthesizer", 1, 0)
+	synthesizer := manager.GetLimiter("synthesizer")
+	if !synthesizer.TryAcquire() || synthesizer.TryAcquire() {
+		t.Error("Expected a burst of ceil(1) = 1 for the synthesizer")
+	}
+}
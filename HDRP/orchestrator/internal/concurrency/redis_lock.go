@@ -3,24 +3,48 @@ package concurrency
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
-// RedisLock implements distributed locking using Redis (Redlock algorithm).
-// This is a placeholder implementation that will be completed when Redis dependencies are added.
+// releaseScript deletes a lock only if it still holds our token, so a lock
+// that expired and was taken by another instance is left alone.
+var releaseScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("del", KEYS[1])
+	else
+		return 0
+	end
+`)
+
+// extendScript resets a lock's TTL (ARGV[2], in milliseconds) only if it
+// still holds our token.
+var extendScript = redis.NewScript(`
+	if redis.call("get", KEYS[1]) == ARGV[1] then
+		return redis.call("pexpire", KEYS[1], ARGV[2])
+	else
+		return 0
+	end
+`)
+
+// RedisLock implements distributed locking using a single Redis instance.
+// Each lock is a key set with NX and a TTL whose value is a token unique to
+// the acquisition, so an instance only ever releases or extends its own locks.
 type RedisLock struct {
-	addr string
-	// client *redis.Client // Will be added when redis is imported
+	addr   string
+	client *redis.Client
+
+	mu      sync.Mutex
+	tokens  map[string]string // nodeID -> token of the lock we hold
+	metrics LockMetrics
 }
 
-// NewRedisLock creates a Redis-based distributed lock.
+// NewRedisLock creates a Redis-based distributed lock, failing if Redis
+// can't be reached.
 func NewRedisLock(addr string) (*RedisLock, error) {
-	// TODO: Implement Redis client initialization
-	// For now, return an error to trigger fallback to in-memory
-	return nil, fmt.Errorf("redis support not yet implemented - install redis client: go get github.com/redis/go-redis/v9")
-
-	/*
-	// Future implementation:
 	client := redis.NewClient(&redis.Options{
 		Addr:         addr,
 		DialTimeout:  5 * time.Second,
@@ -31,108 +55,141 @@ func NewRedisLock(addr string) (*RedisLock, error) {
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
-	
+
 	if err := client.Ping(ctx).Err(); err != nil {
-		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", addr, err)
 	}
 
 	return &RedisLock{
 		addr:   addr,
 		client: client,
+		tokens: make(map[string]string),
 	}, nil
-	*/
 }
 
-// AcquireNodeLock acquires a distributed lock using Redis SET NX.
+// lockKey returns the Redis key holding a node's lock.
+func lockKey(nodeID string) string {
+	return fmt.Sprintf("hdrp:lock:%s", nodeID)
+}
+
+// AcquireNodeLock acquires a distributed lock using SET NX with an expiry.
 func (r *RedisLock) AcquireNodeLock(ctx context.Context, nodeID string, ttl time.Duration) (bool, error) {
-	return false, fmt.Errorf("redis lock not implemented")
-
-	/*
-	// Future implementation using SET NX with expiry:
-	key := fmt.Sprintf("hdrp:lock:%s", nodeID)
-	value := fmt.Sprintf("%d", time.Now().UnixNano()) // Unique identifier
-	
-	ok, err := r.client.SetNX(ctx, key, value, ttl).Result()
+	token := uuid.NewString()
+	ok, err := r.client.SetNX(ctx, lockKey(nodeID), token, ttl).Result()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics.AcquireAttempts++
 	if err != nil {
+		r.metrics.AcquireFailures++
 		return false, fmt.Errorf("redis SetNX failed: %w", err)
 	}
-	
-	return ok, nil
-	*/
+	if !ok {
+		r.metrics.AcquireFailures++
+		return false, nil
+	}
+
+	r.tokens[nodeID] = token
+	r.metrics.AcquireSuccess++
+	return true, nil
 }
 
-// ReleaseNodeLock releases the Redis lock using a Lua script for atomicity.
+// ReleaseNodeLock releases the lock if this instance still holds it.
 func (r *RedisLock) ReleaseNodeLock(ctx context.Context, nodeID string) error {
-	return fmt.Errorf("redis lock not implemented")
-
-	/*
-	// Future implementation using Lua script to ensure we only delete our own lock:
-	const releaseLuaScript = `
-		if redis.call("get", KEYS[1]) == ARGV[1] then
-			return redis.call("del", KEYS[1])
-		else
-			return 0
-		end
-	`
-	
-	key := fmt.Sprintf("hdrp:lock:%s", nodeID)
-	// We'd need to store the value when acquiring to verify ownership
-	
-	result, err := r.client.Eval(ctx, releaseLuaScript, []string{key}, value).Result()
+	r.mu.Lock()
+	r.metrics.ReleaseAttempts++
+	token, held := r.tokens[nodeID]
+	delete(r.tokens, nodeID)
+	r.mu.Unlock()
+
+	if !held {
+		r.recordRelease(false)
+		return fmt.Errorf("lock for node %s does not exist", nodeID)
+	}
+
+	deleted, err := releaseScript.Run(ctx, r.client, []string{lockKey(nodeID)}, token).Int64()
 	if err != nil {
+		r.recordRelease(false)
 		return fmt.Errorf("redis release failed: %w", err)
 	}
-	
-	if result.(int64) == 0 {
-		return fmt.Errorf("lock not owned by this client")
+	if deleted == 0 {
+		r.recordRelease(false)
+		return fmt.Errorf("lock for node %s expired and is no longer owned by this instance", nodeID)
 	}
-	
+
+	r.recordRelease(true)
 	return nil
-	*/
 }
 
-// ExtendLock extends the TTL using Redis EXPIRE command.
+// ExtendLock extends the TTL of a lock this instance holds.
 func (r *RedisLock) ExtendLock(ctx context.Context, nodeID string, ttl time.Duration) error {
-	return fmt.Errorf("redis lock not implemented")
+	r.mu.Lock()
+	r.metrics.ExtendAttempts++
+	token, held := r.tokens[nodeID]
+	r.mu.Unlock()
 
-	/*
-	// Future implementation:
-	key := fmt.Sprintf("hdrp:lock:%s", nodeID)
-	
-	ok, err := r.client.Expire(ctx, key, ttl).Result()
+	if !held {
+		r.recordExtend(false)
+		return fmt.Errorf("lock for node %s does not exist", nodeID)
+	}
+
+	extended, err := extendScript.Run(ctx, r.client, []string{lockKey(nodeID)}, token, ttl.Milliseconds()).Int64()
 	if err != nil {
+		r.recordExtend(false)
 		return fmt.Errorf("redis extend failed: %w", err)
 	}
-	
-	if !ok {
-		return fmt.Errorf("lock does not exist")
+	if extended == 0 {
+		r.mu.Lock()
+		if r.tokens[nodeID] == token {
+			delete(r.tokens, nodeID)
+		}
+		r.mu.Unlock()
+		r.recordExtend(false)
+		return fmt.Errorf("lock for node %s expired and is no longer owned by this instance", nodeID)
 	}
-	
+
+	r.recordExtend(true)
 	return nil
-	*/
 }
 
-// Close closes the Redis client connection.
-func (r *RedisLock) Close() error {
-	return nil
-	/*
-	// Future implementation:
-	if r.client != nil {
-		return r.client.Close()
+func (r *RedisLock) recordRelease(success bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if success {
+		r.metrics.ReleaseSuccess++
+	} else {
+		r.metrics.ReleaseFailures++
 	}
-	return nil
-	*/
+}
+
+func (r *RedisLock) recordExtend(success bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if success {
+		r.metrics.ExtendSuccess++
+	} else {
+		r.metrics.ExtendFailures++
+	}
+}
+
+// GetMetrics returns the current lock metrics.
+func (r *RedisLock) GetMetrics() LockMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// Close closes the Redis client connection. Locks still held expire with
+// their TTL.
+func (r *RedisLock) Close() error {
+	return r.client.Close()
 }
 
 // HealthCheck verifies Redis is accessible.
 func (r *RedisLock) HealthCheck(ctx context.Context) error {
-	return fmt.Errorf("redis lock not implemented")
-
-	/*
-	// Future implementation:
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	
+
 	return r.client.Ping(ctx).Err()
-	*/
 }
@@ -0,0 +1,92 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestRedisLock(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+
+	// Two orchestrator instances sharing one Redis
+	a, err := NewRedisLock(server.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisLock failed: %v", err)
+	}
+	defer a.Close()
+	b, err := NewRedisLock(server.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisLock failed: %v", err)
+	}
+	defer b.Close()
+
+	if acquired, err := a.AcquireNodeLock(ctx, "node1", 10*time.Second); err != nil || !acquired {
+		t.Fatalf("First acquire = %v, %v; want true", acquired, err)
+	}
+	if ttl := server.TTL("hdrp:lock:node1"); ttl != 10*time.Second {
+		t.Errorf("Lock TTL = %v, want 10s", ttl)
+	}
+	if acquired, err := b.AcquireNodeLock(ctx, "node1", 10*time.Second); err != nil || acquired {
+		t.Fatalf("Acquire of held lock = %v, %v; want false", acquired, err)
+	}
+	if err := b.ReleaseNodeLock(ctx, "node1"); err == nil {
+		t.Error("Expected releasing another instance's lock to fail")
+	}
+	if err := b.ExtendLock(ctx, "node1", time.Minute); err == nil {
+		t.Error("Expected extending another instance's lock to fail")
+	}
+
+	if err := a.ExtendLock(ctx, "node1", time.Minute); err != nil {
+		t.Fatalf("ExtendLock failed: %v", err)
+	}
+	if ttl := server.TTL("hdrp:lock:node1"); ttl != time.Minute {
+		t.Errorf("Lock TTL after extend = %v, want 1m", ttl)
+	}
+
+	// Once a's lock expires, b may take it, and a must not release b's lock
+	server.FastForward(2 * time.Minute)
+	if acquired, err := b.AcquireNodeLock(ctx, "node1", 10*time.Second); err != nil || !acquired {
+		t.Fatalf("Acquire after expiry = %v, %v; want true", acquired, err)
+	}
+	if err := a.ExtendLock(ctx, "node1", time.Minute); err == nil {
+		t.Error("Expected extending an expired lock to fail")
+	}
+	if err := a.ReleaseNodeLock(ctx, "node1"); err == nil {
+		t.Error("Expected releasing an expired lock to fail")
+	}
+	if !server.Exists("hdrp:lock:node1") {
+		t.Fatal("Expired holder deleted the new holder's lock")
+	}
+
+	if err := b.ReleaseNodeLock(ctx, "node1"); err != nil {
+		t.Fatalf("ReleaseNodeLock failed: %v", err)
+	}
+	if server.Exists("hdrp:lock:node1") {
+		t.Error("Lock key remains after release")
+	}
+	if m := b.GetMetrics(); m.AcquireSuccess != 1 || m.AcquireFailures != 1 || m.ReleaseSuccess != 1 || m.ReleaseFailures != 1 {
+		t.Errorf("Unexpected metrics: %+v", m)
+	}
+
+	if err := a.HealthCheck(ctx); err != nil {
+		t.Errorf("HealthCheck failed: %v", err)
+	}
+	server.Close()
+	if err := a.HealthCheck(ctx); err == nil {
+		t.Error("Expected HealthCheck to fail with Redis down")
+	}
+}
+
+func TestNewRedisLock_Unreachable(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	if _, err := NewRedisLock(addr); err == nil {
+		t.Fatal("Expected an error connecting to a stopped Redis")
+	}
+}
@@ -13,14 +13,18 @@ type Task struct {
 }
 
 // WorkerPool manages a pool of goroutines for concurrent task execution.
+// The number of workers can be changed while tasks run with Resize.
 type WorkerPool struct {
 	maxWorkers   int
+	stops        []chan struct{} // One per running worker; closing it retires the worker
+	nextID       int             // ID of the next worker spawned; IDs are never reused
 	taskQueue    chan Task
 	resultQueue  chan TaskResult
 	wg           sync.WaitGroup
 	ctx          context.Context
 	cancel       context.CancelFunc
 	started      bool
+	stopped      bool
 	mu           sync.Mutex
 }
 
@@ -57,22 +61,81 @@ func (wp *WorkerPool) Start() error {
 	}
 
 	for i := 0; i < wp.maxWorkers; i++ {
-		wp.wg.Add(1)
-		go wp.worker(i)
+		wp.spawnLocked()
 	}
 
 	wp.started = true
 	return nil
 }
 
-// worker is the goroutine that processes tasks from the queue.
-func (wp *WorkerPool) worker(id int) {
+// Resize changes the number of workers to n (at least 1). Growing spawns
+// workers straight away; shrinking signals the surplus workers to exit once
+// their current task, if any, is done, so no task is interrupted. Resizing a
+// pool that hasn't started only sets how many workers Start spawns, and
+// resizing one that has shut down does nothing.
+func (wp *WorkerPool) Resize(n int) {
+	if n <= 0 {
+		n = 1
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.stopped {
+		return
+	}
+	wp.maxWorkers = n
+	if !wp.started {
+		return
+	}
+
+	for len(wp.stops) < n {
+		wp.spawnLocked()
+	}
+	for len(wp.stops) > n {
+		last := len(wp.stops) - 1
+		close(wp.stops[last])
+		wp.stops = wp.stops[:last]
+	}
+}
+
+// Size returns the number of workers the pool is sized for. Workers retired
+// by a shrink may still be finishing their last task.
+func (wp *WorkerPool) Size() int {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	return wp.maxWorkers
+}
+
+// spawnLocked starts one more worker. wp.mu must be held.
+func (wp *WorkerPool) spawnLocked() {
+	stop := make(chan struct{})
+	wp.stops = append(wp.stops, stop)
+	id := wp.nextID
+	wp.nextID++
+	wp.wg.Add(1)
+	go wp.worker(id, stop)
+}
+
+// worker is the goroutine that processes tasks from the queue until the
+// pool shuts down or stop is closed.
+func (wp *WorkerPool) worker(id int, stop <-chan struct{}) {
 	defer wp.wg.Done()
 
 	for {
+		// Check for retirement first: with a task also waiting, select
+		// would otherwise pick at random
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
 		select {
 		case <-wp.ctx.Done():
 			return
+		case <-stop:
+			return
 		case task, ok := <-wp.taskQueue:
 			if !ok {
 				return
@@ -118,10 +181,11 @@ func (wp *WorkerPool) Results() <-chan TaskResult {
 // It waits for all in-flight tasks to complete.
 func (wp *WorkerPool) Shutdown() {
 	wp.mu.Lock()
-	if !wp.started {
+	if !wp.started || wp.stopped {
 		wp.mu.Unlock()
 		return
 	}
+	wp.stopped = true
 	wp.mu.Unlock()
 
 	close(wp.taskQueue)
@@ -132,6 +196,10 @@ func (wp *WorkerPool) Shutdown() {
 
 // ShutdownNow immediately stops the worker pool without waiting for tasks.
 func (wp *WorkerPool) ShutdownNow() {
+	wp.mu.Lock()
+	wp.stopped = true
+	wp.mu.Unlock()
+
 	wp.cancel()
 	close(wp.taskQueue)
 	close(wp.resultQueue)
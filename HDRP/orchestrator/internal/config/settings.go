@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,12 @@ type Config struct {
 	Services    ServiceConfig   `mapstructure:"services"`
 	Concurrency ConcurrencyConfig `mapstructure:"concurrency"`
 	Storage     StorageConfig   `mapstructure:"storage"`
+	Planning    PlanningConfig  `mapstructure:"planning"`
+	Retry       RetryConfig     `mapstructure:"retry"`
+	Execution   ExecutionConfig `mapstructure:"execution"`
+	Recovery    RecoveryConfig  `mapstructure:"recovery"`
+	Events      EventsConfig    `mapstructure:"events"`
+	HTTP        HTTPConfig      `mapstructure:"http"`
 }
 
 // ServiceConfig holds service discovery addresses
@@ -23,6 +30,22 @@ type ServiceConfig struct {
 	Researcher  ServiceAddress `mapstructure:"researcher"`
 	Critic      ServiceAddress `mapstructure:"critic"`
 	Synthesizer ServiceAddress `mapstructure:"synthesizer"`
+	Connection  Connection     `mapstructure:"connection"`
+	// Secondary providers per service (researcher, critic, synthesizer),
+	// tried in order when the primary's breaker is open
+	Fallbacks map[string][]FallbackProvider `mapstructure:"fallbacks"`
+}
+
+// Connection controls how the server connects to the services at startup.
+// Connections are always made lazily, so startup never fails on backends
+// that are still coming up
+type Connection struct {
+	// Wait at startup (up to WarmUpSeconds) until every service passes a
+	// health check; services still down are logged, not fatal
+	WaitForReady bool `mapstructure:"wait_for_ready"`
+	// Connect and health-check for up to this long after startup, in the
+	// background unless WaitForReady is set; 0 connects on first use
+	WarmUpSeconds int `mapstructure:"warm_up_seconds"`
 }
 
 // ServiceAddress represents a single service endpoint
@@ -30,19 +53,41 @@ type ServiceAddress struct {
 	Address string `mapstructure:"address"`
 }
 
+// FallbackProvider is a secondary provider of a service
+type FallbackProvider struct {
+	Name    string `mapstructure:"name"`
+	Address string `mapstructure:"address"`
+}
+
 // ConcurrencyConfig holds concurrency settings
 type ConcurrencyConfig struct {
-	MaxWorkers int         `mapstructure:"max_workers"`
-	RateLimits RateLimits  `mapstructure:"rate_limits"`
-	Lock       LockConfig  `mapstructure:"lock"`
-	Timeouts   Timeouts    `mapstructure:"timeouts"`
+	MaxWorkers    int        `mapstructure:"max_workers"`
+	MaxActiveRuns int        `mapstructure:"max_active_runs"` // Runs the server executes at once; 0 means unlimited
+	GlobalWorkers int        `mapstructure:"global_workers"`  // Nodes running at once across all runs, by priority; 0 means no shared limit
+	PriorityAging float64    `mapstructure:"priority_aging"`  // Priority a waiting node gains per second
+	RateLimits    RateLimits `mapstructure:"rate_limits"`
+	// Retry policies for individual node types, keyed by type; other types
+	// use the default policy
+	Retries  map[string]ServiceRetry `mapstructure:"retries"`
+	Lock     LockConfig              `mapstructure:"lock"`
+	Timeouts Timeouts                `mapstructure:"timeouts"`
 }
 
-// RateLimits holds per-service rate limits
+// RateLimits holds per-service rate limits. A service with a QPS set is
+// limited by request rate instead of concurrent calls.
 type RateLimits struct {
-	Researcher  int `mapstructure:"researcher"`
-	Critic      int `mapstructure:"critic"`
-	Synthesizer int `mapstructure:"synthesizer"`
+	Researcher     int     `mapstructure:"researcher"`
+	Critic         int     `mapstructure:"critic"`
+	Synthesizer    int     `mapstructure:"synthesizer"`
+	ResearcherQPS  float64 `mapstructure:"researcher_qps"`
+	CriticQPS      float64 `mapstructure:"critic_qps"`
+	SynthesizerQPS float64 `mapstructure:"synthesizer_qps"`
+	Burst          int     `mapstructure:"burst"` // Requests a QPS limit allows at once; 0 means ceil(qps)
+}
+
+// ServiceRetry overrides the default retry policy for one node type
+type ServiceRetry struct {
+	MaxAttempts int `mapstructure:"max_attempts"` // Retries after the first attempt
 }
 
 // LockConfig holds distributed locking configuration
@@ -67,11 +112,41 @@ type RedisConfig struct {
 type Timeouts struct {
 	NodeExecutionMinutes int `mapstructure:"node_execution_minutes"`
 	LockSeconds          int `mapstructure:"lock_seconds"`
+	HeartbeatSeconds     int `mapstructure:"heartbeat_seconds"`
 }
 
 // StorageConfig holds storage path configuration
 type StorageConfig struct {
-	Database DatabaseConfig `mapstructure:"database"`
+	Database       DatabaseConfig `mapstructure:"database"`
+	AsyncQueueSize int            `mapstructure:"async_queue_size"` // Queued status transitions; 0 persists synchronously
+	// Seconds between scheduled snapshots of each running graph; 0 disables
+	SnapshotIntervalSeconds int `mapstructure:"snapshot_interval_seconds"`
+	// Seconds a single storage query or statement may run; 0 keeps the default
+	OpTimeoutSeconds int             `mapstructure:"op_timeout_seconds"`
+	Artifacts        ArtifactsConfig `mapstructure:"artifacts"`
+	Snapshots        SnapshotsConfig `mapstructure:"snapshots"`
+	// WAL sequence allocation: memory (default, single process) or database
+	WALSequences string `mapstructure:"wal_sequences"`
+	// Hours a finished graph is kept after its last update; 0 keeps graphs forever
+	RetentionHours int `mapstructure:"retention_hours"`
+	// Minutes between cleanups of finished graphs past retention_hours
+	RetentionCleanupMinutes int `mapstructure:"retention_cleanup_minutes"`
+}
+
+// SnapshotsConfig moves large graph snapshots out of the database
+type SnapshotsConfig struct {
+	Directory string `mapstructure:"directory"` // Empty keeps every snapshot in the database
+	// Snapshots at least this large are written to Directory with only a
+	// reference kept in the database
+	MinBytes int `mapstructure:"min_bytes"`
+}
+
+// ArtifactsConfig holds where large run outputs are stored
+type ArtifactsConfig struct {
+	Directory string `mapstructure:"directory"`
+	// Report bytes kept in memory; larger reports are streamed to Directory
+	// and responses carry a preview plus the artifact URI. 0 is unbounded
+	MaxReportBytes int `mapstructure:"max_report_bytes"`
 }
 
 // DatabaseConfig holds database-specific settings
@@ -79,6 +154,119 @@ type DatabaseConfig struct {
 	Path string `mapstructure:"path"`
 }
 
+// PlanningConfig selects how queries are decomposed into graphs
+type PlanningConfig struct {
+	Decomposer    string `mapstructure:"decomposer"`     // principal (default), local
+	BlueprintsDir string `mapstructure:"blueprints_dir"` // YAML blueprints for the local decomposer; empty uses the built-in ones
+}
+
+// RetryConfig holds node retry settings
+type RetryConfig struct {
+	Upstream            bool `mapstructure:"upstream"`              // Re-run parents when a node fails on unusable parent output
+	MaxOverrideAttempts int  `mapstructure:"max_override_attempts"` // Upper bound for per-request retry overrides
+	AllowBreakerBypass  bool `mapstructure:"allow_breaker_bypass"`  // Whether requests may ignore circuit breakers
+	MaxConcurrent       int  `mapstructure:"max_concurrent"`        // Nodes per run backing off at once; 0 is unlimited
+	// gRPC codes (e.g. RESOURCE_EXHAUSTED) that return a node to the scheduler
+	// after a cooldown instead of retrying in place
+	RequeueCodes      []string `mapstructure:"requeue_codes"`
+	RequeueCooldownMs int      `mapstructure:"requeue_cooldown_ms"`
+	// Node types with their own circuit breaker before the least recently
+	// used is evicted; 0 uses the default
+	MaxBreakers int `mapstructure:"max_breakers"`
+	// Period between saves of circuit breaker states, which are restored
+	// at startup; 0 disables saving
+	BreakerSaveSeconds int `mapstructure:"breaker_save_seconds"`
+}
+
+// ExecutionConfig holds node execution behaviour
+type ExecutionConfig struct {
+	UnknownNodeTypes string `mapstructure:"unknown_node_types"` // strict (default), lenient
+	EmptyResults     string `mapstructure:"empty_results"`      // allow (default), retry, fail
+	MaxDepth         int    `mapstructure:"max_depth"`          // Layers a graph may have unless it sets its own limit; 0 means 3
+	FailedParents    string `mapstructure:"failed_parents"`     // skip (default), fail, block
+	// Node types plans may contain; empty allows all. A tenant listed in
+	// TenantNodeTypes uses its own list instead, even an empty one.
+	AllowedNodeTypes []string            `mapstructure:"allowed_node_types"`
+	TenantNodeTypes  map[string][]string `mapstructure:"tenant_node_types"`
+	// Scheduling priority added per level of depth, capped at DepthBoostMax,
+	// so ready aggregation nodes aren't starved by high-relevance roots
+	DepthBoostPerLevel float64 `mapstructure:"depth_boost_per_level"`
+	DepthBoostMax      float64 `mapstructure:"depth_boost_max"`
+	// Run nodes one at a time in a fixed order for reproducible traces
+	Deterministic bool `mapstructure:"deterministic"`
+	// Researcher results cached by node type and config; 0 disables caching
+	ResultCacheSize int `mapstructure:"result_cache_size"`
+	// Assumptions /plan uses for node types without latency history
+	Estimate EstimateConfig `mapstructure:"estimate"`
+	Critic   CriticConfig   `mapstructure:"critic"`
+	Budget   BudgetConfig   `mapstructure:"budget"`
+}
+
+// BudgetConfig caps what a single run may spend; 0 leaves a limit unset
+type BudgetConfig struct {
+	MaxSeconds  int     `mapstructure:"max_seconds"`  // Wall-clock time per run
+	MaxAttempts int     `mapstructure:"max_attempts"` // Node attempts per run, retries included
+	MaxCost     float64 `mapstructure:"max_cost"`     // Sum of estimate.node_costs over node attempts
+}
+
+// CriticConfig controls how critic nodes send claims for verification
+type CriticConfig struct {
+	BatchSize        int  `mapstructure:"batch_size"`        // Claims per Verify request; 0 sends all at once
+	BatchConcurrency int  `mapstructure:"batch_concurrency"` // Batches in flight per node; 0 means 4
+	AllowPartial     bool `mapstructure:"allow_partial"`     // Succeed with verified batches when others fail
+}
+
+// EstimateConfig sets per-node-type defaults for run estimates
+type EstimateConfig struct {
+	LatencyMs map[string]int     `mapstructure:"latency_ms"` // Average latency per node type
+	NodeCosts map[string]float64 `mapstructure:"node_costs"` // Cost weight per node type; unlisted types cost 1
+}
+
+// EventsConfig selects where run lifecycle events are published
+type EventsConfig struct {
+	Publisher string `mapstructure:"publisher"`  // none (default), log, http, nats
+	URL       string `mapstructure:"url"`        // http(s) endpoint or nats://host:port
+	Subject   string `mapstructure:"subject"`    // NATS subject prefix
+	QueueSize int    `mapstructure:"queue_size"` // Events buffered for delivery; 0 uses the default
+
+	// HTTP delivery; 0 uses the publisher defaults
+	Workers        int `mapstructure:"workers"`         // Concurrent deliveries
+	TimeoutSeconds int `mapstructure:"timeout_seconds"` // Per delivery attempt
+	MaxRetries     int `mapstructure:"max_retries"`     // Retries per event; -1 disables
+}
+
+// HTTPConfig controls the orchestrator's HTTP API
+type HTTPConfig struct {
+	Compression CompressionConfig `mapstructure:"compression"`
+
+	// API key each tenant authenticates with (X-API-Key header), keyed by
+	// tenant name. Requests without a key run with no tenant.
+	TenantKeys map[string]string `mapstructure:"tenant_keys"`
+
+	// Seconds an async run (/execute?async=true) may take; 0 means no limit
+	AsyncTimeoutSeconds int `mapstructure:"async_timeout_seconds"`
+}
+
+// CompressionConfig controls gzip compression of HTTP responses
+type CompressionConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	MinBytes int  `mapstructure:"min_bytes"` // Smaller responses are sent as is; 0 means 1024
+}
+
+// RecoveryConfig controls resuming graphs abandoned by a crashed instance
+type RecoveryConfig struct {
+	Enabled         bool `mapstructure:"enabled"`
+	Concurrency     int  `mapstructure:"concurrency"`      // Max graphs recovered at once
+	LeaseSeconds    int  `mapstructure:"lease_seconds"`    // How long a worker owns a recovering graph
+	QuarantineAfter int  `mapstructure:"quarantine_after"` // Failed resumes before a node is skipped; 0 disables
+	// Rebuild retry metrics from logged node history on resume, so attempts
+	// made before a crash count against the retry budget
+	RestoreRetryMetrics bool `mapstructure:"restore_retry_metrics"`
+	// Store each successful node's result, so a resumed run keeps the nodes
+	// that completed before a crash and feeds their outputs downstream
+	PersistNodeResults bool `mapstructure:"persist_node_results"`
+}
+
 // Load reads configuration from YAML files and environment variables
 //
 // Configuration precedence (highest to lowest):
@@ -154,6 +342,8 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	applyDeprecatedKeys(v, &cfg)
+
 	// Validate required fields
 	if err := validate(&cfg); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -162,6 +352,18 @@ func Load(configPath string) (*Config, error) {
 	return &cfg, nil
 }
 
+// applyDeprecatedKeys honours keys that have been replaced, unless their
+// replacement is also set, and warns about each one used
+func applyDeprecatedKeys(v *viper.Viper, cfg *Config) {
+	// services.connection.lazy is the inverse of wait_for_ready
+	if v.IsSet("services.connection.lazy") {
+		log.Printf("[Config] Warning: services.connection.lazy is deprecated, use services.connection.wait_for_ready instead")
+		if !v.IsSet("services.connection.wait_for_ready") {
+			cfg.Services.Connection.WaitForReady = !v.GetBool("services.connection.lazy")
+		}
+	}
+}
+
 // validate checks required configuration fields
 func validate(cfg *Config) error {
 	if cfg.Services.Principal.Address == "" {
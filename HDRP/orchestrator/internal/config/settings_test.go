@@ -103,3 +103,65 @@ concurrency:
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestLoad_DeprecatedLazyConnection(t *testing.T) {
+	services := `
+services:
+  principal:
+    address: "p"
+  researcher:
+    address: "r"
+  critic:
+    address: "c"
+  synthesizer:
+    address: "s"
+  connection:
+`
+	tests := []struct {
+		name       string
+		connection string
+		want       bool
+	}{
+		{"lazy false waits", "    lazy: false\n", true},
+		{"lazy true does not wait", "    lazy: true\n", false},
+		{"wait_for_ready wins", "    lazy: false\n    wait_for_ready: false\n", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, t.TempDir(), "config.yaml", services+tt.connection+"concurrency:\n  max_workers: 1\n")
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if cfg.Services.Connection.WaitForReady != tt.want {
+				t.Errorf("WaitForReady = %v, want %v", cfg.Services.Connection.WaitForReady, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_RetryOverrideLimit(t *testing.T) {
+	dir := t.TempDir()
+	base := `
+services:
+  principal:
+    address: "principal"
+  researcher:
+    address: "researcher"
+  critic:
+    address: "critic"
+  synthesizer:
+    address: "synthesizer"
+concurrency:
+  max_workers: 4
+retry:
+  max_override_attempts: 7
+`
+	cfg, err := Load(writeConfig(t, dir, "config.yaml", base))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Retry.MaxOverrideAttempts != 7 {
+		t.Errorf("Retry.MaxOverrideAttempts = %d, want 7", cfg.Retry.MaxOverrideAttempts)
+	}
+}
@@ -0,0 +1,69 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataTenant is the graph metadata key naming the tenant that owns a plan.
+const MetadataTenant = "tenant"
+
+// ErrNodeTypeNotAllowed is returned when a plan contains a node type its
+// tenant may not run.
+var ErrNodeTypeNotAllowed = errors.New("node type not allowed")
+
+// NodeTypeAllowlist restricts which node types a plan may contain. A tenant
+// with its own list uses it in place of the global list, even when that list
+// is empty. An empty global list allows every type.
+type NodeTypeAllowlist struct {
+	Global  []string            // Types allowed for tenants without their own list
+	Tenants map[string][]string // Per-tenant lists, keyed by tenant name
+}
+
+// TypesFor returns the node types allowed for a tenant, and false if any type
+// is allowed. A tenant listed with no types may use none.
+func (a *NodeTypeAllowlist) TypesFor(tenant string) ([]string, bool) {
+	if a == nil {
+		return nil, false
+	}
+	if types, ok := a.Tenants[tenant]; ok && tenant != "" {
+		return types, true
+	}
+	return a.Global, len(a.Global) > 0
+}
+
+// ValidateNodeTypes checks every node against the allowlist for the graph's
+// tenant (see MetadataTenant). A nil allowlist allows everything.
+func (g *Graph) ValidateNodeTypes(allowlist *NodeTypeAllowlist) error {
+	tenant := g.Metadata[MetadataTenant]
+	types, restricted := allowlist.TypesFor(tenant)
+	if !restricted {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	var rejected []string
+	for _, n := range g.Nodes {
+		if !allowed[n.Type] {
+			rejected = append(rejected, fmt.Sprintf("%s (%s)", n.ID, n.Type))
+		}
+	}
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	owner := "plan"
+	if tenant != "" {
+		owner = fmt.Sprintf("tenant %q", tenant)
+	}
+	sorted := append([]string(nil), types...)
+	sort.Strings(sorted)
+	return fmt.Errorf("%w: %s may only use [%s], rejected nodes: %s",
+		ErrNodeTypeNotAllowed, owner, strings.Join(sorted, ", "), strings.Join(rejected, ", "))
+}
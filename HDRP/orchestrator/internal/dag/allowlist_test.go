@@ -0,0 +1,99 @@
+package dag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGraph_ValidateNodeTypes(t *testing.T) {
+	allowlist := &NodeTypeAllowlist{
+		Global: []string{"researcher", "critic", "synthesizer"},
+		Tenants: map[string][]string{
+			"sandboxed": {"researcher"},
+			"internal":  {"researcher", "critic", "synthesizer", "code_executor"},
+			"disabled":  {},
+		},
+	}
+
+	plan := func(tenant string, types ...string) *Graph {
+		g := &Graph{Metadata: map[string]string{}}
+		if tenant != "" {
+			g.Metadata[MetadataTenant] = tenant
+		}
+		for i, typ := range types {
+			g.Nodes = append(g.Nodes, Node{ID: string(rune('a' + i)), Type: typ})
+		}
+		return g
+	}
+
+	tests := []struct {
+		name      string
+		allowlist *NodeTypeAllowlist
+		graph     *Graph
+		wantErr   string
+	}{
+		{
+			name:      "Allowed plan",
+			allowlist: allowlist,
+			graph:     plan("", "researcher", "critic", "synthesizer"),
+		},
+		{
+			name:      "Disallowed type",
+			allowlist: allowlist,
+			graph:     plan("", "researcher", "code_executor"),
+			wantErr:   "b (code_executor)",
+		},
+		{
+			name:      "Tenant list replaces global",
+			allowlist: allowlist,
+			graph:     plan("internal", "researcher", "code_executor"),
+		},
+		{
+			name:      "Tenant list is narrower than global",
+			allowlist: allowlist,
+			graph:     plan("sandboxed", "researcher", "critic"),
+			wantErr:   `tenant "sandboxed" may only use [researcher]`,
+		},
+		{
+			name:      "Unlisted tenant uses global",
+			allowlist: allowlist,
+			graph:     plan("other", "code_executor"),
+			wantErr:   "a (code_executor)",
+		},
+		{
+			name:      "Tenant with an empty list may use nothing",
+			allowlist: allowlist,
+			graph:     plan("disabled", "researcher"),
+			wantErr:   `tenant "disabled" may only use []`,
+		},
+		{
+			name:      "Tenant with an empty list and no global list",
+			allowlist: &NodeTypeAllowlist{Tenants: map[string][]string{"disabled": nil}},
+			graph:     plan("disabled", "researcher"),
+			wantErr:   "a (researcher)",
+		},
+		{
+			name:  "No allowlist",
+			graph: plan("", "code_executor"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.graph.ValidateNodeTypes(tt.allowlist)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateNodeTypes() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNodeTypeNotAllowed) {
+				t.Fatalf("ValidateNodeTypes() error = %v, want ErrNodeTypeNotAllowed", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateNodeTypes() error = %q, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
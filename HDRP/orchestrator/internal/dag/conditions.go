@@ -0,0 +1,124 @@
+package dag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Condition is a parsed edge condition: a comparison of one of the source
+// node's outputs against a number. The grammar is deliberately minimal:
+//
+//	condition := name op number
+//	name      := letter { letter | digit | "_" }   (e.g. rejected_count)
+//	op        := ">" | ">=" | "<" | "<=" | "==" | "!="
+//	number    := any value strconv.ParseFloat accepts (e.g. 3, 0.5, -1)
+//
+// Whitespace around the operator is optional. There are no boolean
+// operators or parentheses; a target with several conditional edges runs
+// if any one of them holds.
+type Condition struct {
+	Output string
+	Op     string
+	Value  float64
+}
+
+// conditionOps lists the comparison operators, two-character ones first so
+// ">=" isn't read as ">".
+var conditionOps = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// ParseCondition parses an edge condition such as "rejected_count > 3".
+func ParseCondition(s string) (Condition, error) {
+	for _, op := range conditionOps {
+		i := strings.Index(s, op)
+		if i < 0 {
+			continue
+		}
+		name := strings.TrimSpace(s[:i])
+		if !isOutputName(name) {
+			return Condition{}, fmt.Errorf("invalid condition %q: bad output name %q", s, name)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(s[i+len(op):]), 64)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid condition %q: %s must be compared to a number", s, name)
+		}
+		return Condition{Output: name, Op: op, Value: value}, nil
+	}
+	return Condition{}, fmt.Errorf("invalid condition %q: no comparison operator", s)
+}
+
+func isOutputName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '_' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Holds reports whether the condition is met by outputs. A condition on an
+// output the node didn't report doesn't hold.
+func (c Condition) Holds(outputs map[string]float64) bool {
+	v, ok := outputs[c.Output]
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case ">":
+		return v > c.Value
+	case ">=":
+		return v >= c.Value
+	case "<":
+		return v < c.Value
+	case "<=":
+		return v <= c.Value
+	case "==":
+		return v == c.Value
+	case "!=":
+		return v != c.Value
+	}
+	return false
+}
+
+// SetNodeOutputs records the named numeric outputs of a finished node, which
+// the conditions on its outgoing edges are evaluated against. The executor
+// sets them before re-evaluating readiness.
+func (g *Graph) SetNodeOutputs(nodeID string, outputs map[string]float64) {
+	if g.outputs == nil {
+		g.outputs = make(map[string]map[string]float64)
+	}
+	g.outputs[nodeID] = outputs
+}
+
+// markUntaken records that nodeID was skipped because its branch wasn't
+// taken rather than because a parent failed.
+func (g *Graph) markUntaken(nodeID string) {
+	if g.untaken == nil {
+		g.untaken = make(map[string]bool)
+	}
+	g.untaken[nodeID] = true
+}
+
+// branchTaken reports whether a node whose parents all succeeded may run
+// given its incoming edges: it may if none are conditional, or if any
+// condition holds for its source's outputs. Conditions are checked by
+// Validate, so one that doesn't parse here never holds.
+func (g *Graph) branchTaken(incoming []Edge) bool {
+	conditional := false
+	for _, e := range incoming {
+		if e.Condition == "" {
+			continue
+		}
+		conditional = true
+		if cond, err := ParseCondition(e.Condition); err == nil && cond.Holds(g.outputs[e.From]) {
+			return true
+		}
+	}
+	return !conditional
+}
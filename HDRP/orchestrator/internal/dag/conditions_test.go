@@ -0,0 +1,143 @@
+package dag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Condition
+		wantErr bool
+	}{
+		{"rejected_count > 3", Condition{"rejected_count", ">", 3}, false},
+		{"rejected_count>=3", Condition{"rejected_count", ">=", 3}, false},
+		{"claim_count <= 0.5", Condition{"claim_count", "<=", 0.5}, false},
+		{"x == -1", Condition{"x", "==", -1}, false},
+		{"x != 2", Condition{"x", "!=", 2}, false},
+		{"x < 2", Condition{"x", "<", 2}, false},
+		{"rejected_count", Condition{}, true},
+		{"> 3", Condition{}, true},
+		{"1x > 3", Condition{}, true},
+		{"x > many", Condition{}, true},
+		{"x > 1 && y < 2", Condition{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCondition(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCondition(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCondition(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestConditionHolds(t *testing.T) {
+	outputs := map[string]float64{"rejected_count": 3}
+	for cond, want := range map[string]bool{
+		"rejected_count > 3":  false,
+		"rejected_count >= 3": true,
+		"rejected_count == 3": true,
+		"rejected_count != 3": false,
+		"rejected_count < 4":  true,
+		"verified_count > 0":  false, // Not reported
+	} {
+		c, err := ParseCondition(cond)
+		if err != nil {
+			t.Fatalf("ParseCondition(%q): %v", cond, err)
+		}
+		if got := c.Holds(outputs); got != want {
+			t.Errorf("%q holds = %v, want %v", cond, got, want)
+		}
+	}
+}
+
+// conditionalGraph is critic -> verify (if more than 3 claims rejected)
+// -> report, with critic already succeeded.
+func conditionalGraph() *Graph {
+	return &Graph{
+		Nodes: []Node{
+			{ID: "critic", Status: StatusSucceeded},
+			{ID: "verify", Status: StatusCreated},
+			{ID: "report", Status: StatusCreated},
+		},
+		Edges: []Edge{
+			{From: "critic", To: "verify", Condition: "rejected_count > 3"},
+			{From: "verify", To: "report"},
+		},
+	}
+}
+
+func TestEvaluateReadiness_Conditions(t *testing.T) {
+	t.Run("Condition holds", func(t *testing.T) {
+		g := conditionalGraph()
+		g.SetNodeOutputs("critic", map[string]float64{"rejected_count": 5})
+		if err := g.EvaluateReadiness(); err != nil {
+			t.Fatalf("EvaluateReadiness failed: %v", err)
+		}
+		if g.Nodes[1].Status != StatusPending {
+			t.Errorf("verify status = %s, want PENDING", g.Nodes[1].Status)
+		}
+		if g.Nodes[2].Status != StatusBlocked {
+			t.Errorf("report status = %s, want BLOCKED", g.Nodes[2].Status)
+		}
+	})
+
+	t.Run("Condition fails", func(t *testing.T) {
+		g := conditionalGraph()
+		// A branch not taken skips downstream nodes even under the fail policy
+		g.SetFailedParentPolicy(FailedParentFail)
+		g.SetNodeOutputs("critic", map[string]float64{"rejected_count": 1})
+		if err := g.EvaluateReadiness(); err != nil {
+			t.Fatalf("EvaluateReadiness failed: %v", err)
+		}
+		for _, n := range g.Nodes[1:] {
+			if n.Status != StatusSkipped {
+				t.Errorf("%s status = %s, want SKIPPED", n.ID, n.Status)
+			}
+		}
+		if !strings.Contains(g.Nodes[2].LastError, "verify was not taken") {
+			t.Errorf("report LastError = %q", g.Nodes[2].LastError)
+		}
+	})
+
+	t.Run("Any condition is enough", func(t *testing.T) {
+		g := conditionalGraph()
+		g.Nodes = append(g.Nodes, Node{ID: "audit", Status: StatusSucceeded})
+		g.Edges = append(g.Edges, Edge{From: "audit", To: "verify", Condition: "flagged > 0"})
+		g.SetNodeOutputs("critic", map[string]float64{"rejected_count": 0})
+		g.SetNodeOutputs("audit", map[string]float64{"flagged": 1})
+		if err := g.EvaluateReadiness(); err != nil {
+			t.Fatalf("EvaluateReadiness failed: %v", err)
+		}
+		if g.Nodes[1].Status != StatusPending {
+			t.Errorf("verify status = %s, want PENDING", g.Nodes[1].Status)
+		}
+	})
+
+	t.Run("Waits for parents", func(t *testing.T) {
+		g := conditionalGraph()
+		g.Nodes[0].Status = StatusRunning
+		if err := g.EvaluateReadiness(); err != nil {
+			t.Fatalf("EvaluateReadiness failed: %v", err)
+		}
+		if g.Nodes[1].Status != StatusBlocked {
+			t.Errorf("verify status = %s, want BLOCKED until critic finishes", g.Nodes[1].Status)
+		}
+	})
+}
+
+func TestValidate_RejectsBadCondition(t *testing.T) {
+	g := conditionalGraph()
+	for i := range g.Nodes {
+		g.Nodes[i].Type = "critic"
+	}
+	g.Edges[0].Condition = "rejected_count is high"
+	err := g.Validate()
+	if err == nil || !strings.Contains(err.Error(), "critic -> verify") {
+		t.Fatalf("Validate() = %v, want the bad condition reported", err)
+	}
+}
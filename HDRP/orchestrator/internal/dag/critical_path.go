@@ -0,0 +1,130 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ConfigCost is the node config key weighting a node on the critical path.
+// Nodes without it weigh 1, so unweighted graphs are measured in nodes.
+const ConfigCost = "cost"
+
+// Cost returns the node's critical path weight. The value must be a
+// non-negative number.
+func (n *Node) Cost() (float64, error) {
+	raw, ok := n.Config[ConfigCost]
+	if !ok {
+		return 1, nil
+	}
+	cost, err := strconv.ParseFloat(raw, 64)
+	if err != nil || cost < 0 {
+		return 0, fmt.Errorf("node '%s' has invalid %s %q: must be a non-negative number", n.ID, ConfigCost, raw)
+	}
+	return cost, nil
+}
+
+// CriticalPath returns the heaviest chain of nodes from a root to a leaf,
+// weighing each node by its Cost: the serial bottleneck that bounds how
+// fast the graph can run however many workers it gets. Equal chains are
+// resolved by node ID so the result is stable. The graph is expected to
+// have passed Validate; a cycle is reported as an error.
+func (g *Graph) CriticalPath() ([]string, error) {
+	costs := make(map[string]float64, len(g.Nodes))
+	for i := range g.Nodes {
+		cost, err := g.Nodes[i].Cost()
+		if err != nil {
+			return nil, err
+		}
+		costs[g.Nodes[i].ID] = cost
+	}
+
+	adj := make(map[string][]string)
+	hasParent := make(map[string]bool)
+	for _, e := range g.Edges {
+		adj[e.From] = append(adj[e.From], e.To)
+		hasParent[e.To] = true
+	}
+	for _, children := range adj {
+		sort.Strings(children)
+	}
+
+	// Memoize the heaviest path from each node down to a leaf, remembering
+	// which child it continues through
+	memo := make(map[string]float64, len(g.Nodes))
+	next := make(map[string]string, len(g.Nodes))
+	visiting := make(map[string]bool)
+	weightFrom := func(root string) (float64, error) {
+		if w, ok := memo[root]; ok {
+			return w, nil
+		}
+		// As in hasCycle, walk with an explicit stack of frames so long
+		// chains can't exhaust the goroutine stack
+		type frame struct {
+			id   string
+			next int // Index of the next child to visit
+		}
+		visiting[root] = true
+		path := []frame{{id: root}}
+		for len(path) > 0 {
+			top := &path[len(path)-1]
+			children := adj[top.id]
+			if top.next < len(children) {
+				child := children[top.next]
+				top.next++
+				if visiting[child] {
+					return 0, fmt.Errorf("cycle detected at node '%s'", child)
+				}
+				if _, ok := memo[child]; !ok {
+					visiting[child] = true
+					path = append(path, frame{id: child})
+				}
+				continue
+			}
+
+			// Every child is weighed; pick the heaviest
+			best, bestChild := 0.0, ""
+			for _, child := range children {
+				if w := memo[child]; bestChild == "" || w > best {
+					best, bestChild = w, child
+				}
+			}
+			visiting[top.id] = false
+			memo[top.id] = costs[top.id] + best
+			next[top.id] = bestChild
+			path = path[:len(path)-1]
+		}
+		return memo[root], nil
+	}
+
+	roots := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		if !hasParent[n.ID] {
+			roots = append(roots, n.ID)
+		}
+	}
+	if len(roots) == 0 && len(g.Nodes) > 0 {
+		return nil, fmt.Errorf("graph has no root nodes")
+	}
+	sort.Strings(roots)
+
+	start, heaviest := "", 0.0
+	for _, root := range roots {
+		w, err := weightFrom(root)
+		if err != nil {
+			return nil, err
+		}
+		if start == "" || w > heaviest {
+			start, heaviest = root, w
+		}
+	}
+	if start == "" {
+		return nil, nil
+	}
+
+	var path []string
+	for id := start; id != ""; id = next[id] {
+		path = append(path, id)
+	}
+	return path, nil
+}
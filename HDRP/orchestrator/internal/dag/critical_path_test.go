@@ -0,0 +1,81 @@
+package dag
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCriticalPath(t *testing.T) {
+	// A -> B -> D and A -> C -> D, plus a separate root E -> F
+	edges := []Edge{{From: "A", To: "B"}, {From: "B", To: "D"}, {From: "A", To: "C"}, {From: "C", To: "D"}, {From: "E", To: "F"}}
+	nodes := func(costs map[string]string) []Node {
+		var out []Node
+		for _, id := range []string{"A", "B", "C", "D", "E", "F"} {
+			n := Node{ID: id, Type: "task", Config: map[string]string{}}
+			if cost, ok := costs[id]; ok {
+				n.Config[ConfigCost] = cost
+			}
+			out = append(out, n)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name  string
+		costs map[string]string
+		want  []string
+	}{
+		{name: "by node count, ties by ID", want: []string{"A", "B", "D"}},
+		{name: "by cost", costs: map[string]string{"C": "5"}, want: []string{"A", "C", "D"}},
+		{name: "heavier separate chain", costs: map[string]string{"F": "10.5"}, want: []string{"E", "F"}},
+	}
+	for _, tt := range tests {
+		g := &Graph{Nodes: nodes(tt.costs), Edges: edges}
+		got, err := g.CriticalPath()
+		if err != nil {
+			t.Fatalf("%s: CriticalPath failed: %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: CriticalPath = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCriticalPath_Errors(t *testing.T) {
+	g := &Graph{Nodes: []Node{{ID: "A", Type: "task", Config: map[string]string{ConfigCost: "-1"}}}}
+	if _, err := g.CriticalPath(); err == nil {
+		t.Error("Expected error for a negative cost")
+	}
+
+	g = &Graph{
+		Nodes: []Node{{ID: "R", Type: "task"}, {ID: "A", Type: "task"}, {ID: "B", Type: "task"}},
+		Edges: []Edge{{From: "R", To: "A"}, {From: "A", To: "B"}, {From: "B", To: "A"}},
+	}
+	if _, err := g.CriticalPath(); err == nil {
+		t.Error("Expected error for a cycle")
+	}
+
+	if path, err := (&Graph{}).CriticalPath(); err != nil || path != nil {
+		t.Errorf("Empty graph CriticalPath = %v, %v; want nil, nil", path, err)
+	}
+}
+
+func TestCriticalPath_LongChain(t *testing.T) {
+	const n = 10000
+	g := &Graph{Nodes: make([]Node, n)}
+	for i := range g.Nodes {
+		g.Nodes[i] = Node{ID: fmt.Sprintf("n%d", i), Type: "task"}
+		if i > 0 {
+			g.Edges = append(g.Edges, Edge{From: g.Nodes[i-1].ID, To: g.Nodes[i].ID})
+		}
+	}
+
+	path, err := g.CriticalPath()
+	if err != nil {
+		t.Fatalf("CriticalPath failed: %v", err)
+	}
+	if len(path) != n || path[0] != "n0" || path[n-1] != fmt.Sprintf("n%d", n-1) {
+		t.Errorf("Expected the whole chain, got %d nodes", len(path))
+	}
+}
@@ -0,0 +1,40 @@
+package dag
+
+import "strings"
+
+// MetadataConfigPrefix marks graph metadata entries that are default node
+// config. "config.model" = "m" gives every node Config["model"] = "m" unless
+// the node sets model itself.
+const MetadataConfigPrefix = "config."
+
+// ConfigDefaults returns the node config defaults declared in the graph's
+// metadata, keyed without MetadataConfigPrefix.
+func (g *Graph) ConfigDefaults() map[string]string {
+	defaults := make(map[string]string)
+	for k, v := range g.Metadata {
+		if key := strings.TrimPrefix(k, MetadataConfigPrefix); key != k && key != "" {
+			defaults[key] = v
+		}
+	}
+	return defaults
+}
+
+// ApplyConfigDefaults merges the graph's config defaults into each node's
+// Config. Keys a node already sets are left alone, so per-node config wins.
+func (g *Graph) ApplyConfigDefaults() {
+	defaults := g.ConfigDefaults()
+	if len(defaults) == 0 {
+		return
+	}
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		if n.Config == nil {
+			n.Config = make(map[string]string, len(defaults))
+		}
+		for k, v := range defaults {
+			if _, ok := n.Config[k]; !ok {
+				n.Config[k] = v
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyConfigDefaults(t *testing.T) {
+	g := &Graph{
+		ID: "defaults",
+		Metadata: map[string]string{
+			"goal":               "test",
+			"config.model":       "base-model",
+			"config.temperature": "0.2",
+			"config.locale":      "en-GB",
+		},
+		Nodes: []Node{
+			{ID: "r1", Type: "researcher", Config: map[string]string{"query": "q"}},
+			{ID: "r2", Type: "researcher", Config: map[string]string{"query": "q", "temperature": "0.9"}},
+			{ID: "s1", Type: "synthesizer"},
+		},
+	}
+
+	g.ApplyConfigDefaults()
+
+	want := map[string]map[string]string{
+		"r1": {"query": "q", "model": "base-model", "temperature": "0.2", "locale": "en-GB"},
+		"r2": {"query": "q", "model": "base-model", "temperature": "0.9", "locale": "en-GB"},
+		"s1": {"model": "base-model", "temperature": "0.2", "locale": "en-GB"},
+	}
+	for _, n := range g.Nodes {
+		if !reflect.DeepEqual(n.Config, want[n.ID]) {
+			t.Errorf("Node %s config = %v, want %v", n.ID, n.Config, want[n.ID])
+		}
+	}
+}
+
+func TestApplyConfigDefaults_NoDefaults(t *testing.T) {
+	g := &Graph{
+		Metadata: map[string]string{"goal": "test", "config.": "ignored"},
+		Nodes:    []Node{{ID: "n1", Type: "researcher"}},
+	}
+
+	g.ApplyConfigDefaults()
+
+	if g.Nodes[0].Config != nil {
+		t.Errorf("Expected config untouched, got %v", g.Nodes[0].Config)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"sync"
 
 	"hdrp/internal/storage"
 )
@@ -21,6 +23,7 @@ const (
 	StatusFailed    Status = "FAILED"
 	StatusRetrying  Status = "RETRYING"  // Node is waiting to retry after failure
 	StatusCancelled Status = "CANCELLED"
+	StatusSkipped   Status = "SKIPPED"   // Node never ran because a parent failed
 )
 
 // Node represents a step in the processing pipeline.
@@ -33,9 +36,12 @@ type Node struct {
 	Depth          int               `json:"depth"`
 	RetryCount     int               `json:"retry_count"`      // Number of retry attempts made
 	LastError      string            `json:"last_error,omitempty"` // Last error encountered
+	Attempt        int               `json:"attempt,omitempty"`    // Execution attempt in progress (1-based); 0 before the first
+	Phase          string            `json:"phase,omitempty"`      // Free-form sub-state for display; see SetNodePhase
 }
 
-// Validate ensures the node represents a single, atomic unit of work.
+// Validate ensures the node represents a single, atomic unit of work with
+// a usable configuration.
 func (n *Node) Validate() error {
 	forbiddenKeys := []string{"steps", "tasks", "pipeline", "subgraph", "batch"}
 
@@ -44,6 +50,9 @@ func (n *Node) Validate() error {
 			return fmt.Errorf("node '%s' violates atomicity: config key '%s' implies composite/non-atomic behavior", n.ID, forbidden)
 		}
 	}
+	if _, err := n.Timeout(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -51,6 +60,9 @@ func (n *Node) Validate() error {
 type Edge struct {
 	From string `json:"from"`
 	To   string `json:"to"`
+	// Condition gates To on From's outputs (see ParseCondition). Empty
+	// edges are unconditional.
+	Condition string `json:"condition,omitempty"`
 }
 
 // Signal represents an event or message that can trigger graph modifications.
@@ -67,9 +79,31 @@ type Graph struct {
 	Edges    []Edge            `json:"edges"`
 	Status   Status            `json:"status"`
 	Metadata map[string]string `json:"metadata"`
+
+	// Longest dependency chain Validate accepts, in layers; 0 means DefaultMaxDepth
+	MaxDepth int `json:"max_depth,omitempty"`
 	
 	// Storage backend for persistence (nil for in-memory only)
 	storage storage.Storage `json:"-"`
+
+	// Scheduling priority adjustment for deeper nodes (zero value disables)
+	depthBoost DepthBoost `json:"-"`
+
+	// Node depths for the depth boost, cached until the structure changes
+	depths map[string]int `json:"-"`
+
+	// What EvaluateReadiness does with nodes whose parents failed ("" means DefaultFailedParentPolicy)
+	failedParentPolicy FailedParentPolicy `json:"-"`
+
+	// Finished nodes' outputs, for edge conditions (see SetNodeOutputs)
+	outputs map[string]map[string]float64 `json:"-"`
+
+	// Nodes skipped because no incoming edge condition held, or downstream of one
+	untaken map[string]bool `json:"-"`
+
+	// Guards node state, which the executor's workers update while the
+	// scheduling loop reads it
+	mu sync.Mutex
 }
 
 // ValidationError represents an aggregation of validation issues.
@@ -84,6 +118,21 @@ func (v *ValidationError) Error() string {
 	return fmt.Sprintf("graph validation failed with %d errors: %v", len(v.Errors), v.Errors[0])
 }
 
+// DefaultMaxDepth is the layer limit for graphs that don't set MaxDepth.
+const DefaultMaxDepth = 3
+
+// MetadataMaxDepth is the graph metadata key that carries MaxDepth through
+// persistence, so a recovered graph is validated against its own limit.
+const MetadataMaxDepth = "max_depth"
+
+// EffectiveMaxDepth returns the layer limit Validate enforces.
+func (g *Graph) EffectiveMaxDepth() int {
+	if g.MaxDepth <= 0 {
+		return DefaultMaxDepth
+	}
+	return g.MaxDepth
+}
+
 // Validate performs structural and semantic validation on the Graph.
 // It ensures the graph is a valid DAG (Directed Acyclic Graph).
 func (g *Graph) Validate() error {
@@ -127,6 +176,11 @@ func (g *Graph) Validate() error {
 		if e.From == e.To {
 			errs = append(errs, fmt.Sprintf("self-loop detected on node '%s'", e.From))
 		}
+		if e.Condition != "" {
+			if _, err := ParseCondition(e.Condition); err != nil {
+				errs = append(errs, fmt.Sprintf("edge %s -> %s: %v", e.From, e.To, err))
+			}
+		}
 
 		// Build adjacency list only for valid nodes to avoid panic/issues later
 		if nodeMap[e.From] && nodeMap[e.To] {
@@ -144,9 +198,14 @@ func (g *Graph) Validate() error {
 	}
 
 	// 4. Max Depth Enforcement
-	// We limit the graph to 3 layers to prevent complex, uncontrollable chains in this MVP.
-	const MaxDepth = 3
-	if err := checkDepth(g.Nodes, adj, MaxDepth); err != nil {
+	// Graphs are limited to DefaultMaxDepth layers unless they opt into deeper
+	// pipelines, to prevent complex, uncontrollable chains.
+	if err := checkDepth(g.Nodes, adj, g.EffectiveMaxDepth()); err != nil {
+		return err
+	}
+
+	// 5. Review loops must point back to an ancestor
+	if err := g.validateLoopbacks(); err != nil {
 		return err
 	}
 
@@ -206,7 +265,9 @@ func (g *Graph) handleEntityDiscovery(sig Signal) error {
 		RelevanceScore: 1.0, // Placeholder
 		Depth:          sourceNode.Depth + 1,
 	}
-	g.Nodes = append(g.Nodes, newNode)
+	if !g.AddNodeIfAbsent(newNode) {
+		return nil // Already expanded
+	}
 
 	// Persist node
 	if err := g.persistNode(&newNode); err != nil {
@@ -233,22 +294,21 @@ func (g *Graph) handleEntityDiscovery(sig Signal) error {
 	}
 
 	// Add edge
-	newEdge := Edge{From: sig.Source, To: newNodeID}
-	g.Edges = append(g.Edges, newEdge)
-
-	// Persist edge
-	if err := g.persistEdge(sig.Source, newNodeID); err != nil {
-		return fmt.Errorf("failed to persist new edge: %w", err)
-	}
-
-	// Log to WAL
-	if g.storage != nil {
-		payload := &storage.AddEdgePayload{
-			From: sig.Source,
-			To:   newNodeID,
+	if g.AddEdgeIfAbsent(sig.Source, newNodeID) {
+		// Persist edge
+		if err := g.persistEdge(sig.Source, newNodeID); err != nil {
+			return fmt.Errorf("failed to persist new edge: %w", err)
 		}
-		if err := g.storage.LogMutation(g.ID, storage.MutationAddEdge, payload); err != nil {
-			log.Printf("[DAG] Warning: failed to log add edge mutation: %v", err)
+
+		// Log to WAL
+		if g.storage != nil {
+			payload := &storage.AddEdgePayload{
+				From: sig.Source,
+				To:   newNodeID,
+			}
+			if err := g.storage.LogMutation(g.ID, storage.MutationAddEdge, payload); err != nil {
+				log.Printf("[DAG] Warning: failed to log add edge mutation: %v", err)
+			}
 		}
 	}
 
@@ -275,6 +335,115 @@ func (g *Graph) findNode(id string) *Node {
 	return nil
 }
 
+// AddNodeIfAbsent appends node unless the graph already has a node with its
+// ID. Returns whether it was added. Persisting the node is up to the caller.
+func (g *Graph) AddNodeIfAbsent(node Node) bool {
+	if g.findNode(node.ID) != nil {
+		return false
+	}
+	g.Nodes = append(g.Nodes, node)
+	g.invalidateDepths()
+	return true
+}
+
+// AddEdgeIfAbsent appends the edge from -> to unless the graph already has
+// it. Returns whether it was added. Persisting the edge is up to the caller.
+func (g *Graph) AddEdgeIfAbsent(from, to string) bool {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return false
+		}
+	}
+	g.Edges = append(g.Edges, Edge{From: from, To: to})
+	g.invalidateDepths()
+	return true
+}
+
+// RemoveNode removes a node and every edge touching it, in memory and in
+// storage. Running nodes can't be removed. If the graph would no longer
+// validate without the node, it is left unchanged and the validation error
+// is returned.
+func (g *Graph) RemoveNode(id string) error {
+	node := g.findNode(id)
+	if node == nil {
+		return fmt.Errorf("node '%s' not found", id)
+	}
+	if node.Status == StatusRunning {
+		return fmt.Errorf("cannot remove node '%s' while it is running", id)
+	}
+
+	nodes := make([]Node, 0, len(g.Nodes)-1)
+	for _, n := range g.Nodes {
+		if n.ID != id {
+			nodes = append(nodes, n)
+		}
+	}
+	edges := make([]Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		if e.From != id && e.To != id {
+			edges = append(edges, e)
+		}
+	}
+	if err := g.replaceStructure(nodes, edges); err != nil {
+		return fmt.Errorf("removing node '%s' leaves an invalid graph: %w", id, err)
+	}
+
+	if g.storage == nil {
+		return nil
+	}
+	if err := g.storage.DeleteNode(g.ID, id); err != nil {
+		return fmt.Errorf("failed to delete node from storage: %w", err)
+	}
+	payload := &storage.RemoveNodePayload{NodeID: id}
+	if err := g.storage.LogMutation(g.ID, storage.MutationRemoveNode, payload); err != nil {
+		log.Printf("[DAG] Warning: failed to log remove node mutation: %v", err)
+	}
+	return nil
+}
+
+// RemoveEdge removes the edge from -> to, in memory and in storage. If the
+// graph would no longer validate without it, it is left unchanged and the
+// validation error is returned.
+func (g *Graph) RemoveEdge(from, to string) error {
+	edges := make([]Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		if e.From != from || e.To != to {
+			edges = append(edges, e)
+		}
+	}
+	if len(edges) == len(g.Edges) {
+		return fmt.Errorf("edge '%s' -> '%s' not found", from, to)
+	}
+	if err := g.replaceStructure(g.Nodes, edges); err != nil {
+		return fmt.Errorf("removing edge '%s' -> '%s' leaves an invalid graph: %w", from, to, err)
+	}
+
+	if g.storage == nil {
+		return nil
+	}
+	if err := g.storage.DeleteEdge(g.ID, from, to); err != nil {
+		return fmt.Errorf("failed to delete edge from storage: %w", err)
+	}
+	payload := &storage.RemoveEdgePayload{From: from, To: to}
+	if err := g.storage.LogMutation(g.ID, storage.MutationRemoveEdge, payload); err != nil {
+		log.Printf("[DAG] Warning: failed to log remove edge mutation: %v", err)
+	}
+	return nil
+}
+
+// replaceStructure swaps in new nodes and edges, restoring the old ones if
+// the result doesn't validate.
+func (g *Graph) replaceStructure(nodes []Node, edges []Edge) error {
+	oldNodes, oldEdges := g.Nodes, g.Edges
+	g.Nodes, g.Edges = nodes, edges
+	if err := g.Validate(); err != nil {
+		g.Nodes, g.Edges = oldNodes, oldEdges
+		return err
+	}
+	g.invalidateDepths()
+	return nil
+}
+
 func checkCycles(nodes []Node, adj map[string][]string) error {
 	visited := make(map[string]bool)
 	recursionStack := make(map[string]bool)
@@ -290,22 +459,40 @@ func checkCycles(nodes []Node, adj map[string][]string) error {
 	return nil
 }
 
+// hasCycle runs a depth-first search from nodeID, reporting whether it reaches
+// a node still on the current path. It keeps an explicit stack of frames rather
+// than recursing, so arbitrarily long chains can't exhaust the goroutine stack.
 func hasCycle(nodeID string, adj map[string][]string, visited, stack map[string]bool) bool {
+	type frame struct {
+		id   string
+		next int // Index of the next neighbor to visit
+	}
+
 	visited[nodeID] = true
 	stack[nodeID] = true
+	path := []frame{{id: nodeID}}
+
+	for len(path) > 0 {
+		top := &path[len(path)-1]
+		neighbors := adj[top.id]
+		if top.next == len(neighbors) {
+			// All neighbors explored, leave the current path
+			stack[top.id] = false
+			path = path[:len(path)-1]
+			continue
+		}
 
-	for _, neighbor := range adj[nodeID] {
+		neighbor := neighbors[top.next]
+		top.next++
 		if !visited[neighbor] {
-			if hasCycle(neighbor, adj, visited, stack) {
-				return true
-			}
+			visited[neighbor] = true
+			stack[neighbor] = true
+			path = append(path, frame{id: neighbor})
 		} else if stack[neighbor] {
-			// If neighbor is in the current recursion stack, we found a cycle
+			// If neighbor is on the current path, we found a cycle
 			return true
 		}
 	}
-
-	stack[nodeID] = false
 	return false
 }
 
@@ -389,6 +576,7 @@ func (g *Graph) persistNode(node *Node) error {
 		Depth:          node.Depth,
 		RetryCount:     node.RetryCount,
 		LastError:      node.LastError,
+		Phase:          node.Phase,
 	}
 
 	return g.storage.SaveNode(g.ID, nodeState)
@@ -423,6 +611,11 @@ func (g *Graph) LoadFromStorage(graphID string) error {
 	g.ID = recovered.Graph.ID
 	g.Status = Status(recovered.Graph.Status)
 	g.Metadata = recovered.Graph.Metadata
+	if raw, ok := g.Metadata[MetadataMaxDepth]; ok {
+		if depth, err := strconv.Atoi(raw); err == nil {
+			g.MaxDepth = depth
+		}
+	}
 
 	// Restore nodes
 	g.Nodes = make([]Node, 0, len(recovered.Nodes))
@@ -436,6 +629,7 @@ func (g *Graph) LoadFromStorage(graphID string) error {
 			Depth:          nodeState.Depth,
 			RetryCount:     nodeState.RetryCount,
 			LastError:      nodeState.LastError,
+			Phase:          nodeState.Phase,
 		})
 	}
 
@@ -443,10 +637,12 @@ func (g *Graph) LoadFromStorage(graphID string) error {
 	g.Edges = make([]Edge, 0, len(recovered.Edges))
 	for _, edgeState := range recovered.Edges {
 		g.Edges = append(g.Edges, Edge{
-			From: edgeState.From,
-			To:   edgeState.To,
+			From:      edgeState.From,
+			To:        edgeState.To,
+			Condition: edgeState.Condition,
 		})
 	}
+	g.invalidateDepths()
 
 	return nil
 }
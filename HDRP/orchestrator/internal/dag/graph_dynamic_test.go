@@ -204,3 +204,33 @@ func TestGraph_ReceiveSignal(t *testing.T) {
 		}
 	})
 }
+
+func TestGraph_AddIfAbsent(t *testing.T) {
+	g := &Graph{ID: "g1", Nodes: []Node{{ID: "root", Type: "researcher"}}}
+
+	if !g.AddNodeIfAbsent(Node{ID: "child", Type: "agent"}) {
+		t.Fatal("Expected the first add of child to report true")
+	}
+	if g.AddNodeIfAbsent(Node{ID: "child", Type: "critic"}) {
+		t.Error("Expected a repeated add of child to report false")
+	}
+	if g.AddNodeIfAbsent(Node{ID: "root", Type: "agent"}) {
+		t.Error("Expected adding an existing node to report false")
+	}
+	if len(g.Nodes) != 2 || g.Nodes[1].Type != "agent" {
+		t.Errorf("Nodes after repeated adds = %+v", g.Nodes)
+	}
+
+	if !g.AddEdgeIfAbsent("root", "child") {
+		t.Fatal("Expected the first add of root->child to report true")
+	}
+	if g.AddEdgeIfAbsent("root", "child") {
+		t.Error("Expected a repeated add of root->child to report false")
+	}
+	if !g.AddEdgeIfAbsent("child", "root") {
+		t.Error("Expected the reverse edge to be added")
+	}
+	if len(g.Edges) != 2 {
+		t.Errorf("Edges after repeated adds = %+v", g.Edges)
+	}
+}
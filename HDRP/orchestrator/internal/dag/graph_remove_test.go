@@ -0,0 +1,127 @@
+package dag
+
+import (
+	"strings"
+	"testing"
+
+	"hdrp/internal/storage"
+)
+
+// newRemovalTestGraph builds A -> B -> C plus A -> C, persisted and logged
+// to the WAL as if it had been created through storage.
+func newRemovalTestGraph(t *testing.T) (*Graph, *storage.InMemoryStorage) {
+	t.Helper()
+	store := storage.NewInMemoryStorage()
+	g := NewGraphWithStorage("remove-graph", store)
+	g.Nodes = []Node{
+		{ID: "A", Type: "task", Status: StatusSucceeded},
+		{ID: "B", Type: "task", Status: StatusPending},
+		{ID: "C", Type: "task", Status: StatusCreated},
+	}
+	g.Edges = []Edge{{From: "A", To: "B"}, {From: "B", To: "C"}, {From: "A", To: "C"}}
+
+	if err := store.SaveGraph(&storage.GraphState{ID: g.ID, Status: string(g.Status)}); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	for _, n := range g.Nodes {
+		state := storage.NodeState{NodeID: n.ID, Type: n.Type, Status: string(n.Status)}
+		store.SaveNode(g.ID, &state)
+		store.LogMutation(g.ID, storage.MutationAddNode, &storage.AddNodePayload{Node: state})
+	}
+	for _, e := range g.Edges {
+		store.SaveEdge(g.ID, e.From, e.To)
+		store.LogMutation(g.ID, storage.MutationAddEdge, &storage.AddEdgePayload{From: e.From, To: e.To})
+	}
+	return g, store
+}
+
+func TestRemoveNode(t *testing.T) {
+	g, store := newRemovalTestGraph(t)
+
+	if err := g.RemoveNode("B"); err != nil {
+		t.Fatalf("RemoveNode failed: %v", err)
+	}
+	if len(g.Nodes) != 2 || g.findNode("B") != nil {
+		t.Errorf("Nodes = %+v, want A and C", g.Nodes)
+	}
+	if len(g.Edges) != 1 || g.Edges[0] != (Edge{From: "A", To: "C"}) {
+		t.Errorf("Edges = %+v, want only A -> C", g.Edges)
+	}
+
+	nodes, _ := store.LoadNodes(g.ID)
+	edges, _ := store.LoadEdges(g.ID)
+	if len(nodes) != 2 || len(edges) != 1 {
+		t.Errorf("Stored %d nodes and %d edges, want 2 and 1", len(nodes), len(edges))
+	}
+
+	// Replaying the WAL reaches the same structure
+	recovered, err := store.RecoverGraph(g.ID)
+	if err != nil {
+		t.Fatalf("RecoverGraph failed: %v", err)
+	}
+	if _, ok := recovered.Nodes["B"]; ok || len(recovered.Nodes) != 2 {
+		t.Errorf("Recovered nodes = %v, want A and C", recovered.Nodes)
+	}
+	if len(recovered.Edges) != 1 || recovered.Edges[0].From != "A" || recovered.Edges[0].To != "C" {
+		t.Errorf("Recovered %d edges, want only A -> C", len(recovered.Edges))
+	}
+}
+
+func TestRemoveNode_Rejected(t *testing.T) {
+	g, _ := newRemovalTestGraph(t)
+	g.Nodes[1].Status = StatusRunning
+
+	err := g.RemoveNode("B")
+	if err == nil || !strings.Contains(err.Error(), "running") {
+		t.Errorf("Expected running node to be rejected, got %v", err)
+	}
+	if err := g.RemoveNode("missing"); err == nil {
+		t.Error("Expected error removing unknown node")
+	}
+	if len(g.Nodes) != 3 || len(g.Edges) != 3 {
+		t.Errorf("Graph changed after rejected removals: %d nodes, %d edges", len(g.Nodes), len(g.Edges))
+	}
+}
+
+func TestRemoveNode_InvalidResultRestoresGraph(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	g := NewGraphWithStorage("remove-last", store)
+	g.Nodes = []Node{{ID: "A", Type: "task", Status: StatusCreated}}
+	store.SaveNode(g.ID, &storage.NodeState{NodeID: "A", Type: "task", Status: string(StatusCreated)})
+
+	err := g.RemoveNode("A")
+	if err == nil || !strings.Contains(err.Error(), "leaves an invalid graph") {
+		t.Fatalf("Expected invalid graph error, got %v", err)
+	}
+	if len(g.Nodes) != 1 {
+		t.Errorf("Expected node to be restored, got %+v", g.Nodes)
+	}
+	if nodes, _ := store.LoadNodes(g.ID); len(nodes) != 1 {
+		t.Errorf("Expected stored node to be kept, got %d", len(nodes))
+	}
+}
+
+func TestRemoveEdge(t *testing.T) {
+	g, store := newRemovalTestGraph(t)
+
+	if err := g.RemoveEdge("A", "C"); err != nil {
+		t.Fatalf("RemoveEdge failed: %v", err)
+	}
+	if len(g.Edges) != 2 {
+		t.Errorf("Edges = %+v, want A -> B and B -> C", g.Edges)
+	}
+	if err := g.RemoveEdge("A", "C"); err == nil {
+		t.Error("Expected error removing an edge twice")
+	}
+
+	recovered, err := store.RecoverGraph(g.ID)
+	if err != nil {
+		t.Fatalf("RecoverGraph failed: %v", err)
+	}
+	if len(recovered.Edges) != 2 || len(recovered.Nodes) != 3 {
+		t.Errorf("Recovered %d nodes and %d edges, want 3 and 2", len(recovered.Nodes), len(recovered.Edges))
+	}
+	if edges, _ := store.LoadEdges(g.ID); len(edges) != 2 {
+		t.Errorf("Stored %d edges, want 2", len(edges))
+	}
+}
@@ -1,6 +1,8 @@
 package dag
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -126,6 +128,38 @@ func TestGraph_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "Configured Max Depth Allows 4 Layers",
+			graph: Graph{
+				MaxDepth: 4,
+				Nodes: []Node{
+					{ID: "A", Type: "task"},
+					{ID: "B", Type: "task"},
+					{ID: "C", Type: "task"},
+					{ID: "D", Type: "task"},
+				},
+				Edges: []Edge{
+					{From: "A", To: "B"},
+					{From: "B", To: "C"},
+					{From: "C", To: "D"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Configured Max Depth Below Default",
+			graph: Graph{
+				MaxDepth: 1,
+				Nodes: []Node{
+					{ID: "A", Type: "task"},
+					{ID: "B", Type: "task"},
+				},
+				Edges: []Edge{
+					{From: "A", To: "B"},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "Non-Atomic Node Config (Hidden Subgraph)",
 			graph: Graph{
@@ -143,7 +177,8 @@ func TestGraph_Validate(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
+	for i := range tests {
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			if err := tt.graph.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Graph.Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -151,3 +186,54 @@ func TestGraph_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestGraph_Validate_MaxDepthError(t *testing.T) {
+	chain := func(n int) *Graph {
+		g := &Graph{}
+		for i := 0; i < n; i++ {
+			id := string(rune('A' + i))
+			g.Nodes = append(g.Nodes, Node{ID: id, Type: "task"})
+			if i > 0 {
+				g.Edges = append(g.Edges, Edge{From: string(rune('A' + i - 1)), To: id})
+			}
+		}
+		return g
+	}
+
+	g := chain(4)
+	if err := g.Validate(); err == nil || !strings.Contains(err.Error(), "max depth of 3 layers") {
+		t.Errorf("Expected default depth error, got %v", err)
+	}
+
+	g = chain(6)
+	g.MaxDepth = 5
+	if err := g.Validate(); err == nil || !strings.Contains(err.Error(), "max depth of 5 layers") {
+		t.Errorf("Expected configured depth error, got %v", err)
+	}
+}
+
+func TestCheckCycles_LongChain(t *testing.T) {
+	const n = 10000
+	nodes := make([]Node, n)
+	adj := make(map[string][]string, n)
+	for i := range nodes {
+		nodes[i] = Node{ID: fmt.Sprintf("n%d", i), Type: "task"}
+		if i > 0 {
+			adj[nodes[i-1].ID] = []string{nodes[i].ID}
+		}
+	}
+
+	if err := checkCycles(nodes, adj); err != nil {
+		t.Fatalf("Expected no cycle in a linear chain, got %v", err)
+	}
+
+	// Close the chain with a back-edge from the last node to the middle
+	adj[nodes[n-1].ID] = []string{nodes[n/2].ID}
+	err := checkCycles(nodes, adj)
+	if err == nil {
+		t.Fatal("Expected the back-edge to be detected as a cycle")
+	}
+	if want := "cycle detected starting at or involving node 'n0'"; err.Error() != want {
+		t.Errorf("Error = %q, want %q", err, want)
+	}
+}
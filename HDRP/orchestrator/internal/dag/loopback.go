@@ -0,0 +1,126 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Node config keys declaring a review loop. A node (typically a critic) with
+// ConfigLoopbackTo set loops back to that ancestor when it rejects its input:
+// the ancestor and everything between it and the node run again, then the
+// node reviews the refined output. ConfigMaxIterations bounds how many extra
+// iterations the loop may take.
+const (
+	ConfigLoopbackTo    = "loopback_to"
+	ConfigMaxIterations = "max_iterations"
+)
+
+// DefaultMaxIterations bounds a review loop that doesn't set max_iterations.
+const DefaultMaxIterations = 2
+
+// Loopback is a review loop from a node back to one of its ancestors.
+type Loopback struct {
+	From          string // Reviewing node whose rejection activates the loop
+	To            string // Ancestor that runs again
+	MaxIterations int    // Extra iterations allowed per run
+}
+
+// Loopback returns the review loop declared on nodeID, if any.
+func (g *Graph) Loopback(nodeID string) (Loopback, bool, error) {
+	n := g.findNode(nodeID)
+	if n == nil {
+		return Loopback{}, false, fmt.Errorf("node %s not found in graph", nodeID)
+	}
+	target, ok := n.Config[ConfigLoopbackTo]
+	if !ok || target == "" {
+		return Loopback{}, false, nil
+	}
+
+	lb := Loopback{From: nodeID, To: target, MaxIterations: DefaultMaxIterations}
+	if raw, ok := n.Config[ConfigMaxIterations]; ok {
+		max, err := strconv.Atoi(raw)
+		if err != nil || max < 1 {
+			return Loopback{}, false, fmt.Errorf("node %s has invalid %s %q: must be a positive integer", nodeID, ConfigMaxIterations, raw)
+		}
+		lb.MaxIterations = max
+	}
+	return lb, true, nil
+}
+
+// LoopBody returns the nodes a loop re-runs: its target and every node on a
+// path from the target to the reviewing node, excluding the reviewer, in
+// dependency order.
+func (g *Graph) LoopBody(lb Loopback) []string {
+	children := make(map[string][]string)
+	parents := make(map[string][]string)
+	for _, e := range g.Edges {
+		children[e.From] = append(children[e.From], e.To)
+		parents[e.To] = append(parents[e.To], e.From)
+	}
+
+	// Nodes on a target->reviewer path are both descendants of the target
+	// and ancestors of the reviewer
+	below := reachable(lb.To, children)
+	above := reachable(lb.From, parents)
+
+	depths := g.NodeDepths()
+	var body []string
+	for id := range below {
+		if above[id] && id != lb.From {
+			body = append(body, id)
+		}
+	}
+	sort.Slice(body, func(i, j int) bool {
+		if depths[body[i]] != depths[body[j]] {
+			return depths[body[i]] < depths[body[j]]
+		}
+		return body[i] < body[j]
+	})
+	return body
+}
+
+// validateLoopbacks checks that every declared loop targets an ancestor of
+// its reviewing node and has a usable iteration bound.
+func (g *Graph) validateLoopbacks() error {
+	parents := make(map[string][]string)
+	for _, e := range g.Edges {
+		parents[e.To] = append(parents[e.To], e.From)
+	}
+
+	var errs []string
+	for _, n := range g.Nodes {
+		lb, ok, err := g.Loopback(n.ID)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if lb.To == lb.From || !reachable(lb.From, parents)[lb.To] {
+			errs = append(errs, fmt.Sprintf("node %s loops back to %s, which is not one of its ancestors", lb.From, lb.To))
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// reachable returns the nodes reachable from start through adj, including start.
+func reachable(start string, adj map[string][]string) map[string]bool {
+	seen := map[string]bool{start: true}
+	stack := []string{start}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range adj[id] {
+			if !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return seen
+}
@@ -0,0 +1,69 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func loopGraph(target, maxIterations string) *Graph {
+	config := map[string]string{ConfigLoopbackTo: target}
+	if maxIterations != "" {
+		config[ConfigMaxIterations] = maxIterations
+	}
+	return &Graph{
+		ID: "loop",
+		Nodes: []Node{
+			{ID: "r", Type: "researcher"},
+			{ID: "x", Type: "extractor"},
+			{ID: "other", Type: "researcher"},
+			{ID: "c", Type: "critic", Config: config},
+		},
+		Edges: []Edge{
+			{From: "r", To: "x"},
+			{From: "x", To: "c"},
+			{From: "other", To: "c"},
+		},
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	g := loopGraph("r", "")
+	if err := g.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	lb, ok, err := g.Loopback("c")
+	if err != nil || !ok {
+		t.Fatalf("Loopback() = %v, %v", ok, err)
+	}
+	if lb.To != "r" || lb.MaxIterations != DefaultMaxIterations {
+		t.Errorf("Unexpected loopback: %+v", lb)
+	}
+	if body := g.LoopBody(lb); !reflect.DeepEqual(body, []string{"r", "x"}) {
+		t.Errorf("LoopBody() = %v, want [r x]", body)
+	}
+
+	if _, ok, _ := g.Loopback("r"); ok {
+		t.Error("Node without loopback_to reported a loop")
+	}
+}
+
+func TestLoopback_Validation(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		maxIterations string
+	}{
+		{name: "not an ancestor", target: "missing"},
+		{name: "self", target: "c"},
+		{name: "zero iterations", target: "r", maxIterations: "0"},
+		{name: "non-numeric iterations", target: "r", maxIterations: "many"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := loopGraph(tt.target, tt.maxIterations).Validate(); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
@@ -10,6 +10,90 @@ var (
 	ErrNodeAlreadyRunning = errors.New("scheduler violation: a node is already in RUNNING state")
 )
 
+// DepthBoost raises the scheduling priority of nodes further from the
+// graph's roots, so aggregating stages (critics, synthesizers) run promptly
+// once ready instead of queueing behind high-relevance researchers.
+type DepthBoost struct {
+	PerLevel float64 // Added to a node's priority per level of depth; <= 0 disables
+	Max      float64 // Cap on the total boost; <= 0 means uncapped
+}
+
+// SetDepthBoost configures the depth-aware priority adjustment used by
+// ScheduleNextBatch. It does not change stored relevance scores.
+func (g *Graph) SetDepthBoost(boost DepthBoost) {
+	g.depthBoost = boost
+}
+
+// boost returns the priority added to a node at the given depth.
+func (b DepthBoost) boost(depth int) float64 {
+	if b.PerLevel <= 0 || depth <= 0 {
+		return 0
+	}
+	v := b.PerLevel * float64(depth)
+	if b.Max > 0 && v > b.Max {
+		v = b.Max
+	}
+	return v
+}
+
+// NodeDepths returns each node's depth: the length of the longest path
+// from a root (a node without parents) to it. Depths are relaxed along a
+// topological order rather than by recursion, so long chains can't exhaust
+// the goroutine stack. Nodes on a cycle, which Validate rejects, keep the
+// depth reached from their acyclic ancestors.
+func (g *Graph) NodeDepths() map[string]int {
+	children := make(map[string][]string)
+	inDegree := make(map[string]int, len(g.Nodes))
+	for _, n := range g.Nodes {
+		inDegree[n.ID] = 0
+	}
+	for _, e := range g.Edges {
+		children[e.From] = append(children[e.From], e.To)
+		inDegree[e.To]++
+		if _, ok := inDegree[e.From]; !ok {
+			inDegree[e.From] = 0
+		}
+	}
+
+	depths := make(map[string]int, len(inDegree))
+	var ready []string
+	for id, d := range inDegree {
+		depths[id] = 0
+		if d == 0 {
+			ready = append(ready, id)
+		}
+	}
+	for len(ready) > 0 {
+		id := ready[len(ready)-1]
+		ready = ready[:len(ready)-1]
+		for _, child := range children[id] {
+			if d := depths[id] + 1; d > depths[child] {
+				depths[child] = d
+			}
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	return depths
+}
+
+// scheduleDepths returns the node depths used for the depth boost. They are
+// computed once and reused until the graph's structure changes (see
+// invalidateDepths). Callers hold g.mu.
+func (g *Graph) scheduleDepths() map[string]int {
+	if g.depths == nil {
+		g.depths = g.NodeDepths()
+	}
+	return g.depths
+}
+
+// invalidateDepths drops the cached node depths after nodes or edges change.
+func (g *Graph) invalidateDepths() {
+	g.depths = nil
+}
+
 // ScheduleNext acts as a compatibility wrapper for the legacy serial scheduler.
 // It selects exactly one node from the PENDING pool to transition to RUNNING.
 // For parallel execution, use ScheduleNextBatch instead.
@@ -33,7 +117,8 @@ func (g *Graph) ScheduleNext() (*Node, error) {
 //
 // Selection Policy:
 // 1. Only selects PENDING nodes (not BLOCKED or already RUNNING)
-// 2. Sorts by RelevanceScore (descending) then ID (ascending) for determinism
+// 2. Sorts by RelevanceScore plus any depth boost (descending) then ID
+//    (ascending) for determinism
 // 3. Returns up to maxNodes, or fewer if not enough eligible nodes exist
 // 4. Transitions selected nodes to RUNNING state atomically
 //
@@ -41,6 +126,9 @@ func (g *Graph) ScheduleNext() (*Node, error) {
 // - A slice of nodes ready for execution (may be empty)
 // - An error if state transition fails
 func (g *Graph) ScheduleNextBatch(maxNodes int) ([]*Node, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
 	if maxNodes <= 0 {
 		maxNodes = 1
 	}
@@ -58,11 +146,21 @@ func (g *Graph) ScheduleNextBatch(maxNodes int) ([]*Node, error) {
 	}
 
 	// 2. Apply Selection Policy
+	priority := make(map[string]float64, len(candidates))
+	var depths map[string]int
+	if g.depthBoost.PerLevel > 0 {
+		depths = g.scheduleDepths()
+	}
+	for _, c := range candidates {
+		priority[c.ID] = c.RelevanceScore + g.depthBoost.boost(depths[c.ID])
+	}
+
 	// Sort stability is crucial for deterministic replayability.
 	sort.Slice(candidates, func(i, j int) bool {
-		// Primary: High relevance first
-		if candidates[i].RelevanceScore != candidates[j].RelevanceScore {
-			return candidates[i].RelevanceScore > candidates[j].RelevanceScore
+		// Primary: High relevance (plus depth boost) first
+		pi, pj := priority[candidates[i].ID], priority[candidates[j].ID]
+		if pi != pj {
+			return pi > pj
 		}
 		// Secondary: Lexicographical ID for determinism
 		return candidates[i].ID < candidates[j].ID
@@ -80,10 +178,13 @@ func (g *Graph) ScheduleNextBatch(maxNodes int) ([]*Node, error) {
 	// Transition all selected nodes to RUNNING state
 	var transitioned []*Node
 	for _, node := range selected {
-		if err := g.SetNodeStatus(node.ID, StatusRunning); err != nil {
+		if node.Attempt == 0 {
+			node.Attempt = 1
+		}
+		if err := g.setNodeStatus(node.ID, StatusRunning); err != nil {
 			// Rollback: Set already-transitioned nodes back to PENDING
 			for _, rollbackNode := range transitioned {
-				_ = g.SetNodeStatus(rollbackNode.ID, StatusPending)
+				_ = g.setNodeStatus(rollbackNode.ID, StatusPending)
 			}
 			return nil, fmt.Errorf("failed to transition scheduled node %s: %w", node.ID, err)
 		}
@@ -96,6 +197,8 @@ func (g *Graph) ScheduleNextBatch(maxNodes int) ([]*Node, error) {
 // GetReadyNodesCount returns the number of nodes currently in PENDING state.
 // This is useful for determining how many nodes can be scheduled.
 func (g *Graph) GetReadyNodesCount() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	count := 0
 	for i := range g.Nodes {
 		if g.Nodes[i].Status == StatusPending {
@@ -105,8 +208,18 @@ func (g *Graph) GetReadyNodesCount() int {
 	return count
 }
 
+// NodeSnapshot returns a copy of the graph's nodes, safe to read while the
+// executor updates them.
+func (g *Graph) NodeSnapshot() []Node {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Node(nil), g.Nodes...)
+}
+
 // GetRunningNodesCount returns the number of nodes currently in RUNNING state.
 func (g *Graph) GetRunningNodesCount() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
 	count := 0
 	for i := range g.Nodes {
 		if g.Nodes[i].Status == StatusRunning {
@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -205,3 +206,90 @@ func BenchmarkScheduling(b *testing.B) {
 		}
 	})
 }
+
+func TestScheduleNextBatch_DepthBoost(t *testing.T) {
+	// r1 -> c1 -> s1 has finished up to the synthesizer; r2 is an unrelated
+	// high-relevance researcher that is also ready.
+	newGraph := func() *Graph {
+		return &Graph{
+			Nodes: []Node{
+				{ID: "r1", Type: "researcher", Status: StatusSucceeded, RelevanceScore: 0.9},
+				{ID: "c1", Type: "critic", Status: StatusSucceeded, RelevanceScore: 0.5},
+				{ID: "s1", Type: "synthesizer", Status: StatusPending, RelevanceScore: 0.4},
+				{ID: "r2", Type: "researcher", Status: StatusPending, RelevanceScore: 0.9},
+			},
+			Edges: []Edge{{From: "r1", To: "c1"}, {From: "c1", To: "s1"}},
+		}
+	}
+
+	if depths := newGraph().NodeDepths(); depths["s1"] != 2 || depths["r2"] != 0 {
+		t.Fatalf("Unexpected depths: %v", depths)
+	}
+
+	tests := []struct {
+		name  string
+		boost DepthBoost
+		want  string
+	}{
+		{name: "No boost", want: "r2"},
+		{name: "Boost lifts ready synthesizer", boost: DepthBoost{PerLevel: 0.3, Max: 0.6}, want: "s1"},
+		{name: "Cap limits boost", boost: DepthBoost{PerLevel: 0.3, Max: 0.4}, want: "r2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newGraph()
+			g.SetDepthBoost(tt.boost)
+
+			batch, err := g.ScheduleNextBatch(1)
+			if err != nil {
+				t.Fatalf("ScheduleNextBatch failed: %v", err)
+			}
+			if len(batch) != 1 || batch[0].ID != tt.want {
+				t.Fatalf("Expected %s to be scheduled first, got %v", tt.want, batch)
+			}
+			if g.Nodes[2].RelevanceScore != 0.4 {
+				t.Errorf("Depth boost must not change stored relevance, got %v", g.Nodes[2].RelevanceScore)
+			}
+		})
+	}
+}
+
+func TestNodeDepths_LongChain(t *testing.T) {
+	const n = 10000
+	g := &Graph{Nodes: make([]Node, n)}
+	for i := range g.Nodes {
+		g.Nodes[i] = Node{ID: fmt.Sprintf("n%d", i), Type: "task"}
+		if i > 0 {
+			g.Edges = append(g.Edges, Edge{From: g.Nodes[i-1].ID, To: g.Nodes[i].ID})
+		}
+	}
+
+	depths := g.NodeDepths()
+	if depths["n0"] != 0 || depths[fmt.Sprintf("n%d", n-1)] != n-1 {
+		t.Errorf("Unexpected chain depths: n0=%d, last=%d", depths["n0"], depths[fmt.Sprintf("n%d", n-1)])
+	}
+}
+
+func TestScheduleDepths_InvalidatedOnStructureChange(t *testing.T) {
+	g := &Graph{
+		Nodes: []Node{{ID: "a", Type: "task"}, {ID: "b", Type: "task"}},
+		Edges: []Edge{{From: "a", To: "b"}},
+	}
+	if depths := g.scheduleDepths(); depths["b"] != 1 {
+		t.Fatalf("Expected b at depth 1, got %v", depths)
+	}
+
+	g.AddNodeIfAbsent(Node{ID: "c", Type: "task"})
+	g.AddEdgeIfAbsent("b", "c")
+	if depths := g.scheduleDepths(); depths["c"] != 2 {
+		t.Errorf("Expected cached depths to be recomputed with c at depth 2, got %v", depths)
+	}
+
+	if err := g.RemoveEdge("b", "c"); err != nil {
+		t.Fatalf("RemoveEdge failed: %v", err)
+	}
+	if depths := g.scheduleDepths(); depths["c"] != 0 {
+		t.Errorf("Expected c back at depth 0 after removing its edge, got %v", depths)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // LoadJSON decodes a JSON-encoded DAG from the reader and validates it.
@@ -57,3 +58,72 @@ func WriteJSON(w io.Writer, g *Graph) error {
 
 	return nil
 }
+
+// dotStatusColors are the fill colors WriteDOT uses per node status.
+// Statuses not listed (including unset) are drawn white.
+var dotStatusColors = map[Status]string{
+	StatusPending:   "lightyellow",
+	StatusRunning:   "lightblue",
+	StatusRetrying:  "orange",
+	StatusSucceeded: "palegreen",
+	StatusFailed:    "salmon",
+	StatusBlocked:   "lightgrey",
+	StatusCancelled: "grey",
+	StatusSkipped:   "grey",
+}
+
+// WriteDOT renders the DAG as a Graphviz digraph: one vertex per node,
+// labelled with its ID, type and status and filled by status, and one arrow
+// per edge, dashed and labelled with its condition if it has one. Like
+// WriteJSON, it validates the graph first.
+//
+// Example:
+//
+//	go run ./cmd/planner -query "..." -dot | dot -Tpng -o plan.png
+func WriteDOT(w io.Writer, g *Graph) error {
+	if w == nil {
+		return fmt.Errorf("writer cannot be nil")
+	}
+	if g == nil {
+		return fmt.Errorf("graph cannot be nil")
+	}
+
+	if err := g.Validate(); err != nil {
+		return fmt.Errorf("cannot render invalid graph: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.ID))
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white];\n")
+	for _, n := range g.Nodes {
+		label := n.ID + "\n" + n.Type
+		if n.Status != "" {
+			label += "\n" + string(n.Status)
+		}
+		fmt.Fprintf(&b, "  %s [label=%s", dotQuote(n.ID), dotQuote(label))
+		if color, ok := dotStatusColors[n.Status]; ok {
+			fmt.Fprintf(&b, ", fillcolor=%s", color)
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		if e.Condition != "" {
+			fmt.Fprintf(&b, "  %s -> %s [label=%s, style=dashed];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Condition))
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	b.WriteString("}\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write graph DOT: %w", err)
+	}
+	return nil
+}
+
+// dotQuote quotes s as a DOT string, escaping quotes, backslashes and newlines.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -168,3 +168,56 @@ func TestWriteJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestWriteDOT(t *testing.T) {
+	graph := &Graph{
+		ID: "plan \"1\"",
+		Nodes: []Node{
+			{ID: "research", Type: "researcher", Status: StatusSucceeded},
+			{ID: "critique", Type: "critic", Status: StatusFailed},
+			{ID: "synthesize", Type: "synthesizer", Status: StatusBlocked},
+		},
+		Edges: []Edge{
+			{From: "research", To: "critique"},
+			{From: "critique", To: "synthesize"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, graph); err != nil {
+		t.Fatalf("WriteDOT() error = %v", err)
+	}
+	output := buf.String()
+
+	for _, want := range []string{
+		`digraph "plan \"1\"" {`,
+		`"research" [label="research\nresearcher\nSUCCEEDED", fillcolor=palegreen];`,
+		`"critique" [label="critique\ncritic\nFAILED", fillcolor=salmon];`,
+		`"synthesize" [label="synthesize\nsynthesizer\nBLOCKED", fillcolor=lightgrey];`,
+		`"research" -> "critique";`,
+		`"critique" -> "synthesize";`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %s:\n%s", want, output)
+		}
+	}
+	if !strings.HasSuffix(output, "}\n") {
+		t.Errorf("Output not terminated:\n%s", output)
+	}
+
+	t.Run("Invalid Graph", func(t *testing.T) {
+		err := WriteDOT(&buf, &Graph{ID: "bad"})
+		if err == nil || !strings.Contains(err.Error(), "cannot render invalid graph") {
+			t.Errorf("Expected validation error, got %v", err)
+		}
+	})
+
+	t.Run("Nil Arguments", func(t *testing.T) {
+		if err := WriteDOT(nil, graph); err == nil {
+			t.Error("WriteDOT() should have failed for nil writer")
+		}
+		if err := WriteDOT(&buf, nil); err == nil {
+			t.Error("WriteDOT() should have failed for nil graph")
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package dag
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ConfigTimeoutSeconds is the node config key overriding how long a single
+// execution attempt of the node may take.
+const ConfigTimeoutSeconds = "timeout_seconds"
+
+// Timeout returns the node's execution timeout override, or 0 if it doesn't
+// set one. The value must be a positive number of seconds.
+func (n *Node) Timeout() (time.Duration, error) {
+	raw, ok := n.Config[ConfigTimeoutSeconds]
+	if !ok {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 1 {
+		return 0, fmt.Errorf("node '%s' has invalid %s %q: must be a positive integer", n.ID, ConfigTimeoutSeconds, raw)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
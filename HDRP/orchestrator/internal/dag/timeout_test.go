@@ -0,0 +1,48 @@
+package dag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNodeTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "absent", config: map[string]string{}, want: 0},
+		{name: "override", config: map[string]string{ConfigTimeoutSeconds: "90"}, want: 90 * time.Second},
+		{name: "zero", config: map[string]string{ConfigTimeoutSeconds: "0"}, wantErr: true},
+		{name: "negative", config: map[string]string{ConfigTimeoutSeconds: "-5"}, wantErr: true},
+		{name: "unparseable", config: map[string]string{ConfigTimeoutSeconds: "soon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &Node{ID: "n1", Type: "researcher", Config: tt.config}
+			got, err := node.Timeout()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Timeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Timeout() = %v, want %v", got, tt.want)
+			}
+			if err := node.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGraphValidate_RejectsInvalidTimeout(t *testing.T) {
+	g := &Graph{
+		ID:    "g1",
+		Nodes: []Node{{ID: "n1", Type: "researcher", Config: map[string]string{ConfigTimeoutSeconds: "0"}}},
+	}
+	err := g.Validate()
+	if err == nil || !strings.Contains(err.Error(), ConfigTimeoutSeconds) {
+		t.Errorf("Expected a timeout_seconds validation error, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"hdrp/internal/storage"
 )
@@ -51,21 +52,24 @@ func isValidTransition(current, target Status) bool {
 
 	switch current {
 	case StatusCreated:
-		return target == StatusPending || target == StatusRunning || target == StatusCancelled || target == StatusBlocked
+		return target == StatusPending || target == StatusRunning || target == StatusCancelled || target == StatusBlocked ||
+			target == StatusFailed || target == StatusSkipped
 	case StatusBlocked:
-		return target == StatusPending || target == StatusCancelled
+		// Failed or skipped when a parent fails (see FailedParentPolicy)
+		return target == StatusPending || target == StatusCancelled || target == StatusFailed || target == StatusSkipped
 	case StatusPending:
 		return target == StatusRunning || target == StatusCancelled || target == StatusFailed
 	case StatusRunning:
 		return target == StatusSucceeded || target == StatusFailed || target == StatusCancelled || target == StatusRetrying
 	case StatusFailed:
-		// Allow retries from failed to retrying or cancelled
-		return target == StatusRetrying || target == StatusCancelled
+		// Allow retries from failed (directly or via retrying) or cancellation
+		return target == StatusRetrying || target == StatusRunning || target == StatusCancelled
 	case StatusRetrying:
-		// From retrying, can go back to running (retry attempt) or to failed (retries exhausted)
-		return target == StatusRunning || target == StatusFailed || target == StatusCancelled
-	case StatusCancelled:
-		// Cancelled is terminal for an execution attempt, but could be reset to Created
+		// From retrying, can go back to running (retry attempt), to pending (requeued
+		// for the scheduler) or to failed (retries exhausted)
+		return target == StatusRunning || target == StatusPending || target == StatusFailed || target == StatusCancelled
+	case StatusCancelled, StatusSkipped:
+		// Cancelled and skipped are terminal for an execution attempt, but could be reset to Created
 		return target == StatusCreated
 	case StatusSucceeded:
 		// Succeeded is terminal for a specific run
@@ -75,59 +79,135 @@ func isValidTransition(current, target Status) bool {
 	}
 }
 
+// FailedParentPolicy decides what EvaluateReadiness does with a waiting
+// node that has a FAILED or SKIPPED parent.
+type FailedParentPolicy string
+
+const (
+	// FailedParentBlock keeps the node BLOCKED in case the parent is
+	// retried. A run with nothing left to retry ends deadlocked.
+	FailedParentBlock FailedParentPolicy = "block"
+	// FailedParentFail marks the node FAILED, naming the parent as the cause.
+	FailedParentFail FailedParentPolicy = "fail"
+	// FailedParentSkip marks the node SKIPPED.
+	FailedParentSkip FailedParentPolicy = "skip"
+)
+
+// DefaultFailedParentPolicy is used by graphs that don't set a policy. It
+// lets runs with a failed node finish instead of deadlocking.
+const DefaultFailedParentPolicy = FailedParentSkip
+
+// ParseFailedParentPolicy converts a config value to a policy. Empty selects
+// DefaultFailedParentPolicy.
+func ParseFailedParentPolicy(s string) (FailedParentPolicy, error) {
+	switch FailedParentPolicy(s) {
+	case "":
+		return DefaultFailedParentPolicy, nil
+	case FailedParentBlock, FailedParentFail, FailedParentSkip:
+		return FailedParentPolicy(s), nil
+	default:
+		return "", fmt.Errorf("unsupported failed parent policy: %s", s)
+	}
+}
+
+// SetFailedParentPolicy sets how EvaluateReadiness handles nodes whose
+// parents failed. "" selects DefaultFailedParentPolicy.
+func (g *Graph) SetFailedParentPolicy(policy FailedParentPolicy) {
+	g.failedParentPolicy = policy
+}
+
 // EvaluateReadiness scans the graph and updates node statuses based on dependencies.
-// It moves eligible nodes to PENDING and unsatisfied ones to BLOCKED.
+// It moves eligible nodes to PENDING and unsatisfied ones to BLOCKED. Nodes with
+// a FAILED or SKIPPED parent are handled by the graph's FailedParentPolicy; under
+// fail and skip the outcome carries on down to every dependent node.
+//
+// A node with conditional incoming edges is SKIPPED once its parents succeed
+// if none of the conditions hold. That branch isn't taken, so its dependent
+// nodes are SKIPPED too, whatever the FailedParentPolicy.
 func (g *Graph) EvaluateReadiness() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	policy := g.failedParentPolicy
+	if policy == "" {
+		policy = DefaultFailedParentPolicy
+	}
+
 	// Build a map of node ID to Status for quick lookup
 	nodeStatus := make(map[string]Status)
 	for _, n := range g.Nodes {
 		nodeStatus[n.ID] = n.Status
 	}
 
-	// Build reverse adjacency list (Child -> Parents)
-	parents := make(map[string][]string)
+	// Build reverse adjacency list (Child -> incoming edges)
+	incoming := make(map[string][]Edge)
 	for _, e := range g.Edges {
-		parents[e.To] = append(parents[e.To], e.From)
+		incoming[e.To] = append(incoming[e.To], e)
 	}
 
-	// Iterate and update statuses
-	for _, n := range g.Nodes {
-		// Only evaluate nodes waiting to start
-		if n.Status != StatusCreated && n.Status != StatusBlocked {
-			continue
-		}
-
-		// Check if all parents have succeeded OR are in a retryable state
-		// This enables graceful degradation - children can proceed even if parent is retrying
-		allParentsSucceeded := true
-		hasRetryingParent := false
-		for _, parentID := range parents[n.ID] {
-			parentStatus := nodeStatus[parentID]
-			if parentStatus == StatusRetrying {
-				hasRetryingParent = true
-				allParentsSucceeded = false
-				break
+	// A failed or skipped node can make its children fail or skip in turn,
+	// so repeat until a pass changes nothing
+	for changed := true; changed; {
+		changed = false
+		for i := range g.Nodes {
+			n := &g.Nodes[i]
+			// Only evaluate nodes waiting to start
+			if n.Status != StatusCreated && n.Status != StatusBlocked {
+				continue
 			}
-			if parentStatus != StatusSucceeded {
-				allParentsSucceeded = false
-				break
+
+			// Check if all parents have succeeded. A retrying parent keeps the
+			// node blocked; a failed one is handled by the policy.
+			allParentsSucceeded := true
+			failedParent := ""
+			untakenParent := ""
+			for _, e := range incoming[n.ID] {
+				parentID := e.From
+				parentStatus := nodeStatus[parentID]
+				if parentStatus == StatusSkipped && g.untaken[parentID] {
+					untakenParent = parentID
+					allParentsSucceeded = false
+					break
+				}
+				if parentStatus == StatusFailed || parentStatus == StatusSkipped {
+					failedParent = parentID
+					allParentsSucceeded = false
+					break
+				}
+				if parentStatus != StatusSucceeded {
+					allParentsSucceeded = false
+				}
 			}
-		}
 
-		var targetStatus Status
-		if allParentsSucceeded {
-			targetStatus = StatusPending
-		} else if hasRetryingParent {
-			// Keep blocked while parent is retrying
-			targetStatus = StatusBlocked
-		} else {
-			targetStatus = StatusBlocked
-		}
+			var targetStatus Status
+			switch {
+			case allParentsSucceeded && !g.branchTaken(incoming[n.ID]):
+				targetStatus = StatusSkipped
+				n.LastError = "skipped: no incoming edge condition held"
+				g.markUntaken(n.ID)
+			case untakenParent != "":
+				targetStatus = StatusSkipped
+				n.LastError = fmt.Sprintf("skipped: parent %s was not taken", untakenParent)
+				g.markUntaken(n.ID)
+			case allParentsSucceeded:
+				targetStatus = StatusPending
+			case failedParent != "" && policy == FailedParentFail:
+				targetStatus = StatusFailed
+				n.LastError = fmt.Sprintf("parent %s failed", failedParent)
+			case failedParent != "" && policy == FailedParentSkip:
+				targetStatus = StatusSkipped
+				n.LastError = fmt.Sprintf("skipped: parent %s did not succeed", failedParent)
+			default:
+				targetStatus = StatusBlocked
+			}
 
-		// Only update if state changes to avoid unnecessary writes/locks in real DB
-		if n.Status != targetStatus {
-			if err := g.SetNodeStatus(n.ID, targetStatus); err != nil {
-				return fmt.Errorf("failed to update node %s readiness: %w", n.ID, err)
+			// Only update if state changes to avoid unnecessary writes/locks in real DB
+			if n.Status != targetStatus {
+				if err := g.setNodeStatus(n.ID, targetStatus); err != nil {
+					return fmt.Errorf("failed to update node %s readiness: %w", n.ID, err)
+				}
+				nodeStatus[n.ID] = targetStatus
+				changed = true
 			}
 		}
 	}
@@ -172,8 +252,16 @@ func (g *Graph) SetStatus(s Status) error {
 }
 
 // SetNodeStatus updates a specific node's status.
-// It persists the change to storage and logs to WAL for crash recovery.
+// It persists the change to storage and logs to WAL for crash recovery,
+// labelled with the node's current attempt (see SetNodeAttempt).
 func (g *Graph) SetNodeStatus(nodeID string, s Status) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.setNodeStatus(nodeID, s)
+}
+
+// setNodeStatus is SetNodeStatus for callers already holding g.mu.
+func (g *Graph) setNodeStatus(nodeID string, s Status) error {
 	for i := range g.Nodes {
 		if g.Nodes[i].ID == nodeID {
 			oldStatus := g.Nodes[i].Status
@@ -195,6 +283,8 @@ func (g *Graph) SetNodeStatus(nodeID string, s Status) error {
 					NewStatus:  string(s),
 					RetryCount: g.Nodes[i].RetryCount,
 					LastError:  g.Nodes[i].LastError,
+					Attempt:    g.Nodes[i].Attempt,
+					Timestamp:  time.Now(),
 				}
 				if err := g.storage.LogMutation(g.ID, storage.MutationUpdateNodeStatus, payload); err != nil {
 					log.Printf("[DAG] Warning: failed to log node status mutation: %v", err)
@@ -213,3 +303,66 @@ func (g *Graph) SetNodeStatus(nodeID string, s Status) error {
 	}
 	return fmt.Errorf("node %s not found in graph", nodeID)
 }
+
+// Phases the executor reports while a node waits. Phases are free-form, so
+// workflows may set their own (e.g. WAITING_APPROVAL) alongside these.
+const (
+	PhaseWaitingLock = "WAITING_LOCK" // Waiting for the distributed node lock
+	PhaseWaitingSlot = "WAITING_SLOT" // Waiting for a worker slot
+	PhaseRateLimited = "RATE_LIMITED" // Waiting for a rate limit token
+	PhaseBackoff     = "BACKOFF"      // Waiting to retry after a failed attempt
+)
+
+// SetNodePhase records a node's phase, an auxiliary sub-state shown next to
+// its status. Phases are orthogonal to the status state machine: any phase
+// may follow any other, and setting one never changes the node's status.
+// An empty phase clears it. The phase is persisted but not logged to the WAL,
+// since recovery doesn't depend on it.
+func (g *Graph) SetNodePhase(nodeID string, phase string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == nodeID {
+			if g.Nodes[i].Phase == phase {
+				return nil
+			}
+			g.Nodes[i].Phase = phase
+			if g.storage != nil {
+				if err := g.storage.UpdateNodePhase(g.ID, nodeID, phase); err != nil {
+					log.Printf("[DAG] Warning: failed to persist node phase: %v", err)
+				}
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("node %s not found in graph", nodeID)
+}
+
+// SetNodeAttempt records which execution attempt (1-based) a node is on, so
+// the status changes it makes are logged against that attempt.
+func (g *Graph) SetNodeAttempt(nodeID string, attempt int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == nodeID {
+			g.Nodes[i].Attempt = attempt
+			return nil
+		}
+	}
+	return fmt.Errorf("node %s not found in graph", nodeID)
+}
+
+// SetNodeError records a node's last error and how many attempts it has
+// made. They are persisted with its next status change.
+func (g *Graph) SetNodeError(nodeID string, lastError string, retryCount int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == nodeID {
+			g.Nodes[i].LastError = lastError
+			g.Nodes[i].RetryCount = retryCount
+			return nil
+		}
+	}
+	return fmt.Errorf("node %s not found in graph", nodeID)
+}
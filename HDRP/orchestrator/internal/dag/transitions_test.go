@@ -2,6 +2,8 @@ package dag
 
 import (
 	"testing"
+
+	"hdrp/internal/storage"
 )
 
 func TestStatusTransitions(t *testing.T) {
@@ -140,3 +142,108 @@ func TestGraphStatusTransitions(t *testing.T) {
 		t.Error("Expected error for missing node, got nil")
 	}
 }
+
+func TestSetNodePhase(t *testing.T) {
+	store := storage.NewInMemoryStorage()
+	g := NewGraphWithStorage("phase-graph", store)
+	g.Nodes = []Node{{ID: "A", Type: "task", Status: StatusCreated}}
+	store.SaveNode(g.ID, &storage.NodeState{NodeID: "A", Type: "task", Status: string(StatusCreated)})
+
+	if err := g.SetNodePhase("A", "WAITING_APPROVAL"); err != nil {
+		t.Fatalf("SetNodePhase failed: %v", err)
+	}
+	if g.Nodes[0].Phase != "WAITING_APPROVAL" || g.Nodes[0].Status != StatusCreated {
+		t.Errorf("Node = %+v, want phase WAITING_APPROVAL with status unchanged", g.Nodes[0])
+	}
+
+	// Status transitions are validated as usual, whatever the phase
+	if err := g.SetNodeStatus("A", StatusSucceeded); err == nil {
+		t.Error("Expected CREATED -> SUCCEEDED to stay invalid")
+	}
+	if err := g.SetNodeStatus("A", StatusRunning); err != nil {
+		t.Fatalf("SetNodeStatus failed: %v", err)
+	}
+	if g.Nodes[0].Phase != "WAITING_APPROVAL" {
+		t.Errorf("Status change cleared the phase: %q", g.Nodes[0].Phase)
+	}
+
+	if err := g.SetNodePhase("A", PhaseRateLimited); err != nil {
+		t.Fatalf("SetNodePhase failed: %v", err)
+	}
+	nodes, _ := store.LoadNodes(g.ID)
+	if nodes[0].Phase != PhaseRateLimited || nodes[0].Status != string(StatusRunning) {
+		t.Errorf("Persisted node = %+v, want phase %s and status RUNNING", nodes[0], PhaseRateLimited)
+	}
+
+	if err := g.SetNodePhase("missing", PhaseBackoff); err == nil {
+		t.Error("Expected an error for an unknown node")
+	}
+}
+
+func TestEvaluateReadiness_FailedParentPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     FailedParentPolicy
+		wantChild  Status
+		wantLeaf   Status
+		wantSister Status
+	}{
+		{"Block", FailedParentBlock, StatusBlocked, StatusBlocked, StatusPending},
+		{"Fail downstream", FailedParentFail, StatusFailed, StatusFailed, StatusPending},
+		{"Skip", FailedParentSkip, StatusSkipped, StatusSkipped, StatusPending},
+		{"Default skips", "", StatusSkipped, StatusSkipped, StatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A (failed) -> B -> C, with D depending on the succeeded S
+			store := storage.NewInMemoryStorage()
+			g := NewGraphWithStorage("failed-parent", store)
+			g.Nodes = []Node{
+				{ID: "A", Status: StatusFailed},
+				{ID: "B", Status: StatusCreated},
+				{ID: "C", Status: StatusCreated},
+				{ID: "S", Status: StatusSucceeded},
+				{ID: "D", Status: StatusCreated},
+			}
+			g.Edges = []Edge{{From: "A", To: "B"}, {From: "B", To: "C"}, {From: "S", To: "D"}}
+			for _, n := range g.Nodes {
+				store.SaveNode(g.ID, &storage.NodeState{NodeID: n.ID, Status: string(n.Status)})
+			}
+			g.SetFailedParentPolicy(tt.policy)
+
+			if err := g.EvaluateReadiness(); err != nil {
+				t.Fatalf("EvaluateReadiness failed: %v", err)
+			}
+			if g.Nodes[1].Status != tt.wantChild {
+				t.Errorf("Child B = %s, want %s", g.Nodes[1].Status, tt.wantChild)
+			}
+			if g.Nodes[2].Status != tt.wantLeaf {
+				t.Errorf("Grandchild C = %s, want %s", g.Nodes[2].Status, tt.wantLeaf)
+			}
+			if g.Nodes[4].Status != tt.wantSister {
+				t.Errorf("Unrelated D = %s, want %s", g.Nodes[4].Status, tt.wantSister)
+			}
+			if tt.wantChild != StatusBlocked && g.Nodes[1].LastError == "" {
+				t.Error("Expected B to record why it didn't run")
+			}
+
+			nodes, _ := store.LoadNodes(g.ID)
+			if len(nodes) != 5 || nodes[2].Status != string(tt.wantLeaf) {
+				t.Errorf("Persisted nodes = %+v, want C %s", nodes, tt.wantLeaf)
+			}
+		})
+	}
+}
+
+func TestParseFailedParentPolicy(t *testing.T) {
+	if p, err := ParseFailedParentPolicy(""); err != nil || p != DefaultFailedParentPolicy {
+		t.Errorf("ParseFailedParentPolicy(\"\") = %q, %v, want default", p, err)
+	}
+	if p, err := ParseFailedParentPolicy("fail"); err != nil || p != FailedParentFail {
+		t.Errorf("ParseFailedParentPolicy(fail) = %q, %v", p, err)
+	}
+	if _, err := ParseFailedParentPolicy("ignore"); err == nil {
+		t.Error("Expected error for unknown policy")
+	}
+}
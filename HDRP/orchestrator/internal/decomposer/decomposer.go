@@ -0,0 +1,151 @@
+package decomposer
+
+import (
+	"context"
+	"fmt"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/generator"
+	"hdrp/internal/intent"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// Provider names accepted by New.
+const (
+	ProviderPrincipal = "principal"
+	ProviderLocal     = "local"
+)
+
+// Request describes a query to decompose into an execution graph.
+type Request struct {
+	Query   string
+	Context map[string]string
+	RunID   string
+}
+
+// Decomposer turns a user query into an executable DAG.
+type Decomposer interface {
+	Decompose(ctx context.Context, req *Request) (*dag.Graph, error)
+}
+
+// New returns the decomposer for the named provider. An empty name selects
+// the Principal service. The local decomposer loads its blueprints from
+// blueprintsDir, or uses the built-in ones when it is empty.
+func New(provider string, principal pb.PrincipalServiceClient, blueprintsDir string) (Decomposer, error) {
+	switch provider {
+	case ProviderPrincipal, "":
+		if principal == nil {
+			return nil, fmt.Errorf("principal decomposer requires a Principal client")
+		}
+		return NewPrincipalDecomposer(principal), nil
+	case ProviderLocal:
+		gen, err := generator.NewTemplateGeneratorFromDir(blueprintsDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load blueprints: %w", err)
+		}
+		return NewLocalDecomposer(intent.NewBasicParser(), gen), nil
+	default:
+		return nil, fmt.Errorf("unsupported decomposer provider: %s", provider)
+	}
+}
+
+// PrincipalDecomposer delegates decomposition to the Principal gRPC service.
+type PrincipalDecomposer struct {
+	client pb.PrincipalServiceClient
+}
+
+// NewPrincipalDecomposer creates a decomposer backed by the Principal service.
+func NewPrincipalDecomposer(client pb.PrincipalServiceClient) *PrincipalDecomposer {
+	return &PrincipalDecomposer{client: client}
+}
+
+// Decompose calls DecomposeQuery and converts the returned graph.
+// gRPC errors are wrapped so callers can still inspect the status code.
+func (d *PrincipalDecomposer) Decompose(ctx context.Context, req *Request) (*dag.Graph, error) {
+	resp, err := d.client.DecomposeQuery(ctx, &pb.QueryRequest{
+		Query:   req.Query,
+		Context: req.Context,
+		RunId:   req.RunID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("principal decomposition failed: %w", err)
+	}
+	if resp.GetGraph() == nil {
+		return nil, fmt.Errorf("principal returned no graph")
+	}
+	return ConvertProtoGraph(resp.Graph), nil
+}
+
+// LocalDecomposer plans in-process using the intent parser and template generator.
+type LocalDecomposer struct {
+	parser    intent.Parser
+	generator generator.Generator
+}
+
+// NewLocalDecomposer creates a decomposer that needs no external services.
+func NewLocalDecomposer(parser intent.Parser, gen generator.Generator) *LocalDecomposer {
+	return &LocalDecomposer{parser: parser, generator: gen}
+}
+
+// Decompose parses the query into an objective and expands it into a graph.
+func (d *LocalDecomposer) Decompose(ctx context.Context, req *Request) (*dag.Graph, error) {
+	objective, err := d.parser.Parse(req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+	if objective.Metadata == nil {
+		objective.Metadata = make(map[string]string)
+	}
+	for k, v := range req.Context {
+		objective.Metadata[k] = v
+	}
+
+	graph, err := d.generator.Generate(objective)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate graph: %w", err)
+	}
+
+	if graph.Metadata == nil {
+		graph.Metadata = make(map[string]string)
+	}
+	graph.Metadata["run_id"] = req.RunID
+	graph.Metadata["query"] = req.Query
+	graph.Metadata["intent"] = string(objective.Type)
+
+	if err := graph.Validate(); err != nil {
+		return nil, fmt.Errorf("generated graph is invalid: %w", err)
+	}
+	return graph, nil
+}
+
+// ConvertProtoGraph converts a protobuf Graph into a dag.Graph.
+func ConvertProtoGraph(pbGraph *pb.Graph) *dag.Graph {
+	nodes := make([]dag.Node, len(pbGraph.Nodes))
+	for i, pbNode := range pbGraph.Nodes {
+		nodes[i] = dag.Node{
+			ID:             pbNode.Id,
+			Type:           pbNode.Type,
+			Config:         pbNode.Config,
+			Status:         dag.Status(pbNode.Status),
+			RelevanceScore: pbNode.RelevanceScore,
+			Depth:          int(pbNode.Depth),
+		}
+	}
+
+	edges := make([]dag.Edge, len(pbGraph.Edges))
+	for i, pbEdge := range pbGraph.Edges {
+		edges[i] = dag.Edge{
+			From: pbEdge.From,
+			To:   pbEdge.To,
+		}
+	}
+
+	return &dag.Graph{
+		ID:       pbGraph.Id,
+		Nodes:    nodes,
+		Edges:    edges,
+		Status:   dag.StatusCreated,
+		Metadata: pbGraph.Metadata,
+	}
+}
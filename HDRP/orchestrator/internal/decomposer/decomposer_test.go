@@ -0,0 +1,111 @@
+package decomposer
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockPrincipalClient struct {
+	resp *pb.DecompositionResponse
+	err  error
+	req  *pb.QueryRequest
+}
+
+func (m *mockPrincipalClient) DecomposeQuery(ctx context.Context, req *pb.QueryRequest, opts ...grpc.CallOption) (*pb.DecompositionResponse, error) {
+	m.req = req
+	return m.resp, m.err
+}
+
+func TestLocalDecomposer(t *testing.T) {
+	d, err := New(ProviderLocal, nil, "")
+	if err != nil {
+		t.Fatalf("New(local) error = %v", err)
+	}
+
+	graph, err := d.Decompose(context.Background(), &Request{
+		Query:   "Research the history of \"graph databases\"",
+		Context: map[string]string{"tenant": "acme"},
+		RunID:   "run-local",
+	})
+	if err != nil {
+		t.Fatalf("Decompose() error = %v", err)
+	}
+
+	if len(graph.Nodes) == 0 {
+		t.Fatal("Expected generated graph to have nodes")
+	}
+	if graph.Metadata["run_id"] != "run-local" {
+		t.Errorf("Expected run_id metadata, got %q", graph.Metadata["run_id"])
+	}
+	if graph.Metadata["intent"] != "RESEARCH" {
+		t.Errorf("Expected RESEARCH intent, got %q", graph.Metadata["intent"])
+	}
+	for _, n := range graph.Nodes {
+		if n.Config["meta_tenant"] != "acme" {
+			t.Errorf("Node %s missing request context in config", n.ID)
+		}
+	}
+}
+
+func TestLocalDecomposer_EmptyQuery(t *testing.T) {
+	d, _ := New(ProviderLocal, nil, "")
+	if _, err := d.Decompose(context.Background(), &Request{Query: "  "}); err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
+
+func TestPrincipalDecomposer(t *testing.T) {
+	client := &mockPrincipalClient{
+		resp: &pb.DecompositionResponse{
+			Graph: &pb.Graph{
+				Id: "graph-1",
+				Nodes: []*pb.Node{
+					{Id: "r1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: "CREATED"},
+					{Id: "s1", Type: "synthesizer", Status: "CREATED"},
+				},
+				Edges: []*pb.Edge{{From: "r1", To: "s1"}},
+			},
+		},
+	}
+
+	d, err := New(ProviderPrincipal, client, "")
+	if err != nil {
+		t.Fatalf("New(principal) error = %v", err)
+	}
+
+	graph, err := d.Decompose(context.Background(), &Request{Query: "q", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("Decompose() error = %v", err)
+	}
+	if client.req.GetRunId() != "run-1" {
+		t.Errorf("Expected run ID to be forwarded, got %q", client.req.GetRunId())
+	}
+	if graph.ID != "graph-1" || len(graph.Nodes) != 2 || len(graph.Edges) != 1 {
+		t.Errorf("Unexpected converted graph: %+v", graph)
+	}
+}
+
+func TestPrincipalDecomposer_PreservesStatus(t *testing.T) {
+	client := &mockPrincipalClient{err: status.Error(codes.InvalidArgument, "bad query")}
+	d := NewPrincipalDecomposer(client)
+
+	_, err := d.Decompose(context.Background(), &Request{Query: "q"})
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument status through wrapping, got %v", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New(ProviderPrincipal, nil, ""); err == nil {
+		t.Error("Expected error for principal decomposer without client")
+	}
+	if _, err := New("unknown", nil, ""); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+}
@@ -0,0 +1,27 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"hdrp/internal/dag"
+)
+
+func TestExecute_RejectedPlanNotPersisted(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetNodeTypeAllowlist(&dag.NodeTypeAllowlist{Global: []string{"critic"}})
+
+	graph := &dag.Graph{
+		ID:     "graph-rejected",
+		Status: dag.StatusCreated,
+		Nodes:  []dag.Node{{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated}},
+	}
+	_, err := executor.Execute(context.Background(), graph, "run-rejected")
+	if !errors.Is(err, dag.ErrNodeTypeNotAllowed) {
+		t.Fatalf("Expected ErrNodeTypeNotAllowed, got %v", err)
+	}
+	if _, err := executor.storage.LoadGraph(graph.ID); err == nil {
+		t.Error("Rejected plan was persisted")
+	}
+}
@@ -0,0 +1,38 @@
+package executor
+
+import (
+	"hdrp/internal/metrics"
+	"hdrp/internal/retry"
+)
+
+// breakerGaugeValues maps breaker states to hdrp_circuit_breaker_state values.
+var breakerGaugeValues = map[retry.CircuitState]int{
+	retry.CircuitClosed:   metrics.BreakerClosed,
+	retry.CircuitHalfOpen: metrics.BreakerHalfOpen,
+	retry.CircuitOpen:     metrics.BreakerOpen,
+}
+
+// recordBreakerStateChange counts breakers opening and half-opening.
+func recordBreakerStateChange(serviceType string, from, to retry.CircuitState) {
+	breakerLog.Infof("%s breaker %v -> %v", serviceType, from, to)
+	switch to {
+	case retry.CircuitOpen:
+		metrics.RecordCircuitBreakerOpened(serviceType)
+	case retry.CircuitHalfOpen:
+		metrics.RecordCircuitBreakerHalfOpened(serviceType)
+	}
+}
+
+// RegisterBreakerMetrics exports the state of this executor's circuit
+// breakers as the hdrp_circuit_breaker_state gauge, read on every scrape of
+// /metrics. Only one executor's breakers are exported at a time.
+func (e *DAGExecutor) RegisterBreakerMetrics() {
+	metrics.RegisterCircuitBreakers(func() map[string]int {
+		states := e.circuitBreakers.States()
+		values := make(map[string]int, len(states))
+		for serviceType, state := range states {
+			values[serviceType] = breakerGaugeValues[state]
+		}
+		return values
+	})
+}
@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"sync"
+	"time"
+)
+
+// restoreBreakers loads the circuit breaker states saved by an earlier
+// process, so services that were failing stay cut off across a restart.
+func (e *DAGExecutor) restoreBreakers() {
+	if e.breakerStore == nil {
+		return
+	}
+	snapshots, err := e.breakerStore.LoadBreakers()
+	if err != nil {
+		execLog.Warnf("failed to load circuit breaker states: %v", err)
+		return
+	}
+	e.circuitBreakers.Restore(snapshots)
+	if len(snapshots) > 0 {
+		execLog.Infof("Restored %d circuit breaker states", len(snapshots))
+	}
+}
+
+// saveBreakers stores the current state of every circuit breaker.
+func (e *DAGExecutor) saveBreakers() {
+	if err := e.breakerStore.SaveBreakers(e.circuitBreakers.GetSnapshot()); err != nil {
+		execLog.Warnf("failed to save circuit breaker states: %v", err)
+	}
+}
+
+// SetBreakerSaveInterval saves circuit breaker states to storage on a fixed
+// schedule and once more on Close, for the next process to restore at
+// startup. A non-positive interval stops saving. Has no effect without
+// persistent storage.
+func (e *DAGExecutor) SetBreakerSaveInterval(interval time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopBreakerSaves != nil {
+		e.stopBreakerSaves()
+		e.stopBreakerSaves = nil
+	}
+	if interval <= 0 {
+		return
+	}
+	if e.breakerStore == nil {
+		execLog.Infof("No persistent storage, circuit breaker states will not be saved")
+		return
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				e.saveBreakers()
+			}
+		}
+	}()
+
+	e.stopBreakerSaves = func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// stopSavingBreakers stops the periodic saves and, if they were running,
+// saves the final breaker states.
+func (e *DAGExecutor) stopSavingBreakers() {
+	e.mu.Lock()
+	stop := e.stopBreakerSaves
+	e.stopBreakerSaves = nil
+	e.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		e.saveBreakers()
+	}
+}
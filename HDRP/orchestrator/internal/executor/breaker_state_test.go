@@ -0,0 +1,36 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/retry"
+)
+
+// TestBreakerStateSurvivesRestart verifies an open breaker saved on Close is
+// restored by the next executor on the same database.
+func TestBreakerStateSurvivesRestart(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", t.TempDir()+"/breakers.db")
+
+	first := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	first.SetBreakerSaveInterval(time.Hour)
+	for i := 0; i < 10; i++ {
+		first.circuitBreakers.RecordFailure("critic")
+	}
+	if first.circuitBreakers.States()["critic"] != retry.CircuitOpen {
+		t.Fatal("Expected the critic breaker to open")
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	second := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	defer second.Close()
+	if state := second.circuitBreakers.States()["critic"]; state != retry.CircuitOpen {
+		t.Fatalf("Restored critic breaker = %v, want Open", state)
+	}
+	if second.circuitBreakers.ShouldAllow("critic") {
+		t.Error("Restored critic breaker allowed a request")
+	}
+}
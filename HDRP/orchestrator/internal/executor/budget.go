@@ -0,0 +1,180 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+)
+
+// Reasons a run's budget is exceeded, reported as "budget exceeded: <reason>".
+const (
+	BudgetDuration = "duration"
+	BudgetAttempts = "attempts"
+	BudgetCost     = "cost"
+)
+
+// ExecutionBudget caps what a single run may spend. A run that exceeds it
+// stops scheduling nodes, cancels the ones in flight and returns a failed
+// result. Zero fields are unlimited.
+type ExecutionBudget struct {
+	MaxDuration time.Duration // Wall-clock time from the start of the run
+	MaxAttempts int           // Node attempts across the run, retries included
+	// Sum of the cost weight (EstimateDefaults.Costs) of every node attempt
+	MaxCost float64
+}
+
+// IsZero reports whether the budget sets no limits.
+func (b ExecutionBudget) IsZero() bool {
+	return b.MaxDuration <= 0 && b.MaxAttempts <= 0 && b.MaxCost <= 0
+}
+
+// SetExecutionBudget sets the budget of runs that don't request their own
+// in RunOptions.
+func (e *DAGExecutor) SetExecutionBudget(budget ExecutionBudget) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.budget = budget
+}
+
+// runBudget enforces an ExecutionBudget for one run. A nil runBudget is
+// unlimited.
+type runBudget struct {
+	limits    ExecutionBudget
+	costs     map[string]float64 // Cost weight per node type
+	nodeTypes map[string]string  // Node ID to service type, for costing attempts
+
+	mu       sync.Mutex // Serializes attempt checks with their recording
+	once     sync.Once
+	reason   string
+	exceeded chan struct{} // Closed once the budget is exceeded
+	timer    *time.Timer
+}
+
+// newRunBudget starts enforcing limits for a run of graph, or returns nil if
+// limits sets none.
+func newRunBudget(limits ExecutionBudget, graph *dag.Graph, costs map[string]float64) *runBudget {
+	if limits.IsZero() {
+		return nil
+	}
+	b := &runBudget{
+		limits:    limits,
+		costs:     costs,
+		nodeTypes: make(map[string]string, len(graph.Nodes)),
+		exceeded:  make(chan struct{}),
+	}
+	for _, n := range graph.NodeSnapshot() {
+		b.nodeTypes[n.ID] = serviceType(n.Type)
+	}
+	if limits.MaxDuration > 0 {
+		b.timer = time.AfterFunc(limits.MaxDuration, func() { b.trip(BudgetDuration) })
+	}
+	return b
+}
+
+// trip marks the budget exceeded for reason. Only the first reason is kept.
+func (b *runBudget) trip(reason string) {
+	b.once.Do(func() {
+		b.reason = reason
+		close(b.exceeded)
+	})
+}
+
+// done returns a channel closed once the budget is exceeded.
+func (b *runBudget) done() <-chan struct{} {
+	if b == nil {
+		return nil
+	}
+	return b.exceeded
+}
+
+// tripped reports whether the budget has been exceeded.
+func (b *runBudget) tripped() bool {
+	select {
+	case <-b.done():
+		return true
+	default:
+		return false
+	}
+}
+
+// stop releases the duration timer.
+func (b *runBudget) stop() {
+	if b != nil && b.timer != nil {
+		b.timer.Stop()
+	}
+}
+
+// cost returns the weight of one attempt of nodeID, by its service type.
+func (b *runBudget) cost(nodeID string) float64 {
+	if cost, ok := b.costs[b.nodeTypes[nodeID]]; ok {
+		return cost
+	}
+	return DefaultNodeCost
+}
+
+// recordAttempt records an attempt of nodeID in runMetrics if the budget
+// allows one more. Otherwise it trips the budget and returns an error for
+// the node.
+func (b *runBudget) recordAttempt(runMetrics *retry.RetryMetrics, nodeID string) error {
+	if b == nil {
+		runMetrics.RecordAttempt(nodeID)
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	select {
+	case <-b.exceeded:
+		return fmt.Errorf("budget exceeded: %s", b.reason)
+	default:
+	}
+	if b.limits.MaxAttempts > 0 && runMetrics.TotalAttempts() >= b.limits.MaxAttempts {
+		b.trip(BudgetAttempts)
+		return fmt.Errorf("budget exceeded: %s", BudgetAttempts)
+	}
+	if b.limits.MaxCost > 0 {
+		spent := 0.0
+		for id, m := range runMetrics.GetAllMetrics() {
+			spent += float64(m.TotalAttempts) * b.cost(id)
+		}
+		if spent+b.cost(nodeID) > b.limits.MaxCost {
+			b.trip(BudgetCost)
+			return fmt.Errorf("budget exceeded: %s", BudgetCost)
+		}
+	}
+	runMetrics.RecordAttempt(nodeID)
+	return nil
+}
+
+// stopOverBudget ends a run whose budget was exceeded: unfinished nodes are
+// marked CANCELLED and the graph FAILED, keeping the results of nodes that
+// already finished.
+func (e *DAGExecutor) stopOverBudget(graph *dag.Graph, budget *runBudget, runMetrics *retry.RetryMetrics) *ExecutionResult {
+	cancelled := e.cancelUnfinished(graph)
+
+	succeededNodes := []string{}
+	failedNodes := make(map[string]string)
+	for _, n := range graph.NodeSnapshot() {
+		switch n.Status {
+		case dag.StatusSucceeded:
+			succeededNodes = append(succeededNodes, n.ID)
+		case dag.StatusFailed:
+			failedNodes[n.ID] = n.LastError
+		}
+	}
+	e.finishGraph(graph, dag.StatusFailed)
+
+	execLog.Warnf("Graph %s exceeded its %s budget, %d unfinished nodes marked CANCELLED", graph.ID, budget.reason, cancelled)
+	return &ExecutionResult{
+		GraphID:        graph.ID,
+		Success:        false,
+		SucceededNodes: succeededNodes,
+		FailedNodes:    failedNodes,
+		ErrorMessage:   fmt.Sprintf("budget exceeded: %s", budget.reason),
+		RetryMetrics:   runMetrics,
+	}
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newBudgetTestExecutor(t *testing.T, researcher *mockResearcherClient) *DAGExecutor {
+	t.Helper()
+	executor := newTestExecutor(t, &clients.ServiceClients{Researcher: researcher, Critic: &mockCriticClient{}})
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: 10 * time.Millisecond})
+	return executor
+}
+
+// TestExecutionBudget_Attempts verifies a run stops once its node attempts,
+// retries included, reach the budget.
+func TestExecutionBudget_Attempts(t *testing.T) {
+	researcher := &mockResearcherClient{maxFailures: 10, failureType: status.Error(codes.Unavailable, "down")}
+	executor := newBudgetTestExecutor(t, researcher)
+	executor.SetExecutionBudget(ExecutionBudget{MaxAttempts: 3})
+
+	graph := researchCriticGraph("test-budget-attempts", false)
+	result, err := executor.Execute(context.Background(), graph, "run-budget-attempts")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success || result.ErrorMessage != "budget exceeded: attempts" {
+		t.Fatalf("Expected the attempts budget to stop the run, got success=%v %q", result.Success, result.ErrorMessage)
+	}
+	if got := result.RetryMetrics.TotalAttempts(); got != 3 {
+		t.Errorf("Run made %d attempts, want 3", got)
+	}
+	if researcher.calls() != 3 {
+		t.Errorf("Researcher called %d times, want 3", researcher.calls())
+	}
+	if graph.Nodes[1].Status != dag.StatusCancelled {
+		t.Errorf("critic1 status = %s, want CANCELLED", graph.Nodes[1].Status)
+	}
+	if graph.Status != dag.StatusFailed {
+		t.Errorf("Graph status = %s, want FAILED", graph.Status)
+	}
+}
+
+// TestExecutionBudget_Duration verifies a run over its time budget cancels
+// the node in flight rather than waiting for it.
+func TestExecutionBudget_Duration(t *testing.T) {
+	researcher := &gatedResearcherClient{started: make(chan struct{}, 1), release: make(chan struct{})}
+	executor := newTestExecutor(t, &clients.ServiceClients{Researcher: researcher, Critic: &mockCriticClient{}})
+
+	graph := researchCriticGraph("test-budget-duration", false)
+	start := time.Now()
+	result, err := executor.ExecuteWithOptions(context.Background(), graph, "run-budget-duration",
+		RunOptions{Budget: &ExecutionBudget{MaxDuration: 100 * time.Millisecond}})
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.ErrorMessage != "budget exceeded: duration" {
+		t.Fatalf("ErrorMessage = %q, want the duration budget", result.ErrorMessage)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Run took %v to stop after its 100ms budget", elapsed)
+	}
+	for _, n := range graph.Nodes {
+		if n.Status != dag.StatusCancelled {
+			t.Errorf("Node %s status = %s, want CANCELLED", n.ID, n.Status)
+		}
+	}
+}
+
+// TestExecutionBudget_Cost verifies node attempts are costed by type, so a
+// run stops before the attempt that would take it over budget.
+func TestExecutionBudget_Cost(t *testing.T) {
+	executor := newBudgetTestExecutor(t, &mockResearcherClient{})
+	executor.SetEstimateDefaults(EstimateDefaults{Costs: map[string]float64{"researcher": 1, "critic": 2}})
+	executor.SetExecutionBudget(ExecutionBudget{MaxCost: 2.5})
+
+	graph := researchCriticGraph("test-budget-cost", false)
+	result, err := executor.Execute(context.Background(), graph, "run-budget-cost")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.ErrorMessage != "budget exceeded: cost" {
+		t.Fatalf("ErrorMessage = %q, want the cost budget", result.ErrorMessage)
+	}
+	if len(result.SucceededNodes) != 1 || result.SucceededNodes[0] != "researcher1" {
+		t.Errorf("SucceededNodes = %v, want the researcher within budget", result.SucceededNodes)
+	}
+
+	// The same graph fits a larger budget
+	executor.SetExecutionBudget(ExecutionBudget{MaxCost: 3})
+	result, err = executor.Execute(context.Background(), researchCriticGraph("test-budget-cost-ok", false), "run-budget-cost-ok")
+	if err != nil || !result.Success {
+		t.Fatalf("Expected the run to fit its budget, got %+v, %v", result, err)
+	}
+}
+
+// TestRunBudget_CostsAgentNodeTypes verifies _agent nodes are costed as
+// their service type.
+func TestRunBudget_CostsAgentNodeTypes(t *testing.T) {
+	graph := &dag.Graph{Nodes: []dag.Node{
+		{ID: "researcher1", Type: "researcher_agent"},
+		{ID: "custom1", Type: "custom"},
+	}}
+	budget := newRunBudget(ExecutionBudget{MaxCost: 10}, graph, map[string]float64{"researcher": 3})
+	defer budget.stop()
+
+	if got := budget.cost("researcher1"); got != 3 {
+		t.Errorf("researcher_agent cost = %v, want the researcher's 3", got)
+	}
+	if got := budget.cost("custom1"); got != DefaultNodeCost {
+		t.Errorf("custom cost = %v, want the default %v", got, DefaultNodeCost)
+	}
+}
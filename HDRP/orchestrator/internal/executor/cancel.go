@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hdrp/internal/dag"
+)
+
+// ErrExecutionCancelled is returned by Execute when the run's context ends
+// before the graph finishes.
+var ErrExecutionCancelled = errors.New("execution cancelled")
+
+// cancelRun stops a run whose context ended: every node that hadn't
+// finished is marked CANCELLED and the graph ends CANCELLED, with each
+// transition persisted like any other. Nodes that already succeeded,
+// failed or were skipped keep their status. The caller must have waited for
+// the nodes in flight to return. Returns the error Execute reports.
+func (e *DAGExecutor) cancelRun(ctx context.Context, graph *dag.Graph) error {
+	cancelled := e.cancelUnfinished(graph)
+	e.finishGraph(graph, dag.StatusCancelled)
+
+	execLog.Infof("Graph %s cancelled (%v), %d unfinished nodes marked CANCELLED", graph.ID, ctx.Err(), cancelled)
+	return fmt.Errorf("%w: %w", ErrExecutionCancelled, ctx.Err())
+}
+
+// cancelUnfinished marks every node that hasn't finished CANCELLED and
+// returns how many were.
+func (e *DAGExecutor) cancelUnfinished(graph *dag.Graph) int {
+	cancelled := 0
+	for _, node := range graph.NodeSnapshot() {
+		switch node.Status {
+		case dag.StatusSucceeded, dag.StatusFailed, dag.StatusCancelled, dag.StatusSkipped:
+			continue
+		}
+		if err := graph.SetNodeStatus(node.ID, dag.StatusCancelled); err != nil {
+			execLog.Warnf("failed to cancel node %s: %v", node.ID, err)
+			continue
+		}
+		cancelled++
+	}
+	return cancelled
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// blockingCriticClient blocks every call until its context ends.
+type blockingCriticClient struct {
+	started chan struct{}
+}
+
+func (c *blockingCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestExecute_Cancelled(t *testing.T) {
+	critic := &blockingCriticClient{started: make(chan struct{}, 1)}
+	executor := newTestExecutor(t, &clients.ServiceClients{
+		Researcher: &mockResearcherClient{},
+		Critic:     critic,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	graph := researchCriticGraph("test-cancel", false)
+	go func() {
+		<-critic.started
+		cancel()
+	}()
+
+	start := time.Now()
+	result, err := executor.Execute(ctx, graph, "run-cancel")
+	if !errors.Is(err, ErrExecutionCancelled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute error = %v, want ErrExecutionCancelled wrapping context.Canceled", err)
+	}
+	if result != nil {
+		t.Errorf("Expected no result for a cancelled run, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Execute took %v to return after cancellation", elapsed)
+	}
+
+	if graph.Status != dag.StatusCancelled {
+		t.Errorf("Graph status = %s, want CANCELLED", graph.Status)
+	}
+	want := map[string]dag.Status{"researcher1": dag.StatusSucceeded, "critic1": dag.StatusCancelled}
+	for _, n := range graph.Nodes {
+		if n.Status != want[n.ID] {
+			t.Errorf("Node %s status = %s, want %s", n.ID, n.Status, want[n.ID])
+		}
+	}
+
+	nodes, err := executor.storage.LoadNodes(graph.ID)
+	if err != nil {
+		t.Fatalf("LoadNodes failed: %v", err)
+	}
+	for _, n := range nodes {
+		if dag.Status(n.Status) != want[n.NodeID] {
+			t.Errorf("Stored node %s status = %s, want %s", n.NodeID, n.Status, want[n.NodeID])
+		}
+	}
+
+	// The cancelled node's lock is released once its goroutine unwinds
+	waitFor(t, func() bool {
+		acquired, err := executor.lockManager.AcquireNodeLock(context.Background(), "run-cancel/critic1")
+		return err == nil && acquired
+	})
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+)
+
+// numberedResearcher returns a fixed number of claims named claim-0, claim-1, ...
+type numberedResearcher struct {
+	claims int
+}
+
+func (r *numberedResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	return &pb.ResearchResponse{Claims: numberedClaims(r.claims)}, nil
+}
+
+// claimCounter returns the value of a claims counter for one run and node.
+func claimCounter(t *testing.T, name, runID, nodeID string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather failed: %v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["run_id"] == runID && labels["node_id"] == nodeID {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestExecute_RecordsClaimMetrics(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher: &numberedResearcher{claims: 5},
+		Critic:     &batchRecordingCritic{}, // Accepts even-numbered claims
+	}, 2)
+	defer executor.Close()
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-claim-metrics", false), "run-claim-metrics")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got failed nodes %v", result.FailedNodes)
+	}
+
+	for _, tt := range []struct {
+		metric string
+		nodeID string
+		want   float64
+	}{
+		{"hdrp_claims_extracted_total", "researcher1", 5},
+		{"hdrp_claims_verified_total", "critic1", 3},
+		{"hdrp_claims_rejected_total", "critic1", 2},
+	} {
+		if got := claimCounter(t, tt.metric, "run-claim-metrics", tt.nodeID); got != tt.want {
+			t.Errorf("%s{node_id=%q} = %v, want %v", tt.metric, tt.nodeID, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// Outputs of built-in node types that edge conditions can test (see
+// dag.ParseCondition).
+const (
+	OutputClaimCount    = "claim_count"    // Claims a researcher found or a critic checked
+	OutputVerifiedCount = "verified_count" // Claims a critic accepted
+	OutputRejectedCount = "rejected_count" // Claims a critic rejected
+	OutputReportLength  = "report_length"  // Characters in a synthesizer's report
+)
+
+// nodeOutputs derives the outputs edge conditions are evaluated against from
+// a succeeded node's result. Results of other types have none, so conditions
+// on edges out of them never hold.
+func nodeOutputs(result *NodeResult) map[string]float64 {
+	switch data := result.Data.(type) {
+	case []*pb.AtomicClaim:
+		return map[string]float64{OutputClaimCount: float64(len(data))}
+	case []*pb.CritiqueResult:
+		verified := 0
+		for _, r := range data {
+			if r.GetIsValid() {
+				verified++
+			}
+		}
+		return map[string]float64{
+			OutputClaimCount:    float64(len(data)),
+			OutputVerifiedCount: float64(verified),
+			OutputRejectedCount: float64(len(data) - verified),
+		}
+	case *pb.SynthesizeResponse:
+		return map[string]float64{OutputReportLength: float64(len(data.GetReport()))}
+	}
+	return nil
+}
+
+// restoreNodeOutputs sets the outputs of succeeded nodes kept by ResumeGraph
+// whose outgoing edges are conditional, so their children are evaluated as
+// in the original run.
+func restoreNodeOutputs(graph *dag.Graph, nodeResults *resultSet) {
+	sources := make(map[string]bool)
+	for _, e := range graph.Edges {
+		if e.Condition != "" {
+			sources[e.From] = true
+		}
+	}
+	for _, node := range graph.NodeSnapshot() {
+		if !sources[node.ID] || node.Status != dag.StatusSucceeded {
+			continue
+		}
+		if result, ok := nodeResults.Get(node.ID); ok {
+			graph.SetNodeOutputs(node.ID, nodeOutputs(result))
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// refutingCriticClient finds every claim invalid.
+type refutingCriticClient struct{}
+
+func (c *refutingCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	for _, claim := range req.Claims {
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: false, Confidence: 0.9})
+	}
+	return &pb.VerifyResponse{Results: results}, nil
+}
+
+// conditionalSynthesisGraph only synthesizes if the critic rejected a claim.
+func conditionalSynthesisGraph(id string) *dag.Graph {
+	graph := researchCriticGraph(id, true)
+	graph.Edges[1].Condition = OutputRejectedCount + " > 0"
+	return graph
+}
+
+func TestExecute_ConditionalEdge(t *testing.T) {
+	tests := []struct {
+		name       string
+		critic     pb.CriticServiceClient
+		wantSynth  dag.Status
+		wantReport string
+	}{
+		{"Condition holds", &refutingCriticClient{}, dag.StatusSucceeded, "Test report"},
+		{"Condition fails", &echoCriticClient{}, dag.StatusSkipped, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := newTestExecutor(t, &clients.ServiceClients{
+				Researcher:  &mockResearcherClient{},
+				Critic:      tt.critic,
+				Synthesizer: &mockSynthesizerClient{},
+			})
+
+			graph := conditionalSynthesisGraph("test-conditional-" + string(tt.wantSynth))
+			result, err := executor.Execute(context.Background(), graph, "run-conditional-"+string(tt.wantSynth))
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+			}
+			if graph.Nodes[2].Status != tt.wantSynth {
+				t.Errorf("synthesizer1 status = %s, want %s", graph.Nodes[2].Status, tt.wantSynth)
+			}
+			if result.FinalReport != tt.wantReport {
+				t.Errorf("FinalReport = %q, want %q", result.FinalReport, tt.wantReport)
+			}
+		})
+	}
+}
+
+// TestResumeGraph_KeepsEdgeConditions verifies conditions survive storage
+// and are evaluated against the outputs of nodes kept from the first run.
+func TestResumeGraph_KeepsEdgeConditions(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetPersistNodeResults(true)
+	executor.clients.Critic = &refutingCriticClient{}
+	executor.clients.Synthesizer = &failingSynthesizerClient{}
+
+	graph := conditionalSynthesisGraph("graph-resume-conditional")
+	if _, err := executor.Execute(context.Background(), graph, "run-resume-conditional"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	recovered, err := executor.RecoverGraph(graph.ID)
+	if err != nil {
+		t.Fatalf("Recovery failed: %v", err)
+	}
+	if got := recovered.Edges[1].Condition; got != OutputRejectedCount+" > 0" {
+		t.Fatalf("Recovered condition = %q", got)
+	}
+
+	// A critic re-run now would accept the claim and skip the synthesizer
+	executor.clients.Critic = &echoCriticClient{}
+	executor.clients.Synthesizer = &mockSynthesizerClient{}
+	result, err := executor.ResumeGraph(context.Background(), recovered)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if !result.Success || result.FinalReport != "Test report" {
+		t.Fatalf("Expected the kept critic's rejections to run the synthesizer, got %+v", result)
+	}
+}
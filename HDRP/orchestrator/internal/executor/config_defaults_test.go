@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// configCapturingResearcher records the config each node was called with.
+type configCapturingResearcher struct {
+	mu      sync.Mutex
+	configs map[string]map[string]string
+}
+
+func (r *configCapturingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs[req.SourceNodeId] = req.Config
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func TestExecute_InheritsGraphConfigDefaults(t *testing.T) {
+	researcher := &configCapturingResearcher{configs: make(map[string]map[string]string)}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+
+	graph := &dag.Graph{
+		ID:       "config-defaults",
+		Status:   dag.StatusCreated,
+		Metadata: map[string]string{"config.model": "base-model", "config.locale": "en-GB"},
+		Nodes: []dag.Node{
+			{ID: "a", Type: "researcher", Config: map[string]string{"query": "a"}, Status: dag.StatusCreated},
+			{ID: "b", Type: "researcher", Config: map[string]string{"query": "b", "model": "large-model"}, Status: dag.StatusCreated},
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), graph, "run-config-defaults")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	if got := researcher.configs["a"]; got["model"] != "base-model" || got["locale"] != "en-GB" {
+		t.Errorf("Node a config = %v, expected inherited model and locale", got)
+	}
+	if got := researcher.configs["b"]; got["model"] != "large-model" || got["locale"] != "en-GB" {
+		t.Errorf("Node b config = %v, expected its own model and inherited locale", got)
+	}
+}
+
+func TestExecute_DefaultMaxDepth(t *testing.T) {
+	chain := func(id string) *dag.Graph {
+		g := &dag.Graph{ID: id, Status: dag.StatusCreated}
+		for _, n := range []string{"a", "b", "c", "d"} {
+			g.Nodes = append(g.Nodes, dag.Node{ID: n, Type: "researcher", Config: map[string]string{"query": n}, Status: dag.StatusCreated})
+		}
+		g.Edges = []dag.Edge{{From: "a", To: "b"}, {From: "b", To: "c"}, {From: "c", To: "d"}}
+		return g
+	}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher: &configCapturingResearcher{configs: make(map[string]map[string]string)},
+	}, 2)
+
+	_, err := executor.Execute(context.Background(), chain("depth-default"), "run-depth-default")
+	if err == nil || !strings.Contains(err.Error(), "max depth of 3 layers") {
+		t.Fatalf("Expected the default depth limit to reject a 4-layer graph, got %v", err)
+	}
+
+	executor.SetDefaultMaxDepth(4)
+	graph := chain("depth-configured")
+	result, err := executor.Execute(context.Background(), graph, "run-depth-configured")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+	if graph.Metadata[dag.MetadataMaxDepth] != "4" {
+		t.Errorf("Expected max depth recorded in metadata, got %q", graph.Metadata[dag.MetadataMaxDepth])
+	}
+}
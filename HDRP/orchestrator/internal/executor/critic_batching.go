@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"hdrp/internal/metrics"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// DefaultCriticBatchConcurrency is how many claim batches a critic node
+// verifies at once when CriticBatching.Concurrency is unset.
+const DefaultCriticBatchConcurrency = 4
+
+// CriticBatching splits a critic node's claims across several Verify
+// requests so large claim sets don't produce one oversized RPC.
+type CriticBatching struct {
+	BatchSize   int // Claims per Verify request; <= 0 sends every claim in one request
+	Concurrency int // Batches in flight at once per node; <= 0 means DefaultCriticBatchConcurrency
+
+	// AllowPartial lets a node succeed with the claims from the batches that
+	// were verified when others fail. Otherwise any failed batch fails the
+	// node. A node whose batches all fail always fails.
+	AllowPartial bool
+}
+
+// SetCriticBatching sets how critic nodes split claims across Verify requests.
+func (e *DAGExecutor) SetCriticBatching(batching CriticBatching) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.criticBatching = batching
+}
+
+// claimVerification is the merged outcome of a critic node's batches.
+type claimVerification struct {
+	results       []*pb.CritiqueResult // In claim order across successful batches
+	verified      int                  // Claims the critic accepted
+	checked       int                  // Claims in successful batches
+	failedBatches int
+}
+
+// verifyClaims verifies claims in batches, bounded by the configured
+// concurrency, and merges the results in claim order.
+func (e *DAGExecutor) verifyClaims(ctx context.Context, nodeID string, claims []*pb.AtomicClaim, task, runID string) (*claimVerification, error) {
+	e.mu.RLock()
+	batching := e.criticBatching
+	e.mu.RUnlock()
+
+	if batching.BatchSize <= 0 || len(claims) <= batching.BatchSize {
+		resp, err := e.verifyBatch(ctx, claims, task, runID)
+		if err != nil {
+			return nil, fmt.Errorf("critic RPC failed: %w", err)
+		}
+		return &claimVerification{results: resp.Results, verified: int(resp.VerifiedCount), checked: len(claims)}, nil
+	}
+
+	var batches [][]*pb.AtomicClaim
+	for start := 0; start < len(claims); start += batching.BatchSize {
+		end := min(start+batching.BatchSize, len(claims))
+		batches = append(batches, claims[start:end])
+	}
+	concurrency := batching.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultCriticBatchConcurrency
+	}
+
+	// Without partial results the first failure makes the rest pointless
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]*pb.VerifyResponse, len(batches))
+	errs := make([]error, len(batches))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	// The first batch to fail is reported; later ones may only have been
+	// cancelled because of it
+	var firstFailure sync.Once
+	failedBatch := -1
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []*pb.AtomicClaim) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-batchCtx.Done():
+				errs[i] = batchCtx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			responses[i], errs[i] = e.verifyBatch(batchCtx, batch, task, runID)
+			if errs[i] != nil {
+				firstFailure.Do(func() { failedBatch = i })
+				if !batching.AllowPartial {
+					cancel()
+				}
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+
+	merged := &claimVerification{}
+	for i, resp := range responses {
+		if errs[i] != nil {
+			merged.failedBatches++
+			continue
+		}
+		merged.results = append(merged.results, resp.Results...)
+		merged.verified += int(resp.VerifiedCount)
+		merged.checked += len(batches[i])
+	}
+
+	if merged.failedBatches == 0 {
+		return merged, nil
+	}
+	if failedBatch < 0 {
+		// Every failed batch was cancelled before it started
+		for i, err := range errs {
+			if err != nil {
+				failedBatch = i
+				break
+			}
+		}
+	}
+	err := fmt.Errorf("critic RPC failed (batch %d of %d): %w", failedBatch+1, len(batches), errs[failedBatch])
+	if merged.failedBatches == len(batches) || !batching.AllowPartial {
+		return nil, err
+	}
+
+	metrics.RecordError("critic", "partial_verification")
+	execLog.Warnf("Critic node %s: %d of %d claim batches failed, continuing with %d checked claims: %v",
+		nodeID, merged.failedBatches, len(batches), merged.checked, err)
+	return merged, nil
+}
+
+// verifyBatch sends one Verify request.
+func (e *DAGExecutor) verifyBatch(ctx context.Context, claims []*pb.AtomicClaim, task, runID string) (*pb.VerifyResponse, error) {
+	req := &pb.VerifyRequest{
+		Claims: claims,
+		Task:   task,
+		RunId:  runID,
+	}
+
+	var hints rateLimitHints
+	resp, err := e.serviceClients(ctx).Critic.Verify(ctx, req, hints.callOptions()...)
+	e.applyRateLimitHints("critic", &hints)
+
+	if err != nil {
+		metrics.RecordError("critic", "rpc_failed")
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchRecordingCritic accepts claims with even-numbered statements and
+// records the size of every request and how many overlapped. Requests
+// containing failClaim fail.
+type batchRecordingCritic struct {
+	failClaim string
+
+	mu          sync.Mutex
+	sizes       []int
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *batchRecordingCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.mu.Lock()
+	c.sizes = append(c.sizes, len(req.Claims))
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	var verified int32
+	for _, claim := range req.Claims {
+		if claim.Statement == c.failClaim {
+			return nil, status.Error(codes.ResourceExhausted, "message too large")
+		}
+		var n int
+		fmt.Sscanf(claim.Statement, "claim-%d", &n)
+		valid := n%2 == 0
+		if valid {
+			verified++
+		}
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: valid})
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: verified}, nil
+}
+
+func numberedClaims(n int) []*pb.AtomicClaim {
+	claims := make([]*pb.AtomicClaim, n)
+	for i := range claims {
+		claims[i] = &pb.AtomicClaim{Statement: fmt.Sprintf("claim-%d", i)}
+	}
+	return claims
+}
+
+func TestVerifyClaims_Batching(t *testing.T) {
+	critic := &batchRecordingCritic{}
+	executor := NewDAGExecutor(&clients.ServiceClients{Critic: critic}, 2)
+	executor.SetCriticBatching(CriticBatching{BatchSize: 40, Concurrency: 2})
+
+	verification, err := executor.verifyClaims(context.Background(), "critic1", numberedClaims(250), "verify", "run-batch")
+	if err != nil {
+		t.Fatalf("verifyClaims failed: %v", err)
+	}
+
+	if len(critic.sizes) != 7 {
+		t.Fatalf("Expected 7 Verify requests, got %d: %v", len(critic.sizes), critic.sizes)
+	}
+	total := 0
+	for _, size := range critic.sizes {
+		if size > 40 {
+			t.Errorf("Batch of %d claims exceeds the batch size", size)
+		}
+		total += size
+	}
+	if total != 250 {
+		t.Errorf("Batches covered %d claims, want 250", total)
+	}
+	if critic.maxInFlight > 2 {
+		t.Errorf("%d batches were in flight at once, want at most 2", critic.maxInFlight)
+	}
+
+	// Results are merged in claim order regardless of completion order
+	if len(verification.results) != 250 || verification.verified != 125 || verification.checked != 250 {
+		t.Fatalf("Merged %d results, %d verified of %d checked", len(verification.results), verification.verified, verification.checked)
+	}
+	for i, result := range verification.results {
+		if want := fmt.Sprintf("claim-%d", i); result.Claim.Statement != want {
+			t.Fatalf("Result %d is for %s, want %s", i, result.Claim.Statement, want)
+		}
+	}
+}
+
+func TestVerifyClaims_PartialFailure(t *testing.T) {
+	newExecutor := func(allowPartial bool) *DAGExecutor {
+		critic := &batchRecordingCritic{failClaim: "claim-45"}
+		executor := NewDAGExecutor(&clients.ServiceClients{Critic: critic}, 2)
+		executor.SetCriticBatching(CriticBatching{BatchSize: 20, Concurrency: 1, AllowPartial: allowPartial})
+		return executor
+	}
+
+	_, err := newExecutor(false).verifyClaims(context.Background(), "critic1", numberedClaims(100), "verify", "run-strict")
+	if err == nil || !strings.Contains(err.Error(), "batch 3 of 5") || status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected the failed batch's error, got %v", err)
+	}
+
+	verification, err := newExecutor(true).verifyClaims(context.Background(), "critic1", numberedClaims(100), "verify", "run-partial")
+	if err != nil {
+		t.Fatalf("verifyClaims with partial results failed: %v", err)
+	}
+	if verification.failedBatches != 1 || verification.checked != 80 || len(verification.results) != 80 {
+		t.Errorf("Partial verification = %d failed batches, %d checked, %d results; want 1, 80, 80",
+			verification.failedBatches, verification.checked, len(verification.results))
+	}
+	for _, result := range verification.results {
+		var n int
+		fmt.Sscanf(result.Claim.Statement, "claim-%d", &n)
+		if n >= 40 && n < 60 {
+			t.Errorf("Result for %s from the failed batch was kept", result.Claim.Statement)
+		}
+	}
+}
+
+func TestExecuteCritic_BatchedClaims(t *testing.T) {
+	critic := &batchRecordingCritic{}
+	executor := NewDAGExecutor(&clients.ServiceClients{Critic: critic}, 2)
+	executor.SetCriticBatching(CriticBatching{BatchSize: 10})
+
+	graph := researchCriticGraph("test-critic-batching", false)
+	nodeResults := map[string]*NodeResult{
+		"researcher1": {NodeID: "researcher1", Success: true, Data: numberedClaims(35)},
+	}
+	result := executor.executeCritic(context.Background(), &graph.Nodes[1], graph, nodeResults, "run-critic-batching")
+	if !result.Success {
+		t.Fatalf("Critic node failed: %v", result.Error)
+	}
+	if results, _ := result.Data.([]*pb.CritiqueResult); len(results) != 35 {
+		t.Errorf("Critic node returned %d results, want 35", len(results))
+	}
+	if len(critic.sizes) != 4 {
+		t.Errorf("Expected 4 Verify requests, got %v", critic.sizes)
+	}
+}
@@ -2,11 +2,13 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"strconv"
 	"sync"
 	"time"
 
+	"hdrp/internal/artifacts"
 	"hdrp/internal/clients"
 	"hdrp/internal/concurrency"
 	"hdrp/internal/dag"
@@ -20,17 +22,54 @@ import (
 
 // DAGExecutor orchestrates concurrent DAG node execution.
 type DAGExecutor struct {
-	clients         *clients.ServiceClients
-	maxWorkers      int
-	config          *concurrency.Config
-	rateLimiters    *concurrency.RateLimiterManager
-	lockManager     *concurrency.LockManager
-	retryPolicy     *retry.RetryPolicy
-	circuitBreakers *retry.PerServiceBreakers
-	checkpointStore retry.CheckpointStore
-	retryMetrics    *retry.RetryMetrics
-	storage         storage.Storage // Persistent storage for DAG state
-	mu              sync.RWMutex
+	clients              *clients.ServiceClients
+	maxWorkers           int
+	config               *concurrency.Config
+	rateLimiters         *concurrency.RateLimiterManager
+	lockManager          *concurrency.LockManager
+	retryPolicy          *retry.RetryPolicy
+	nodeRetryPolicies    map[string]*retry.RetryPolicy // Per node type; types not listed use retryPolicy
+	circuitBreakers      *retry.PerServiceBreakers
+	serviceHealth        *retry.ServiceHealthTracker // Rolling success ratio per node type across runs
+	checkpointStore      retry.CheckpointStore
+	breakerStore         retry.BreakerStore // Circuit breaker states kept across restarts; nil without persistent storage
+	stopBreakerSaves     func()             // Stops the periodic breaker state saves; nil when not saving
+	stopRetention        func()             // Stops the periodic cleanup of finished graphs; nil when not cleaning up
+	storage              storage.Storage    // Persistent storage for DAG state
+	eventHandler         EventHandler
+	publisher            EventPublisher // Run lifecycle events for external consumers
+	heartbeatInterval    time.Duration
+	resultMemoryLimit    int                    // Max node results kept in memory per run; <= 0 means unbounded
+	retryUpstream        bool                   // Re-run parents when a node fails on unusable parent output
+	persistNodeResults   bool                   // Store successful results so resumed runs can reuse them
+	resultCache          ResultCache            // Researcher results by type and config; nil disables
+	handlers             map[string]NodeHandler // Node executors by node type
+	unknownTypePolicy    UnknownTypePolicy
+	emptyResultPolicy    EmptyResultPolicy      // Whether empty claims or reports count as failures
+	maxRunAttempts       int                    // Upper bound for RunOptions.MaxAttempts
+	allowBreakerBypass   bool                   // Whether RunOptions may ignore circuit breakers
+	persistQueueSize     int                    // Async transition persistence queue length; <= 0 persists synchronously
+	quarantineThreshold  int                    // Failed resumes before a node is quarantined; <= 0 disables
+	maxConcurrentRetries int                    // Nodes per run allowed to back off at once; <= 0 means unlimited
+	restoreRetryMetrics  bool                   // Rebuild retry metrics from node history on resume
+	nodeTypeAllowlist    *dag.NodeTypeAllowlist // Node types plans may contain; nil allows all
+	depthBoost           dag.DepthBoost         // Scheduling priority boost for deeper nodes
+	failedParentPolicy   dag.FailedParentPolicy // What happens to nodes whose parents failed; "" uses the dag default
+	defaultMaxDepth      int                    // Layer limit for graphs without their own MaxDepth; <= 0 keeps dag.DefaultMaxDepth
+	snapshotInterval     time.Duration          // Period between scheduled snapshots; <= 0 disables
+	deterministic        bool                   // Run every graph in deterministic mode
+	estimateDefaults     EstimateDefaults       // Latencies and costs for Estimate
+	nodeLatencies        *latencyTracker        // Recent successful execution times per node type
+	maxReportBytes       int                    // Report bytes kept in memory before spilling to artifactStore; <= 0 is unbounded
+	artifactStore        artifacts.Store        // Destination for oversized reports
+	requeuePolicy        RequeuePolicy          // Failures returned to the scheduler instead of retried in place
+	criticBatching       CriticBatching         // How critic nodes split claims across Verify requests
+	streamed             *streamedVerifications // Verifications of streamed claims awaiting their critic nodes
+	pauses               *runPauses             // Pause state of executing runs
+	budget               ExecutionBudget        // Limits for runs without their own; zero is unlimited
+
+	runSlots *concurrency.PrioritySemaphore // Worker slots shared by all runs; nil means no global limit
+	mu       sync.RWMutex
 }
 
 // ExecutionResult contains the final DAG execution outcome.
@@ -43,7 +82,17 @@ type ExecutionResult struct {
 	FinalReport    string
 	ArtifactURI    string
 	ErrorMessage   string
-	RetryMetrics   *retry.RetryMetrics // Retry statistics
+	RetryMetrics   *retry.RetryMetrics // Retry statistics for this run
+
+	// PeakResidentResults is the most node results held in memory at once during the run.
+	PeakResidentResults int
+
+	// ReportTruncated is true when FinalReport is only a preview and the full
+	// report is at ArtifactURI.
+	ReportTruncated bool
+
+	// VerificationResults holds aggregated critic output for graphs without a synthesizer.
+	VerificationResults []*pb.CritiqueResult
 }
 
 // NodeResult contains a single node's execution outcome.
@@ -52,13 +101,25 @@ type NodeResult struct {
 	Success bool
 	Data    interface{} // Node-specific output: claims, verification results, etc.
 	Error   error
+
+	// RequeueAfter is set when the node should go back to PENDING after this
+	// delay rather than being marked finished.
+	RequeueAfter time.Duration
+
+	// ReportTruncated is set when a synthesizer's report was spilled to the
+	// artifact store and Data holds only a preview.
+	ReportTruncated bool
 }
 
+// defaultMaxWorkers is how many nodes a run executes at once when no worker
+// count is configured.
+const defaultMaxWorkers = 4
+
 // NewDAGExecutor creates a DAG executor with the specified worker pool size.
 // If maxWorkers <= 0, uses default value of 4.
 func NewDAGExecutor(clients *clients.ServiceClients, maxWorkers int) *DAGExecutor {
 	if maxWorkers <= 0 {
-		maxWorkers = 4 // Default value
+		maxWorkers = defaultMaxWorkers
 	}
 
 	// Create concurrency config with defaults
@@ -75,60 +136,283 @@ func NewDAGExecutor(clients *clients.ServiceClients, maxWorkers int) *DAGExecuto
 	// Initialize lock manager
 	lockManager, err := concurrency.NewLockManager(config)
 	if err != nil {
-		log.Printf("[DAGExecutor] Warning: failed to initialize lock manager: %v", err)
+		execLog.Warnf("failed to initialize lock manager: %v", err)
 		// Continue with nil lock manager - will skip distributed locking
 	}
 
-	// Initialize checkpoint store
-	var checkpointStore retry.CheckpointStore
-	checkpointStore, err = retry.NewFileCheckpointStore("./checkpoints")
+	// Initialize storage for DAG persistence
+	var store storage.Storage
+	sqliteStore, err := storage.NewSQLiteStorage()
 	if err != nil {
-		log.Printf("[DAGExecutor] Warning: failed to initialize checkpoint store: %v", err)
-		checkpointStore = retry.NewInMemoryCheckpointStore()
+		execLog.Warnf("failed to initialize storage: %v. Falling back to in-memory storage; state will not survive a restart.", err)
+		store = storage.NewInMemoryStorage()
+	} else {
+		store = sqliteStore
 	}
 
-	// Initialize storage for DAG persistence
-	store, err := storage.NewSQLiteStorage()
-	if err != nil {
-		log.Printf("[DAGExecutor] Warning: failed to initialize storage: %v. Running in-memory only.", err)
-		store = nil
+	// Initialize checkpoint store, keeping checkpoints in the same database
+	// as the graph state when there is one
+	var checkpointStore retry.CheckpointStore
+	var breakerStore retry.BreakerStore
+	if sqliteStore != nil {
+		checkpointStore = storage.NewSQLiteCheckpointStore(sqliteStore)
+		breakerStore = storage.NewSQLiteBreakerStore(sqliteStore)
+	} else if checkpointStore, err = retry.NewFileCheckpointStore("./checkpoints"); err != nil {
+		execLog.Warnf("failed to initialize checkpoint store: %v", err)
+		checkpointStore = retry.NewInMemoryCheckpointStore()
 	}
 
 	executor := &DAGExecutor{
-		clients:         clients,
-		maxWorkers:      maxWorkers,
-		config:          config,
-		rateLimiters:    concurrency.NewRateLimiterManager(config),
-		lockManager:     lockManager,
-		retryPolicy:     retry.DefaultPolicy(),
-		circuitBreakers: retry.NewPerServiceBreakers(),
-		checkpointStore: checkpointStore,
-		retryMetrics:    retry.NewRetryMetrics(),
-		storage:         store,
+		clients:           clients,
+		maxWorkers:        maxWorkers,
+		config:            config,
+		rateLimiters:      concurrency.NewRateLimiterManager(config),
+		lockManager:       lockManager,
+		retryPolicy:       retry.DefaultPolicy(),
+		circuitBreakers:   retry.NewPerServiceBreakers(),
+		serviceHealth:     retry.NewServiceHealthTracker(retry.DefaultHealthWindow),
+		nodeLatencies:     newLatencyTracker(),
+		checkpointStore:   checkpointStore,
+		breakerStore:      breakerStore,
+		storage:           store,
+		heartbeatInterval: DefaultHeartbeatInterval,
+		unknownTypePolicy: UnknownTypeStrict,
+		maxRunAttempts:    DefaultMaxRunAttempts,
+		publisher:         NopPublisher{},
+		streamed:          newStreamedVerifications(),
+		pauses:            newRunPauses(),
 	}
 
-	if store != nil {
-		log.Printf("[DAGExecutor] Persistent storage enabled")
+	if _, ok := store.(*storage.SQLiteStorage); ok {
+		execLog.Infof("Persistent storage enabled")
 	}
+	executor.handlers = executor.builtinHandlers()
+	executor.circuitBreakers.OnStateChange(recordBreakerStateChange)
+	executor.restoreBreakers()
 
 	return executor
 }
 
+// SetResultMemoryLimit bounds how many completed node results each run keeps
+// in memory. Older results are offloaded to storage and reloaded when a child
+// needs them. Has no effect without a storage backend.
+func (e *DAGExecutor) SetResultMemoryLimit(limit int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resultMemoryLimit = limit
+}
+
+// SetMaxWorkers changes how many nodes each run executes at once, including
+// runs already in progress: they pick up the new limit on their next
+// scheduling iteration. Shrinking doesn't interrupt nodes already running;
+// no new ones start until the run is under the limit. Deterministic runs
+// stay at one worker. n <= 0 restores the default of 4.
+func (e *DAGExecutor) SetMaxWorkers(n int) {
+	if n <= 0 {
+		n = defaultMaxWorkers
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxWorkers = n
+	e.config.MaxWorkers = n
+}
+
+// SetMaxCircuitBreakers caps how many node types get their own circuit
+// breaker; the least recently used are evicted beyond that. max <= 0 keeps
+// retry.DefaultMaxServiceBreakers.
+func (e *DAGExecutor) SetMaxCircuitBreakers(max int) {
+	e.circuitBreakers.SetMaxBreakers(max)
+}
+
+// SetRateLimits limits calls to each service from limits. A service with a
+// QPS set is limited by request rate; otherwise by concurrent calls, where a
+// limit of 0 keeps the current one. Call before executing graphs.
+func (e *DAGExecutor) SetRateLimits(limits *concurrency.Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, l := range []struct {
+		current *int
+		limit   int
+	}{
+		{&e.config.ResearcherRateLimit, limits.ResearcherRateLimit},
+		{&e.config.CriticRateLimit, limits.CriticRateLimit},
+		{&e.config.SynthesizerRateLimit, limits.SynthesizerRateLimit},
+	} {
+		if l.limit > 0 {
+			*l.current = l.limit
+		}
+	}
+	e.config.ResearcherQPS = limits.ResearcherQPS
+	e.config.CriticQPS = limits.CriticQPS
+	e.config.SynthesizerQPS = limits.SynthesizerQPS
+	e.config.RateLimitBurst = limits.RateLimitBurst
+	e.rateLimiters.Configure(e.config)
+}
+
+// SetRetryUpstream enables dependency-aware retry. When a node fails with a
+// retry.UpstreamDataError, the blamed parents are re-run before the node is
+// retried. Each re-run consumes one of the failing node's retry attempts.
+func (e *DAGExecutor) SetRetryUpstream(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.retryUpstream = enabled
+}
+
+// SetAsyncPersistence moves node and graph status persistence off the
+// execution loop onto a background writer with a queue of queueSize
+// transitions, so slow storage doesn't stall scheduling. The loop only
+// blocks when the queue is full. queueSize <= 0 persists synchronously.
+func (e *DAGExecutor) SetAsyncPersistence(queueSize int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.persistQueueSize = queueSize
+}
+
+// SetNodeTypeAllowlist restricts the node types a plan may contain, per
+// tenant or globally. Plans with other types are rejected before any node
+// runs. nil allows every type.
+func (e *DAGExecutor) SetNodeTypeAllowlist(allowlist *dag.NodeTypeAllowlist) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nodeTypeAllowlist = allowlist
+}
+
+// SetDepthBoost raises the scheduling priority of nodes deeper in the graph
+// so final stages run promptly once their dependencies are met. The zero
+// value disables the boost.
+func (e *DAGExecutor) SetDepthBoost(boost dag.DepthBoost) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.depthBoost = boost
+}
+
+// SetFailedParentPolicy sets what happens to nodes whose parents failed:
+// blocked, failed or skipped (see dag.FailedParentPolicy). "" selects
+// dag.DefaultFailedParentPolicy.
+func (e *DAGExecutor) SetFailedParentPolicy(policy dag.FailedParentPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failedParentPolicy = policy
+}
+
+// SetDefaultMaxDepth sets the layer limit for graphs that don't set
+// dag.Graph.MaxDepth themselves. depth <= 0 keeps dag.DefaultMaxDepth.
+func (e *DAGExecutor) SetDefaultMaxDepth(depth int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.defaultMaxDepth = depth
+}
+
+// applyDefaultMaxDepth gives a graph without its own depth limit the
+// executor's default.
+func (e *DAGExecutor) applyDefaultMaxDepth(graph *dag.Graph) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if graph.MaxDepth <= 0 && e.defaultMaxDepth > 0 {
+		graph.MaxDepth = e.defaultMaxDepth
+	}
+}
+
+// ServiceHealth returns the tracker of recent node outcomes per node type.
+func (e *DAGExecutor) ServiceHealth() *retry.ServiceHealthTracker {
+	return e.serviceHealth
+}
+
+// SetRetryPolicy replaces the retry policy used for node execution.
+func (e *DAGExecutor) SetRetryPolicy(policy *retry.RetryPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.retryPolicy = policy
+}
+
+// SetNodeRetryPolicy sets the retry policy for nodes of one type, e.g. more
+// attempts for cheap, idempotent critic calls than for synthesis. A nil
+// policy reverts the type to the executor's default policy.
+func (e *DAGExecutor) SetNodeRetryPolicy(nodeType string, policy *retry.RetryPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if policy == nil {
+		delete(e.nodeRetryPolicies, nodeType)
+		return
+	}
+	if e.nodeRetryPolicies == nil {
+		e.nodeRetryPolicies = make(map[string]*retry.RetryPolicy)
+	}
+	e.nodeRetryPolicies[nodeType] = policy
+}
+
 // Execute runs the DAG to completion with dependency-aware parallel scheduling.
 func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID string) (*ExecutionResult, error) {
-	startTime := time.Now()
-	metrics.IncrementActiveDagExecutions()
-	defer metrics.DecrementActiveDagExecutions()
+	return e.ExecuteWithOptions(ctx, graph, runID, RunOptions{})
+}
+
+// ExecuteWithOptions runs the DAG like Execute, applying per-run retry and
+// circuit breaker overrides. The outcome is persisted so it can be read
+// back with LoadRunResult once the caller has gone.
+func (e *DAGExecutor) ExecuteWithOptions(ctx context.Context, graph *dag.Graph, runID string, opts RunOptions) (*ExecutionResult, error) {
+	startedAt := time.Now()
 
 	// Start tracing span for entire DAG execution
 	ctx, span := metrics.StartSpan(ctx, "dag.execute",
 		attribute.String("graph.id", graph.ID),
 		attribute.String("run.id", runID),
-		attribute.Int("max.workers", e.maxWorkers),
 	)
 	defer span.End()
 
-	log.Printf("[Executor] Starting execution of graph %s with max %d workers", graph.ID, e.maxWorkers)
+	result, err := e.execute(ctx, graph, runID, opts)
+	recordRunSpan(ctx, result, err)
+	e.saveRunResult(graph, runID, startedAt, result, err)
+	return result, err
+}
+
+// execute runs the DAG for ExecuteWithOptions.
+func (e *DAGExecutor) execute(ctx context.Context, graph *dag.Graph, runID string, opts RunOptions) (*ExecutionResult, error) {
+	startTime := time.Now()
+	metrics.IncrementActiveDagExecutions()
+	defer metrics.DecrementActiveDagExecutions()
+	defer e.streamed.discardRun(runID)
+	gate, unregister := e.pauses.register(runID, graph.ID)
+	defer unregister()
+
+	policy := e.resolveRunPolicy(opts)
+	policy.priority = runPriority(graph, opts)
+
+	metrics.AddSpanAttributes(ctx,
+		attribute.Int("max.workers", policy.workers),
+		attribute.Bool("deterministic", policy.deterministic),
+	)
+
+	execLog.Infof("Starting execution of graph %s with max %d workers", graph.ID, policy.workers)
+	if policy.deterministic {
+		execLog.Infof("Deterministic mode: graph %s runs one node at a time", graph.ID)
+	}
+
+	// Record the run on the graph so it can be found by run ID after a restart
+	if graph.Metadata == nil {
+		graph.Metadata = make(map[string]string)
+	}
+	if graph.Metadata["run_id"] == "" {
+		graph.Metadata["run_id"] = runID
+	}
+	if opts.Priority != nil {
+		graph.Metadata[MetadataPriority] = strconv.Itoa(policy.priority)
+	}
+	e.applyDefaultMaxDepth(graph)
+	if graph.MaxDepth > 0 {
+		graph.Metadata[dag.MetadataMaxDepth] = strconv.Itoa(graph.MaxDepth)
+	}
+
+	// Nodes inherit graph-level config defaults before anything is persisted
+	graph.ApplyConfigDefaults()
+
+	// Rejected plans are never persisted
+	e.mu.RLock()
+	allowlist := e.nodeTypeAllowlist
+	e.mu.RUnlock()
+	if err := graph.ValidateNodeTypes(allowlist); err != nil {
+		return nil, fmt.Errorf("graph rejected: %w", err)
+	}
 
 	// Attach storage to graph if available
 	if e.storage != nil {
@@ -136,43 +420,122 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 
 		// Persist initial graph state
 		if err := e.persistInitialGraph(graph); err != nil {
-			log.Printf("[Executor] Warning: failed to persist initial graph: %v", err)
+			execLog.Warnf("failed to persist initial graph: %v", err)
+		}
+
+		// Transitions from here on may be persisted in the background. The
+		// writer is drained before returning so callers see the final state.
+		e.mu.RLock()
+		queueSize := e.persistQueueSize
+		e.mu.RUnlock()
+		if queueSize > 0 {
+			writer := storage.NewAsyncWriter(e.storage, queueSize)
+			graph.SetStorage(writer)
+			defer writer.Stop()
 		}
+
+		// Snapshot on a schedule too, so stalled graphs stay quick to recover
+		defer e.startPeriodicSnapshots(graph.ID)()
 	}
 
 	if err := graph.Validate(); err != nil {
 		return nil, fmt.Errorf("graph validation failed: %w", err)
 	}
 
+	e.mu.RLock()
+	graph.SetDepthBoost(e.depthBoost)
+	graph.SetFailedParentPolicy(e.failedParentPolicy)
+	e.mu.RUnlock()
+
 	if err := graph.SetStatus(dag.StatusRunning); err != nil {
 		return nil, fmt.Errorf("failed to set graph status: %w", err)
 	}
+	e.publishRunStarted(graph, runID)
+	defer e.publishRunFinished(ctx, graph, runID, startTime)
+
+	e.mu.RLock()
+	resultLimit := e.resultMemoryLimit
+	persistResults := e.persistNodeResults
+	e.mu.RUnlock()
+	nodeResults := newResultSet(graph.ID, resultLimit, e.storage)
+	nodeResults.persist = persistResults
+
+	// Nodes kept by ResumeGraph already have their results in storage
+	for _, node := range graph.Nodes {
+		if node.Status == dag.StatusSucceeded {
+			nodeResults.Restore(node.ID)
+		}
+	}
+	restoreNodeOutputs(graph, nodeResults)
 
 	if err := graph.EvaluateReadiness(); err != nil {
 		return nil, fmt.Errorf("failed to evaluate readiness: %w", err)
 	}
 
-	nodeResults := make(map[string]*NodeResult)
-	var resultsMu sync.RWMutex
+	// Retry metrics are scoped to this run so concurrent runs reusing node IDs don't collide
+	runMetrics := opts.RetryMetrics
+	if runMetrics == nil {
+		runMetrics = retry.NewRetryMetrics()
+	}
 
-	// Channel for node completion notifications
-	resultChan := make(chan *NodeResult, e.maxWorkers)
-	defer close(resultChan)
+	// Enforce the run's budget. Nodes run under nodeCtx so that exceeding
+	// it can cancel the ones in flight.
+	e.mu.RLock()
+	limits := e.budget
+	costs := e.estimateDefaults.Costs
+	e.mu.RUnlock()
+	if opts.Budget != nil {
+		limits = *opts.Budget
+	}
+	policy.budget = newRunBudget(limits, graph, costs)
+	defer policy.budget.stop()
+	nodeCtx, cancelNodes := context.WithCancel(ctx)
+	defer cancelNodes()
+
+	// Channel for node completion notifications, buffered for the workers
+	// the run starts with. It is deliberately left open: nodes still running
+	// after an early return (e.g. on cancellation) must be able to deliver
+	// their result without panicking. Since SetMaxWorkers may have grown the
+	// run past the buffer, their results are drained in the background.
+	resultChan := make(chan *NodeResult, policy.workers)
 
 	// Track number of nodes currently executing
 	pendingCount := 0
+	defer func() {
+		if pendingCount > 0 {
+			go drainResults(resultChan, pendingCount)
+		}
+	}()
+
+	// stopNodes cancels the nodes still in flight and waits for them to
+	// return, so none of them touches the graph once the run is over
+	stopNodes := func() {
+		cancelNodes()
+		for ; pendingCount > 0; pendingCount-- {
+			<-resultChan
+		}
+	}
+
+	// Nodes requeued after a rate-limit style failure are handed back on
+	// requeueChan once their cooldown passes. Stopping the run stops the waits.
+	requeueCtx, stopRequeues := context.WithCancel(ctx)
+	defer stopRequeues()
+	requeueChan := make(chan string)
+	requeued := 0
 
 	// Execution loop
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+			stopNodes()
+			return nil, e.cancelRun(ctx, graph)
 		default:
 		}
 
-		// Schedule a batch of ready nodes
-		availableSlots := e.maxWorkers - pendingCount
-		if availableSlots > 0 {
+		// Schedule a batch of ready nodes, unless the run is paused
+		paused := gate.paused()
+		availableSlots := e.runWorkers(policy) - pendingCount
+		if availableSlots > 0 && paused == nil {
 			batch, err := graph.ScheduleNextBatch(availableSlots)
 			if err != nil {
 				return nil, fmt.Errorf("scheduling failed: %w", err)
@@ -181,20 +544,34 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 			// Launch goroutines for each scheduled node
 			for _, node := range batch {
 				pendingCount++
-				go e.executeNodeAsync(ctx, node, graph, nodeResults, &resultsMu, runID, resultChan)
+				go e.executeNodeAsync(nodeCtx, node, graph, nodeResults, runID, runMetrics, policy, resultChan)
 			}
 		}
 
-		// Wait for at least one node to complete if any are pending
-		if pendingCount > 0 {
+		// Wait for at least one node to complete, a requeued node to cool
+		// down, or a paused run with ready nodes to resume
+		waitResume := paused != nil && graph.GetReadyNodesCount() > 0
+		if pendingCount > 0 || requeued > 0 || waitResume {
 			select {
 			case result := <-resultChan:
 				pendingCount--
 
-				// Store result
-				resultsMu.Lock()
-				nodeResults[result.NodeID] = result
-				resultsMu.Unlock()
+				if result.RequeueAfter > 0 {
+					// Park the node until its cooldown passes; its slot is free now
+					if err := graph.SetNodeStatus(result.NodeID, dag.StatusRetrying); err != nil {
+						return nil, fmt.Errorf("failed to update node status: %w", err)
+					}
+					execLog.Infof("Node %s requeued for %v: %v", result.NodeID, result.RequeueAfter, result.Error)
+					requeued++
+					go waitRequeue(requeueCtx, result.NodeID, result.RequeueAfter, requeueChan)
+					break
+				}
+
+				// Store result, and the outputs its edge conditions test
+				nodeResults.Put(result)
+				if result.Success {
+					graph.SetNodeOutputs(result.NodeID, nodeOutputs(result))
+				}
 
 				// Update graph state
 				var newStatus dag.Status
@@ -202,32 +579,55 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 					newStatus = dag.StatusSucceeded
 				} else {
 					newStatus = dag.StatusFailed
-					log.Printf("[Executor] Node %s failed: %v", result.NodeID, result.Error)
+					execLog.Errorf("Node %s failed: %v", result.NodeID, result.Error)
 				}
 
 				if err := graph.SetNodeStatus(result.NodeID, newStatus); err != nil {
 					return nil, fmt.Errorf("failed to update node status: %w", err)
 				}
+				e.publishNodeCompleted(graph, runID, result, newStatus)
 
 				// Re-evaluate readiness to unblock dependent nodes
 				if err := graph.EvaluateReadiness(); err != nil {
 					return nil, fmt.Errorf("failed to re-evaluate readiness: %w", err)
 				}
 
+			case nodeID := <-requeueChan:
+				requeued--
+				if err := graph.SetNodeStatus(nodeID, dag.StatusPending); err != nil {
+					return nil, fmt.Errorf("failed to requeue node %s: %w", nodeID, err)
+				}
+
+			case <-paused:
+				// Resumed; schedule on the next iteration
+
+			case <-policy.budget.done():
+				// Handled below
+
 			case <-ctx.Done():
-				return nil, fmt.Errorf("execution cancelled: %w", ctx.Err())
+				stopNodes()
+				return nil, e.cancelRun(ctx, graph)
 			}
 		}
 
+		// Stop a run over budget, cancelling the nodes still in flight and
+		// waiting for them to return
+		select {
+		case <-policy.budget.done():
+			stopNodes()
+			return e.stopOverBudget(graph, policy.budget, runMetrics), nil
+		default:
+		}
+
 		// Check termination conditions
-		if pendingCount == 0 && graph.GetReadyNodesCount() == 0 {
+		if pendingCount == 0 && requeued == 0 && graph.GetReadyNodesCount() == 0 {
 			// No more work to schedule and nothing running
 			allDone := true
 			anyFailed := false
 			succeededNodes := []string{}
 			failedNodes := make(map[string]string)
 
-			for _, n := range graph.Nodes {
+			for _, n := range graph.NodeSnapshot() {
 				if n.Status == dag.StatusPending || n.Status == dag.StatusRunning || n.Status == dag.StatusBlocked || n.Status == dag.StatusRetrying {
 					allDone = false
 					break
@@ -253,14 +653,17 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 							result.SucceededNodes = succeededNodes
 							result.FailedNodes = failedNodes
 							result.ErrorMessage = fmt.Sprintf("%d nodes failed, %d succeeded", len(failedNodes), len(succeededNodes))
-							result.RetryMetrics = e.retryMetrics
-							log.Printf("[Executor] Graph completed with partial success: %d succeeded, %d failed", len(succeededNodes), len(failedNodes))
+							result.RetryMetrics = runMetrics
+							result.PeakResidentResults = nodeResults.Peak()
+							e.finishGraph(graph, dag.StatusFailed)
+							execLog.Warnf("Graph completed with partial success: %d succeeded, %d failed", len(succeededNodes), len(failedNodes))
 							metrics.RecordDAGExecution(duration, "partial_success")
 							metrics.AddSpanAttributes(ctx, attribute.Bool("partial_success", true))
 							return result, nil
 						}
 					}
 					// Total failure
+					e.finishGraph(graph, dag.StatusFailed)
 					metrics.RecordDAGExecution(duration, "failed")
 					metrics.RecordError("executor", "dag_execution_failed")
 					metrics.AddSpanAttributes(ctx,
@@ -274,7 +677,7 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 						SucceededNodes: succeededNodes,
 						FailedNodes:    failedNodes,
 						ErrorMessage:   fmt.Sprintf("All critical nodes failed: %d total failures", len(failedNodes)),
-						RetryMetrics:   e.retryMetrics,
+						RetryMetrics:   runMetrics,
 					}, nil
 				}
 
@@ -284,8 +687,10 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 					return nil, err
 				}
 				result.SucceededNodes = succeededNodes
-				result.RetryMetrics = e.retryMetrics
-				log.Printf("[Executor] Graph completed successfully: %d nodes", len(succeededNodes))
+				result.RetryMetrics = runMetrics
+				result.PeakResidentResults = nodeResults.Peak()
+				e.finishGraph(graph, dag.StatusSucceeded)
+				execLog.Infof("Graph completed successfully: %d nodes", len(succeededNodes))
 				metrics.RecordDAGExecution(duration, "success")
 				metrics.AddSpanAttributes(ctx,
 					attribute.Bool("success", true),
@@ -295,17 +700,19 @@ func (e *DAGExecutor) Execute(ctx context.Context, graph *dag.Graph, runID strin
 			}
 
 			// Deadlock detected: no work available but not all nodes completed
+			e.finishGraph(graph, dag.StatusFailed)
 			return &ExecutionResult{
 				GraphID:      graph.ID,
 				Success:      false,
 				ErrorMessage: "Execution deadlocked: nodes are blocked",
-				RetryMetrics: e.retryMetrics,
+				RetryMetrics: runMetrics,
 			}, nil
 		}
 	}
 }
 
 // executeNode dispatches to type-specific execution handlers.
+// nodeResults must contain the results of the node's completed parents.
 func (e *DAGExecutor) executeNode(
 	ctx context.Context,
 	node *dag.Node,
@@ -318,39 +725,40 @@ func (e *DAGExecutor) executeNode(
 		attribute.String("node.id", node.ID),
 		attribute.String("node.type", node.Type),
 		attribute.String("run.id", runID),
+		attribute.Int("node.attempt", attemptFromContext(ctx)),
 	)
 	defer span.End()
 
 	startTime := time.Now()
 	var result *NodeResult
 
-	switch node.Type {
-	case "researcher":
-		result = e.executeResearcher(ctx, node, runID)
-	case "critic":
-		result = e.executeCritic(ctx, node, graph, nodeResults, runID)
-	case "synthesizer":
-		result = e.executeSynthesizer(ctx, node, graph, nodeResults, runID)
-	default:
-		result = &NodeResult{
-			NodeID:  node.ID,
-			Success: false,
-			Error:   fmt.Errorf("unknown node type: %s", node.Type),
+	if handler, ok := e.nodeHandler(node.Type); ok {
+		result = handler(ctx, node, graph, nodeResults, runID)
+	} else {
+		result = e.executeUnknownNode(node, graph, nodeResults)
+	}
+	if result.Success {
+		if rejected := e.validateResult(node.Type, result); rejected != nil {
+			result = rejected
 		}
-		metrics.RecordError("executor", "unknown_node_type")
 	}
 
 	// Record metrics
 	duration := time.Since(startTime).Seconds()
 	status := "success"
-	if !result.Success {
+	outcome := outcomeSucceeded
+	if result.Success {
+		e.nodeLatencies.Record(serviceType(node.Type), time.Since(startTime))
+	} else {
 		status = "failed"
+		outcome = outcomeFailed
 		metrics.RecordSpanError(ctx, result.Error)
 		metrics.AddSpanAttributes(ctx, attribute.String("error", result.Error.Error()))
 	}
 	metrics.RecordNodeExecution(node.Type, status)
 	metrics.AddSpanAttributes(ctx,
 		attribute.Bool("success", result.Success),
+		attribute.String("node.outcome", outcome),
 		attribute.Float64("duration_seconds", duration),
 	)
 
@@ -358,7 +766,7 @@ func (e *DAGExecutor) executeNode(
 }
 
 // executeResearcher invokes the Researcher service via gRPC.
-func (e *DAGExecutor) executeResearcher(ctx context.Context, node *dag.Node, runID string) *NodeResult {
+func (e *DAGExecutor) executeResearcher(ctx context.Context, node *dag.Node, graph *dag.Graph, runID string) *NodeResult {
 	query, ok := node.Config["query"]
 	if !ok {
 		return &NodeResult{
@@ -375,11 +783,7 @@ func (e *DAGExecutor) executeResearcher(ctx context.Context, node *dag.Node, run
 		Config:       node.Config,
 	}
 
-	startTime := time.Now()
-	resp, err := e.clients.Researcher.Research(ctx, req)
-	duration := time.Since(startTime).Seconds()
-	metrics.RecordRPCLatency("researcher", "Research", duration, err == nil)
-
+	claims, err := e.research(ctx, node, graph, req)
 	if err != nil {
 		metrics.RecordError("researcher", "rpc_failed")
 		return &NodeResult{
@@ -389,15 +793,15 @@ func (e *DAGExecutor) executeResearcher(ctx context.Context, node *dag.Node, run
 		}
 	}
 
-	claimCount := len(resp.Claims)
-	log.Printf("[Executor] Researcher node %s extracted %d claims", node.ID, claimCount)
+	claimCount := len(claims)
+	execLog.Infof("Researcher node %s extracted %d claims", node.ID, claimCount)
 	metrics.RecordClaimExtracted(runID, node.ID, claimCount)
 	metrics.AddSpanAttributes(ctx, attribute.Int("claims.extracted", claimCount))
 
 	return &NodeResult{
 		NodeID:  node.ID,
 		Success: true,
-		Data:    resp.Claims,
+		Data:    claims,
 	}
 }
 
@@ -419,6 +823,9 @@ func (e *DAGExecutor) executeCritic(
 	}
 
 	var allClaims []*pb.AtomicClaim
+	var emptyParents []string
+	var parentIDs []string
+	parentClaims := make(map[string][]*pb.AtomicClaim)
 	for _, edge := range graph.Edges {
 		if edge.To == node.ID {
 			parentResult, ok := nodeResults[edge.From]
@@ -430,35 +837,41 @@ func (e *DAGExecutor) executeCritic(
 				}
 			}
 
-			if claims, ok := parentResult.Data.([]*pb.AtomicClaim); ok {
-				allClaims = append(allClaims, claims...)
+			claims, _ := parentResult.Data.([]*pb.AtomicClaim)
+			if len(claims) == 0 {
+				emptyParents = append(emptyParents, edge.From)
 			}
+			allClaims = append(allClaims, claims...)
+			parentIDs = append(parentIDs, edge.From)
+			parentClaims[edge.From] = claims
 		}
 	}
 
-	req := &pb.VerifyRequest{
-		Claims: allClaims,
-		Task:   task,
-		RunId:  runID,
+	// Nothing to verify is an upstream problem, not something a critic retry can fix.
+	// Without upstream retry the critic still runs on the empty set as before.
+	if len(allClaims) == 0 && len(emptyParents) > 0 && e.upstreamRetryEnabled() {
+		return &NodeResult{
+			NodeID:  node.ID,
+			Success: false,
+			Error: &retry.UpstreamDataError{
+				NodeIDs: emptyParents,
+				Reason:  "no claims to verify",
+			},
+		}
 	}
 
-	startTime := time.Now()
-	resp, err := e.clients.Critic.Verify(ctx, req)
-	duration := time.Since(startTime).Seconds()
-	metrics.RecordRPCLatency("critic", "Verify", duration, err == nil)
-
+	verification, err := e.verifyParentClaims(ctx, node.ID, parentIDs, parentClaims, allClaims, task, runID)
 	if err != nil {
-		metrics.RecordError("critic", "rpc_failed")
 		return &NodeResult{
 			NodeID:  node.ID,
 			Success: false,
-			Error:   fmt.Errorf("critic RPC failed: %w", err),
+			Error:   err,
 		}
 	}
 
-	verifiedCount := int(resp.VerifiedCount)
-	rejectedCount := len(allClaims) - verifiedCount
-	log.Printf("[Executor] Critic node %s verified %d/%d claims", node.ID, verifiedCount, len(allClaims))
+	verifiedCount := verification.verified
+	rejectedCount := verification.checked - verifiedCount
+	execLog.Infof("Critic node %s verified %d/%d claims", node.ID, verifiedCount, verification.checked)
 	metrics.RecordClaimVerified(runID, node.ID, verifiedCount)
 	metrics.RecordClaimRejected(runID, node.ID, rejectedCount)
 	metrics.AddSpanAttributes(ctx,
@@ -466,11 +879,14 @@ func (e *DAGExecutor) executeCritic(
 		attribute.Int("claims.verified", verifiedCount),
 		attribute.Int("claims.rejected", rejectedCount),
 	)
+	if verification.failedBatches > 0 {
+		metrics.AddSpanAttributes(ctx, attribute.Int("claims.unchecked", len(allClaims)-verification.checked))
+	}
 
 	return &NodeResult{
 		NodeID:  node.ID,
 		Success: true,
-		Data:    resp.Results,
+		Data:    verification.results,
 	}
 }
 
@@ -512,11 +928,7 @@ func (e *DAGExecutor) executeSynthesizer(
 		RunId:               runID,
 	}
 
-	startTime := time.Now()
-	resp, err := e.clients.Synthesizer.Synthesize(ctx, req)
-	duration := time.Since(startTime).Seconds()
-	metrics.RecordRPCLatency("synthesizer", "Synthesize", duration, err == nil)
-
+	resp, truncated, err := e.synthesize(ctx, node, graph.ID, req)
 	if err != nil {
 		metrics.RecordError("synthesizer", "rpc_failed")
 		return &NodeResult{
@@ -527,39 +939,60 @@ func (e *DAGExecutor) executeSynthesizer(
 	}
 
 	reportSize := len(resp.Report)
-	log.Printf("[Executor] Synthesizer node %s generated report (%d chars)", node.ID, reportSize)
+	execLog.Infof("Synthesizer node %s generated report (%d chars)", node.ID, reportSize)
 	metrics.AddSpanAttributes(ctx,
 		attribute.Int("report.size_chars", reportSize),
 		attribute.Int("verification_results.count", len(allResults)),
 	)
 
 	return &NodeResult{
-		NodeID:  node.ID,
-		Success: true,
-		Data:    resp,
+		NodeID:          node.ID,
+		Success:         true,
+		Data:            resp,
+		ReportTruncated: truncated,
 	}
 }
 
 // extractFinalResult retrieves the report from completed synthesizer nodes.
-func (e *DAGExecutor) extractFinalResult(graph *dag.Graph, nodeResults map[string]*NodeResult) (*ExecutionResult, error) {
+// Graphs without a synthesizer by design succeed with the aggregated critic
+// results; graphs whose synthesizer did not produce output fail.
+func (e *DAGExecutor) extractFinalResult(graph *dag.Graph, nodeResults *resultSet) (*ExecutionResult, error) {
+	hasSynthesizer := false
 	for _, node := range graph.Nodes {
-		if node.Type == "synthesizer" && node.Status == dag.StatusSucceeded {
-			result, ok := nodeResults[node.ID]
-			if !ok || !result.Success {
-				continue
-			}
+		// A synthesizer on a branch that wasn't taken doesn't count
+		if serviceType(node.Type) != "synthesizer" || node.Status == dag.StatusSkipped {
+			continue
+		}
+		hasSynthesizer = true
+		if node.Status != dag.StatusSucceeded {
+			continue
+		}
 
-			if synthResp, ok := result.Data.(*pb.SynthesizeResponse); ok {
-				return &ExecutionResult{
-					GraphID:     graph.ID,
-					Success:     true,
-					FinalReport: synthResp.Report,
-					ArtifactURI: synthResp.ArtifactUri,
-				}, nil
-			}
+		result, ok := nodeResults.Get(node.ID)
+		if !ok || !result.Success {
+			continue
+		}
+
+		if synthResp, ok := result.Data.(*pb.SynthesizeResponse); ok {
+			return &ExecutionResult{
+				GraphID:         graph.ID,
+				Success:         true,
+				FinalReport:     synthResp.Report,
+				ArtifactURI:     synthResp.ArtifactUri,
+				ReportTruncated: result.ReportTruncated,
+			}, nil
 		}
 	}
 
+	if !hasSynthesizer {
+		execLog.Infof("Graph %s has no synthesizer, returning aggregated critic results", graph.ID)
+		return &ExecutionResult{
+			GraphID:             graph.ID,
+			Success:             true,
+			VerificationResults: collectCritiqueResults(graph, nodeResults),
+		}, nil
+	}
+
 	return &ExecutionResult{
 		GraphID:      graph.ID,
 		Success:      false,
@@ -567,6 +1000,32 @@ func (e *DAGExecutor) extractFinalResult(graph *dag.Graph, nodeResults map[strin
 	}, nil
 }
 
+// collectCritiqueResults gathers verification results from succeeded critic nodes in graph order.
+func collectCritiqueResults(graph *dag.Graph, nodeResults *resultSet) []*pb.CritiqueResult {
+	var all []*pb.CritiqueResult
+	for _, node := range graph.Nodes {
+		if serviceType(node.Type) != "critic" || node.Status != dag.StatusSucceeded {
+			continue
+		}
+		result, ok := nodeResults.Get(node.ID)
+		if !ok || !result.Success {
+			continue
+		}
+		if results, ok := result.Data.([]*pb.CritiqueResult); ok {
+			all = append(all, results...)
+		}
+	}
+	return all
+}
+
+// finishGraph records a graph's terminal status so it isn't mistaken for an
+// abandoned run during recovery.
+func (e *DAGExecutor) finishGraph(graph *dag.Graph, status dag.Status) {
+	if err := graph.SetStatus(status); err != nil {
+		execLog.Warnf("failed to set final status for graph %s: %v", graph.ID, err)
+	}
+}
+
 // RecoverGraph attempts to recover a graph from persistent storage.
 // Returns the recovered graph or nil if no recovery data exists.
 func (e *DAGExecutor) RecoverGraph(graphID string) (*dag.Graph, error) {
@@ -574,19 +1033,32 @@ func (e *DAGExecutor) RecoverGraph(graphID string) (*dag.Graph, error) {
 		return nil, fmt.Errorf("no storage backend available")
 	}
 
-	log.Printf("[Executor] Attempting to recover graph %s from storage", graphID)
+	execLog.Infof("Attempting to recover graph %s from storage", graphID)
 
 	graph := dag.NewGraphWithStorage(graphID, e.storage)
 	if err := graph.LoadFromStorage(graphID); err != nil {
 		return nil, fmt.Errorf("failed to load graph from storage: %w", err)
 	}
 
-	log.Printf("[Executor] Successfully recovered graph %s with %d nodes (status: %s)",
+	execLog.Infof("Successfully recovered graph %s with %d nodes (status: %s)",
 		graphID, len(graph.Nodes), graph.Status)
 
 	return graph, nil
 }
 
+// ErrIntegrityUnsupported is returned by CheckStorageIntegrity when the
+// storage backend can't audit itself.
+var ErrIntegrityUnsupported = errors.New("storage backend does not support integrity checks")
+
+// CheckStorageIntegrity runs a read-only consistency scan of persisted state.
+func (e *DAGExecutor) CheckStorageIntegrity() (*storage.IntegrityReport, error) {
+	checker, ok := e.storage.(storage.IntegrityChecker)
+	if !ok {
+		return nil, ErrIntegrityUnsupported
+	}
+	return checker.CheckIntegrity()
+}
+
 // persistInitialGraph saves the initial graph state to storage.
 func (e *DAGExecutor) persistInitialGraph(graph *dag.Graph) error {
 	if e.storage == nil {
@@ -603,13 +1075,12 @@ func (e *DAGExecutor) persistInitialGraph(graph *dag.Graph) error {
 		return fmt.Errorf("failed to save graph: %w", err)
 	}
 
-	// Log graph creation to WAL
-	payload := &storage.CreateGraphPayload{
-		Graph: *graphState,
-	}
-	if err := e.storage.LogMutation(graph.ID, storage.MutationCreateGraph, payload); err != nil {
-		return fmt.Errorf("failed to log graph creation: %w", err)
-	}
+	// Log the creation, nodes and edges to the WAL as one batch
+	mutations := make([]storage.MutationRequest, 0, 1+len(graph.Nodes)+len(graph.Edges))
+	mutations = append(mutations, storage.MutationRequest{
+		Type:    storage.MutationCreateGraph,
+		Payload: &storage.CreateGraphPayload{Graph: *graphState},
+	})
 
 	// Save all nodes
 	for i := range graph.Nodes {
@@ -622,37 +1093,33 @@ func (e *DAGExecutor) persistInitialGraph(graph *dag.Graph) error {
 			Depth:          graph.Nodes[i].Depth,
 			RetryCount:     graph.Nodes[i].RetryCount,
 			LastError:      graph.Nodes[i].LastError,
+			Phase:          graph.Nodes[i].Phase,
 		}
 		if err := e.storage.SaveNode(graph.ID, nodeState); err != nil {
 			return fmt.Errorf("failed to save node %s: %w", graph.Nodes[i].ID, err)
 		}
-
-		// Log node creation to WAL
-		addPayload := &storage.AddNodePayload{
-			Node: *nodeState,
-		}
-		if err := e.storage.LogMutation(graph.ID, storage.MutationAddNode, addPayload); err != nil {
-			log.Printf("[Executor] Warning: failed to log node creation: %v", err)
-		}
+		mutations = append(mutations, storage.MutationRequest{
+			Type:    storage.MutationAddNode,
+			Payload: &storage.AddNodePayload{Node: *nodeState},
+		})
 	}
 
 	// Save all edges
 	for _, edge := range graph.Edges {
-		if err := e.storage.SaveEdge(graph.ID, edge.From, edge.To); err != nil {
+		if err := e.storage.SaveConditionalEdge(graph.ID, edge.From, edge.To, edge.Condition); err != nil {
 			return fmt.Errorf("failed to save edge %s->%s: %w", edge.From, edge.To, err)
 		}
+		mutations = append(mutations, storage.MutationRequest{
+			Type:    storage.MutationAddEdge,
+			Payload: &storage.AddEdgePayload{From: edge.From, To: edge.To, Condition: edge.Condition},
+		})
+	}
 
-		// Log edge creation to WAL
-		edgePayload := &storage.AddEdgePayload{
-			From: edge.From,
-			To:   edge.To,
-		}
-		if err := e.storage.LogMutation(graph.ID, storage.MutationAddEdge, edgePayload); err != nil {
-			log.Printf("[Executor] Warning: failed to log edge creation: %v", err)
-		}
+	if err := e.storage.LogMutations(graph.ID, mutations); err != nil {
+		return fmt.Errorf("failed to log graph creation: %w", err)
 	}
 
-	log.Printf("[Executor] Persisted initial graph %s with %d nodes and %d edges",
+	execLog.Infof("Persisted initial graph %s with %d nodes and %d edges",
 		graph.ID, len(graph.Nodes), len(graph.Edges))
 
 	return nil
@@ -660,6 +1127,16 @@ func (e *DAGExecutor) persistInitialGraph(graph *dag.Graph) error {
 
 // Close releases resources held by the executor.
 func (e *DAGExecutor) Close() error {
+	e.stopSavingBreakers()
+	e.stopGraphRetention()
+
+	e.mu.RLock()
+	publisher := e.publisher
+	e.mu.RUnlock()
+	if err := publisher.Close(); err != nil {
+		execLog.Warnf("failed to close event publisher: %v", err)
+	}
+
 	if e.storage != nil {
 		return e.storage.Close()
 	}
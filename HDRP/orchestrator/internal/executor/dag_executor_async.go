@@ -3,179 +3,454 @@ package executor
 import (
 	"context"
 	"fmt"
-	"log"
-	"sync"
 	"time"
 
 	"hdrp/internal/dag"
+	"hdrp/internal/metrics"
 	"hdrp/internal/retry"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// resultChannelWaitWarning is how long a node may wait to hand off its
+// result before the delay is logged.
+const resultChannelWaitWarning = time.Second
+
 // executeNodeAsync wraps executeNode to run it asynchronously with retry logic.
 func (e *DAGExecutor) executeNodeAsync(
 	ctx context.Context,
 	node *dag.Node,
 	graph *dag.Graph,
-	nodeResults map[string]*NodeResult,
-	resultsMu *sync.RWMutex,
+	nodeResults *resultSet,
 	runID string,
+	runMetrics *retry.RetryMetrics,
+	policy runPolicy,
 	resultChan chan<- *NodeResult,
 ) {
-	log.Printf("[Executor] Executing node %s (type: %s)", node.ID, node.Type)
+	execLog.Debugf("Executing node %s (type: %s)", node.ID, node.Type)
+
+	// The span covers the node from waiting on its lock and slot to its last
+	// retry. Each attempt is a node.execute span beneath it. The span ends
+	// and the phase is cleared before the result is handed off, so neither
+	// touches the graph once the run loop has the result.
+	ctx, span := metrics.StartSpan(ctx, "node.run",
+		attribute.String("node.id", node.ID),
+		attribute.String("node.type", node.Type),
+		attribute.String("graph.id", graph.ID),
+		attribute.String("run.id", runID),
+	)
+	attempts := 0
+	finish := func(result *NodeResult) {
+		setNodePhase(graph, node.ID, "")
+		recordNodeSpan(ctx, result, attempts)
+		span.End()
+		sendResult(resultChan, result)
+	}
 
-	// Acquire distributed lock if configured
+	// Acquire distributed lock if configured. Locks are scoped to the run so
+	// independent graphs reusing node IDs don't contend.
+	lockKey := runID + "/" + node.ID
 	if e.lockManager != nil {
-		acquired, err := e.lockManager.AcquireNodeLockWithRetry(ctx, node.ID, 3)
+		setNodePhase(graph, node.ID, dag.PhaseWaitingLock)
+		acquired, err := e.lockManager.AcquireNodeLockWithRetry(ctx, lockKey, 3)
 		if err != nil {
-			resultChan <- &NodeResult{
+			finish(&NodeResult{
 				NodeID:  node.ID,
 				Success: false,
 				Error:   fmt.Errorf("failed to acquire lock: %w", err),
-			}
+			})
 			return
 		}
 		if !acquired {
-			resultChan <- &NodeResult{
+			finish(&NodeResult{
 				NodeID:  node.ID,
 				Success: false,
 				Error:   fmt.Errorf("node already being executed by another instance"),
-			}
+			})
 			return
 		}
 		defer func() {
-			if err := e.lockManager.ReleaseNodeLock(ctx, node.ID); err != nil {
-				log.Printf("[Executor] Warning: failed to release lock for node %s: %v", node.ID, err)
+			// Release even when the run was cancelled, so the lock doesn't
+			// linger until its TTL expires
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.config.LockTimeout)
+			defer cancel()
+			if err := e.lockManager.ReleaseNodeLock(releaseCtx, lockKey); err != nil {
+				execLog.Warnf("failed to release lock for node %s: %v", node.ID, err)
 			}
 		}()
 	}
 
-	// Acquire rate limit token
-	limiter := e.rateLimiters.GetLimiter(node.Type)
-	if err := limiter.Acquire(ctx); err != nil {
-		resultChan <- &NodeResult{
+	// Wait for a shared worker slot; other runs' nodes compete by priority
+	setNodePhase(graph, node.ID, dag.PhaseWaitingSlot)
+	releaseSlot, err := e.acquireRunSlot(ctx, policy.priority)
+	if err != nil {
+		finish(&NodeResult{
 			NodeID:  node.ID,
 			Success: false,
-			Error:   fmt.Errorf("rate limit acquire failed: %w", err),
-		}
+			Error:   fmt.Errorf("worker slot acquire failed: %w", err),
+		})
 		return
 	}
-	defer limiter.Release()
+	defer releaseSlot()
+
+	// Acquire rate limit token. Unknown types never call a service, so they
+	// are governed by the unknown type policy rather than a limiter.
+	if isKnownNodeType(node.Type) {
+		limiter := e.rateLimiters.GetLimiter(serviceType(node.Type))
+		setNodePhase(graph, node.ID, dag.PhaseRateLimited)
+		if err := limiter.Acquire(ctx); err != nil {
+			finish(&NodeResult{
+				NodeID:  node.ID,
+				Success: false,
+				Error:   fmt.Errorf("rate limit acquire failed: %w", err),
+			})
+			return
+		}
+		defer limiter.Release()
+	}
 
 	// Load checkpoint to determine starting attempt
 	checkpoint, _ := e.checkpointStore.Load(runID, node.ID)
 	startAttempt := checkpoint.AttemptNumber
+	if prior := priorAttempts(runMetrics, node.ID); prior > startAttempt {
+		startAttempt = prior
+	}
 
 	var result *NodeResult
+	nodeStart := time.Now()
+	retryPolicy := policy.retryFor(node.Type)
+
+	if startAttempt > retryPolicy.MaxAttempts {
+		result = &NodeResult{
+			NodeID:  node.ID,
+			Success: false,
+			Error:   fmt.Errorf("retry budget exhausted: %d attempts made before resume", startAttempt),
+		}
+		retryLog.Infof("Node %s has no attempts left after %d before resume", node.ID, startAttempt)
+	}
 
 	// Retry loop with exponential backoff
-	for attempt := startAttempt; attempt <= e.retryPolicy.MaxAttempts; attempt++ {
-		e.retryMetrics.RecordAttempt(node.ID)
+	for attempt := startAttempt; attempt <= retryPolicy.MaxAttempts; attempt++ {
+		if err := policy.budget.recordAttempt(runMetrics, node.ID); err != nil {
+			result = &NodeResult{NodeID: node.ID, Success: false, Error: err}
+			retryLog.Warnf("Node %s not attempted: %v", node.ID, err)
+			break
+		}
 
-		// Check circuit breaker before attempting
-		if !e.circuitBreakers.ShouldAllow(node.Type) {
-			e.retryMetrics.RecordCircuitBreakerHit(node.ID)
+		// Check circuit breakers before attempting
+		if !e.providerAvailable(serviceType(node.Type), policy) {
+			runMetrics.RecordCircuitBreakerHit(node.ID)
 			result = &NodeResult{
 				NodeID:  node.ID,
 				Success: false,
-				Error:   fmt.Errorf("circuit breaker open for service type %s", node.Type),
+				Error:   fmt.Errorf("circuit breaker open for service type %s", serviceType(node.Type)),
 			}
-			log.Printf("[Retry] Circuit breaker open for %s, skipping node %s", node.Type, node.ID)
+			retryLog.Warnf("Circuit breaker open for %s, skipping node %s", node.Type, node.ID)
 			break
 		}
 
+		// Label this attempt's transitions in the WAL
+		if err := graph.SetNodeAttempt(node.ID, attempt+1); err != nil {
+			retryLog.Warnf("failed to record attempt for node %s: %v", node.ID, err)
+		}
+
 		// Set status to RETRYING if this is a retry attempt
 		if attempt > 0 {
 			if err := graph.SetNodeStatus(node.ID, dag.StatusRetrying); err != nil {
-				log.Printf("[Retry] Warning: failed to set retrying status for node %s: %v", node.ID, err)
+				retryLog.Warnf("failed to set retrying status for node %s: %v", node.ID, err)
+			}
+			retryLog.Infof("Retrying node %s (attempt %d/%d)", node.ID, attempt+1, retryPolicy.MaxAttempts+1)
+			// Move back to RUNNING so the attempt can terminate in SUCCEEDED or FAILED
+			if err := graph.SetNodeStatus(node.ID, dag.StatusRunning); err != nil {
+				retryLog.Warnf("failed to set running status for node %s: %v", node.ID, err)
 			}
-			log.Printf("[Retry] Retrying node %s (attempt %d/%d)", node.ID, attempt+1, e.retryPolicy.MaxAttempts+1)
 		}
 
-		// Execute the node with timeout
-		execCtx, cancel := context.WithTimeout(ctx, e.config.NodeExecutionTimeout)
-		
-		// Read current results (thread-safe)
-		resultsMu.RLock()
-		resultsCopy := make(map[string]*NodeResult, len(nodeResults))
-		for k, v := range nodeResults {
-			resultsCopy[k] = v
+		// Execute the node with timeout, unless the run deadline leaves no time
+		timeout := e.attemptTimeout(ctx, node)
+		if timeout <= 0 {
+			result = &NodeResult{
+				NodeID:  node.ID,
+				Success: false,
+				Error:   fmt.Errorf("run deadline passed before node %s could start: %w", node.ID, context.DeadlineExceeded),
+			}
+			retryLog.Warnf("Node %s not attempted: run deadline passed", node.ID)
+			break
 		}
-		resultsMu.RUnlock()
+		attempts++
+		execCtx, cancel := context.WithTimeout(withAttempt(ctx, attempt+1), timeout)
+
+		// Gather only this node's parent results rather than copying every result.
+		// Re-read each attempt, since an upstream re-run may have replaced them.
+		parentResults := nodeResults.Parents(graph, node.ID)
 
-		result = e.executeNode(execCtx, node, graph, resultsCopy, runID)
+		setNodePhase(graph, node.ID, "")
+		stopHeartbeat := e.startHeartbeat(node, graph.ID, runID, attempt)
+		attemptStart := time.Now()
+		result = e.executeAttempt(execCtx, node, graph, parentResults, runID, policy)
+		runMetrics.RecordAttemptDuration(node.ID, time.Since(attemptStart))
+		stopHeartbeat()
 		cancel()
 
 		if result.Success {
 			// Success - record metrics and clean up checkpoint
-			e.circuitBreakers.RecordSuccess(node.Type)
-			e.retryMetrics.RecordSuccess(node.ID)
+			e.serviceHealth.RecordSuccess(serviceType(node.Type))
+			runMetrics.RecordSuccess(node.ID)
 			e.checkpointStore.Delete(runID, node.ID)
-			log.Printf("[Executor] Node %s succeeded on attempt %d", node.ID, attempt+1)
+			execLog.Infof("Node %s succeeded on attempt %d", node.ID, attempt+1)
 			break
 		}
 
 		// Failure - classify error and decide on retry
 		errorType := retry.ClassifyError(result.Error)
-		e.circuitBreakers.RecordFailure(node.Type)
-		e.retryMetrics.RecordFailure(node.ID, errorType)
+		e.serviceHealth.RecordFailure(serviceType(node.Type))
+		runMetrics.RecordFailure(node.ID, errorType)
 
-		log.Printf("[Retry] Node %s failed on attempt %d: %v (error type: %s)", 
+		retryLog.Warnf("Node %s failed on attempt %d: %v (error type: %s)",
 			node.ID, attempt+1, result.Error, errorType.String())
 
+		// Upstream data problems are only worth retrying after re-running the parents
+		if errorType == retry.ErrorTypeUpstream {
+			if !e.upstreamRetryEnabled() {
+				retryLog.Warnf("Node %s failed on upstream data, upstream retry disabled", node.ID)
+				break
+			}
+			if attempt >= retryPolicy.MaxAttempts {
+				retryLog.Warnf("Node %s exhausted all %d retry attempts", node.ID, retryPolicy.MaxAttempts+1)
+				break
+			}
+			if !e.rerunUpstream(ctx, node, graph, nodeResults, retry.UpstreamNodes(result.Error), runID, runMetrics, policy.budget) {
+				break
+			}
+			continue
+		}
+
+		// Rate-limit style failures go back to the scheduler to free the slot
+		if policy.requeue.matches(result.Error) && attempt < retryPolicy.MaxAttempts {
+			if err := e.checkpointStore.Save(runID, node.ID, attempt+1, result.Error); err != nil {
+				retryLog.Warnf("failed to save checkpoint for node %s: %v", node.ID, err)
+			}
+			delay := policy.requeue.cooldown()
+			if isKnownNodeType(node.Type) {
+				if cooldown := e.rateLimiters.GetLimiter(serviceType(node.Type)).CooldownRemaining(); cooldown > delay {
+					delay = cooldown
+				}
+			}
+			result.RequeueAfter = delay
+			retryLog.Infof("Node %s requeued, will be rescheduled in %v", node.ID, delay)
+			break
+		}
+
 		// Check if we should retry
 		if !retry.IsRetryable(result.Error) {
-			log.Printf("[Retry] Node %s encountered permanent error, no retry", node.ID)
+			retryLog.Warnf("Node %s encountered permanent error, no retry", node.ID)
 			break
 		}
 
-		if attempt >= e.retryPolicy.MaxAttempts {
-			log.Printf("[Retry] Node %s exhausted all %d retry attempts", node.ID, e.retryPolicy.MaxAttempts+1)
+		if attempt >= retryPolicy.MaxAttempts {
+			retryLog.Warnf("Node %s exhausted all %d retry attempts", node.ID, retryPolicy.MaxAttempts+1)
 			break
 		}
 
 		// Save checkpoint before waiting
 		if err := e.checkpointStore.Save(runID, node.ID, attempt+1, result.Error); err != nil {
-			log.Printf("[Retry] Warning: failed to save checkpoint for node %s: %v", node.ID, err)
+			retryLog.Warnf("failed to save checkpoint for node %s: %v", node.ID, err)
 		}
 
 		// Update node's LastError in graph
-		if n := graph.Nodes; n != nil {
-			for i := range n {
-				if n[i].ID == node.ID {
-					n[i].LastError = result.Error.Error()
-					n[i].RetryCount = attempt + 1
-					break
-				}
+		if err := graph.SetNodeError(node.ID, result.Error.Error(), attempt+1); err != nil {
+			retryLog.Warnf("failed to record error for node %s: %v", node.ID, err)
+		}
+
+		// Calculate backoff delay, waiting out any cooldown the service asked for
+		delay := retry.ExponentialBackoff(retryPolicy, attempt)
+		if isKnownNodeType(node.Type) {
+			if cooldown := e.rateLimiters.GetLimiter(serviceType(node.Type)).CooldownRemaining(); cooldown > delay {
+				delay = cooldown
 			}
 		}
+		retryLog.Infof("Node %s will retry in %v", node.ID, delay)
 
-		// Calculate backoff delay
-		delay := retry.ExponentialBackoff(e.retryPolicy, attempt)
-		log.Printf("[Retry] Node %s will retry in %v", node.ID, delay)
+		// Stagger retries: only a limited number of nodes may back off at once
+		setNodePhase(graph, node.ID, dag.PhaseBackoff)
+		if err := policy.acquireBackoffSlot(ctx); err != nil {
+			result.Error = fmt.Errorf("retry cancelled: %w", err)
+			retryLog.Infof("Node %s retry cancelled while waiting for a backoff slot", node.ID)
+			break
+		}
+		runMetrics.RecordBackoffStart()
 
 		// Wait with context cancellation support
+		waitStart := time.Now()
 		select {
 		case <-time.After(delay):
 			// Continue to next retry attempt
+			runMetrics.RecordRetryDelay(node.ID, delay)
 		case <-ctx.Done():
+			runMetrics.RecordRetryDelay(node.ID, time.Since(waitStart))
 			result.Error = fmt.Errorf("retry cancelled: %w", ctx.Err())
-			log.Printf("[Retry] Node %s retry cancelled by context", node.ID)
+			retryLog.Infof("Node %s retry cancelled by context", node.ID)
+		}
+
+		runMetrics.RecordBackoffEnd()
+		policy.releaseBackoffSlot()
+		if ctx.Err() != nil {
 			break
 		}
 	}
 
+	// A reviewing node that rejected claims may loop back for refinement
+	if result.Success {
+		result = e.runReviewLoop(ctx, node, graph, nodeResults, result, runID, runMetrics, policy.budget)
+	}
+
+	runMetrics.RecordWallTime(node.ID, time.Since(nodeStart))
+
 	// Update final error in graph if failed
-	if !result.Success && result.Error != nil {
-		if n := graph.Nodes; n != nil {
-			for i := range n {
-				if n[i].ID == node.ID {
-					n[i].LastError = result.Error.Error()
-					n[i].RetryCount = startAttempt + 1
-					break
-				}
-			}
+	if !result.Success && result.Error != nil && result.RequeueAfter == 0 {
+		if err := graph.SetNodeError(node.ID, result.Error.Error(), startAttempt+1); err != nil {
+			retryLog.Warnf("failed to record error for node %s: %v", node.ID, err)
+		}
+		// Nodes stopped by cancellation or the run's budget didn't give up
+		if ctx.Err() == nil && !policy.budget.tripped() {
+			e.recordDeadLetter(graph.ID, node, result.Error)
 		}
 	}
 
+	finish(result)
+}
+
+// setNodePhase records what a node is waiting on. Phases are informational,
+// so a failure to set one never fails the node.
+func setNodePhase(graph *dag.Graph, nodeID, phase string) {
+	if err := graph.SetNodePhase(nodeID, phase); err != nil {
+		execLog.Warnf("failed to set phase for node %s: %v", nodeID, err)
+	}
+}
+
+// nodeTimeout returns how long one execution attempt of node may take: its
+// timeout_seconds override if valid, else the executor-wide timeout.
+func (e *DAGExecutor) nodeTimeout(node *dag.Node) time.Duration {
+	timeout, err := node.Timeout()
+	if err != nil {
+		execLog.Warnf("ignoring timeout override: %v", err)
+		return e.config.NodeExecutionTimeout
+	}
+	if timeout == 0 {
+		return e.config.NodeExecutionTimeout
+	}
+	execLog.Debugf("Node %s uses a %v execution timeout (default %v)", node.ID, timeout, e.config.NodeExecutionTimeout)
+	return timeout
+}
+
+// attemptTimeout returns how long an attempt of node starting now may take:
+// its nodeTimeout, clamped to the time left before ctx's deadline (the run
+// deadline), so a node scheduled late in a run doesn't start work it can't
+// finish. It is zero or negative once the deadline has passed.
+func (e *DAGExecutor) attemptTimeout(ctx context.Context, node *dag.Node) time.Duration {
+	timeout := e.nodeTimeout(node)
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout
+	}
+	if remaining := time.Until(deadline); remaining < timeout {
+		execLog.Debugf("Node %s limited to %v by the run deadline (node timeout %v)", node.ID, remaining, timeout)
+		return remaining
+	}
+	return timeout
+}
+
+// sendResult delivers a node result to the execution loop, recording how
+// long the send blocked when the loop is slow to drain the channel.
+func sendResult(resultChan chan<- *NodeResult, result *NodeResult) {
+	select {
+	case resultChan <- result:
+		metrics.RecordResultChannelWait(0)
+		return
+	default:
+	}
+
+	start := time.Now()
 	resultChan <- result
+	wait := time.Since(start)
+	metrics.RecordResultChannelWait(wait.Seconds())
+	if wait > resultChannelWaitWarning {
+		execLog.Infof("Result for node %s waited %v for the execution loop", result.NodeID, wait)
+	}
+}
+
+// drainResults discards the results of n nodes still running after the
+// execution loop has returned, so none of them stays blocked on resultChan.
+func drainResults(resultChan <-chan *NodeResult, n int) {
+	for ; n > 0; n-- {
+		<-resultChan
+	}
+}
+
+// upstreamRetryEnabled reports whether dependency-aware retry is on.
+func (e *DAGExecutor) upstreamRetryEnabled() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.retryUpstream
+}
+
+// rerunUpstream re-executes the parents blamed for a node's failure and
+// replaces their stored results. Each re-run counts against the run's
+// budget. Returns false if any parent could not be re-run.
+func (e *DAGExecutor) rerunUpstream(
+	ctx context.Context,
+	node *dag.Node,
+	graph *dag.Graph,
+	nodeResults *resultSet,
+	parentIDs []string,
+	runID string,
+	runMetrics *retry.RetryMetrics,
+	budget *runBudget,
+) bool {
+	for _, parentID := range parentIDs {
+		var parent *dag.Node
+		nodes := graph.NodeSnapshot()
+		for i := range nodes {
+			if nodes[i].ID == parentID {
+				parent = &nodes[i]
+				break
+			}
+		}
+		if parent == nil {
+			retryLog.Warnf("Upstream node %s of %s not found in graph", parentID, node.ID)
+			return false
+		}
+
+		retryLog.Infof("Re-running upstream node %s for %s", parentID, node.ID)
+		runMetrics.RecordUpstreamRetry(node.ID)
+		if err := budget.recordAttempt(runMetrics, parentID); err != nil {
+			retryLog.Warnf("Upstream node %s not re-run: %v", parentID, err)
+			return false
+		}
+
+		limiter := e.rateLimiters.GetLimiter(serviceType(parent.Type))
+		if err := limiter.Acquire(ctx); err != nil {
+			retryLog.Warnf("Rate limit acquire failed for upstream node %s: %v", parentID, err)
+			return false
+		}
+
+		execCtx, cancel := context.WithTimeout(ctx, e.attemptTimeout(ctx, parent))
+		result := e.executeNode(execCtx, parent, graph, nodeResults.Parents(graph, parentID), runID)
+		cancel()
+		limiter.Release()
+
+		if !result.Success {
+			e.circuitBreakers.RecordFailure(serviceType(parent.Type))
+			e.serviceHealth.RecordFailure(serviceType(parent.Type))
+			runMetrics.RecordFailure(parentID, retry.ClassifyError(result.Error))
+			retryLog.Warnf("Upstream node %s failed on re-run: %v", parentID, result.Error)
+			return false
+		}
+
+		e.circuitBreakers.RecordSuccess(serviceType(parent.Type))
+		e.serviceHealth.RecordSuccess(serviceType(parent.Type))
+		runMetrics.RecordSuccess(parentID)
+		nodeResults.Put(result)
+	}
+	return true
 }
@@ -5,18 +5,10 @@ import (
 	"testing"
 	"time"
 
-	"hdrp/internal/clients"
 	"hdrp/internal/concurrency"
 	"hdrp/internal/dag"
 )
 
-// Mock service clients for testing
-type mockServiceClients struct{}
-
-func (m *mockServiceClients) Researcher() clients.ResearcherClient { return nil }
-func (m *mockServiceClients) Critic() clients.CriticClient         { return nil }
-func (m *mockServiceClients) Synthesizer() clients.SynthesizerClient { return nil }
-
 // BenchmarkThreeBranchExecution tests concurrent execution of 3 independent branches
 func BenchmarkThreeBranchExecution(b *testing.B) {
 	// Create a DAG with 3 independent branches merging at the end
@@ -54,6 +46,7 @@ func BenchmarkThreeBranchExecution(b *testing.B) {
 			g := createThreeBranchDAG()
 			// Create executor with parallelism=1 (serial)
 			// Note: This is a benchmark skeleton - actual execution requires mock clients
+			_ = g
 			b.StartTimer()
 
 			// Simulate work
@@ -118,10 +111,11 @@ func TestHundredNodeDAG(t *testing.T) {
 		}
 
 		return &dag.Graph{
-			ID:     "hundred-node-dag",
-			Nodes:  nodes,
-			Edges:  edges,
-			Status: dag.StatusCreated,
+			ID:       "hundred-node-dag",
+			Nodes:    nodes,
+			Edges:    edges,
+			Status:   dag.StatusCreated,
+			MaxDepth: 10,
 		}
 	}
 
@@ -199,8 +193,12 @@ func TestConcurrentNodeExecution(t *testing.T) {
 
 // TestRateLimiting verifies rate limiting works correctly
 func TestRateLimiting(t *testing.T) {
-	config := concurrency.LoadConfig()
-	config.ResearcherRateLimit = 2
+	config := &concurrency.Config{
+		MaxWorkers:           4,
+		ResearcherRateLimit:  2,
+		CriticRateLimit:      100,
+		SynthesizerRateLimit: 100,
+	}
 
 	manager := concurrency.NewRateLimiterManager(config)
 	limiter := manager.GetLimiter("researcher")
@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// perRunResearcherClient fails a configurable number of times per run ID.
+type perRunResearcherClient struct {
+	mu          sync.Mutex
+	maxFailures map[string]int
+	calls       map[string]int
+}
+
+func (m *perRunResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls[req.RunId]++
+	if m.calls[req.RunId] <= m.maxFailures[req.RunId] {
+		return nil, context.DeadlineExceeded
+	}
+	return &pb.ResearchResponse{
+		Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}},
+	}, nil
+}
+
+// TestRetryMetricsIsolatedPerRun verifies that concurrent runs reusing node IDs keep separate retry metrics
+func TestRetryMetricsIsolatedPerRun(t *testing.T) {
+	researcher := &perRunResearcherClient{
+		maxFailures: map[string]int{"run-a": 2, "run-b": 0},
+		calls:       make(map[string]int),
+	}
+	clients := &clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}
+
+	executor := NewDAGExecutor(clients, 4)
+	executor.retryPolicy = &retry.RetryPolicy{
+		MaxAttempts:       3,
+		InitialDelay:      5 * time.Millisecond,
+		BackoffMultiplier: 1.5,
+		MaxDelay:          20 * time.Millisecond,
+	}
+
+	newGraph := func(id string) *dag.Graph {
+		return &dag.Graph{
+			ID:     id,
+			Status: dag.StatusCreated,
+			Nodes: []dag.Node{
+				{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+			},
+			Edges: []dag.Edge{},
+		}
+	}
+
+	runs := []struct {
+		runID   string
+		graphID string
+	}{
+		{"run-a", "graph-a"},
+		{"run-b", "graph-b"},
+	}
+
+	results := make([]*ExecutionResult, len(runs))
+	var wg sync.WaitGroup
+	for i, run := range runs {
+		wg.Add(1)
+		go func(i int, runID, graphID string) {
+			defer wg.Done()
+			result, err := executor.Execute(context.Background(), newGraph(graphID), runID)
+			if err != nil {
+				t.Errorf("Run %s failed: %v", runID, err)
+				return
+			}
+			results[i] = result
+		}(i, run.runID, run.graphID)
+	}
+	wg.Wait()
+
+	if results[0] == nil || results[1] == nil {
+		t.Fatal("Expected both runs to produce results")
+	}
+
+	metricsA := results[0].RetryMetrics.GetNodeMetrics("researcher1")
+	metricsB := results[1].RetryMetrics.GetNodeMetrics("researcher1")
+	if metricsA == nil || metricsB == nil {
+		t.Fatal("Expected retry metrics for researcher1 in both runs")
+	}
+
+	if metricsA.TotalAttempts != 3 || metricsA.FailureCount != 2 {
+		t.Errorf("run-a: expected 3 attempts/2 failures, got %d/%d", metricsA.TotalAttempts, metricsA.FailureCount)
+	}
+	if metricsB.TotalAttempts != 1 || metricsB.FailureCount != 0 {
+		t.Errorf("run-b: expected 1 attempt/0 failures, got %d/%d", metricsB.TotalAttempts, metricsB.FailureCount)
+	}
+	if results[0].RetryMetrics == results[1].RetryMetrics {
+		t.Error("Runs should not share a RetryMetrics instance")
+	}
+}
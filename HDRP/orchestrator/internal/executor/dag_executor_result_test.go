@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// echoCriticClient marks every incoming claim as valid.
+type echoCriticClient struct{}
+
+func (m *echoCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	for _, claim := range req.Claims {
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: true, Confidence: 0.9})
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: int32(len(results))}, nil
+}
+
+// failingSynthesizerClient always fails with a permanent error.
+type failingSynthesizerClient struct{}
+
+func (m *failingSynthesizerClient) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
+	return nil, status.Error(codes.InvalidArgument, "synthesis rejected")
+}
+
+func researchCriticGraph(id string, withSynthesizer bool) *dag.Graph {
+	graph := &dag.Graph{
+		ID:     id,
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+			{ID: "critic1", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{{From: "researcher1", To: "critic1"}},
+	}
+	if withSynthesizer {
+		graph.Nodes = append(graph.Nodes, dag.Node{
+			ID: "synthesizer1", Type: "synthesizer", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated,
+		})
+		graph.Edges = append(graph.Edges, dag.Edge{From: "critic1", To: "synthesizer1"})
+	}
+	return graph
+}
+
+// TestNoSynthesizerByDesign verifies that a synthesizer-less graph succeeds with aggregated critic results
+func TestNoSynthesizerByDesign(t *testing.T) {
+	clients := &clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}
+	executor := NewDAGExecutor(clients, 2)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-no-synth", false), "test-run-no-synth")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	if !result.Success {
+		t.Fatalf("Expected success for graph without synthesizer, got: %s", result.ErrorMessage)
+	}
+	if result.FinalReport != "" {
+		t.Errorf("Expected empty report, got %q", result.FinalReport)
+	}
+	if len(result.VerificationResults) != 1 {
+		t.Fatalf("Expected 1 aggregated critic result, got %d", len(result.VerificationResults))
+	}
+	if got := result.VerificationResults[0].Claim.GetStatement(); got != "Test claim" {
+		t.Errorf("Unexpected critic result claim: %q", got)
+	}
+}
+
+// TestFailedSynthesizer verifies that a graph whose synthesizer failed is not reported as successful
+func TestFailedSynthesizer(t *testing.T) {
+	clients := &clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &failingSynthesizerClient{},
+	}
+	executor := NewDAGExecutor(clients, 2)
+	executor.retryPolicy = &retry.RetryPolicy{MaxAttempts: 0}
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-failed-synth", true), "test-run-failed-synth")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	if result.Success {
+		t.Fatal("Expected failure when synthesizer fails")
+	}
+	if _, ok := result.FailedNodes["synthesizer1"]; !ok {
+		t.Errorf("Expected synthesizer1 in failed nodes, got %v", result.FailedNodes)
+	}
+	if result.FinalReport != "" {
+		t.Errorf("Expected no report, got %q", result.FinalReport)
+	}
+}
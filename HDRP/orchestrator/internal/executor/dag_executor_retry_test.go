@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,12 +15,14 @@ import (
 	"hdrp/internal/retry"
 
 	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // Mock client that can inject failures
 type mockResearcherClient struct {
+	mu              sync.Mutex
 	failureCount    int
 	maxFailures     int
 	failureType     error
@@ -26,7 +30,9 @@ type mockResearcherClient struct {
 	callCount       int
 }
 
-func (m *mockResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...interface{}) (*pb.ResearchResponse, error) {
+func (m *mockResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.callCount++
 	
 	if m.shouldFail != nil && m.shouldFail(m.callCount) {
@@ -41,14 +47,20 @@ func (m *mockResearcherClient) Research(ctx context.Context, req *pb.ResearchReq
 	// Success
 	return &pb.ResearchResponse{
 		Claims: []*pb.AtomicClaim{
-			{Text: "Test claim", Confidence: 0.9},
+			{Statement: "Test claim", SourceNodeId: req.SourceNodeId},
 		},
 	}, nil
 }
 
+func (m *mockResearcherClient) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.callCount
+}
+
 type mockCriticClient struct{}
 
-func (m *mockCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...interface{}) (*pb.VerifyResponse, error) {
+func (m *mockCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
 	return &pb.VerifyResponse{
 		Results:       []*pb.CritiqueResult{},
 		VerifiedCount: int32(len(req.Claims)),
@@ -57,7 +69,7 @@ func (m *mockCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, op
 
 type mockSynthesizerClient struct{}
 
-func (m *mockSynthesizerClient) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...interface{}) (*pb.SynthesizeResponse, error) {
+func (m *mockSynthesizerClient) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
 	return &pb.SynthesizeResponse{
 		Report:      "Test report",
 		ArtifactUri: "test://artifact",
@@ -98,8 +110,14 @@ func TestRetryTransientError(t *testing.T) {
 				Config: map[string]string{"query": "test query"},
 				Status: dag.StatusCreated,
 			},
+			{
+				ID:     "synthesizer1",
+				Type:   "synthesizer",
+				Config: map[string]string{"query": "test query"},
+				Status: dag.StatusCreated,
+			},
 		},
-		Edges: []dag.Edge{},
+		Edges: []dag.Edge{{From: "researcher1", To: "synthesizer1"}},
 	}
 
 	ctx := context.Background()
@@ -114,7 +132,7 @@ func TestRetryTransientError(t *testing.T) {
 	}
 
 	// Verify retry metrics
-	metrics := executor.retryMetrics.GetNodeMetrics("researcher1")
+	metrics := result.RetryMetrics.GetNodeMetrics("researcher1")
 	if metrics == nil {
 		t.Fatal("Expected retry metrics for researcher1")
 	}
@@ -128,8 +146,66 @@ func TestRetryTransientError(t *testing.T) {
 		t.Errorf("Expected 2 failures, got %d", metrics.FailureCount)
 	}
 
-	if mockClient.callCount != 3 {
-		t.Errorf("Expected 3 calls to researcher, got %d", mockClient.callCount)
+	if mockClient.calls() != 3 {
+		t.Errorf("Expected 3 calls to researcher, got %d", mockClient.calls())
+	}
+}
+
+// TestRetryTimingBreakdown verifies retry delay and wall time are recorded for a node that retried twice
+func TestRetryTimingBreakdown(t *testing.T) {
+	mockClient := &mockResearcherClient{
+		maxFailures: 2,
+		failureType: context.DeadlineExceeded,
+	}
+
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  mockClient,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 4)
+	policy := &retry.RetryPolicy{
+		MaxAttempts:       3,
+		InitialDelay:      10 * time.Millisecond,
+		BackoffMultiplier: 2.0,
+		MaxDelay:          100 * time.Millisecond,
+	}
+	executor.retryPolicy = policy
+
+	graph := &dag.Graph{
+		ID:     "test-retry-timing",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "test query"}, Status: dag.StatusCreated},
+			{ID: "synthesizer1", Type: "synthesizer", Config: map[string]string{"query": "test query"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{{From: "researcher1", To: "synthesizer1"}},
+	}
+
+	result, err := executor.Execute(context.Background(), graph, "test-run-retry-timing")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success after retries, got: %s", result.ErrorMessage)
+	}
+
+	metrics := result.RetryMetrics.GetNodeMetrics("researcher1")
+	if metrics == nil {
+		t.Fatal("Expected retry metrics for researcher1")
+	}
+
+	// Two retries wait the first two backoff steps: 10ms + 20ms
+	wantDelay := retry.ExponentialBackoff(policy, 0) + retry.ExponentialBackoff(policy, 1)
+	if metrics.RetryDelay != wantDelay {
+		t.Errorf("Expected retry delay %v, got %v", wantDelay, metrics.RetryDelay)
+	}
+	if metrics.WallTime < metrics.RetryDelay+metrics.AttemptTime {
+		t.Errorf("Wall time %v should cover retry delay %v plus attempt time %v",
+			metrics.WallTime, metrics.RetryDelay, metrics.AttemptTime)
+	}
+
+	if summary := result.RetryMetrics.Summary(); !strings.Contains(summary, wantDelay.String()+" retry delay") {
+		t.Errorf("Expected summary to report retry delay, got:\n%s", summary)
 	}
 }
 
@@ -181,22 +257,13 @@ func TestNoPermanentErrorRetry(t *testing.T) {
 	}
 
 	// Should only attempt once (no retries for permanent errors)
-	if mockClient.callCount != 1 {
-		t.Errorf("Expected 1 call (no retries for permanent error), got %d", mockClient.callCount)
+	if mockClient.calls() != 1 {
+		t.Errorf("Expected 1 call (no retries for permanent error), got %d", mockClient.calls())
 	}
 }
 
 // TestSiblingContinuesAfterFailure verifies that sibling nodes execute even when one branch fails
 func TestSiblingContinuesAfterFailure(t *testing.T) {
-	failingClient := &mockResearcherClient{
-		maxFailures: 10,
-		failureType: errors.New("permanent failure"),
-	}
-
-	successClient := &mockResearcherClient{
-		maxFailures: 0, // Never fails
-	}
-
 	// We'll track which node is being called by the query
 	mockClient := &mockResearcherClient{
 		shouldFail: func(callCount int) bool {
@@ -257,8 +324,8 @@ func TestSiblingContinuesAfterFailure(t *testing.T) {
 	}
 
 	// Both nodes should have been attempted
-	if mockClient.callCount != 2 {
-		t.Errorf("Expected both siblings to execute, got %d calls", mockClient.callCount)
+	if mockClient.calls() != 2 {
+		t.Errorf("Expected both siblings to execute, got %d calls", mockClient.calls())
 	}
 
 	// Check succeeded and failed nodes
@@ -275,7 +342,7 @@ func TestSiblingContinuesAfterFailure(t *testing.T) {
 func Test30PercentFailureRate(t *testing.T) {
 	rand.Seed(time.Now().UnixNano())
 	
-	m mockClient := &mockResearcherClient{
+	mockClient := &mockResearcherClient{
 		shouldFail: func(callCount int) bool {
 			// 30% chance of failure
 			return rand.Float64() < 0.3
@@ -339,7 +406,7 @@ func Test30PercentFailureRate(t *testing.T) {
 		len(result.SucceededNodes), len(result.FailedNodes), len(nodes))
 	
 	// Log retry metrics
-	allMetrics := executor.retryMetrics.GetAllMetrics()
+	allMetrics := result.RetryMetrics.GetAllMetrics()
 	totalRetries := 0
 	for _, metrics := range allMetrics {
 		if metrics.TotalAttempts > 1 {
@@ -401,12 +468,12 @@ func TestCircuitBreakerTrip(t *testing.T) {
 
 	// Circuit should eventually open
 	if state != retry.CircuitOpen {
-		t.Logf("Warning: Circuit breaker not open after %d failures (state: %v)", mockClient.callCount, state)
+		t.Logf("Warning: Circuit breaker not open after %d failures (state: %v)", mockClient.calls(), state)
 	}
 
 	// Some requests should have been blocked by circuit breaker
 	circuitBreakerBlocked := false
-	for _, metrics := range executor.retryMetrics.GetAllMetrics() {
+	for _, metrics := range result.RetryMetrics.GetAllMetrics() {
 		if metrics.CircuitBreakerHits > 0 {
 			circuitBreakerBlocked = true
 			break
@@ -417,5 +484,54 @@ func TestCircuitBreakerTrip(t *testing.T) {
 		t.Logf("Warning: No circuit breaker hits recorded")
 	}
 
-	t.Logf("Circuit breaker state: %v, Total calls: %d", state, mockClient.callCount)
+	t.Logf("Circuit breaker state: %v, Total calls: %d", state, mockClient.calls())
+}
+
+// TestFailedParentPolicy verifies that dependents of a failed node end the run
+// according to the configured policy instead of waiting on it
+func TestFailedParentPolicy(t *testing.T) {
+	tests := []struct {
+		policy     dag.FailedParentPolicy
+		wantStatus dag.Status
+		wantFailed int
+	}{
+		{dag.FailedParentSkip, dag.StatusSkipped, 1},
+		{dag.FailedParentFail, dag.StatusFailed, 2},
+		{dag.FailedParentBlock, dag.StatusBlocked, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			clients := &clients.ServiceClients{
+				Researcher: &mockResearcherClient{
+					shouldFail:  func(int) bool { return true },
+					failureType: errors.New("permanent failure"),
+				},
+				Critic:      &mockCriticClient{},
+				Synthesizer: &mockSynthesizerClient{},
+			}
+			executor := NewDAGExecutor(clients, 2)
+			executor.retryPolicy = &retry.RetryPolicy{MaxAttempts: 0}
+			executor.SetFailedParentPolicy(tt.policy)
+
+			graph := researchCriticGraph("test-failed-parent-"+string(tt.policy), false)
+			result, err := executor.Execute(context.Background(), graph, "test-run-failed-parent")
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if result.Success {
+				t.Error("Expected the run to fail")
+			}
+			if graph.Nodes[1].Status != tt.wantStatus {
+				t.Errorf("Critic status = %s, want %s", graph.Nodes[1].Status, tt.wantStatus)
+			}
+			if len(result.FailedNodes) != tt.wantFailed {
+				t.Errorf("Expected %d failed nodes, got %v", tt.wantFailed, result.FailedNodes)
+			}
+			deadlocked := strings.Contains(result.ErrorMessage, "deadlocked")
+			if deadlocked != (tt.policy == dag.FailedParentBlock) {
+				t.Errorf("Unexpected error message for %s: %s", tt.policy, result.ErrorMessage)
+			}
+		})
+	}
 }
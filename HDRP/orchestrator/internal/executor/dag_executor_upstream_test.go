@@ -0,0 +1,185 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// emptyResearcherClient returns no claims for the first emptyCalls calls.
+type emptyResearcherClient struct {
+	mu         sync.Mutex
+	emptyCalls int
+	callCount  int
+}
+
+func (m *emptyResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callCount++
+	if m.callCount <= m.emptyCalls {
+		return &pb.ResearchResponse{}, nil
+	}
+	return &pb.ResearchResponse{
+		Claims: []*pb.AtomicClaim{{Statement: "Test claim", SourceNodeId: req.SourceNodeId}},
+	}, nil
+}
+
+func (m *emptyResearcherClient) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.callCount
+}
+
+func newUpstreamTestExecutor(researcher *emptyResearcherClient) *DAGExecutor {
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRetryPolicy(&retry.RetryPolicy{
+		MaxAttempts:       2,
+		InitialDelay:      10 * time.Millisecond,
+		BackoffMultiplier: 2.0,
+		MaxDelay:          50 * time.Millisecond,
+	})
+	executor.SetRetryUpstream(true)
+	return executor
+}
+
+// TestUpstreamRetryRerunsResearcher verifies a critic starved of claims re-runs its researcher and then succeeds
+func TestUpstreamRetryRerunsResearcher(t *testing.T) {
+	researcher := &emptyResearcherClient{emptyCalls: 1}
+	executor := newUpstreamTestExecutor(researcher)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-upstream", false), "test-run-upstream")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success after upstream retry, got: %s", result.ErrorMessage)
+	}
+
+	if got := researcher.calls(); got != 2 {
+		t.Errorf("Expected researcher to run twice, got %d", got)
+	}
+	if len(result.VerificationResults) != 1 {
+		t.Errorf("Expected 1 verified claim from re-run researcher, got %d", len(result.VerificationResults))
+	}
+
+	criticMetrics := result.RetryMetrics.GetNodeMetrics("critic1")
+	if criticMetrics == nil {
+		t.Fatal("Expected metrics for critic1")
+	}
+	if criticMetrics.UpstreamErrors != 1 || criticMetrics.UpstreamRetries != 1 {
+		t.Errorf("Expected 1 upstream error and retry, got %d and %d",
+			criticMetrics.UpstreamErrors, criticMetrics.UpstreamRetries)
+	}
+}
+
+// TestUpstreamRetryBoundedByBudget verifies upstream re-runs stop once the critic's retry budget is spent
+func TestUpstreamRetryBoundedByBudget(t *testing.T) {
+	researcher := &emptyResearcherClient{emptyCalls: 100}
+	executor := newUpstreamTestExecutor(researcher)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-upstream-budget", false), "test-run-upstream-budget")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected failure when researcher never produces claims")
+	}
+	if _, ok := result.FailedNodes["critic1"]; !ok {
+		t.Errorf("Expected critic1 in failed nodes, got %v", result.FailedNodes)
+	}
+
+	// One original run plus one re-run per critic retry
+	if got := researcher.calls(); got != 3 {
+		t.Errorf("Expected 3 researcher runs, got %d", got)
+	}
+}
+
+// TestUpstreamRetryDisabled verifies the critic still verifies an empty claim set by default
+func TestUpstreamRetryDisabled(t *testing.T) {
+	researcher := &emptyResearcherClient{emptyCalls: 1}
+	executor := newUpstreamTestExecutor(researcher)
+	executor.SetRetryUpstream(false)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-upstream-off", false), "test-run-upstream-off")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+	}
+	if got := researcher.calls(); got != 1 {
+		t.Errorf("Expected researcher to run once, got %d", got)
+	}
+}
+
+// versionedResearcherClient returns a differently worded claim on each call.
+type versionedResearcherClient struct {
+	mu        sync.Mutex
+	callCount int
+}
+
+func (m *versionedResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callCount++
+	return &pb.ResearchResponse{
+		Claims: []*pb.AtomicClaim{{Statement: fmt.Sprintf("claim v%d", m.callCount), SourceNodeId: req.SourceNodeId}},
+	}, nil
+}
+
+// staleRejectingCritic blames the researcher for any claim but the wanted one.
+type staleRejectingCritic struct {
+	want string
+
+	mu   sync.Mutex
+	seen []string
+}
+
+func (c *staleRejectingCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	for _, claim := range req.Claims {
+		c.seen = append(c.seen, claim.Statement)
+		if claim.Statement != c.want {
+			return nil, &retry.UpstreamDataError{NodeIDs: []string{"researcher1"}, Reason: "stale claim " + claim.Statement}
+		}
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: true, Confidence: 0.9})
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: int32(len(results))}, nil
+}
+
+// TestUpstreamRetryUsesFreshParentResults verifies a retried critic sees the re-run researcher's output, not the first attempt's
+func TestUpstreamRetryUsesFreshParentResults(t *testing.T) {
+	critic := &staleRejectingCritic{want: "claim v2"}
+	executor := newUpstreamTestExecutor(&emptyResearcherClient{})
+	executor.clients.Researcher = &versionedResearcherClient{}
+	executor.clients.Critic = critic
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-upstream-fresh", false), "test-run-upstream-fresh")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success after upstream retry, got: %s", result.ErrorMessage)
+	}
+
+	critic.mu.Lock()
+	defer critic.mu.Unlock()
+	if len(critic.seen) != 2 || critic.seen[0] != "claim v1" || critic.seen[1] != "claim v2" {
+		t.Errorf("Critic saw claims %v, want [claim v1 claim v2]", critic.seen)
+	}
+}
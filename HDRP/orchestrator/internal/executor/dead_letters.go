@@ -0,0 +1,60 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+)
+
+// RunGraphStore is implemented by storage that can find the graph a run
+// executed.
+type RunGraphStore interface {
+	FindGraphByRunID(runID string) (string, error)
+}
+
+// ErrRunFailuresUnsupported is returned by RunFailures when the storage
+// backend can't find the graph of a run.
+var ErrRunFailuresUnsupported = errors.New("storage backend cannot look up run graphs")
+
+// RunFailures lists the nodes of a run's graph that gave up.
+type RunFailures struct {
+	RunID       string
+	GraphID     string
+	DeadLetters []*storage.DeadLetter // Oldest first
+}
+
+// recordDeadLetter stores a node that failed permanently or ran out of
+// retries, so it can be inspected and reprocessed after the run.
+func (e *DAGExecutor) recordDeadLetter(graphID string, node *dag.Node, cause error) {
+	if e.storage == nil {
+		return
+	}
+	if err := e.storage.AppendDeadLetter(graphID, node.ID, node.Type, node.Config, cause.Error()); err != nil {
+		execLog.Warnf("failed to record dead letter for node %s: %v", node.ID, err)
+	}
+}
+
+// RunFailures returns the dead letters of the graph a run executed. Returns
+// ErrRunNotFound if no stored graph belongs to the run. A graph resumed under
+// several run IDs shares its dead letters between them.
+func (e *DAGExecutor) RunFailures(runID string) (*RunFailures, error) {
+	store, ok := e.storage.(RunGraphStore)
+	if !ok {
+		return nil, ErrRunFailuresUnsupported
+	}
+	graphID, err := store.FindGraphByRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+	if graphID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+
+	letters, err := e.storage.ListDeadLetters(graphID)
+	if err != nil {
+		return nil, err
+	}
+	return &RunFailures{RunID: runID, GraphID: graphID, DeadLetters: letters}, nil
+}
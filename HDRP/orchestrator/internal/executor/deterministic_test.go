@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// traceResearcher records the order nodes are called in, takes a random
+// time to answer and fails the first call for nodes listed in flaky.
+type traceResearcher struct {
+	mu          sync.Mutex
+	trace       []string
+	flaky       map[string]bool
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *traceResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	r.trace = append(r.trace, req.SourceNodeId)
+	r.inFlight++
+	if r.inFlight > r.maxInFlight {
+		r.maxInFlight = r.inFlight
+	}
+	fail := r.flaky[req.SourceNodeId]
+	r.flaky[req.SourceNodeId] = false
+	r.mu.Unlock()
+
+	time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+
+	r.mu.Lock()
+	r.inFlight--
+	r.mu.Unlock()
+	if fail {
+		return nil, status.Error(codes.Unavailable, "flaky")
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func deterministicTestGraph() *dag.Graph {
+	researcher := func(id string, relevance float64) dag.Node {
+		return dag.Node{ID: id, Type: "researcher", Config: map[string]string{"query": id}, Status: dag.StatusCreated, RelevanceScore: relevance}
+	}
+	return &dag.Graph{
+		ID:     "deterministic-test",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			researcher("r-low", 0.1),
+			researcher("r-b", 0.5),
+			researcher("r-a", 0.5),
+			researcher("r-high", 0.9),
+			researcher("r-child", 1.0),
+			{ID: "critic1", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{
+			{From: "r-low", To: "r-child"},
+			{From: "r-a", To: "critic1"},
+			{From: "r-b", To: "critic1"},
+			{From: "r-child", To: "critic1"},
+			{From: "r-high", To: "critic1"},
+		},
+	}
+}
+
+func TestDeterministicMode_IdenticalTraces(t *testing.T) {
+	run := func(runID string) []string {
+		researcher := &traceResearcher{flaky: map[string]bool{"r-a": true}}
+		executor := NewDAGExecutor(&clients.ServiceClients{
+			Researcher:  researcher,
+			Critic:      &echoCriticClient{},
+			Synthesizer: &mockSynthesizerClient{},
+		}, 4)
+		executor.retryPolicy = &retry.RetryPolicy{MaxAttempts: 1, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond}
+
+		result, err := executor.ExecuteWithOptions(context.Background(), deterministicTestGraph(), runID, RunOptions{Deterministic: true})
+		if err != nil || !result.Success {
+			t.Fatalf("Run %s failed: %v %+v", runID, err, result)
+		}
+		if researcher.maxInFlight != 1 {
+			t.Errorf("Run %s had %d nodes in flight, want 1", runID, researcher.maxInFlight)
+		}
+		return researcher.trace
+	}
+
+	// Highest relevance first, ties by ID, children only once their parents finish
+	want := []string{"r-high", "r-a", "r-a", "r-b", "r-low", "r-child"}
+	for i := 0; i < 3; i++ {
+		if got := run("deterministic-run"); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Run %d trace = %v, want %v", i, got, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+)
+
+// DefaultEstimateLatency is assumed for node types with neither latency
+// history nor a configured default.
+const DefaultEstimateLatency = 5 * time.Second
+
+// DefaultNodeCost is the cost of a node type without a configured weight.
+const DefaultNodeCost = 1.0
+
+// latencyWindow is the number of recent successful executions averaged per
+// node type.
+const latencyWindow = 100
+
+// Latency sources reported in an Estimate.
+const (
+	LatencySourceHistory = "history"
+	LatencySourceConfig  = "config"
+	LatencySourceDefault = "default"
+)
+
+// EstimateDefaults configures Estimate for node types without history.
+type EstimateDefaults struct {
+	Latencies map[string]time.Duration // Average execution time per node type
+	Costs     map[string]float64       // Cost weight per node type; unlisted types cost DefaultNodeCost
+}
+
+// Estimate is a projection of what running a graph will take.
+type Estimate struct {
+	GraphID             string                     `json:"graph_id"`
+	Nodes               int                        `json:"nodes"`
+	TotalCost           float64                    `json:"total_cost"`
+	ProjectedDurationMs int64                      `json:"projected_duration_ms"` // Critical path, or longer if workers are the bottleneck
+	CriticalPath        []string                   `json:"critical_path"`         // Slowest chain of dependent nodes
+	CriticalPathMs      int64                      `json:"critical_path_ms"`
+	PeakConcurrency     int                        `json:"peak_concurrency"` // Most nodes expected to run at once
+	Latencies           map[string]LatencyEstimate `json:"latencies"`        // Per node type
+}
+
+// LatencyEstimate is the per-node latency assumed for a node type.
+type LatencyEstimate struct {
+	AverageMs int64  `json:"average_ms"`
+	Source    string `json:"source"` // history, config or default
+	Samples   int    `json:"samples,omitempty"`
+}
+
+// SetEstimateDefaults sets the latencies and cost weights Estimate uses.
+func (e *DAGExecutor) SetEstimateDefaults(defaults EstimateDefaults) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.estimateDefaults = defaults
+}
+
+// Estimate projects a graph's total cost, wall-clock time and peak
+// concurrency without running it. Wall-clock time is the critical path with
+// each node taking its type's average latency, taken from recent successful
+// executions when available and configured defaults otherwise; it is
+// stretched when the graph is too wide for the worker pool.
+func (e *DAGExecutor) Estimate(graph *dag.Graph) (*Estimate, error) {
+	e.applyDefaultMaxDepth(graph)
+	if err := graph.Validate(); err != nil {
+		return nil, fmt.Errorf("graph validation failed: %w", err)
+	}
+
+	e.mu.RLock()
+	defaults := e.estimateDefaults
+	e.mu.RUnlock()
+	workers := e.resolveRunPolicy(RunOptions{}).workers
+
+	est := &Estimate{
+		GraphID:   graph.ID,
+		Nodes:     len(graph.Nodes),
+		Latencies: make(map[string]LatencyEstimate),
+	}
+
+	latency := make(map[string]time.Duration, len(graph.Nodes))
+	var totalLatency time.Duration
+	for _, n := range graph.Nodes {
+		// _agent types share their service's latency and cost
+		nodeType := serviceType(n.Type)
+		typeLatency, ok := est.Latencies[nodeType]
+		if !ok {
+			typeLatency = e.latencyFor(nodeType, defaults)
+			est.Latencies[nodeType] = typeLatency
+		}
+		latency[n.ID] = time.Duration(typeLatency.AverageMs) * time.Millisecond
+		totalLatency += latency[n.ID]
+
+		cost, ok := defaults.Costs[nodeType]
+		if !ok {
+			cost = DefaultNodeCost
+		}
+		est.TotalCost += cost
+	}
+
+	path, pathLatency := criticalPath(graph, latency)
+	est.CriticalPath = path
+	est.CriticalPathMs = pathLatency.Milliseconds()
+
+	// The widest dependency level is what could run at once
+	width := make(map[int]int)
+	for _, depth := range graph.NodeDepths() {
+		width[depth]++
+		if width[depth] > est.PeakConcurrency {
+			est.PeakConcurrency = width[depth]
+		}
+	}
+	if est.PeakConcurrency > workers {
+		est.PeakConcurrency = workers
+	}
+
+	projected := pathLatency
+	if perWorker := totalLatency / time.Duration(workers); perWorker > projected {
+		projected = perWorker
+	}
+	est.ProjectedDurationMs = projected.Milliseconds()
+
+	return est, nil
+}
+
+// latencyFor picks the latency assumed for a node type.
+func (e *DAGExecutor) latencyFor(nodeType string, defaults EstimateDefaults) LatencyEstimate {
+	if avg, samples := e.nodeLatencies.Average(nodeType); samples > 0 {
+		return LatencyEstimate{AverageMs: avg.Milliseconds(), Source: LatencySourceHistory, Samples: samples}
+	}
+	if d, ok := defaults.Latencies[nodeType]; ok {
+		return LatencyEstimate{AverageMs: d.Milliseconds(), Source: LatencySourceConfig}
+	}
+	return LatencyEstimate{AverageMs: DefaultEstimateLatency.Milliseconds(), Source: LatencySourceDefault}
+}
+
+// criticalPath returns the chain of dependent nodes with the largest total
+// latency, and that total. The graph must be acyclic.
+func criticalPath(graph *dag.Graph, latency map[string]time.Duration) ([]string, time.Duration) {
+	parents := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		parents[edge.To] = append(parents[edge.To], edge.From)
+	}
+
+	// Visit in depth order (ties by ID) so parents are finished first and
+	// equal-length paths resolve the same way every time
+	depths := graph.NodeDepths()
+	order := make([]string, 0, len(graph.Nodes))
+	for _, n := range graph.Nodes {
+		order = append(order, n.ID)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if depths[order[i]] != depths[order[j]] {
+			return depths[order[i]] < depths[order[j]]
+		}
+		return order[i] < order[j]
+	})
+
+	finish := make(map[string]time.Duration, len(order))
+	via := make(map[string]string, len(order))
+	var last string
+	for _, id := range order {
+		ps := parents[id]
+		sort.Strings(ps)
+		var start time.Duration
+		for _, p := range ps {
+			if via[id] == "" || finish[p] > start {
+				start = finish[p]
+				via[id] = p
+			}
+		}
+		finish[id] = start + latency[id]
+		if last == "" || finish[id] > finish[last] {
+			last = id
+		}
+	}
+	if last == "" {
+		return nil, 0
+	}
+
+	var path []string
+	for id := last; id != ""; id = via[id] {
+		path = append([]string{id}, path...)
+	}
+	return path, finish[last]
+}
+
+// latencyTracker keeps a rolling average of successful execution times per
+// node type.
+type latencyTracker struct {
+	mu    sync.RWMutex
+	types map[string]*latencySamples
+}
+
+type latencySamples struct {
+	durations []time.Duration
+	next      int
+	count     int
+	total     time.Duration
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{types: make(map[string]*latencySamples)}
+}
+
+// Record adds an execution time for a node type.
+func (t *latencyTracker) Record(nodeType string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.types[nodeType]
+	if !ok {
+		s = &latencySamples{durations: make([]time.Duration, latencyWindow)}
+		t.types[nodeType] = s
+	}
+	if s.count == len(s.durations) {
+		s.total -= s.durations[s.next]
+	} else {
+		s.count++
+	}
+	s.durations[s.next] = d
+	s.total += d
+	s.next = (s.next + 1) % len(s.durations)
+}
+
+// Average returns the mean recent execution time for a node type and how
+// many samples it covers.
+func (t *latencyTracker) Average(nodeType string) (time.Duration, int) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	s, ok := t.types[nodeType]
+	if !ok || s.count == 0 {
+		return 0, 0
+	}
+	return s.total / time.Duration(s.count), s.count
+}
@@ -0,0 +1,131 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+)
+
+func diamondGraph() *dag.Graph {
+	return &dag.Graph{
+		ID: "diamond",
+		Nodes: []dag.Node{
+			{ID: "A", Type: "researcher"},
+			{ID: "B", Type: "critic"},
+			{ID: "C", Type: "researcher"},
+			{ID: "D", Type: "synthesizer"},
+		},
+		Edges: []dag.Edge{
+			{From: "A", To: "B"},
+			{From: "A", To: "C"},
+			{From: "B", To: "D"},
+			{From: "C", To: "D"},
+		},
+	}
+}
+
+func TestEstimate_DiamondDAG(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 4)
+	executor.SetEstimateDefaults(EstimateDefaults{
+		Latencies: map[string]time.Duration{
+			"researcher":  time.Second,
+			"critic":      3 * time.Second,
+			"synthesizer": 2 * time.Second,
+		},
+		Costs: map[string]float64{"researcher": 1, "critic": 2, "synthesizer": 5},
+	})
+
+	est, err := executor.Estimate(diamondGraph())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	if want := []string{"A", "B", "D"}; !reflect.DeepEqual(est.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", est.CriticalPath, want)
+	}
+	if est.CriticalPathMs != 6000 || est.ProjectedDurationMs != 6000 {
+		t.Errorf("Expected 6000ms critical path and projection, got %d and %d", est.CriticalPathMs, est.ProjectedDurationMs)
+	}
+	if est.TotalCost != 9 {
+		t.Errorf("TotalCost = %v, want 9", est.TotalCost)
+	}
+	if est.PeakConcurrency != 2 {
+		t.Errorf("PeakConcurrency = %d, want 2", est.PeakConcurrency)
+	}
+	if got := est.Latencies["critic"]; got.Source != LatencySourceConfig || got.AverageMs != 3000 {
+		t.Errorf("Critic latency = %+v, want 3000ms from config", got)
+	}
+
+	// One worker runs the branches back to back
+	executor.SetDeterministicMode(true)
+	est, err = executor.Estimate(diamondGraph())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if est.PeakConcurrency != 1 || est.ProjectedDurationMs != 7000 {
+		t.Errorf("With one worker expected peak 1 and 7000ms, got %d and %d", est.PeakConcurrency, est.ProjectedDurationMs)
+	}
+}
+
+func TestEstimate_UsesLatencyHistory(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 4)
+	executor.nodeLatencies.Record("researcher", 400*time.Millisecond)
+	executor.nodeLatencies.Record("researcher", 600*time.Millisecond)
+
+	est, err := executor.Estimate(diamondGraph())
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	if got := est.Latencies["researcher"]; got.Source != LatencySourceHistory || got.AverageMs != 500 || got.Samples != 2 {
+		t.Errorf("Researcher latency = %+v, want 500ms from 2 samples of history", got)
+	}
+	if got := est.Latencies["critic"]; got.Source != LatencySourceDefault || got.AverageMs != DefaultEstimateLatency.Milliseconds() {
+		t.Errorf("Critic latency = %+v, want the default", got)
+	}
+	// A (0.5s) -> B (5s) -> D (5s)
+	if est.CriticalPathMs != 10500 {
+		t.Errorf("CriticalPathMs = %d, want 10500", est.CriticalPathMs)
+	}
+}
+
+func TestEstimate_AgentNodeTypes(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 4)
+	executor.SetEstimateDefaults(EstimateDefaults{
+		Latencies: map[string]time.Duration{"critic": 3 * time.Second},
+		Costs:     map[string]float64{"researcher": 1, "critic": 2, "synthesizer": 5},
+	})
+	executor.nodeLatencies.Record("researcher", 400*time.Millisecond)
+
+	graph := diamondGraph()
+	for i := range graph.Nodes {
+		graph.Nodes[i].Type += "_agent"
+	}
+	est, err := executor.Estimate(graph)
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	if est.TotalCost != 9 {
+		t.Errorf("TotalCost = %v, want the service types' 9", est.TotalCost)
+	}
+	if got := est.Latencies["researcher"]; got.Source != LatencySourceHistory || got.AverageMs != 400 {
+		t.Errorf("Researcher latency = %+v, want 400ms from history", got)
+	}
+	if got := est.Latencies["critic"]; got.Source != LatencySourceConfig || got.AverageMs != 3000 {
+		t.Errorf("Critic latency = %+v, want 3000ms from config", got)
+	}
+}
+
+func TestEstimate_InvalidGraph(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 4)
+	graph := diamondGraph()
+	graph.Edges = append(graph.Edges, dag.Edge{From: "D", To: "A"})
+
+	if _, err := executor.Estimate(graph); err == nil {
+		t.Error("Expected error for cyclic graph")
+	}
+}
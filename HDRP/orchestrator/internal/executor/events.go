@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+)
+
+// EventType identifies the kind of execution event emitted by the executor.
+type EventType string
+
+const (
+	// EventNodeHeartbeat is emitted periodically while a node is still running.
+	EventNodeHeartbeat EventType = "node_heartbeat"
+)
+
+// DefaultHeartbeatInterval is the default period between node heartbeats.
+const DefaultHeartbeatInterval = 30 * time.Second
+
+// Event describes a single execution event for streaming to clients.
+type Event struct {
+	Type      EventType         `json:"type"`
+	RunID     string            `json:"run_id"`
+	GraphID   string            `json:"graph_id,omitempty"`
+	NodeID    string            `json:"node_id,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+// EventHandler receives execution events. Handlers must not block.
+type EventHandler func(Event)
+
+// SetEventHandler registers a handler that receives execution events.
+func (e *DAGExecutor) SetEventHandler(handler EventHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.eventHandler = handler
+}
+
+// SetHeartbeatInterval configures how often running nodes emit heartbeats.
+// A non-positive interval disables heartbeats.
+func (e *DAGExecutor) SetHeartbeatInterval(interval time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.heartbeatInterval = interval
+}
+
+// emitEvent forwards an event to the registered handler, if any.
+func (e *DAGExecutor) emitEvent(evt Event) {
+	e.mu.RLock()
+	handler := e.eventHandler
+	e.mu.RUnlock()
+
+	if handler == nil {
+		return
+	}
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+	handler(evt)
+}
+
+// startHeartbeat emits periodic heartbeat events for a running node until
+// the returned stop function is called.
+func (e *DAGExecutor) startHeartbeat(node *dag.Node, graphID, runID string, attempt int) func() {
+	e.mu.RLock()
+	interval := e.heartbeatInterval
+	e.mu.RUnlock()
+
+	if interval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		startTime := time.Now()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				elapsed := time.Since(startTime).Round(time.Millisecond)
+				execLog.Infof("Node %s still running (attempt %d, elapsed %v)", node.ID, attempt+1, elapsed)
+				e.emitEvent(Event{
+					Type:    EventNodeHeartbeat,
+					RunID:   runID,
+					GraphID: graphID,
+					NodeID:  node.ID,
+					Data: map[string]string{
+						"node_type":  node.Type,
+						"attempt":    strconv.Itoa(attempt + 1),
+						"elapsed_ms": strconv.FormatInt(elapsed.Milliseconds(), 10),
+					},
+				})
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
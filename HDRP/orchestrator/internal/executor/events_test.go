@@ -0,0 +1,134 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// slowResearcherClient blocks for a fixed duration before returning claims.
+type slowResearcherClient struct {
+	delay time.Duration
+}
+
+func (m *slowResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pb.ResearchResponse{
+		Claims: []*pb.AtomicClaim{{Statement: "Slow claim", SourceNodeId: req.SourceNodeId}},
+	}, nil
+}
+
+// TestHeartbeatsForSlowNode verifies that a long-running node emits heartbeats at the configured interval
+func TestHeartbeatsForSlowNode(t *testing.T) {
+	clients := &clients.ServiceClients{
+		Researcher:  &slowResearcherClient{delay: 260 * time.Millisecond},
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}
+
+	executor := NewDAGExecutor(clients, 2)
+	interval := 50 * time.Millisecond
+	executor.SetHeartbeatInterval(interval)
+
+	var mu sync.Mutex
+	var heartbeats []Event
+	executor.SetEventHandler(func(evt Event) {
+		if evt.Type != EventNodeHeartbeat {
+			return
+		}
+		mu.Lock()
+		heartbeats = append(heartbeats, evt)
+		mu.Unlock()
+	})
+
+	graph := &dag.Graph{
+		ID:     "test-heartbeat",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{
+				ID:     "researcher1",
+				Type:   "researcher",
+				Config: map[string]string{"query": "slow query"},
+				Status: dag.StatusCreated,
+			},
+		},
+		Edges: []dag.Edge{},
+	}
+
+	if _, err := executor.Execute(context.Background(), graph, "test-run-heartbeat"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// 260ms of work at a 50ms interval should yield ~5 heartbeats
+	if len(heartbeats) < 3 || len(heartbeats) > 6 {
+		t.Fatalf("Expected 3-6 heartbeats, got %d", len(heartbeats))
+	}
+
+	for i, hb := range heartbeats {
+		if hb.NodeID != "researcher1" || hb.RunID != "test-run-heartbeat" || hb.GraphID != "test-heartbeat" {
+			t.Errorf("Heartbeat %d has unexpected identity: %+v", i, hb)
+		}
+		if i == 0 {
+			continue
+		}
+		gap := hb.Timestamp.Sub(heartbeats[i-1].Timestamp)
+		if gap < interval/2 || gap > interval*3 {
+			t.Errorf("Heartbeat %d gap %v outside expected range around %v", i, gap, interval)
+		}
+	}
+}
+
+// TestHeartbeatsDisabled verifies that a non-positive interval disables heartbeats
+func TestHeartbeatsDisabled(t *testing.T) {
+	clients := &clients.ServiceClients{
+		Researcher:  &slowResearcherClient{delay: 60 * time.Millisecond},
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}
+
+	executor := NewDAGExecutor(clients, 2)
+	executor.SetHeartbeatInterval(0)
+
+	var mu sync.Mutex
+	count := 0
+	executor.SetEventHandler(func(evt Event) {
+		if evt.Type == EventNodeHeartbeat {
+			mu.Lock()
+			count++
+			mu.Unlock()
+		}
+	})
+
+	graph := &dag.Graph{
+		ID:     "test-heartbeat-disabled",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{},
+	}
+
+	if _, err := executor.Execute(context.Background(), graph, "test-run-heartbeat-disabled"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if count != 0 {
+		t.Errorf("Expected no heartbeats when disabled, got %d", count)
+	}
+}
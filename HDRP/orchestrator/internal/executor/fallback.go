@@ -0,0 +1,125 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// providerErrorCodes are permanent failures tied to one provider's
+// deployment, such as a missing method or rejected credentials, which a
+// different provider of the same service may not share.
+var providerErrorCodes = map[codes.Code]bool{
+	codes.Unimplemented:    true,
+	codes.Unauthenticated:  true,
+	codes.PermissionDenied: true,
+}
+
+// isProviderError reports whether err is worth failing over to another provider.
+func isProviderError(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && providerErrorCodes[st.Code()]
+}
+
+type providerKey struct{}
+
+// withProvider directs the node's service calls under ctx to a fallback provider.
+func withProvider(ctx context.Context, provider *clients.ServiceClients) context.Context {
+	return context.WithValue(ctx, providerKey{}, provider)
+}
+
+// serviceClients returns the clients to call under ctx: a fallback provider
+// chosen by executeAttempt, or the primary clients.
+func (e *DAGExecutor) serviceClients(ctx context.Context) *clients.ServiceClients {
+	if provider, ok := ctx.Value(providerKey{}).(*clients.ServiceClients); ok {
+		return provider
+	}
+	return e.clients
+}
+
+// fallbacks returns the secondary providers configured for a node type.
+func (e *DAGExecutor) fallbacks(nodeType string) []clients.Provider {
+	if e.clients == nil {
+		return nil
+	}
+	return e.clients.Fallbacks[nodeType]
+}
+
+// fallbackBreaker names the circuit breaker of a node type's fallback provider.
+func fallbackBreaker(nodeType string, provider clients.Provider) string {
+	return nodeType + "@" + provider.Name
+}
+
+// providerAvailable reports whether any provider of a node type may be
+// tried: the primary or a fallback whose circuit breaker isn't open.
+func (e *DAGExecutor) providerAvailable(nodeType string, policy runPolicy) bool {
+	if !policy.honorBreakers || e.circuitBreakers.ShouldAllow(nodeType) {
+		return true
+	}
+	for _, provider := range e.fallbacks(nodeType) {
+		if e.circuitBreakers.ShouldAllow(fallbackBreaker(nodeType, provider)) {
+			return true
+		}
+	}
+	return false
+}
+
+// executeAttempt runs one attempt of a node on its primary provider. If the
+// primary's circuit breaker is open or it fails with a provider-specific
+// error, the node type's fallback providers are tried in order under the
+// same attempt deadline. Each provider has its own circuit breaker.
+func (e *DAGExecutor) executeAttempt(
+	ctx context.Context,
+	node *dag.Node,
+	graph *dag.Graph,
+	parentResults map[string]*NodeResult,
+	runID string,
+	policy runPolicy,
+) *NodeResult {
+	service := serviceType(node.Type)
+	var result *NodeResult
+	if !policy.honorBreakers || e.circuitBreakers.ShouldAllow(service) {
+		result = e.executeNode(ctx, node, graph, parentResults, runID)
+		e.recordBreakerOutcome(service, result)
+		if result.Success || !isProviderError(result.Error) {
+			return result
+		}
+	}
+
+	for _, provider := range e.fallbacks(service) {
+		breaker := fallbackBreaker(service, provider)
+		if policy.honorBreakers && !e.circuitBreakers.ShouldAllow(breaker) {
+			continue
+		}
+		execLog.Warnf("Node %s failing over to %s provider %q", node.ID, service, provider.Name)
+		result = e.executeNode(withProvider(ctx, provider.Clients), node, graph, parentResults, runID)
+		e.recordBreakerOutcome(breaker, result)
+		if result.Success || !isProviderError(result.Error) {
+			return result
+		}
+	}
+
+	if result == nil {
+		// Every breaker opened since providerAvailable checked
+		result = &NodeResult{
+			NodeID:  node.ID,
+			Success: false,
+			Error:   fmt.Errorf("circuit breaker open for service type %s", service),
+		}
+	}
+	return result
+}
+
+// recordBreakerOutcome feeds an attempt's result to a circuit breaker.
+func (e *DAGExecutor) recordBreakerOutcome(breaker string, result *NodeResult) {
+	if result.Success {
+		e.circuitBreakers.RecordSuccess(breaker)
+	} else {
+		e.circuitBreakers.RecordFailure(breaker)
+	}
+}
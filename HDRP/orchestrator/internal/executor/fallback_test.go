@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExecute_ProviderFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		primaryErr  error
+		tripBreaker bool
+	}{
+		{name: "Primary breaker open", primaryErr: status.Error(codes.Unavailable, "researcher down"), tripBreaker: true},
+		{name: "Provider-specific error", primaryErr: status.Error(codes.Unimplemented, "method not deployed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &mockResearcherClient{maxFailures: 100, failureType: tt.primaryErr}
+			secondary := &mockResearcherClient{}
+			executor := NewDAGExecutor(&clients.ServiceClients{
+				Researcher: primary,
+				Critic:     &echoCriticClient{},
+				Fallbacks: map[string][]clients.Provider{
+					"researcher": {{Name: "secondary", Clients: &clients.ServiceClients{Researcher: secondary}}},
+				},
+			}, 2)
+			defer executor.Close()
+			if tt.tripBreaker {
+				for i := 0; i < 10; i++ {
+					executor.circuitBreakers.RecordFailure("researcher")
+				}
+			}
+
+			result, err := executor.Execute(context.Background(), researchCriticGraph("test-fallback", false), "run-fallback")
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success via the fallback, failed nodes: %v", result.FailedNodes)
+			}
+
+			wantPrimary := 1
+			if tt.tripBreaker {
+				wantPrimary = 0
+			}
+			if got := primary.calls(); got != wantPrimary {
+				t.Errorf("Primary researcher called %d times, want %d", got, wantPrimary)
+			}
+			if got := secondary.calls(); got != 1 {
+				t.Errorf("Secondary researcher called %d times, want 1", got)
+			}
+			if state := executor.circuitBreakers.States()["researcher@secondary"]; state.String() != "Closed" {
+				t.Errorf("Fallback breaker state = %v, want Closed", state)
+			}
+		})
+	}
+}
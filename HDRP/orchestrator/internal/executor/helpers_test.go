@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/storage"
+)
+
+// mockServiceClients returns clients whose researcher, critic and
+// synthesizer all succeed.
+func mockServiceClients() *clients.ServiceClients {
+	return &clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}
+}
+
+// newTestExecutor creates an executor calling svc, backed by a private
+// database so graphs left by other tests aren't picked up. It is closed when
+// the test ends, and the test is skipped if storage is unavailable.
+func newTestExecutor(t *testing.T, svc *clients.ServiceClients) *DAGExecutor {
+	t.Helper()
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "hdrp.db"))
+
+	executor := NewDAGExecutor(svc, 2)
+	t.Cleanup(func() { executor.Close() })
+	if executor.storage == nil {
+		t.Skip("Storage unavailable")
+	}
+	return executor
+}
+
+// newTestStorage creates a SQLite store on a private database, closed when
+// the test ends.
+func newTestStorage(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "hdrp.db"))
+
+	store, err := storage.NewSQLiteStorage()
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
@@ -0,0 +1,12 @@
+package executor
+
+import "hdrp/internal/logger"
+
+// Leveled JSONL loggers for the executor, tagged by the part of execution
+// they cover.
+var (
+	execLog     = logger.New("executor")
+	retryLog    = logger.New("retry")
+	recoveryLog = logger.New("recovery")
+	breakerLog  = logger.New("circuit_breaker")
+)
@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+// TestMain runs the package tests from a scratch directory so the executor's
+// default ./checkpoints and ./data stores do not leak between runs.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "executor-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create temp dir: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Chdir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to chdir to temp dir: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
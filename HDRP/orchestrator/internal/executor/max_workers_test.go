@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// concurrencyRecordingResearcher records how many calls were in flight as
+// each one started. Calls block until they receive from release.
+type concurrencyRecordingResearcher struct {
+	mu       sync.Mutex
+	inFlight int
+	starts   []int
+	release  chan struct{}
+}
+
+func (r *concurrencyRecordingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	r.inFlight++
+	r.starts = append(r.starts, r.inFlight)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func (r *concurrencyRecordingResearcher) running() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.inFlight
+}
+
+func (r *concurrencyRecordingResearcher) startedWith() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.starts...)
+}
+
+// TestSetMaxWorkers_ResizesRunInProgress grows and then shrinks the worker
+// limit of a run while its nodes are executing. Run with -race.
+func TestSetMaxWorkers_ResizesRunInProgress(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", t.TempDir()+"/max_workers.db")
+	researcher := &concurrencyRecordingResearcher{release: make(chan struct{})}
+	executor := NewDAGExecutor(&clients.ServiceClients{Researcher: researcher}, 1)
+	defer executor.Close()
+
+	graph := &dag.Graph{ID: "graph-max-workers", Status: dag.StatusCreated}
+	for i := 1; i <= 6; i++ {
+		id := fmt.Sprintf("researcher%d", i)
+		graph.Nodes = append(graph.Nodes, dag.Node{ID: id, Type: "researcher", Config: map[string]string{"query": id}, Status: dag.StatusCreated})
+	}
+
+	type outcome struct {
+		result *ExecutionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := executor.Execute(context.Background(), graph, "run-max-workers")
+		done <- outcome{result, err}
+	}()
+	waitFor(t, func() bool { return researcher.running() == 1 })
+
+	// Growing takes effect once the loop next schedules
+	executor.SetMaxWorkers(3)
+	executor.mu.RLock()
+	configured := executor.config.MaxWorkers
+	executor.mu.RUnlock()
+	if configured != 3 {
+		t.Errorf("config.MaxWorkers = %d after SetMaxWorkers(3)", configured)
+	}
+	researcher.release <- struct{}{}
+	waitFor(t, func() bool { return researcher.running() == 3 })
+
+	// Shrinking lets the running nodes finish, then runs the rest one at a time
+	executor.SetMaxWorkers(1)
+	close(researcher.release)
+	out := <-done
+	if out.err != nil {
+		t.Fatalf("Execution error: %v", out.err)
+	}
+	if !out.result.Success {
+		t.Fatalf("Expected success, got: %s", out.result.ErrorMessage)
+	}
+
+	starts := researcher.startedWith()
+	if len(starts) != 6 {
+		t.Fatalf("Expected 6 nodes to run, got %d", len(starts))
+	}
+	for i, n := range starts[4:] {
+		if n != 1 {
+			t.Errorf("Node %d started with %d running, want 1 after shrinking", i+5, n)
+		}
+	}
+}
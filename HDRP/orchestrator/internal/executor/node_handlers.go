@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"context"
+	"strings"
+
+	"hdrp/internal/dag"
+)
+
+// NodeHandler executes one attempt of a node. nodeResults holds the results
+// of nodes that have already completed in the run, keyed by node ID. The
+// handler reports failure through the returned result's Error, which is
+// classified for retries like any other node error.
+type NodeHandler func(ctx context.Context, node *dag.Node, graph *dag.Graph, nodeResults map[string]*NodeResult, runID string) *NodeResult
+
+// agentSuffix marks the service node types as the template generator names
+// them, e.g. researcher_agent for researcher.
+const agentSuffix = "_agent"
+
+// serviceType returns the service a node type is executed by: the type
+// itself, or researcher, critic or synthesizer for their "_agent" names.
+// Rate limiters, circuit breakers, retry policies and fallbacks are keyed
+// by service, so both names of a type share them.
+func serviceType(nodeType string) string {
+	switch base := strings.TrimSuffix(nodeType, agentSuffix); base {
+	case "researcher", "critic", "synthesizer":
+		return base
+	default:
+		return nodeType
+	}
+}
+
+// builtinHandlers returns the handlers for the service-backed node types.
+// Plans from the template generator name them with an "_agent" suffix, so
+// those names dispatch to the same handlers.
+func (e *DAGExecutor) builtinHandlers() map[string]NodeHandler {
+	researcher := func(ctx context.Context, node *dag.Node, graph *dag.Graph, _ map[string]*NodeResult, runID string) *NodeResult {
+		return e.executeResearcherCached(ctx, node, graph, runID)
+	}
+	return map[string]NodeHandler{
+		"researcher":                researcher,
+		"researcher" + agentSuffix:  researcher,
+		"critic":                    e.executeCritic,
+		"critic" + agentSuffix:      e.executeCritic,
+		"synthesizer":               e.executeSynthesizer,
+		"synthesizer" + agentSuffix: e.executeSynthesizer,
+	}
+}
+
+// RegisterNodeHandler makes nodes of nodeType run handler, replacing any
+// handler already registered for the type, including the built-in ones. A
+// nil handler unregisters the type, leaving its nodes to the unknown type
+// policy. Call before executing graphs.
+func (e *DAGExecutor) RegisterNodeHandler(nodeType string, handler NodeHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if handler == nil {
+		delete(e.handlers, nodeType)
+		return
+	}
+	e.handlers[nodeType] = handler
+}
+
+// nodeHandler returns the handler registered for nodeType.
+func (e *DAGExecutor) nodeHandler(nodeType string) (NodeHandler, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	handler, ok := e.handlers[nodeType]
+	return handler, ok
+}
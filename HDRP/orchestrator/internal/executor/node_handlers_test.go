@@ -0,0 +1,126 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/generator"
+	"hdrp/internal/intent"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// TestRegisterNodeHandler verifies custom node types run their registered
+// handler with their parents' results, and feed their output downstream.
+func TestRegisterNodeHandler(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+
+	var sawClaims int
+	executor.RegisterNodeHandler("manual_review", func(ctx context.Context, node *dag.Node, graph *dag.Graph, nodeResults map[string]*NodeResult, runID string) *NodeResult {
+		claims, _ := nodeResults["researcher1"].Data.([]*pb.AtomicClaim)
+		sawClaims = len(claims)
+		reviewed := append(claims, &pb.AtomicClaim{Statement: "Reviewed claim", SourceNodeId: node.ID})
+		return &NodeResult{NodeID: node.ID, Success: true, Data: reviewed}
+	})
+
+	graph := placeholderGraph("test-custom-handler")
+	result, err := executor.Execute(context.Background(), graph, "test-run-custom-handler")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success with a registered handler, got: %s", result.ErrorMessage)
+	}
+	if sawClaims != 1 {
+		t.Errorf("Handler saw %d parent claims, want 1", sawClaims)
+	}
+	if got := len(result.VerificationResults); got != 2 {
+		t.Errorf("Expected the critic to verify both claims, got %d results", got)
+	}
+}
+
+// TestRegisterNodeHandler_OverrideAndUnregister verifies built-in handlers
+// can be replaced, and that a nil handler hands the type to the unknown
+// type policy.
+func TestRegisterNodeHandler_OverrideAndUnregister(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+
+	var calls atomic.Int32
+	executor.RegisterNodeHandler("researcher", func(ctx context.Context, node *dag.Node, graph *dag.Graph, nodeResults map[string]*NodeResult, runID string) *NodeResult {
+		calls.Add(1)
+		return &NodeResult{NodeID: node.ID, Success: false, Error: &retry.InvalidResultError{Reason: "custom researcher failed"}}
+	})
+	graph := researchCriticGraph("test-override-handler", false)
+	if _, err := executor.Execute(context.Background(), graph, "test-run-override-handler"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if calls.Load() == 0 || !strings.Contains(graph.Nodes[0].LastError, "custom researcher failed") {
+		t.Errorf("Expected the replacement handler to run, got %d calls, error %q", calls.Load(), graph.Nodes[0].LastError)
+	}
+
+	executor.RegisterNodeHandler("researcher", nil)
+	graph = researchCriticGraph("test-unregistered-handler", false)
+	if _, err := executor.Execute(context.Background(), graph, "test-run-unregistered-handler"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !strings.Contains(graph.Nodes[0].LastError, "unknown node type: researcher") {
+		t.Errorf("Expected the unregistered type to be unknown, got %q", graph.Nodes[0].LastError)
+	}
+}
+
+// TestExecute_GeneratedResearchGraph runs a plan straight from the template
+// generator, whose service nodes use the "_agent" type names.
+func TestExecute_GeneratedResearchGraph(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+	critic := &claimRecordingCritic{}
+	executor.clients.Critic = critic
+
+	graph, err := generator.NewTemplateGenerator().Generate(&intent.Objective{
+		ID:          "obj-generated",
+		Type:        intent.IntentResearch,
+		Description: "Research generated plans",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	result, err := executor.Execute(context.Background(), graph, "test-run-generated")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected the generated plan to succeed, got: %s (failed nodes %v)", result.ErrorMessage, result.FailedNodes)
+	}
+	if result.FinalReport != "Test report" {
+		t.Errorf("Expected the synthesizer's report, got %q", result.FinalReport)
+	}
+	for _, n := range graph.Nodes {
+		if n.Status != dag.StatusSucceeded {
+			t.Errorf("Node %s (%s) ended %s: %s", n.ID, n.Type, n.Status, n.LastError)
+		}
+	}
+	critic.mu.Lock()
+	defer critic.mu.Unlock()
+	if len(critic.claims) != 1 {
+		t.Errorf("Expected the critic to verify the researcher's claim, got %v", critic.claims)
+	}
+}
+
+func TestServiceType(t *testing.T) {
+	tests := map[string]string{
+		"researcher":        "researcher",
+		"researcher_agent":  "researcher",
+		"critic_agent":      "critic",
+		"synthesizer_agent": "synthesizer",
+		"coding_agent":      "coding_agent",
+		"manual_review":     "manual_review",
+	}
+	for in, want := range tests {
+		if got := serviceType(in); got != want {
+			t.Errorf("serviceType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
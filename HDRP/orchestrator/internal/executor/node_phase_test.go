@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+	"hdrp/internal/storage"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// phaseRecordingStorage records the phase and status writes for each node.
+type phaseRecordingStorage struct {
+	storage.Storage
+
+	mu       sync.Mutex
+	phases   map[string][]string
+	statuses map[string][]string
+}
+
+func (s *phaseRecordingStorage) UpdateNodePhase(graphID string, nodeID string, phase string) error {
+	s.mu.Lock()
+	s.phases[nodeID] = append(s.phases[nodeID], phase)
+	s.mu.Unlock()
+	return s.Storage.UpdateNodePhase(graphID, nodeID, phase)
+}
+
+func (s *phaseRecordingStorage) UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error {
+	s.mu.Lock()
+	s.statuses[nodeID] = append(s.statuses[nodeID], status)
+	s.mu.Unlock()
+	return s.Storage.UpdateNodeStatus(graphID, nodeID, status, retryCount, lastError)
+}
+
+func TestExecute_RecordsNodePhases(t *testing.T) {
+	executor := newTestExecutor(t, &clients.ServiceClients{
+		Researcher: &mockResearcherClient{maxFailures: 1, failureType: status.Error(codes.Unavailable, "busy")},
+		Critic:     &echoCriticClient{},
+	})
+	recorder := &phaseRecordingStorage{
+		Storage:  executor.storage,
+		phases:   make(map[string][]string),
+		statuses: make(map[string][]string),
+	}
+	executor.storage = recorder
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: 10 * time.Millisecond})
+
+	graph := researchCriticGraph("graph-phases", false)
+	result, err := executor.Execute(context.Background(), graph, "run-phases")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	wantPhases := []string{dag.PhaseWaitingLock, dag.PhaseWaitingSlot, dag.PhaseRateLimited, "", dag.PhaseBackoff, ""}
+	if got := recorder.phases["researcher1"]; !reflect.DeepEqual(got, wantPhases) {
+		t.Errorf("researcher1 phases = %q, want %q", got, wantPhases)
+	}
+
+	// Phases leave the status state machine alone
+	wantStatuses := []string{
+		string(dag.StatusPending), string(dag.StatusRunning), string(dag.StatusRetrying),
+		string(dag.StatusRunning), string(dag.StatusSucceeded),
+	}
+	if got := recorder.statuses["researcher1"]; !reflect.DeepEqual(got, wantStatuses) {
+		t.Errorf("researcher1 statuses = %q, want %q", got, wantStatuses)
+	}
+
+	stored, err := recorder.LoadNodes(graph.ID)
+	if err != nil {
+		t.Fatalf("Failed to load nodes: %v", err)
+	}
+	for _, node := range stored {
+		if node.Phase != "" {
+			t.Errorf("Expected node %s to end with no phase, got %q", node.NodeID, node.Phase)
+		}
+	}
+}
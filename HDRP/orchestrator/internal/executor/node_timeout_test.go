@@ -0,0 +1,104 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// deadlineRecordingResearcher records how long each call had before its
+// context deadline.
+type deadlineRecordingResearcher struct {
+	mu        sync.Mutex
+	remaining map[string]time.Duration // SourceNodeId -> time left at call start
+}
+
+func (r *deadlineRecordingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	deadline, _ := ctx.Deadline()
+	r.mu.Lock()
+	r.remaining[req.SourceNodeId] = time.Until(deadline)
+	r.mu.Unlock()
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: req.Query}}}, nil
+}
+
+func TestExecute_PerNodeTimeout(t *testing.T) {
+	researcher := &deadlineRecordingResearcher{remaining: make(map[string]time.Duration)}
+	executor := NewDAGExecutor(&clients.ServiceClients{Researcher: researcher}, 2)
+
+	graph := &dag.Graph{
+		ID:     "test-node-timeout",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "slow", Type: "researcher", Config: map[string]string{"query": "q", dag.ConfigTimeoutSeconds: "2"}, Status: dag.StatusCreated},
+			{ID: "default", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+		},
+	}
+	if _, err := executor.Execute(context.Background(), graph, "run-node-timeout"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	if got := researcher.remaining["slow"]; got <= 0 || got > 2*time.Second {
+		t.Errorf("Node with timeout_seconds=2 had %v before its deadline", got)
+	}
+	if got := researcher.remaining["default"]; got <= 2*time.Second || got > executor.config.NodeExecutionTimeout {
+		t.Errorf("Node without an override had %v before its deadline, want close to %v", got, executor.config.NodeExecutionTimeout)
+	}
+}
+
+func TestNodeTimeout_InvalidFallsBack(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	node := &dag.Node{ID: "n1", Type: "researcher", Config: map[string]string{dag.ConfigTimeoutSeconds: "later"}}
+	if got := executor.nodeTimeout(node); got != executor.config.NodeExecutionTimeout {
+		t.Errorf("nodeTimeout = %v, want the default %v", got, executor.config.NodeExecutionTimeout)
+	}
+}
+
+func TestAttemptTimeout_ClampedToRunDeadline(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	node := &dag.Node{ID: "late", Type: "researcher", Config: map[string]string{dag.ConfigTimeoutSeconds: "10"}}
+
+	if got := executor.attemptTimeout(context.Background(), node); got != 10*time.Second {
+		t.Errorf("attemptTimeout without a run deadline = %v, want the node timeout", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if got := executor.attemptTimeout(ctx, node); got <= 0 || got > 200*time.Millisecond {
+		t.Errorf("attemptTimeout near the run deadline = %v, want at most 200ms", got)
+	}
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	if got := executor.attemptTimeout(expired, node); got > 0 {
+		t.Errorf("attemptTimeout after the run deadline = %v, want <= 0", got)
+	}
+}
+
+func TestExecute_NodeTimeoutClampedToRunDeadline(t *testing.T) {
+	researcher := &deadlineRecordingResearcher{remaining: make(map[string]time.Duration)}
+	executor := NewDAGExecutor(&clients.ServiceClients{Researcher: researcher}, 1)
+
+	graph := &dag.Graph{
+		ID:     "test-run-deadline",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "late", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := executor.Execute(ctx, graph, "run-deadline"); err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+
+	if got := researcher.remaining["late"]; got <= 0 || got > time.Second {
+		t.Errorf("Node had %v before its deadline, want at most the 1s left in the run", got)
+	}
+}
@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// gatedResearcherClient signals when called and answers once released.
+type gatedResearcherClient struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *gatedResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	c.started <- struct{}{}
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "Test claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func newPauseTestExecutor(t *testing.T) (*DAGExecutor, *gatedResearcherClient, *claimRecordingCritic, *channelPublisher) {
+	t.Helper()
+	researcher := &gatedResearcherClient{started: make(chan struct{}, 1), release: make(chan struct{})}
+	critic := &claimRecordingCritic{}
+	executor := newTestExecutor(t, &clients.ServiceClients{Researcher: researcher, Critic: critic})
+	publisher := &channelPublisher{events: make(chan Event, 16)}
+	executor.SetEventPublisher(publisher)
+	return executor, researcher, critic, publisher
+}
+
+// waitNodeCompleted waits for the node_completed event of nodeID.
+func waitNodeCompleted(t *testing.T, publisher *channelPublisher, nodeID string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case evt := <-publisher.events:
+			if evt.Type == EventNodeCompleted && evt.NodeID == nodeID {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timed out waiting for %s to complete", nodeID)
+		}
+	}
+}
+
+// TestPauseResume verifies a paused run lets its running node finish but
+// schedules nothing new until resumed.
+func TestPauseResume(t *testing.T) {
+	executor, researcher, critic, publisher := newPauseTestExecutor(t)
+
+	graph := researchCriticGraph("test-pause", false)
+	type outcome struct {
+		result *ExecutionResult
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := executor.Execute(context.Background(), graph, "run-pause")
+		done <- outcome{result, err}
+	}()
+
+	<-researcher.started
+	if err := executor.Pause("run-pause"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if !executor.IsPaused("run-pause") {
+		t.Fatal("Expected the run to be paused")
+	}
+	close(researcher.release)
+
+	// The in-flight researcher finishes, but its critic isn't scheduled
+	waitNodeCompleted(t, publisher, "researcher1")
+	time.Sleep(100 * time.Millisecond)
+	critic.mu.Lock()
+	verified := len(critic.claims)
+	critic.mu.Unlock()
+	if verified != 0 {
+		t.Fatalf("Critic ran while the run was paused")
+	}
+
+	if err := executor.Resume("run-pause"); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	select {
+	case out := <-done:
+		if out.err != nil || !out.result.Success {
+			t.Fatalf("Expected the resumed run to succeed, got %+v, %v", out.result, out.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not finish after resuming")
+	}
+	if executor.IsPaused("run-pause") {
+		t.Error("Finished run still reported as paused")
+	}
+}
+
+// TestPause_CancelWhilePaused verifies a paused run can still be cancelled.
+func TestPause_CancelWhilePaused(t *testing.T) {
+	executor, researcher, _, publisher := newPauseTestExecutor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	graph := researchCriticGraph("test-pause-cancel", false)
+	done := make(chan error, 1)
+	go func() {
+		_, err := executor.Execute(ctx, graph, "run-pause-cancel")
+		done <- err
+	}()
+
+	<-researcher.started
+	if err := executor.Pause("run-pause-cancel"); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	close(researcher.release)
+	waitNodeCompleted(t, publisher, "researcher1")
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrExecutionCancelled) {
+			t.Fatalf("Execute error = %v, want ErrExecutionCancelled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Paused run did not stop after cancellation")
+	}
+	if graph.Nodes[1].Status != dag.StatusCancelled {
+		t.Errorf("critic1 status = %s, want CANCELLED", graph.Nodes[1].Status)
+	}
+}
+
+func TestPause_UnknownRun(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+	if err := executor.Pause("run-missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Pause error = %v, want ErrRunNotFound", err)
+	}
+	if err := executor.Resume("run-missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Resume error = %v, want ErrRunNotFound", err)
+	}
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"hdrp/internal/dag"
+)
+
+// SetPersistNodeResults makes every successful node write its result to
+// storage. ResumeGraph then keeps nodes that succeeded before a crash, and
+// their children get the stored claims and critiques as inputs, instead of
+// the whole graph running again.
+func (e *DAGExecutor) SetPersistNodeResults(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.persistNodeResults = enabled
+}
+
+// persistingNodeResults reports whether node results are persisted.
+func (e *DAGExecutor) persistingNodeResults() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.persistNodeResults
+}
+
+// persistedResults returns the succeeded nodes of a graph whose results are
+// in storage, or nil unless node results are persisted.
+func (e *DAGExecutor) persistedResults(graph *dag.Graph) map[string]bool {
+	if !e.persistingNodeResults() || e.storage == nil {
+		return nil
+	}
+
+	completed := make(map[string]bool)
+	for _, node := range graph.NodeSnapshot() {
+		if node.Status != dag.StatusSucceeded {
+			continue
+		}
+		if _, err := e.storage.LoadNodeResult(graph.ID, node.ID); err == nil {
+			completed[node.ID] = true
+		}
+	}
+	return completed
+}
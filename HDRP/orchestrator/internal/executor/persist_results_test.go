@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// claimRecordingCritic accepts every claim and records the statements it saw.
+type claimRecordingCritic struct {
+	mu     sync.Mutex
+	claims []string
+}
+
+func (c *claimRecordingCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	for _, claim := range req.Claims {
+		c.claims = append(c.claims, claim.Statement)
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: true, Confidence: 0.9})
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: int32(len(results))}, nil
+}
+
+func TestResumeGraph_ReusesPersistedResults(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetPersistNodeResults(true)
+
+	// The first run crashes once the researcher has succeeded
+	ctx, crash := context.WithCancel(context.Background())
+	defer crash()
+	blocking := &blockingCriticClient{started: make(chan struct{}, 1)}
+	executor.clients.Critic = blocking
+	go func() {
+		<-blocking.started
+		crash()
+	}()
+
+	graph := researchCriticGraph("graph-persist-results", false)
+	if _, err := executor.Execute(ctx, graph, "run-persist-results"); err == nil {
+		t.Fatal("Expected the first run to be cut short")
+	}
+
+	if err := executor.storage.UpdateGraphStatus(graph.ID, string(dag.StatusRunning)); err != nil {
+		t.Fatalf("Failed to mark graph running: %v", err)
+	}
+	recovered, err := executor.RecoverGraph(graph.ID)
+	if err != nil {
+		t.Fatalf("Recovery failed: %v", err)
+	}
+
+	researcher := &mockResearcherClient{}
+	critic := &claimRecordingCritic{}
+	executor.clients.Researcher = researcher
+	executor.clients.Critic = critic
+	result, err := executor.ResumeGraph(context.Background(), recovered)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected resumed run to succeed, got %+v", result)
+	}
+
+	if got := researcher.calls(); got != 0 {
+		t.Errorf("Expected the succeeded researcher to be skipped, got %d calls", got)
+	}
+	critic.mu.Lock()
+	defer critic.mu.Unlock()
+	if len(critic.claims) != 1 || critic.claims[0] != "Test claim" {
+		t.Errorf("Critic received claims %v, want the researcher's persisted claim", critic.claims)
+	}
+}
+
+// TestResumeRun_SkipsSucceededNodes crashes a run after its researcher
+// succeeded, then resumes the graph from storage under a new run ID.
+func TestResumeRun_SkipsSucceededNodes(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetPersistNodeResults(true)
+
+	ctx, crash := context.WithCancel(context.Background())
+	defer crash()
+	blocking := &blockingCriticClient{started: make(chan struct{}, 1)}
+	executor.clients.Critic = blocking
+	go func() {
+		<-blocking.started
+		crash()
+	}()
+
+	graph := researchCriticGraph("graph-resume-run", true)
+	if _, err := executor.Execute(ctx, graph, "run-resume-first"); err == nil {
+		t.Fatal("Expected the first run to be cut short")
+	}
+
+	researcher := &mockResearcherClient{}
+	critic := &claimRecordingCritic{}
+	executor.clients.Researcher = researcher
+	executor.clients.Critic = critic
+	result, err := executor.ResumeRun(context.Background(), graph.ID, "run-resume-second")
+	if err != nil {
+		t.Fatalf("ResumeRun failed: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected the resumed run to succeed, got %+v", result)
+	}
+	if got := researcher.calls(); got != 0 {
+		t.Errorf("Expected the succeeded researcher to be skipped, got %d calls", got)
+	}
+	critic.mu.Lock()
+	claims := critic.claims
+	critic.mu.Unlock()
+	if len(claims) != 1 || claims[0] != "Test claim" {
+		t.Errorf("Critic received claims %v, want the researcher's persisted claim", claims)
+	}
+	if saved, err := executor.LoadRunResult("run-resume-second"); err != nil || !saved.Success {
+		t.Errorf("Expected the resumed run's result to be saved, got %+v, %v", saved, err)
+	}
+
+	if _, err := executor.ResumeRun(context.Background(), graph.ID, ""); !errors.Is(err, ErrGraphCompleted) {
+		t.Errorf("Resuming a completed graph returned %v, want ErrGraphCompleted", err)
+	}
+}
+
+func TestResumeRun_RequiresPersistedResults(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+
+	graph := researchCriticGraph("graph-resume-unpersisted", true)
+	if err := executor.persistInitialGraph(graph); err != nil {
+		t.Fatalf("Failed to persist graph: %v", err)
+	}
+
+	researcher := &mockResearcherClient{}
+	executor.clients.Researcher = researcher
+	if _, err := executor.ResumeRun(context.Background(), graph.ID, ""); !errors.Is(err, ErrResultsNotPersisted) {
+		t.Errorf("ResumeRun without persisted results returned %v, want ErrResultsNotPersisted", err)
+	}
+	if got := researcher.calls(); got != 0 {
+		t.Errorf("Expected nothing to run, got %d researcher calls", got)
+	}
+}
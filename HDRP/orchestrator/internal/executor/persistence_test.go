@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// slowStatusStorage delays every node status write.
+type slowStatusStorage struct {
+	storage.Storage
+	delay time.Duration
+}
+
+func (s *slowStatusStorage) UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error {
+	time.Sleep(s.delay)
+	return s.Storage.UpdateNodeStatus(graphID, nodeID, status, retryCount, lastError)
+}
+
+// startRecordingResearcher records when each research call starts.
+type startRecordingResearcher struct {
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (r *startRecordingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	r.starts = append(r.starts, time.Now())
+	r.mu.Unlock()
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: req.Query}}}, nil
+}
+
+func (r *startRecordingResearcher) spread() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	first, last := r.starts[0], r.starts[0]
+	for _, t := range r.starts {
+		if t.Before(first) {
+			first = t
+		}
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last.Sub(first)
+}
+
+func independentResearchGraph(id string, n int) *dag.Graph {
+	graph := &dag.Graph{ID: id, Status: dag.StatusCreated}
+	for i := 0; i < n; i++ {
+		graph.Nodes = append(graph.Nodes, dag.Node{
+			ID:     fmt.Sprintf("researcher%d", i),
+			Type:   "researcher",
+			Config: map[string]string{"query": "q"},
+			Status: dag.StatusCreated,
+		})
+	}
+	return graph
+}
+
+func TestAsyncPersistence_SlowStorageDoesNotBlockScheduling(t *testing.T) {
+	researcher := &startRecordingResearcher{}
+	executor := newTestExecutor(t, &clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	})
+
+	// Six waves of two nodes, each wave needing four status writes
+	const delay = 20 * time.Millisecond
+	const nodes = 12
+	executor.storage = &slowStatusStorage{Storage: executor.storage, delay: delay}
+	executor.SetAsyncPersistence(storage.DefaultAsyncQueueSize)
+
+	graph := independentResearchGraph("graph-slow-persist", nodes)
+	result, err := executor.Execute(context.Background(), graph, "run-slow-persist")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+	}
+
+	// With synchronous writes the last wave would start at least 20 writes
+	// after the first. Queued writes must not hold it up.
+	if spread := researcher.spread(); spread > 10*delay {
+		t.Errorf("Scheduling blocked on persistence: node starts spread over %v", spread)
+	}
+
+	// Execute drains the queue before returning
+	stored, err := executor.storage.LoadNodes(graph.ID)
+	if err != nil {
+		t.Fatalf("Failed to load nodes: %v", err)
+	}
+	for _, node := range stored {
+		if node.Status != string(dag.StatusSucceeded) {
+			t.Errorf("Expected persisted status SUCCEEDED for %s, got %s", node.NodeID, node.Status)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"hdrp/internal/dag"
+)
+
+// Run lifecycle events sent to the EventPublisher.
+const (
+	EventRunStarted    EventType = "run_started"
+	EventNodeCompleted EventType = "node_completed"
+	EventRunFinished   EventType = "run_finished"
+)
+
+// EventPublisher sends run lifecycle events to an external system such as a
+// message queue, for downstream consumers like analytics or billing.
+// Publish is called from the execution loop and must not block on the
+// network; implementations should queue and deliver in the background.
+type EventPublisher interface {
+	Publish(evt Event) error
+	// Close delivers or drops queued events and releases resources.
+	Close() error
+}
+
+// NopPublisher discards every event. It is the executor's default.
+type NopPublisher struct{}
+
+// Publish discards the event.
+func (NopPublisher) Publish(Event) error { return nil }
+
+// Close does nothing.
+func (NopPublisher) Close() error { return nil }
+
+// SetEventPublisher sends run lifecycle events to publisher. The executor
+// takes ownership and closes it in Close. nil restores the no-op default.
+func (e *DAGExecutor) SetEventPublisher(publisher EventPublisher) {
+	if publisher == nil {
+		publisher = NopPublisher{}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.publisher = publisher
+}
+
+// publishEvent hands an event to the publisher. Failures only lose the
+// event, so they are logged rather than failing the run.
+func (e *DAGExecutor) publishEvent(evt Event) {
+	e.mu.RLock()
+	publisher := e.publisher
+	e.mu.RUnlock()
+
+	if evt.Timestamp.IsZero() {
+		evt.Timestamp = time.Now()
+	}
+	if err := publisher.Publish(evt); err != nil {
+		execLog.Warnf("failed to publish %s event for run %s: %v", evt.Type, evt.RunID, err)
+	}
+}
+
+// publishRunStarted announces a run that passed validation and is starting.
+func (e *DAGExecutor) publishRunStarted(graph *dag.Graph, runID string) {
+	e.publishEvent(Event{
+		Type:    EventRunStarted,
+		RunID:   runID,
+		GraphID: graph.ID,
+		Data:    map[string]string{"nodes": strconv.Itoa(len(graph.Nodes))},
+	})
+}
+
+// publishNodeCompleted announces a node reaching a final status.
+func (e *DAGExecutor) publishNodeCompleted(graph *dag.Graph, runID string, result *NodeResult, status dag.Status) {
+	data := map[string]string{"status": string(status)}
+	for _, node := range graph.NodeSnapshot() {
+		if node.ID == result.NodeID {
+			data["node_type"] = node.Type
+			break
+		}
+	}
+	if result.Error != nil {
+		data["error"] = result.Error.Error()
+	}
+	e.publishEvent(Event{
+		Type:    EventNodeCompleted,
+		RunID:   runID,
+		GraphID: graph.ID,
+		NodeID:  result.NodeID,
+		Data:    data,
+	})
+}
+
+// publishRunFinished announces the end of a run, however it ended. A graph
+// left running was interrupted: cancelled if ctx ended, failed otherwise.
+func (e *DAGExecutor) publishRunFinished(ctx context.Context, graph *dag.Graph, runID string, start time.Time) {
+	status := graph.Status
+	if status == dag.StatusRunning {
+		status = dag.StatusFailed
+		if ctx.Err() != nil {
+			status = dag.StatusCancelled
+		}
+	}
+	e.publishEvent(Event{
+		Type:    EventRunFinished,
+		RunID:   runID,
+		GraphID: graph.ID,
+		Data: map[string]string{
+			"status":      string(status),
+			"duration_ms": strconv.FormatInt(time.Since(start).Milliseconds(), 10),
+		},
+	})
+}
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+)
+
+// channelPublisher delivers events on a buffered channel.
+type channelPublisher struct {
+	events chan Event
+	closed bool
+}
+
+func (p *channelPublisher) Publish(evt Event) error {
+	p.events <- evt
+	return nil
+}
+
+func (p *channelPublisher) Close() error {
+	p.closed = true
+	return nil
+}
+
+func TestEventPublisher_SimpleRun(t *testing.T) {
+	publisher := &channelPublisher{events: make(chan Event, 16)}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetEventPublisher(publisher)
+
+	graph := researchCriticGraph("publish-simple", true)
+	result, err := executor.Execute(context.Background(), graph, "run-publish-simple")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+	close(publisher.events)
+
+	var got []Event
+	for evt := range publisher.events {
+		if evt.RunID != "run-publish-simple" || evt.GraphID != graph.ID || evt.Timestamp.IsZero() {
+			t.Errorf("Event missing run details: %+v", evt)
+		}
+		got = append(got, evt)
+	}
+
+	want := []struct {
+		typ    EventType
+		nodeID string
+	}{
+		{EventRunStarted, ""},
+		{EventNodeCompleted, "researcher1"},
+		{EventNodeCompleted, "critic1"},
+		{EventNodeCompleted, "synthesizer1"},
+		{EventRunFinished, ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(want), len(got), got)
+	}
+	for i, w := range want {
+		if got[i].Type != w.typ || got[i].NodeID != w.nodeID {
+			t.Errorf("Event %d = %s %s, want %s %s", i, got[i].Type, got[i].NodeID, w.typ, w.nodeID)
+		}
+	}
+	if status := got[1].Data["status"]; status != string(dag.StatusSucceeded) {
+		t.Errorf("Expected node_completed status SUCCEEDED, got %q", status)
+	}
+	if got[1].Data["node_type"] != "researcher" {
+		t.Errorf("Expected node_completed node type researcher, got %q", got[1].Data["node_type"])
+	}
+	if status := got[4].Data["status"]; status != string(dag.StatusSucceeded) {
+		t.Errorf("Expected run_finished status SUCCEEDED, got %q", status)
+	}
+
+	executor.Close()
+	if !publisher.closed {
+		t.Error("Expected Close to close the publisher")
+	}
+}
@@ -0,0 +1,111 @@
+package executor
+
+import (
+	"fmt"
+
+	"hdrp/internal/dag"
+)
+
+// ResumeFailureStore is implemented by storage backends that persist how
+// many resumed runs each node has failed in.
+type ResumeFailureStore interface {
+	RecordResumeFailure(graphID string, nodeID string, lastError string) (int, error)
+	LoadResumeFailures(graphID string) (map[string]int, error)
+}
+
+// SetQuarantineThreshold quarantines nodes that have failed in at least
+// threshold resumed runs of the same graph. A quarantined node is marked
+// FAILED without being executed and its descendants are skipped, so the
+// run can finish as a partial success. threshold <= 0 disables quarantine;
+// failures are still counted.
+func (e *DAGExecutor) SetQuarantineThreshold(threshold int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.quarantineThreshold = threshold
+}
+
+// recordResumeFailures counts every node that had FAILED when the previous
+// run of graph stopped, then returns the nodes whose count has reached the
+// quarantine threshold. Nodes merely in flight when it stopped are not
+// counted. Must be called before statuses are reset.
+func (e *DAGExecutor) recordResumeFailures(graph *dag.Graph) map[string]int {
+	store, ok := e.storage.(ResumeFailureStore)
+	if !ok {
+		return nil
+	}
+
+	for _, node := range graph.Nodes {
+		if node.Status != dag.StatusFailed {
+			continue
+		}
+		if _, err := store.RecordResumeFailure(graph.ID, node.ID, node.LastError); err != nil {
+			recoveryLog.Warnf("%v", err)
+		}
+	}
+
+	e.mu.RLock()
+	threshold := e.quarantineThreshold
+	e.mu.RUnlock()
+	if threshold <= 0 {
+		return nil
+	}
+
+	failures, err := store.LoadResumeFailures(graph.ID)
+	if err != nil {
+		recoveryLog.Warnf("quarantine skipped for graph %s: %v", graph.ID, err)
+		return nil
+	}
+
+	quarantined := make(map[string]int)
+	for nodeID, count := range failures {
+		if count >= threshold {
+			quarantined[nodeID] = count
+		}
+	}
+	return quarantined
+}
+
+// applyQuarantine marks quarantined nodes FAILED and cancels everything
+// downstream of them. Statuses are assigned directly because the graph has
+// just been reset and hasn't started executing.
+func applyQuarantine(graph *dag.Graph, quarantined map[string]int) {
+	if len(quarantined) == 0 {
+		return
+	}
+
+	children := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+	}
+
+	skippedBy := make(map[string]string)
+	for nodeID := range quarantined {
+		queue := append([]string(nil), children[nodeID]...)
+		for len(queue) > 0 {
+			child := queue[0]
+			queue = queue[1:]
+			if _, ok := quarantined[child]; ok {
+				continue
+			}
+			if _, seen := skippedBy[child]; seen {
+				continue
+			}
+			skippedBy[child] = nodeID
+			queue = append(queue, children[child]...)
+		}
+	}
+
+	for i := range graph.Nodes {
+		node := &graph.Nodes[i]
+		if count, ok := quarantined[node.ID]; ok {
+			node.Status = dag.StatusFailed
+			node.LastError = fmt.Sprintf("quarantined after failing in %d resumed runs", count)
+			recoveryLog.Warnf("Quarantined node %s of graph %s after %d failed resumes", node.ID, graph.ID, count)
+			continue
+		}
+		if source, ok := skippedBy[node.ID]; ok {
+			node.Status = dag.StatusCancelled
+			node.LastError = fmt.Sprintf("skipped: depends on quarantined node %s", source)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// queryFailingResearcher permanently fails research for one query.
+type queryFailingResearcher struct {
+	failQuery string
+
+	mu       sync.Mutex
+	failures int
+}
+
+func (r *queryFailingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	if req.Query == r.failQuery {
+		r.mu.Lock()
+		r.failures++
+		r.mu.Unlock()
+		return nil, status.Error(codes.InvalidArgument, "unanswerable query")
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: req.Query}}}, nil
+}
+
+func (r *queryFailingResearcher) failedCalls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failures
+}
+
+func TestResumeGraph_QuarantinesRepeatedlyFailingNode(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	researcher := &queryFailingResearcher{failQuery: "bad"}
+	executor.clients.Researcher = researcher
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 0})
+	executor.SetQuarantineThreshold(3)
+
+	graph := researchCriticGraph("graph-quarantine", false)
+	graph.Metadata = map[string]string{"run_id": "run-quarantine"}
+	graph.Nodes = append(graph.Nodes,
+		dag.Node{ID: "bad", Type: "researcher", Config: map[string]string{"query": "bad"}, Status: dag.StatusCreated},
+		dag.Node{ID: "badcritic", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+	)
+	graph.Edges = append(graph.Edges, dag.Edge{From: "bad", To: "badcritic"})
+	if err := executor.persistInitialGraph(graph); err != nil {
+		t.Fatalf("Failed to persist graph: %v", err)
+	}
+	// The first run failed "bad" and then crashed
+	graph.SetStorage(executor.storage)
+	for _, status := range []dag.Status{dag.StatusRunning, dag.StatusFailed} {
+		if err := graph.SetNodeStatus("bad", status); err != nil {
+			t.Fatalf("Failed to mark node %s: %v", status, err)
+		}
+	}
+
+	var result *ExecutionResult
+	for cycle := 1; cycle <= 3; cycle++ {
+		// Each resumed run is itself interrupted and left RUNNING
+		if err := executor.storage.UpdateGraphStatus(graph.ID, string(dag.StatusRunning)); err != nil {
+			t.Fatalf("Failed to mark graph running: %v", err)
+		}
+		recovered, err := executor.RecoverGraph(graph.ID)
+		if err != nil {
+			t.Fatalf("Cycle %d: recovery failed: %v", cycle, err)
+		}
+		result, err = executor.ResumeGraph(context.Background(), recovered)
+		if err != nil {
+			t.Fatalf("Cycle %d: resume failed: %v", cycle, err)
+		}
+		if cycle < 3 && result.Success {
+			t.Fatalf("Cycle %d: expected failure before quarantine", cycle)
+		}
+	}
+
+	// Only the two resumes before quarantine attempted the node
+	if got := researcher.failedCalls(); got != 2 {
+		t.Errorf("Expected 2 attempts of the failing node, got %d", got)
+	}
+
+	if !result.PartialSuccess {
+		t.Fatalf("Expected partial success once the node is quarantined, got: %s", result.ErrorMessage)
+	}
+	if _, ok := result.FailedNodes["bad"]; !ok {
+		t.Errorf("Expected quarantined node in failed nodes, got %v", result.FailedNodes)
+	}
+
+	nodes, err := executor.storage.LoadNodes(graph.ID)
+	if err != nil {
+		t.Fatalf("Failed to load nodes: %v", err)
+	}
+	want := map[string]dag.Status{
+		"researcher1": dag.StatusSucceeded,
+		"critic1":     dag.StatusSucceeded,
+		"bad":         dag.StatusFailed,
+		"badcritic":   dag.StatusCancelled,
+	}
+	for _, node := range nodes {
+		if node.Status != string(want[node.NodeID]) {
+			t.Errorf("Expected %s to be %s, got %s", node.NodeID, want[node.NodeID], node.Status)
+		}
+	}
+}
+
+// TestRecordResumeFailures_IgnoresInFlightNodes verifies only nodes that had
+// failed count towards quarantine, not ones interrupted mid-run.
+func TestRecordResumeFailures_IgnoresInFlightNodes(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetQuarantineThreshold(1)
+
+	graph := &dag.Graph{ID: "graph-resume-failures", Nodes: []dag.Node{
+		{ID: "failed", Status: dag.StatusFailed, LastError: "boom"},
+		{ID: "running", Status: dag.StatusRunning},
+		{ID: "retrying", Status: dag.StatusRetrying},
+	}}
+	quarantined := executor.recordResumeFailures(graph)
+	if len(quarantined) != 1 || quarantined["failed"] != 1 {
+		t.Errorf("Quarantined %v, want only the failed node", quarantined)
+	}
+
+	failures, err := executor.storage.(ResumeFailureStore).LoadResumeFailures(graph.ID)
+	if err != nil {
+		t.Fatalf("LoadResumeFailures failed: %v", err)
+	}
+	if len(failures) != 1 || failures["failed"] != 1 {
+		t.Errorf("Recorded failures %v, want only the failed node", failures)
+	}
+}
@@ -0,0 +1,206 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+)
+
+const (
+	// DefaultRecoveryConcurrency is the number of graphs recovered at once.
+	DefaultRecoveryConcurrency = 2
+	// DefaultRecoveryLeaseTTL is how long a recovering worker owns a graph
+	// before other workers may reclaim it.
+	DefaultRecoveryLeaseTTL = 10 * time.Minute
+)
+
+// GraphClaimer is implemented by storage backends that support lease-based
+// claiming of abandoned graphs.
+type GraphClaimer interface {
+	ClaimResumableGraph(workerID string, leaseTTL time.Duration) (*storage.GraphState, error)
+	ReleaseGraphLease(graphID, workerID string) error
+}
+
+// RecoveryOptions configures RecoverAbandonedGraphs.
+type RecoveryOptions struct {
+	Concurrency int           // Max graphs recovered at once; <= 0 uses DefaultRecoveryConcurrency
+	WorkerID    string        // Lease owner; empty uses hostname and PID
+	LeaseTTL    time.Duration // <= 0 uses DefaultRecoveryLeaseTTL
+}
+
+// ResumeFunc resumes execution of a recovered graph.
+type ResumeFunc func(ctx context.Context, graph *dag.Graph) error
+
+// RecoverAbandonedGraphs claims RUNNING graphs whose owner went away and
+// resumes them with at most opts.Concurrency in flight. A nil resume uses
+// ResumeGraph. It returns once no resumable graphs remain, reporting how
+// many were resumed successfully.
+func (e *DAGExecutor) RecoverAbandonedGraphs(ctx context.Context, opts RecoveryOptions, resume ResumeFunc) (int, error) {
+	claimer, ok := e.storage.(GraphClaimer)
+	if !ok {
+		return 0, fmt.Errorf("storage backend does not support graph claiming")
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultRecoveryConcurrency
+	}
+	if opts.LeaseTTL <= 0 {
+		opts.LeaseTTL = DefaultRecoveryLeaseTTL
+	}
+	if opts.WorkerID == "" {
+		host, _ := os.Hostname()
+		opts.WorkerID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if resume == nil {
+		resume = func(ctx context.Context, graph *dag.Graph) error {
+			_, err := e.ResumeGraph(ctx, graph)
+			return err
+		}
+	}
+
+	recoveryLog.Infof("Recovering abandoned graphs with %d workers as %s", opts.Concurrency, opts.WorkerID)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		recovered int
+		firstErr  error
+		seen      = make(map[string]bool)
+	)
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				state, err := claimer.ClaimResumableGraph(opts.WorkerID, opts.LeaseTTL)
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("failed to claim graph: %w", err)
+					}
+					mu.Unlock()
+					return
+				}
+				if state == nil {
+					return // Nothing left to recover
+				}
+
+				// A graph left RUNNING by its resume would be reclaimed forever;
+				// hold its lease instead so it waits for a later pass.
+				mu.Lock()
+				again := seen[state.ID]
+				seen[state.ID] = true
+				mu.Unlock()
+				if again {
+					recoveryLog.Infof("Graph %s still RUNNING after resume, deferring until lease expires", state.ID)
+					continue
+				}
+
+				if err := e.recoverClaimedGraph(ctx, state.ID, resume); err != nil {
+					// Keep the lease so this pass doesn't reclaim the graph in a
+					// loop; it becomes claimable again once the lease expires.
+					recoveryLog.Errorf("Failed to resume graph %s: %v", state.ID, err)
+					continue
+				}
+				mu.Lock()
+				recovered++
+				mu.Unlock()
+
+				if err := claimer.ReleaseGraphLease(state.ID, opts.WorkerID); err != nil {
+					recoveryLog.Warnf("failed to release lease for graph %s: %v", state.ID, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	recoveryLog.Infof("Resumed %d abandoned graphs", recovered)
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = fmt.Errorf("recovery cancelled: %w", ctx.Err())
+	}
+	return recovered, firstErr
+}
+
+// recoverClaimedGraph loads a claimed graph and hands it to resume.
+func (e *DAGExecutor) recoverClaimedGraph(ctx context.Context, graphID string, resume ResumeFunc) error {
+	graph, err := e.RecoverGraph(graphID)
+	if err != nil {
+		return err
+	}
+	return resume(ctx, graph)
+}
+
+// ResumeGraph re-executes a recovered graph. Nodes start over unless node
+// results are persisted (see SetPersistNodeResults), in which case nodes
+// that already succeeded keep their results and only the rest run. The run ID is taken from the
+// graph's run_id metadata, falling back to the graph ID. Nodes that failed
+// in too many resumed runs are quarantined (see SetQuarantineThreshold), and
+// retry history may be carried over (see SetRestoreRetryMetrics).
+func (e *DAGExecutor) ResumeGraph(ctx context.Context, graph *dag.Graph) (*ExecutionResult, error) {
+	runID := graph.Metadata["run_id"]
+	if runID == "" {
+		runID = graph.ID
+	}
+
+	quarantined := e.recordResumeFailures(graph)
+	restored := e.restoredRetryMetrics(graph.ID)
+	completed := e.persistedResults(graph)
+
+	// Reset lifecycle state directly: the state machine has no edge from
+	// in-flight or terminal statuses back to CREATED.
+	graph.Status = dag.StatusCreated
+	for i := range graph.Nodes {
+		graph.Nodes[i].Phase = ""
+		if !completed[graph.Nodes[i].ID] {
+			graph.Nodes[i].Status = dag.StatusCreated
+		}
+	}
+	if len(completed) > 0 {
+		recoveryLog.Infof("Reusing persisted results of %d succeeded nodes in graph %s", len(completed), graph.ID)
+	}
+	applyQuarantine(graph, quarantined)
+
+	recoveryLog.Infof("Resuming graph %s as run %s", graph.ID, runID)
+	return e.ExecuteWithOptions(ctx, graph, runID, RunOptions{RetryMetrics: restored})
+}
+
+var (
+	// ErrGraphCompleted is returned by ResumeRun for a graph that already
+	// succeeded, so there is nothing left to run.
+	ErrGraphCompleted = errors.New("graph already completed")
+	// ErrResultsNotPersisted is returned by ResumeRun when node results
+	// aren't persisted, so succeeded nodes couldn't be kept.
+	ErrResultsNotPersisted = errors.New("node results are not persisted")
+)
+
+// ResumeRun loads graphID from storage and continues it as runID, or under
+// its recorded run ID if runID is empty. Succeeded nodes whose results were
+// persisted are kept and only the remaining nodes are scheduled, so it
+// requires SetPersistNodeResults: without stored results every node would
+// run again, and it returns ErrResultsNotPersisted instead. Use
+// RecoverGraph and ResumeGraph to rerun a graph from the start.
+func (e *DAGExecutor) ResumeRun(ctx context.Context, graphID, runID string) (*ExecutionResult, error) {
+	if !e.persistingNodeResults() {
+		return nil, fmt.Errorf("%w: cannot resume graph %s", ErrResultsNotPersisted, graphID)
+	}
+	graph, err := e.RecoverGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if graph.Status == dag.StatusSucceeded {
+		return nil, fmt.Errorf("%w: %s", ErrGraphCompleted, graphID)
+	}
+	if runID != "" {
+		if graph.Metadata == nil {
+			graph.Metadata = make(map[string]string)
+		}
+		graph.Metadata["run_id"] = runID
+	}
+	return e.ResumeGraph(ctx, graph)
+}
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/dag"
+)
+
+// seedAbandonedGraphs persists graphs as if their runs crashed mid-execution.
+func seedAbandonedGraphs(t *testing.T, executor *DAGExecutor, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		graph := researchCriticGraph(fmt.Sprintf("abandoned-%d", i), false)
+		graph.Metadata = map[string]string{"run_id": fmt.Sprintf("run-abandoned-%d", i)}
+		if err := executor.persistInitialGraph(graph); err != nil {
+			t.Fatalf("Failed to persist graph: %v", err)
+		}
+		if err := executor.storage.UpdateGraphStatus(graph.ID, string(dag.StatusRunning)); err != nil {
+			t.Fatalf("Failed to mark graph running: %v", err)
+		}
+		ids[i] = graph.ID
+	}
+	return ids
+}
+
+func TestRecoverAbandonedGraphs_RespectsConcurrency(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	ids := seedAbandonedGraphs(t, executor, 6)
+
+	var (
+		mu       sync.Mutex
+		inFlight int
+		maxSeen  int
+		resumed  = make(map[string]bool)
+	)
+	resume := func(ctx context.Context, graph *dag.Graph) error {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		resumed[graph.ID] = true
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return executor.storage.UpdateGraphStatus(graph.ID, string(dag.StatusSucceeded))
+	}
+
+	count, err := executor.RecoverAbandonedGraphs(context.Background(), RecoveryOptions{Concurrency: 2, WorkerID: "worker-test"}, resume)
+	if err != nil {
+		t.Fatalf("RecoverAbandonedGraphs() error = %v", err)
+	}
+
+	if count != len(ids) {
+		t.Errorf("Expected %d recovered graphs, got %d", len(ids), count)
+	}
+	for _, id := range ids {
+		if !resumed[id] {
+			t.Errorf("Graph %s was not resumed", id)
+		}
+	}
+	if maxSeen > 2 {
+		t.Errorf("Expected at most 2 concurrent recoveries, saw %d", maxSeen)
+	}
+}
+
+func TestRecoverAbandonedGraphs_DefaultResume(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	ids := seedAbandonedGraphs(t, executor, 3)
+
+	count, err := executor.RecoverAbandonedGraphs(context.Background(), RecoveryOptions{Concurrency: 2}, nil)
+	if err != nil {
+		t.Fatalf("RecoverAbandonedGraphs() error = %v", err)
+	}
+	if count != len(ids) {
+		t.Errorf("Expected %d recovered graphs, got %d", len(ids), count)
+	}
+
+	for _, id := range ids {
+		state, err := executor.storage.LoadGraph(id)
+		if err != nil {
+			t.Fatalf("Failed to load graph %s: %v", id, err)
+		}
+		if state.Status != string(dag.StatusSucceeded) {
+			t.Errorf("Expected graph %s to be SUCCEEDED after resume, got %s", id, state.Status)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/metrics"
+	"hdrp/internal/retry"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrReplayUnsupported is returned when the storage backend can't find a
+	// run's stored nodes.
+	ErrReplayUnsupported = errors.New("storage backend does not support node replay")
+	// ErrNodeNotFound is returned when a run's graph has no such node.
+	ErrNodeNotFound = errors.New("node not found")
+)
+
+// ReplayStore is implemented by storage backends that can reload a node of
+// a run, with its parents' results, for replay.
+type ReplayStore interface {
+	FindGraphByRunID(runID string) (string, error)
+	LoadNodes(graphID string) ([]*storage.NodeState, error)
+	LoadEdges(graphID string) ([]*storage.EdgeState, error)
+	LoadNodeResult(graphID string, nodeID string) ([]byte, error)
+}
+
+// CapturedCall is a service RPC made while replaying a node.
+type CapturedCall struct {
+	Service    string          `json:"service"`
+	Method     string          `json:"method"`
+	Request    json.RawMessage `json:"request"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	Code       string          `json:"code,omitempty"` // gRPC status code of a failed call
+	DurationMs float64         `json:"duration_ms"`
+}
+
+// ReplayDiagnostic describes one replay of a stored node: what it was given,
+// every request it sent and what came back, and how the attempt ended.
+type ReplayDiagnostic struct {
+	RunID        string            `json:"run_id"`
+	GraphID      string            `json:"graph_id"`
+	NodeID       string            `json:"node_id"`
+	NodeType     string            `json:"node_type"`
+	Config       map[string]string `json:"config,omitempty"`
+	StoredStatus string            `json:"stored_status"`
+	StoredError  string            `json:"stored_error,omitempty"`
+
+	Parents        []string `json:"parents,omitempty"`         // Parents whose stored results were passed in
+	MissingParents []string `json:"missing_parents,omitempty"` // Parents with no stored result
+
+	Calls      []CapturedCall `json:"calls"`
+	Success    bool           `json:"success"`
+	Error      string         `json:"error,omitempty"`
+	ErrorType  string         `json:"error_type,omitempty"` // Retry classification of Error
+	DurationMs float64        `json:"duration_ms"`
+	TraceID    string         `json:"trace_id,omitempty"`
+}
+
+// ReplayNode re-executes a single node of a stored run for debugging. The
+// node's config and its parents' stored results are reloaded from storage,
+// and the node runs once, traced, with every service request and response
+// captured. Parents are not re-run and nothing is written back, so the
+// stored run is unaffected. Parent results are only stored when node result
+// persistence is on (see SetPersistNodeResults) or results were offloaded;
+// parents without one are listed in MissingParents.
+func (e *DAGExecutor) ReplayNode(ctx context.Context, runID, nodeID string) (*ReplayDiagnostic, error) {
+	store, ok := e.storage.(ReplayStore)
+	if !ok {
+		return nil, ErrReplayUnsupported
+	}
+	graphID, err := store.FindGraphByRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+	if graphID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+
+	graph, err := loadReplayGraph(store, graphID)
+	if err != nil {
+		return nil, err
+	}
+	var node *dag.Node
+	for i := range graph.Nodes {
+		if graph.Nodes[i].ID == nodeID {
+			node = &graph.Nodes[i]
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("%w: %s in run %s", ErrNodeNotFound, nodeID, runID)
+	}
+
+	diag := &ReplayDiagnostic{
+		RunID:        runID,
+		GraphID:      graphID,
+		NodeID:       node.ID,
+		NodeType:     node.Type,
+		Config:       node.Config,
+		StoredStatus: string(node.Status),
+		StoredError:  node.LastError,
+		Calls:        []CapturedCall{},
+	}
+
+	parentResults := make(map[string]*NodeResult)
+	for _, edge := range graph.Edges {
+		if edge.To != nodeID {
+			continue
+		}
+		data, err := store.LoadNodeResult(graphID, edge.From)
+		if err != nil {
+			diag.MissingParents = append(diag.MissingParents, edge.From)
+			continue
+		}
+		result, err := decodeNodeResult(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load result of parent %s: %w", edge.From, err)
+		}
+		parentResults[edge.From] = result
+		diag.Parents = append(diag.Parents, edge.From)
+	}
+
+	recorder := &callRecorder{}
+	ctx = withProvider(ctx, recorder.wrap(e.clients))
+	ctx = withoutResultCache(ctx)
+	ctx, span := metrics.StartSpan(ctx, "node.replay",
+		attribute.String("node.id", node.ID),
+		attribute.String("node.type", node.Type),
+		attribute.String("run.id", runID),
+	)
+	defer span.End()
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		diag.TraceID = sc.TraceID().String()
+	}
+
+	execLog.Infof("Replaying node %s of run %s (stored status %s, %d parent results)", nodeID, runID, node.Status, len(parentResults))
+	execCtx, cancel := context.WithTimeout(ctx, e.attemptTimeout(ctx, node))
+	start := time.Now()
+	result := e.executeNode(execCtx, node, graph, parentResults, runID)
+	cancel()
+
+	diag.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+	diag.Calls = append(diag.Calls, recorder.calls()...)
+	diag.Success = result.Success
+	if result.Error != nil {
+		diag.Error = result.Error.Error()
+		diag.ErrorType = retry.ClassifyError(result.Error).String()
+	}
+	return diag, nil
+}
+
+// loadReplayGraph rebuilds a stored graph without storage attached, so
+// nothing done to it during a replay is persisted.
+func loadReplayGraph(store ReplayStore, graphID string) (*dag.Graph, error) {
+	nodes, err := store.LoadNodes(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	edges, err := store.LoadEdges(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edges: %w", err)
+	}
+
+	graph := &dag.Graph{ID: graphID, Status: dag.StatusRunning}
+	for _, n := range nodes {
+		graph.Nodes = append(graph.Nodes, dag.Node{
+			ID:             n.NodeID,
+			Type:           n.Type,
+			Config:         n.Config,
+			Status:         dag.Status(n.Status),
+			RelevanceScore: n.RelevanceScore,
+			Depth:          n.Depth,
+			RetryCount:     n.RetryCount,
+			LastError:      n.LastError,
+		})
+	}
+	for _, e := range edges {
+		graph.Edges = append(graph.Edges, dag.Edge{From: e.From, To: e.To, Condition: e.Condition})
+	}
+	return graph, nil
+}
+
+// callRecorder captures the service calls of a replay.
+type callRecorder struct {
+	mu       sync.Mutex
+	captured []CapturedCall
+}
+
+// wrap returns clients that record every call before passing it on. Only
+// the unary RPCs are wrapped, so a replayed node never streams and each
+// request and response is captured whole.
+func (r *callRecorder) wrap(c *clients.ServiceClients) *clients.ServiceClients {
+	wrapped := &clients.ServiceClients{}
+	if c == nil {
+		return wrapped
+	}
+	if c.Researcher != nil {
+		wrapped.Researcher = &recordingResearcher{next: c.Researcher, rec: r}
+	}
+	if c.Critic != nil {
+		wrapped.Critic = &recordingCritic{next: c.Critic, rec: r}
+	}
+	if c.Synthesizer != nil {
+		wrapped.Synthesizer = &recordingSynthesizer{next: c.Synthesizer, rec: r}
+	}
+	return wrapped
+}
+
+func (r *callRecorder) record(service, method string, req, resp proto.Message, err error, start time.Time) {
+	call := CapturedCall{
+		Service:    service,
+		Method:     method,
+		Request:    marshalCaptured(req),
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		call.Error = err.Error()
+		if st, ok := status.FromError(err); ok {
+			call.Code = st.Code().String()
+		}
+	} else {
+		call.Response = marshalCaptured(resp)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.captured = append(r.captured, call)
+}
+
+func (r *callRecorder) calls() []CapturedCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]CapturedCall(nil), r.captured...)
+}
+
+// marshalCaptured renders a captured message as JSON, or null if it can't be.
+func marshalCaptured(m proto.Message) json.RawMessage {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		execLog.Warnf("failed to capture %T: %v", m, err)
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+type recordingResearcher struct {
+	next clients.ResearcherClient
+	rec  *callRecorder
+}
+
+func (c *recordingResearcher) Research(ctx context.Context, in *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	start := time.Now()
+	resp, err := c.next.Research(ctx, in, opts...)
+	c.rec.record("researcher", "Research", in, resp, err, start)
+	return resp, err
+}
+
+type recordingCritic struct {
+	next pb.CriticServiceClient
+	rec  *callRecorder
+}
+
+func (c *recordingCritic) Verify(ctx context.Context, in *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	start := time.Now()
+	resp, err := c.next.Verify(ctx, in, opts...)
+	c.rec.record("critic", "Verify", in, resp, err, start)
+	return resp, err
+}
+
+type recordingSynthesizer struct {
+	next clients.SynthesizerClient
+	rec  *callRecorder
+}
+
+func (c *recordingSynthesizer) Synthesize(ctx context.Context, in *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
+	start := time.Now()
+	resp, err := c.next.Synthesize(ctx, in, opts...)
+	c.rec.record("synthesizer", "Synthesize", in, resp, err, start)
+	return resp, err
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rejectingCriticClient fails every verification with InvalidArgument.
+type rejectingCriticClient struct{}
+
+func (c *rejectingCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	return nil, status.Error(codes.InvalidArgument, "claim batch rejected")
+}
+
+func TestReplayNode_FailedNode(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetPersistNodeResults(true)
+	executor.clients.Critic = &rejectingCriticClient{}
+
+	graph := researchCriticGraph("graph-replay", false)
+	result, err := executor.Execute(context.Background(), graph, "run-replay")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected the run to fail at the critic")
+	}
+
+	diag, err := executor.ReplayNode(context.Background(), "run-replay", "critic1")
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if diag.StoredStatus != string(dag.StatusFailed) || diag.NodeType != "critic" {
+		t.Errorf("Unexpected stored node: status %s, type %s", diag.StoredStatus, diag.NodeType)
+	}
+	if len(diag.Parents) != 1 || diag.Parents[0] != "researcher1" {
+		t.Errorf("Expected researcher1's stored result as input, got parents %v (missing %v)", diag.Parents, diag.MissingParents)
+	}
+
+	if len(diag.Calls) == 0 {
+		t.Fatal("Expected the Verify call to be captured")
+	}
+	call := diag.Calls[0]
+	if call.Service != "critic" || call.Method != "Verify" {
+		t.Errorf("Captured %s/%s, want critic/Verify", call.Service, call.Method)
+	}
+	if !strings.Contains(string(call.Request), "Test claim") {
+		t.Errorf("Captured request %s does not carry the parent's claim", call.Request)
+	}
+	if call.Code != codes.InvalidArgument.String() || call.Response != nil {
+		t.Errorf("Expected a failed call with code InvalidArgument, got %+v", call)
+	}
+
+	if diag.Success || diag.Error == "" {
+		t.Errorf("Expected the replay to fail, got %+v", diag)
+	}
+	if diag.ErrorType != "Permanent" {
+		t.Errorf("Expected error type Permanent, got %q", diag.ErrorType)
+	}
+
+	// The stored run is untouched
+	nodes, err := executor.storage.LoadNodes(graph.ID)
+	if err != nil {
+		t.Fatalf("LoadNodes failed: %v", err)
+	}
+	for _, n := range nodes {
+		if n.NodeID == "critic1" && (n.Status != string(dag.StatusFailed) || n.LastError != diag.StoredError) {
+			t.Errorf("Replay changed the stored critic: %+v", n)
+		}
+	}
+
+	if _, err := executor.ReplayNode(context.Background(), "run-replay", "missing"); !errors.Is(err, ErrNodeNotFound) {
+		t.Errorf("Expected ErrNodeNotFound, got %v", err)
+	}
+	if _, err := executor.ReplayNode(context.Background(), "run-unknown", "critic1"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Expected ErrRunNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"hdrp/internal/artifacts"
+)
+
+// SetReportLimit caps how much of a synthesizer report is held in memory.
+// Reports larger than maxBytes are streamed to the artifact store as they
+// arrive; the node result, ExecutionResult and API response then carry only
+// the first maxBytes as a preview plus the artifact URI. maxBytes <= 0 or a
+// nil store keeps whole reports in memory.
+func (e *DAGExecutor) SetReportLimit(maxBytes int, store artifacts.Store) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxReportBytes = maxBytes
+	e.artifactStore = store
+}
+
+// reportBuffer assembles a report, keeping at most limit bytes in memory and
+// spilling the full report to an artifact once it grows past the limit.
+type reportBuffer struct {
+	limit   int
+	store   artifacts.Store
+	name    string
+	preview []byte
+	size    int64
+	w       io.WriteCloser // Open artifact once spilled
+	uri     string
+	stored  bool // Finish completed the artifact
+}
+
+// newReportBuffer creates the buffer for one synthesizer attempt.
+func (e *DAGExecutor) newReportBuffer(runID, nodeID string) *reportBuffer {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	b := &reportBuffer{name: fmt.Sprintf("%s-%s-report.md", runID, nodeID)}
+	if e.maxReportBytes > 0 && e.artifactStore != nil {
+		b.limit = e.maxReportBytes
+		b.store = e.artifactStore
+	}
+	return b
+}
+
+// WriteString appends part of the report.
+func (b *reportBuffer) WriteString(s string) error {
+	b.size += int64(len(s))
+
+	if b.w != nil {
+		_, err := io.WriteString(b.w, s)
+		return err
+	}
+	if b.limit <= 0 || len(b.preview)+len(s) <= b.limit {
+		b.preview = append(b.preview, s...)
+		return nil
+	}
+
+	// Over the limit: move what we have to the artifact and stream from here on
+	w, uri, err := b.store.Create(b.name)
+	if err != nil {
+		return fmt.Errorf("failed to spill oversized report: %w", err)
+	}
+	b.w, b.uri = w, uri
+	if _, err := b.w.Write(b.preview); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(b.w, s); err != nil {
+		return err
+	}
+	b.preview = append(b.preview, s[:b.limit-len(b.preview)]...)
+	execLog.Infof("Report %s exceeded %d bytes, streaming to %s", b.name, b.limit, uri)
+	return nil
+}
+
+// Spilled reports whether the report outgrew the limit.
+func (b *reportBuffer) Spilled() bool {
+	return b.uri != ""
+}
+
+// Finish closes the artifact, if any, and returns the in-memory report: the
+// whole report, or a preview cut at a rune boundary if it was spilled.
+func (b *reportBuffer) Finish() (string, error) {
+	if b.w == nil {
+		return string(b.preview), nil
+	}
+	err := b.w.Close()
+	b.w = nil
+	if err != nil {
+		return "", fmt.Errorf("failed to store oversized report: %w", err)
+	}
+	b.stored = true
+	return trimPartialRune(string(b.preview)), nil
+}
+
+// Abort deletes a spilled artifact after a failed synthesis, so no partial
+// report is left behind. It does nothing once Finish has succeeded.
+func (b *reportBuffer) Abort() {
+	if b.uri == "" || b.stored {
+		return
+	}
+	if b.w != nil {
+		b.w.Close()
+		b.w = nil
+	}
+	if err := b.store.Delete(b.uri); err != nil {
+		execLog.Warnf("Report %s: failed to delete partial artifact: %v", b.name, err)
+	}
+	b.uri = ""
+}
+
+// trimPartialRune drops a multi-byte character cut off at the end of s.
+func trimPartialRune(s string) string {
+	for i := 0; i < utf8.UTFMax && len(s) > 0; i++ {
+		r, size := utf8.DecodeLastRuneInString(s)
+		if r != utf8.RuneError || size != 1 {
+			break
+		}
+		s = s[:len(s)-1]
+	}
+	return s
+}
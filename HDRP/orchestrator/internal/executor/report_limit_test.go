@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hdrp/internal/artifacts"
+	"hdrp/internal/clients"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReportLimit_SpillsLargeReport(t *testing.T) {
+	// 10 KB report in 100 byte chunks
+	var chunks []string
+	for i := 0; i < 100; i++ {
+		chunks = append(chunks, strings.Repeat(string(rune('a'+i%26)), 100))
+	}
+	full := strings.Join(chunks, "")
+
+	tests := []struct {
+		name          string
+		unimplemented bool
+	}{
+		{name: "Streaming"},
+		{name: "Unary", unimplemented: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := artifacts.NewFileStore(dir)
+			if err != nil {
+				t.Fatalf("Failed to create artifact store: %v", err)
+			}
+
+			executor := NewDAGExecutor(&clients.ServiceClients{
+				Researcher:  &mockResearcherClient{},
+				Critic:      &echoCriticClient{},
+				Synthesizer: &streamingSynthesizerClient{chunks: chunks, unimplemented: tt.unimplemented},
+			}, 2)
+			executor.SetReportLimit(256, store)
+
+			result, err := executor.Execute(context.Background(), researchCriticGraph("report-limit-"+tt.name, true), "run-report-limit")
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+			}
+
+			if !result.ReportTruncated {
+				t.Error("Expected report to be marked truncated")
+			}
+			if result.FinalReport != full[:256] {
+				t.Errorf("Expected 256 byte preview, got %d bytes", len(result.FinalReport))
+			}
+
+			path := filepath.Join(dir, "run-report-limit-synthesizer1-report.md")
+			if want := "file://" + filepath.ToSlash(path); result.ArtifactURI != want {
+				t.Errorf("ArtifactURI = %s, want %s", result.ArtifactURI, want)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read artifact: %v", err)
+			}
+			if string(data) != full {
+				t.Errorf("Artifact holds %d bytes, want the full %d byte report", len(data), len(full))
+			}
+		})
+	}
+}
+
+func TestReportLimit_FailedSynthesisLeavesNoArtifact(t *testing.T) {
+	dir := t.TempDir()
+	store, err := artifacts.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create artifact store: %v", err)
+	}
+
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher: &mockResearcherClient{},
+		Critic:     &echoCriticClient{},
+		Synthesizer: &streamingSynthesizerClient{
+			chunks:    []string{strings.Repeat("a", 200), strings.Repeat("b", 200)},
+			streamErr: status.Error(codes.InvalidArgument, "synthesis failed"),
+		},
+	}, 2)
+	executor.SetReportLimit(256, store)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("report-limit-failed", true), "run-report-failed")
+	if err == nil && result.Success {
+		t.Fatal("Expected the synthesis to fail")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the partial artifact to be deleted, found %d artifacts", len(entries))
+	}
+}
+
+func TestReportLimit_SmallReportStaysInMemory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := artifacts.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("Failed to create artifact store: %v", err)
+	}
+
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &streamingSynthesizerClient{chunks: []string{"short ", "report"}},
+	}, 2)
+	executor.SetReportLimit(256, store)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("report-limit-small", true), "run-report-small")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.FinalReport != "short report" || result.ReportTruncated {
+		t.Errorf("Expected full report in memory, got %q (truncated=%v)", result.FinalReport, result.ReportTruncated)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no artifacts, found %d", len(entries))
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	s := "naïve"
+	if got := trimPartialRune(s[:3]); got != "na" {
+		t.Errorf("trimPartialRune() = %q, want %q", got, "na")
+	}
+	if got := trimPartialRune(s); got != s {
+		t.Errorf("trimPartialRune() = %q, want %q", got, s)
+	}
+}
@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRequeueCooldown is how long a requeued node waits before it may be
+// scheduled again when the policy doesn't set a cooldown.
+const DefaultRequeueCooldown = time.Second
+
+// RequeuePolicy selects failures that send a node back to the scheduler
+// instead of retrying in place. A requeued node gives up its worker slot and
+// becomes PENDING again once the cooldown has passed, so a rate-limited
+// backend doesn't keep slots idle that other nodes could use. Requeues count
+// against the node's retry budget like in-place retries.
+type RequeuePolicy struct {
+	Codes    []codes.Code  // gRPC codes to requeue on, e.g. ResourceExhausted; empty disables
+	Cooldown time.Duration // Wait before rescheduling; <= 0 uses DefaultRequeueCooldown
+}
+
+// SetRequeuePolicy enables requeue with cooldown for the policy's error codes.
+func (e *DAGExecutor) SetRequeuePolicy(policy RequeuePolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requeuePolicy = policy
+}
+
+// ParseRequeueCodes converts config code names such as "RESOURCE_EXHAUSTED"
+// to gRPC codes.
+func ParseRequeueCodes(names []string) ([]codes.Code, error) {
+	parsed := make([]codes.Code, 0, len(names))
+	for _, name := range names {
+		var code codes.Code
+		quoted := `"` + strings.ToUpper(strings.TrimSpace(name)) + `"`
+		if err := code.UnmarshalJSON([]byte(quoted)); err != nil {
+			return nil, fmt.Errorf("invalid requeue code %q", name)
+		}
+		parsed = append(parsed, code)
+	}
+	return parsed, nil
+}
+
+// matches reports whether err should requeue the node.
+func (p RequeuePolicy) matches(err error) bool {
+	if err == nil || len(p.Codes) == 0 {
+		return false
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range p.Codes {
+		if st.Code() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// cooldown returns the wait before a requeued node is rescheduled.
+func (p RequeuePolicy) cooldown() time.Duration {
+	if p.Cooldown <= 0 {
+		return DefaultRequeueCooldown
+	}
+	return p.Cooldown
+}
+
+// waitRequeue hands nodeID back to the execution loop after delay.
+func waitRequeue(ctx context.Context, nodeID string, delay time.Duration, requeueChan chan<- string) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return
+	}
+	select {
+	case requeueChan <- nodeID:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,139 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitedResearcher rejects the first call for each listed node with
+// ResourceExhausted and records the order in which nodes are called.
+type rateLimitedResearcher struct {
+	mu      sync.Mutex
+	limited map[string]bool
+	calls   []string
+}
+
+func (r *rateLimitedResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, req.SourceNodeId)
+	if r.limited[req.SourceNodeId] {
+		r.limited[req.SourceNodeId] = false
+		return nil, status.Error(codes.ResourceExhausted, "quota exceeded")
+	}
+	return &pb.ResearchResponse{
+		Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}},
+	}, nil
+}
+
+func TestRequeueResourceExhausted(t *testing.T) {
+	researcher := &rateLimitedResearcher{limited: map[string]bool{"a": true}}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 1)
+	// In-place backoff would hold the only worker slot for 10s
+	executor.retryPolicy = &retry.RetryPolicy{
+		MaxAttempts:       2,
+		InitialDelay:      10 * time.Second,
+		BackoffMultiplier: 1,
+		MaxDelay:          10 * time.Second,
+	}
+	executor.SetRequeuePolicy(RequeuePolicy{
+		Codes:    []codes.Code{codes.ResourceExhausted},
+		Cooldown: 100 * time.Millisecond,
+	})
+
+	graph := &dag.Graph{
+		ID:     "requeue-test",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "a", Type: "researcher", Config: map[string]string{"query": "a"}, Status: dag.StatusCreated},
+			{ID: "b", Type: "researcher", Config: map[string]string{"query": "b"}, Status: dag.StatusCreated},
+		},
+	}
+
+	start := time.Now()
+	result, err := executor.Execute(context.Background(), graph, "requeue-run")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %v, expected requeue instead of in-place backoff", elapsed)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+	}
+
+	// b ran in the slot a gave up while cooling down
+	if want := []string{"a", "b", "a"}; !reflect.DeepEqual(researcher.calls, want) {
+		t.Errorf("Call order = %v, want %v", researcher.calls, want)
+	}
+
+	metrics := result.RetryMetrics.GetNodeMetrics("a")
+	if metrics == nil || metrics.TotalAttempts != 2 || metrics.SuccessCount != 1 {
+		t.Errorf("Expected 2 attempts with 1 success for a, got %+v", metrics)
+	}
+}
+
+func TestRequeueRespectsRetryBudget(t *testing.T) {
+	researcher := &mockResearcherClient{
+		maxFailures: 100,
+		failureType: status.Error(codes.ResourceExhausted, "quota exceeded"),
+	}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 1)
+	executor.retryPolicy = &retry.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond}
+	executor.SetRequeuePolicy(RequeuePolicy{
+		Codes:    []codes.Code{codes.ResourceExhausted},
+		Cooldown: 10 * time.Millisecond,
+	})
+
+	graph := &dag.Graph{
+		ID:     "requeue-budget-test",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "a", Type: "researcher", Config: map[string]string{"query": "a"}, Status: dag.StatusCreated},
+		},
+	}
+
+	result, err := executor.Execute(context.Background(), graph, "requeue-budget-run")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected failure once the retry budget is spent")
+	}
+	if got := researcher.calls(); got != 3 {
+		t.Errorf("Expected 3 attempts (1 + 2 requeues), got %d", got)
+	}
+}
+
+func TestParseRequeueCodes(t *testing.T) {
+	got, err := ParseRequeueCodes([]string{"RESOURCE_EXHAUSTED", "unavailable"})
+	if err != nil {
+		t.Fatalf("ParseRequeueCodes() error = %v", err)
+	}
+	if want := []codes.Code{codes.ResourceExhausted, codes.Unavailable}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRequeueCodes() = %v, want %v", got, want)
+	}
+	if _, err := ParseRequeueCodes([]string{"SLOW_DOWN"}); err == nil {
+		t.Error("Expected error for unknown code")
+	}
+}
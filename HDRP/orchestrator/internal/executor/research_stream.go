@@ -0,0 +1,283 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// ConfigStream is the researcher node config key that opts the node into
+// the streaming research RPC ("true"). Claims are then sent on to the
+// node's critics for verification as they arrive.
+const ConfigStream = "stream"
+
+// EventResearchClaims reports a batch of claims streamed by a researcher.
+const EventResearchClaims EventType = "research_claims"
+
+// errResearchStreamingUnsupported signals that the researcher cannot stream
+// and the unary RPC should be used instead.
+var errResearchStreamingUnsupported = errors.New("researcher does not support streaming")
+
+// streamingRequested reports whether a researcher node asked to stream.
+func streamingRequested(node *dag.Node) bool {
+	enabled, _ := strconv.ParseBool(node.Config[ConfigStream])
+	return enabled
+}
+
+// streamedBatch is a batch of streamed claims sent to a critic ahead of the
+// critic node itself. done is closed once resp or err is set.
+type streamedBatch struct {
+	claims []*pb.AtomicClaim
+	done   chan struct{}
+	resp   *pb.VerifyResponse
+	err    error
+}
+
+// streamedVerifications holds batches verified ahead of their critic nodes,
+// keyed by run, researcher and critic.
+type streamedVerifications struct {
+	mu      sync.Mutex
+	batches map[string][]*streamedBatch
+}
+
+func newStreamedVerifications() *streamedVerifications {
+	return &streamedVerifications{batches: make(map[string][]*streamedBatch)}
+}
+
+func streamedKey(runID, researcherID, criticID string) string {
+	return runID + "\x00" + researcherID + "\x00" + criticID
+}
+
+// reset drops batches from an earlier attempt of the researcher.
+func (s *streamedVerifications) reset(runID, researcherID, criticID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.batches, streamedKey(runID, researcherID, criticID))
+}
+
+func (s *streamedVerifications) add(runID, researcherID, criticID string, batch *streamedBatch) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := streamedKey(runID, researcherID, criticID)
+	s.batches[key] = append(s.batches[key], batch)
+}
+
+// take removes and returns the batches streamed from researcherID to criticID.
+func (s *streamedVerifications) take(runID, researcherID, criticID string) []*streamedBatch {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := streamedKey(runID, researcherID, criticID)
+	batches := s.batches[key]
+	delete(s.batches, key)
+	return batches
+}
+
+// discardRun drops whatever a run's critics didn't use.
+func (s *streamedVerifications) discardRun(runID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prefix := runID + "\x00"
+	for key := range s.batches {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.batches, key)
+		}
+	}
+}
+
+// streamResearch collects a researcher's claims from the streaming RPC,
+// starting verification of each batch by the node's critic children as it
+// arrives. Returns errResearchStreamingUnsupported if the service rejects
+// the streaming RPC before sending anything.
+func (e *DAGExecutor) streamResearch(
+	ctx context.Context,
+	streamer clients.StreamingResearcher,
+	node *dag.Node,
+	graph *dag.Graph,
+	req *pb.ResearchRequest,
+) ([]*pb.AtomicClaim, error) {
+	critics := criticChildren(graph, node.ID)
+	for _, critic := range critics {
+		e.streamed.reset(req.RunId, node.ID, critic.ID)
+	}
+
+	var claims []*pb.AtomicClaim
+	open := func(opts ...grpc.CallOption) (serverStream[*pb.ResearchResponse], error) {
+		return streamer.ResearchStream(ctx, req, opts...)
+	}
+	err := receiveStream(e, "researcher", "ResearchStream", errResearchStreamingUnsupported, open, func(index int, resp *pb.ResearchResponse) error {
+		if len(resp.Claims) == 0 {
+			return nil
+		}
+		claims = append(claims, resp.Claims...)
+		for _, critic := range critics {
+			e.verifyAhead(ctx, critic, node.ID, resp.Claims, req.RunId)
+		}
+		e.emitEvent(Event{
+			Type:    EventResearchClaims,
+			RunID:   req.RunId,
+			GraphID: graph.ID,
+			NodeID:  node.ID,
+			Data: map[string]string{
+				"index":  strconv.Itoa(index),
+				"claims": strconv.Itoa(len(resp.Claims)),
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// criticChildren returns the critic nodes fed directly by nodeID.
+func criticChildren(graph *dag.Graph, nodeID string) []*dag.Node {
+	var critics []*dag.Node
+	nodes := graph.NodeSnapshot()
+	for _, edge := range graph.Edges {
+		if edge.From != nodeID {
+			continue
+		}
+		for i := range nodes {
+			child := &nodes[i]
+			if child.ID == edge.To && serviceType(child.Type) == "critic" && child.Config["task"] != "" {
+				critics = append(critics, child)
+			}
+		}
+	}
+	return critics
+}
+
+// verifyAhead sends a batch of streamed claims to critic's Verify RPC in
+// the background. The researcher node may finish first, so the call is
+// bounded by the critic's own timeout rather than the researcher's context.
+func (e *DAGExecutor) verifyAhead(ctx context.Context, critic *dag.Node, researcherID string, claims []*pb.AtomicClaim, runID string) {
+	batch := &streamedBatch{claims: claims, done: make(chan struct{})}
+	e.streamed.add(runID, researcherID, critic.ID, batch)
+
+	verifyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.nodeTimeout(critic))
+	task := critic.Config["task"]
+	go func() {
+		defer cancel()
+		defer close(batch.done)
+		limiter := e.rateLimiters.GetLimiter(serviceType(critic.Type))
+		if batch.err = limiter.Acquire(verifyCtx); batch.err != nil {
+			return
+		}
+		defer limiter.Release()
+		batch.resp, batch.err = e.verifyBatch(verifyCtx, claims, task, runID)
+	}()
+}
+
+// streamedVerification returns the verification of parentClaims made while
+// they were streamed to the critic, or false if there is none or it can't
+// be used (the parent was re-run, or a batch failed).
+func (e *DAGExecutor) streamedVerification(ctx context.Context, runID, parentID, criticID string, parentClaims []*pb.AtomicClaim) (*claimVerification, bool) {
+	batches := e.streamed.take(runID, parentID, criticID)
+	if len(batches) == 0 {
+		return nil, false
+	}
+
+	var streamed []*pb.AtomicClaim
+	for _, batch := range batches {
+		streamed = append(streamed, batch.claims...)
+	}
+	if len(streamed) != len(parentClaims) {
+		return nil, false
+	}
+	for i := range streamed {
+		if streamed[i] != parentClaims[i] {
+			return nil, false
+		}
+	}
+
+	verification := &claimVerification{}
+	for _, batch := range batches {
+		select {
+		case <-batch.done:
+		case <-ctx.Done():
+			return nil, false
+		}
+		if batch.err != nil {
+			execLog.Warnf("Critic node %s: streamed batch from %s failed, verifying its claims again: %v", criticID, parentID, batch.err)
+			return nil, false
+		}
+		verification.results = append(verification.results, batch.resp.Results...)
+		verification.verified += int(batch.resp.VerifiedCount)
+		verification.checked += len(batch.claims)
+	}
+	return verification, true
+}
+
+// research returns a researcher node's claims, streaming them when the node
+// asks to and the researcher supports it, and using the unary RPC otherwise.
+func (e *DAGExecutor) research(ctx context.Context, node *dag.Node, graph *dag.Graph, req *pb.ResearchRequest) ([]*pb.AtomicClaim, error) {
+	researcher := e.serviceClients(ctx).Researcher
+	if streamingRequested(node) {
+		if streamer, ok := researcher.(clients.StreamingResearcher); ok {
+			claims, err := e.streamResearch(ctx, streamer, node, graph, req)
+			if !errors.Is(err, errResearchStreamingUnsupported) {
+				return claims, err
+			}
+		}
+		execLog.Infof("Researcher node %s: streaming unavailable, using unary RPC", node.ID)
+	}
+
+	var hints rateLimitHints
+	resp, err := researcher.Research(ctx, req, hints.callOptions()...)
+	e.applyRateLimitHints("researcher", &hints)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Claims, nil
+}
+
+// verifyParentClaims verifies a critic node's claims, reusing verifications
+// made while a parent streamed them and verifying the rest now. Results of
+// streamed claims come first.
+func (e *DAGExecutor) verifyParentClaims(
+	ctx context.Context,
+	nodeID string,
+	parentIDs []string,
+	parentClaims map[string][]*pb.AtomicClaim,
+	allClaims []*pb.AtomicClaim,
+	task, runID string,
+) (*claimVerification, error) {
+	merged := &claimVerification{}
+	var pending []*pb.AtomicClaim
+	for _, parentID := range parentIDs {
+		streamed, ok := e.streamedVerification(ctx, runID, parentID, nodeID, parentClaims[parentID])
+		if !ok {
+			pending = append(pending, parentClaims[parentID]...)
+			continue
+		}
+		merged.results = append(merged.results, streamed.results...)
+		merged.verified += streamed.verified
+		merged.checked += streamed.checked
+	}
+	if merged.checked == 0 {
+		return e.verifyClaims(ctx, nodeID, allClaims, task, runID)
+	}
+	execLog.Infof("Critic node %s: %d claims verified while streamed, %d to verify now", nodeID, merged.checked, len(pending))
+	if len(pending) == 0 {
+		return merged, nil
+	}
+
+	rest, err := e.verifyClaims(ctx, nodeID, pending, task, runID)
+	if err != nil {
+		return nil, err
+	}
+	merged.results = append(merged.results, rest.results...)
+	merged.verified += rest.verified
+	merged.checked += rest.checked
+	merged.failedBatches += rest.failedBatches
+	return merged, nil
+}
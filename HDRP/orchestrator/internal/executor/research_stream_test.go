@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamingResearcherClient streams fixed claim batches. Each batch after the
+// first is only sent once the critic has seen the one before it, so a run
+// only completes if claims reach the critic while research is still going.
+// With unimplemented set it behaves like a service without the streaming RPC.
+type streamingResearcherClient struct {
+	batches       [][]string
+	unimplemented bool
+	critic        *recordingCriticClient
+}
+
+func (m *streamingResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	resp := &pb.ResearchResponse{}
+	for _, batch := range m.batches {
+		for _, statement := range batch {
+			resp.Claims = append(resp.Claims, &pb.AtomicClaim{Statement: statement, SourceNodeId: req.SourceNodeId})
+		}
+	}
+	return resp, nil
+}
+
+func (m *streamingResearcherClient) ResearchStream(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (clients.ResearchStream, error) {
+	if m.unimplemented {
+		return nil, status.Error(codes.Unimplemented, "unknown method ResearchStream")
+	}
+	return &claimStream{client: m, req: req}, nil
+}
+
+type claimStream struct {
+	client *streamingResearcherClient
+	req    *pb.ResearchRequest
+	next   int
+}
+
+func (s *claimStream) Recv() (*pb.ResearchResponse, error) {
+	if s.next >= len(s.client.batches) {
+		return nil, io.EOF
+	}
+	if s.next > 0 && !s.client.critic.waitForCalls(s.next, time.Second) {
+		return nil, errors.New("critic never received the previous batch")
+	}
+	resp := &pb.ResearchResponse{}
+	for _, statement := range s.client.batches[s.next] {
+		resp.Claims = append(resp.Claims, &pb.AtomicClaim{Statement: statement, SourceNodeId: s.req.SourceNodeId})
+	}
+	s.next++
+	return resp, nil
+}
+
+// recordingCriticClient accepts every claim and counts Verify calls.
+type recordingCriticClient struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *recordingCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return (&echoCriticClient{}).Verify(ctx, req, opts...)
+}
+
+func (c *recordingCriticClient) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+func (c *recordingCriticClient) waitForCalls(n int, timeout time.Duration) bool {
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if c.callCount() >= n {
+			return true
+		}
+	}
+	return false
+}
+
+func TestResearcherStreaming(t *testing.T) {
+	batches := [][]string{{"claim 1", "claim 2"}, {"claim 3"}, {"claim 4"}}
+
+	tests := []struct {
+		name          string
+		stream        string
+		unimplemented bool
+		wantEvents    int
+		wantVerifies  int
+	}{
+		// One Verify per streamed batch; the critic node reuses them
+		{name: "Streams batches to the critic", stream: "true", wantEvents: 3, wantVerifies: 3},
+		{name: "Unary by default", wantVerifies: 1},
+		{name: "Falls back to unary", stream: "true", unimplemented: true, wantVerifies: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			critic := &recordingCriticClient{}
+			researcher := &streamingResearcherClient{batches: batches, unimplemented: tt.unimplemented, critic: critic}
+			executor := NewDAGExecutor(&clients.ServiceClients{
+				Researcher:  researcher,
+				Critic:      critic,
+				Synthesizer: &mockSynthesizerClient{},
+			}, 2)
+
+			var mu sync.Mutex
+			events := 0
+			executor.SetEventHandler(func(evt Event) {
+				if evt.Type == EventResearchClaims {
+					mu.Lock()
+					events++
+					mu.Unlock()
+				}
+			})
+
+			graph := researchCriticGraph("test-research-stream", false)
+			if tt.stream != "" {
+				graph.Nodes[0].Config[ConfigStream] = tt.stream
+			}
+			result, err := executor.Execute(context.Background(), graph, "test-run-research-stream")
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+			}
+
+			if len(result.VerificationResults) != 4 {
+				t.Errorf("Expected 4 verified claims, got %d", len(result.VerificationResults))
+			}
+			if got := critic.callCount(); got != tt.wantVerifies {
+				t.Errorf("Expected %d Verify calls, got %d", tt.wantVerifies, got)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if events != tt.wantEvents {
+				t.Errorf("Expected %d claim batch events, got %d", tt.wantEvents, events)
+			}
+		})
+	}
+}
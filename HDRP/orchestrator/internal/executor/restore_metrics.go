@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"errors"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+	"hdrp/internal/storage"
+)
+
+// SetRestoreRetryMetrics makes ResumeGraph rebuild retry metrics from the
+// graph's logged node history, so a resumed run reports attempts made before
+// the crash and counts them against each unfinished node's retry budget.
+func (e *DAGExecutor) SetRestoreRetryMetrics(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.restoreRetryMetrics = enabled
+}
+
+// restoredRetryMetrics rebuilds retry metrics for a graph from its node
+// history. Returns nil if disabled or the history is unavailable.
+func (e *DAGExecutor) restoredRetryMetrics(graphID string) *retry.RetryMetrics {
+	e.mu.RLock()
+	enabled := e.restoreRetryMetrics
+	e.mu.RUnlock()
+	if !enabled {
+		return nil
+	}
+
+	store, ok := e.storage.(NodeHistoryStore)
+	if !ok {
+		recoveryLog.Warnf("Retry metrics not restored for graph %s: %v", graphID, ErrTimelineUnsupported)
+		return nil
+	}
+	history, err := store.LoadNodeHistory(graphID)
+	if err != nil {
+		recoveryLog.Warnf("retry metrics not restored for graph %s: %v", graphID, err)
+		return nil
+	}
+
+	metrics := retryMetricsFromHistory(history)
+	recoveryLog.Infof("Restored retry metrics for graph %s from %d transitions", graphID, len(history))
+	return metrics
+}
+
+// retryMetricsFromHistory replays node transitions into retry metrics. Each
+// move into RUNNING is an attempt; RETRYING and FAILED record the failure
+// of the preceding attempt, classified from the logged error. An attempt
+// interrupted by a crash counts as an attempt with no outcome.
+func retryMetricsFromHistory(history []storage.NodeTransition) *retry.RetryMetrics {
+	metrics := retry.NewRetryMetrics()
+	for _, tr := range history {
+		switch dag.Status(tr.NewStatus) {
+		case dag.StatusRunning:
+			metrics.RecordAttempt(tr.NodeID)
+		case dag.StatusSucceeded:
+			metrics.RecordSuccess(tr.NodeID)
+		case dag.StatusRetrying, dag.StatusFailed:
+			// Quarantined or skipped nodes are marked without running
+			if dag.Status(tr.OldStatus) != dag.StatusRunning {
+				continue
+			}
+			metrics.RecordFailure(tr.NodeID, retry.ClassifyError(errors.New(tr.LastError)))
+		}
+	}
+	return metrics
+}
+
+// priorAttempts returns how many attempts of a node were made before this
+// run started, if the run was seeded with restored metrics and the node
+// never succeeded. Such attempts use up the node's retry budget.
+func priorAttempts(runMetrics *retry.RetryMetrics, nodeID string) int {
+	m := runMetrics.GetNodeMetrics(nodeID)
+	if m == nil || m.SuccessCount > 0 {
+		return 0
+	}
+	return m.TotalAttempts
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestResumeGraph_RestoresRetryMetrics(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: 10 * time.Millisecond})
+	executor.SetRestoreRetryMetrics(true)
+
+	// The first run crashes during its second attempt
+	ctx, crash := context.WithCancel(context.Background())
+	defer crash()
+	first := &mockResearcherClient{
+		failureType: status.Error(codes.Unavailable, "backend outage"),
+		shouldFail: func(call int) bool {
+			if call == 2 {
+				crash()
+			}
+			return true
+		},
+	}
+	executor.clients.Researcher = first
+
+	graph := researchCriticGraph("graph-restore-metrics", false)
+	graph.Metadata = map[string]string{"run_id": "run-restore-metrics"}
+	if _, err := executor.Execute(ctx, graph, "run-restore-metrics"); err == nil {
+		t.Fatal("Expected the first run to be cut short")
+	}
+	if got := first.calls(); got != 2 {
+		t.Fatalf("Expected 2 attempts before the crash, got %d", got)
+	}
+
+	if err := executor.storage.UpdateGraphStatus(graph.ID, string(dag.StatusRunning)); err != nil {
+		t.Fatalf("Failed to mark graph running: %v", err)
+	}
+	recovered, err := executor.RecoverGraph(graph.ID)
+	if err != nil {
+		t.Fatalf("Recovery failed: %v", err)
+	}
+
+	// The outage persists, so the resumed run spends what is left of the budget
+	second := &mockResearcherClient{
+		failureType: status.Error(codes.Unavailable, "backend outage"),
+		shouldFail:  func(int) bool { return true },
+	}
+	executor.clients.Researcher = second
+	result, err := executor.ResumeGraph(context.Background(), recovered)
+	if err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected resumed run to fail")
+	}
+
+	if got := second.calls(); got != 2 {
+		t.Errorf("Expected 2 attempts after resume, got %d", got)
+	}
+	m := result.RetryMetrics.GetNodeMetrics("researcher1")
+	if m == nil {
+		t.Fatal("Expected metrics for researcher1")
+	}
+	if m.TotalAttempts != 4 {
+		t.Errorf("Expected 4 combined attempts, got %d", m.TotalAttempts)
+	}
+	// The attempt cut short by the crash has no logged outcome
+	if m.FailureCount != 3 {
+		t.Errorf("Expected 3 combined failures, got %d", m.FailureCount)
+	}
+}
@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/metrics"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/protobuf/proto"
+)
+
+// ResultCache stores node result data by a key derived from the node's type
+// and config, so repeated identical queries can skip the service call.
+type ResultCache interface {
+	Get(key string) (interface{}, bool)
+	Put(key string, value interface{})
+}
+
+// LRUResultCache is an in-memory ResultCache holding at most capacity
+// entries, evicting the least recently used.
+type LRUResultCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List // Front = most recently used
+}
+
+type cacheEntry struct {
+	key   string
+	value interface{}
+}
+
+// NewLRUResultCache creates an LRU cache. A capacity <= 0 means 1000.
+func NewLRUResultCache(capacity int) *LRUResultCache {
+	if capacity <= 0 {
+		capacity = 1000
+	}
+	return &LRUResultCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns a cached value and marks it recently used.
+func (c *LRUResultCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).value, true
+}
+
+// Put stores a value, evicting the least recently used entry when full.
+func (c *LRUResultCache) Put(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).value = value
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, value: value})
+	if c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *LRUResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// SetResultCache enables caching of researcher results across nodes, retries
+// and runs with the same type and config. nil (the default) disables it.
+func (e *DAGExecutor) SetResultCache(cache ResultCache) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resultCache = cache
+}
+
+func (e *DAGExecutor) getResultCache() ResultCache {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.resultCache
+}
+
+// nodeCacheKey hashes a node's type and config, with config keys sorted so
+// equal configs always hash the same.
+func nodeCacheKey(node *dag.Node) string {
+	keys := make([]string, 0, len(node.Config))
+	for k := range node.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(node.Type))
+	for _, k := range keys {
+		// NUL separators keep "a"+"bc" distinct from "ab"+"c"
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(node.Config[k]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type noResultCacheKey struct{}
+
+// withoutResultCache marks ctx so researcher calls under it bypass the
+// result cache, e.g. when replaying a node to see what the service returns.
+func withoutResultCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noResultCacheKey{}, true)
+}
+
+// executeResearcherCached serves a researcher node from the result cache when
+// one is set, calling the service and caching its claims on a miss. Cached
+// claims are copied and attributed to the node they now serve.
+func (e *DAGExecutor) executeResearcherCached(ctx context.Context, node *dag.Node, graph *dag.Graph, runID string) *NodeResult {
+	cache := e.getResultCache()
+	if bypass, _ := ctx.Value(noResultCacheKey{}).(bool); cache == nil || bypass {
+		return e.executeResearcher(ctx, node, graph, runID)
+	}
+
+	key := nodeCacheKey(node)
+	if data, ok := cache.Get(key); ok {
+		claims := cachedClaims(data, node.ID)
+		execLog.Infof("Researcher node %s served %d claims from result cache", node.ID, len(claims))
+		metrics.RecordClaimExtracted(runID, node.ID, len(claims))
+		return &NodeResult{NodeID: node.ID, Success: true, Data: claims}
+	}
+
+	result := e.executeResearcher(ctx, node, graph, runID)
+	if result.Success {
+		cache.Put(key, result.Data)
+	}
+	return result
+}
+
+// cachedClaims copies cached claims for nodeID, so nodes sharing a cache
+// entry never share or mislabel each other's claims.
+func cachedClaims(data interface{}, nodeID string) []*pb.AtomicClaim {
+	cached, _ := data.([]*pb.AtomicClaim)
+	claims := make([]*pb.AtomicClaim, len(cached))
+	for i, c := range cached {
+		claims[i] = proto.Clone(c).(*pb.AtomicClaim)
+		claims[i].SourceNodeId = nodeID
+	}
+	return claims
+}
@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+func TestLRUResultCache(t *testing.T) {
+	cache := NewLRUResultCache(2)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Get("a") // b is now least recently used
+	cache.Put("c", 3)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected b to be evicted")
+	}
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %v, %v; want 1, true", v, ok)
+	}
+	cache.Put("c", 4)
+	if v, _ := cache.Get("c"); v != 4 {
+		t.Errorf("Get(c) = %v, want the updated 4", v)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cache.Len())
+	}
+}
+
+func TestNodeCacheKey(t *testing.T) {
+	a := &dag.Node{ID: "n1", Type: "researcher", Config: map[string]string{"query": "q", "depth": "2"}}
+	b := &dag.Node{ID: "n2", Type: "researcher", Config: map[string]string{"depth": "2", "query": "q"}}
+	if nodeCacheKey(a) != nodeCacheKey(b) {
+		t.Error("Expected nodes with equal type and config to share a key")
+	}
+
+	c := &dag.Node{Type: "researcher", Config: map[string]string{"query": "q", "depth": "3"}}
+	d := &dag.Node{Type: "critic", Config: map[string]string{"query": "q", "depth": "2"}}
+	e := &dag.Node{Type: "researcher", Config: map[string]string{"query": "q\x00depth"}}
+	for _, other := range []*dag.Node{c, d, e} {
+		if nodeCacheKey(a) == nodeCacheKey(other) {
+			t.Errorf("Expected a distinct key for %+v", other)
+		}
+	}
+}
+
+func TestExecute_ResultCache(t *testing.T) {
+	researcher := &mockResearcherClient{}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher: researcher,
+		Critic:     &echoCriticClient{},
+	}, 2)
+	defer executor.Close()
+
+	// Without a cache every run calls the researcher
+	if _, err := executor.Execute(context.Background(), researchCriticGraph("cache-off", false), "run-cache-off"); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if got := researcher.calls(); got != 1 {
+		t.Fatalf("Expected 1 researcher call, got %d", got)
+	}
+
+	executor.SetResultCache(NewLRUResultCache(10))
+	for _, id := range []string{"cache-1", "cache-2"} {
+		result, err := executor.Execute(context.Background(), researchCriticGraph(id, false), "run-"+id)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if !result.Success || len(result.VerificationResults) != 1 {
+			t.Fatalf("Expected success verifying the cached claim, got %+v", result)
+		}
+	}
+	if got := researcher.calls(); got != 2 {
+		t.Errorf("Expected the second cached run to skip the researcher, got %d calls", got)
+	}
+}
+
+func TestExecuteResearcherCached_AttributesClaimsToNode(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{Researcher: &mockResearcherClient{}}, 1)
+	defer executor.Close()
+	executor.SetResultCache(NewLRUResultCache(10))
+
+	config := map[string]string{"query": "q"}
+	first := &dag.Node{ID: "researcher1", Type: "researcher", Config: config}
+	second := &dag.Node{ID: "researcher2", Type: "researcher", Config: config}
+	miss := executor.executeResearcherCached(context.Background(), first, nil, "run-cache")
+	hit := executor.executeResearcherCached(context.Background(), second, nil, "run-cache")
+	if !miss.Success || !hit.Success {
+		t.Fatalf("Expected both nodes to succeed, got %v and %v", miss.Error, hit.Error)
+	}
+
+	missClaims := miss.Data.([]*pb.AtomicClaim)
+	hitClaims := hit.Data.([]*pb.AtomicClaim)
+	if len(hitClaims) != 1 || hitClaims[0].SourceNodeId != "researcher2" {
+		t.Errorf("Expected the cached claim attributed to researcher2, got %v", hitClaims)
+	}
+	if missClaims[0].SourceNodeId != "researcher1" {
+		t.Errorf("Serving researcher2 relabelled researcher1's claim as %s", missClaims[0].SourceNodeId)
+	}
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"hdrp/internal/metrics"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// EmptyResultPolicy controls how a service call that succeeds with empty
+// output is treated: a researcher with no claims or a synthesizer with an
+// empty report.
+type EmptyResultPolicy string
+
+const (
+	// EmptyResultAllow accepts empty output as a success.
+	EmptyResultAllow EmptyResultPolicy = "allow"
+	// EmptyResultRetry fails the node with a retryable error.
+	EmptyResultRetry EmptyResultPolicy = "retry"
+	// EmptyResultFail fails the node without retrying.
+	EmptyResultFail EmptyResultPolicy = "fail"
+)
+
+// ParseEmptyResultPolicy converts a config value to a policy. Empty selects allow.
+func ParseEmptyResultPolicy(s string) (EmptyResultPolicy, error) {
+	switch EmptyResultPolicy(s) {
+	case "", EmptyResultAllow:
+		return EmptyResultAllow, nil
+	case EmptyResultRetry:
+		return EmptyResultRetry, nil
+	case EmptyResultFail:
+		return EmptyResultFail, nil
+	default:
+		return "", fmt.Errorf("unsupported empty result policy: %s", s)
+	}
+}
+
+// SetEmptyResultPolicy sets how empty researcher and synthesizer output is handled.
+func (e *DAGExecutor) SetEmptyResultPolicy(policy EmptyResultPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.emptyResultPolicy = policy
+}
+
+// validateResult applies the empty result policy to a successful node
+// result, returning the failure to report instead or nil to keep it.
+func (e *DAGExecutor) validateResult(nodeType string, result *NodeResult) *NodeResult {
+	e.mu.RLock()
+	policy := e.emptyResultPolicy
+	e.mu.RUnlock()
+	if policy == "" || policy == EmptyResultAllow {
+		return nil
+	}
+
+	var reason string
+	switch data := result.Data.(type) {
+	case []*pb.AtomicClaim:
+		if serviceType(nodeType) == "researcher" && len(data) == 0 {
+			reason = "researcher returned no claims"
+		}
+	case *pb.SynthesizeResponse:
+		if serviceType(nodeType) == "synthesizer" && strings.TrimSpace(data.GetReport()) == "" {
+			reason = "synthesizer returned an empty report"
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+
+	metrics.RecordError(nodeType, "empty_result")
+	return &NodeResult{
+		NodeID:  result.NodeID,
+		Success: false,
+		Error:   &retry.InvalidResultError{Reason: reason, Retryable: policy == EmptyResultRetry},
+	}
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// emptyResearcher succeeds without finding any claims.
+type emptyResearcher struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (r *emptyResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count++
+	return &pb.ResearchResponse{}, nil
+}
+
+func TestEmptyResultPolicy_Researcher(t *testing.T) {
+	tests := []struct {
+		policy      EmptyResultPolicy
+		wantSuccess bool
+		wantCalls   int
+	}{
+		{policy: EmptyResultAllow, wantSuccess: true, wantCalls: 1},
+		{policy: EmptyResultRetry, wantSuccess: false, wantCalls: 3},
+		{policy: EmptyResultFail, wantSuccess: false, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			researcher := &emptyResearcher{}
+			executor := NewDAGExecutor(&clients.ServiceClients{Researcher: researcher}, 2)
+			executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+			executor.SetEmptyResultPolicy(tt.policy)
+
+			graph := &dag.Graph{
+				ID:     "empty-result-" + string(tt.policy),
+				Status: dag.StatusCreated,
+				Nodes: []dag.Node{
+					{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+				},
+			}
+			result, err := executor.Execute(context.Background(), graph, "run-"+graph.ID)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (%s)", result.Success, tt.wantSuccess, result.ErrorMessage)
+			}
+			if researcher.count != tt.wantCalls {
+				t.Errorf("Expected %d researcher calls, got %d", tt.wantCalls, researcher.count)
+			}
+			if !tt.wantSuccess && !strings.Contains(result.FailedNodes["researcher1"], "no claims") {
+				t.Errorf("Expected the node to fail on empty claims, got %v", result.FailedNodes)
+			}
+		})
+	}
+}
+
+func TestParseEmptyResultPolicy(t *testing.T) {
+	if got, err := ParseEmptyResultPolicy(""); err != nil || got != EmptyResultAllow {
+		t.Errorf("ParseEmptyResultPolicy(\"\") = %v, %v", got, err)
+	}
+	if _, err := ParseEmptyResultPolicy("ignore"); err == nil {
+		t.Error("Expected error for unsupported policy")
+	}
+}
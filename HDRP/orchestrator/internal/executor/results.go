@@ -0,0 +1,226 @@
+package executor
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// resultSet holds completed node results for a single run. When a memory
+// limit is set and storage is available, least recently used results are
+// offloaded to storage and loaded back on demand.
+type resultSet struct {
+	mu        sync.Mutex
+	graphID   string
+	limit     int // Max results kept in memory; <= 0 means unbounded
+	store     storage.Storage
+	entries   map[string]*list.Element
+	lru       *list.List // Front = most recently used
+	offloaded map[string]bool
+	peak      int
+	persist   bool // Write successful results to storage as they arrive
+}
+
+// newResultSet creates a result set for a graph. Without storage, results
+// are always kept in memory regardless of limit.
+func newResultSet(graphID string, limit int, store storage.Storage) *resultSet {
+	if limit > 0 && store == nil {
+		execLog.Infof("Result memory limit %d ignored: no storage backend to offload to", limit)
+		limit = 0
+	}
+	return &resultSet{
+		graphID:   graphID,
+		limit:     limit,
+		store:     store,
+		entries:   make(map[string]*list.Element),
+		lru:       list.New(),
+		offloaded: make(map[string]bool),
+	}
+}
+
+// Put records a node result, evicting older results to storage if over the limit.
+func (rs *resultSet) Put(result *NodeResult) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if elem, ok := rs.entries[result.NodeID]; ok {
+		elem.Value = result
+		rs.lru.MoveToFront(elem)
+	} else {
+		rs.entries[result.NodeID] = rs.lru.PushFront(result)
+	}
+	delete(rs.offloaded, result.NodeID)
+
+	if rs.persist && result.Success {
+		if err := rs.offload(result); err != nil {
+			execLog.Warnf("failed to persist result for node %s: %v", result.NodeID, err)
+		}
+	}
+
+	if rs.lru.Len() > rs.peak {
+		rs.peak = rs.lru.Len()
+	}
+
+	for rs.limit > 0 && rs.lru.Len() > rs.limit {
+		oldest := rs.lru.Back()
+		evicted := oldest.Value.(*NodeResult)
+		if err := rs.offload(evicted); err != nil {
+			execLog.Warnf("failed to offload result for node %s, keeping in memory: %v", evicted.NodeID, err)
+			break
+		}
+		rs.lru.Remove(oldest)
+		delete(rs.entries, evicted.NodeID)
+		rs.offloaded[evicted.NodeID] = true
+	}
+}
+
+// Get returns a node's result, loading it from storage if it was offloaded.
+func (rs *resultSet) Get(nodeID string) (*NodeResult, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if elem, ok := rs.entries[nodeID]; ok {
+		rs.lru.MoveToFront(elem)
+		return elem.Value.(*NodeResult), true
+	}
+
+	if !rs.offloaded[nodeID] {
+		return nil, false
+	}
+
+	result, err := rs.load(nodeID)
+	if err != nil {
+		execLog.Warnf("failed to load offloaded result for node %s: %v", nodeID, err)
+		return nil, false
+	}
+	return result, true
+}
+
+// Restore makes a result persisted by an earlier run of the graph available,
+// loading it from storage when first needed.
+func (rs *resultSet) Restore(nodeID string) {
+	if rs.store == nil {
+		return
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if _, ok := rs.entries[nodeID]; !ok {
+		rs.offloaded[nodeID] = true
+	}
+}
+
+// Parents returns the results of a node's direct parents that have completed.
+func (rs *resultSet) Parents(graph *dag.Graph, nodeID string) map[string]*NodeResult {
+	parents := make(map[string]*NodeResult)
+	for _, edge := range graph.Edges {
+		if edge.To != nodeID {
+			continue
+		}
+		if result, ok := rs.Get(edge.From); ok {
+			parents[edge.From] = result
+		}
+	}
+	return parents
+}
+
+// Resident returns the number of results currently held in memory.
+func (rs *resultSet) Resident() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.lru.Len()
+}
+
+// Peak returns the largest number of results held in memory at once.
+func (rs *resultSet) Peak() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.peak
+}
+
+func (rs *resultSet) offload(result *NodeResult) error {
+	data, err := encodeNodeResult(result)
+	if err != nil {
+		return err
+	}
+	return rs.store.SaveNodeResult(rs.graphID, result.NodeID, data)
+}
+
+func (rs *resultSet) load(nodeID string) (*NodeResult, error) {
+	data, err := rs.store.LoadNodeResult(rs.graphID, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeNodeResult(data)
+}
+
+// storedNodeResult is the serialized form of a NodeResult.
+type storedNodeResult struct {
+	NodeID    string                 `json:"node_id"`
+	Success   bool                   `json:"success"`
+	Error     string                 `json:"error,omitempty"`
+	Claims    []*pb.AtomicClaim      `json:"claims,omitempty"`
+	Critiques []*pb.CritiqueResult   `json:"critiques,omitempty"`
+	Synthesis *pb.SynthesizeResponse `json:"synthesis,omitempty"`
+	Truncated bool                   `json:"report_truncated,omitempty"`
+}
+
+// encodeNodeResult serializes a NodeResult, preserving the concrete Data type.
+func encodeNodeResult(result *NodeResult) ([]byte, error) {
+	stored := storedNodeResult{
+		NodeID:    result.NodeID,
+		Success:   result.Success,
+		Truncated: result.ReportTruncated,
+	}
+	if result.Error != nil {
+		stored.Error = result.Error.Error()
+	}
+
+	switch data := result.Data.(type) {
+	case nil:
+	case []*pb.AtomicClaim:
+		stored.Claims = data
+	case []*pb.CritiqueResult:
+		stored.Critiques = data
+	case *pb.SynthesizeResponse:
+		stored.Synthesis = data
+	default:
+		return nil, fmt.Errorf("unsupported result data type %T", result.Data)
+	}
+
+	return json.Marshal(stored)
+}
+
+// decodeNodeResult restores a NodeResult serialized by encodeNodeResult.
+func decodeNodeResult(data []byte) (*NodeResult, error) {
+	var stored storedNodeResult
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode node result: %w", err)
+	}
+
+	result := &NodeResult{
+		NodeID:          stored.NodeID,
+		Success:         stored.Success,
+		ReportTruncated: stored.Truncated,
+	}
+	if stored.Error != "" {
+		result.Error = errors.New(stored.Error)
+	}
+
+	switch {
+	case stored.Claims != nil:
+		result.Data = stored.Claims
+	case stored.Critiques != nil:
+		result.Data = stored.Critiques
+	case stored.Synthesis != nil:
+		result.Data = stored.Synthesis
+	}
+
+	return result, nil
+}
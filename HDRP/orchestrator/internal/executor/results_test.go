@@ -0,0 +1,135 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+func TestResultSet_OffloadsBeyondLimit(t *testing.T) {
+	store := newTestStorage(t)
+	if err := store.SaveGraph(&storage.GraphState{ID: "graph-results", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	rs := newResultSet("graph-results", 3, store)
+
+	for i := 0; i < 10; i++ {
+		rs.Put(&NodeResult{
+			NodeID:  fmt.Sprintf("node-%d", i),
+			Success: true,
+			Data:    []*pb.AtomicClaim{{Statement: fmt.Sprintf("claim %d", i)}},
+		})
+	}
+
+	if got := rs.Resident(); got != 3 {
+		t.Errorf("Expected 3 resident results, got %d", got)
+	}
+	if got := rs.Peak(); got > 4 {
+		t.Errorf("Expected peak to stay near limit, got %d", got)
+	}
+
+	// node-0 was offloaded first and must round-trip through storage intact
+	result, ok := rs.Get("node-0")
+	if !ok {
+		t.Fatal("Expected offloaded result to be loadable")
+	}
+	claims, ok := result.Data.([]*pb.AtomicClaim)
+	if !ok || len(claims) != 1 || claims[0].Statement != "claim 0" {
+		t.Errorf("Unexpected offloaded result data: %#v", result.Data)
+	}
+
+	if _, ok := rs.Get("missing"); ok {
+		t.Error("Expected unknown node to be absent")
+	}
+}
+
+func TestResultSet_UnboundedWithoutStorage(t *testing.T) {
+	rs := newResultSet("graph-results", 2, nil)
+
+	for i := 0; i < 5; i++ {
+		rs.Put(&NodeResult{NodeID: fmt.Sprintf("node-%d", i), Success: true})
+	}
+
+	if got := rs.Resident(); got != 5 {
+		t.Errorf("Expected all 5 results in memory without storage, got %d", got)
+	}
+}
+
+// countingCriticClient records how many claims each critic call received.
+type countingCriticClient struct {
+	mu     sync.Mutex
+	counts []int
+}
+
+func (m *countingCriticClient) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	m.mu.Lock()
+	m.counts = append(m.counts, len(req.Claims))
+	m.mu.Unlock()
+	return (&echoCriticClient{}).Verify(ctx, req, opts...)
+}
+
+// TestLargeGraphWithResultLimit verifies that offloaded parent results still reach their children
+func TestLargeGraphWithResultLimit(t *testing.T) {
+	const critics, researchersPerCritic = 4, 10
+
+	graph := &dag.Graph{ID: "test-large-results", Status: dag.StatusCreated}
+	graph.Nodes = append(graph.Nodes, dag.Node{
+		ID: "synthesizer", Type: "synthesizer", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated,
+	})
+	for c := 0; c < critics; c++ {
+		criticID := fmt.Sprintf("critic-%d", c)
+		graph.Nodes = append(graph.Nodes, dag.Node{
+			ID: criticID, Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated,
+		})
+		graph.Edges = append(graph.Edges, dag.Edge{From: criticID, To: "synthesizer"})
+		for r := 0; r < researchersPerCritic; r++ {
+			researcherID := fmt.Sprintf("researcher-%d-%d", c, r)
+			graph.Nodes = append(graph.Nodes, dag.Node{
+				ID: researcherID, Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated,
+			})
+			graph.Edges = append(graph.Edges, dag.Edge{From: researcherID, To: criticID})
+		}
+	}
+
+	critic := &countingCriticClient{}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      critic,
+		Synthesizer: &mockSynthesizerClient{},
+	}, 4)
+	defer executor.Close()
+	if executor.storage == nil {
+		t.Skip("Storage unavailable")
+	}
+	executor.SetResultMemoryLimit(5)
+
+	result, err := executor.Execute(context.Background(), graph, "test-run-large-results")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+	}
+
+	// Put evicts after inserting, so the working set can briefly exceed the limit by one
+	if result.PeakResidentResults > 6 {
+		t.Errorf("Expected at most 6 resident results, peak was %d", result.PeakResidentResults)
+	}
+
+	if len(critic.counts) != critics {
+		t.Fatalf("Expected %d critic calls, got %d", critics, len(critic.counts))
+	}
+	for i, n := range critic.counts {
+		if n != researchersPerCritic {
+			t.Errorf("Critic call %d received %d claims, want %d", i, n, researchersPerCritic)
+		}
+	}
+}
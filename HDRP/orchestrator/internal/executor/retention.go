@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"hdrp/internal/storage"
+)
+
+// SetGraphRetention deletes finished graphs from storage once they have gone
+// unchanged for longer than ttl, checking every interval. Graphs still
+// running are never deleted. A non-positive ttl or interval stops the
+// cleanup.
+func (e *DAGExecutor) SetGraphRetention(ttl, interval time.Duration) {
+	e.mu.Lock()
+	stop := e.stopRetention
+	e.stopRetention = nil
+	store := e.storage
+	if ttl > 0 && interval > 0 && store != nil {
+		e.stopRetention = startGraphRetention(store, ttl, interval)
+	}
+	e.mu.Unlock()
+
+	// Stop the previous cleanup outside e.mu, like stopGraphRetention
+	if stop != nil {
+		stop()
+	}
+}
+
+// startGraphRetention runs the cleanup for SetGraphRetention against store
+// and returns a function that stops it and waits for it to return.
+func startGraphRetention(store storage.Storage, ttl, interval time.Duration) func() {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := store.CleanupGraphsOlderThan(ttl); err != nil {
+					execLog.Warnf("failed to clean up finished graphs: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// stopGraphRetention stops the periodic cleanup of finished graphs.
+func (e *DAGExecutor) stopGraphRetention() {
+	e.mu.Lock()
+	stop := e.stopRetention
+	e.stopRetention = nil
+	e.mu.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+}
@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+)
+
+// TestSetGraphRetention_ReplacesRunningCleanup changes the retention while
+// its cleanup is ticking, which must not wait on the executor's lock.
+func TestSetGraphRetention_ReplacesRunningCleanup(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", t.TempDir()+"/retention.db")
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	defer executor.Close()
+
+	executor.SetGraphRetention(time.Hour, time.Millisecond)
+
+	// Hold a read lock so SetGraphRetention queues for the write lock while
+	// the cleanup keeps ticking
+	executor.mu.RLock()
+	done := make(chan struct{})
+	go func() {
+		executor.SetGraphRetention(0, 0)
+		close(done)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	executor.mu.RUnlock()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetGraphRetention deadlocked stopping a running cleanup")
+	}
+}
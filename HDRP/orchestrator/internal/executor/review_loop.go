@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// Config keys passed to the target of a review loop when it runs again, so
+// it can refine its output rather than repeat it.
+const (
+	ConfigRejectedClaims = "rejected_claims" // Rejected statements and the critic's reasoning, one per line
+	ConfigLoopIteration  = "loop_iteration"  // 1 for the first refinement
+)
+
+// runReviewLoop drives a node's review loop (see dag.Loopback). While the
+// node's result rejects claims and iterations remain, the loop body runs
+// again with the rejections as feedback and the node reviews the refined
+// output. Every re-run counts against the run's budget. Returns the node's
+// latest result; if the body fails to re-run, the last review stands.
+func (e *DAGExecutor) runReviewLoop(
+	ctx context.Context,
+	node *dag.Node,
+	graph *dag.Graph,
+	nodeResults *resultSet,
+	result *NodeResult,
+	runID string,
+	runMetrics *retry.RetryMetrics,
+	budget *runBudget,
+) *NodeResult {
+	lb, ok, err := graph.Loopback(node.ID)
+	if err != nil || !ok {
+		return result
+	}
+	body := graph.LoopBody(lb)
+
+	for iteration := 1; iteration <= lb.MaxIterations; iteration++ {
+		rejected := rejectedClaims(result)
+		if len(rejected) == 0 {
+			return result
+		}
+		if ctx.Err() != nil {
+			return result
+		}
+
+		execLog.Infof("Node %s rejected %d claims, looping back to %s (iteration %d/%d)",
+			node.ID, len(rejected), lb.To, iteration, lb.MaxIterations)
+		feedback := map[string]string{
+			ConfigRejectedClaims: strings.Join(rejected, "\n"),
+			ConfigLoopIteration:  strconv.Itoa(iteration),
+		}
+		if !e.rerunLoopBody(ctx, graph, nodeResults, body, lb.To, feedback, runID, runMetrics, budget) {
+			execLog.Warnf("Review loop %s->%s stopped after a failed re-run", lb.From, lb.To)
+			return result
+		}
+
+		if err := budget.recordAttempt(runMetrics, node.ID); err != nil {
+			execLog.Warnf("Node %s not reviewed again: %v", node.ID, err)
+			return result
+		}
+		execCtx, cancel := context.WithTimeout(ctx, e.attemptTimeout(ctx, node))
+		review := e.executeNode(execCtx, node, graph, nodeResults.Parents(graph, node.ID), runID)
+		cancel()
+		if !review.Success {
+			e.circuitBreakers.RecordFailure(serviceType(node.Type))
+			e.serviceHealth.RecordFailure(serviceType(node.Type))
+			runMetrics.RecordFailure(node.ID, retry.ClassifyError(review.Error))
+			execLog.Warnf("Node %s failed to review iteration %d: %v", node.ID, iteration, review.Error)
+			return result
+		}
+		e.circuitBreakers.RecordSuccess(serviceType(node.Type))
+		e.serviceHealth.RecordSuccess(serviceType(node.Type))
+		result = review
+	}
+
+	if rejected := rejectedClaims(result); len(rejected) > 0 {
+		execLog.Infof("Review loop %s->%s hit its %d iteration cap with %d claims still rejected",
+			lb.From, lb.To, lb.MaxIterations, len(rejected))
+	}
+	return result
+}
+
+// rerunLoopBody re-executes a loop's body in dependency order, giving the
+// loop target the review feedback, and replaces the stored results. Returns
+// false if any node could not be re-run.
+func (e *DAGExecutor) rerunLoopBody(
+	ctx context.Context,
+	graph *dag.Graph,
+	nodeResults *resultSet,
+	body []string,
+	target string,
+	feedback map[string]string,
+	runID string,
+	runMetrics *retry.RetryMetrics,
+	budget *runBudget,
+) bool {
+	for _, id := range body {
+		var node *dag.Node
+		nodes := graph.NodeSnapshot()
+		for i := range nodes {
+			if nodes[i].ID == id {
+				node = &nodes[i]
+				break
+			}
+		}
+		if node == nil {
+			execLog.Warnf("Loop node %s not found in graph", id)
+			return false
+		}
+
+		// Run a copy so the feedback doesn't leak into the graph's config
+		run := *node
+		if id == target {
+			run.Config = make(map[string]string, len(node.Config)+len(feedback))
+			for k, v := range node.Config {
+				run.Config[k] = v
+			}
+			for k, v := range feedback {
+				run.Config[k] = v
+			}
+		}
+
+		if err := budget.recordAttempt(runMetrics, id); err != nil {
+			execLog.Warnf("Loop node %s not re-run: %v", id, err)
+			return false
+		}
+		limiter := e.rateLimiters.GetLimiter(serviceType(node.Type))
+		if err := limiter.Acquire(ctx); err != nil {
+			execLog.Warnf("Rate limit acquire failed for loop node %s: %v", id, err)
+			return false
+		}
+
+		execCtx, cancel := context.WithTimeout(ctx, e.attemptTimeout(ctx, &run))
+		result := e.executeNode(execCtx, &run, graph, nodeResults.Parents(graph, id), runID)
+		cancel()
+		limiter.Release()
+
+		if !result.Success {
+			e.circuitBreakers.RecordFailure(serviceType(node.Type))
+			e.serviceHealth.RecordFailure(serviceType(node.Type))
+			runMetrics.RecordFailure(id, retry.ClassifyError(result.Error))
+			execLog.Warnf("Loop node %s failed on re-run: %v", id, result.Error)
+			return false
+		}
+
+		e.circuitBreakers.RecordSuccess(serviceType(node.Type))
+		e.serviceHealth.RecordSuccess(serviceType(node.Type))
+		runMetrics.RecordSuccess(id)
+		nodeResults.Put(result)
+	}
+	return true
+}
+
+// rejectedClaims lists the claims a review result rejected as
+// "statement: reasoning" lines.
+func rejectedClaims(result *NodeResult) []string {
+	results, _ := result.Data.([]*pb.CritiqueResult)
+	var rejected []string
+	for _, r := range results {
+		if r.IsValid {
+			continue
+		}
+		line := r.Claim.GetStatement()
+		if r.Reasoning != "" {
+			line = fmt.Sprintf("%s: %s", line, r.Reasoning)
+		}
+		rejected = append(rejected, line)
+	}
+	return rejected
+}
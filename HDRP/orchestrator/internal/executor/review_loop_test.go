@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// iterationResearcher records the config of every research call.
+type iterationResearcher struct {
+	mu      sync.Mutex
+	configs []map[string]string
+}
+
+func (r *iterationResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configs = append(r.configs, req.Config)
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+// rejectingCritic rejects every claim for its first rejections calls.
+type rejectingCritic struct {
+	mu         sync.Mutex
+	rejections int
+	calls      int
+}
+
+func (c *rejectingCritic) Verify(ctx context.Context, req *pb.VerifyRequest, opts ...grpc.CallOption) (*pb.VerifyResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	valid := c.calls > c.rejections
+	results := make([]*pb.CritiqueResult, 0, len(req.Claims))
+	verified := 0
+	for _, claim := range req.Claims {
+		results = append(results, &pb.CritiqueResult{Claim: claim, IsValid: valid, Reasoning: "unsupported"})
+		if valid {
+			verified++
+		}
+	}
+	return &pb.VerifyResponse{Results: results, VerifiedCount: int32(verified)}, nil
+}
+
+func reviewLoopGraph(id, maxIterations string) *dag.Graph {
+	graph := researchCriticGraph(id, true)
+	graph.Nodes[1].Config[dag.ConfigLoopbackTo] = "researcher1"
+	graph.Nodes[1].Config[dag.ConfigMaxIterations] = maxIterations
+	return graph
+}
+
+func TestReviewLoop_RejectOnceThenAccept(t *testing.T) {
+	researcher := &iterationResearcher{}
+	critic := &rejectingCritic{rejections: 1}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      critic,
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+
+	result, err := executor.Execute(context.Background(), reviewLoopGraph("review-loop", "3"), "run-review-loop")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	if len(researcher.configs) != 2 {
+		t.Fatalf("Expected exactly one extra researcher iteration, got %d calls", len(researcher.configs))
+	}
+	if critic.calls != 2 {
+		t.Errorf("Expected the critic to review twice, got %d", critic.calls)
+	}
+	refined := researcher.configs[1]
+	if refined[ConfigLoopIteration] != "1" || refined[ConfigRejectedClaims] != "claim: unsupported" {
+		t.Errorf("Refined research config = %v, expected iteration 1 with the rejected claim", refined)
+	}
+	if _, ok := researcher.configs[0][ConfigRejectedClaims]; ok {
+		t.Error("First research attempt should not carry rejection feedback")
+	}
+}
+
+func TestReviewLoop_IterationCap(t *testing.T) {
+	researcher := &iterationResearcher{}
+	critic := &rejectingCritic{rejections: 10}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      critic,
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+
+	result, err := executor.Execute(context.Background(), reviewLoopGraph("review-loop-cap", "2"), "run-review-loop-cap")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	// The first run plus two refinements, then the last review stands
+	if len(researcher.configs) != 3 || critic.calls != 3 {
+		t.Errorf("Expected 3 researcher and critic calls, got %d and %d", len(researcher.configs), critic.calls)
+	}
+}
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+
+	"hdrp/internal/retry"
+)
+
+// DefaultMaxRunAttempts caps per-run retry overrides unless changed with SetRunOverrideLimits.
+const DefaultMaxRunAttempts = 5
+
+// RunOptions overrides executor-level retry behaviour for a single run.
+type RunOptions struct {
+	// MaxAttempts replaces the retry policy's MaxAttempts (retries after the
+	// first attempt). It is clamped to the executor's configured maximum.
+	MaxAttempts *int
+	// IgnoreCircuitBreakers lets nodes run even when their service's breaker
+	// is open. Only honored if the executor allows breaker bypass.
+	IgnoreCircuitBreakers bool
+	// RetryMetrics seeds the run's metrics, e.g. with history restored on
+	// resume. Prior attempts of nodes that never succeeded count against
+	// their retry budget.
+	RetryMetrics *retry.RetryMetrics
+	// Deterministic runs nodes one at a time, strictly in readiness then
+	// relevance then ID order, and retries in place without requeues or
+	// jitter, so a graph with the same service responses always produces the
+	// same execution trace. Also enabled for every run by SetDeterministicMode.
+	Deterministic bool
+	// Priority orders this run's nodes against other runs' when they compete
+	// for shared worker slots (see SetGlobalWorkerLimit); higher runs first.
+	// Nil falls back to the graph's priority metadata, then 0.
+	Priority *int
+	// Budget replaces the executor's execution budget for this run.
+	Budget *ExecutionBudget
+}
+
+// runPolicy is the effective retry behaviour for one run.
+type runPolicy struct {
+	retry         *retry.RetryPolicy            // Default for node types without their own policy
+	nodeRetry     map[string]*retry.RetryPolicy // Per node type
+	honorBreakers bool
+	backoffSlots  chan struct{} // Limits nodes backing off at once; nil means unlimited
+	requeue       RequeuePolicy
+	workers       int        // Nodes run at once when the run starts; see runWorkers
+	deterministic bool       // Reproducible ordering and retry timing
+	priority      int        // Claim on shared worker slots relative to other runs
+	budget        *runBudget // Set per run by execute; nil is unlimited
+}
+
+// retryFor returns the retry policy for nodes of nodeType.
+func (p runPolicy) retryFor(nodeType string) *retry.RetryPolicy {
+	if policy, ok := p.nodeRetry[nodeType]; ok {
+		return policy
+	}
+	if policy, ok := p.nodeRetry[serviceType(nodeType)]; ok {
+		return policy
+	}
+	return p.retry
+}
+
+// acquireBackoffSlot waits until the node may start its retry backoff.
+func (p runPolicy) acquireBackoffSlot(ctx context.Context) error {
+	if p.backoffSlots == nil {
+		return nil
+	}
+	select {
+	case p.backoffSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// releaseBackoffSlot frees a slot taken by acquireBackoffSlot.
+func (p runPolicy) releaseBackoffSlot() {
+	if p.backoffSlots != nil {
+		<-p.backoffSlots
+	}
+}
+
+// SetMaxConcurrentRetries caps how many nodes of a run may wait out a retry
+// backoff at the same time. Further failing nodes queue for a slot before
+// starting their backoff, so a correlated outage doesn't produce a burst of
+// simultaneous retries. max <= 0 means unlimited.
+func (e *DAGExecutor) SetMaxConcurrentRetries(max int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxConcurrentRetries = max
+}
+
+// SetDeterministicMode makes every run deterministic (see RunOptions.Deterministic).
+func (e *DAGExecutor) SetDeterministicMode(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.deterministic = enabled
+}
+
+// SetRunOverrideLimits bounds what RunOptions may request: retry attempts are
+// clamped to maxAttempts, and breaker bypass is ignored unless allowed.
+func (e *DAGExecutor) SetRunOverrideLimits(maxAttempts int, allowBreakerBypass bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxRunAttempts = maxAttempts
+	e.allowBreakerBypass = allowBreakerBypass
+}
+
+// runWorkers returns how many nodes a run may have executing at once. It is
+// read on every scheduling iteration so SetMaxWorkers applies to runs in
+// progress.
+func (e *DAGExecutor) runWorkers(policy runPolicy) int {
+	if policy.deterministic {
+		return 1
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.maxWorkers
+}
+
+// resolveRunPolicy applies run overrides to the executor defaults within the configured limits.
+func (e *DAGExecutor) resolveRunPolicy(opts RunOptions) runPolicy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	policy := runPolicy{retry: e.retryPolicy, honorBreakers: true, requeue: e.requeuePolicy, workers: e.maxWorkers}
+	if e.maxConcurrentRetries > 0 {
+		policy.backoffSlots = make(chan struct{}, e.maxConcurrentRetries)
+	}
+
+	if opts.Deterministic || e.deterministic {
+		// One node at a time, and no timer-driven requeues to reorder the trace
+		policy.deterministic = true
+		policy.workers = 1
+		policy.requeue = RequeuePolicy{}
+	}
+
+	attempts := -1
+	if opts.MaxAttempts != nil {
+		attempts = *opts.MaxAttempts
+		if attempts < 0 {
+			attempts = 0
+		}
+		if attempts > e.maxRunAttempts {
+			execLog.Infof("Clamping requested retry attempts %d to maximum %d", attempts, e.maxRunAttempts)
+			attempts = e.maxRunAttempts
+		}
+	}
+
+	// A run's attempt override applies to every node type, and jittered
+	// backoff would make retry timing differ between deterministic runs.
+	// Overrides are applied to copies so the executor's policies are untouched.
+	adjust := func(p *retry.RetryPolicy) *retry.RetryPolicy {
+		if attempts < 0 && !(policy.deterministic && p.JitterFraction > 0) {
+			return p
+		}
+		override := *p
+		if attempts >= 0 {
+			override.MaxAttempts = attempts
+		}
+		if policy.deterministic {
+			override.JitterFraction = 0
+		}
+		return &override
+	}
+	policy.retry = adjust(e.retryPolicy)
+	if len(e.nodeRetryPolicies) > 0 {
+		policy.nodeRetry = make(map[string]*retry.RetryPolicy, len(e.nodeRetryPolicies))
+		for nodeType, p := range e.nodeRetryPolicies {
+			policy.nodeRetry[nodeType] = adjust(p)
+		}
+	}
+
+	if opts.IgnoreCircuitBreakers {
+		if e.allowBreakerBypass {
+			policy.honorBreakers = false
+		} else {
+			execLog.Infof("Ignoring circuit breaker bypass request: not allowed by server configuration")
+		}
+	}
+
+	return policy
+}
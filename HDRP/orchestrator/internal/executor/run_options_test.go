@@ -0,0 +1,217 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestResolveRunPolicy(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: time.Second})
+	executor.SetRunOverrideLimits(4, false)
+
+	tests := []struct {
+		name         string
+		opts         RunOptions
+		wantAttempts int
+		wantBreakers bool
+		allowBypass  bool
+	}{
+		{name: "Defaults", opts: RunOptions{}, wantAttempts: 3, wantBreakers: true},
+		{name: "Reduced", opts: RunOptions{MaxAttempts: intPtr(1)}, wantAttempts: 1, wantBreakers: true},
+		{name: "Clamped to maximum", opts: RunOptions{MaxAttempts: intPtr(10)}, wantAttempts: 4, wantBreakers: true},
+		{name: "Negative", opts: RunOptions{MaxAttempts: intPtr(-1)}, wantAttempts: 0, wantBreakers: true},
+		{name: "Bypass not allowed", opts: RunOptions{IgnoreCircuitBreakers: true}, wantAttempts: 3, wantBreakers: true},
+		{name: "Bypass allowed", opts: RunOptions{IgnoreCircuitBreakers: true}, wantAttempts: 3, wantBreakers: false, allowBypass: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor.SetRunOverrideLimits(4, tt.allowBypass)
+			policy := executor.resolveRunPolicy(tt.opts)
+			if policy.retry.MaxAttempts != tt.wantAttempts {
+				t.Errorf("MaxAttempts = %d, want %d", policy.retry.MaxAttempts, tt.wantAttempts)
+			}
+			if policy.honorBreakers != tt.wantBreakers {
+				t.Errorf("honorBreakers = %v, want %v", policy.honorBreakers, tt.wantBreakers)
+			}
+		})
+	}
+
+	// Overrides must not leak into the executor's own policy
+	if executor.retryPolicy.MaxAttempts != 3 {
+		t.Errorf("Executor policy modified: MaxAttempts = %d", executor.retryPolicy.MaxAttempts)
+	}
+}
+
+// TestResolveRunPolicy_DeterministicDropsJitter verifies deterministic runs back off by exact delays
+func TestResolveRunPolicy_DeterministicDropsJitter(t *testing.T) {
+	executor := NewDAGExecutor(&clients.ServiceClients{}, 1)
+	executor.SetRetryPolicy(retry.DefaultPolicy())
+
+	if policy := executor.resolveRunPolicy(RunOptions{}); policy.retry.JitterFraction != retry.DefaultJitterFraction {
+		t.Errorf("JitterFraction = %v, want %v", policy.retry.JitterFraction, retry.DefaultJitterFraction)
+	}
+	policy := executor.resolveRunPolicy(RunOptions{Deterministic: true, MaxAttempts: intPtr(1)})
+	if policy.retry.JitterFraction != 0 || policy.retry.MaxAttempts != 1 {
+		t.Errorf("Deterministic policy = %+v, want no jitter and 1 attempt", policy.retry)
+	}
+	if executor.retryPolicy.JitterFraction != retry.DefaultJitterFraction {
+		t.Errorf("Executor policy modified: JitterFraction = %v", executor.retryPolicy.JitterFraction)
+	}
+}
+
+// TestExecuteWithOptions_ReducedRetries verifies a run honors a lower retry budget than the executor default
+func TestExecuteWithOptions_ReducedRetries(t *testing.T) {
+	researcher := &mockResearcherClient{maxFailures: 100, failureType: context.DeadlineExceeded}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+
+	result, err := executor.ExecuteWithOptions(context.Background(), researchCriticGraph("test-run-options", false), "test-run-options", RunOptions{MaxAttempts: intPtr(1)})
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected failure with an always-failing researcher")
+	}
+	if got := researcher.calls(); got != 2 {
+		t.Errorf("Expected 2 researcher calls (1 retry), got %d", got)
+	}
+}
+
+// TestNodeRetryPolicy verifies nodes retry under their type's policy, and run overrides apply to it
+func TestNodeRetryPolicy(t *testing.T) {
+	researcher := &mockResearcherClient{maxFailures: 100, failureType: context.DeadlineExceeded}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 1, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+	executor.SetNodeRetryPolicy("researcher", &retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond, JitterFraction: 0.5})
+
+	policy := executor.resolveRunPolicy(RunOptions{})
+	if got := policy.retryFor("researcher").MaxAttempts; got != 3 {
+		t.Errorf("researcher MaxAttempts = %d, want 3", got)
+	}
+	if got := policy.retryFor("critic").MaxAttempts; got != 1 {
+		t.Errorf("critic MaxAttempts = %d, want the default 1", got)
+	}
+	policy = executor.resolveRunPolicy(RunOptions{MaxAttempts: intPtr(2), Deterministic: true})
+	if got := policy.retryFor("researcher"); got.MaxAttempts != 2 || got.JitterFraction != 0 {
+		t.Errorf("Overridden researcher policy = %+v, want 2 attempts and no jitter", got)
+	}
+	if executor.nodeRetryPolicies["researcher"].MaxAttempts != 3 {
+		t.Error("Run override modified the executor's researcher policy")
+	}
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-node-retry", false), "test-node-retry")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected failure with an always-failing researcher")
+	}
+	if got := researcher.calls(); got != 4 {
+		t.Errorf("Expected 4 researcher calls (3 retries), got %d", got)
+	}
+
+	executor.SetNodeRetryPolicy("researcher", nil)
+	if got := executor.resolveRunPolicy(RunOptions{}).retryFor("researcher").MaxAttempts; got != 1 {
+		t.Errorf("researcher MaxAttempts after reset = %d, want the default 1", got)
+	}
+}
+
+// TestExecuteWithOptions_IgnoreCircuitBreakers verifies an allowed bypass runs nodes behind an open breaker
+func TestExecuteWithOptions_IgnoreCircuitBreakers(t *testing.T) {
+	researcher := &mockResearcherClient{}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRunOverrideLimits(DefaultMaxRunAttempts, true)
+
+	// Trip the researcher breaker
+	for i := 0; i < 10; i++ {
+		executor.circuitBreakers.RecordFailure("researcher")
+	}
+	if executor.circuitBreakers.ShouldAllow("researcher") {
+		t.Fatal("Expected researcher breaker to be open")
+	}
+
+	graph := researchCriticGraph("test-run-bypass", false)
+	result, err := executor.ExecuteWithOptions(context.Background(), graph, "test-run-bypass", RunOptions{IgnoreCircuitBreakers: true})
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success with breaker bypass, got: %s", result.ErrorMessage)
+	}
+	if graph.Nodes[0].Status != dag.StatusSucceeded {
+		t.Errorf("Expected researcher to succeed, got %s", graph.Nodes[0].Status)
+	}
+}
+
+func TestMaxConcurrentRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		cap      int
+		wantPeak func(peak int) bool
+	}{
+		{name: "Capped", cap: 2, wantPeak: func(peak int) bool { return peak >= 1 && peak <= 2 }},
+		{name: "Unlimited", cap: 0, wantPeak: func(peak int) bool { return peak > 2 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			researcher := &mockResearcherClient{
+				shouldFail:  func(int) bool { return true },
+				failureType: status.Error(codes.Unavailable, "backend outage"),
+			}
+			const nodes = 8
+			executor := NewDAGExecutor(&clients.ServiceClients{
+				Researcher:  researcher,
+				Critic:      &mockCriticClient{},
+				Synthesizer: &mockSynthesizerClient{},
+			}, nodes)
+			t.Cleanup(func() { executor.Close() })
+			executor.SetRetryPolicy(&retry.RetryPolicy{
+				MaxAttempts:       2,
+				InitialDelay:      20 * time.Millisecond,
+				MaxDelay:          20 * time.Millisecond,
+				BackoffMultiplier: 1,
+			})
+			executor.SetRunOverrideLimits(DefaultMaxRunAttempts, true)
+			executor.SetMaxConcurrentRetries(tt.cap)
+
+			graph := independentResearchGraph("graph-retry-cap-"+tt.name, nodes)
+			result, err := executor.ExecuteWithOptions(context.Background(), graph, "run-retry-cap-"+tt.name, RunOptions{IgnoreCircuitBreakers: true})
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+
+			// Every node still gets all its retries
+			if got := researcher.calls(); got != nodes*3 {
+				t.Errorf("Expected %d research calls, got %d", nodes*3, got)
+			}
+			if peak := result.RetryMetrics.PeakConcurrentBackoffs(); !tt.wantPeak(peak) {
+				t.Errorf("Unexpected peak concurrent backoffs %d with cap %d", peak, tt.cap)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package executor
+
+import (
+	"context"
+	"strconv"
+
+	"hdrp/internal/concurrency"
+	"hdrp/internal/dag"
+)
+
+// MetadataPriority is the graph metadata key holding a run's priority when
+// RunOptions.Priority isn't set. Resumed runs keep the priority they started with.
+const MetadataPriority = "priority"
+
+// SetGlobalWorkerLimit caps how many nodes run at once across all runs of
+// the executor. Nodes beyond the cap wait for a slot, and freed slots go to
+// the waiting node from the highest-priority run (see RunOptions.Priority).
+// A waiting node gains agingPerSecond priority for every second it waits, so
+// low-priority runs still progress under a steady stream of urgent work.
+// limit <= 0 removes the cap, leaving only each run's own worker limit.
+func (e *DAGExecutor) SetGlobalWorkerLimit(limit int, agingPerSecond float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if limit <= 0 {
+		e.runSlots = nil
+		return
+	}
+	e.runSlots = concurrency.NewPrioritySemaphore(limit, agingPerSecond)
+}
+
+// runPriority resolves a run's priority: the run option if set, otherwise the
+// graph's priority metadata, otherwise 0.
+func runPriority(graph *dag.Graph, opts RunOptions) int {
+	if opts.Priority != nil {
+		return *opts.Priority
+	}
+	raw, ok := graph.Metadata[MetadataPriority]
+	if !ok || raw == "" {
+		return 0
+	}
+	priority, err := strconv.Atoi(raw)
+	if err != nil {
+		execLog.Infof("Ignoring invalid priority %q on graph %s", raw, graph.ID)
+		return 0
+	}
+	return priority
+}
+
+// acquireRunSlot waits for a shared worker slot if a global limit is set.
+// The returned release function must be called once the node is done.
+func (e *DAGExecutor) acquireRunSlot(ctx context.Context, priority int) (func(), error) {
+	e.mu.RLock()
+	slots := e.runSlots
+	e.mu.RUnlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+	if err := slots.Acquire(ctx, priority); err != nil {
+		return nil, err
+	}
+	return slots.Release, nil
+}
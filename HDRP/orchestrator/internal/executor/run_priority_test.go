@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// orderRecordingResearcher records the order nodes start in. The first call
+// blocks until gate is closed.
+type orderRecordingResearcher struct {
+	mu    sync.Mutex
+	order []string
+	gate  chan struct{}
+}
+
+func (r *orderRecordingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	r.order = append(r.order, req.SourceNodeId)
+	first := len(r.order) == 1
+	r.mu.Unlock()
+	if first {
+		<-r.gate
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func (r *orderRecordingResearcher) started() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.order...)
+}
+
+func TestGlobalWorkerLimit_HighPriorityRunSchedulesFirst(t *testing.T) {
+	researcher := &orderRecordingResearcher{gate: make(chan struct{})}
+	executor := NewDAGExecutor(&clients.ServiceClients{Researcher: researcher}, 4)
+	executor.SetGlobalWorkerLimit(1, 0)
+
+	lowGraph := &dag.Graph{
+		ID:       "priority-low",
+		Status:   dag.StatusCreated,
+		Metadata: map[string]string{MetadataPriority: "0"},
+	}
+	for _, id := range []string{"low-a", "low-b", "low-c", "low-d"} {
+		lowGraph.Nodes = append(lowGraph.Nodes, dag.Node{ID: id, Type: "researcher", Config: map[string]string{"query": id}, Status: dag.StatusCreated})
+	}
+	highGraph := &dag.Graph{
+		ID:     "priority-high",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "high", Type: "researcher", Config: map[string]string{"query": "high"}, Status: dag.StatusCreated},
+		},
+	}
+
+	var wg sync.WaitGroup
+	results := make(map[string]*ExecutionResult)
+	var resultsMu sync.Mutex
+	run := func(graph *dag.Graph, opts RunOptions) {
+		defer wg.Done()
+		result, err := executor.ExecuteWithOptions(context.Background(), graph, "run-"+graph.ID, opts)
+		if err != nil {
+			t.Errorf("Execute(%s) error = %v", graph.ID, err)
+			return
+		}
+		resultsMu.Lock()
+		results[graph.ID] = result
+		resultsMu.Unlock()
+	}
+
+	// The long low-priority run takes the only slot and queues its other nodes
+	wg.Add(1)
+	go run(lowGraph, RunOptions{})
+	waitFor(t, func() bool { return len(researcher.started()) == 1 && executor.runSlots.Waiting() == 3 })
+
+	priority := 10
+	wg.Add(1)
+	go run(highGraph, RunOptions{Priority: &priority})
+	waitFor(t, func() bool { return executor.runSlots.Waiting() == 4 })
+
+	close(researcher.gate)
+	wg.Wait()
+
+	order := researcher.started()
+	if len(order) != 5 {
+		t.Fatalf("Expected 5 nodes to run, got %v", order)
+	}
+	if order[1] != "high" {
+		t.Errorf("Expected the high-priority node to get the next slot, got order %v", order)
+	}
+	for id, result := range results {
+		if !result.Success {
+			t.Errorf("Run %s failed: %+v", id, result)
+		}
+	}
+	if highGraph.Metadata[MetadataPriority] != "10" {
+		t.Errorf("Expected run priority recorded in metadata, got %q", highGraph.Metadata[MetadataPriority])
+	}
+}
+
+func TestRunPriority(t *testing.T) {
+	override := 3
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		opts     RunOptions
+		want     int
+	}{
+		{name: "default", want: 0},
+		{name: "metadata", metadata: map[string]string{MetadataPriority: "5"}, want: 5},
+		{name: "option wins", metadata: map[string]string{MetadataPriority: "5"}, opts: RunOptions{Priority: &override}, want: 3},
+		{name: "invalid metadata", metadata: map[string]string{MetadataPriority: "urgent"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := &dag.Graph{ID: "g", Metadata: tt.metadata}
+			if got := runPriority(graph, tt.opts); got != tt.want {
+				t.Errorf("runPriority() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// waitFor polls cond until it holds or a second passes.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
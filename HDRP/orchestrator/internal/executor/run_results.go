@@ -0,0 +1,84 @@
+package executor
+
+import (
+	"errors"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+)
+
+// RunResultStore is implemented by storage that keeps the final outcome of
+// each run.
+type RunResultStore interface {
+	SaveRunResult(result *storage.RunResult) error
+	LoadRunResult(runID string) (*storage.RunResult, error)
+}
+
+// RunStatsStore is implemented by storage that can aggregate run results.
+type RunStatsStore interface {
+	GetRunStats(window time.Duration) (*storage.RunStats, error)
+}
+
+// ErrRunResultsUnsupported is returned by LoadRunResult when the storage
+// backend does not keep run results.
+var ErrRunResultsUnsupported = errors.New("storage backend does not keep run results")
+
+// ErrRunStatsUnsupported is returned by RunStats when the storage backend
+// cannot aggregate run results.
+var ErrRunStatsUnsupported = errors.New("storage backend does not aggregate run results")
+
+// saveRunResult persists the outcome of a run started at startedAt. A run
+// that returned an error is stored as unsuccessful with the error as its
+// message.
+func (e *DAGExecutor) saveRunResult(graph *dag.Graph, runID string, startedAt time.Time, result *ExecutionResult, execErr error) {
+	store, ok := e.storage.(RunResultStore)
+	if !ok {
+		return
+	}
+
+	record := &storage.RunResult{
+		RunID:       runID,
+		GraphID:     graph.ID,
+		Status:      string(graph.Status),
+		StartedAt:   startedAt,
+		CompletedAt: time.Now(),
+	}
+	if result != nil {
+		record.Success = result.Success
+		record.PartialSuccess = result.PartialSuccess
+		record.FinalReport = result.FinalReport
+		record.ArtifactURI = result.ArtifactURI
+		record.ReportTruncated = result.ReportTruncated
+		record.ErrorMessage = result.ErrorMessage
+		record.SucceededNodes = len(result.SucceededNodes)
+		record.FailedNodes = len(result.FailedNodes)
+	}
+	if execErr != nil {
+		record.ErrorMessage = execErr.Error()
+	}
+
+	if err := store.SaveRunResult(record); err != nil {
+		execLog.Warnf("failed to save result of run %s: %v", runID, err)
+	}
+}
+
+// LoadRunResult returns the persisted outcome of a finished run. Returns
+// sql.ErrNoRows if the run has no stored result.
+func (e *DAGExecutor) LoadRunResult(runID string) (*storage.RunResult, error) {
+	store, ok := e.storage.(RunResultStore)
+	if !ok {
+		return nil, ErrRunResultsUnsupported
+	}
+	return store.LoadRunResult(runID)
+}
+
+// RunStats aggregates the runs that finished within window of now, or every
+// stored run when window <= 0.
+func (e *DAGExecutor) RunStats(window time.Duration) (*storage.RunStats, error) {
+	store, ok := e.storage.(RunStatsStore)
+	if !ok {
+		return nil, ErrRunStatsUnsupported
+	}
+	return store.GetRunStats(window)
+}
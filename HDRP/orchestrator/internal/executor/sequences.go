@@ -0,0 +1,22 @@
+package executor
+
+// SequenceModeSetter is implemented by storage that can choose how WAL
+// sequence numbers are allocated.
+type SequenceModeSetter interface {
+	SetSequenceMode(mode string) error
+}
+
+// SetWALSequenceMode selects how the storage allocates WAL sequence
+// numbers: "memory" (the default) counts in process, "database" reserves
+// each number in the database so several processes can share it.
+func (e *DAGExecutor) SetWALSequenceMode(mode string) error {
+	if mode == "" || e.storage == nil {
+		return nil
+	}
+	setter, ok := e.storage.(SequenceModeSetter)
+	if !ok {
+		execLog.Infof("Storage backend allocates its own WAL sequences, ignoring mode %q", mode)
+		return nil
+	}
+	return setter.SetSequenceMode(mode)
+}
@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"hdrp/internal/storage"
+)
+
+// SnapshotLagReporter is implemented by storage that can report how many
+// WAL entries a graph has logged since its latest snapshot.
+type SnapshotLagReporter interface {
+	SnapshotLag(graphID string) (int64, error)
+}
+
+// SnapshotStoreSetter is implemented by storage that can keep snapshot
+// payloads outside its database.
+type SnapshotStoreSetter interface {
+	SetSnapshotStore(store storage.SnapshotStore, minBytes int)
+}
+
+// SetSnapshotStore stores snapshots of at least minBytes in store, keeping
+// only a reference in the database.
+func (e *DAGExecutor) SetSnapshotStore(store storage.SnapshotStore, minBytes int) {
+	if e.storage == nil {
+		return
+	}
+	setter, ok := e.storage.(SnapshotStoreSetter)
+	if !ok {
+		execLog.Infof("Storage backend does not support external snapshots, keeping them inline")
+		return
+	}
+	setter.SetSnapshotStore(store, minBytes)
+}
+
+// SetSnapshotInterval makes every run snapshot its graph on a fixed
+// schedule, in addition to the snapshots taken as the WAL grows. Graphs that
+// stall with few transitions then still recover from a recent snapshot. A
+// non-positive interval disables periodic snapshots.
+func (e *DAGExecutor) SetSnapshotInterval(interval time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.snapshotInterval = interval
+}
+
+// startPeriodicSnapshots snapshots a graph every snapshot interval until the
+// returned stop function is called. Ticks where nothing was logged since the
+// last snapshot are skipped.
+func (e *DAGExecutor) startPeriodicSnapshots(graphID string) func() {
+	e.mu.RLock()
+	interval := e.snapshotInterval
+	e.mu.RUnlock()
+
+	if interval <= 0 || e.storage == nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				e.snapshotIfBehind(graphID)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// snapshotIfBehind creates a snapshot unless the storage reports that the
+// latest one is already current.
+func (e *DAGExecutor) snapshotIfBehind(graphID string) {
+	if reporter, ok := e.storage.(SnapshotLagReporter); ok {
+		lag, err := reporter.SnapshotLag(graphID)
+		if err != nil {
+			execLog.Warnf("failed to check snapshot lag for graph %s: %v", graphID, err)
+			return
+		}
+		if lag == 0 {
+			return
+		}
+	}
+
+	if err := e.storage.CreateSnapshot(graphID); err != nil {
+		execLog.Warnf("periodic snapshot of graph %s failed: %v", graphID, err)
+	}
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// snapshotProbeResearcher stalls, then records whether its graph had been
+// snapshotted while it was running.
+type snapshotProbeResearcher struct {
+	store   *storage.SQLiteStorage
+	graphID string
+	stall   time.Duration
+
+	snapshotted bool
+}
+
+func (r *snapshotProbeResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	time.Sleep(r.stall)
+	snapshot, err := r.store.LoadSnapshot(r.graphID)
+	r.snapshotted = err == nil && snapshot != nil
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func TestPeriodicSnapshots_StalledGraph(t *testing.T) {
+	tests := []struct {
+		name         string
+		interval     time.Duration
+		wantSnapshot bool
+	}{
+		{name: "Disabled", interval: 0, wantSnapshot: false},
+		{name: "Every 50ms", interval: 50 * time.Millisecond, wantSnapshot: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := newTestExecutor(t, mockServiceClients())
+			executor.SetSnapshotInterval(tt.interval)
+
+			// A single slow node logs far fewer than the 100 WAL entries
+			// that trigger a transition-driven snapshot
+			graph := &dag.Graph{
+				ID:     "graph-stalled-" + tt.name,
+				Status: dag.StatusCreated,
+				Nodes: []dag.Node{
+					{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+				},
+			}
+			researcher := &snapshotProbeResearcher{
+				store:   executor.storage.(*storage.SQLiteStorage),
+				graphID: graph.ID,
+				stall:   300 * time.Millisecond,
+			}
+			executor.clients.Researcher = researcher
+
+			result, err := executor.Execute(context.Background(), graph, "run-stalled")
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+			}
+
+			if researcher.snapshotted != tt.wantSnapshot {
+				t.Errorf("Snapshot taken while stalled = %v, want %v", researcher.snapshotted, tt.wantSnapshot)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"time"
+)
+
+// OpTimeoutSetter is implemented by storage that bounds how long a single
+// query or statement may run.
+type OpTimeoutSetter interface {
+	SetOpTimeout(timeout time.Duration)
+}
+
+// SetStorageOpTimeout bounds each storage operation so a locked or wedged
+// database fails fast rather than stalling the run. A non-positive timeout
+// keeps the storage default.
+func (e *DAGExecutor) SetStorageOpTimeout(timeout time.Duration) {
+	if timeout <= 0 || e.storage == nil {
+		return
+	}
+	setter, ok := e.storage.(OpTimeoutSetter)
+	if !ok {
+		execLog.Infof("Storage backend does not support operation timeouts, ignoring %v", timeout)
+		return
+	}
+	setter.SetOpTimeout(timeout)
+}
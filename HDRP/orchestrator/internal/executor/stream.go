@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"io"
+	"time"
+
+	"hdrp/internal/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serverStream is the receiving side of a server-streaming RPC.
+type serverStream[T any] interface {
+	Recv() (T, error)
+}
+
+// receiveStream opens a server-streaming RPC to service with open and
+// passes each response to handle, with its index, until the stream ends.
+// The call's latency is recorded under method and its rate limit hints are
+// applied. Returns unsupported if the service rejects the RPC before
+// sending anything.
+func receiveStream[T any](
+	e *DAGExecutor,
+	service, method string,
+	unsupported error,
+	open func(opts ...grpc.CallOption) (serverStream[T], error),
+	handle func(index int, resp T) error,
+) error {
+	startTime := time.Now()
+	var hints rateLimitHints
+	stream, err := open(hints.callOptions()...)
+	defer e.applyRateLimitHints(service, &hints)
+	if status.Code(err) == codes.Unimplemented {
+		return unsupported
+	}
+	if err != nil {
+		metrics.RecordRPCLatency(service, method, time.Since(startTime).Seconds(), false)
+		return err
+	}
+
+	for index := 0; ; index++ {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if index == 0 && status.Code(err) == codes.Unimplemented {
+				return unsupported
+			}
+			metrics.RecordRPCLatency(service, method, time.Since(startTime).Seconds(), false)
+			return err
+		}
+		if err := handle(index, resp); err != nil {
+			return err
+		}
+	}
+	metrics.RecordRPCLatency(service, method, time.Since(startTime).Seconds(), true)
+	return nil
+}
@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+)
+
+// EventSynthesisChunk carries a piece of a report as the synthesizer writes it.
+const EventSynthesisChunk EventType = "synthesis_chunk"
+
+// errStreamingUnsupported signals that the synthesizer cannot stream and the
+// unary RPC should be used instead.
+var errStreamingUnsupported = errors.New("synthesizer does not support streaming")
+
+// synthesize produces a report, streaming partial output to event
+// subscribers when the synthesizer supports it and falling back to the unary
+// RPC otherwise. Reports over the report limit are spilled to the artifact
+// store: the response then holds a preview and the artifact URI, and
+// truncated is true.
+func (e *DAGExecutor) synthesize(ctx context.Context, node *dag.Node, graphID string, req *pb.SynthesizeRequest) (resp *pb.SynthesizeResponse, truncated bool, err error) {
+	report := e.newReportBuffer(req.RunId, node.ID)
+	defer report.Abort()
+
+	resp, err = e.synthesizeInto(ctx, node, graphID, req, report)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp.Report, err = report.Finish()
+	if err != nil {
+		return nil, false, err
+	}
+	if report.Spilled() {
+		if resp.ArtifactUri != "" {
+			execLog.Infof("Synthesizer node %s: replacing artifact %s with full report %s", node.ID, resp.ArtifactUri, report.uri)
+		}
+		resp.ArtifactUri = report.uri
+	}
+	return resp, report.Spilled(), nil
+}
+
+// synthesizeInto runs the synthesis RPC, writing the report into report.
+func (e *DAGExecutor) synthesizeInto(ctx context.Context, node *dag.Node, graphID string, req *pb.SynthesizeRequest, report *reportBuffer) (*pb.SynthesizeResponse, error) {
+	synthesizer := e.serviceClients(ctx).Synthesizer
+	if streamer, ok := synthesizer.(clients.StreamingSynthesizer); ok {
+		resp, err := e.streamSynthesis(ctx, streamer, node, graphID, req, report)
+		if !errors.Is(err, errStreamingUnsupported) {
+			return resp, err
+		}
+		execLog.Infof("Synthesizer node %s: streaming unavailable, using unary RPC", node.ID)
+	}
+
+	var hints rateLimitHints
+	resp, err := synthesizer.Synthesize(ctx, req, hints.callOptions()...)
+	e.applyRateLimitHints("synthesizer", &hints)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := report.WriteString(resp.Report); err != nil {
+		return nil, err
+	}
+	resp.Report = ""
+	return resp, nil
+}
+
+// streamSynthesis forwards each chunk as an EventSynthesisChunk and writes
+// the report into report. Returns errStreamingUnsupported if the service
+// rejects the streaming RPC before sending anything.
+func (e *DAGExecutor) streamSynthesis(
+	ctx context.Context,
+	streamer clients.StreamingSynthesizer,
+	node *dag.Node,
+	graphID string,
+	req *pb.SynthesizeRequest,
+	report *reportBuffer,
+) (*pb.SynthesizeResponse, error) {
+	resp := &pb.SynthesizeResponse{}
+	open := func(opts ...grpc.CallOption) (serverStream[*pb.SynthesizeResponse], error) {
+		return streamer.SynthesizeStream(ctx, req, opts...)
+	}
+	err := receiveStream(e, "synthesizer", "SynthesizeStream", errStreamingUnsupported, open, func(index int, chunk *pb.SynthesizeResponse) error {
+		if err := report.WriteString(chunk.Report); err != nil {
+			return err
+		}
+		if chunk.ArtifactUri != "" {
+			resp.ArtifactUri = chunk.ArtifactUri
+		}
+		e.emitEvent(Event{
+			Type:    EventSynthesisChunk,
+			RunID:   req.RunId,
+			GraphID: graphID,
+			NodeID:  node.ID,
+			Data: map[string]string{
+				"index": strconv.Itoa(index),
+				"chunk": chunk.Report,
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
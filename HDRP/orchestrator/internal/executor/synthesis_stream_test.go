@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"hdrp/internal/clients"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamingSynthesizerClient streams a fixed report in chunks. With
+// unimplemented set it behaves like a service without the streaming RPC;
+// with streamErr set the stream fails with it after the last chunk.
+type streamingSynthesizerClient struct {
+	chunks        []string
+	unimplemented bool
+	streamErr     error
+}
+
+func (m *streamingSynthesizerClient) Synthesize(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (*pb.SynthesizeResponse, error) {
+	return &pb.SynthesizeResponse{Report: strings.Join(m.chunks, "")}, nil
+}
+
+func (m *streamingSynthesizerClient) SynthesizeStream(ctx context.Context, req *pb.SynthesizeRequest, opts ...grpc.CallOption) (clients.SynthesizeStream, error) {
+	if m.unimplemented {
+		return nil, status.Error(codes.Unimplemented, "unknown method SynthesizeStream")
+	}
+	return &chunkStream{chunks: m.chunks, err: m.streamErr}, nil
+}
+
+type chunkStream struct {
+	chunks []string
+	next   int
+	err    error
+}
+
+func (s *chunkStream) Recv() (*pb.SynthesizeResponse, error) {
+	if s.next >= len(s.chunks) {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	chunk := &pb.SynthesizeResponse{Report: s.chunks[s.next]}
+	if s.next == len(s.chunks)-1 {
+		chunk.ArtifactUri = "file:///tmp/report.md"
+	}
+	s.next++
+	return chunk, nil
+}
+
+func TestSynthesizerStreaming(t *testing.T) {
+	chunks := []string{"# Report\n", "Batteries ", "are improving.\n"}
+
+	tests := []struct {
+		name          string
+		unimplemented bool
+		wantChunks    []string
+	}{
+		{name: "Streams chunks", wantChunks: chunks},
+		{name: "Falls back to unary", unimplemented: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := NewDAGExecutor(&clients.ServiceClients{
+				Researcher:  &mockResearcherClient{},
+				Critic:      &echoCriticClient{},
+				Synthesizer: &streamingSynthesizerClient{chunks: chunks, unimplemented: tt.unimplemented},
+			}, 2)
+
+			var mu sync.Mutex
+			var forwarded []string
+			executor.SetEventHandler(func(evt Event) {
+				if evt.Type != EventSynthesisChunk {
+					return
+				}
+				mu.Lock()
+				forwarded = append(forwarded, evt.Data["chunk"])
+				mu.Unlock()
+			})
+
+			runID := "run-stream-" + strings.ReplaceAll(tt.name, " ", "-")
+			result, err := executor.Execute(context.Background(), researchCriticGraph("graph-"+runID, true), runID)
+			if err != nil {
+				t.Fatalf("Execution error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Expected success, got: %s", result.ErrorMessage)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if strings.Join(forwarded, "|") != strings.Join(tt.wantChunks, "|") {
+				t.Errorf("Forwarded chunks = %q, want %q", forwarded, tt.wantChunks)
+			}
+			if want := strings.Join(chunks, ""); result.FinalReport != want {
+				t.Errorf("FinalReport = %q, want %q", result.FinalReport, want)
+			}
+			if !tt.unimplemented && result.ArtifactURI != "file:///tmp/report.md" {
+				t.Errorf("ArtifactURI = %q, want the streamed URI", result.ArtifactURI)
+			}
+		})
+	}
+}
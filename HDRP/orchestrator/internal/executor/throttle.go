@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys backends use to ask callers to slow down. retry-after is a
+// delay in seconds (or a Go duration such as "1500ms"); a remaining quota of
+// zero with a reset delay has the same effect.
+const (
+	mdRetryAfter     = "retry-after"
+	mdQuotaRemaining = "x-ratelimit-remaining"
+	mdQuotaReset     = "x-ratelimit-reset"
+)
+
+// maxThrottleCooldown bounds how long a single hint can pause a service.
+const maxThrottleCooldown = 5 * time.Minute
+
+// rateLimitHints captures the response headers and trailers of an RPC so
+// rate-limit hints can be read after the call.
+type rateLimitHints struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+// callOptions returns the options that populate the hints when passed to an RPC.
+func (h *rateLimitHints) callOptions() []grpc.CallOption {
+	return []grpc.CallOption{grpc.Header(&h.header), grpc.Trailer(&h.trailer)}
+}
+
+// cooldown returns how long the backend asked callers to wait, if at all.
+func (h *rateLimitHints) cooldown() (time.Duration, bool) {
+	for _, md := range []metadata.MD{h.trailer, h.header} {
+		if d, ok := parseHintDelay(md, mdRetryAfter); ok {
+			return d, true
+		}
+		if remaining := md.Get(mdQuotaRemaining); len(remaining) > 0 && strings.TrimSpace(remaining[0]) == "0" {
+			if d, ok := parseHintDelay(md, mdQuotaReset); ok {
+				return d, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseHintDelay reads a delay in seconds or as a Go duration. Delays that
+// aren't positive finite numbers are ignored, and ones beyond
+// maxThrottleCooldown are capped before they can overflow a Duration.
+func parseHintDelay(md metadata.MD, key string) (time.Duration, bool) {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return 0, false
+	}
+	v := strings.TrimSpace(values[0])
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		// ParseFloat also accepts "NaN" and "Inf"
+		if math.IsNaN(secs) || math.IsInf(secs, 0) || secs <= 0 {
+			return 0, false
+		}
+		if secs >= maxThrottleCooldown.Seconds() {
+			return maxThrottleCooldown, true
+		}
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if d, err := time.ParseDuration(v); err == nil && d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// applyRateLimitHints pauses a service's rate limiter if its last response
+// asked callers to back off, so a throttled backend isn't hammered.
+func (e *DAGExecutor) applyRateLimitHints(service string, hints *rateLimitHints) {
+	d, ok := hints.cooldown()
+	if !ok {
+		return
+	}
+	if d > maxThrottleCooldown {
+		d = maxThrottleCooldown
+	}
+	execLog.Infof("%s asked to back off, pausing new calls for %v", service, d)
+	e.rateLimiters.GetLimiter(service).Throttle(d)
+}
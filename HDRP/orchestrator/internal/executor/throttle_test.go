@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// throttledResearcherClient rejects its first call with a retry-after
+// trailer, then succeeds, recording when each call arrived.
+type throttledResearcherClient struct {
+	retryAfter string
+
+	mu    sync.Mutex
+	calls []time.Time
+}
+
+func (m *throttledResearcherClient) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, time.Now())
+	first := len(m.calls) == 1
+	m.mu.Unlock()
+
+	if first {
+		for _, opt := range opts {
+			if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+				*trailer.TrailerAddr = metadata.Pairs("retry-after", m.retryAfter)
+			}
+		}
+		return nil, status.Error(codes.ResourceExhausted, "quota exceeded")
+	}
+	return &pb.ResearchResponse{Claims: []*pb.AtomicClaim{{Statement: "claim", SourceNodeId: req.SourceNodeId}}}, nil
+}
+
+func TestRateLimitHints_RetryAfterTrailer(t *testing.T) {
+	researcher := &throttledResearcherClient{retryAfter: "0.2"}
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  researcher,
+		Critic:      &mockCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+
+	graph := &dag.Graph{
+		ID:     "graph-retry-after",
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+		},
+	}
+	result, err := executor.Execute(context.Background(), graph, "run-retry-after")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	researcher.mu.Lock()
+	defer researcher.mu.Unlock()
+	if len(researcher.calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %d", len(researcher.calls))
+	}
+	// The 1ms retry backoff is stretched to the 200ms the backend asked for
+	if gap := researcher.calls[1].Sub(researcher.calls[0]); gap < 180*time.Millisecond {
+		t.Errorf("Retry came %v after the throttled call, expected the retry-after cooldown", gap)
+	}
+}
+
+func TestRateLimitHints_Cooldown(t *testing.T) {
+	tests := []struct {
+		name    string
+		trailer metadata.MD
+		header  metadata.MD
+		want    time.Duration
+	}{
+		{name: "None", want: 0},
+		{name: "Retry-after seconds", trailer: metadata.Pairs("retry-after", "2"), want: 2 * time.Second},
+		{name: "Retry-after duration in header", header: metadata.Pairs("retry-after", "1500ms"), want: 1500 * time.Millisecond},
+		{name: "Exhausted quota", trailer: metadata.Pairs("x-ratelimit-remaining", "0", "x-ratelimit-reset", "3"), want: 3 * time.Second},
+		{name: "Quota left", trailer: metadata.Pairs("x-ratelimit-remaining", "10", "x-ratelimit-reset", "3"), want: 0},
+		{name: "Unparseable", trailer: metadata.Pairs("retry-after", "soon"), want: 0},
+		{name: "Negative", trailer: metadata.Pairs("retry-after", "-5"), want: 0},
+		{name: "NaN", trailer: metadata.Pairs("retry-after", "NaN"), want: 0},
+		{name: "Infinite", trailer: metadata.Pairs("retry-after", "+Inf"), want: 0},
+		{name: "Huge delay capped", trailer: metadata.Pairs("retry-after", "1e300"), want: maxThrottleCooldown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints := rateLimitHints{header: tt.header, trailer: tt.trailer}
+			got, _ := hints.cooldown()
+			if got != tt.want {
+				t.Errorf("cooldown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/storage"
+)
+
+var (
+	// ErrTimelineUnsupported is returned when the storage backend keeps no node history.
+	ErrTimelineUnsupported = errors.New("storage backend does not support node history")
+	// ErrRunNotFound is returned when no stored graph belongs to a run.
+	ErrRunNotFound = errors.New("run not found")
+)
+
+// NodeHistoryStore is implemented by storage backends that can reconstruct
+// node status history for a run.
+type NodeHistoryStore interface {
+	FindGraphByRunID(runID string) (string, error)
+	LoadNodes(graphID string) ([]*storage.NodeState, error)
+	LoadNodeHistory(graphID string) ([]storage.NodeTransition, error)
+}
+
+// TimelineSpan is the execution window of one node.
+type TimelineSpan struct {
+	NodeID   string    `json:"node_id"`
+	NodeType string    `json:"node_type"`
+	Status   string    `json:"status"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Attempts int       `json:"attempts"` // Times the node entered RUNNING
+	Error    string    `json:"error,omitempty"`
+
+	// Offsets from the start of the timeline, for Gantt rendering
+	StartOffsetMs float64 `json:"start_offset_ms"`
+	DurationMs    float64 `json:"duration_ms"`
+}
+
+// Timeline is a self-contained record of when each node of a run executed.
+type Timeline struct {
+	RunID   string         `json:"run_id"`
+	GraphID string         `json:"graph_id"`
+	Start   time.Time      `json:"start"`
+	End     time.Time      `json:"end"`
+	Spans   []TimelineSpan `json:"spans"` // Ordered by start time
+}
+
+// TimelineBuilder assembles run timelines from persisted node history.
+type TimelineBuilder struct {
+	store NodeHistoryStore
+}
+
+// NewTimelineBuilder creates a builder over store, which must keep node history.
+func NewTimelineBuilder(store storage.Storage) (*TimelineBuilder, error) {
+	history, ok := store.(NodeHistoryStore)
+	if !ok {
+		return nil, ErrTimelineUnsupported
+	}
+	return &TimelineBuilder{store: history}, nil
+}
+
+// Build reconstructs the timeline of a run. Nodes that never started get a
+// zero-length span at their last transition.
+func (b *TimelineBuilder) Build(runID string) (*Timeline, error) {
+	graphID, err := b.store.FindGraphByRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+	if graphID == "" {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+
+	nodes, err := b.store.LoadNodes(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	history, err := b.store.LoadNodeHistory(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load node history: %w", err)
+	}
+
+	spans := make(map[string]*TimelineSpan, len(nodes))
+	for _, node := range nodes {
+		spans[node.NodeID] = &TimelineSpan{
+			NodeID:   node.NodeID,
+			NodeType: node.Type,
+			Status:   node.Status,
+			Error:    node.LastError,
+		}
+	}
+
+	// A resumed graph reuses its node IDs; only the latest run counts, which
+	// starts at the node's last transition out of CREATED.
+	for _, tr := range history {
+		span, ok := spans[tr.NodeID]
+		if !ok {
+			continue
+		}
+		if tr.OldStatus == string(dag.StatusCreated) {
+			span.Start, span.End, span.Attempts = time.Time{}, time.Time{}, 0
+		}
+		if tr.NewStatus == string(dag.StatusRunning) {
+			span.Attempts++
+			if span.Start.IsZero() {
+				span.Start = tr.Timestamp
+			}
+		}
+		span.End = tr.Timestamp
+	}
+
+	timeline := &Timeline{RunID: runID, GraphID: graphID, Spans: make([]TimelineSpan, 0, len(spans))}
+	for _, span := range spans {
+		if span.Start.IsZero() {
+			span.Start = span.End
+		}
+		timeline.Spans = append(timeline.Spans, *span)
+	}
+
+	sort.Slice(timeline.Spans, func(i, j int) bool {
+		a, b := timeline.Spans[i], timeline.Spans[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.NodeID < b.NodeID
+	})
+
+	for _, span := range timeline.Spans {
+		if span.Start.IsZero() {
+			continue
+		}
+		if timeline.Start.IsZero() || span.Start.Before(timeline.Start) {
+			timeline.Start = span.Start
+		}
+		if span.End.After(timeline.End) {
+			timeline.End = span.End
+		}
+	}
+	for i := range timeline.Spans {
+		span := &timeline.Spans[i]
+		if span.Start.IsZero() {
+			continue
+		}
+		span.StartOffsetMs = float64(span.Start.Sub(timeline.Start)) / float64(time.Millisecond)
+		span.DurationMs = float64(span.End.Sub(span.Start)) / float64(time.Millisecond)
+	}
+
+	return timeline, nil
+}
+
+// chromeTraceEvent is a complete ("X") event in the Chrome trace event format.
+type chromeTraceEvent struct {
+	Name string            `json:"name"`
+	Cat  string            `json:"cat"`
+	Ph   string            `json:"ph"`
+	Ts   int64             `json:"ts"`  // Microseconds
+	Dur  int64             `json:"dur"` // Microseconds
+	Pid  int               `json:"pid"`
+	Tid  int               `json:"tid"`
+	Args map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the timeline in the Chrome trace event format,
+// loadable in chrome://tracing or Perfetto. Overlapping spans are placed on
+// separate lanes.
+func (t *Timeline) WriteChromeTrace(w io.Writer) error {
+	var laneEnds []time.Time
+	events := make([]chromeTraceEvent, 0, len(t.Spans))
+
+	for _, span := range t.Spans {
+		// Reuse the first lane that is free by the time this span starts
+		lane := -1
+		for i, end := range laneEnds {
+			if !end.After(span.Start) {
+				lane = i
+				break
+			}
+		}
+		if lane < 0 {
+			lane = len(laneEnds)
+			laneEnds = append(laneEnds, time.Time{})
+		}
+		laneEnds[lane] = span.End
+
+		args := map[string]string{
+			"status":   span.Status,
+			"attempts": fmt.Sprintf("%d", span.Attempts),
+		}
+		if span.Error != "" {
+			args["error"] = span.Error
+		}
+
+		events = append(events, chromeTraceEvent{
+			Name: span.NodeID,
+			Cat:  span.NodeType,
+			Ph:   "X",
+			Ts:   span.Start.UnixMicro(),
+			Dur:  span.End.Sub(span.Start).Microseconds(),
+			Pid:  1,
+			Tid:  lane + 1,
+			Args: args,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
+
+// Timeline builds the execution timeline of a run from persisted node history.
+func (e *DAGExecutor) Timeline(runID string) (*Timeline, error) {
+	builder, err := NewTimelineBuilder(e.storage)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Build(runID)
+}
@@ -0,0 +1,124 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"hdrp/internal/retry"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTimeline_CompletedRun(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("graph-timeline", true), "run-timeline")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	timeline, err := executor.Timeline("run-timeline")
+	if err != nil {
+		t.Fatalf("Timeline() error = %v", err)
+	}
+	if timeline.GraphID != "graph-timeline" {
+		t.Errorf("Expected graph-timeline, got %s", timeline.GraphID)
+	}
+
+	wantOrder := []string{"researcher1", "critic1", "synthesizer1"}
+	if len(timeline.Spans) != len(wantOrder) {
+		t.Fatalf("Expected %d spans, got %+v", len(wantOrder), timeline.Spans)
+	}
+	for i, span := range timeline.Spans {
+		if span.NodeID != wantOrder[i] {
+			t.Errorf("Span %d: expected %s, got %s", i, wantOrder[i], span.NodeID)
+		}
+		if span.Status != "SUCCEEDED" || span.Attempts != 1 {
+			t.Errorf("Span %s: expected one successful attempt, got status %s attempts %d", span.NodeID, span.Status, span.Attempts)
+		}
+		if span.Start.IsZero() || span.End.Before(span.Start) {
+			t.Errorf("Span %s has invalid window %v - %v", span.NodeID, span.Start, span.End)
+		}
+		if i > 0 && span.Start.Before(timeline.Spans[i-1].End) {
+			t.Errorf("Span %s starts before its parent %s finished", span.NodeID, timeline.Spans[i-1].NodeID)
+		}
+		if span.Start.Before(timeline.Start) || span.End.After(timeline.End) {
+			t.Errorf("Span %s falls outside the timeline", span.NodeID)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := timeline.WriteChromeTrace(&buf); err != nil {
+		t.Fatalf("WriteChromeTrace() error = %v", err)
+	}
+	var trace struct {
+		TraceEvents []chromeTraceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("Invalid Chrome trace: %v", err)
+	}
+	if len(trace.TraceEvents) != len(wantOrder) {
+		t.Fatalf("Expected %d trace events, got %d", len(wantOrder), len(trace.TraceEvents))
+	}
+	for i, event := range trace.TraceEvents {
+		if event.Name != wantOrder[i] || event.Ph != "X" {
+			t.Errorf("Unexpected trace event %d: %+v", i, event)
+		}
+		// Sequential spans share a lane
+		if event.Tid != 1 {
+			t.Errorf("Expected %s on lane 1, got %d", event.Name, event.Tid)
+		}
+	}
+
+	if _, err := executor.Timeline("run-missing"); !errors.Is(err, ErrRunNotFound) {
+		t.Errorf("Expected ErrRunNotFound, got %v", err)
+	}
+}
+
+func TestNodeHistory_AttemptNumbers(t *testing.T) {
+	executor := newTestExecutor(t, mockServiceClients())
+	executor.clients.Researcher = &mockResearcherClient{
+		maxFailures: 2,
+		failureType: status.Error(codes.Unavailable, "backend outage"),
+	}
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffMultiplier: 1, MaxDelay: time.Millisecond})
+
+	graph := researchCriticGraph("graph-attempts", false)
+	result, err := executor.Execute(context.Background(), graph, "run-attempts")
+	if err != nil || !result.Success {
+		t.Fatalf("Execution failed: %v %+v", err, result)
+	}
+
+	history, err := executor.storage.(NodeHistoryStore).LoadNodeHistory(graph.ID)
+	if err != nil {
+		t.Fatalf("LoadNodeHistory() error = %v", err)
+	}
+
+	var running []int
+	last := 0
+	for _, tr := range history {
+		if tr.NodeID != "researcher1" {
+			continue
+		}
+		if tr.Attempt < last {
+			t.Errorf("Attempt went backwards: %s -> %s logged as attempt %d after %d", tr.OldStatus, tr.NewStatus, tr.Attempt, last)
+		}
+		last = tr.Attempt
+		if tr.NewStatus == "RUNNING" {
+			running = append(running, tr.Attempt)
+		}
+	}
+
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(running, want) {
+		t.Errorf("RUNNING entries logged attempts %v, want %v", running, want)
+	}
+	if last != 3 {
+		t.Errorf("Final transition logged attempt %d, want 3", last)
+	}
+}
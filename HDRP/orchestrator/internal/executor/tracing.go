@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"errors"
+
+	"hdrp/internal/metrics"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Outcomes recorded on run and node spans.
+const (
+	outcomeSucceeded = "succeeded"
+	outcomeFailed    = "failed"
+	outcomeRequeued  = "requeued"
+	outcomeCancelled = "cancelled"
+)
+
+// attemptKey carries the attempt number of a node execution, so its span
+// can tell retries apart.
+type attemptKey struct{}
+
+// withAttempt returns ctx labelled with a node's attempt number, counting
+// from 1.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// attemptFromContext returns the attempt number set by withAttempt, or 0 for
+// executions outside the retry loop such as upstream re-runs.
+func attemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// nodeOutcome classifies a node's final result for its span. ctx is the
+// run's context, whose cancellation stopped the node rather than failed it.
+func nodeOutcome(ctx context.Context, result *NodeResult) string {
+	switch {
+	case result.Success:
+		return outcomeSucceeded
+	case result.RequeueAfter > 0:
+		return outcomeRequeued
+	case ctx.Err() != nil:
+		return outcomeCancelled
+	default:
+		return outcomeFailed
+	}
+}
+
+// recordRunSpan records the outcome of a run on the span in ctx, marking the
+// span failed if the run was.
+func recordRunSpan(ctx context.Context, result *ExecutionResult, err error) {
+	if err != nil {
+		outcome := outcomeFailed
+		if errors.Is(err, ErrExecutionCancelled) {
+			outcome = outcomeCancelled
+		}
+		metrics.AddSpanAttributes(ctx, attribute.String("run.outcome", outcome))
+		metrics.RecordSpanError(ctx, err)
+		return
+	}
+	if result == nil {
+		return
+	}
+
+	outcome := outcomeSucceeded
+	if !result.Success {
+		outcome = outcomeFailed
+	}
+	metrics.AddSpanAttributes(ctx,
+		attribute.String("run.outcome", outcome),
+		attribute.Int("nodes.succeeded", len(result.SucceededNodes)),
+		attribute.Int("nodes.failed", len(result.FailedNodes)),
+	)
+	if !result.Success && result.ErrorMessage != "" {
+		metrics.RecordSpanError(ctx, errors.New(result.ErrorMessage))
+	}
+}
+
+// recordNodeSpan records how a node finished, and after how many attempts,
+// on the span in ctx. Only failures mark the span failed; a cancelled or
+// requeued node didn't fail.
+func recordNodeSpan(ctx context.Context, result *NodeResult, attempts int) {
+	outcome := nodeOutcome(ctx, result)
+	metrics.AddSpanAttributes(ctx,
+		attribute.String("node.outcome", outcome),
+		attribute.Int("node.attempts", attempts),
+	)
+	if outcome == outcomeFailed {
+		metrics.RecordSpanError(ctx, result.Error)
+	}
+}
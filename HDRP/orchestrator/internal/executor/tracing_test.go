@@ -0,0 +1,175 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/metrics"
+	"hdrp/internal/retry"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// spanCapturingResearcher records the span each call was made under before
+// delegating to a mockResearcherClient.
+type spanCapturingResearcher struct {
+	*mockResearcherClient
+	mu    sync.Mutex
+	spans []trace.SpanID
+}
+
+func (r *spanCapturingResearcher) Research(ctx context.Context, req *pb.ResearchRequest, opts ...grpc.CallOption) (*pb.ResearchResponse, error) {
+	r.mu.Lock()
+	r.spans = append(r.spans, trace.SpanContextFromContext(ctx).SpanID())
+	r.mu.Unlock()
+	return r.mockResearcherClient.Research(ctx, req, opts...)
+}
+
+func newTracingTestExecutor(t *testing.T, researcher clients.ResearcherClient) (*DAGExecutor, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	metrics.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { metrics.SetTracerProvider(nil) })
+
+	executor := newTestExecutor(t, &clients.ServiceClients{Researcher: researcher, Critic: &mockCriticClient{}})
+	executor.SetRetryPolicy(&retry.RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond, BackoffMultiplier: 2, MaxDelay: 10 * time.Millisecond})
+	return executor, recorder
+}
+
+// spansNamed returns the ended spans called name, filtered to nodeID unless
+// it is empty.
+func spansNamed(recorder *tracetest.SpanRecorder, name, nodeID string) []sdktrace.ReadOnlySpan {
+	var spans []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.Name() != name {
+			continue
+		}
+		if nodeID != "" && spanAttr(s, "node.id").AsString() != nodeID {
+			continue
+		}
+		spans = append(spans, s)
+	}
+	return spans
+}
+
+func spanAttr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// TestExecute_Spans verifies a run is traced as a dag.execute span, with a
+// node.run span per node and a node.execute span per attempt beneath it,
+// and that the researcher is called under its attempt's span.
+func TestExecute_Spans(t *testing.T) {
+	researcher := &spanCapturingResearcher{mockResearcherClient: &mockResearcherClient{
+		maxFailures: 1,
+		failureType: status.Error(codes.Unavailable, "down"),
+	}}
+	executor, recorder := newTracingTestExecutor(t, researcher)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-spans", false), "run-spans")
+	if err != nil || !result.Success {
+		t.Fatalf("Expected the run to succeed, got %+v, %v", result, err)
+	}
+
+	roots := spansNamed(recorder, "dag.execute", "")
+	if len(roots) != 1 {
+		t.Fatalf("Got %d dag.execute spans, want 1", len(roots))
+	}
+	root := roots[0]
+	if got := spanAttr(root, "run.outcome").AsString(); got != outcomeSucceeded {
+		t.Errorf("run.outcome = %q, want %q", got, outcomeSucceeded)
+	}
+
+	runs := spansNamed(recorder, "node.run", "researcher1")
+	if len(runs) != 1 {
+		t.Fatalf("Got %d node.run spans for researcher1, want 1", len(runs))
+	}
+	run := runs[0]
+	if run.Parent().SpanID() != root.SpanContext().SpanID() {
+		t.Error("node.run span is not a child of dag.execute")
+	}
+	if got := spanAttr(run, "node.attempts").AsInt64(); got != 2 {
+		t.Errorf("node.attempts = %d, want 2", got)
+	}
+	if got := spanAttr(run, "node.outcome").AsString(); got != outcomeSucceeded {
+		t.Errorf("node.outcome = %q, want %q", got, outcomeSucceeded)
+	}
+
+	attempts := spansNamed(recorder, "node.execute", "researcher1")
+	if len(attempts) != 2 {
+		t.Fatalf("Got %d node.execute spans for researcher1, want 2", len(attempts))
+	}
+	for i, s := range attempts {
+		if s.Parent().SpanID() != run.SpanContext().SpanID() {
+			t.Errorf("Attempt %d span is not a child of node.run", i+1)
+		}
+		if got := spanAttr(s, "node.attempt").AsInt64(); got != int64(i+1) {
+			t.Errorf("Attempt %d span has node.attempt %d", i+1, got)
+		}
+		if researcher.spans[i] != s.SpanContext().SpanID() {
+			t.Errorf("Researcher call %d wasn't made under its attempt's span", i+1)
+		}
+	}
+	if attempts[0].Status().Code != otelcodes.Error || len(attempts[0].Events()) == 0 {
+		t.Errorf("Failed attempt span should record its error, got status %v", attempts[0].Status())
+	}
+	if attempts[1].Status().Code == otelcodes.Error {
+		t.Error("Successful attempt span marked failed")
+	}
+}
+
+// TestExecute_SpansFailedRun verifies a node that gives up and the run it
+// fails record their errors.
+func TestExecute_SpansFailedRun(t *testing.T) {
+	researcher := &mockResearcherClient{maxFailures: 10, failureType: status.Error(codes.Unavailable, "down")}
+	executor, recorder := newTracingTestExecutor(t, researcher)
+
+	result, err := executor.Execute(context.Background(), researchCriticGraph("test-spans-failed", false), "run-spans-failed")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected the run to fail")
+	}
+
+	run := spansNamed(recorder, "node.run", "researcher1")
+	if len(run) != 1 {
+		t.Fatalf("Got %d node.run spans for researcher1, want 1", len(run))
+	}
+	if got := spanAttr(run[0], "node.outcome").AsString(); got != outcomeFailed {
+		t.Errorf("node.outcome = %q, want %q", got, outcomeFailed)
+	}
+	if got := spanAttr(run[0], "node.attempts").AsInt64(); got != 3 {
+		t.Errorf("node.attempts = %d, want 3", got)
+	}
+	if run[0].Status().Code != otelcodes.Error {
+		t.Errorf("Failed node span status = %v, want Error", run[0].Status())
+	}
+
+	root := spansNamed(recorder, "dag.execute", "")
+	if len(root) != 1 {
+		t.Fatalf("Got %d dag.execute spans, want 1", len(root))
+	}
+	if got := spanAttr(root[0], "run.outcome").AsString(); got != outcomeFailed {
+		t.Errorf("run.outcome = %q, want %q", got, outcomeFailed)
+	}
+	if root[0].Status().Code != otelcodes.Error {
+		t.Errorf("Failed run span status = %v, want Error", root[0].Status())
+	}
+}
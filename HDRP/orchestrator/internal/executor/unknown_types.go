@@ -0,0 +1,105 @@
+package executor
+
+import (
+	"fmt"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/metrics"
+
+	pb "github.com/deepdag/hdrp/api/gen/services"
+)
+
+// UnknownTypePolicy controls how nodes with no registered handler are executed.
+type UnknownTypePolicy string
+
+const (
+	// UnknownTypeStrict fails unknown nodes with a permanent error.
+	UnknownTypeStrict UnknownTypePolicy = "strict"
+	// UnknownTypeLenient treats unknown nodes as no-op pass-throughs that
+	// forward their parents' output, e.g. for placeholder or manual steps.
+	UnknownTypeLenient UnknownTypePolicy = "lenient"
+)
+
+// ParseUnknownTypePolicy converts a config value to a policy. Empty selects strict.
+func ParseUnknownTypePolicy(s string) (UnknownTypePolicy, error) {
+	switch UnknownTypePolicy(s) {
+	case "", UnknownTypeStrict:
+		return UnknownTypeStrict, nil
+	case UnknownTypeLenient:
+		return UnknownTypeLenient, nil
+	default:
+		return "", fmt.Errorf("unsupported unknown node type policy: %s", s)
+	}
+}
+
+// isKnownNodeType reports whether t is one of the service types, whose calls
+// are rate limited per service.
+func isKnownNodeType(t string) bool {
+	switch serviceType(t) {
+	case "researcher", "critic", "synthesizer":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetUnknownTypePolicy sets how nodes of unrecognized types are handled.
+func (e *DAGExecutor) SetUnknownTypePolicy(policy UnknownTypePolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.unknownTypePolicy = policy
+}
+
+// executeUnknownNode applies the unknown type policy to a node.
+func (e *DAGExecutor) executeUnknownNode(node *dag.Node, graph *dag.Graph, nodeResults map[string]*NodeResult) *NodeResult {
+	e.mu.RLock()
+	policy := e.unknownTypePolicy
+	e.mu.RUnlock()
+
+	if policy != UnknownTypeLenient {
+		metrics.RecordError("executor", "unknown_node_type")
+		return &NodeResult{
+			NodeID:  node.ID,
+			Success: false,
+			Error:   fmt.Errorf("unknown node type: %s", node.Type),
+		}
+	}
+
+	execLog.Infof("Passing through node %s of unknown type %s", node.ID, node.Type)
+	return &NodeResult{
+		NodeID:  node.ID,
+		Success: true,
+		Data:    passThroughData(node.ID, graph, nodeResults),
+	}
+}
+
+// passThroughData merges the claims or critiques of a node's parents so
+// downstream handlers see the same input they would without the node.
+func passThroughData(nodeID string, graph *dag.Graph, nodeResults map[string]*NodeResult) interface{} {
+	var claims []*pb.AtomicClaim
+	var critiques []*pb.CritiqueResult
+	for _, edge := range graph.Edges {
+		if edge.To != nodeID {
+			continue
+		}
+		parent, ok := nodeResults[edge.From]
+		if !ok {
+			continue
+		}
+		switch data := parent.Data.(type) {
+		case []*pb.AtomicClaim:
+			claims = append(claims, data...)
+		case []*pb.CritiqueResult:
+			critiques = append(critiques, data...)
+		}
+	}
+
+	switch {
+	case critiques != nil:
+		return critiques
+	case claims != nil:
+		return claims
+	default:
+		return nil
+	}
+}
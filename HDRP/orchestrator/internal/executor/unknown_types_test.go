@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/retry"
+)
+
+// placeholderGraph routes researcher output through a node of an unknown type into a critic.
+func placeholderGraph(id string) *dag.Graph {
+	return &dag.Graph{
+		ID:     id,
+		Status: dag.StatusCreated,
+		Nodes: []dag.Node{
+			{ID: "researcher1", Type: "researcher", Config: map[string]string{"query": "q"}, Status: dag.StatusCreated},
+			{ID: "review1", Type: "manual_review", Status: dag.StatusCreated},
+			{ID: "critic1", Type: "critic", Config: map[string]string{"task": "verify"}, Status: dag.StatusCreated},
+		},
+		Edges: []dag.Edge{
+			{From: "researcher1", To: "review1"},
+			{From: "review1", To: "critic1"},
+		},
+	}
+}
+
+func newUnknownTypeTestExecutor() *DAGExecutor {
+	executor := NewDAGExecutor(&clients.ServiceClients{
+		Researcher:  &mockResearcherClient{},
+		Critic:      &echoCriticClient{},
+		Synthesizer: &mockSynthesizerClient{},
+	}, 2)
+	executor.SetRetryPolicy(retry.DefaultPolicy())
+	return executor
+}
+
+func TestParseUnknownTypePolicy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    UnknownTypePolicy
+		wantErr bool
+	}{
+		{in: "", want: UnknownTypeStrict},
+		{in: "strict", want: UnknownTypeStrict},
+		{in: "lenient", want: UnknownTypeLenient},
+		{in: "ignore", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseUnknownTypePolicy(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseUnknownTypePolicy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseUnknownTypePolicy(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestUnknownTypeStrict verifies unknown nodes fail once with a permanent error
+func TestUnknownTypeStrict(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+
+	graph := placeholderGraph("test-unknown-strict")
+	result, err := executor.Execute(context.Background(), graph, "test-run-unknown-strict")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if result.Success {
+		t.Fatal("Expected failure for unknown node type in strict mode")
+	}
+
+	review := graph.Nodes[1]
+	if review.Status != dag.StatusFailed {
+		t.Errorf("Expected review1 to be FAILED, got %s", review.Status)
+	}
+	if !strings.Contains(review.LastError, "unknown node type: manual_review") {
+		t.Errorf("Unexpected error: %q", review.LastError)
+	}
+
+	// Permanent error: no retries despite the default retry policy
+	if m := result.RetryMetrics.GetNodeMetrics("review1"); m == nil || m.TotalAttempts != 1 {
+		t.Errorf("Expected exactly one attempt for review1, got %+v", m)
+	}
+}
+
+// TestUnknownTypeLenient verifies unknown nodes pass their parents' output through
+func TestUnknownTypeLenient(t *testing.T) {
+	executor := newUnknownTypeTestExecutor()
+	executor.SetUnknownTypePolicy(UnknownTypeLenient)
+
+	result, err := executor.Execute(context.Background(), placeholderGraph("test-unknown-lenient"), "test-run-unknown-lenient")
+	if err != nil {
+		t.Fatalf("Execution error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Expected success in lenient mode, got: %s", result.ErrorMessage)
+	}
+
+	// The critic verified the researcher's claim forwarded by the placeholder
+	if len(result.VerificationResults) != 1 {
+		t.Fatalf("Expected 1 verification result, got %d", len(result.VerificationResults))
+	}
+	if got := result.VerificationResults[0].Claim.GetStatement(); got != "Test claim" {
+		t.Errorf("Unexpected verified claim: %q", got)
+	}
+}
@@ -0,0 +1,155 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/intent"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// blueprintFile is the YAML form of a blueprint, one file per intent type:
+//
+//	intent: RESEARCH          # optional, defaults to the upper-cased file name
+//	nodes:
+//	  - id: researcher
+//	    type: researcher_agent
+//	    config: {max_results: "5"}
+//	edges:
+//	  - {from: researcher, to: critic}
+//	defaults:                 # per node type, may use ${goal}, ${intent}, metadata
+//	  researcher_agent: {query: "${goal}"}
+//	required: [goal]
+type blueprintFile struct {
+	Intent   string                       `yaml:"intent"`
+	Nodes    []blueprintNode              `yaml:"nodes"`
+	Edges    []blueprintEdge              `yaml:"edges"`
+	Defaults map[string]map[string]string `yaml:"defaults"`
+	Required []string                     `yaml:"required"`
+}
+
+type blueprintNode struct {
+	ID     string            `yaml:"id"`
+	Type   string            `yaml:"type"`
+	Config map[string]string `yaml:"config"`
+}
+
+type blueprintEdge struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// NewTemplateGeneratorFromDir initializes the generator with the standard
+// blueprints, replaced or extended by one YAML blueprint per .yaml/.yml file
+// in dir. Every file is validated on load, so a broken pipeline fails at
+// startup rather than at generation. An empty dir uses only the standard
+// blueprints, as NewTemplateGenerator does.
+func NewTemplateGeneratorFromDir(dir string) (*TemplateGenerator, error) {
+	blueprints := loadStandardBlueprints()
+	if dir == "" {
+		return &TemplateGenerator{blueprints: blueprints}, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blueprint directory: %w", err)
+	}
+	// ReadDir returns entries sorted by name, so duplicates are reported
+	// against the same file every time
+	loadedFrom := make(map[intent.IntentType]string)
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		intentType, bp, err := loadBlueprintFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := loadedFrom[intentType]; ok {
+			return nil, fmt.Errorf("blueprint %s: intent %s is already defined by %s", path, intentType, prev)
+		}
+		loadedFrom[intentType] = path
+		blueprints[intentType] = bp
+	}
+	return &TemplateGenerator{blueprints: blueprints}, nil
+}
+
+// loadBlueprintFile parses and validates one blueprint file.
+func loadBlueprintFile(path string) (intent.IntentType, blueprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", blueprint{}, fmt.Errorf("failed to read blueprint: %w", err)
+	}
+	var file blueprintFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return "", blueprint{}, fmt.Errorf("blueprint %s: invalid YAML: %w", path, err)
+	}
+
+	intentType := intent.IntentType(strings.ToUpper(strings.TrimSpace(file.Intent)))
+	if intentType == "" {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		intentType = intent.IntentType(strings.ToUpper(name))
+	}
+
+	bp := blueprint{
+		nodes:    make([]dag.Node, len(file.Nodes)),
+		edges:    make([]dag.Edge, len(file.Edges)),
+		defaults: file.Defaults,
+		required: file.Required,
+	}
+	for i, n := range file.Nodes {
+		bp.nodes[i] = dag.Node{ID: n.ID, Type: n.Type, Config: n.Config}
+	}
+	for i, e := range file.Edges {
+		bp.edges[i] = dag.Edge{From: e.From, To: e.To}
+	}
+	if err := bp.validate(); err != nil {
+		return "", blueprint{}, fmt.Errorf("blueprint %s: %w", path, err)
+	}
+	return intentType, bp, nil
+}
+
+// validate checks that the blueprint hydrates into a valid DAG. Defaults are
+// applied with every variable empty, as only their keys matter to Validate.
+func (bp blueprint) validate() error {
+	graph := &dag.Graph{ID: "blueprint", Status: dag.StatusCreated, Edges: bp.edges}
+	for _, tmpl := range bp.nodes {
+		n := tmpl
+		n.Status = dag.StatusCreated
+		n.Config = make(map[string]string)
+		for k, v := range bp.defaults[tmpl.Type] {
+			n.Config[k] = expandVars(v, nil)
+		}
+		for k, v := range tmpl.Config {
+			n.Config[k] = v
+		}
+		graph.Nodes = append(graph.Nodes, n)
+	}
+	if err := graph.Validate(); err != nil {
+		return err
+	}
+
+	// Defaults for a type no node has are almost certainly a typo
+	types := make(map[string]bool, len(bp.nodes))
+	for _, n := range bp.nodes {
+		types[n.Type] = true
+	}
+	var unused []string
+	for t := range bp.defaults {
+		if !types[t] {
+			unused = append(unused, t)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return fmt.Errorf("defaults given for node types with no nodes: %s", strings.Join(unused, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hdrp/internal/intent"
+)
+
+func writeBlueprint(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write blueprint: %v", err)
+	}
+}
+
+func TestNewTemplateGeneratorFromDir(t *testing.T) {
+	dir := t.TempDir()
+	writeBlueprint(t, dir, "research.yaml", `
+nodes:
+  - id: search
+    type: researcher_agent
+    config: {max_results: "5"}
+  - id: verify
+    type: critic_agent
+edges:
+  - {from: search, to: verify}
+defaults:
+  researcher_agent: {query: "${goal}"}
+  critic_agent: {task: verify}
+required: [goal]
+`)
+	writeBlueprint(t, dir, "triage.yml", `
+intent: TRIAGE
+nodes:
+  - {id: sort, type: triage_agent}
+`)
+	writeBlueprint(t, dir, "README.md", "not a blueprint")
+
+	gen, err := NewTemplateGeneratorFromDir(dir)
+	if err != nil {
+		t.Fatalf("NewTemplateGeneratorFromDir() error = %v", err)
+	}
+
+	g, err := gen.Generate(&intent.Objective{ID: "obj-1", Type: intent.IntentResearch, Description: "Research YAML"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(g.Nodes) != 2 || len(g.Edges) != 1 {
+		t.Fatalf("Expected the file's 2 nodes and 1 edge, got %d and %d", len(g.Nodes), len(g.Edges))
+	}
+	search := g.Nodes[0]
+	if search.ID != "graph-obj-1-search" || search.Config["query"] != "Research YAML" || search.Config["max_results"] != "5" {
+		t.Errorf("Unexpected researcher node: %+v", search)
+	}
+	if g.Edges[0].From != "graph-obj-1-search" || g.Edges[0].To != "graph-obj-1-verify" {
+		t.Errorf("Unexpected edge: %+v", g.Edges[0])
+	}
+
+	g, err = gen.Generate(&intent.Objective{ID: "obj-2", Type: "TRIAGE", Description: "d"})
+	if err != nil || len(g.Nodes) != 1 || g.Nodes[0].Type != "triage_agent" {
+		t.Errorf("Expected the TRIAGE blueprint, got %+v (err %v)", g, err)
+	}
+
+	// Intents without a file keep the built-in blueprint
+	g, err = gen.Generate(&intent.Objective{ID: "obj-3", Type: intent.IntentCodeGen, Description: "d"})
+	if err != nil || len(g.Nodes) != 3 || g.Nodes[0].Type != "architect_agent" {
+		t.Errorf("Expected the built-in CODE_GEN blueprint, got %+v (err %v)", g, err)
+	}
+}
+
+func TestNewTemplateGeneratorFromDir_EmptyDirUsesBuiltIns(t *testing.T) {
+	gen, err := NewTemplateGeneratorFromDir("")
+	if err != nil {
+		t.Fatalf("NewTemplateGeneratorFromDir() error = %v", err)
+	}
+	if len(gen.blueprints) != len(NewTemplateGenerator().blueprints) {
+		t.Errorf("Expected the built-in blueprints, got %d", len(gen.blueprints))
+	}
+}
+
+func TestNewTemplateGeneratorFromDir_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "cycle",
+			files:   map[string]string{"general.yaml": "nodes: [{id: a, type: t}, {id: b, type: t}]\nedges: [{from: a, to: b}, {from: b, to: a}]\n"},
+			wantErr: "cycle",
+		},
+		{
+			name:    "unknown edge target",
+			files:   map[string]string{"general.yaml": "nodes: [{id: a, type: t}]\nedges: [{from: a, to: missing}]\n"},
+			wantErr: "'missing' does not exist",
+		},
+		{
+			name:    "no nodes",
+			files:   map[string]string{"general.yaml": "required: [goal]\n"},
+			wantErr: "graph is empty",
+		},
+		{
+			name:    "composite node",
+			files:   map[string]string{"general.yaml": "nodes: [{id: a, type: t, config: {steps: \"3\"}}]\n"},
+			wantErr: "atomicity",
+		},
+		{
+			name:    "unused defaults",
+			files:   map[string]string{"general.yaml": "nodes: [{id: a, type: t}]\ndefaults: {other: {k: v}}\n"},
+			wantErr: "no nodes: other",
+		},
+		{
+			name: "duplicate intent",
+			files: map[string]string{
+				"a.yaml": "intent: GENERAL\nnodes: [{id: a, type: t}]\n",
+				"b.yaml": "intent: general\nnodes: [{id: a, type: t}]\n",
+			},
+			wantErr: "already defined",
+		},
+		{
+			name:    "bad yaml",
+			files:   map[string]string{"general.yaml": "nodes: [\n"},
+			wantErr: "invalid YAML",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				writeBlueprint(t, dir, name, content)
+			}
+			_, err := NewTemplateGeneratorFromDir(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if _, err := NewTemplateGeneratorFromDir(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
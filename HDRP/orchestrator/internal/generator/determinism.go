@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"hdrp/internal/intent"
+)
+
+// DefaultDeterminismRuns is how many times VerifyDeterminism regenerates a
+// graph when no count is given. Map-order bugs show up with high probability
+// well within this many runs.
+const DefaultDeterminismRuns = 5
+
+// NondeterminismError reports a generator that produced a different graph
+// for the same objective.
+type NondeterminismError struct {
+	ObjectiveID string
+	Run         int    // 1-based regeneration that diverged from the first graph
+	Line        int    // First differing line of the serialized graphs
+	Want, Got   string // That line in the first graph and in the diverging one
+}
+
+func (e *NondeterminismError) Error() string {
+	return fmt.Sprintf("generation for objective %s is nondeterministic: run %d differs at line %d: want %q, got %q",
+		e.ObjectiveID, e.Run, e.Line, e.Want, e.Got)
+}
+
+// VerifyDeterminism generates a graph for obj runs+1 times and checks that
+// every serialized graph is byte-identical to the first. Graph and node IDs
+// derive from the objective ID, so any difference points at generation
+// depending on something else, such as map iteration order.
+func VerifyDeterminism(gen Generator, obj *intent.Objective, runs int) error {
+	if runs <= 0 {
+		runs = DefaultDeterminismRuns
+	}
+
+	first, err := generateSerialized(gen, obj)
+	if err != nil {
+		return err
+	}
+	for run := 1; run <= runs; run++ {
+		got, err := generateSerialized(gen, obj)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(first, got) {
+			continue
+		}
+		line, want, have := firstDifference(first, got)
+		return &NondeterminismError{ObjectiveID: obj.ID, Run: run, Line: line, Want: want, Got: have}
+	}
+	return nil
+}
+
+// generateSerialized generates a graph and encodes it as indented JSON, one
+// field per line so differences can be reported by line.
+func generateSerialized(gen Generator, obj *intent.Objective) ([]byte, error) {
+	graph, err := gen.Generate(obj)
+	if err != nil {
+		return nil, fmt.Errorf("generation failed: %w", err)
+	}
+	data, err := json.MarshalIndent(graph, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize graph %s: %w", graph.ID, err)
+	}
+	return data, nil
+}
+
+// firstDifference returns the 1-based number and contents of the first line
+// where a and b differ.
+func firstDifference(a, b []byte) (int, string, string) {
+	linesA := bytes.Split(a, []byte("\n"))
+	linesB := bytes.Split(b, []byte("\n"))
+	for i := 0; i < len(linesA) || i < len(linesB); i++ {
+		var lineA, lineB []byte
+		if i < len(linesA) {
+			lineA = linesA[i]
+		}
+		if i < len(linesB) {
+			lineB = linesB[i]
+		}
+		if !bytes.Equal(lineA, lineB) {
+			return i + 1, string(bytes.TrimSpace(lineA)), string(bytes.TrimSpace(lineB))
+		}
+	}
+	return 0, "", ""
+}
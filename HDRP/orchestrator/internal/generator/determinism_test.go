@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"hdrp/internal/dag"
+	"hdrp/internal/intent"
+)
+
+// mapOrderGenerator emits nodes in map iteration order, the kind of bug
+// VerifyDeterminism is meant to catch.
+type mapOrderGenerator struct{}
+
+func (mapOrderGenerator) Generate(obj *intent.Objective) (*dag.Graph, error) {
+	steps := make(map[string]string)
+	for i := 0; i < 16; i++ {
+		steps[fmt.Sprintf("step%d", i)] = "researcher"
+	}
+
+	graph := &dag.Graph{ID: "graph-" + obj.ID, Status: dag.StatusCreated}
+	for id, nodeType := range steps {
+		graph.Nodes = append(graph.Nodes, dag.Node{ID: graph.ID + "-" + id, Type: nodeType, Status: dag.StatusCreated})
+	}
+	return graph, nil
+}
+
+func TestVerifyDeterminism_Blueprints(t *testing.T) {
+	gen := NewTemplateGenerator()
+	for _, intentType := range []intent.IntentType{intent.IntentResearch, intent.IntentGeneral} {
+		obj := &intent.Objective{
+			ID:          "fixed-obj-id",
+			Type:        intentType,
+			Description: "Repeatable test",
+			Metadata:    map[string]string{"region": "eu", "audience": "analysts", "format": "brief"},
+		}
+		if err := VerifyDeterminism(gen, obj, 10); err != nil {
+			t.Errorf("Blueprint %s: %v", intentType, err)
+		}
+	}
+}
+
+func TestVerifyDeterminism_CatchesMapOrder(t *testing.T) {
+	obj := &intent.Objective{ID: "fixed-obj-id", Type: intent.IntentGeneral}
+
+	err := VerifyDeterminism(mapOrderGenerator{}, obj, 10)
+	var nondeterminism *NondeterminismError
+	if !errors.As(err, &nondeterminism) {
+		t.Fatalf("Expected a NondeterminismError, got %v", err)
+	}
+	if nondeterminism.Line == 0 || nondeterminism.Want == nondeterminism.Got {
+		t.Errorf("Expected the first differing line to be reported, got %+v", nondeterminism)
+	}
+}
+
+func TestVerifyDeterminism_GenerationError(t *testing.T) {
+	if err := VerifyDeterminism(NewTemplateGenerator(), nil, 1); err == nil {
+		t.Fatal("Expected generation errors to be returned")
+	}
+}
@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -138,3 +139,101 @@ func TestTemplateGenerator_NilObjective(t *testing.T) {
 		t.Error("Expected error when generating from nil objective")
 	}
 }
+
+func TestTemplateGenerator_TypeDefaults(t *testing.T) {
+	gen := NewTemplateGenerator()
+
+	obj := &intent.Objective{
+		ID:          "obj-defaults",
+		Type:        intent.IntentResearch,
+		Description: "Research solid-state batteries",
+	}
+	g, err := gen.Generate(obj)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	researchers := 0
+	for _, n := range g.Nodes {
+		switch n.Type {
+		case "researcher_agent":
+			researchers++
+			if n.Config["query"] == "" {
+				t.Errorf("Researcher node %s has empty query", n.ID)
+			}
+			if n.Config["query"] != obj.Description {
+				t.Errorf("Researcher query = %q, want %q", n.Config["query"], obj.Description)
+			}
+		case "critic_agent":
+			if n.Config["task"] == "" {
+				t.Errorf("Critic node %s has empty task", n.ID)
+			}
+		}
+	}
+	if researchers == 0 {
+		t.Fatal("Expected at least one researcher node")
+	}
+
+	// Per-instance configs must not leak back into the blueprint
+	g.Nodes[0].Config["query"] = "mutated"
+	other, err := gen.Generate(&intent.Objective{ID: "obj-other", Type: intent.IntentResearch, Description: "Other"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := other.Nodes[0].Config["query"]; got != "Other" {
+		t.Errorf("Second graph query = %q, want %q", got, "Other")
+	}
+}
+
+func TestTemplateGenerator_RequiredVariables(t *testing.T) {
+	gen := &TemplateGenerator{blueprints: map[intent.IntentType]blueprint{
+		intent.IntentResearch: {
+			nodes:    []dag.Node{{ID: "researcher", Type: "researcher_agent"}},
+			defaults: map[string]map[string]string{"researcher_agent": {"query": "${goal} for ${audience}"}},
+			required: []string{"goal", "audience"},
+		},
+	}}
+
+	_, err := gen.Generate(&intent.Objective{ID: "obj-missing", Type: intent.IntentResearch, Description: "Explain batteries"})
+	var missing *MissingVariablesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Generate() error = %v, want MissingVariablesError", err)
+	}
+	if len(missing.Missing) != 1 || missing.Missing[0] != "audience" {
+		t.Errorf("Missing = %v, want [audience]", missing.Missing)
+	}
+	if !strings.Contains(err.Error(), "audience") {
+		t.Errorf("Error %q does not name the missing variable", err)
+	}
+
+	g, err := gen.Generate(&intent.Objective{
+		ID:          "obj-provided",
+		Type:        intent.IntentResearch,
+		Description: "Explain batteries",
+		Metadata:    map[string]string{"audience": "students"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := g.Nodes[0].Config["query"]; got != "Explain batteries for students" {
+		t.Errorf("query = %q", got)
+	}
+}
+
+func TestExpandVars(t *testing.T) {
+	vars := map[string]string{"goal": "batteries", "locale": "en"}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"${goal}", "batteries"},
+		{"Research ${goal} in ${locale}", "Research batteries in en"},
+		{"${missing}", ""},
+		{"no placeholders", "no placeholders"},
+	}
+	for _, tt := range tests {
+		if got := expandVars(tt.in, vars); got != tt.want {
+			t.Errorf("expandVars(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package generator
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"hdrp/internal/dag"
 	"hdrp/internal/intent"
@@ -14,16 +16,46 @@ type Generator interface {
 
 // TemplateGenerator creates graphs based on predefined blueprints for each intent type.
 type TemplateGenerator struct {
-	// In a real system, these might be loaded from YAML/JSON files
+	// Built-in blueprints, optionally overridden from YAML files
+	// (see NewTemplateGeneratorFromDir)
 	blueprints map[intent.IntentType]blueprint
 }
 
 type blueprint struct {
 	nodes []dag.Node
 	edges []dag.Edge
+	// defaults maps a node type to the config every node of that type starts
+	// with. Values may reference ${goal}, ${intent}, or objective metadata keys.
+	defaults map[string]map[string]string
+	// required lists variables that must have a non-empty value for the
+	// blueprint to generate, so critical prompts never expand to "".
+	required []string
 }
 
-// NewTemplateGenerator initializes the generator with standard intent blueprints.
+// MissingVariablesError reports required blueprint variables that had no
+// value when a graph was generated.
+type MissingVariablesError struct {
+	Intent  intent.IntentType
+	Missing []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("blueprint %s is missing required variables: %s", e.Intent, strings.Join(e.Missing, ", "))
+}
+
+// templateVar matches ${name} placeholders in default config values.
+var templateVar = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// expandVars substitutes ${name} placeholders from vars. Unknown names expand
+// to the empty string.
+func expandVars(value string, vars map[string]string) string {
+	return templateVar.ReplaceAllStringFunc(value, func(match string) string {
+		return vars[templateVar.FindStringSubmatch(match)[1]]
+	})
+}
+
+// NewTemplateGenerator initializes the generator with the built-in intent
+// blueprints.
 func NewTemplateGenerator() *TemplateGenerator {
 	return &TemplateGenerator{
 		blueprints: loadStandardBlueprints(),
@@ -35,16 +67,18 @@ func (g *TemplateGenerator) Generate(obj *intent.Objective) (*dag.Graph, error)
 		return nil, fmt.Errorf("cannot generate graph from nil objective")
 	}
 
-	bp, ok := g.blueprints[obj.Type]
+	bpIntent := obj.Type
+	bp, ok := g.blueprints[bpIntent]
 	if !ok {
 		// Fallback to a generic single-step graph if intent is unknown
-		bp = g.blueprints[intent.IntentGeneral]
+		bpIntent = intent.IntentGeneral
+		bp = g.blueprints[bpIntent]
 	}
 
 	// Hydrate the blueprint into a unique graph instance
 	// DETERMINISTIC ID: graph ID is derived directly from the objective ID.
 	graphID := fmt.Sprintf("graph-%s", obj.ID)
-	
+
 	graph := &dag.Graph{
 		ID:     graphID,
 		Status: dag.StatusCreated,
@@ -52,6 +86,26 @@ func (g *TemplateGenerator) Generate(obj *intent.Objective) (*dag.Graph, error)
 		Edges:  make([]dag.Edge, len(bp.edges)),
 	}
 
+	// Variables available to ${...} placeholders in blueprint defaults
+	vars := map[string]string{
+		"goal":   obj.Description,
+		"intent": string(obj.Type),
+	}
+	for k, v := range obj.Metadata {
+		if _, reserved := vars[k]; !reserved {
+			vars[k] = v
+		}
+	}
+	var missing []string
+	for _, name := range bp.required {
+		if strings.TrimSpace(vars[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &MissingVariablesError{Intent: bpIntent, Missing: missing}
+	}
+
 	// Deep copy nodes and inject context from the objective
 	for i, nodeTmpl := range bp.nodes {
 		n := nodeTmpl // copy
@@ -59,12 +113,17 @@ func (g *TemplateGenerator) Generate(obj *intent.Objective) (*dag.Graph, error)
 		// Since TemplateNodeID is unique within the blueprint, this is safe.
 		n.ID = fmt.Sprintf("%s-%s", graphID, nodeTmpl.ID)
 		n.Status = dag.StatusCreated
-		
-		// Initialize config if nil
-		if n.Config == nil {
-			n.Config = make(map[string]string)
+
+		// Build a fresh config so instances never share the blueprint's map.
+		// Precedence: node type defaults < template node config < objective context.
+		n.Config = make(map[string]string)
+		for k, v := range bp.defaults[nodeTmpl.Type] {
+			n.Config[k] = expandVars(v, vars)
+		}
+		for k, v := range nodeTmpl.Config {
+			n.Config[k] = v
 		}
-		
+
 		// Inject objective context
 		n.Config["goal"] = obj.Description
 		for k, v := range obj.Metadata {
@@ -103,6 +162,13 @@ func loadStandardBlueprints() map[intent.IntentType]blueprint {
 				{From: "researcher", To: "critic"},
 				{From: "critic", To: "synthesizer"},
 			},
+			// Match the config keys the executor's handlers require
+			defaults: map[string]map[string]string{
+				"researcher_agent":  {"query": "${goal}"},
+				"critic_agent":      {"task": "verify"},
+				"synthesizer_agent": {"query": "${goal}"},
+			},
+			required: []string{"goal"},
 		},
 		intent.IntentCodeGen: {
 			nodes: []dag.Node{
@@ -0,0 +1,100 @@
+package intent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ConstraintKind classifies a constraint extracted from a query.
+type ConstraintKind string
+
+const (
+	ConstraintPhrase   ConstraintKind = "PHRASE"   // Quoted phrase that must appear
+	ConstraintTemporal ConstraintKind = "TEMPORAL" // Time bound, e.g. "before 2020"
+	ConstraintLanguage ConstraintKind = "LANGUAGE" // Programming language, e.g. "in Python"
+	ConstraintLimit    ConstraintKind = "LIMIT"    // Numeric cap, e.g. "no more than 5"
+)
+
+// Constraint is a typed restriction on the objective.
+type Constraint struct {
+	Kind  ConstraintKind `json:"kind"`
+	Value string         `json:"value"`
+}
+
+// String returns a compact "KIND:value" representation.
+func (c Constraint) String() string {
+	return string(c.Kind) + ":" + c.Value
+}
+
+// ConstraintExtractor defines a strategy for pulling constraints out of a query.
+// Implementations may be heuristic, regex-based, or backed by a model.
+type ConstraintExtractor interface {
+	Extract(query string) []Constraint
+}
+
+// QuotedPhraseExtractor treats phrases in double quotes as constraints.
+// It is the default strategy used by BasicParser.
+type QuotedPhraseExtractor struct{}
+
+// Extract returns each quoted phrase as a PHRASE constraint.
+func (QuotedPhraseExtractor) Extract(query string) []Constraint {
+	var constraints []Constraint
+	parts := strings.Split(query, "\"")
+	for i := 1; i < len(parts); i += 2 {
+		constraints = append(constraints, Constraint{Kind: ConstraintPhrase, Value: parts[i]})
+	}
+	return constraints
+}
+
+// RegexRule maps a pattern to a constraint kind. If the pattern has a capture
+// group, the first group becomes the value; otherwise the whole match does.
+type RegexRule struct {
+	Kind    ConstraintKind
+	Pattern *regexp.Regexp
+}
+
+// RegexExtractor extracts constraints by matching a set of regex rules.
+type RegexExtractor struct {
+	rules []RegexRule
+}
+
+// NewRegexExtractor creates an extractor from the given rules.
+func NewRegexExtractor(rules ...RegexRule) *RegexExtractor {
+	return &RegexExtractor{rules: rules}
+}
+
+// DefaultRegexRules covers common temporal, language, and limit phrasings.
+func DefaultRegexRules() []RegexRule {
+	return []RegexRule{
+		{Kind: ConstraintTemporal, Pattern: regexp.MustCompile(`(?i)\b((?:before|after|since|until) \d{4})\b`)},
+		{Kind: ConstraintLanguage, Pattern: regexp.MustCompile(`\b(?:in|using) (Python|Go|Golang|Java|JavaScript|TypeScript|Rust|C\+\+|C#|Ruby)\b`)},
+		{Kind: ConstraintLimit, Pattern: regexp.MustCompile(`(?i)\b((?:no more than|at most|up to|at least) \d+)\b`)},
+	}
+}
+
+// Extract applies each rule in order and returns all matches.
+func (r *RegexExtractor) Extract(query string) []Constraint {
+	var constraints []Constraint
+	for _, rule := range r.rules {
+		for _, match := range rule.Pattern.FindAllStringSubmatch(query, -1) {
+			value := match[0]
+			if len(match) > 1 && match[1] != "" {
+				value = match[1]
+			}
+			constraints = append(constraints, Constraint{Kind: rule.Kind, Value: value})
+		}
+	}
+	return constraints
+}
+
+// ChainExtractor runs several extractors and concatenates their results.
+type ChainExtractor []ConstraintExtractor
+
+// Extract returns the constraints from every extractor in order.
+func (c ChainExtractor) Extract(query string) []Constraint {
+	var constraints []Constraint
+	for _, extractor := range c {
+		constraints = append(constraints, extractor.Extract(query)...)
+	}
+	return constraints
+}
@@ -0,0 +1,97 @@
+package intent
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestQuotedPhraseExtractor(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []Constraint
+	}{
+		{
+			name:  "No quotes",
+			query: "Research quantum computing",
+			want:  nil,
+		},
+		{
+			name:  "Two phrases",
+			query: `Compare "rust" with "go"`,
+			want: []Constraint{
+				{Kind: ConstraintPhrase, Value: "rust"},
+				{Kind: ConstraintPhrase, Value: "go"},
+			},
+		},
+		{
+			name:  "Unterminated quote",
+			query: `Find "open source`,
+			want:  []Constraint{{Kind: ConstraintPhrase, Value: "open source"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := QuotedPhraseExtractor{}.Extract(tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegexExtractor_DefaultRules(t *testing.T) {
+	extractor := NewRegexExtractor(DefaultRegexRules()...)
+
+	got := extractor.Extract("Implement a parser in Python using papers published before 2020, no more than 5 sources")
+	want := []Constraint{
+		{Kind: ConstraintTemporal, Value: "before 2020"},
+		{Kind: ConstraintLanguage, Value: "Python"},
+		{Kind: ConstraintLimit, Value: "no more than 5"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+}
+
+func TestRegexExtractor_CustomRule(t *testing.T) {
+	regionKind := ConstraintKind("REGION")
+	extractor := NewRegexExtractor(RegexRule{
+		Kind:    regionKind,
+		Pattern: regexp.MustCompile(`\bin the (EU|US|UK)\b`),
+	})
+
+	got := extractor.Extract("Summarize privacy law in the EU and in the US")
+	want := []Constraint{
+		{Kind: regionKind, Value: "EU"},
+		{Kind: regionKind, Value: "US"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %v, want %v", got, want)
+	}
+}
+
+func TestBasicParser_CustomExtractor(t *testing.T) {
+	parser := NewBasicParser()
+	parser.SetConstraintExtractor(ChainExtractor{
+		QuotedPhraseExtractor{},
+		NewRegexExtractor(DefaultRegexRules()...),
+	})
+
+	obj, err := parser.Parse(`Research "transformers" since 2017`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Constraint{
+		{Kind: ConstraintPhrase, Value: "transformers"},
+		{Kind: ConstraintTemporal, Value: "since 2017"},
+	}
+	if !reflect.DeepEqual(obj.Constraints, want) {
+		t.Errorf("Constraints = %v, want %v", obj.Constraints, want)
+	}
+}
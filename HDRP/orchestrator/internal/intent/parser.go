@@ -22,7 +22,7 @@ type Objective struct {
 	ID          string            `json:"id"`
 	Description string            `json:"description"`
 	Type        IntentType        `json:"type"`
-	Constraints []string          `json:"constraints"`
+	Constraints []Constraint      `json:"constraints"`
 	Metadata    map[string]string `json:"metadata"`
 	CreatedAt   time.Time         `json:"created_at"`
 }
@@ -33,11 +33,19 @@ type Parser interface {
 }
 
 // BasicParser implements a simple heuristic-based parser for the MVP.
-type BasicParser struct{}
+type BasicParser struct {
+	extractor ConstraintExtractor
+}
 
 // NewBasicParser creates a new instance of BasicParser.
+// Constraints are extracted from quoted phrases by default.
 func NewBasicParser() *BasicParser {
-	return &BasicParser{}
+	return &BasicParser{extractor: QuotedPhraseExtractor{}}
+}
+
+// SetConstraintExtractor replaces the constraint extraction strategy.
+func (p *BasicParser) SetConstraintExtractor(extractor ConstraintExtractor) {
+	p.extractor = extractor
 }
 
 // Parse converts a user query string into an Objective using keyword heuristics.
@@ -51,7 +59,7 @@ trimmedQuery := strings.TrimSpace(query)
 	lowerQuery := strings.ToLower(trimmedQuery)
 
 	intentType := detectIntent(lowerQuery)
-	constraints := extractConstraints(trimmedQuery)
+	constraints := p.extractConstraints(trimmedQuery)
 
 	return &Objective{
 		ID:          uuid.New().String(),
@@ -79,14 +87,10 @@ func detectIntent(query string) IntentType {
 	}
 }
 
-// extractConstraints is a placeholder for extraction logic.
-// In a real system, this might use NER or dependency parsing.
-func extractConstraints(query string) []string {
-	var constraints []string
-	// Simple heuristic: phrases in quotes are constraints
-	parts := strings.Split(query, "\"")
-	for i := 1; i < len(parts); i += 2 {
-		constraints = append(constraints, parts[i])
+// extractConstraints delegates to the configured extraction strategy.
+func (p *BasicParser) extractConstraints(query string) []Constraint {
+	if p.extractor == nil {
+		return QuotedPhraseExtractor{}.Extract(query)
 	}
-	return constraints
+	return p.extractor.Extract(query)
 }
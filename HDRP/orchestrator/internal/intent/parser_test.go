@@ -97,7 +97,7 @@ func TestConstraintExtraction(t *testing.T) {
 	}
 
 	for i, c := range obj.Constraints {
-		if c != expected[i] {
+		if c.Kind != ConstraintPhrase || c.Value != expected[i] {
 			t.Errorf("Constraint %d: got %s, want %s", i, c, expected[i])
 		}
 	}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LevelEnv names the environment variable setting the minimum level logged:
+// debug, info (the default), warn or error.
+const LevelEnv = "HDRP_LOG_LEVEL"
+
+// Level is the severity of a log record.
+type Level = slog.Level
+
+const (
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
+// minLevel filters every record written by this package, run logs included.
+var minLevel = new(slog.LevelVar)
+
+func init() {
+	if value := os.Getenv(LevelEnv); value != "" {
+		level, err := ParseLevel(value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logger: %s: %v, logging at info\n", LevelEnv, err)
+			return
+		}
+		minLevel.Set(level)
+	}
+}
+
+// ParseLevel parses a level name: debug, info, warn (or warning) or error,
+// in any case.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+}
+
+// SetLevel changes the minimum level logged, overriding HDRP_LOG_LEVEL.
+func SetLevel(level Level) {
+	minLevel.Set(level)
+}
+
+// GetLevel returns the minimum level logged.
+func GetLevel() Level {
+	return minLevel.Level()
+}
+
+// handlerOptions applies the minimum level and writes records in the same
+// schema as the Python services: timestamp, level, component, event.
+func handlerOptions() *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level: minLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				return slog.String("timestamp", a.Value.Time().UTC().Format(time.RFC3339Nano))
+			case slog.MessageKey:
+				a.Key = "event"
+			}
+			return a
+		},
+	}
+}
+
+// syncWriter serializes writes to a replaceable destination.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// output is where every Logger writes.
+var output = &syncWriter{w: os.Stderr}
+
+// SetOutput redirects every Logger, existing ones included. Loggers write
+// to stderr by default.
+func SetOutput(w io.Writer) {
+	output.mu.Lock()
+	defer output.mu.Unlock()
+	output.w = w
+}
+
+// Logger writes leveled JSONL records for one component of the
+// orchestrator. Records below the minimum level are dropped before their
+// message is formatted.
+type Logger struct {
+	l *slog.Logger
+}
+
+// New creates a logger whose records are tagged with component.
+func New(component string) *Logger {
+	handler := slog.NewJSONHandler(output, handlerOptions())
+	return &Logger{l: slog.New(handler).With(slog.String("component", component))}
+}
+
+// With returns a logger that adds the given key-value pairs to every record.
+func (l *Logger) With(args ...any) *Logger {
+	return &Logger{l: l.l.With(args...)}
+}
+
+// Enabled reports whether records at level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return l.l.Enabled(context.Background(), level)
+}
+
+// Debug, Info, Warn and Error log msg with key-value pairs as fields.
+func (l *Logger) Debug(msg string, args ...any) { l.l.Debug(msg, args...) }
+func (l *Logger) Info(msg string, args ...any)  { l.l.Info(msg, args...) }
+func (l *Logger) Warn(msg string, args ...any)  { l.l.Warn(msg, args...) }
+func (l *Logger) Error(msg string, args ...any) { l.l.Error(msg, args...) }
+
+// Debugf, Infof, Warnf and Errorf log a message formatted as with fmt.Sprintf.
+func (l *Logger) Debugf(format string, args ...any) { l.logf(LevelDebug, format, args) }
+func (l *Logger) Infof(format string, args ...any)  { l.logf(LevelInfo, format, args) }
+func (l *Logger) Warnf(format string, args ...any)  { l.logf(LevelWarn, format, args) }
+func (l *Logger) Errorf(format string, args ...any) { l.logf(LevelError, format, args) }
+
+// Fatalf logs a formatted message at error level and exits the process.
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.logf(LevelError, format, args)
+	os.Exit(1)
+}
+
+func (l *Logger) logf(level Level, format string, args []any) {
+	ctx := context.Background()
+	if !l.l.Enabled(ctx, level) {
+		return
+	}
+	l.l.Log(ctx, level, fmt.Sprintf(format, args...))
+}
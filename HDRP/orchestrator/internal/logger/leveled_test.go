@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]Level{
+		"debug":   LevelDebug,
+		"INFO":    LevelInfo,
+		"warn":    LevelWarn,
+		"Warning": LevelWarn,
+		" error ": LevelError,
+	}
+	for name, want := range tests {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+// captureLogger redirects Loggers to a buffer at level for one test.
+func captureLogger(t *testing.T, level Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := GetLevel()
+	SetOutput(&buf)
+	SetLevel(level)
+	t.Cleanup(func() {
+		SetOutput(os.Stderr)
+		SetLevel(previous)
+	})
+	return &buf
+}
+
+func decodeRecords(t *testing.T, data []byte) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Invalid JSONL record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// TestLoggerLevels verifies records below the minimum level are dropped and
+// the rest carry their level, timestamp and component.
+func TestLoggerLevels(t *testing.T) {
+	buf := captureLogger(t, LevelWarn)
+
+	log := New("executor")
+	log.Debugf("node %s scheduled", "a")
+	log.Infof("node %s started", "a")
+	log.Warnf("node %s slow", "a")
+	log.With("node_id", "a").Error("node failed", "attempts", 3)
+
+	records := decodeRecords(t, buf.Bytes())
+	if len(records) != 2 {
+		t.Fatalf("Expected the warn and error records only, got %d: %s", len(records), buf.String())
+	}
+	warn, failed := records[0], records[1]
+	if warn["level"] != "WARN" || warn["event"] != "node a slow" || warn["component"] != "executor" {
+		t.Errorf("Unexpected warn record: %v", warn)
+	}
+	if ts, _ := warn["timestamp"].(string); ts == "" {
+		t.Errorf("Record has no timestamp: %v", warn)
+	}
+	if failed["level"] != "ERROR" || failed["node_id"] != "a" || failed["attempts"] != float64(3) {
+		t.Errorf("Unexpected error record: %v", failed)
+	}
+
+	if log.Enabled(LevelInfo) || !log.Enabled(LevelError) {
+		t.Error("Enabled doesn't follow the minimum level")
+	}
+}
+
+// TestLogEventLevel verifies run log entries use the shared event schema
+// and honor the minimum level.
+func TestLogEventLevel(t *testing.T) {
+	runID := "test-run-levels"
+	logPath := filepath.Join("..", "..", "logs", runID+".jsonl")
+	_ = os.Remove(logPath)
+	t.Cleanup(func() { _ = os.Remove(logPath) })
+	captureLogger(t, LevelInfo)
+
+	if err := InitLogger(runID); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	LogEventLevel(nil, LevelDebug, runID, "cli", "debug_event", nil)
+	LogEventLevel(nil, LevelError, runID, "cli", "error_event", map[string]string{"phase": "intent"})
+	Close()
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	records := decodeRecords(t, content)
+	if len(records) != 2 || records[0]["event"] != "session_start" {
+		t.Fatalf("Expected session_start and error_event, got %s", content)
+	}
+	last := records[1]
+	if last["event"] != "error_event" || last["level"] != "ERROR" || last["run_id"] != runID || last["timestamp"] == nil {
+		t.Errorf("Unexpected record: %v", last)
+	}
+}
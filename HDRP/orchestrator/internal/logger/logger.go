@@ -1,11 +1,14 @@
 package logger
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -19,17 +22,61 @@ type LogSchema struct {
 	Payload   interface{} `json:"payload"`
 }
 
+const (
+	// FlushInterval bounds how long a logged event can sit in the buffer.
+	FlushInterval = 500 * time.Millisecond
+	// DefaultCloseTimeout is the flush deadline used by Close.
+	DefaultCloseTimeout = 5 * time.Second
+)
+
 var (
+	mu            sync.Mutex
 	currentLogger *slog.Logger
-	logFile       *os.File
+	logFile       *bufferedFile
+	stopFlusher   chan struct{}
+	flusherDone   chan struct{}
 )
 
+// bufferedFile batches log writes in memory and flushes them to disk on
+// demand. Flushes fsync so events survive a crash once flushed.
+type bufferedFile struct {
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+}
+
+func (b *bufferedFile) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *bufferedFile) flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.buf.Flush(); err != nil {
+		return err
+	}
+	return b.file.Sync()
+}
+
+func (b *bufferedFile) close() error {
+	flushErr := b.flush()
+	if err := b.file.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
+
 // InitLogger sets up a new logging session for a specific run
 func InitLogger(runID string) error {
 	if runID == "" {
 		runID = uuid.New().String()
 	}
 
+	// Finish any previous session so its buffered events aren't lost
+	Close()
+
 	// Ensure logs directory exists
 	logDir := "../../logs"
 	if err := os.MkdirAll(logDir, 0755); err != nil {
@@ -42,13 +89,18 @@ func InitLogger(runID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
-	logFile = f
+	out := &bufferedFile{file: f, buf: bufio.NewWriter(f)}
 
-	// JSON Handler for structured output
-	handler := slog.NewJSONHandler(f, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	})
+	// JSON Handler for structured output, filtered by HDRP_LOG_LEVEL
+	handler := slog.NewJSONHandler(out, handlerOptions())
+
+	mu.Lock()
+	logFile = out
 	currentLogger = slog.New(handler)
+	stopFlusher = make(chan struct{})
+	flusherDone = make(chan struct{})
+	go runFlusher(out, stopFlusher, flusherDone)
+	mu.Unlock()
 
 	// Log the start of the session
 	LogEvent(context.Background(), runID, "orchestrator", "session_start", map[string]string{
@@ -58,16 +110,46 @@ func InitLogger(runID string) error {
 	return nil
 }
 
-// LogEvent writes a structured log entry
+// runFlusher periodically flushes buffered events until stop is closed.
+func runFlusher(out *bufferedFile, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := out.flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: periodic flush failed: %v\n", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// LogEvent writes a structured log entry at info level
 func LogEvent(ctx context.Context, runID, component, event string, payload interface{}) {
+	LogEventLevel(ctx, LevelInfo, runID, component, event, payload)
+}
+
+// LogEventLevel writes a structured log entry at level. Entries below the
+// minimum level (HDRP_LOG_LEVEL) are dropped.
+func LogEventLevel(ctx context.Context, level Level, runID, component, event string, payload interface{}) {
+	mu.Lock()
 	if currentLogger == nil {
 		// Fallback if not initialized
-		handler := slog.NewJSONHandler(os.Stdout, nil)
+		handler := slog.NewJSONHandler(os.Stdout, handlerOptions())
 		currentLogger = slog.New(handler)
 	}
+	l := currentLogger
+	mu.Unlock()
 
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	// We use the attributes to match our schema
-	currentLogger.Info(event,
+	l.Log(ctx, level, event,
 		slog.String("run_id", runID),
 		slog.String("component", component),
 		slog.Any("payload", payload),
@@ -79,9 +161,58 @@ func GenerateRunID() string {
 	return uuid.New().String()
 }
 
-// Close ensures the file is closed
+// Flush writes buffered events to disk, giving up when ctx is done.
+func Flush(ctx context.Context) error {
+	mu.Lock()
+	out := logFile
+	mu.Unlock()
+	if out == nil {
+		return nil
+	}
+	return withContext(ctx, out.flush)
+}
+
+// CloseContext flushes buffered events and closes the log file. If ctx ends
+// before the flush completes, including one the periodic flusher is still
+// running, it returns ctx's error and the file is closed in the background
+// once the flush finishes.
+func CloseContext(ctx context.Context) error {
+	mu.Lock()
+	out := logFile
+	stop, done := stopFlusher, flusherDone
+	logFile = nil
+	currentLogger = nil
+	stopFlusher, flusherDone = nil, nil
+	mu.Unlock()
+
+	if out == nil {
+		return nil
+	}
+	close(stop)
+	return withContext(ctx, func() error {
+		<-done
+		return out.close()
+	})
+}
+
+// Close flushes and closes the log file, waiting at most DefaultCloseTimeout.
 func Close() {
-	if logFile != nil {
-		logFile.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCloseTimeout)
+	defer cancel()
+	if err := CloseContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "logger: close failed: %v\n", err)
+	}
+}
+
+// withContext runs fn, returning early if ctx is done first.
+func withContext(ctx context.Context, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- fn() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("log flush interrupted: %w", ctx.Err())
 	}
 }
@@ -1,10 +1,14 @@
 package logger
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestInitLoggerWritesLogFile(t *testing.T) {
@@ -29,3 +33,86 @@ func TestInitLoggerWritesLogFile(t *testing.T) {
 
 	_ = os.Remove(logPath)
 }
+
+func TestEventsBeforeCloseArePersisted(t *testing.T) {
+	runID := "test-run-close-flush"
+	logPath := filepath.Join("..", "..", "logs", runID+".jsonl")
+
+	_ = os.Remove(logPath)
+	t.Cleanup(func() { _ = os.Remove(logPath) })
+
+	if err := InitLogger(runID); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		LogEvent(context.Background(), runID, "cli", "burst_event", map[string]int{"i": i})
+	}
+	LogEvent(context.Background(), runID, "cli", "last_event", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := CloseContext(ctx); err != nil {
+		t.Fatalf("CloseContext failed: %v", err)
+	}
+
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if got := strings.Count(string(content), "burst_event"); got != 100 {
+		t.Errorf("expected 100 burst events on disk, got %d", got)
+	}
+	if !strings.Contains(string(content), "last_event") {
+		t.Errorf("expected event logged just before Close to be on disk")
+	}
+}
+
+func TestPeriodicFlush(t *testing.T) {
+	runID := "test-run-periodic-flush"
+	logPath := filepath.Join("..", "..", "logs", runID+".jsonl")
+
+	_ = os.Remove(logPath)
+	t.Cleanup(func() { _ = os.Remove(logPath) })
+
+	if err := InitLogger(runID); err != nil {
+		t.Fatalf("InitLogger failed: %v", err)
+	}
+	defer Close()
+
+	LogEvent(context.Background(), runID, "cli", "buffered_event", nil)
+	time.Sleep(2 * FlushInterval)
+
+	// Read without closing, as if the process were about to be killed
+	content, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "buffered_event") {
+		t.Errorf("expected event flushed within %v", FlushInterval)
+	}
+}
+
+func TestCloseContextDoesNotWaitForStuckFlusher(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stuck-*.jsonl")
+	if err != nil {
+		t.Fatalf("create log file: %v", err)
+	}
+	done := make(chan struct{})
+	mu.Lock()
+	logFile = &bufferedFile{file: f, buf: bufio.NewWriter(f)}
+	stopFlusher, flusherDone = make(chan struct{}), done
+	mu.Unlock()
+	// Let the background close finish once the test is over
+	t.Cleanup(func() { close(done) })
+
+	// A flusher that never exits stands in for a flush stuck on a slow disk
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := CloseContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CloseContext returned %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("CloseContext took %v, expected it to return at the 50ms deadline", elapsed)
+	}
+}
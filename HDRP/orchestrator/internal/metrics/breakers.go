@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Values of the hdrp_circuit_breaker_state gauge
+const (
+	BreakerClosed   = 0
+	BreakerHalfOpen = 1
+	BreakerOpen     = 2
+)
+
+var (
+	// Breakers tripping open, by service
+	circuitBreakerOpens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hdrp_circuit_breaker_opens_total",
+			Help: "Total number of times a service's circuit breaker opened",
+		},
+		[]string{"service"},
+	)
+
+	// Breakers letting test requests through after their open timeout
+	circuitBreakerHalfOpens = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hdrp_circuit_breaker_half_opens_total",
+			Help: "Total number of times a service's circuit breaker moved to half-open",
+		},
+		[]string{"service"},
+	)
+
+	circuitBreakerStateDesc = prometheus.NewDesc(
+		"hdrp_circuit_breaker_state",
+		"Current circuit breaker state by service (0=closed, 1=half-open, 2=open)",
+		[]string{"service"}, nil,
+	)
+
+	breakerStates = &breakerStateCollector{}
+)
+
+func init() {
+	prometheus.MustRegister(breakerStates)
+}
+
+// breakerStateCollector reads breaker states when /metrics is scraped, so
+// the gauge always reflects the live breakers, and services whose breakers
+// are dropped disappear from it.
+type breakerStateCollector struct {
+	mu     sync.RWMutex
+	states func() map[string]int
+}
+
+func (c *breakerStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- circuitBreakerStateDesc
+}
+
+func (c *breakerStateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	states := c.states
+	c.mu.RUnlock()
+	if states == nil {
+		return
+	}
+	for service, state := range states() {
+		ch <- prometheus.MustNewConstMetric(circuitBreakerStateDesc, prometheus.GaugeValue, float64(state), service)
+	}
+}
+
+// RegisterCircuitBreakers sets the source of the hdrp_circuit_breaker_state
+// gauge: states is called on every scrape and returns each service's state
+// as BreakerClosed, BreakerHalfOpen or BreakerOpen. Registering again
+// replaces the previous source.
+func RegisterCircuitBreakers(states func() map[string]int) {
+	breakerStates.mu.Lock()
+	defer breakerStates.mu.Unlock()
+	breakerStates.states = states
+}
+
+// RecordCircuitBreakerOpened increments the breaker opens counter
+func RecordCircuitBreakerOpened(service string) {
+	circuitBreakerOpens.WithLabelValues(service).Inc()
+}
+
+// RecordCircuitBreakerHalfOpened increments the breaker half-open counter
+func RecordCircuitBreakerHalfOpened(service string) {
+	circuitBreakerHalfOpens.WithLabelValues(service).Inc()
+}
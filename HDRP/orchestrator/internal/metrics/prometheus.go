@@ -56,6 +56,15 @@ var (
 		[]string{"service", "method", "status"},
 	)
 
+	// Failed service RPCs by gRPC status code
+	rpcErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hdrp_rpc_errors_total",
+			Help: "Total number of failed RPC calls by service, method and gRPC code",
+		},
+		[]string{"service", "method", "code"},
+	)
+
 	// Error rate counter
 	errorCount = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -74,6 +83,15 @@ var (
 		[]string{"node_type", "status"},
 	)
 
+	// Execute request outcomes, separating client disconnects from failures
+	requestOutcomes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hdrp_execute_requests_total",
+			Help: "Total number of execute requests by the phase they ended in and outcome",
+		},
+		[]string{"phase", "status"}, // decomposition, execution; success, failed, error, timeout, client_cancelled
+	)
+
 	// Current active DAG executions gauge
 	activeDagExecutions = promauto.NewGauge(
 		prometheus.GaugeOpts{
@@ -81,6 +99,41 @@ var (
 			Help: "Current number of active DAG executions",
 		},
 	)
+
+	// Time node goroutines spend blocked handing results to the scheduler
+	resultChannelWait = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "hdrp_result_channel_wait_seconds",
+			Help:    "Time spent waiting to send a node result to the execution loop",
+			Buckets: []float64{0.0001, 0.001, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		},
+	)
+
+	// Time state transitions spend blocked on a full persistence queue
+	persistenceQueueWait = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "hdrp_persistence_queue_wait_seconds",
+			Help:    "Time spent waiting for space in the async persistence queue",
+			Buckets: []float64{0.0001, 0.001, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		},
+	)
+
+	// Current number of queued state transitions
+	persistenceQueueDepth = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "hdrp_persistence_queue_depth",
+			Help: "Current number of state transitions waiting to be persisted",
+		},
+	)
+
+	// Failed run event deliveries, by backend and whether they were retried
+	eventDeliveryFailures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hdrp_event_delivery_failures_total",
+			Help: "Total number of failed run event delivery attempts by backend and outcome",
+		},
+		[]string{"backend", "outcome"}, // retried, dropped
+	)
 )
 
 // RecordDAGExecution records DAG execution metrics
@@ -112,6 +165,11 @@ func RecordRPCLatency(service, method string, durationSeconds float64, success b
 	rpcLatency.WithLabelValues(service, method, status).Observe(durationSeconds)
 }
 
+// RecordRPCError increments the failed RPC counter
+func RecordRPCError(service, method, code string) {
+	rpcErrors.WithLabelValues(service, method, code).Inc()
+}
+
 // RecordError increments the error counter
 func RecordError(service, errorType string) {
 	errorCount.WithLabelValues(service, errorType).Inc()
@@ -122,6 +180,11 @@ func RecordNodeExecution(nodeType, status string) {
 	nodeExecutions.WithLabelValues(nodeType, status).Inc()
 }
 
+// RecordRequestOutcome counts an execute request that ended in phase with status
+func RecordRequestOutcome(phase, status string) {
+	requestOutcomes.WithLabelValues(phase, status).Inc()
+}
+
 // IncrementActiveDagExecutions increments the active DAG executions gauge
 func IncrementActiveDagExecutions() {
 	activeDagExecutions.Inc()
@@ -132,6 +195,26 @@ func DecrementActiveDagExecutions() {
 	activeDagExecutions.Dec()
 }
 
+// RecordResultChannelWait records how long a node waited to deliver its result
+func RecordResultChannelWait(durationSeconds float64) {
+	resultChannelWait.Observe(durationSeconds)
+}
+
+// RecordPersistenceQueueWait records how long a transition waited for queue space
+func RecordPersistenceQueueWait(durationSeconds float64) {
+	persistenceQueueWait.Observe(durationSeconds)
+}
+
+// SetPersistenceQueueDepth sets the persistence queue depth gauge
+func SetPersistenceQueueDepth(depth int) {
+	persistenceQueueDepth.Set(float64(depth))
+}
+
+// RecordEventDeliveryFailure counts a failed event delivery attempt
+func RecordEventDeliveryFailure(backend, outcome string) {
+	eventDeliveryFailures.WithLabelValues(backend, outcome).Inc()
+}
+
 // GetMetricsHandler returns the HTTP handler for the /metrics endpoint
 func GetMetricsHandler() http.Handler {
 	return promhttp.Handler()
@@ -52,3 +52,26 @@ hdrp_dag_execution_seconds_count{status="success"} 1
 		t.Fatalf("unexpected histogram output: %v", err)
 	}
 }
+
+func TestCircuitBreakerMetrics(t *testing.T) {
+	RegisterCircuitBreakers(func() map[string]int {
+		return map[string]int{"critic": BreakerOpen, "researcher": BreakerClosed}
+	})
+	defer RegisterCircuitBreakers(nil)
+
+	expected := `
+# HELP hdrp_circuit_breaker_state Current circuit breaker state by service (0=closed, 1=half-open, 2=open)
+# TYPE hdrp_circuit_breaker_state gauge
+hdrp_circuit_breaker_state{service="critic"} 2
+hdrp_circuit_breaker_state{service="researcher"} 0
+`
+	if err := testutil.CollectAndCompare(breakerStates, strings.NewReader(expected)); err != nil {
+		t.Fatalf("unexpected breaker state output: %v", err)
+	}
+
+	before := testutil.ToFloat64(circuitBreakerOpens.WithLabelValues("critic"))
+	RecordCircuitBreakerOpened("critic")
+	if got := testutil.ToFloat64(circuitBreakerOpens.WithLabelValues("critic")); got != before+1 {
+		t.Fatalf("expected breaker opens %v, got %v", before+1, got)
+	}
+}
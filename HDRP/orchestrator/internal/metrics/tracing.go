@@ -7,6 +7,7 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -58,6 +59,16 @@ func InitTracing(serviceName, otlpEndpoint string) error {
 	return nil
 }
 
+// SetTracerProvider makes StartSpan create spans from tp, e.g. an in-memory
+// provider in tests. A nil tp disables tracing again.
+func SetTracerProvider(tp trace.TracerProvider) {
+	if tp == nil {
+		tracer = nil
+		return
+	}
+	tracer = tp.Tracer("hdrp")
+}
+
 // StartSpan starts a new span with the given name
 func StartSpan(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
 	if tracer == nil {
@@ -83,11 +94,12 @@ func AddSpanEvent(ctx context.Context, name string, attrs ...attribute.KeyValue)
 	}
 }
 
-// RecordSpanError records an error on the current span
+// RecordSpanError records an error on the current span and marks it failed
 func RecordSpanError(ctx context.Context, err error) {
 	span := trace.SpanFromContext(ctx)
 	if span.IsRecording() && err != nil {
 		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
 }
 
@@ -0,0 +1,130 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"hdrp/internal/executor"
+	"hdrp/internal/metrics"
+)
+
+// Defaults for HTTPOptions fields left at zero.
+const (
+	DefaultHTTPWorkers    = 1
+	DefaultHTTPTimeout    = 10 * time.Second
+	DefaultHTTPMaxRetries = 2
+	DefaultHTTPRetryDelay = 500 * time.Millisecond
+)
+
+// HTTPOptions tunes delivery for an HTTPPublisher.
+type HTTPOptions struct {
+	QueueSize int // Events buffered for delivery; <= 0 uses DefaultQueueSize
+
+	// Workers is the number of deliveries in flight at once. Events are
+	// delivered in order only with a single worker.
+	Workers int
+
+	Timeout    time.Duration // Bounds each delivery attempt
+	MaxRetries int           // Retries after a failed attempt; < 0 disables
+	RetryDelay time.Duration // Wait before the first retry, doubling after each
+}
+
+// HTTPPublisher POSTs each event as JSON to a URL, e.g. a webhook or a
+// message broker's REST ingestion endpoint. Deliveries run on a pool of
+// workers, so a slow endpoint never holds up the run that published the
+// event; failed deliveries are retried with backoff, then dropped.
+type HTTPPublisher struct {
+	*queue
+	url    string
+	client *http.Client
+	opts   HTTPOptions
+}
+
+// NewHTTPPublisher creates a publisher that posts to url. A nil client uses
+// a default client.
+func NewHTTPPublisher(url string, client *http.Client, opts HTTPOptions) *HTTPPublisher {
+	if client == nil {
+		client = &http.Client{}
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultHTTPWorkers
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultHTTPTimeout
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultHTTPMaxRetries
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = DefaultHTTPRetryDelay
+	}
+	p := &HTTPPublisher{url: url, client: client, opts: opts}
+	p.queue = newWorkerQueue(opts.QueueSize, opts.Workers, p.deliver)
+	return p
+}
+
+// deliver sends one event, retrying transient failures.
+func (p *HTTPPublisher) deliver(evt executor.Event) error {
+	delay := p.opts.RetryDelay
+	for attempt := 0; ; attempt++ {
+		err := p.send(evt)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || attempt >= p.opts.MaxRetries {
+			metrics.RecordEventDeliveryFailure(BackendHTTP, "dropped")
+			return err
+		}
+		metrics.RecordEventDeliveryFailure(BackendHTTP, "retried")
+		log.Printf("[Events] Retrying %s event for run %s in %v: %v", evt.Type, evt.RunID, delay, err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// permanentError marks a delivery failure that retrying won't fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// send posts one event and fails on any non-2xx response. Client errors
+// other than 429 are permanent.
+func (p *HTTPPublisher) send(evt executor.Event) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to encode event: %w", err)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{fmt.Errorf("failed to build request: %w", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		err := fmt.Errorf("event endpoint returned %s", resp.Status)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return &permanentError{err}
+		}
+		return err
+	}
+	return nil
+}
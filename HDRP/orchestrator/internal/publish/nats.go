@@ -0,0 +1,138 @@
+package publish
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"hdrp/internal/executor"
+)
+
+// natsDialTimeout bounds connecting and completing the NATS handshake.
+const natsDialTimeout = 5 * time.Second
+
+// NATSPublisher publishes each event as JSON to a NATS subject named
+// <subject>.<event type>, speaking the core NATS text protocol directly.
+// The connection is opened on first use and re-opened after a failure.
+type NATSPublisher struct {
+	*queue
+	addr    string
+	subject string
+
+	mu   sync.Mutex // Guards conn and serializes writes to it
+	conn net.Conn
+}
+
+// NewNATSPublisher creates a publisher for the server at rawURL
+// (nats://host[:port]), buffering up to queueSize events.
+func NewNATSPublisher(rawURL, subject string, queueSize int) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "nats" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid nats url %q", rawURL)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if subject == "" {
+		subject = DefaultSubject
+	}
+
+	p := &NATSPublisher{addr: addr, subject: subject}
+	p.queue = newQueue(queueSize, p.send)
+	return p, nil
+}
+
+// Close delivers queued events, then closes the connection.
+func (p *NATSPublisher) Close() error {
+	p.queue.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn = nil
+	}
+	return nil
+}
+
+// send publishes one event, connecting first if needed.
+func (p *NATSPublisher) send(evt executor.Event) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		if err := p.connectLocked(); err != nil {
+			return err
+		}
+	}
+
+	msg := fmt.Sprintf("PUB %s.%s %d\r\n%s\r\n", p.subject, evt.Type, len(body), body)
+	if _, err := p.conn.Write([]byte(msg)); err != nil {
+		p.conn.Close()
+		p.conn = nil
+		return fmt.Errorf("failed to publish to nats: %w", err)
+	}
+	return nil
+}
+
+// connectLocked dials the server and completes the handshake.
+func (p *NATSPublisher) connectLocked() error {
+	conn, err := net.DialTimeout("tcp", p.addr, natsDialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(natsDialTimeout))
+
+	r := bufio.NewReader(conn)
+	line, err := r.ReadString('\n')
+	if err != nil || !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("nats handshake failed: expected INFO, got %q: %v", strings.TrimSpace(line), err)
+	}
+	if _, err := conn.Write([]byte("CONNECT {\"verbose\":false,\"pedantic\":false,\"name\":\"hdrp-orchestrator\"}\r\n")); err != nil {
+		conn.Close()
+		return fmt.Errorf("nats handshake failed: %w", err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	p.conn = conn
+	go p.readLoop(conn, r)
+	return nil
+}
+
+// readLoop answers server keepalives and logs protocol errors until the
+// connection closes.
+func (p *NATSPublisher) readLoop(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			p.mu.Lock()
+			if p.conn == conn {
+				conn.Close()
+				p.conn = nil
+			}
+			p.mu.Unlock()
+			return
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PING":
+			p.mu.Lock()
+			if p.conn == conn {
+				conn.Write([]byte("PONG\r\n"))
+			}
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			log.Printf("[Events] NATS server error: %s", line)
+		}
+	}
+}
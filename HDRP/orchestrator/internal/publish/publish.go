@@ -0,0 +1,168 @@
+// Package publish delivers executor run lifecycle events to external
+// systems such as message queues.
+package publish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"hdrp/internal/executor"
+)
+
+// Backend names accepted by New.
+const (
+	BackendNone = "none"
+	BackendLog  = "log"
+	BackendHTTP = "http"
+	BackendNATS = "nats"
+)
+
+// DefaultQueueSize is the number of events buffered for delivery when
+// Config.QueueSize is unset.
+const DefaultQueueSize = 1024
+
+// DefaultSubject prefixes NATS subjects when Config.Subject is unset.
+const DefaultSubject = "hdrp.events"
+
+// ErrQueueFull is returned by Publish when delivery has fallen behind and
+// the event was dropped.
+var ErrQueueFull = errors.New("event queue full")
+
+// ErrClosed is returned by Publish after Close.
+var ErrClosed = errors.New("publisher closed")
+
+// Config selects and configures a publisher backend.
+type Config struct {
+	Backend   string // none (default), log, http or nats
+	URL       string // http(s) endpoint, or nats://host:port
+	Subject   string // NATS subject prefix; events go to <prefix>.<event type>
+	QueueSize int    // Events buffered for delivery; <= 0 uses DefaultQueueSize
+
+	// HTTP delivery; zero values use the HTTPOptions defaults
+	Workers    int           // Concurrent deliveries
+	Timeout    time.Duration // Per delivery attempt
+	MaxRetries int           // Retries after a failed attempt; < 0 disables
+}
+
+// New returns the publisher for the configured backend.
+func New(cfg Config) (executor.EventPublisher, error) {
+	switch cfg.Backend {
+	case BackendNone, "":
+		return executor.NopPublisher{}, nil
+	case BackendLog:
+		return LogPublisher{}, nil
+	case BackendHTTP:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("http event publisher requires a url")
+		}
+		return NewHTTPPublisher(cfg.URL, nil, HTTPOptions{
+			QueueSize:  cfg.QueueSize,
+			Workers:    cfg.Workers,
+			Timeout:    cfg.Timeout,
+			MaxRetries: cfg.MaxRetries,
+		}), nil
+	case BackendNATS:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("nats event publisher requires a url")
+		}
+		p, err := NewNATSPublisher(cfg.URL, cfg.Subject, cfg.QueueSize)
+		if err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unsupported event publisher: %s", cfg.Backend)
+	}
+}
+
+// LogPublisher writes each event to the log as JSON.
+type LogPublisher struct{}
+
+// Publish logs the event.
+func (LogPublisher) Publish(evt executor.Event) error {
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	log.Printf("[Events] %s", body)
+	return nil
+}
+
+// Close does nothing.
+func (LogPublisher) Close() error { return nil }
+
+// queue delivers events on background goroutines, so Publish never waits
+// on the network. With one worker events are delivered in order. When
+// delivery falls behind, new events are dropped rather than stalling the
+// run.
+type queue struct {
+	mu     sync.Mutex
+	closed bool
+	events chan executor.Event
+	done   chan struct{}
+}
+
+// newQueue starts delivering queued events in order through send. Failed
+// deliveries are logged and dropped.
+func newQueue(size int, send func(executor.Event) error) *queue {
+	return newWorkerQueue(size, 1, send)
+}
+
+// newWorkerQueue is newQueue with up to workers deliveries in flight.
+func newWorkerQueue(size, workers int, send func(executor.Event) error) *queue {
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	q := &queue{events: make(chan executor.Event, size), done: make(chan struct{})}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for evt := range q.events {
+				if err := send(evt); err != nil {
+					log.Printf("[Events] Warning: dropped %s event for run %s: %v", evt.Type, evt.RunID, err)
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(q.done)
+	}()
+	return q
+}
+
+// Publish queues an event for delivery.
+func (q *queue) Publish(evt executor.Event) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	select {
+	case q.events <- evt:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting events and waits for queued ones to be delivered.
+func (q *queue) Close() error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+	q.mu.Unlock()
+	<-q.done
+	return nil
+}
@@ -0,0 +1,247 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hdrp/internal/clients"
+	"hdrp/internal/dag"
+	"hdrp/internal/executor"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		wantErr bool
+	}{
+		{cfg: Config{}},
+		{cfg: Config{Backend: BackendNone}},
+		{cfg: Config{Backend: BackendLog}},
+		{cfg: Config{Backend: BackendHTTP, URL: "http://localhost:1/events"}},
+		{cfg: Config{Backend: BackendHTTP}, wantErr: true},
+		{cfg: Config{Backend: BackendNATS, URL: "nats://localhost:4222"}},
+		{cfg: Config{Backend: BackendNATS, URL: "http://localhost:4222"}, wantErr: true},
+		{cfg: Config{Backend: "kafka"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		p, err := New(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("New(%+v) error = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+		}
+		if p != nil {
+			p.Close()
+		}
+	}
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	var mu sync.Mutex
+	var received []executor.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt executor.Event
+		if err := json.NewDecoder(r.Body).Decode(&evt); err != nil {
+			t.Errorf("Failed to decode event: %v", err)
+		}
+		mu.Lock()
+		received = append(received, evt)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPublisher(srv.URL, nil, HTTPOptions{})
+	for _, typ := range []executor.EventType{executor.EventRunStarted, executor.EventRunFinished} {
+		if err := p.Publish(executor.Event{Type: typ, RunID: "run-1"}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	p.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0].Type != executor.EventRunStarted || received[1].Type != executor.EventRunFinished {
+		t.Errorf("Unexpected events delivered in order: %+v", received)
+	}
+	if err := p.Publish(executor.Event{Type: executor.EventRunStarted}); err != ErrClosed {
+		t.Errorf("Publish after Close error = %v, want ErrClosed", err)
+	}
+}
+
+func TestQueueDropsWhenFull(t *testing.T) {
+	block := make(chan struct{})
+	q := newQueue(1, func(executor.Event) error {
+		<-block
+		return nil
+	})
+
+	var full bool
+	for i := 0; i < 3; i++ {
+		if err := q.Publish(executor.Event{Type: executor.EventNodeCompleted}); err == ErrQueueFull {
+			full = true
+		}
+	}
+	close(block)
+	q.Close()
+	if !full {
+		t.Error("Expected ErrQueueFull once the queue backed up")
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	// Minimal NATS server: send INFO, then collect PUB subjects and payloads
+	type pub struct{ subject, payload string }
+	pubs := make(chan pub, 4)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) != 3 || fields[0] != "PUB" {
+				continue
+			}
+			size, _ := strconv.Atoi(fields[2])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			pubs <- pub{subject: fields[1], payload: string(payload[:size])}
+		}
+	}()
+
+	p, err := NewNATSPublisher("nats://"+ln.Addr().String(), "", 0)
+	if err != nil {
+		t.Fatalf("NewNATSPublisher() error = %v", err)
+	}
+	if err := p.Publish(executor.Event{Type: executor.EventRunStarted, RunID: "run-1"}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	p.Close()
+
+	got := <-pubs
+	if got.subject != DefaultSubject+".run_started" {
+		t.Errorf("Published to %s, want %s.run_started", got.subject, DefaultSubject)
+	}
+	var evt executor.Event
+	if err := json.Unmarshal([]byte(got.payload), &evt); err != nil || evt.RunID != "run-1" {
+		t.Errorf("Unexpected payload %q: %v", got.payload, err)
+	}
+}
+
+func TestHTTPPublisherSlowEndpoint(t *testing.T) {
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "webhook.db"))
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var attempts, inFlight, maxInFlight int
+	var delivered []executor.EventType
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt executor.Event
+		json.NewDecoder(r.Body).Decode(&evt)
+		mu.Lock()
+		attempts++
+		first := attempts == 1
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		<-release
+		mu.Lock()
+		defer mu.Unlock()
+		inFlight--
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		delivered = append(delivered, evt.Type)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPublisher(srv.URL, nil, HTTPOptions{Workers: 2, MaxRetries: 1, RetryDelay: time.Millisecond})
+	exec := executor.NewDAGExecutor(&clients.ServiceClients{}, 1)
+	exec.SetUnknownTypePolicy(executor.UnknownTypeLenient)
+	exec.SetEventPublisher(p)
+
+	graph := &dag.Graph{
+		ID:     "webhook-graph",
+		Status: dag.StatusCreated,
+		Nodes:  []dag.Node{{ID: "step", Type: "manual", Status: dag.StatusCreated}},
+	}
+	start := time.Now()
+	result, err := exec.Execute(context.Background(), graph, "webhook-run")
+	if err != nil || !result.Success {
+		t.Fatalf("Execute() = %+v, %v", result, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run took %v with the webhook receiver blocked", elapsed)
+	}
+
+	// Both workers pick up an event while the receiver is still blocked
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		mu.Lock()
+		busy := inFlight
+		mu.Unlock()
+		if busy == 2 {
+			break
+		}
+	}
+	close(release)
+	p.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxInFlight != 2 {
+		t.Errorf("Expected 2 deliveries in flight, got %d", maxInFlight)
+	}
+	// run_started, node_completed and run_finished, one retried after the 503
+	if attempts != 4 || len(delivered) != 3 {
+		t.Errorf("Expected 4 attempts delivering 3 events, got %d attempts, delivered %v", attempts, delivered)
+	}
+}
+
+func TestHTTPPublisherPermanentFailure(t *testing.T) {
+	var mu sync.Mutex
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPublisher(srv.URL, nil, HTTPOptions{MaxRetries: 3, RetryDelay: time.Millisecond})
+	p.Publish(executor.Event{Type: executor.EventRunStarted, RunID: "run-1"})
+	p.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 1 {
+		t.Errorf("Expected a 400 response not to be retried, got %d attempts", attempts)
+	}
+}
@@ -1,6 +1,8 @@
 package retry
 
 import (
+	"container/list"
+	"log"
 	"sync"
 	"time"
 )
@@ -40,38 +42,99 @@ type CircuitBreaker struct {
 	minRequests      int           // Minimum requests before evaluating threshold
 	openTimeout      time.Duration // Time to wait before transitioning to half-open
 	halfOpenMaxTests int           // Max requests allowed in half-open state
+	maxOpenTimeout   time.Duration // Cap for the adaptive open timeout; 0 keeps openTimeout fixed
 
 	// State
 	state            CircuitState
-	failures         int
-	successes        int
+	outcomes         slidingCounts // Failures and successes within the sliding window
 	consecutiveSuccesses int // For half-open state
 	lastFailureTime  time.Time
 	openedAt         time.Time
+	reopens          int // Failed half-open tests since the circuit last closed
+
+	onTransition func(from, to CircuitState) // Called with the lock held; may be nil
+}
+
+// setState moves the breaker to a new state, reporting the change.
+// Must be called with lock held.
+func (cb *CircuitBreaker) setState(to CircuitState) {
+	from := cb.state
+	cb.state = to
+	if to == CircuitOpen {
+		cb.openedAt = time.Now()
+	}
+	if from != to && cb.onTransition != nil {
+		cb.onTransition(from, to)
+	}
 }
 
+// Default sliding window over which a breaker's failure rate is measured.
+const (
+	DefaultBreakerWindow  = time.Minute
+	DefaultBreakerBuckets = 10
+)
+
 // NewCircuitBreaker creates a new circuit breaker with default settings.
 func NewCircuitBreaker() *CircuitBreaker {
-	return &CircuitBreaker{
-		failureThreshold:  0.5,  // 50% failure rate
-		minRequests:      10,    // Need at least 10 requests
-		openTimeout:      30 * time.Second,
-		halfOpenMaxTests: 3,     // Allow 3 test requests
-		state:            CircuitClosed,
-	}
+	return NewCircuitBreakerWithConfig(0.5, 10, 30*time.Second) // 50% of at least 10 requests
 }
 
-// NewCircuitBreakerWithConfig creates a circuit breaker with custom settings.
+// NewCircuitBreakerWithConfig creates a circuit breaker with custom settings,
+// measuring the failure rate over the default sliding window.
 func NewCircuitBreakerWithConfig(failureThreshold float64, minRequests int, openTimeout time.Duration) *CircuitBreaker {
+	return NewCircuitBreakerWithWindow(DefaultBreakerWindow, DefaultBreakerBuckets, failureThreshold, minRequests, openTimeout)
+}
+
+// NewCircuitBreakerWithWindow creates a circuit breaker whose failure rate
+// only counts requests made within the last window, tracked in buckets of
+// window/buckets each. Outcomes age out a bucket at a time, so a burst of
+// old successes can't mask a recent run of failures. Non-positive window or
+// buckets use the defaults.
+func NewCircuitBreakerWithWindow(window time.Duration, buckets int, failureThreshold float64, minRequests int, openTimeout time.Duration) *CircuitBreaker {
 	return &CircuitBreaker{
 		failureThreshold:  failureThreshold,
 		minRequests:      minRequests,
 		openTimeout:      openTimeout,
-		halfOpenMaxTests: 3,
+		halfOpenMaxTests: 3, // Allow 3 test requests
+		outcomes:         newSlidingCounts(window, buckets),
 		state:            CircuitClosed,
 	}
 }
 
+// SetAdaptiveOpenTimeout makes the open timeout back off: each time a
+// half-open test fails and the circuit reopens, the wait before the next
+// test doubles, up to max. It drops back to the configured timeout once the
+// circuit closes. max <= 0 keeps the timeout fixed.
+func (cb *CircuitBreaker) SetAdaptiveOpenTimeout(max time.Duration) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.maxOpenTimeout = max
+}
+
+// OpenTimeout returns how long the circuit currently stays open before
+// allowing test requests.
+func (cb *CircuitBreaker) OpenTimeout() time.Duration {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	return cb.currentOpenTimeout()
+}
+
+// currentOpenTimeout applies the adaptive backoff to openTimeout.
+// Must be called with lock held.
+func (cb *CircuitBreaker) currentOpenTimeout() time.Duration {
+	if cb.maxOpenTimeout <= 0 {
+		return cb.openTimeout
+	}
+	timeout := cb.openTimeout
+	for i := 0; i < cb.reopens && timeout < cb.maxOpenTimeout; i++ {
+		timeout *= 2
+	}
+	if timeout > cb.maxOpenTimeout {
+		timeout = cb.maxOpenTimeout
+	}
+	return timeout
+}
+
 // ShouldAllow determines if a request should be allowed through.
 func (cb *CircuitBreaker) ShouldAllow() bool {
 	cb.mu.Lock()
@@ -83,8 +146,8 @@ func (cb *CircuitBreaker) ShouldAllow() bool {
 
 	case CircuitOpen:
 		// Check if we should transition to half-open
-		if time.Since(cb.openedAt) >= cb.openTimeout {
-			cb.state = CircuitHalfOpen
+		if time.Since(cb.openedAt) >= cb.currentOpenTimeout() {
+			cb.setState(CircuitHalfOpen)
 			cb.consecutiveSuccesses = 0
 			return true
 		}
@@ -104,14 +167,14 @@ func (cb *CircuitBreaker) RecordSuccess() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.successes++
+	cb.outcomes.record(time.Now(), false)
 
 	switch cb.state {
 	case CircuitHalfOpen:
 		cb.consecutiveSuccesses++
 		// If enough consecutive successes, close the circuit
 		if cb.consecutiveSuccesses >= cb.halfOpenMaxTests {
-			cb.state = CircuitClosed
+			cb.setState(CircuitClosed)
 			cb.reset()
 		}
 
@@ -122,15 +185,15 @@ func (cb *CircuitBreaker) RecordSuccess() {
 	}
 }
 
-// checkThreshold evaluates the failure rate and opens the circuit if needed.
-// Must be called with lock held.
+// checkThreshold evaluates the failure rate within the window and opens the
+// circuit if needed. Must be called with lock held.
 func (cb *CircuitBreaker) checkThreshold() {
-	totalRequests := cb.failures + cb.successes
+	failures, successes := cb.outcomes.counts(time.Now())
+	totalRequests := failures + successes
 	if totalRequests >= cb.minRequests {
-		failureRate := float64(cb.failures) / float64(totalRequests)
+		failureRate := float64(failures) / float64(totalRequests)
 		if failureRate >= cb.failureThreshold {
-			cb.state = CircuitOpen
-			cb.openedAt = time.Now()
+			cb.setState(CircuitOpen)
 		}
 	}
 }
@@ -140,14 +203,15 @@ func (cb *CircuitBreaker) RecordFailure() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	cb.failures++
 	cb.lastFailureTime = time.Now()
+	cb.outcomes.record(cb.lastFailureTime, true)
 
 	switch cb.state {
 	case CircuitHalfOpen:
-		// Any failure in half-open immediately reopens the circuit
-		cb.state = CircuitOpen
-		cb.openedAt = time.Now()
+		// Any failure in half-open immediately reopens the circuit, for
+		// longer each time when the open timeout is adaptive
+		cb.reopens++
+		cb.setState(CircuitOpen)
 		cb.consecutiveSuccesses = 0
 
 	case CircuitClosed:
@@ -163,55 +227,203 @@ func (cb *CircuitBreaker) GetState() CircuitState {
 	return cb.state
 }
 
-// GetStats returns current statistics.
+// GetStats returns the failures and successes within the window, and the
+// current state.
 func (cb *CircuitBreaker) GetStats() (failures, successes int, state CircuitState) {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return cb.failures, cb.successes, cb.state
+	failures, successes = cb.outcomes.counts(time.Now())
+	return failures, successes, cb.state
+}
+
+// BreakerSnapshot is the state of a circuit breaker, for persisting it
+// across restarts. Configuration is not included, and Failures and Successes
+// are the counts within the window when the snapshot was taken.
+type BreakerSnapshot struct {
+	State                CircuitState `json:"state"`
+	Failures             int          `json:"failures"`
+	Successes            int          `json:"successes"`
+	ConsecutiveSuccesses int          `json:"consecutive_successes"`
+	Reopens              int          `json:"reopens"`
+	LastFailureTime      time.Time    `json:"last_failure_time"`
+	OpenedAt             time.Time    `json:"opened_at"`
+}
+
+// GetSnapshot returns the breaker's current state and counters.
+func (cb *CircuitBreaker) GetSnapshot() BreakerSnapshot {
+	cb.mu.RLock()
+	defer cb.mu.RUnlock()
+	failures, successes := cb.outcomes.counts(time.Now())
+	return BreakerSnapshot{
+		State:                cb.state,
+		Failures:             failures,
+		Successes:            successes,
+		ConsecutiveSuccesses: cb.consecutiveSuccesses,
+		Reopens:              cb.reopens,
+		LastFailureTime:      cb.lastFailureTime,
+		OpenedAt:             cb.openedAt,
+	}
+}
+
+// Restore replaces the breaker's state and counters with a snapshot. An
+// open breaker stays open until its open timeout has passed since the
+// snapshot's OpenedAt, so time spent down counts towards it. The snapshot's
+// counts are restored as if recorded now, and age out of the window from
+// there.
+func (cb *CircuitBreaker) Restore(snapshot BreakerSnapshot) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	from := cb.state
+	cb.state = snapshot.State
+	cb.outcomes.set(time.Now(), snapshot.Failures, snapshot.Successes)
+	cb.consecutiveSuccesses = snapshot.ConsecutiveSuccesses
+	cb.reopens = snapshot.Reopens
+	cb.lastFailureTime = snapshot.LastFailureTime
+	cb.openedAt = snapshot.OpenedAt
+	if from != cb.state && cb.onTransition != nil {
+		cb.onTransition(from, cb.state)
+	}
 }
 
 // reset clears the counters (must be called with lock held).
 func (cb *CircuitBreaker) reset() {
-	cb.failures = 0
-	cb.successes = 0
+	cb.outcomes.reset()
 	cb.consecutiveSuccesses = 0
+	cb.reopens = 0
 }
 
+// DefaultMaxServiceBreakers bounds how many service types PerServiceBreakers
+// tracks before evicting the least recently used.
+const DefaultMaxServiceBreakers = 256
+
 // PerServiceBreakers manages circuit breakers for different service types.
+// It tracks at most maxBreakers service types; when a new type would exceed
+// the cap, the least recently used closed breaker is evicted (or the least
+// recently used breaker if every one is open), so callers passing unbounded
+// distinct service types can't grow it without limit.
 type PerServiceBreakers struct {
-	mu       sync.RWMutex
-	breakers map[string]*CircuitBreaker
+	mu           sync.Mutex
+	breakers     map[string]*list.Element // serviceType -> element in lru
+	lru          *list.List               // *serviceBreaker, most recently used first
+	maxBreakers  int
+	onTransition StateChangeFunc
+}
+
+// StateChangeFunc is called when a service's breaker changes state. It runs
+// while the breaker is locked, so it must be quick and must not call back
+// into the breaker.
+type StateChangeFunc func(serviceType string, from, to CircuitState)
+
+type serviceBreaker struct {
+	serviceType string
+	breaker     *CircuitBreaker
 }
 
 // NewPerServiceBreakers creates a new manager for per-service circuit breakers.
 func NewPerServiceBreakers() *PerServiceBreakers {
 	return &PerServiceBreakers{
-		breakers: make(map[string]*CircuitBreaker),
+		breakers:    make(map[string]*list.Element),
+		lru:         list.New(),
+		maxBreakers: DefaultMaxServiceBreakers,
+	}
+}
+
+// SetMaxBreakers changes how many service types are tracked, evicting
+// breakers immediately if the map is over the new cap. max <= 0 restores
+// DefaultMaxServiceBreakers.
+func (psb *PerServiceBreakers) SetMaxBreakers(max int) {
+	if max <= 0 {
+		max = DefaultMaxServiceBreakers
 	}
+	psb.mu.Lock()
+	defer psb.mu.Unlock()
+	psb.maxBreakers = max
+	for psb.lru.Len() > psb.maxBreakers {
+		psb.evictLocked()
+	}
+}
+
+// Len returns the number of service types currently tracked.
+func (psb *PerServiceBreakers) Len() int {
+	psb.mu.Lock()
+	defer psb.mu.Unlock()
+	return psb.lru.Len()
 }
 
 // GetBreaker returns the circuit breaker for a service type, creating it if needed.
 func (psb *PerServiceBreakers) GetBreaker(serviceType string) *CircuitBreaker {
-	psb.mu.RLock()
-	breaker, exists := psb.breakers[serviceType]
-	psb.mu.RUnlock()
+	psb.mu.Lock()
+	defer psb.mu.Unlock()
+
+	if elem, exists := psb.breakers[serviceType]; exists {
+		psb.lru.MoveToFront(elem)
+		return elem.Value.(*serviceBreaker).breaker
+	}
 
-	if exists {
-		return breaker
+	for psb.lru.Len() >= psb.maxBreakers {
+		psb.evictLocked()
 	}
 
-	// Create new breaker
+	breaker := NewCircuitBreaker()
+	breaker.onTransition = psb.transitionHook(serviceType)
+	psb.breakers[serviceType] = psb.lru.PushFront(&serviceBreaker{serviceType: serviceType, breaker: breaker})
+	return breaker
+}
+
+// OnStateChange registers fn to be called on every breaker state change,
+// for breakers that exist now and ones created later. A nil fn stops
+// reporting.
+func (psb *PerServiceBreakers) OnStateChange(fn StateChangeFunc) {
 	psb.mu.Lock()
 	defer psb.mu.Unlock()
+	psb.onTransition = fn
+	for elem := psb.lru.Front(); elem != nil; elem = elem.Next() {
+		sb := elem.Value.(*serviceBreaker)
+		sb.breaker.mu.Lock()
+		sb.breaker.onTransition = psb.transitionHook(sb.serviceType)
+		sb.breaker.mu.Unlock()
+	}
+}
 
-	// Double-check after acquiring write lock
-	if breaker, exists := psb.breakers[serviceType]; exists {
-		return breaker
+// transitionHook binds the state change callback to one service type.
+// Must be called with lock held.
+func (psb *PerServiceBreakers) transitionHook(serviceType string) func(from, to CircuitState) {
+	fn := psb.onTransition
+	if fn == nil {
+		return nil
 	}
+	return func(from, to CircuitState) { fn(serviceType, from, to) }
+}
 
-	breaker = NewCircuitBreaker()
-	psb.breakers[serviceType] = breaker
-	return breaker
+// States returns the current state of every tracked breaker by service type.
+func (psb *PerServiceBreakers) States() map[string]CircuitState {
+	psb.mu.Lock()
+	defer psb.mu.Unlock()
+	states := make(map[string]CircuitState, psb.lru.Len())
+	for elem := psb.lru.Front(); elem != nil; elem = elem.Next() {
+		sb := elem.Value.(*serviceBreaker)
+		states[sb.serviceType] = sb.breaker.GetState()
+	}
+	return states
+}
+
+// evictLocked drops the least recently used closed breaker, or the least
+// recently used breaker if all are open. Must be called with lock held.
+func (psb *PerServiceBreakers) evictLocked() {
+	victim := psb.lru.Back()
+	for elem := psb.lru.Back(); elem != nil; elem = elem.Prev() {
+		if elem.Value.(*serviceBreaker).breaker.GetState() != CircuitOpen {
+			victim = elem
+			break
+		}
+	}
+	if victim == nil {
+		return
+	}
+	evicted := psb.lru.Remove(victim).(*serviceBreaker)
+	delete(psb.breakers, evicted.serviceType)
+	log.Printf("[CircuitBreaker] Warning: more than %d service types seen, evicted breaker for %q", psb.maxBreakers, evicted.serviceType)
 }
 
 // ShouldAllow checks if requests to a service type should be allowed.
@@ -228,3 +440,33 @@ func (psb *PerServiceBreakers) RecordSuccess(serviceType string) {
 func (psb *PerServiceBreakers) RecordFailure(serviceType string) {
 	psb.GetBreaker(serviceType).RecordFailure()
 }
+
+// GetSnapshot returns the state of every tracked breaker by service type.
+func (psb *PerServiceBreakers) GetSnapshot() map[string]BreakerSnapshot {
+	psb.mu.Lock()
+	defer psb.mu.Unlock()
+	snapshots := make(map[string]BreakerSnapshot, psb.lru.Len())
+	for elem := psb.lru.Front(); elem != nil; elem = elem.Next() {
+		sb := elem.Value.(*serviceBreaker)
+		snapshots[sb.serviceType] = sb.breaker.GetSnapshot()
+	}
+	return snapshots
+}
+
+// Restore applies snapshots to the breakers of their service types,
+// creating breakers as needed. Breakers without a snapshot are unchanged.
+func (psb *PerServiceBreakers) Restore(snapshots map[string]BreakerSnapshot) {
+	for serviceType, snapshot := range snapshots {
+		psb.GetBreaker(serviceType).Restore(snapshot)
+	}
+}
+
+// BreakerStore persists circuit breaker state so it survives restarts.
+type BreakerStore interface {
+	// SaveBreakers stores the state of each service type's breaker,
+	// replacing what was stored for those service types.
+	SaveBreakers(snapshots map[string]BreakerSnapshot) error
+
+	// LoadBreakers retrieves every stored breaker state.
+	LoadBreakers() (map[string]BreakerSnapshot, error)
+}
@@ -1,6 +1,9 @@
 package retry
 
 import (
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -168,3 +171,225 @@ func TestPerServiceBreakersConcurrent(t *testing.T) {
 		t.Errorf("Expected 1000 successes, got %d", successes)
 	}
 }
+
+// TestCircuitBreakerSlidingWindow verifies successes that have aged out of
+// the window don't stop a recent run of failures from opening the circuit.
+func TestCircuitBreakerSlidingWindow(t *testing.T) {
+	const window = 200 * time.Millisecond
+
+	// Within the window, earlier successes dilute the failures
+	cb := NewCircuitBreakerWithWindow(window, 4, 0.5, 4, time.Minute)
+	for i := 0; i < 20; i++ {
+		cb.RecordSuccess()
+	}
+	for i := 0; i < 4; i++ {
+		cb.RecordFailure()
+	}
+	if cb.GetState() != CircuitClosed {
+		t.Fatalf("Expected 4 failures in 24 requests to keep the circuit closed, got %v", cb.GetState())
+	}
+
+	// Once they age out, the same failures open it
+	cb = NewCircuitBreakerWithWindow(window, 4, 0.5, 4, time.Minute)
+	for i := 0; i < 20; i++ {
+		cb.RecordSuccess()
+	}
+	time.Sleep(window + window/4)
+	if failures, successes, _ := cb.GetStats(); failures != 0 || successes != 0 {
+		t.Fatalf("Expected an empty window, got %d failures and %d successes", failures, successes)
+	}
+	for i := 0; i < 4; i++ {
+		cb.RecordFailure()
+	}
+	if cb.GetState() != CircuitOpen {
+		t.Errorf("Expected the recent failures to open the circuit, got %v", cb.GetState())
+	}
+}
+
+func TestCircuitBreakerAdaptiveOpenTimeout(t *testing.T) {
+	cb := NewCircuitBreakerWithConfig(0.5, 2, time.Millisecond)
+	cb.SetAdaptiveOpenTimeout(4 * time.Millisecond)
+	cb.RecordFailure()
+	cb.RecordFailure()
+
+	// Each failed half-open test doubles the timeout, up to the cap
+	for _, want := range []time.Duration{2, 4, 4} {
+		time.Sleep(cb.OpenTimeout())
+		if !cb.ShouldAllow() {
+			t.Fatal("Expected a test request after the open timeout")
+		}
+		cb.RecordFailure()
+		if got := cb.OpenTimeout(); got != want*time.Millisecond {
+			t.Errorf("OpenTimeout = %v, want %v", got, want*time.Millisecond)
+		}
+	}
+
+	// Closing the circuit restores the configured timeout
+	time.Sleep(cb.OpenTimeout())
+	for i := 0; i < 3; i++ {
+		cb.ShouldAllow()
+		cb.RecordSuccess()
+	}
+	if cb.GetState() != CircuitClosed || cb.OpenTimeout() != time.Millisecond {
+		t.Errorf("Expected closed circuit with 1ms timeout, got %v and %v", cb.GetState(), cb.OpenTimeout())
+	}
+}
+
+func TestCircuitBreakerAdaptiveOpenTimeoutConcurrent(t *testing.T) {
+	const base, limit = time.Millisecond, 8 * time.Millisecond
+	cb := NewCircuitBreakerWithConfig(0.5, 2, base)
+	cb.SetAdaptiveOpenTimeout(limit)
+
+	// Enough failures keep the circuit cycling through open and half-open,
+	// so reopens are counted while other goroutines read the timeout
+	var wg sync.WaitGroup
+	var outOfRange atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				if cb.ShouldAllow() {
+					if (worker+j)%3 == 0 {
+						cb.RecordFailure()
+					} else {
+						cb.RecordSuccess()
+					}
+				}
+				if timeout := cb.OpenTimeout(); timeout < base || timeout > limit {
+					outOfRange.Add(1)
+				}
+				if j%50 == 0 {
+					time.Sleep(time.Millisecond)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := outOfRange.Load(); n > 0 {
+		t.Errorf("OpenTimeout was outside [%v, %v] %d times", base, limit, n)
+	}
+
+	// Whatever state the hammering left, the breaker still recovers
+	time.Sleep(limit)
+	for i := 0; i < 3; i++ {
+		cb.ShouldAllow()
+		cb.RecordSuccess()
+	}
+	if state := cb.GetState(); state != CircuitClosed {
+		t.Fatalf("Expected circuit to close after successful tests, got %v", state)
+	}
+	if timeout := cb.OpenTimeout(); timeout != base {
+		t.Errorf("Expected timeout reset to %v once closed, got %v", base, timeout)
+	}
+}
+
+func TestPerServiceBreakersBounded(t *testing.T) {
+	psb := NewPerServiceBreakers()
+	psb.SetMaxBreakers(3)
+
+	// Keep "researcher" open; it should survive eviction of closed breakers
+	open := psb.GetBreaker("researcher")
+	for i := 0; i < 10; i++ {
+		open.RecordFailure()
+	}
+	if open.GetState() != CircuitOpen {
+		t.Fatalf("Expected researcher breaker to be open")
+	}
+
+	for i := 0; i < 1000; i++ {
+		psb.RecordSuccess(fmt.Sprintf("dynamic-type-%d", i))
+		if n := psb.Len(); n > 3 {
+			t.Fatalf("Tracking %d breakers after %d types, want at most 3", n, i+1)
+		}
+	}
+
+	if psb.GetBreaker("researcher") != open {
+		t.Error("Open breaker was evicted while closed ones remained")
+	}
+	if psb.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", psb.Len())
+	}
+}
+
+func TestPerServiceBreakersStateChanges(t *testing.T) {
+	psb := NewPerServiceBreakers()
+	psb.GetBreaker("critic").openTimeout = 0 // Existing breakers get the callback too
+
+	var changes []string
+	psb.OnStateChange(func(serviceType string, from, to CircuitState) {
+		changes = append(changes, fmt.Sprintf("%s:%v->%v", serviceType, from, to))
+	})
+
+	for i := 0; i < 10; i++ {
+		psb.RecordFailure("critic")
+		psb.RecordSuccess("researcher")
+	}
+	if states := psb.States(); states["critic"] != CircuitOpen || states["researcher"] != CircuitClosed {
+		t.Errorf("States() = %v, want critic open and researcher closed", states)
+	}
+
+	psb.ShouldAllow("critic")
+	for i := 0; i < 3; i++ {
+		psb.RecordSuccess("critic")
+	}
+
+	want := []string{"critic:Closed->Open", "critic:Open->HalfOpen", "critic:HalfOpen->Closed"}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("State changes = %v, want %v", changes, want)
+	}
+}
+
+// TestCircuitBreakerSnapshotRestore verifies an open breaker restored into a
+// fresh breaker stays open and keeps its counters.
+func TestCircuitBreakerSnapshotRestore(t *testing.T) {
+	cb := NewCircuitBreakerWithConfig(0.5, 10, time.Minute)
+	for i := 0; i < 10; i++ {
+		cb.RecordFailure()
+	}
+	if cb.GetState() != CircuitOpen {
+		t.Fatalf("Expected the breaker to open, got %v", cb.GetState())
+	}
+	snapshot := cb.GetSnapshot()
+	if snapshot.OpenedAt.IsZero() {
+		t.Error("Snapshot of an open breaker has no OpenedAt")
+	}
+
+	restored := NewCircuitBreakerWithConfig(0.5, 10, time.Minute)
+	restored.Restore(snapshot)
+	if restored.GetState() != CircuitOpen {
+		t.Fatalf("Restored breaker state = %v, want Open", restored.GetState())
+	}
+	if restored.ShouldAllow() {
+		t.Error("Restored open breaker allowed a request")
+	}
+	if got := restored.GetSnapshot(); got != snapshot {
+		t.Errorf("Restored snapshot = %+v, want %+v", got, snapshot)
+	}
+}
+
+func TestPerServiceBreakersSnapshotRestore(t *testing.T) {
+	psb := NewPerServiceBreakers()
+	for i := 0; i < 10; i++ {
+		psb.RecordFailure("critic")
+		psb.RecordSuccess("researcher")
+	}
+
+	restored := NewPerServiceBreakers()
+	var changes []string
+	restored.OnStateChange(func(serviceType string, from, to CircuitState) {
+		changes = append(changes, fmt.Sprintf("%s:%v->%v", serviceType, from, to))
+	})
+	restored.Restore(psb.GetSnapshot())
+
+	if states := restored.States(); states["critic"] != CircuitOpen || states["researcher"] != CircuitClosed {
+		t.Errorf("States() = %v, want critic open and researcher closed", states)
+	}
+	if restored.ShouldAllow("critic") {
+		t.Error("Restored critic breaker allowed a request")
+	}
+	if want := []string{"critic:Closed->Open"}; fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("State changes = %v, want %v", changes, want)
+	}
+}
@@ -21,6 +21,9 @@ const (
 	ErrorTypeTransient
 	// ErrorTypePermanent represents errors that will not succeed even with retries.
 	ErrorTypePermanent
+	// ErrorTypeUpstream represents failures caused by unusable parent output.
+	// Retrying the node alone won't help, but re-running its parents might.
+	ErrorTypeUpstream
 )
 
 // String returns the string representation of ErrorType.
@@ -30,6 +33,8 @@ func (e ErrorType) String() string {
 		return "Transient"
 	case ErrorTypePermanent:
 		return "Permanent"
+	case ErrorTypeUpstream:
+		return "Upstream"
 	default:
 		return "Unknown"
 	}
@@ -41,6 +46,21 @@ func ClassifyError(err error) ErrorType {
 		return ErrorTypePermanent // No error means no retry
 	}
 
+	// Upstream data problems are checked first since they may wrap any error
+	var upstreamErr *UpstreamDataError
+	if errors.As(err, &upstreamErr) {
+		return ErrorTypeUpstream
+	}
+
+	// Rejected results say for themselves whether a retry may help
+	var resultErr *InvalidResultError
+	if errors.As(err, &resultErr) {
+		if resultErr.Retryable {
+			return ErrorTypeTransient
+		}
+		return ErrorTypePermanent
+	}
+
 	// Context-related errors
 	if errors.Is(err, context.DeadlineExceeded) {
 		return ErrorTypeTransient // Timeout might work with more time
@@ -109,6 +129,7 @@ func ClassifyError(err error) ErrorType {
 		"bad request",
 		"missing",
 		"malformed",
+		"unknown node type",
 	}
 	
 	for _, pattern := range permanentPatterns {
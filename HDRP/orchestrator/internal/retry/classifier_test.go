@@ -130,6 +130,7 @@ func TestIsRetryable(t *testing.T) {
 		{"PermanentError", context.Canceled, false},
 		{"TimeoutString", errors.New("timeout occurred"), true},
 		{"ValidationError", errors.New("validation failed"), false},
+		{"UpstreamError", &UpstreamDataError{NodeIDs: []string{"r1"}, Reason: "no claims"}, false},
 	}
 
 	for _, tt := range tests {
@@ -141,3 +142,36 @@ func TestIsRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestClassifyUpstreamErrors(t *testing.T) {
+	// Wrapped upstream errors win over string heuristics like "missing"
+	err := fmt.Errorf("critic failed: %w", &UpstreamDataError{
+		NodeIDs: []string{"researcher1", "researcher2"},
+		Reason:  "missing claims",
+	})
+
+	if got := ClassifyError(err); got != ErrorTypeUpstream {
+		t.Errorf("Expected %v, got %v", ErrorTypeUpstream, got)
+	}
+
+	nodes := UpstreamNodes(err)
+	if len(nodes) != 2 || nodes[0] != "researcher1" || nodes[1] != "researcher2" {
+		t.Errorf("Unexpected upstream nodes: %v", nodes)
+	}
+	if UpstreamNodes(errors.New("other")) != nil {
+		t.Error("Expected no upstream nodes for unrelated error")
+	}
+}
+
+func TestClassifyInvalidResultErrors(t *testing.T) {
+	// Retryability comes from the error rather than the default guess
+	retryable := fmt.Errorf("researcher: %w", &InvalidResultError{Reason: "no claims", Retryable: true})
+	if got := ClassifyError(retryable); got != ErrorTypeTransient {
+		t.Errorf("Expected %v, got %v", ErrorTypeTransient, got)
+	}
+
+	permanent := &InvalidResultError{Reason: "empty report"}
+	if got := ClassifyError(permanent); got != ErrorTypePermanent {
+		t.Errorf("Expected %v, got %v", ErrorTypePermanent, got)
+	}
+}
@@ -0,0 +1,126 @@
+package retry
+
+import (
+	"sort"
+	"sync"
+)
+
+// DefaultHealthWindow is the number of recent outcomes tracked per service type.
+const DefaultHealthWindow = 100
+
+// ServiceHealth summarizes recent outcomes for one service type.
+type ServiceHealth struct {
+	ServiceType  string  `json:"service_type"`
+	SuccessRatio float64 `json:"success_ratio"` // 1.0 when there are no samples
+	Samples      int     `json:"samples"`
+	Successes    int     `json:"successes"`
+	Failures     int     `json:"failures"`
+}
+
+// outcomeWindow is a fixed-size ring of the most recent outcomes.
+type outcomeWindow struct {
+	outcomes  []bool // true = success
+	next      int
+	count     int
+	successes int
+}
+
+func (w *outcomeWindow) record(success bool) {
+	if w.count == len(w.outcomes) {
+		// Full: drop the oldest outcome, which is about to be overwritten
+		if w.outcomes[w.next] {
+			w.successes--
+		}
+	} else {
+		w.count++
+	}
+
+	w.outcomes[w.next] = success
+	if success {
+		w.successes++
+	}
+	w.next = (w.next + 1) % len(w.outcomes)
+}
+
+// ServiceHealthTracker keeps a rolling success ratio per service type over
+// the last N outcomes. Unlike circuit breakers it never blocks requests; it
+// exists so callers can observe and adapt to service health.
+type ServiceHealthTracker struct {
+	mu       sync.RWMutex
+	window   int
+	services map[string]*outcomeWindow
+}
+
+// NewServiceHealthTracker creates a tracker that remembers the last window
+// outcomes per service type. window <= 0 uses DefaultHealthWindow.
+func NewServiceHealthTracker(window int) *ServiceHealthTracker {
+	if window <= 0 {
+		window = DefaultHealthWindow
+	}
+	return &ServiceHealthTracker{
+		window:   window,
+		services: make(map[string]*outcomeWindow),
+	}
+}
+
+// RecordSuccess records a successful call to a service type.
+func (t *ServiceHealthTracker) RecordSuccess(serviceType string) {
+	t.record(serviceType, true)
+}
+
+// RecordFailure records a failed call to a service type.
+func (t *ServiceHealthTracker) RecordFailure(serviceType string) {
+	t.record(serviceType, false)
+}
+
+func (t *ServiceHealthTracker) record(serviceType string, success bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.services[serviceType]
+	if !ok {
+		w = &outcomeWindow{outcomes: make([]bool, t.window)}
+		t.services[serviceType] = w
+	}
+	w.record(success)
+}
+
+// GetHealth returns the current success ratio and sample count for a service type.
+func (t *ServiceHealthTracker) GetHealth(serviceType string) ServiceHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.healthLocked(serviceType)
+}
+
+// AllHealth returns health for every service type seen, sorted by type.
+func (t *ServiceHealthTracker) AllHealth() []ServiceHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	types := make([]string, 0, len(t.services))
+	for serviceType := range t.services {
+		types = append(types, serviceType)
+	}
+	sort.Strings(types)
+
+	health := make([]ServiceHealth, 0, len(types))
+	for _, serviceType := range types {
+		health = append(health, t.healthLocked(serviceType))
+	}
+	return health
+}
+
+func (t *ServiceHealthTracker) healthLocked(serviceType string) ServiceHealth {
+	health := ServiceHealth{ServiceType: serviceType, SuccessRatio: 1.0}
+
+	w, ok := t.services[serviceType]
+	if !ok || w.count == 0 {
+		return health
+	}
+
+	health.Samples = w.count
+	health.Successes = w.successes
+	health.Failures = w.count - w.successes
+	health.SuccessRatio = float64(w.successes) / float64(w.count)
+	return health
+}
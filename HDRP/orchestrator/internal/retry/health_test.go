@@ -0,0 +1,74 @@
+package retry
+
+import "testing"
+
+func TestServiceHealthTracker_Ratio(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      int
+		outcomes    []bool
+		wantRatio   float64
+		wantSamples int
+	}{
+		{name: "No samples", window: 10, outcomes: nil, wantRatio: 1.0, wantSamples: 0},
+		{name: "All successes", window: 10, outcomes: []bool{true, true, true}, wantRatio: 1.0, wantSamples: 3},
+		{name: "Mixed", window: 10, outcomes: []bool{true, false, true, false}, wantRatio: 0.5, wantSamples: 4},
+		{
+			// The window keeps the last 4: false, true, true, true
+			name:        "Window slides",
+			window:      4,
+			outcomes:    []bool{false, false, false, true, true, true},
+			wantRatio:   0.75,
+			wantSamples: 4,
+		},
+		{
+			name:        "Old successes expire",
+			window:      3,
+			outcomes:    []bool{true, true, true, false, false, false},
+			wantRatio:   0,
+			wantSamples: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewServiceHealthTracker(tt.window)
+			for _, success := range tt.outcomes {
+				if success {
+					tracker.RecordSuccess("researcher")
+				} else {
+					tracker.RecordFailure("researcher")
+				}
+			}
+
+			health := tracker.GetHealth("researcher")
+			if health.SuccessRatio != tt.wantRatio {
+				t.Errorf("SuccessRatio = %v, want %v", health.SuccessRatio, tt.wantRatio)
+			}
+			if health.Samples != tt.wantSamples {
+				t.Errorf("Samples = %d, want %d", health.Samples, tt.wantSamples)
+			}
+			if health.Successes+health.Failures != health.Samples {
+				t.Errorf("Successes (%d) + Failures (%d) != Samples (%d)", health.Successes, health.Failures, health.Samples)
+			}
+		})
+	}
+}
+
+func TestServiceHealthTracker_SeparatesServiceTypes(t *testing.T) {
+	tracker := NewServiceHealthTracker(0)
+	tracker.RecordSuccess("researcher")
+	tracker.RecordFailure("critic")
+	tracker.RecordFailure("critic")
+
+	all := tracker.AllHealth()
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 service types, got %d", len(all))
+	}
+	if all[0].ServiceType != "critic" || all[0].SuccessRatio != 0 || all[0].Samples != 2 {
+		t.Errorf("Unexpected critic health: %+v", all[0])
+	}
+	if all[1].ServiceType != "researcher" || all[1].SuccessRatio != 1 || all[1].Samples != 1 {
+		t.Errorf("Unexpected researcher health: %+v", all[1])
+	}
+}
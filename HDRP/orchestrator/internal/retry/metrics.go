@@ -3,6 +3,7 @@ package retry
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 // NodeMetrics tracks retry metrics for a single node.
@@ -13,13 +14,21 @@ type NodeMetrics struct {
 	FailureCount      int
 	TransientErrors   int
 	PermanentErrors   int
+	UpstreamErrors    int
+	UpstreamRetries   int // Parent re-runs triggered by this node's upstream failures
 	CircuitBreakerHits int
+	AttemptTime        time.Duration // Sum of attempt execution durations
+	RetryDelay         time.Duration // Sum of backoff delays waited between attempts
+	WallTime           time.Duration // Total time from first attempt to final outcome
 }
 
 // RetryMetrics tracks retry statistics across all nodes in an execution.
 type RetryMetrics struct {
 	mu          sync.RWMutex
 	nodeMetrics map[string]*NodeMetrics
+
+	activeBackoffs int // Nodes currently waiting out a retry backoff
+	peakBackoffs   int
 }
 
 // NewRetryMetrics creates a new metrics tracker.
@@ -68,6 +77,8 @@ func (rm *RetryMetrics) RecordFailure(nodeID string, errorType ErrorType) {
 		metrics.TransientErrors++
 	case ErrorTypePermanent:
 		metrics.PermanentErrors++
+	case ErrorTypeUpstream:
+		metrics.UpstreamErrors++
 	}
 }
 
@@ -82,6 +93,87 @@ func (rm *RetryMetrics) RecordCircuitBreakerHit(nodeID string) {
 	rm.nodeMetrics[nodeID].CircuitBreakerHits++
 }
 
+// RecordUpstreamRetry records a parent re-run triggered by a node's upstream failure.
+func (rm *RetryMetrics) RecordUpstreamRetry(nodeID string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.nodeMetrics[nodeID] == nil {
+		rm.nodeMetrics[nodeID] = &NodeMetrics{NodeID: nodeID}
+	}
+	rm.nodeMetrics[nodeID].UpstreamRetries++
+}
+
+// RecordAttemptDuration adds the execution time of a single attempt.
+func (rm *RetryMetrics) RecordAttemptDuration(nodeID string, d time.Duration) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.nodeMetrics[nodeID] == nil {
+		rm.nodeMetrics[nodeID] = &NodeMetrics{NodeID: nodeID}
+	}
+	rm.nodeMetrics[nodeID].AttemptTime += d
+}
+
+// RecordRetryDelay adds a backoff delay waited before retrying a node.
+func (rm *RetryMetrics) RecordRetryDelay(nodeID string, d time.Duration) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.nodeMetrics[nodeID] == nil {
+		rm.nodeMetrics[nodeID] = &NodeMetrics{NodeID: nodeID}
+	}
+	rm.nodeMetrics[nodeID].RetryDelay += d
+}
+
+// RecordWallTime adds the end-to-end time spent executing a node, including retries.
+func (rm *RetryMetrics) RecordWallTime(nodeID string, d time.Duration) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if rm.nodeMetrics[nodeID] == nil {
+		rm.nodeMetrics[nodeID] = &NodeMetrics{NodeID: nodeID}
+	}
+	rm.nodeMetrics[nodeID].WallTime += d
+}
+
+// RecordBackoffStart marks a node as waiting out a retry backoff.
+func (rm *RetryMetrics) RecordBackoffStart() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.activeBackoffs++
+	if rm.activeBackoffs > rm.peakBackoffs {
+		rm.peakBackoffs = rm.activeBackoffs
+	}
+}
+
+// RecordBackoffEnd marks a node as done waiting out a retry backoff.
+func (rm *RetryMetrics) RecordBackoffEnd() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	rm.activeBackoffs--
+}
+
+// PeakConcurrentBackoffs returns the most nodes that were backing off at once.
+func (rm *RetryMetrics) PeakConcurrentBackoffs() int {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.peakBackoffs
+}
+
+// TotalAttempts returns the number of attempts recorded across all nodes.
+func (rm *RetryMetrics) TotalAttempts() int {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	total := 0
+	for _, metrics := range rm.nodeMetrics {
+		total += metrics.TotalAttempts
+	}
+	return total
+}
+
 // GetNodeMetrics returns metrics for a specific node.
 func (rm *RetryMetrics) GetNodeMetrics(nodeID string) *NodeMetrics {
 	rm.mu.RLock()
@@ -96,7 +188,12 @@ func (rm *RetryMetrics) GetNodeMetrics(nodeID string) *NodeMetrics {
 			FailureCount:      metrics.FailureCount,
 			TransientErrors:   metrics.TransientErrors,
 			PermanentErrors:   metrics.PermanentErrors,
+			UpstreamErrors:    metrics.UpstreamErrors,
+			UpstreamRetries:   metrics.UpstreamRetries,
 			CircuitBreakerHits: metrics.CircuitBreakerHits,
+			AttemptTime:        metrics.AttemptTime,
+			RetryDelay:         metrics.RetryDelay,
+			WallTime:           metrics.WallTime,
 		}
 	}
 	return nil
@@ -116,7 +213,12 @@ func (rm *RetryMetrics) GetAllMetrics() map[string]*NodeMetrics {
 			FailureCount:      metrics.FailureCount,
 			TransientErrors:   metrics.TransientErrors,
 			PermanentErrors:   metrics.PermanentErrors,
+			UpstreamErrors:    metrics.UpstreamErrors,
+			UpstreamRetries:   metrics.UpstreamRetries,
 			CircuitBreakerHits: metrics.CircuitBreakerHits,
+			AttemptTime:        metrics.AttemptTime,
+			RetryDelay:         metrics.RetryDelay,
+			WallTime:           metrics.WallTime,
 		}
 	}
 	return result
@@ -137,21 +239,24 @@ func (rm *RetryMetrics) Summary() string {
 	totalAttempts := 0
 	totalFailures := 0
 	totalRetries := 0
+	var totalDelay time.Duration
 	
 	for nodeID, metrics := range rm.nodeMetrics {
 		totalAttempts += metrics.TotalAttempts
 		totalFailures += metrics.FailureCount
+		totalDelay += metrics.RetryDelay
 		
 		if metrics.TotalAttempts > 1 {
 			totalRetries += (metrics.TotalAttempts - 1)
-			summary += fmt.Sprintf("  - %s: %d attempts, %d failures (%d transient, %d permanent)\n",
+			summary += fmt.Sprintf("  - %s: %d attempts, %d failures (%d transient, %d permanent), %v retry delay, %v wall time\n",
 				nodeID, metrics.TotalAttempts, metrics.FailureCount,
-				metrics.TransientErrors, metrics.PermanentErrors)
+				metrics.TransientErrors, metrics.PermanentErrors,
+				metrics.RetryDelay, metrics.WallTime)
 		}
 	}
 	
-	summary += fmt.Sprintf("Total: %d attempts, %d retries, %d failures\n", 
-		totalAttempts, totalRetries, totalFailures)
+	summary += fmt.Sprintf("Total: %d attempts, %d retries, %d failures, %v retry delay, peak %d concurrent backoffs\n",
+		totalAttempts, totalRetries, totalFailures, totalDelay, rm.peakBackoffs)
 	
 	return summary
 }
@@ -2,6 +2,7 @@ package retry
 
 import (
 	"math"
+	"math/rand/v2"
 	"time"
 )
 
@@ -11,21 +12,30 @@ type RetryPolicy struct {
 	InitialDelay     time.Duration // Initial delay before first retry
 	BackoffMultiplier float64       // Multiplier for exponential backoff
 	MaxDelay         time.Duration // Maximum delay between retries
+	JitterFraction   float64       // Share of each delay randomized away, 0 (none) to 1 (full jitter)
 }
 
+// DefaultJitterFraction is the jitter DefaultPolicy applies: each delay is
+// drawn uniformly from [delay/2, delay].
+const DefaultJitterFraction = 0.5
+
 // DefaultPolicy returns a sensible default retry policy.
-// Max 3 retries (4 total attempts), starting at 1s with 2x backoff, capped at 30s.
+// Max 3 retries (4 total attempts), starting at 1s with 2x backoff, capped at 30s,
+// with up to half of each delay randomized away.
 func DefaultPolicy() *RetryPolicy {
 	return &RetryPolicy{
 		MaxAttempts:      3,
 		InitialDelay:     1 * time.Second,
 		BackoffMultiplier: 2.0,
 		MaxDelay:         30 * time.Second,
+		JitterFraction:   DefaultJitterFraction,
 	}
 }
 
 // ExponentialBackoff calculates the delay for a given retry attempt.
 // attempt is 0-indexed (0 = first retry, 1 = second retry, etc.)
+// With a JitterFraction f the delay is drawn uniformly from
+// [delay*(1-f), delay], so nodes that failed together don't retry in lockstep.
 func ExponentialBackoff(policy *RetryPolicy, attempt int) time.Duration {
 	if attempt < 0 {
 		attempt = 0
@@ -39,6 +49,10 @@ func ExponentialBackoff(policy *RetryPolicy, attempt int) time.Duration {
 		delay = float64(policy.MaxDelay)
 	}
 
+	if jitter := math.Min(policy.JitterFraction, 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+
 	return time.Duration(delay)
 }
 
@@ -20,6 +20,9 @@ func TestDefaultPolicy(t *testing.T) {
 	if policy.MaxDelay != 30*time.Second {
 		t.Errorf("Expected MaxDelay=30s, got %v", policy.MaxDelay)
 	}
+	if policy.JitterFraction != DefaultJitterFraction {
+		t.Errorf("Expected JitterFraction=%v, got %v", DefaultJitterFraction, policy.JitterFraction)
+	}
 }
 
 func TestExponentialBackoff(t *testing.T) {
@@ -49,6 +52,45 @@ func TestExponentialBackoff(t *testing.T) {
 	}
 }
 
+func TestExponentialBackoff_Jitter(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialDelay:      100 * time.Millisecond,
+		BackoffMultiplier: 2.0,
+		MaxDelay:          1 * time.Second,
+		JitterFraction:    0.5,
+	}
+
+	// Attempt 2 backs off 400ms before jitter, so delays fall in [200ms, 400ms]
+	seen := make(map[time.Duration]bool)
+	var below, above int
+	for i := 0; i < 1000; i++ {
+		delay := ExponentialBackoff(policy, 2)
+		if delay < 200*time.Millisecond || delay > 400*time.Millisecond {
+			t.Fatalf("Delay %v outside [200ms, 400ms]", delay)
+		}
+		if delay < 300*time.Millisecond {
+			below++
+		} else {
+			above++
+		}
+		seen[delay] = true
+	}
+	if len(seen) < 100 {
+		t.Errorf("Only %d distinct delays in 1000 calls, expected them to vary", len(seen))
+	}
+	// Roughly uniform: each half of the range gets a fair share
+	if below < 350 || above < 350 {
+		t.Errorf("Delays skewed: %d below the midpoint, %d above", below, above)
+	}
+
+	policy.JitterFraction = 1
+	for i := 0; i < 100; i++ {
+		if delay := ExponentialBackoff(policy, 4); delay > time.Second {
+			t.Fatalf("Full jitter delay %v exceeds the cap", delay)
+		}
+	}
+}
+
 func TestShouldRetry(t *testing.T) {
 	policy := &RetryPolicy{MaxAttempts: 3}
 
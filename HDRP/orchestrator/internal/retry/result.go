@@ -0,0 +1,16 @@
+package retry
+
+import "fmt"
+
+// InvalidResultError indicates a service call succeeded but returned output
+// that fails validation, such as a researcher with no claims. Retryable
+// controls whether another attempt is worthwhile.
+type InvalidResultError struct {
+	Reason    string
+	Retryable bool
+}
+
+// Error implements the error interface.
+func (e *InvalidResultError) Error() string {
+	return fmt.Sprintf("result rejected: %s", e.Reason)
+}
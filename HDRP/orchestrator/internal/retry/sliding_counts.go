@@ -0,0 +1,82 @@
+package retry
+
+import "time"
+
+// slidingCounts counts request outcomes over a sliding time window, split
+// into fixed-width buckets. Buckets older than the window are dropped as
+// time moves on, so the counts only reflect recent behavior.
+type slidingCounts struct {
+	width   time.Duration // Time span of one bucket
+	buckets []outcomeBucket
+}
+
+type outcomeBucket struct {
+	start     time.Time // Start of the bucket's span; zero if never used
+	failures  int
+	successes int
+}
+
+// newSlidingCounts creates a window spanning window, split into buckets.
+// Non-positive arguments use DefaultBreakerWindow and DefaultBreakerBuckets.
+func newSlidingCounts(window time.Duration, buckets int) slidingCounts {
+	if window <= 0 {
+		window = DefaultBreakerWindow
+	}
+	if buckets <= 0 {
+		buckets = DefaultBreakerBuckets
+	}
+	width := window / time.Duration(buckets)
+	if width <= 0 {
+		width = 1
+	}
+	return slidingCounts{width: width, buckets: make([]outcomeBucket, buckets)}
+}
+
+// current returns the bucket covering now, clearing it if it last held an
+// older span.
+func (w *slidingCounts) current(now time.Time) *outcomeBucket {
+	start := now.Truncate(w.width)
+	b := &w.buckets[int((start.UnixNano()/int64(w.width))%int64(len(w.buckets)))]
+	if !b.start.Equal(start) {
+		*b = outcomeBucket{start: start}
+	}
+	return b
+}
+
+// record counts one outcome at now.
+func (w *slidingCounts) record(now time.Time, failed bool) {
+	b := w.current(now)
+	if failed {
+		b.failures++
+	} else {
+		b.successes++
+	}
+}
+
+// counts returns the failures and successes recorded within the window
+// ending at now.
+func (w *slidingCounts) counts(now time.Time) (failures, successes int) {
+	oldest := now.Truncate(w.width).Add(-time.Duration(len(w.buckets)-1) * w.width)
+	for _, b := range w.buckets {
+		if !b.start.IsZero() && !b.start.Before(oldest) && !b.start.After(now) {
+			failures += b.failures
+			successes += b.successes
+		}
+	}
+	return failures, successes
+}
+
+// set replaces the window's contents with counts recorded at now.
+func (w *slidingCounts) set(now time.Time, failures, successes int) {
+	w.reset()
+	b := w.current(now)
+	b.failures = failures
+	b.successes = successes
+}
+
+// reset drops every recorded outcome.
+func (w *slidingCounts) reset() {
+	for i := range w.buckets {
+		w.buckets[i] = outcomeBucket{}
+	}
+}
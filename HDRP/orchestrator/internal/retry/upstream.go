@@ -0,0 +1,29 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// UpstreamDataError indicates a node failed because its parents produced
+// unusable data. Retrying the node itself won't help; re-running the listed
+// parents might.
+type UpstreamDataError struct {
+	NodeIDs []string // Parents whose output was unusable
+	Reason  string
+}
+
+// Error implements the error interface.
+func (e *UpstreamDataError) Error() string {
+	return fmt.Sprintf("upstream data problem from %s: %s", strings.Join(e.NodeIDs, ", "), e.Reason)
+}
+
+// UpstreamNodes returns the parents blamed by an UpstreamDataError in err's chain.
+func UpstreamNodes(err error) []string {
+	var upstreamErr *UpstreamDataError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr.NodeIDs
+	}
+	return nil
+}
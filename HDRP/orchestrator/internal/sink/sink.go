@@ -0,0 +1,131 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Report is the final output of a research run.
+type Report struct {
+	RunID        string `json:"run_id"`
+	Query        string `json:"query"`
+	Success      bool   `json:"success"`
+	Content      string `json:"report"`
+	ArtifactURI  string `json:"artifact_uri,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// Sink delivers a final report to its destination.
+type Sink interface {
+	Write(ctx context.Context, report *Report) error
+}
+
+// New parses a sink spec and returns the matching sink:
+//   - "stdout" (or empty) writes the report text to standard output
+//   - "file:<path>" writes the report text to a file
+//   - "http://..." or "https://..." POSTs the report as JSON
+func New(spec string) (Sink, error) {
+	switch {
+	case spec == "" || spec == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case strings.HasPrefix(spec, "file:"):
+		path := strings.TrimPrefix(spec, "file:")
+		if path == "" {
+			return nil, fmt.Errorf("file sink requires a path")
+		}
+		return NewFileSink(path), nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewHTTPSink(spec, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported sink: %s", spec)
+	}
+}
+
+// WriterSink writes the report text to an io.Writer.
+type WriterSink struct {
+	w io.Writer
+}
+
+// NewWriterSink creates a sink that writes to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// Write writes the report content followed by a newline.
+func (s *WriterSink) Write(ctx context.Context, report *Report) error {
+	if _, err := fmt.Fprintln(s.w, report.Content); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// FileSink writes the report text to a file, creating parent directories.
+type FileSink struct {
+	path string
+}
+
+// NewFileSink creates a sink that writes to path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Write replaces the file's contents with the report.
+func (s *FileSink) Write(ctx context.Context, report *Report) error {
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(s.path, []byte(report.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return nil
+}
+
+// HTTPSink POSTs the report as JSON to a URL.
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink creates a sink that posts to url. A nil client uses a default
+// client with a 30 second timeout.
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPSink{url: url, client: client}
+}
+
+// Write posts the report and fails on any non-2xx response.
+func (s *HTTPSink) Write(ctx context.Context, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("report endpoint returned %s", resp.Status)
+	}
+	return nil
+}
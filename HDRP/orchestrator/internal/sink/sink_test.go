@@ -0,0 +1,98 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "", want: "*sink.WriterSink"},
+		{spec: "stdout", want: "*sink.WriterSink"},
+		{spec: "file:/tmp/report.md", want: "*sink.FileSink"},
+		{spec: "https://example.com/reports", want: "*sink.HTTPSink"},
+		{spec: "file:", wantErr: true},
+		{spec: "s3://bucket", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := New(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if err == nil {
+				if got := fmt.Sprintf("%T", s); got != tt.want {
+					t.Errorf("New(%q) = %s, want %s", tt.spec, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriterSink(&buf).Write(context.Background(), &Report{Content: "# Report"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if buf.String() != "# Report\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "report.md")
+	if err := NewFileSink(path).Write(context.Background(), &Report{Content: "# Report"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if string(data) != "# Report" {
+		t.Errorf("Unexpected file content %q", data)
+	}
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received Report
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST, got %s", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	report := &Report{RunID: "run-1", Query: "q", Success: true, Content: "# Report"}
+	if err := NewHTTPSink(srv.URL, srv.Client()).Write(context.Background(), report); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if received != *report {
+		t.Errorf("Received %+v, want %+v", received, *report)
+	}
+}
+
+func TestHTTPSink_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	if err := NewHTTPSink(srv.URL, srv.Client()).Write(context.Background(), &Report{}); err == nil {
+		t.Error("Expected error for 500 response")
+	}
+}
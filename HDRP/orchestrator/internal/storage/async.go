@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"hdrp/internal/metrics"
+)
+
+// DefaultAsyncQueueSize is the write queue length used when none is given.
+const DefaultAsyncQueueSize = 256
+
+// AsyncWriter wraps a Storage so that state transitions (status updates,
+// WAL mutations and snapshots) are applied by a background goroutine in
+// the order they were issued. Callers only block when the queue is full.
+// Other writes (nodes, edges, results, graphs) still run synchronously, so
+// their errors reach the caller, but first wait for the queue to drain, so
+// they land after every transition issued before them. Reads pass straight
+// through and may not observe writes that are still queued; call Flush
+// first if that matters.
+type AsyncWriter struct {
+	Storage
+
+	mu     sync.RWMutex // Guards closed against concurrent enqueue
+	queue  chan func()
+	done   chan struct{}
+	closed bool
+}
+
+// NewAsyncWriter starts a writer that queues up to queueSize transitions
+// for inner. queueSize <= 0 uses DefaultAsyncQueueSize.
+func NewAsyncWriter(inner Storage, queueSize int) *AsyncWriter {
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncQueueSize
+	}
+	w := &AsyncWriter{
+		Storage: inner,
+		queue:   make(chan func(), queueSize),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for op := range w.queue {
+		op()
+		metrics.SetPersistenceQueueDepth(len(w.queue))
+	}
+}
+
+// enqueue hands op to the writer goroutine, blocking while the queue is
+// full. Once the writer is stopped, op runs synchronously instead.
+func (w *AsyncWriter) enqueue(op func()) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		op()
+		return
+	}
+
+	select {
+	case w.queue <- op:
+	default:
+		start := time.Now()
+		w.queue <- op
+		metrics.RecordPersistenceQueueWait(time.Since(start).Seconds())
+	}
+	metrics.SetPersistenceQueueDepth(len(w.queue))
+}
+
+// UpdateGraphStatus queues a graph status update.
+func (w *AsyncWriter) UpdateGraphStatus(graphID string, status string) error {
+	w.enqueue(func() {
+		if err := w.Storage.UpdateGraphStatus(graphID, status); err != nil {
+			log.Printf("[Storage] Warning: async graph status update failed for %s: %v", graphID, err)
+		}
+	})
+	return nil
+}
+
+// UpdateNodeStatus queues a node status update.
+func (w *AsyncWriter) UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error {
+	w.enqueue(func() {
+		if err := w.Storage.UpdateNodeStatus(graphID, nodeID, status, retryCount, lastError); err != nil {
+			log.Printf("[Storage] Warning: async node status update failed for %s/%s: %v", graphID, nodeID, err)
+		}
+	})
+	return nil
+}
+
+// UpdateNodePhase queues a node phase update behind earlier status updates.
+func (w *AsyncWriter) UpdateNodePhase(graphID string, nodeID string, phase string) error {
+	w.enqueue(func() {
+		if err := w.Storage.UpdateNodePhase(graphID, nodeID, phase); err != nil {
+			log.Printf("[Storage] Warning: async node phase update failed for %s/%s: %v", graphID, nodeID, err)
+		}
+	})
+	return nil
+}
+
+// LogMutation queues a WAL append. Sequence numbers are assigned when the
+// entry is written, so queue order is preserved in the log.
+func (w *AsyncWriter) LogMutation(graphID string, mutationType MutationType, payload interface{}) error {
+	w.enqueue(func() {
+		if err := w.Storage.LogMutation(graphID, mutationType, payload); err != nil {
+			log.Printf("[Storage] Warning: async WAL append failed for %s: %v", graphID, err)
+		}
+	})
+	return nil
+}
+
+// LogMutations queues a batch of WAL appends, written together in order.
+func (w *AsyncWriter) LogMutations(graphID string, mutations []MutationRequest) error {
+	w.enqueue(func() {
+		if err := w.Storage.LogMutations(graphID, mutations); err != nil {
+			log.Printf("[Storage] Warning: async WAL batch append failed for %s: %v", graphID, err)
+		}
+	})
+	return nil
+}
+
+// ShouldCreateSnapshot queues the snapshot check so it sees every earlier
+// write, creating the snapshot from the writer goroutine when due. It
+// always reports false so callers don't snapshot stale state themselves.
+func (w *AsyncWriter) ShouldCreateSnapshot(graphID string) (bool, error) {
+	w.enqueue(func() {
+		should, err := w.Storage.ShouldCreateSnapshot(graphID)
+		if err != nil || !should {
+			return
+		}
+		if err := w.Storage.CreateSnapshot(graphID); err != nil {
+			log.Printf("[Storage] Warning: async snapshot failed for %s: %v", graphID, err)
+		}
+	})
+	return false, nil
+}
+
+// SaveGraph saves a graph after the queued transitions.
+func (w *AsyncWriter) SaveGraph(graph *GraphState) error {
+	w.Flush()
+	return w.Storage.SaveGraph(graph)
+}
+
+// SaveNode saves a node after the queued transitions.
+func (w *AsyncWriter) SaveNode(graphID string, node *NodeState) error {
+	w.Flush()
+	return w.Storage.SaveNode(graphID, node)
+}
+
+// DeleteNode deletes a node after the queued transitions.
+func (w *AsyncWriter) DeleteNode(graphID string, nodeID string) error {
+	w.Flush()
+	return w.Storage.DeleteNode(graphID, nodeID)
+}
+
+// SaveEdge saves an edge after the queued transitions.
+func (w *AsyncWriter) SaveEdge(graphID string, from, to string) error {
+	w.Flush()
+	return w.Storage.SaveEdge(graphID, from, to)
+}
+
+// SaveConditionalEdge saves a conditional edge after the queued transitions.
+func (w *AsyncWriter) SaveConditionalEdge(graphID string, from, to, condition string) error {
+	w.Flush()
+	return w.Storage.SaveConditionalEdge(graphID, from, to, condition)
+}
+
+// DeleteEdge deletes an edge after the queued transitions.
+func (w *AsyncWriter) DeleteEdge(graphID string, from, to string) error {
+	w.Flush()
+	return w.Storage.DeleteEdge(graphID, from, to)
+}
+
+// SaveNodeResult saves a node's result after the queued transitions, so it
+// is never stored ahead of the status change that produced it.
+func (w *AsyncWriter) SaveNodeResult(graphID string, nodeID string, data []byte) error {
+	w.Flush()
+	return w.Storage.SaveNodeResult(graphID, nodeID, data)
+}
+
+// AppendWAL appends a WAL entry after the queued transitions.
+func (w *AsyncWriter) AppendWAL(entry *WALEntry) error {
+	w.Flush()
+	return w.Storage.AppendWAL(entry)
+}
+
+// Flush blocks until every queued write has been applied.
+func (w *AsyncWriter) Flush() {
+	flushed := make(chan struct{})
+	w.enqueue(func() { close(flushed) })
+	<-flushed
+}
+
+// Stop applies all queued writes and stops the writer goroutine. Later
+// writes are applied synchronously. The wrapped Storage stays open.
+func (w *AsyncWriter) Stop() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+}
+
+// Close stops the writer and closes the wrapped Storage.
+func (w *AsyncWriter) Close() error {
+	w.Stop()
+	return w.Storage.Close()
+}
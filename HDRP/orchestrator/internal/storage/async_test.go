@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedStorage blocks every node status update until the gate is opened.
+type gatedStorage struct {
+	Storage
+	entered chan struct{}
+	gate    chan struct{}
+
+	mu      sync.Mutex
+	applied []string
+}
+
+func (g *gatedStorage) UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error {
+	select {
+	case g.entered <- struct{}{}:
+	default:
+	}
+	<-g.gate
+	g.mu.Lock()
+	g.applied = append(g.applied, nodeID)
+	g.mu.Unlock()
+	return nil
+}
+
+func (g *gatedStorage) SaveNodeResult(graphID string, nodeID string, data []byte) error {
+	g.mu.Lock()
+	g.applied = append(g.applied, "result "+nodeID)
+	g.mu.Unlock()
+	return nil
+}
+
+func TestAsyncWriter_BlocksOnlyWhenQueueFull(t *testing.T) {
+	inner := &gatedStorage{entered: make(chan struct{}, 1), gate: make(chan struct{})}
+	writer := NewAsyncWriter(inner, 2)
+
+	// The first write is picked up by the writer and stalls on the gate
+	writer.UpdateNodeStatus("g", "n0", "RUNNING", 0, "")
+	<-inner.entered
+
+	// The next two fill the queue without blocking
+	done := make(chan struct{})
+	go func() {
+		writer.UpdateNodeStatus("g", "n1", "RUNNING", 0, "")
+		writer.UpdateNodeStatus("g", "n2", "RUNNING", 0, "")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Writes within the queue capacity blocked")
+	}
+
+	// One more exceeds the buffer and must wait for the writer
+	blocked := make(chan struct{})
+	go func() {
+		writer.UpdateNodeStatus("g", "n3", "RUNNING", 0, "")
+		close(blocked)
+	}()
+	select {
+	case <-blocked:
+		t.Fatal("Write beyond the queue capacity did not block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.gate)
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("Write stayed blocked after the writer drained")
+	}
+
+	writer.Stop()
+	want := []string{"n0", "n1", "n2", "n3"}
+	if len(inner.applied) != len(want) {
+		t.Fatalf("Expected %v applied, got %v", want, inner.applied)
+	}
+	for i := range want {
+		if inner.applied[i] != want[i] {
+			t.Errorf("Expected writes applied in order %v, got %v", want, inner.applied)
+			break
+		}
+	}
+}
+
+func TestAsyncWriter_FlushAndStop(t *testing.T) {
+	store := newTestStorage(t)
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "CREATED"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := store.SaveNode("graph-1", &NodeState{NodeID: "a", Type: "researcher", Status: "CREATED"}); err != nil {
+		t.Fatalf("Failed to save node: %v", err)
+	}
+
+	writer := NewAsyncWriter(store, 4)
+	writer.UpdateNodeStatus("graph-1", "a", "RUNNING", 0, "")
+	writer.LogMutation("graph-1", MutationUpdateNodeStatus, &UpdateNodeStatusPayload{NodeID: "a", NewStatus: "RUNNING"})
+	writer.Flush()
+
+	nodes, err := store.LoadNodes("graph-1")
+	if err != nil {
+		t.Fatalf("Failed to load nodes: %v", err)
+	}
+	if nodes[0].Status != "RUNNING" {
+		t.Errorf("Expected flushed status RUNNING, got %s", nodes[0].Status)
+	}
+
+	// After Stop, writes go straight through and the wrapped storage stays open
+	writer.Stop()
+	writer.UpdateNodeStatus("graph-1", "a", "SUCCEEDED", 0, "")
+	nodes, err = store.LoadNodes("graph-1")
+	if err != nil {
+		t.Fatalf("Storage unusable after Stop: %v", err)
+	}
+	if nodes[0].Status != "SUCCEEDED" {
+		t.Errorf("Expected synchronous write after Stop, got %s", nodes[0].Status)
+	}
+
+	wal, err := store.GetUnreplayedWAL("graph-1")
+	if err != nil {
+		t.Fatalf("Failed to load WAL: %v", err)
+	}
+	if len(wal) != 1 {
+		t.Errorf("Expected 1 WAL entry, got %d", len(wal))
+	}
+}
+
+// TestAsyncWriter_SynchronousWritesFollowQueue verifies a write that isn't
+// queued waits for the transitions issued before it.
+func TestAsyncWriter_SynchronousWritesFollowQueue(t *testing.T) {
+	inner := &gatedStorage{entered: make(chan struct{}, 1), gate: make(chan struct{})}
+	writer := NewAsyncWriter(inner, 4)
+	defer writer.Stop()
+
+	writer.UpdateNodeStatus("g", "n0", "SUCCEEDED", 0, "")
+	<-inner.entered
+
+	saved := make(chan error, 1)
+	go func() { saved <- writer.SaveNodeResult("g", "n0", []byte("{}")) }()
+	select {
+	case <-saved:
+		t.Fatal("SaveNodeResult ran ahead of the queued status update")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.gate)
+	if err := <-saved; err != nil {
+		t.Fatalf("SaveNodeResult failed: %v", err)
+	}
+	inner.mu.Lock()
+	defer inner.mu.Unlock()
+	if len(inner.applied) != 2 || inner.applied[0] != "n0" || inner.applied[1] != "result n0" {
+		t.Errorf("Expected the status update before the result, got %v", inner.applied)
+	}
+}
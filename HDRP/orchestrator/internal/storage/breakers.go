@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hdrp/internal/retry"
+)
+
+// SQLiteBreakerStore implements retry.BreakerStore in the breaker_states
+// table, so circuit breakers keep their state across restarts.
+type SQLiteBreakerStore struct {
+	s *SQLiteStorage
+}
+
+// NewSQLiteBreakerStore creates a breaker store backed by s.
+func NewSQLiteBreakerStore(s *SQLiteStorage) *SQLiteBreakerStore {
+	return &SQLiteBreakerStore{s: s}
+}
+
+// SaveBreakers stores the state of each service's breaker, replacing what
+// was stored for those services.
+func (b *SQLiteBreakerStore) SaveBreakers(snapshots map[string]retry.BreakerSnapshot) error {
+	now := time.Now()
+	for serviceType, snapshot := range snapshots {
+		_, err := b.s.exec(`
+			INSERT INTO breaker_states (service_type, state, failures, successes, consecutive_successes, reopens, last_failure_at, opened_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(service_type) DO UPDATE SET
+				state = excluded.state,
+				failures = excluded.failures,
+				successes = excluded.successes,
+				consecutive_successes = excluded.consecutive_successes,
+				reopens = excluded.reopens,
+				last_failure_at = excluded.last_failure_at,
+				opened_at = excluded.opened_at,
+				updated_at = excluded.updated_at
+		`, serviceType, int(snapshot.State), snapshot.Failures, snapshot.Successes, snapshot.ConsecutiveSuccesses,
+			snapshot.Reopens, nullTime(snapshot.LastFailureTime), nullTime(snapshot.OpenedAt), now)
+		if err != nil {
+			return fmt.Errorf("failed to save breaker state for %s: %w", serviceType, err)
+		}
+	}
+	return nil
+}
+
+// LoadBreakers retrieves every stored breaker state by service type.
+func (b *SQLiteBreakerStore) LoadBreakers() (map[string]retry.BreakerSnapshot, error) {
+	rows, err := b.s.query(`
+		SELECT service_type, state, failures, successes, consecutive_successes, reopens, last_failure_at, opened_at
+		FROM breaker_states
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load breaker states: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make(map[string]retry.BreakerSnapshot)
+	for rows.Next() {
+		var (
+			serviceType           string
+			state                 int
+			snapshot              retry.BreakerSnapshot
+			lastFailure, openedAt sql.NullTime
+		)
+		if err := rows.Scan(&serviceType, &state, &snapshot.Failures, &snapshot.Successes,
+			&snapshot.ConsecutiveSuccesses, &snapshot.Reopens, &lastFailure, &openedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan breaker state: %w", err)
+		}
+		snapshot.State = retry.CircuitState(state)
+		snapshot.LastFailureTime = lastFailure.Time
+		snapshot.OpenedAt = openedAt.Time
+		snapshots[serviceType] = snapshot
+	}
+	return snapshots, rows.Err()
+}
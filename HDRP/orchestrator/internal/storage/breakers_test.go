@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"hdrp/internal/retry"
+)
+
+func TestSQLiteBreakerStore(t *testing.T) {
+	var breakers retry.BreakerStore = NewSQLiteBreakerStore(newTestStorage(t))
+
+	if loaded, err := breakers.LoadBreakers(); err != nil || len(loaded) != 0 {
+		t.Fatalf("LoadBreakers(empty) = %v, %v; want no states", loaded, err)
+	}
+
+	openedAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Millisecond)
+	critic := retry.BreakerSnapshot{State: retry.CircuitOpen, Failures: 10, Reopens: 1, LastFailureTime: openedAt, OpenedAt: openedAt}
+	if err := breakers.SaveBreakers(map[string]retry.BreakerSnapshot{
+		"critic":     critic,
+		"researcher": {State: retry.CircuitClosed, Successes: 4},
+	}); err != nil {
+		t.Fatalf("SaveBreakers failed: %v", err)
+	}
+	if err := breakers.SaveBreakers(map[string]retry.BreakerSnapshot{"researcher": {State: retry.CircuitHalfOpen, ConsecutiveSuccesses: 1}}); err != nil {
+		t.Fatalf("SaveBreakers (overwrite) failed: %v", err)
+	}
+
+	loaded, err := breakers.LoadBreakers()
+	if err != nil {
+		t.Fatalf("LoadBreakers failed: %v", err)
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("LoadBreakers = %d states, want 2", len(loaded))
+	}
+	got := loaded["critic"]
+	if got.State != retry.CircuitOpen || got.Failures != 10 || got.Reopens != 1 || !got.OpenedAt.Equal(openedAt) || !got.LastFailureTime.Equal(openedAt) {
+		t.Errorf("Loaded critic state = %+v, want %+v", got, critic)
+	}
+	if got := loaded["researcher"]; got.State != retry.CircuitHalfOpen || got.ConsecutiveSuccesses != 1 || !got.OpenedAt.IsZero() {
+		t.Errorf("Loaded researcher state = %+v, want the overwritten half-open state", got)
+	}
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hdrp/internal/retry"
+)
+
+// SQLiteCheckpointStore implements retry.CheckpointStore in the checkpoints
+// table, keeping node retry state in the same database as the DAG state.
+type SQLiteCheckpointStore struct {
+	s *SQLiteStorage
+}
+
+// NewSQLiteCheckpointStore creates a checkpoint store backed by s.
+func NewSQLiteCheckpointStore(s *SQLiteStorage) *SQLiteCheckpointStore {
+	return &SQLiteCheckpointStore{s: s}
+}
+
+// Save stores a checkpoint for a node, replacing any earlier one.
+func (c *SQLiteCheckpointStore) Save(runID, nodeID string, attemptNumber int, err error) error {
+	lastError := ""
+	if err != nil {
+		lastError = err.Error()
+	}
+
+	_, execErr := c.s.exec(`
+		INSERT INTO checkpoints (run_id, node_id, attempt_number, last_error, timestamp)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(run_id, node_id) DO UPDATE SET
+			attempt_number = excluded.attempt_number,
+			last_error = excluded.last_error,
+			timestamp = excluded.timestamp
+	`, runID, nodeID, attemptNumber, lastError, time.Now())
+	if execErr != nil {
+		return fmt.Errorf("failed to save checkpoint for node %s: %w", nodeID, execErr)
+	}
+	return nil
+}
+
+// Load retrieves a checkpoint for a node. A node without one gets an empty
+// checkpoint at attempt 0.
+func (c *SQLiteCheckpointStore) Load(runID, nodeID string) (*retry.NodeCheckpoint, error) {
+	checkpoint := &retry.NodeCheckpoint{RunID: runID, NodeID: nodeID}
+	err := c.s.queryRow(`
+		SELECT attempt_number, last_error, timestamp
+		FROM checkpoints
+		WHERE run_id = ? AND node_id = ?
+	`, runID, nodeID).Scan(&checkpoint.AttemptNumber, &checkpoint.LastError, &checkpoint.Timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint for node %s: %w", nodeID, err)
+	}
+	return checkpoint, nil
+}
+
+// Delete removes a node's checkpoint.
+func (c *SQLiteCheckpointStore) Delete(runID, nodeID string) error {
+	if _, err := c.s.exec(`DELETE FROM checkpoints WHERE run_id = ? AND node_id = ?`, runID, nodeID); err != nil {
+		return fmt.Errorf("failed to delete checkpoint for node %s: %w", nodeID, err)
+	}
+	return nil
+}
+
+// LoadAll retrieves all checkpoints for a run.
+func (c *SQLiteCheckpointStore) LoadAll(runID string) ([]*retry.NodeCheckpoint, error) {
+	rows, err := c.s.query(`
+		SELECT node_id, attempt_number, last_error, timestamp
+		FROM checkpoints
+		WHERE run_id = ?
+		ORDER BY node_id
+	`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints for run %s: %w", runID, err)
+	}
+	defer rows.Close()
+
+	checkpoints := []*retry.NodeCheckpoint{}
+	for rows.Next() {
+		checkpoint := &retry.NodeCheckpoint{RunID: runID}
+		if err := rows.Scan(&checkpoint.NodeID, &checkpoint.AttemptNumber, &checkpoint.LastError, &checkpoint.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, rows.Err()
+}
+
+// DeleteAll removes all checkpoints for a run.
+func (c *SQLiteCheckpointStore) DeleteAll(runID string) error {
+	if _, err := c.s.exec(`DELETE FROM checkpoints WHERE run_id = ?`, runID); err != nil {
+		return fmt.Errorf("failed to delete checkpoints for run %s: %w", runID, err)
+	}
+	return nil
+}
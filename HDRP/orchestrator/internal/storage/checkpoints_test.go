@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"hdrp/internal/retry"
+)
+
+func TestSQLiteCheckpointStore(t *testing.T) {
+	var checkpoints retry.CheckpointStore = NewSQLiteCheckpointStore(newTestStorage(t))
+
+	if cp, err := checkpoints.Load("run-1", "n1"); err != nil || cp.AttemptNumber != 0 || cp.NodeID != "n1" {
+		t.Fatalf("Load(missing) = %+v, %v; want an empty checkpoint", cp, err)
+	}
+
+	if err := checkpoints.Save("run-1", "n1", 1, errors.New("timeout")); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := checkpoints.Save("run-1", "n1", 2, errors.New("unavailable")); err != nil {
+		t.Fatalf("Save (overwrite) failed: %v", err)
+	}
+	checkpoints.Save("run-1", "n2", 1, nil)
+	checkpoints.Save("run-2", "n1", 3, nil)
+
+	cp, err := checkpoints.Load("run-1", "n1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cp.AttemptNumber != 2 || cp.LastError != "unavailable" || cp.Timestamp.IsZero() {
+		t.Errorf("Loaded checkpoint = %+v, want attempt 2 with the latest error", cp)
+	}
+
+	all, err := checkpoints.LoadAll("run-1")
+	if err != nil || len(all) != 2 {
+		t.Fatalf("LoadAll = %d checkpoints, %v; want 2", len(all), err)
+	}
+
+	if err := checkpoints.Delete("run-1", "n1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if cp, _ := checkpoints.Load("run-1", "n1"); cp.AttemptNumber != 0 {
+		t.Errorf("Checkpoint remains after Delete: %+v", cp)
+	}
+
+	if err := checkpoints.DeleteAll("run-1"); err != nil {
+		t.Fatalf("DeleteAll failed: %v", err)
+	}
+	if all, _ := checkpoints.LoadAll("run-1"); len(all) != 0 {
+		t.Errorf("LoadAll after DeleteAll = %d checkpoints, want 0", len(all))
+	}
+	if cp, _ := checkpoints.Load("run-2", "n1"); cp.AttemptNumber != 3 {
+		t.Errorf("DeleteAll removed another run's checkpoint: %+v", cp)
+	}
+}
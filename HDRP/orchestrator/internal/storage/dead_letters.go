@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DeadLetter records a node that gave up: it failed permanently or ran out
+// of retries. Entries are kept for inspection and reprocessing after the
+// run ends.
+type DeadLetter struct {
+	ID        int64
+	GraphID   string
+	NodeID    string
+	NodeType  string
+	Config    map[string]string
+	LastError string
+	CreatedAt time.Time
+}
+
+// AppendDeadLetter records that a node of a graph gave up with lastError.
+// A node that gives up in several runs of the graph gets an entry for each.
+func (s *SQLiteStorage) AppendDeadLetter(graphID, nodeID, nodeType string, config map[string]string, lastError string) error {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	_, err = s.exec(`
+		INSERT INTO dead_letters (graph_id, node_id, node_type, config, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, graphID, nodeID, nodeType, string(configJSON), lastError, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to append dead letter for %s/%s: %w", graphID, nodeID, err)
+	}
+	return nil
+}
+
+// ListDeadLetters returns a graph's dead letters, oldest first.
+func (s *SQLiteStorage) ListDeadLetters(graphID string) ([]*DeadLetter, error) {
+	rows, err := s.query(`
+		SELECT id, node_id, node_type, config, last_error, created_at
+		FROM dead_letters
+		WHERE graph_id = ?
+		ORDER BY id
+	`, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := []*DeadLetter{}
+	for rows.Next() {
+		letter := &DeadLetter{GraphID: graphID}
+		var configJSON string
+		if err := rows.Scan(&letter.ID, &letter.NodeID, &letter.NodeType, &configJSON, &letter.LastError, &letter.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(configJSON), &letter.Config); err != nil {
+			return nil, fmt.Errorf("failed to decode config of dead letter %d: %w", letter.ID, err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
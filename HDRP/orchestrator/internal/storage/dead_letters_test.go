@@ -0,0 +1,38 @@
+package storage
+
+import "testing"
+
+func TestDeadLetters(t *testing.T) {
+	for name, store := range map[string]Storage{
+		"sqlite": newTestStorage(t),
+		"memory": NewInMemoryStorage(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "FAILED"}); err != nil {
+				t.Fatalf("Failed to save graph: %v", err)
+			}
+			config := map[string]string{"query": "q"}
+			if err := store.AppendDeadLetter("graph-1", "a", "researcher", config, "first"); err != nil {
+				t.Fatalf("AppendDeadLetter failed: %v", err)
+			}
+			if err := store.AppendDeadLetter("graph-1", "a", "researcher", config, "second"); err != nil {
+				t.Fatalf("AppendDeadLetter failed: %v", err)
+			}
+			if err := store.AppendDeadLetter("graph-2", "b", "critic", nil, "other graph"); err != nil {
+				t.Fatalf("AppendDeadLetter failed: %v", err)
+			}
+
+			letters, err := store.ListDeadLetters("graph-1")
+			if err != nil {
+				t.Fatalf("ListDeadLetters failed: %v", err)
+			}
+			if len(letters) != 2 || letters[0].LastError != "first" || letters[1].LastError != "second" {
+				t.Fatalf("Expected both entries of graph-1 in order, got %+v", letters)
+			}
+			letter := letters[0]
+			if letter.NodeID != "a" || letter.NodeType != "researcher" || letter.Config["query"] != "q" || letter.CreatedAt.IsZero() {
+				t.Errorf("Unexpected dead letter: %+v", letter)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// newTestStorage creates a SQLite store on a private database, closed when
+// the test ends.
+func newTestStorage(t testing.TB) *SQLiteStorage {
+	t.Helper()
+	t.Setenv("HDRP_DB_PATH", filepath.Join(t.TempDir(), "hdrp.db"))
+
+	store, err := NewSQLiteStorage()
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// NodeTransition is one recorded status change of a node.
+type NodeTransition struct {
+	NodeID      string
+	OldStatus   string
+	NewStatus   string
+	RetryCount  int
+	LastError   string
+	Attempt     int // 0 for entries logged before attempts were recorded
+	SequenceNum int64
+	Timestamp   time.Time
+}
+
+// LoadNodeHistory returns every node status change logged for a graph in
+// sequence order, including WAL entries already replayed. Entries removed
+// by CleanupOldWAL are not included.
+func (s *SQLiteStorage) LoadNodeHistory(graphID string) ([]NodeTransition, error) {
+	rows, err := s.query(`
+		SELECT payload, sequence_num, created_at
+		FROM wal_log
+		WHERE graph_id = ? AND mutation_type = ?
+		ORDER BY sequence_num
+	`, graphID, MutationUpdateNodeStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query node history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []NodeTransition
+	for rows.Next() {
+		var payloadJSON string
+		var seqNum int64
+		var createdAt time.Time
+		if err := rows.Scan(&payloadJSON, &seqNum, &createdAt); err != nil {
+			return nil, err
+		}
+
+		decoded, err := decodeWALPayload(MutationUpdateNodeStatus, payloadJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode node history entry %d: %w", seqNum, err)
+		}
+		payload := decoded.(*UpdateNodeStatusPayload)
+
+		// Older entries only carry the row's second-resolution timestamp
+		timestamp := payload.Timestamp
+		if timestamp.IsZero() {
+			timestamp = createdAt
+		}
+
+		history = append(history, NodeTransition{
+			NodeID:      payload.NodeID,
+			OldStatus:   payload.OldStatus,
+			NewStatus:   payload.NewStatus,
+			RetryCount:  payload.RetryCount,
+			LastError:   payload.LastError,
+			Attempt:     payload.Attempt,
+			SequenceNum: seqNum,
+			Timestamp:   timestamp,
+		})
+	}
+	return history, rows.Err()
+}
+
+// FindGraphByRunID returns the ID of the most recently updated graph whose
+// run_id metadata matches runID, or "" if there is none.
+func (s *SQLiteStorage) FindGraphByRunID(runID string) (string, error) {
+	var graphID string
+	err := s.queryRow(`
+		SELECT id FROM graphs
+		WHERE json_extract(metadata, '$.run_id') = ?
+		ORDER BY updated_at DESC
+		LIMIT 1
+	`, runID).Scan(&graphID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find graph for run %s: %w", runID, err)
+	}
+	return graphID, nil
+}
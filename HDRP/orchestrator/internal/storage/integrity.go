@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// IntegrityIssueKind classifies a problem found by CheckIntegrity.
+type IntegrityIssueKind string
+
+const (
+	IssueOrphanedRow      IntegrityIssueKind = "orphaned_row"      // Row references a graph that doesn't exist
+	IssueEmptyGraph       IntegrityIssueKind = "empty_graph"       // Graph has no nodes
+	IssueDanglingEdge     IntegrityIssueKind = "dangling_edge"     // Edge endpoint isn't a node of its graph
+	IssueDuplicateWALSeq  IntegrityIssueKind = "duplicate_wal_seq" // Two WAL entries share a sequence number
+	IssueWALSequenceGap   IntegrityIssueKind = "wal_sequence_gap"  // WAL sequence numbers aren't contiguous
+	IssueSnapshotAheadWAL IntegrityIssueKind = "snapshot_ahead"    // Snapshot claims mutations the WAL never logged
+)
+
+// IntegrityIssue describes one inconsistency in stored state.
+type IntegrityIssue struct {
+	Kind    IntegrityIssueKind `json:"kind"`
+	Table   string             `json:"table"`
+	GraphID string             `json:"graph_id"`
+	Detail  string             `json:"detail"`
+}
+
+// IntegrityReport is the result of a read-only integrity scan.
+type IntegrityReport struct {
+	CheckedAt time.Time        `json:"checked_at"`
+	Issues    []IntegrityIssue `json:"issues"`
+}
+
+// OK reports whether no issues were found.
+func (r *IntegrityReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// IntegrityChecker is implemented by storage backends that can audit their own state.
+type IntegrityChecker interface {
+	CheckIntegrity() (*IntegrityReport, error)
+}
+
+// graphOwnedTables lists tables whose rows belong to a graph, with a column
+// identifying the row for reporting.
+var graphOwnedTables = []struct {
+	table string
+	key   string
+}{
+	{"nodes", "node_id"},
+	{"edges", "from_node || ' -> ' || to_node"},
+	{"wal_log", "'seq ' || sequence_num"},
+	{"snapshots", "'seq ' || sequence_num"},
+	{"graph_leases", "worker_id"},
+	{"node_results", "node_id"},
+	{"node_resume_failures", "node_id"},
+	{"dead_letters", "node_id"},
+}
+
+// CheckIntegrity scans stored state for orphaned rows, empty graphs, edges
+// referencing missing nodes, and WAL/snapshot sequence inconsistencies.
+// It never modifies data.
+func (s *SQLiteStorage) CheckIntegrity() (*IntegrityReport, error) {
+	report := &IntegrityReport{CheckedAt: time.Now(), Issues: []IntegrityIssue{}}
+
+	// Foreign keys aren't enforced on every connection, so cascades can be missed
+	for _, t := range graphOwnedTables {
+		query := fmt.Sprintf(`
+			SELECT graph_id, %s FROM %s
+			WHERE graph_id NOT IN (SELECT id FROM graphs)
+			ORDER BY graph_id
+		`, t.key, t.table)
+		err := s.collectIssues(report, query, func(graphID, key string) IntegrityIssue {
+			return IntegrityIssue{
+				Kind:    IssueOrphanedRow,
+				Table:   t.table,
+				GraphID: graphID,
+				Detail:  fmt.Sprintf("%s references missing graph", key),
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s for orphans: %w", t.table, err)
+		}
+	}
+
+	checks := []struct {
+		name  string
+		query string
+		issue func(graphID, detail string) IntegrityIssue
+	}{
+		{
+			name: "empty graphs",
+			query: `
+				SELECT g.id, g.status FROM graphs g
+				WHERE NOT EXISTS (SELECT 1 FROM nodes n WHERE n.graph_id = g.id)
+				ORDER BY g.id
+			`,
+			issue: func(graphID, status string) IntegrityIssue {
+				return IntegrityIssue{Kind: IssueEmptyGraph, Table: "graphs", GraphID: graphID,
+					Detail: fmt.Sprintf("graph in status %s has no nodes", status)}
+			},
+		},
+		{
+			name: "dangling edges",
+			query: `
+				SELECT e.graph_id, e.from_node || ' -> ' || e.to_node FROM edges e
+				WHERE e.graph_id IN (SELECT id FROM graphs)
+				AND (
+					NOT EXISTS (SELECT 1 FROM nodes n WHERE n.graph_id = e.graph_id AND n.node_id = e.from_node)
+					OR NOT EXISTS (SELECT 1 FROM nodes n WHERE n.graph_id = e.graph_id AND n.node_id = e.to_node)
+				)
+				ORDER BY e.graph_id
+			`,
+			issue: func(graphID, edge string) IntegrityIssue {
+				return IntegrityIssue{Kind: IssueDanglingEdge, Table: "edges", GraphID: graphID,
+					Detail: fmt.Sprintf("edge %s references a missing node", edge)}
+			},
+		},
+		{
+			name: "duplicate WAL sequences",
+			query: `
+				SELECT graph_id, 'seq ' || sequence_num || ' appears ' || COUNT(*) || ' times' FROM wal_log
+				GROUP BY graph_id, sequence_num
+				HAVING COUNT(*) > 1
+				ORDER BY graph_id
+			`,
+			issue: func(graphID, detail string) IntegrityIssue {
+				return IntegrityIssue{Kind: IssueDuplicateWALSeq, Table: "wal_log", GraphID: graphID, Detail: detail}
+			},
+		},
+		{
+			// Old entries are pruned from the front, so only gaps inside the retained range count
+			name: "WAL sequence gaps",
+			query: `
+				SELECT graph_id, 'seq ' || MIN(sequence_num) || '..' || MAX(sequence_num) || ' has ' || COUNT(DISTINCT sequence_num) || ' entries'
+				FROM wal_log
+				GROUP BY graph_id
+				HAVING MAX(sequence_num) - MIN(sequence_num) + 1 != COUNT(DISTINCT sequence_num)
+				ORDER BY graph_id
+			`,
+			issue: func(graphID, detail string) IntegrityIssue {
+				return IntegrityIssue{Kind: IssueWALSequenceGap, Table: "wal_log", GraphID: graphID, Detail: detail}
+			},
+		},
+		{
+			name: "snapshots ahead of WAL",
+			query: `
+				SELECT s.graph_id, 'snapshot seq ' || s.sequence_num || ' > last WAL seq ' || MAX(w.sequence_num)
+				FROM snapshots s
+				JOIN wal_log w ON w.graph_id = s.graph_id
+				GROUP BY s.graph_id
+				HAVING s.sequence_num > MAX(w.sequence_num)
+				ORDER BY s.graph_id
+			`,
+			issue: func(graphID, detail string) IntegrityIssue {
+				return IntegrityIssue{Kind: IssueSnapshotAheadWAL, Table: "snapshots", GraphID: graphID, Detail: detail}
+			},
+		},
+	}
+
+	for _, c := range checks {
+		if err := s.collectIssues(report, c.query, c.issue); err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", c.name, err)
+		}
+	}
+
+	return report, nil
+}
+
+// collectIssues runs a query returning (graph_id, detail) rows and appends an issue per row.
+func (s *SQLiteStorage) collectIssues(report *IntegrityReport, query string, issue func(graphID, detail string) IntegrityIssue) error {
+	rows, err := s.query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var graphID, detail string
+		if err := rows.Scan(&graphID, &detail); err != nil {
+			return err
+		}
+		report.Issues = append(report.Issues, issue(graphID, detail))
+	}
+	return rows.Err()
+}
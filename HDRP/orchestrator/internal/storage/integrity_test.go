@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"testing"
+)
+
+func countIssues(report *IntegrityReport, kind IntegrityIssueKind, graphID string) int {
+	count := 0
+	for _, issue := range report.Issues {
+		if issue.Kind == kind && issue.GraphID == graphID {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCheckIntegrity_Clean(t *testing.T) {
+	store := newTestStorage(t)
+
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "CREATED"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := store.SaveNode("graph-1", &NodeState{NodeID: id, Type: "researcher", Status: "CREATED"}); err != nil {
+			t.Fatalf("Failed to save node: %v", err)
+		}
+	}
+	if err := store.SaveEdge("graph-1", "a", "b"); err != nil {
+		t.Fatalf("Failed to save edge: %v", err)
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("Expected clean report, got %+v", report.Issues)
+	}
+}
+
+func TestCheckIntegrity_ReportsInconsistencies(t *testing.T) {
+	store := newTestStorage(t)
+
+	// graph-1 has a node and an edge to a node that doesn't exist
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := store.SaveNode("graph-1", &NodeState{NodeID: "a", Type: "researcher", Status: "CREATED"}); err != nil {
+		t.Fatalf("Failed to save node: %v", err)
+	}
+	if err := store.SaveEdge("graph-1", "a", "missing"); err != nil {
+		t.Fatalf("Failed to save edge: %v", err)
+	}
+
+	// graph-empty has no nodes
+	if err := store.SaveGraph(&GraphState{ID: "graph-empty", Status: "CREATED"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	// Orphaned edge for a graph that was never saved
+	if _, err := store.db.Exec(`INSERT INTO edges (graph_id, from_node, to_node) VALUES ('ghost', 'x', 'y')`); err != nil {
+		t.Fatalf("Failed to inject orphaned edge: %v", err)
+	}
+
+	// WAL for graph-1 skips sequence 1, and the snapshot claims sequence 5
+	for _, seq := range []int64{0, 2} {
+		entry := &WALEntry{GraphID: "graph-1", MutationType: MutationUpdateGraphStatus, Payload: map[string]string{}, SequenceNum: seq}
+		if err := store.AppendWAL(entry); err != nil {
+			t.Fatalf("Failed to append WAL: %v", err)
+		}
+	}
+	if err := store.SaveSnapshot("graph-1", 5, []byte("{}")); err != nil {
+		t.Fatalf("Failed to save snapshot: %v", err)
+	}
+
+	var edgesBefore int
+	store.db.QueryRow(`SELECT COUNT(*) FROM edges`).Scan(&edgesBefore)
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+
+	tests := []struct {
+		kind    IntegrityIssueKind
+		graphID string
+	}{
+		{IssueOrphanedRow, "ghost"},
+		{IssueDanglingEdge, "graph-1"},
+		{IssueEmptyGraph, "graph-empty"},
+		{IssueWALSequenceGap, "graph-1"},
+		{IssueSnapshotAheadWAL, "graph-1"},
+	}
+	for _, tt := range tests {
+		if got := countIssues(report, tt.kind, tt.graphID); got != 1 {
+			t.Errorf("Expected 1 %s issue for %s, got %d (report: %+v)", tt.kind, tt.graphID, got, report.Issues)
+		}
+	}
+
+	// The orphaned edge is reported only as orphaned, not also as dangling
+	if got := countIssues(report, IssueDanglingEdge, "ghost"); got != 0 {
+		t.Errorf("Expected orphaned edge not to be reported as dangling, got %d", got)
+	}
+
+	var edgesAfter int
+	store.db.QueryRow(`SELECT COUNT(*) FROM edges`).Scan(&edgesAfter)
+	if edgesAfter != edgesBefore {
+		t.Errorf("CheckIntegrity modified data: %d edges before, %d after", edgesBefore, edgesAfter)
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ClaimResumableGraph atomically claims a RUNNING graph whose lease is missing
+// or expired, recording workerID as its owner until leaseTTL elapses.
+// Returns nil if no resumable graph is available.
+func (s *SQLiteStorage) ClaimResumableGraph(workerID string, leaseTTL time.Duration) (*GraphState, error) {
+	if workerID == "" {
+		return nil, fmt.Errorf("worker ID is required")
+	}
+	if leaseTTL <= 0 {
+		return nil, fmt.Errorf("lease TTL must be positive")
+	}
+
+	// Serialize claims within this process; the conditional upsert below
+	// guards against claimers in other processes.
+	s.claimMu.Lock()
+	defer s.claimMu.Unlock()
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	// Take the write lock up front: a deferred transaction that reads and
+	// then writes fails with SQLITE_BUSY, rather than waiting, when another
+	// connection commits in between
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin claim transaction: %w", timeoutError(ctx, err))
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return nil, fmt.Errorf("failed to begin claim transaction: %w", timeoutError(ctx, err))
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	now := time.Now().UnixNano()
+	expiresAt := time.Now().Add(leaseTTL).UnixNano()
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT g.id
+		FROM graphs g
+		LEFT JOIN graph_leases l ON l.graph_id = g.id
+		WHERE g.status = 'RUNNING' AND (l.graph_id IS NULL OR l.expires_at <= ?)
+		ORDER BY g.updated_at, g.id
+	`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resumable graphs: %w", timeoutError(ctx, err))
+	}
+
+	var candidates []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidates = append(candidates, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, graphID := range candidates {
+		res, err := conn.ExecContext(ctx, `
+			INSERT INTO graph_leases (graph_id, worker_id, expires_at)
+			VALUES (?, ?, ?)
+			ON CONFLICT(graph_id) DO UPDATE SET
+				worker_id = excluded.worker_id,
+				expires_at = excluded.expires_at
+			WHERE graph_leases.expires_at <= ?
+		`, graphID, workerID, expiresAt, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write lease for graph %s: %w", graphID, timeoutError(ctx, err))
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			// Lease was taken by another claimer since the select
+			continue
+		}
+
+		if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+			return nil, fmt.Errorf("failed to commit claim: %w", timeoutError(ctx, err))
+		}
+		committed = true
+
+		log.Printf("[Storage] Worker %s claimed graph %s (lease %v)", workerID, graphID, leaseTTL)
+		return s.LoadGraph(graphID)
+	}
+
+	return nil, nil
+}
+
+// ReleaseGraphLease drops the lease on a graph if it is held by workerID.
+func (s *SQLiteStorage) ReleaseGraphLease(graphID, workerID string) error {
+	_, err := s.exec(`
+		DELETE FROM graph_leases
+		WHERE graph_id = ? AND worker_id = ?
+	`, graphID, workerID)
+	return err
+}
+
+// GetGraphLease returns the current lease owner and expiry for a graph.
+// Returns sql.ErrNoRows if the graph has never been claimed.
+func (s *SQLiteStorage) GetGraphLease(graphID string) (string, time.Time, error) {
+	var workerID string
+	var expiresAt int64
+	err := s.queryRow(`
+		SELECT worker_id, expires_at
+		FROM graph_leases
+		WHERE graph_id = ?
+	`, graphID).Scan(&workerID, &expiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", time.Time{}, err
+		}
+		return "", time.Time{}, fmt.Errorf("failed to load lease: %w", err)
+	}
+	return workerID, time.Unix(0, expiresAt), nil
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClaimResumableGraph_ConcurrentClaimers(t *testing.T) {
+	store := newTestStorage(t)
+
+	for i := 0; i < 2; i++ {
+		if err := store.SaveGraph(&GraphState{ID: fmt.Sprintf("graph-%d", i), Status: "RUNNING"}); err != nil {
+			t.Fatalf("Failed to save graph: %v", err)
+		}
+	}
+	if err := store.SaveGraph(&GraphState{ID: "graph-done", Status: "SUCCEEDED"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	claimed := make([]*GraphState, 2)
+	errs := make([]error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			claimed[i], errs[i] = store.ClaimResumableGraph(fmt.Sprintf("worker-%d", i), time.Minute)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("Worker %d claim failed: %v", i, err)
+		}
+		if claimed[i] == nil {
+			t.Fatalf("Worker %d claimed nothing", i)
+		}
+	}
+	if claimed[0].ID == claimed[1].ID {
+		t.Errorf("Both workers claimed the same graph %s", claimed[0].ID)
+	}
+
+	// No RUNNING graphs remain unleased
+	next, err := store.ClaimResumableGraph("worker-2", time.Minute)
+	if err != nil {
+		t.Fatalf("Third claim failed: %v", err)
+	}
+	if next != nil {
+		t.Errorf("Expected no resumable graph, got %s", next.ID)
+	}
+}
+
+func TestClaimResumableGraph_ExpiredLease(t *testing.T) {
+	store := newTestStorage(t)
+
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	first, err := store.ClaimResumableGraph("worker-a", 20*time.Millisecond)
+	if err != nil || first == nil {
+		t.Fatalf("Initial claim failed: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	second, err := store.ClaimResumableGraph("worker-b", time.Minute)
+	if err != nil {
+		t.Fatalf("Reclaim failed: %v", err)
+	}
+	if second == nil || second.ID != "graph-1" {
+		t.Fatalf("Expected worker-b to reclaim graph-1, got %+v", second)
+	}
+
+	owner, _, err := store.GetGraphLease("graph-1")
+	if err != nil {
+		t.Fatalf("Failed to load lease: %v", err)
+	}
+	if owner != "worker-b" {
+		t.Errorf("Expected lease owner worker-b, got %s", owner)
+	}
+
+	// Releasing with the wrong worker is a no-op
+	if err := store.ReleaseGraphLease("graph-1", "worker-a"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if again, _ := store.ClaimResumableGraph("worker-c", time.Minute); again != nil {
+		t.Errorf("Expected live lease to block claim, got %s", again.ID)
+	}
+
+	if err := store.ReleaseGraphLease("graph-1", "worker-b"); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if again, _ := store.ClaimResumableGraph("worker-c", time.Minute); again == nil {
+		t.Error("Expected released graph to be claimable")
+	}
+}
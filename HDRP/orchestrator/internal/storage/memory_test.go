@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+)
+
+func TestInMemoryStorage_BasicOperations(t *testing.T) {
+	store := NewInMemoryStorage()
+	defer store.Close()
+
+	graphID := "mem-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "CREATED", Metadata: map[string]string{"goal": "test"}}); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	if err := store.UpdateGraphStatus(graphID, "RUNNING"); err != nil {
+		t.Fatalf("UpdateGraphStatus failed: %v", err)
+	}
+	graph, err := store.LoadGraph(graphID)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	if graph.Status != "RUNNING" || graph.Metadata["goal"] != "test" {
+		t.Errorf("Loaded graph = %+v", graph)
+	}
+	if _, err := store.LoadGraph("missing"); err != sql.ErrNoRows {
+		t.Errorf("LoadGraph(missing) error = %v, want sql.ErrNoRows", err)
+	}
+
+	for _, id := range []string{"b", "a"} {
+		if err := store.SaveNode(graphID, &NodeState{NodeID: id, Type: "researcher", Status: "CREATED"}); err != nil {
+			t.Fatalf("SaveNode failed: %v", err)
+		}
+	}
+	if err := store.UpdateNodeStatus(graphID, "b", "FAILED", 2, "boom"); err != nil {
+		t.Fatalf("UpdateNodeStatus failed: %v", err)
+	}
+	nodes, err := store.LoadNodes(graphID)
+	if err != nil {
+		t.Fatalf("LoadNodes failed: %v", err)
+	}
+	if len(nodes) != 2 || nodes[0].NodeID != "b" || nodes[1].NodeID != "a" {
+		t.Fatalf("Nodes not returned in creation order: %+v", nodes)
+	}
+	if nodes[0].Status != "FAILED" || nodes[0].RetryCount != 2 || nodes[0].LastError != "boom" {
+		t.Errorf("Node status not updated: %+v", nodes[0])
+	}
+
+	store.SaveEdge(graphID, "b", "a")
+	store.SaveEdge(graphID, "b", "a")
+	if edges, _ := store.LoadEdges(graphID); len(edges) != 1 {
+		t.Errorf("Expected duplicate edge to be ignored, got %d edges", len(edges))
+	}
+
+	if _, err := store.LoadNodeResult(graphID, "a"); err != sql.ErrNoRows {
+		t.Errorf("LoadNodeResult(missing) error = %v, want sql.ErrNoRows", err)
+	}
+
+	if _, err := store.LoadRunResult("run-1"); err != sql.ErrNoRows {
+		t.Errorf("LoadRunResult(missing) error = %v, want sql.ErrNoRows", err)
+	}
+	store.SaveRunResult(&RunResult{RunID: "run-1", GraphID: graphID, Status: "SUCCEEDED", Success: true})
+	if result, err := store.LoadRunResult("run-1"); err != nil || !result.Success || result.GraphID != graphID {
+		t.Errorf("LoadRunResult = %+v, %v", result, err)
+	}
+	store.SaveNodeResult(graphID, "a", []byte("result"))
+	if data, err := store.LoadNodeResult(graphID, "a"); err != nil || string(data) != "result" {
+		t.Errorf("LoadNodeResult = %q, %v", data, err)
+	}
+
+	if err := store.DeleteGraph(graphID); err != nil {
+		t.Fatalf("DeleteGraph failed: %v", err)
+	}
+	if nodes, _ := store.LoadNodes(graphID); len(nodes) != 0 {
+		t.Errorf("Expected nodes to be deleted with graph, got %d", len(nodes))
+	}
+	if _, err := store.LoadNodeResult(graphID, "a"); err != sql.ErrNoRows {
+		t.Errorf("Expected results to be deleted with graph, got %v", err)
+	}
+}
+
+func TestInMemoryStorage_RecoverGraph(t *testing.T) {
+	store := NewInMemoryStorage()
+	graphID := "mem-recover"
+
+	store.LogMutation(graphID, MutationCreateGraph, &CreateGraphPayload{Graph: GraphState{ID: graphID, Status: "CREATED", Metadata: map[string]string{}}})
+	store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: NodeState{NodeID: "n1", Type: "researcher", Status: "CREATED"}})
+	store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: NodeState{NodeID: "n2", Type: "critic", Status: "CREATED"}})
+	store.LogMutation(graphID, MutationAddEdge, &AddEdgePayload{From: "n1", To: "n2"})
+	store.LogMutation(graphID, MutationUpdateNodeStatus, &UpdateNodeStatusPayload{NodeID: "n1", OldStatus: "CREATED", NewStatus: "SUCCEEDED"})
+
+	state, err := store.RecoverGraph(graphID)
+	if err != nil {
+		t.Fatalf("RecoverGraph failed: %v", err)
+	}
+	if len(state.Nodes) != 2 || len(state.Edges) != 1 {
+		t.Fatalf("Recovered %d nodes and %d edges, want 2 and 1", len(state.Nodes), len(state.Edges))
+	}
+	if state.Nodes["n1"].Status != "SUCCEEDED" {
+		t.Errorf("n1 status = %s, want SUCCEEDED", state.Nodes["n1"].Status)
+	}
+
+	if entries, _ := store.GetUnreplayedWAL(graphID); len(entries) != 0 {
+		t.Errorf("Expected WAL to be marked replayed, %d entries remain", len(entries))
+	}
+}
+
+func TestInMemoryStorage_SnapshotAndCleanup(t *testing.T) {
+	store := NewInMemoryStorage()
+	graphID := "mem-snapshot"
+
+	store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING", Metadata: map[string]string{}})
+	for i := 0; i < 150; i++ {
+		node := NodeState{NodeID: fmt.Sprintf("n%d", i), Type: "researcher", Status: "CREATED"}
+		store.SaveNode(graphID, &node)
+		store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: node})
+	}
+
+	should, err := store.ShouldCreateSnapshot(graphID)
+	if err != nil || !should {
+		t.Fatalf("ShouldCreateSnapshot = %v, %v; want true", should, err)
+	}
+	store.MarkWALReplayed(graphID, 149)
+	if err := store.CreateSnapshot(graphID); err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+
+	snapshot, err := store.LoadSnapshot(graphID)
+	if err != nil || snapshot == nil {
+		t.Fatalf("LoadSnapshot = %v, %v", snapshot, err)
+	}
+	if snapshot.SequenceNum != 149 {
+		t.Errorf("Snapshot sequence = %d, want 149", snapshot.SequenceNum)
+	}
+	// Replayed entries more than 100 behind the snapshot are trimmed
+	if got := len(store.wal[graphID]); got != 101 {
+		t.Errorf("WAL holds %d entries after cleanup, want 101", got)
+	}
+
+	store.LogMutation(graphID, MutationUpdateGraphStatus, &UpdateGraphStatusPayload{OldStatus: "RUNNING", NewStatus: "SUCCEEDED"})
+	state, err := store.RecoverGraph(graphID)
+	if err != nil {
+		t.Fatalf("RecoverGraph failed: %v", err)
+	}
+	if len(state.Nodes) != 150 || state.Graph.Status != "SUCCEEDED" {
+		t.Errorf("Recovered %d nodes with status %s, want 150 and SUCCEEDED", len(state.Nodes), state.Graph.Status)
+	}
+}
+
+func TestInMemoryStorage_Transaction(t *testing.T) {
+	store := NewInMemoryStorage()
+
+	tx, _ := store.BeginTx()
+	tx.SaveGraph(&GraphState{ID: "rolled-back", Status: "CREATED"})
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("Rollback failed: %v", err)
+	}
+	if _, err := store.LoadGraph("rolled-back"); err != sql.ErrNoRows {
+		t.Errorf("Rolled back graph was saved: %v", err)
+	}
+
+	tx, _ = store.BeginTx()
+	tx.SaveGraph(&GraphState{ID: "committed", Status: "CREATED"})
+	tx.SaveNode("committed", &NodeState{NodeID: "n1", Type: "researcher"})
+	tx.SaveEdge("committed", "n1", "n1")
+	tx.AppendWAL(&WALEntry{GraphID: "committed", MutationType: MutationAddEdge, Payload: &AddEdgePayload{From: "n1", To: "n1"}, SequenceNum: 0})
+	if _, err := store.LoadGraph("committed"); err != sql.ErrNoRows {
+		t.Errorf("Graph visible before commit: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if err := tx.Commit(); err != sql.ErrTxDone {
+		t.Errorf("Second commit error = %v, want sql.ErrTxDone", err)
+	}
+
+	if _, err := store.LoadGraph("committed"); err != nil {
+		t.Errorf("Committed graph missing: %v", err)
+	}
+	if nodes, _ := store.LoadNodes("committed"); len(nodes) != 1 {
+		t.Errorf("Committed %d nodes, want 1", len(nodes))
+	}
+	if entries, _ := store.GetUnreplayedWAL("committed"); len(entries) != 1 {
+		t.Errorf("Committed %d WAL entries, want 1", len(entries))
+	}
+}
+
+func TestInMemoryStorage_ListGraphs(t *testing.T) {
+	store := NewInMemoryStorage()
+	for _, id := range []string{"g1", "g2", "g3"} {
+		store.SaveGraph(&GraphState{ID: id, Status: "CREATED"})
+	}
+	store.UpdateGraphStatus("g1", "RUNNING")
+
+	page, total, err := store.ListGraphs(0, 2)
+	if err != nil || total != 3 {
+		t.Fatalf("ListGraphs = %d graphs, %v; want total 3", total, err)
+	}
+	if got := graphIDs(page); len(got) != 2 || got[0] != "g1" || got[1] != "g3" {
+		t.Errorf("First page = %v, want [g1 g3]", got)
+	}
+	if page, _, _ := store.ListGraphs(5, 2); len(page) != 0 {
+		t.Errorf("Page past the end returned %v", graphIDs(page))
+	}
+}
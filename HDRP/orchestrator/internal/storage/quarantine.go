@@ -0,0 +1,46 @@
+package storage
+
+import "fmt"
+
+// RecordResumeFailure increments how many resumed runs a node has failed
+// in and returns the new count. The count survives restarts so a node that
+// keeps failing can be quarantined.
+func (s *SQLiteStorage) RecordResumeFailure(graphID string, nodeID string, lastError string) (int, error) {
+	var failures int
+	err := s.queryRow(`
+		INSERT INTO node_resume_failures (graph_id, node_id, failures, last_error)
+		VALUES (?, ?, 1, ?)
+		ON CONFLICT(graph_id, node_id) DO UPDATE SET
+			failures = failures + 1,
+			last_error = excluded.last_error,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING failures
+	`, graphID, nodeID, lastError).Scan(&failures)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record resume failure for %s/%s: %w", graphID, nodeID, err)
+	}
+	return failures, nil
+}
+
+// LoadResumeFailures returns the resume failure count of every node in a
+// graph that has failed at least once.
+func (s *SQLiteStorage) LoadResumeFailures(graphID string) (map[string]int, error) {
+	rows, err := s.query(`
+		SELECT node_id, failures FROM node_resume_failures WHERE graph_id = ?
+	`, graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load resume failures: %w", err)
+	}
+	defer rows.Close()
+
+	failures := make(map[string]int)
+	for rows.Next() {
+		var nodeID string
+		var count int
+		if err := rows.Scan(&nodeID, &count); err != nil {
+			return nil, err
+		}
+		failures[nodeID] = count
+	}
+	return failures, rows.Err()
+}
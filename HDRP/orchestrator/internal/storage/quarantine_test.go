@@ -0,0 +1,31 @@
+package storage
+
+import "testing"
+
+func TestRecordResumeFailure(t *testing.T) {
+	store := newTestStorage(t)
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+
+	for want := 1; want <= 3; want++ {
+		got, err := store.RecordResumeFailure("graph-1", "a", "boom")
+		if err != nil {
+			t.Fatalf("RecordResumeFailure failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected count %d, got %d", want, got)
+		}
+	}
+	if _, err := store.RecordResumeFailure("graph-1", "b", ""); err != nil {
+		t.Fatalf("RecordResumeFailure failed: %v", err)
+	}
+
+	failures, err := store.LoadResumeFailures("graph-1")
+	if err != nil {
+		t.Fatalf("LoadResumeFailures failed: %v", err)
+	}
+	if failures["a"] != 3 || failures["b"] != 1 || len(failures) != 2 {
+		t.Errorf("Unexpected failure counts: %v", failures)
+	}
+}
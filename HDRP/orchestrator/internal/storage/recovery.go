@@ -9,6 +9,13 @@ import (
 // RecoverGraph reconstructs a graph from its last snapshot and WAL replay.
 // Returns the reconstructed graph state or nil if no recovery data exists.
 func (s *SQLiteStorage) RecoverGraph(graphID string) (*RecoveredGraphState, error) {
+	return recoverGraph(s, graphID)
+}
+
+// recoverGraph replays a graph's unreplayed WAL over its last snapshot and
+// marks the replayed entries, using only the Storage interface so every
+// backend recovers the same way.
+func recoverGraph(s Storage, graphID string) (*RecoveredGraphState, error) {
 	log.Printf("[Storage] Starting recovery for graph %s", graphID)
 
 	// Try to load snapshot first
@@ -30,14 +37,7 @@ func (s *SQLiteStorage) RecoverGraph(graphID string) (*RecoveredGraphState, erro
 		log.Printf("[Storage] Loaded snapshot at sequence %d for graph %s", lastSeqNum, graphID)
 	} else {
 		// No snapshot, start from empty state
-		state = &RecoveredGraphState{
-			Graph: &GraphState{
-				ID:       graphID,
-				Metadata: make(map[string]string),
-			},
-			Nodes: make(map[string]*NodeState),
-			Edges: []*EdgeState{},
-		}
+		state = emptyRecoveredState(graphID)
 		lastSeqNum = 0
 		log.Printf("[Storage] No snapshot found for graph %s, starting from empty state", graphID)
 	}
@@ -72,6 +72,67 @@ func (s *SQLiteStorage) RecoverGraph(graphID string) (*RecoveredGraphState, erro
 	return state, nil
 }
 
+// RecoverGraphAtSequence reconstructs a graph as it stood right after the
+// WAL entry with the given sequence number was applied. It starts from the
+// snapshot if that predates seqNum and replays the WAL up to seqNum. Unlike
+// RecoverGraph it is read-only: no entries are marked replayed. Fails if the
+// WAL needed to reach seqNum has been cleaned up or seqNum was never logged.
+func (s *SQLiteStorage) RecoverGraphAtSequence(graphID string, seqNum int64) (*RecoveredGraphState, error) {
+	snapshot, err := s.LoadSnapshot(graphID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	state := emptyRecoveredState(graphID)
+	fromSeqNum := int64(-1)
+	if snapshot != nil && snapshot.SequenceNum <= seqNum {
+		state, err = decodeSnapshot(snapshot.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+		if state.Nodes == nil {
+			state.Nodes = make(map[string]*NodeState)
+		}
+		fromSeqNum = snapshot.SequenceNum
+	}
+
+	walEntries, err := s.getWALRange(graphID, fromSeqNum, seqNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load WAL: %w", err)
+	}
+
+	lastSeqNum := fromSeqNum
+	for _, entry := range walEntries {
+		if entry.SequenceNum != lastSeqNum+1 {
+			return nil, fmt.Errorf("WAL for graph %s is missing sequence %d, it may have been cleaned up", graphID, lastSeqNum+1)
+		}
+		if err := applyWALEntry(state, entry); err != nil {
+			return nil, fmt.Errorf("failed to apply WAL entry %d: %w", entry.ID, err)
+		}
+		lastSeqNum = entry.SequenceNum
+	}
+
+	if lastSeqNum != seqNum {
+		return nil, fmt.Errorf("sequence %d not found in WAL for graph %s", seqNum, graphID)
+	}
+
+	log.Printf("[Storage] Reconstructed graph %s as of sequence %d", graphID, seqNum)
+	return state, nil
+}
+
+// emptyRecoveredState is the starting point for replaying a graph's WAL
+// without a snapshot.
+func emptyRecoveredState(graphID string) *RecoveredGraphState {
+	return &RecoveredGraphState{
+		Graph: &GraphState{
+			ID:       graphID,
+			Metadata: make(map[string]string),
+		},
+		Nodes: make(map[string]*NodeState),
+		Edges: []*EdgeState{},
+	}
+}
+
 // RecoveredGraphState represents a graph reconstructed from storage.
 type RecoveredGraphState struct {
 	Graph *GraphState
@@ -122,10 +183,38 @@ func applyWALEntry(state *RecoveredGraphState, entry *WALEntry) error {
 			return fmt.Errorf("invalid payload type for ADD_EDGE")
 		}
 		state.Edges = append(state.Edges, &EdgeState{
-			From: payload.From,
-			To:   payload.To,
+			From:      payload.From,
+			To:        payload.To,
+			Condition: payload.Condition,
 		})
 
+	case MutationRemoveNode:
+		payload, ok := entry.Payload.(*RemoveNodePayload)
+		if !ok {
+			return fmt.Errorf("invalid payload type for REMOVE_NODE")
+		}
+		delete(state.Nodes, payload.NodeID)
+		edges := state.Edges[:0]
+		for _, edge := range state.Edges {
+			if edge.From != payload.NodeID && edge.To != payload.NodeID {
+				edges = append(edges, edge)
+			}
+		}
+		state.Edges = edges
+
+	case MutationRemoveEdge:
+		payload, ok := entry.Payload.(*RemoveEdgePayload)
+		if !ok {
+			return fmt.Errorf("invalid payload type for REMOVE_EDGE")
+		}
+		edges := state.Edges[:0]
+		for _, edge := range state.Edges {
+			if edge.From != payload.From || edge.To != payload.To {
+				edges = append(edges, edge)
+			}
+		}
+		state.Edges = edges
+
 	case MutationSignalReceived:
 		// Signals are informational and don't modify core state during replay
 		log.Printf("[Storage] Replayed signal: %s", entry.MutationType)
@@ -139,7 +228,17 @@ func applyWALEntry(state *RecoveredGraphState, entry *WALEntry) error {
 
 // CreateSnapshot serializes the current graph state and saves it.
 func (s *SQLiteStorage) CreateSnapshot(graphID string) error {
-	// Load current state from database
+	seqNum, err := s.sequenceAllocator().Last(graphID)
+	if err != nil {
+		return err
+	}
+	return createSnapshot(s, graphID, seqNum)
+}
+
+// createSnapshot snapshots a graph's persisted state as of WAL sequence
+// seqNum and trims old replayed WAL entries.
+func createSnapshot(s Storage, graphID string, seqNum int64) error {
+	// Load current state from storage
 	graph, err := s.LoadGraph(graphID)
 	if err != nil {
 		return fmt.Errorf("failed to load graph: %w", err)
@@ -172,11 +271,6 @@ func (s *SQLiteStorage) CreateSnapshot(graphID string) error {
 		return fmt.Errorf("failed to serialize snapshot: %w", err)
 	}
 
-	// Get current sequence number
-	s.mu.RLock()
-	seqNum := s.seqNumbers[graphID] - 1 // Last written sequence
-	s.mu.RUnlock()
-
 	// Save snapshot
 	if err := s.SaveSnapshot(graphID, seqNum, data); err != nil {
 		return fmt.Errorf("failed to save snapshot: %w", err)
@@ -193,8 +287,12 @@ func (s *SQLiteStorage) CreateSnapshot(graphID string) error {
 	return nil
 }
 
-// decodeSnapshot deserializes snapshot data.
+// decodeSnapshot deserializes snapshot data, compressed or not.
 func decodeSnapshot(data []byte) (*RecoveredGraphState, error) {
+	data, err := decompressSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
 	var state RecoveredGraphState
 	if err := json.Unmarshal(data, &state); err != nil {
 		return nil, err
@@ -206,7 +304,7 @@ func decodeSnapshot(data []byte) (*RecoveredGraphState, error) {
 // Creates snapshot every 100 transitions.
 func (s *SQLiteStorage) ShouldCreateSnapshot(graphID string) (bool, error) {
 	var unreplayedCount int
-	err := s.db.QueryRow(`
+	err := s.queryRow(`
 		SELECT COUNT(*)
 		FROM wal_log
 		WHERE graph_id = ? AND replayed = 0
@@ -218,3 +316,19 @@ func (s *SQLiteStorage) ShouldCreateSnapshot(graphID string) (bool, error) {
 
 	return unreplayedCount >= 100, nil
 }
+
+// SnapshotLag returns how many WAL entries a graph has logged since its
+// latest snapshot, or since it was created if it has none.
+func (s *SQLiteStorage) SnapshotLag(graphID string) (int64, error) {
+	var lag int64
+	err := s.queryRow(`
+		SELECT COUNT(*)
+		FROM wal_log
+		WHERE graph_id = ? AND sequence_num > COALESCE(
+			(SELECT sequence_num FROM snapshots WHERE graph_id = ?), -1)
+	`, graphID, graphID).Scan(&lag)
+	if err != nil {
+		return 0, err
+	}
+	return lag, nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// terminalGraphStatuses are the statuses of graphs that will not run again.
+var terminalGraphStatuses = []string{"SUCCEEDED", "FAILED", "CANCELLED"}
+
+func isTerminalGraphStatus(status string) bool {
+	for _, terminal := range terminalGraphStatuses {
+		if status == terminal {
+			return true
+		}
+	}
+	return false
+}
+
+// CleanupGraphsOlderThan deletes finished graphs (SUCCEEDED, FAILED or
+// CANCELLED) last updated more than age ago, with their nodes, edges, WAL,
+// snapshots, leases and stored results. Returns how many graphs were
+// deleted. Run results are kept as run history.
+//
+// The graphs' rows are deleted from each table explicitly in one
+// transaction: the ON DELETE CASCADE foreign keys only apply on connections
+// that enable them, and the WAL has none.
+func (s *SQLiteStorage) CleanupGraphsOlderThan(age time.Duration) (int, error) {
+	ctx, cancel := s.opContext()
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, timeoutError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	// Fix the cutoff once so every table sees the same set of graphs
+	var cutoff string
+	if err := tx.QueryRowContext(ctx, `SELECT datetime('now', ?)`, fmt.Sprintf("-%d seconds", int64(age.Seconds()))).Scan(&cutoff); err != nil {
+		return 0, timeoutError(ctx, err)
+	}
+	expired := fmt.Sprintf(`SELECT id FROM graphs WHERE updated_at < ? AND status IN (%s)`,
+		strings.TrimSuffix(strings.Repeat("?, ", len(terminalGraphStatuses)), ", "))
+	args := []interface{}{cutoff}
+	for _, status := range terminalGraphStatuses {
+		args = append(args, status)
+	}
+
+	// The graphs' WAL locks are dropped once their rows are gone
+	var graphIDs []string
+	rows, err := tx.QueryContext(ctx, expired, args...)
+	if err != nil {
+		return 0, timeoutError(ctx, err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		graphIDs = append(graphIDs, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	// External snapshot payloads are removed once the rows are gone
+	var refs []string
+	rows, err = tx.QueryContext(ctx, `
+		SELECT snapshot_ref FROM snapshots
+		WHERE snapshot_ref IS NOT NULL AND graph_id IN (`+expired+`)
+	`, args...)
+	if err != nil {
+		return 0, timeoutError(ctx, err)
+	}
+	for rows.Next() {
+		var ref string
+		if err := rows.Scan(&ref); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		refs = append(refs, ref)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	tables := []string{"wal_sequences"}
+	for _, t := range graphOwnedTables {
+		tables = append(tables, t.table)
+	}
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE graph_id IN (%s)`, table, expired), args...); err != nil {
+			return 0, fmt.Errorf("failed to clean up %s: %w", table, timeoutError(ctx, err))
+		}
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM graphs WHERE id IN (`+expired+`)`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean up graphs: %w", timeoutError(ctx, err))
+	}
+	deleted, _ := result.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		return 0, timeoutError(ctx, err)
+	}
+
+	for _, ref := range refs {
+		s.deleteExternalSnapshot(ref)
+	}
+	s.forgetSequences(graphIDs...)
+	s.forgetWAL(graphIDs...)
+	if deleted > 0 {
+		log.Printf("[Storage] Deleted %d finished graphs older than %v", deleted, age)
+	}
+	return int(deleted), nil
+}
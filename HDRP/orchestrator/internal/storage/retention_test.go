@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCleanupGraphsOlderThan verifies only finished graphs past the cutoff
+// are deleted, along with everything stored for them.
+func TestCleanupGraphsOlderThan(t *testing.T) {
+	store := newTestStorage(t)
+
+	graphs := map[string]string{
+		"old-succeeded": "SUCCEEDED",
+		"old-failed":    "FAILED",
+		"old-cancelled": "CANCELLED",
+		"old-running":   "RUNNING",
+		"new-succeeded": "SUCCEEDED",
+	}
+	for id, status := range graphs {
+		if err := store.SaveGraph(&GraphState{ID: id, Status: status}); err != nil {
+			t.Fatalf("Failed to save graph: %v", err)
+		}
+		if err := store.SaveNode(id, &NodeState{NodeID: "a", Type: "researcher", Status: "SUCCEEDED"}); err != nil {
+			t.Fatalf("Failed to save node: %v", err)
+		}
+		if err := store.LogMutation(id, MutationUpdateGraphStatus, map[string]string{"status": status}); err != nil {
+			t.Fatalf("Failed to log mutation: %v", err)
+		}
+		if err := store.SaveSnapshot(id, 0, []byte("{}")); err != nil {
+			t.Fatalf("Failed to save snapshot: %v", err)
+		}
+	}
+	if _, err := store.db.Exec(`UPDATE graphs SET updated_at = datetime('now', '-2 hours') WHERE id LIKE 'old-%'`); err != nil {
+		t.Fatalf("Failed to age graphs: %v", err)
+	}
+
+	deleted, err := store.CleanupGraphsOlderThan(time.Hour)
+	if err != nil {
+		t.Fatalf("CleanupGraphsOlderThan failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Deleted %d graphs, want 3", deleted)
+	}
+
+	for id := range graphs {
+		_, err := store.LoadGraph(id)
+		kept := id == "old-running" || id == "new-succeeded"
+		if kept && err != nil {
+			t.Errorf("Graph %s was deleted: %v", id, err)
+		}
+		if !kept && err == nil {
+			t.Errorf("Graph %s was kept", id)
+		}
+	}
+	for _, table := range []string{"nodes", "wal_log", "snapshots"} {
+		var count int
+		if err := store.db.QueryRow(`SELECT COUNT(*) FROM ` + table + ` WHERE graph_id LIKE 'old-%' AND graph_id != 'old-running'`).Scan(&count); err != nil {
+			t.Fatalf("Failed to count %s: %v", table, err)
+		}
+		if count != 0 {
+			t.Errorf("%d %s rows left for deleted graphs", count, table)
+		}
+	}
+
+	counters := &store.sequenceAllocator().(*MemorySequenceAllocator).counters
+	for id := range graphs {
+		_, locked := store.walLocks.Load(id)
+		kept := id == "old-running" || id == "new-succeeded"
+		if locked != kept {
+			t.Errorf("Graph %s has a WAL lock: %v, want %v", id, locked, kept)
+		}
+		if _, counted := counters.Load(id); counted != kept {
+			t.Errorf("Graph %s has a sequence counter: %v, want %v", id, counted, kept)
+		}
+	}
+
+	report, err := store.CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("Expected clean report after cleanup, got %+v", report.Issues)
+	}
+}
+
+func TestInMemoryStorage_CleanupGraphsOlderThan(t *testing.T) {
+	store := NewInMemoryStorage()
+	for id, status := range map[string]string{"done": "SUCCEEDED", "running": "RUNNING"} {
+		if err := store.SaveGraph(&GraphState{ID: id, Status: status}); err != nil {
+			t.Fatalf("Failed to save graph: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	deleted, err := store.CleanupGraphsOlderThan(10 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("CleanupGraphsOlderThan failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Deleted %d graphs, want 1", deleted)
+	}
+	if _, err := store.LoadGraph("done"); err == nil {
+		t.Error("Finished graph was kept")
+	}
+	if _, err := store.LoadGraph("running"); err != nil {
+		t.Errorf("Running graph was deleted: %v", err)
+	}
+}
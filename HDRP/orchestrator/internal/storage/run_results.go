@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RunResult is the final outcome of a run, kept so it can be read after
+// the request that started the run has gone.
+type RunResult struct {
+	RunID           string
+	GraphID         string
+	Status          string // Final graph status
+	Success         bool
+	PartialSuccess  bool
+	FinalReport     string
+	ArtifactURI     string
+	ReportTruncated bool
+	ErrorMessage    string
+	SucceededNodes  int
+	FailedNodes     int
+	StartedAt       time.Time // Zero for results saved before start times were recorded
+	CompletedAt     time.Time
+}
+
+// SaveRunResult persists a run's outcome, replacing any earlier result for
+// the same run ID.
+func (s *SQLiteStorage) SaveRunResult(result *RunResult) error {
+	_, err := s.exec(`
+		INSERT INTO run_results (
+			run_id, graph_id, status, success, partial_success, final_report, artifact_uri,
+			report_truncated, error_message, succeeded_nodes, failed_nodes, started_at, completed_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(run_id) DO UPDATE SET
+			graph_id = excluded.graph_id,
+			status = excluded.status,
+			success = excluded.success,
+			partial_success = excluded.partial_success,
+			final_report = excluded.final_report,
+			artifact_uri = excluded.artifact_uri,
+			report_truncated = excluded.report_truncated,
+			error_message = excluded.error_message,
+			succeeded_nodes = excluded.succeeded_nodes,
+			failed_nodes = excluded.failed_nodes,
+			started_at = excluded.started_at,
+			completed_at = excluded.completed_at
+	`, result.RunID, result.GraphID, result.Status, result.Success, result.PartialSuccess, result.FinalReport,
+		result.ArtifactURI, result.ReportTruncated, result.ErrorMessage, result.SucceededNodes,
+		result.FailedNodes, nullTime(result.StartedAt), result.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save result of run %s: %w", result.RunID, err)
+	}
+	return nil
+}
+
+// LoadRunResult retrieves a run's outcome.
+// Returns sql.ErrNoRows if no result was stored.
+func (s *SQLiteStorage) LoadRunResult(runID string) (*RunResult, error) {
+	result := &RunResult{RunID: runID}
+	var startedAt sql.NullTime
+	err := s.queryRow(`
+		SELECT graph_id, status, success, partial_success, final_report, artifact_uri,
+			report_truncated, error_message, succeeded_nodes, failed_nodes, started_at, completed_at
+		FROM run_results
+		WHERE run_id = ?
+	`, runID).Scan(&result.GraphID, &result.Status, &result.Success, &result.PartialSuccess, &result.FinalReport,
+		&result.ArtifactURI, &result.ReportTruncated, &result.ErrorMessage, &result.SucceededNodes, &result.FailedNodes,
+		&startedAt, &result.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	result.StartedAt = startedAt.Time
+	return result, nil
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// RunStats aggregates finished runs for a status dashboard.
+type RunStats struct {
+	Since       time.Time      // Start of the window; zero when it covers every run
+	Total       int            // Runs finished in the window
+	ByStatus    map[string]int // Final graph status -> runs
+	Failed      int            // Runs that neither succeeded nor partially succeeded
+	FailureRate float64        // Failed / Total; 0 with no runs
+	AvgNodes    float64        // Mean node count of the runs' graphs
+	AvgDuration time.Duration  // Mean wall time of runs with a recorded start
+}
+
+// GetRunStats aggregates the runs that finished within window of now, or
+// every stored run when window <= 0. The aggregation is done by the
+// database, so no run is loaded into memory.
+func (s *SQLiteStorage) GetRunStats(window time.Duration) (*RunStats, error) {
+	stats := &RunStats{ByStatus: make(map[string]int)}
+	where := ""
+	var args []interface{}
+	if window > 0 {
+		stats.Since = time.Now().Add(-window)
+		where = "WHERE julianday(r.completed_at) >= julianday(?)"
+		args = append(args, stats.Since)
+	}
+
+	rows, err := s.query(`
+		SELECT r.status, COUNT(*), SUM(CASE WHEN r.success OR r.partial_success THEN 0 ELSE 1 END)
+		FROM run_results r
+		`+where+`
+		GROUP BY r.status
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count runs: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count, failed int
+		if err := rows.Scan(&status, &count, &failed); err != nil {
+			return nil, fmt.Errorf("failed to scan run counts: %w", err)
+		}
+		stats.ByStatus[status] = count
+		stats.Total += count
+		stats.Failed += failed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count runs: %w", err)
+	}
+	if stats.Total == 0 {
+		return stats, nil
+	}
+	stats.FailureRate = float64(stats.Failed) / float64(stats.Total)
+
+	var avgNodes, avgSeconds sql.NullFloat64
+	err = s.queryRow(`
+		SELECT
+			AVG((SELECT COUNT(*) FROM nodes n WHERE n.graph_id = r.graph_id)),
+			AVG(CASE WHEN r.started_at IS NOT NULL
+				THEN (julianday(r.completed_at) - julianday(r.started_at)) * 86400.0 END)
+		FROM run_results r
+		`+where, args...).Scan(&avgNodes, &avgSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to average runs: %w", err)
+	}
+	stats.AvgNodes = avgNodes.Float64
+	stats.AvgDuration = time.Duration(avgSeconds.Float64 * float64(time.Second))
+	return stats, nil
+}
@@ -6,7 +6,7 @@ import (
 	"log"
 )
 
-const currentSchemaVersion = 1
+const currentSchemaVersion = 13
 
 // InitSchema creates all required tables and indexes.
 // It's idempotent - safe to call multiple times.
@@ -35,6 +35,20 @@ func InitSchema(db *sql.DB) error {
 		return fmt.Errorf("failed to create tables: %w", err)
 	}
 
+	// Add columns introduced since the database was created
+	if err := ensureColumn(tx, "snapshots", "snapshot_ref", "TEXT"); err != nil {
+		return fmt.Errorf("failed to migrate snapshots table: %w", err)
+	}
+	if err := ensureColumn(tx, "nodes", "phase", "TEXT"); err != nil {
+		return fmt.Errorf("failed to migrate nodes table: %w", err)
+	}
+	if err := ensureColumn(tx, "run_results", "started_at", "TIMESTAMP"); err != nil {
+		return fmt.Errorf("failed to migrate run_results table: %w", err)
+	}
+	if err := ensureColumn(tx, "edges", "condition", "TEXT"); err != nil {
+		return fmt.Errorf("failed to migrate edges table: %w", err)
+	}
+
 	// Create indexes
 	if err := createIndexes(tx); err != nil {
 		return fmt.Errorf("failed to create indexes: %w", err)
@@ -89,6 +103,7 @@ func createTables(tx *sql.Tx) error {
 			depth INTEGER DEFAULT 0,
 			retry_count INTEGER DEFAULT 0,
 			last_error TEXT,
+			phase TEXT,  -- Free-form sub-state shown alongside status
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			PRIMARY KEY (graph_id, node_id),
@@ -104,6 +119,7 @@ func createTables(tx *sql.Tx) error {
 			graph_id TEXT NOT NULL,
 			from_node TEXT NOT NULL,
 			to_node TEXT NOT NULL,
+			condition TEXT,  -- Optional dag.Condition gating the target node
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			PRIMARY KEY (graph_id, from_node, to_node),
 			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
@@ -132,7 +148,8 @@ func createTables(tx *sql.Tx) error {
 		CREATE TABLE IF NOT EXISTS snapshots (
 			graph_id TEXT PRIMARY KEY,
 			sequence_num INTEGER NOT NULL,  -- Last WAL sequence included in snapshot
-			snapshot_data TEXT NOT NULL,  -- JSON encoded full graph state
+			snapshot_data TEXT NOT NULL,  -- JSON encoded full graph state, gzipped behind a format byte when smaller; empty when stored externally
+			snapshot_ref TEXT,  -- Location of the payload in the snapshot store, if external
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
 		)
@@ -140,6 +157,128 @@ func createTables(tx *sql.Tx) error {
 		return fmt.Errorf("failed to create snapshots table: %w", err)
 	}
 
+	// Graph leases table - tracks which recovery worker owns a resumable graph
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS graph_leases (
+			graph_id TEXT PRIMARY KEY,
+			worker_id TEXT NOT NULL,
+			expires_at INTEGER NOT NULL,  -- Unix nanoseconds
+			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create graph_leases table: %w", err)
+	}
+
+	// Node results table - serialized node output offloaded from executor memory
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS node_results (
+			graph_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			data BLOB NOT NULL,  -- JSON encoded node result
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (graph_id, node_id),
+			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create node_results table: %w", err)
+	}
+
+	// Resume failures table - how many resumed runs each node has failed in
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS node_resume_failures (
+			graph_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			failures INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (graph_id, node_id),
+			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create node_resume_failures table: %w", err)
+	}
+
+	// Run results table - final outcome of each run, kept after the run ends
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS run_results (
+			run_id TEXT PRIMARY KEY,
+			graph_id TEXT NOT NULL,
+			status TEXT NOT NULL,  -- Final graph status
+			success BOOLEAN NOT NULL,
+			partial_success BOOLEAN NOT NULL DEFAULT 0,
+			final_report TEXT NOT NULL DEFAULT '',
+			artifact_uri TEXT NOT NULL DEFAULT '',
+			report_truncated BOOLEAN NOT NULL DEFAULT 0,
+			error_message TEXT NOT NULL DEFAULT '',
+			succeeded_nodes INTEGER NOT NULL DEFAULT 0,
+			failed_nodes INTEGER NOT NULL DEFAULT 0,
+			started_at TIMESTAMP,  -- NULL for results saved before it was recorded
+			completed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create run_results table: %w", err)
+	}
+
+	// WAL sequences table - last reserved sequence number per graph, for
+	// allocation shared between processes
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS wal_sequences (
+			graph_id TEXT PRIMARY KEY,
+			last_seq INTEGER NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create wal_sequences table: %w", err)
+	}
+
+	// Checkpoints table - retry state of nodes that failed, per run
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS checkpoints (
+			run_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			attempt_number INTEGER NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			timestamp TIMESTAMP NOT NULL,
+			PRIMARY KEY (run_id, node_id)
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create checkpoints table: %w", err)
+	}
+
+	// Breaker states table - circuit breaker state per service, restored
+	// when the executor restarts
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS breaker_states (
+			service_type TEXT PRIMARY KEY,
+			state INTEGER NOT NULL,
+			failures INTEGER NOT NULL,
+			successes INTEGER NOT NULL,
+			consecutive_successes INTEGER NOT NULL,
+			reopens INTEGER NOT NULL,
+			last_failure_at TIMESTAMP,
+			opened_at TIMESTAMP,
+			updated_at TIMESTAMP NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create breaker_states table: %w", err)
+	}
+
+	// Dead letters table - nodes that failed permanently or ran out of
+	// retries, kept for inspection and reprocessing
+	if _, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS dead_letters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			graph_id TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			node_type TEXT NOT NULL,
+			config TEXT NOT NULL,  -- JSON encoded node config
+			last_error TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP NOT NULL,
+			FOREIGN KEY (graph_id) REFERENCES graphs(id) ON DELETE CASCADE
+		)
+	`); err != nil {
+		return fmt.Errorf("failed to create dead_letters table: %w", err)
+	}
+
 	return nil
 }
 
@@ -151,6 +290,9 @@ func createIndexes(tx *sql.Tx) error {
 		`CREATE INDEX IF NOT EXISTS idx_edges_to ON edges(graph_id, to_node)`,
 		`CREATE INDEX IF NOT EXISTS idx_wal_graph_seq ON wal_log(graph_id, sequence_num)`,
 		`CREATE INDEX IF NOT EXISTS idx_wal_replayed ON wal_log(replayed)`,
+		`CREATE INDEX IF NOT EXISTS idx_graphs_status ON graphs(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_graphs_updated ON graphs(updated_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_dead_letters_graph ON dead_letters(graph_id)`,
 	}
 
 	for _, idx := range indexes {
@@ -162,6 +304,39 @@ func createIndexes(tx *sql.Tx) error {
 	return nil
 }
 
+// ensureColumn adds a column to a table created by an older schema version.
+func ensureColumn(tx *sql.Tx, table, column, decl string) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, decl))
+	return err
+}
+
 func getSchemaVersion(db *sql.DB) (int, error) {
 	var version int
 	err := db.QueryRow("SELECT version FROM schema_version ORDER BY version DESC LIMIT 1").Scan(&version)
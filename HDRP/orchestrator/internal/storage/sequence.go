@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Sequence allocation modes for SetSequenceMode.
+const (
+	// SequencesMemory counts in process, seeded from the WAL at startup.
+	// Fast, but only correct while a single process writes the database.
+	SequencesMemory = "memory"
+	// SequencesDatabase reserves each number with an atomic database
+	// update, so several processes can share a database.
+	SequencesDatabase = "database"
+)
+
+// SequenceAllocator hands out WAL sequence numbers, one gap-free series per
+// graph starting at 0.
+type SequenceAllocator interface {
+	// Next reserves and returns the graph's next sequence number.
+	Next(graphID string) (int64, error)
+	// Last returns the most recently reserved sequence number, or -1 if
+	// none has been.
+	Last(graphID string) (int64, error)
+}
+
+// txSequenceAllocator is a SequenceAllocator that reserves numbers in the
+// transaction inserting the WAL entries, so a reservation commits or rolls
+// back with its entry.
+type txSequenceAllocator interface {
+	nextTx(ctx context.Context, tx *sql.Tx, graphID string) (int64, error)
+}
+
+// sequenceRewinder is a SequenceAllocator that reserves numbers outside
+// the transaction inserting the WAL entries. A reservation is handed back if
+// the transaction doesn't commit, and a graph's counter is dropped with its
+// WAL.
+type sequenceRewinder interface {
+	// rewind makes last the graph's most recently reserved number again.
+	rewind(graphID string, last int64)
+	// forget drops the counter of a graph whose WAL was deleted.
+	forget(graphID string)
+}
+
+// MemorySequenceAllocator keeps one atomic counter per graph, so graphs
+// don't contend with each other.
+type MemorySequenceAllocator struct {
+	counters sync.Map // graph_id -> *atomic.Int64 holding the next number
+}
+
+// NewMemorySequenceAllocator creates an allocator continuing from next,
+// which maps graph IDs to their next sequence number.
+func NewMemorySequenceAllocator(next map[string]int64) *MemorySequenceAllocator {
+	a := &MemorySequenceAllocator{}
+	for graphID, seq := range next {
+		a.counter(graphID).Store(seq)
+	}
+	return a
+}
+
+func (a *MemorySequenceAllocator) counter(graphID string) *atomic.Int64 {
+	if c, ok := a.counters.Load(graphID); ok {
+		return c.(*atomic.Int64)
+	}
+	c, _ := a.counters.LoadOrStore(graphID, new(atomic.Int64))
+	return c.(*atomic.Int64)
+}
+
+// Next reserves the graph's next sequence number.
+func (a *MemorySequenceAllocator) Next(graphID string) (int64, error) {
+	return a.counter(graphID).Add(1) - 1, nil
+}
+
+// Last returns the most recently reserved sequence number, or -1.
+func (a *MemorySequenceAllocator) Last(graphID string) (int64, error) {
+	return a.counter(graphID).Load() - 1, nil
+}
+
+func (a *MemorySequenceAllocator) rewind(graphID string, last int64) {
+	a.counter(graphID).Store(last + 1)
+}
+
+func (a *MemorySequenceAllocator) forget(graphID string) {
+	a.counters.Delete(graphID)
+}
+
+// dbSequenceAllocator reserves sequence numbers in the wal_sequences table.
+// A graph's first reservation continues from its highest logged entry, and
+// later ones never fall behind it, so switching modes is safe.
+type dbSequenceAllocator struct {
+	s *SQLiteStorage
+}
+
+// reserveSequenceSQL reserves a graph's next sequence number, continuing
+// from MAX(sequence_num) + 1 of its logged entries.
+const reserveSequenceSQL = `
+	INSERT INTO wal_sequences (graph_id, last_seq)
+	VALUES (?, COALESCE((SELECT MAX(sequence_num) FROM wal_log WHERE graph_id = ?) + 1, 0))
+	ON CONFLICT(graph_id) DO UPDATE SET
+		last_seq = MAX(last_seq + 1, excluded.last_seq)
+	RETURNING last_seq
+`
+
+func (a *dbSequenceAllocator) Next(graphID string) (int64, error) {
+	var seq int64
+	if err := a.s.queryRow(reserveSequenceSQL, graphID, graphID).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to reserve WAL sequence for graph %s: %w", graphID, err)
+	}
+	return seq, nil
+}
+
+func (a *dbSequenceAllocator) nextTx(ctx context.Context, tx *sql.Tx, graphID string) (int64, error) {
+	var seq int64
+	if err := tx.QueryRowContext(ctx, reserveSequenceSQL, graphID, graphID).Scan(&seq); err != nil {
+		return 0, fmt.Errorf("failed to reserve WAL sequence for graph %s: %w", graphID, timeoutError(ctx, err))
+	}
+	return seq, nil
+}
+
+func (a *dbSequenceAllocator) Last(graphID string) (int64, error) {
+	var seq int64
+	err := a.s.queryRow(`SELECT last_seq FROM wal_sequences WHERE graph_id = ?`, graphID).Scan(&seq)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = a.s.queryRow(`
+			SELECT COALESCE(MAX(sequence_num), -1) FROM wal_log WHERE graph_id = ?
+		`, graphID).Scan(&seq)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read WAL sequence for graph %s: %w", graphID, err)
+	}
+	return seq, nil
+}
+
+// SetSequenceMode selects how WAL sequence numbers are allocated:
+// SequencesMemory (the default) or SequencesDatabase.
+func (s *SQLiteStorage) SetSequenceMode(mode string) error {
+	switch mode {
+	case "", SequencesMemory:
+		next, err := s.loadSequenceNumbers()
+		if err != nil {
+			return fmt.Errorf("failed to load sequence numbers: %w", err)
+		}
+		s.SetSequenceAllocator(NewMemorySequenceAllocator(next))
+	case SequencesDatabase:
+		s.SetSequenceAllocator(&dbSequenceAllocator{s: s})
+	default:
+		return fmt.Errorf("unsupported WAL sequence mode: %s", mode)
+	}
+	return nil
+}
+
+// SetSequenceAllocator replaces how WAL sequence numbers are allocated.
+func (s *SQLiteStorage) SetSequenceAllocator(allocator SequenceAllocator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequences = allocator
+}
+
+func (s *SQLiteStorage) sequenceAllocator() SequenceAllocator {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sequences
+}
+
+// loadSequenceNumbers returns the next sequence number of every graph with
+// logged WAL entries.
+func (s *SQLiteStorage) loadSequenceNumbers() (map[string]int64, error) {
+	rows, err := s.query("SELECT graph_id, MAX(sequence_num) FROM wal_log GROUP BY graph_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	next := make(map[string]int64)
+	for rows.Next() {
+		var graphID string
+		var maxSeq int64
+		if err := rows.Scan(&graphID, &maxSeq); err != nil {
+			return nil, err
+		}
+		next[graphID] = maxSeq + 1
+	}
+	return next, rows.Err()
+}
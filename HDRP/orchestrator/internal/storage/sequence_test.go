@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestSQLiteStorage_ConcurrentSequences(t *testing.T) {
+	for _, mode := range []string{SequencesMemory, SequencesDatabase} {
+		t.Run(mode, func(t *testing.T) {
+			store := newTestStorage(t)
+			if err := store.SetSequenceMode(mode); err != nil {
+				t.Fatalf("SetSequenceMode failed: %v", err)
+			}
+
+			graphs := []string{"seq-a", "seq-b"}
+			const writers, perWriter = 8, 25
+			var wg sync.WaitGroup
+			errs := make(chan error, writers*perWriter*len(graphs))
+			for w := 0; w < writers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < perWriter; i++ {
+						for _, graphID := range graphs {
+							payload := &AddEdgePayload{From: fmt.Sprintf("w%d", w), To: fmt.Sprintf("n%d", i)}
+							if err := store.LogMutation(graphID, MutationAddEdge, payload); err != nil {
+								errs <- err
+							}
+						}
+					}
+				}(w)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Fatalf("LogMutation failed: %v", err)
+			}
+
+			for _, graphID := range graphs {
+				entries, err := store.GetUnreplayedWAL(graphID)
+				if err != nil {
+					t.Fatalf("GetUnreplayedWAL failed: %v", err)
+				}
+				if len(entries) != writers*perWriter {
+					t.Fatalf("Graph %s logged %d entries, want %d", graphID, len(entries), writers*perWriter)
+				}
+				// Entries come back in sequence order, which must be 0..n-1
+				for i, entry := range entries {
+					if entry.SequenceNum != int64(i) {
+						t.Fatalf("Graph %s entry %d has sequence %d: numbers are not gap-free", graphID, i, entry.SequenceNum)
+					}
+				}
+				if last, err := store.sequenceAllocator().Last(graphID); err != nil || last != writers*perWriter-1 {
+					t.Errorf("Last(%s) = %d, %v; want %d", graphID, last, err, writers*perWriter-1)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLiteStorage_SequenceModeSwitch(t *testing.T) {
+	store := newTestStorage(t)
+	for i := 0; i < 3; i++ {
+		store.LogMutation("seq-switch", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"})
+	}
+
+	// Database allocation picks up where in-process counting left off, and
+	// switching back reseeds from the WAL
+	store.SetSequenceMode(SequencesDatabase)
+	store.LogMutation("seq-switch", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"})
+	store.SetSequenceMode(SequencesMemory)
+	store.LogMutation("seq-switch", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"})
+	store.SetSequenceMode(SequencesDatabase)
+	store.LogMutation("seq-switch", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"})
+
+	entries, _ := store.GetUnreplayedWAL("seq-switch")
+	for i, entry := range entries {
+		if entry.SequenceNum != int64(i) {
+			t.Fatalf("Entry %d has sequence %d after switching modes", i, entry.SequenceNum)
+		}
+	}
+	if len(entries) != 6 {
+		t.Errorf("Logged %d entries, want 6", len(entries))
+	}
+
+	if err := store.SetSequenceMode("redis"); err == nil {
+		t.Error("Expected an error for an unsupported mode")
+	}
+}
+
+// TestSQLiteStorage_WALInsertOrder verifies concurrent mutations of one graph
+// are written in sequence order, not just numbered without gaps.
+func TestSQLiteStorage_WALInsertOrder(t *testing.T) {
+	store := newTestStorage(t)
+
+	const writers = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			if err := store.LogMutation("seq-order", MutationAddEdge, &AddEdgePayload{From: "a", To: fmt.Sprintf("n%d", w)}); err != nil {
+				errs <- err
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("LogMutation failed: %v", err)
+	}
+
+	entries, err := store.GetUnreplayedWAL("seq-order")
+	if err != nil {
+		t.Fatalf("GetUnreplayedWAL failed: %v", err)
+	}
+	if len(entries) != writers {
+		t.Fatalf("Logged %d entries, want %d", len(entries), writers)
+	}
+	for i, entry := range entries {
+		if entry.SequenceNum != int64(i) {
+			t.Fatalf("Entry %d has sequence %d: numbers have gaps or duplicates", i, entry.SequenceNum)
+		}
+		if i > 0 && entry.ID < entries[i-1].ID {
+			t.Fatalf("Sequence %d was inserted before sequence %d", entry.SequenceNum, entries[i-1].SequenceNum)
+		}
+	}
+}
+
+// TestSQLiteStorage_FailedInsertKeepsSequence verifies numbers reserved in
+// process for a batch that rolls back are handed back, so the series has no
+// gap where the batch would have been.
+func TestSQLiteStorage_FailedInsertKeepsSequence(t *testing.T) {
+	store := newTestStorage(t)
+	if _, err := store.db.Exec(`
+		CREATE TRIGGER reject_wal BEFORE INSERT ON wal_log
+		WHEN NEW.payload LIKE '%reject%'
+		BEGIN SELECT RAISE(ABORT, 'rejected'); END
+	`); err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+
+	if err := store.LogMutation("seq-rollback", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"}); err != nil {
+		t.Fatalf("LogMutation failed: %v", err)
+	}
+	err := store.LogMutations("seq-rollback", []MutationRequest{
+		{Type: MutationAddEdge, Payload: &AddEdgePayload{From: "a", To: "c"}},
+		{Type: MutationAddEdge, Payload: &AddEdgePayload{From: "a", To: "reject"}},
+	})
+	if err == nil {
+		t.Fatal("Expected the rejected batch to fail")
+	}
+	if err := store.LogMutation("seq-rollback", MutationAddEdge, &AddEdgePayload{From: "a", To: "d"}); err != nil {
+		t.Fatalf("LogMutation failed: %v", err)
+	}
+
+	entries, _ := store.GetUnreplayedWAL("seq-rollback")
+	if len(entries) != 2 {
+		t.Fatalf("Logged %d entries, want 2", len(entries))
+	}
+	for i, entry := range entries {
+		if entry.SequenceNum != int64(i) {
+			t.Errorf("Entry %d has sequence %d after a rolled back batch", i, entry.SequenceNum)
+		}
+	}
+}
+
+// TestSQLiteStorage_DeleteGraphDropsWALLock verifies deleting a graph drops
+// its WAL lock, so the lock map doesn't grow with every graph logged.
+func TestSQLiteStorage_DeleteGraphDropsWALLock(t *testing.T) {
+	store := newTestStorage(t)
+	if err := store.SaveGraph(&GraphState{ID: "seq-delete", Status: "SUCCEEDED"}); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	if err := store.LogMutation("seq-delete", MutationAddEdge, &AddEdgePayload{From: "a", To: "b"}); err != nil {
+		t.Fatalf("LogMutation failed: %v", err)
+	}
+	if _, ok := store.walLocks.Load("seq-delete"); !ok {
+		t.Fatal("Expected a WAL lock after logging a mutation")
+	}
+
+	if err := store.DeleteGraph("seq-delete"); err != nil {
+		t.Fatalf("DeleteGraph failed: %v", err)
+	}
+	if _, ok := store.walLocks.Load("seq-delete"); ok {
+		t.Error("Expected DeleteGraph to drop the graph's WAL lock")
+	}
+}
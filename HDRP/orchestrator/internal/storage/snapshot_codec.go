@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// snapshotFormatGzip prefixes snapshot payloads stored as gzipped JSON.
+// Older snapshots are plain JSON, which never starts with this byte.
+const snapshotFormatGzip byte = 0x01
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// compressSnapshot gzips a JSON snapshot behind the format byte. Payloads
+// that don't shrink are returned unchanged.
+func compressSnapshot(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(snapshotFormatGzip)
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	if buf.Len() >= len(data) {
+		return data, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// isCompressedSnapshot reports whether data was written by compressSnapshot.
+func isCompressedSnapshot(data []byte) bool {
+	return len(data) > len(gzipMagic) && data[0] == snapshotFormatGzip && bytes.HasPrefix(data[1:], gzipMagic)
+}
+
+// decompressSnapshot returns the JSON of a snapshot payload, whether it was
+// stored compressed or as plain JSON.
+func decompressSnapshot(data []byte) ([]byte, error) {
+	if !isCompressedSnapshot(data) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data[1:]))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer zr.Close()
+	decoded, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	return decoded, nil
+}
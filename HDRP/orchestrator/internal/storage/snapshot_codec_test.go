@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestSnapshotCompression measures how much a large graph's snapshot shrinks
+// and verifies it recovers intact.
+func TestSnapshotCompression(t *testing.T) {
+	store := newTestStorage(t)
+
+	graphID := "compressed-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		node := &NodeState{
+			NodeID: fmt.Sprintf("node-%d", i),
+			Type:   "researcher",
+			Status: "SUCCEEDED",
+			Config: map[string]string{
+				"query":        fmt.Sprintf("Research question %d about distributed scheduling and retries", i),
+				"max_results":  "10",
+				"instructions": strings.Repeat("Cite primary sources and summarize each claim. ", 8),
+			},
+		}
+		if err := store.SaveNode(graphID, node); err != nil {
+			t.Fatalf("Failed to save node: %v", err)
+		}
+		store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: *node})
+	}
+	if err := store.CreateSnapshot(graphID); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+
+	var stored []byte
+	if err := store.db.QueryRow("SELECT snapshot_data FROM snapshots WHERE graph_id = ?", graphID).Scan(&stored); err != nil {
+		t.Fatalf("Failed to read snapshot row: %v", err)
+	}
+	snapshot, err := store.LoadSnapshot(graphID)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if !isCompressedSnapshot(stored) {
+		t.Fatal("Expected the stored snapshot to be compressed")
+	}
+	ratio := float64(len(stored)) / float64(len(snapshot.Data))
+	t.Logf("Snapshot of %d nodes: %d bytes of JSON stored in %d bytes (%.1f%%)", 200, len(snapshot.Data), len(stored), 100*ratio)
+	if ratio > 0.25 {
+		t.Errorf("Compressed snapshot is %.1f%% of the JSON, want at most 25%%", 100*ratio)
+	}
+
+	recovered, err := store.RecoverGraph(graphID)
+	if err != nil {
+		t.Fatalf("RecoverGraph() error = %v", err)
+	}
+	if len(recovered.Nodes) != 200 || recovered.Nodes["node-199"].Config["max_results"] != "10" {
+		t.Errorf("Recovered %d nodes from the compressed snapshot, want 200 with their config", len(recovered.Nodes))
+	}
+}
+
+// TestSnapshotCompression_ReadsUncompressed verifies snapshots written before
+// compression still load.
+func TestSnapshotCompression_ReadsUncompressed(t *testing.T) {
+	store := newTestStorage(t)
+
+	graphID := "legacy-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	state := &RecoveredGraphState{
+		Graph: &GraphState{ID: graphID, Status: "RUNNING"},
+		Nodes: map[string]*NodeState{"n1": {NodeID: "n1", Type: "researcher", Status: "SUCCEEDED"}},
+	}
+	data, _ := json.Marshal(state)
+	if _, err := store.db.Exec("INSERT INTO snapshots (graph_id, sequence_num, snapshot_data) VALUES (?, ?, ?)", graphID, 0, string(data)); err != nil {
+		t.Fatalf("Failed to insert legacy snapshot: %v", err)
+	}
+
+	snapshot, err := store.LoadSnapshot(graphID)
+	if err != nil {
+		t.Fatalf("LoadSnapshot() error = %v", err)
+	}
+	if string(snapshot.Data) != string(data) {
+		t.Errorf("LoadSnapshot() = %q, want the stored JSON", snapshot.Data)
+	}
+	decoded, err := decodeSnapshot(snapshot.Data)
+	if err != nil || decoded.Nodes["n1"] == nil {
+		t.Errorf("decodeSnapshot() = %+v, %v; want node n1", decoded, err)
+	}
+
+	// Small payloads that gzip can't shrink are stored as they are
+	small := []byte(`{}`)
+	if got, _ := compressSnapshot(small); string(got) != string(small) {
+		t.Errorf("compressSnapshot(%q) = %q, want it unchanged", small, got)
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SnapshotStore keeps snapshot payloads outside the database. The snapshots
+// table then holds only a reference to each payload, so large graphs don't
+// bloat the primary database. The WAL always stays in SQLite.
+type SnapshotStore interface {
+	// Put stores a graph's snapshot taken at seqNum and returns a reference
+	// for Get and Delete.
+	Put(graphID string, seqNum int64, data []byte) (ref string, err error)
+	Get(ref string) ([]byte, error)
+	Delete(ref string) error
+}
+
+// SetSnapshotStore moves snapshot payloads of at least minBytes to store.
+// Smaller snapshots stay inline in the snapshots table. A nil store keeps
+// every snapshot inline.
+func (s *SQLiteStorage) SetSnapshotStore(store SnapshotStore, minBytes int) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+	s.snapshotStore = store
+	s.snapshotMinBytes = minBytes
+}
+
+// externalSnapshotStore returns the store a snapshot of size bytes belongs
+// in, or nil to keep it inline.
+func (s *SQLiteStorage) externalSnapshotStore(size int) SnapshotStore {
+	s.snapshotMu.RLock()
+	defer s.snapshotMu.RUnlock()
+	if s.snapshotStore == nil || size < s.snapshotMinBytes {
+		return nil
+	}
+	return s.snapshotStore
+}
+
+// snapshotStoreForRef returns the store holding an external snapshot.
+func (s *SQLiteStorage) snapshotStoreForRef(ref string) (SnapshotStore, error) {
+	s.snapshotMu.RLock()
+	defer s.snapshotMu.RUnlock()
+	if s.snapshotStore == nil {
+		return nil, fmt.Errorf("snapshot %s is stored externally but no snapshot store is configured", ref)
+	}
+	return s.snapshotStore, nil
+}
+
+// deleteExternalSnapshot removes a replaced or orphaned external payload.
+// Failures only leak a file, so they are logged rather than returned.
+func (s *SQLiteStorage) deleteExternalSnapshot(ref string) {
+	if ref == "" {
+		return
+	}
+	store, err := s.snapshotStoreForRef(ref)
+	if err == nil {
+		err = store.Delete(ref)
+	}
+	if err != nil {
+		log.Printf("[Storage] Warning: failed to delete external snapshot %s: %v", ref, err)
+	}
+}
+
+// FileSnapshotStore keeps snapshots as files under a directory.
+type FileSnapshotStore struct {
+	dir string
+}
+
+// NewFileSnapshotStore creates a store rooted at dir, creating it if needed.
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve snapshot directory: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &FileSnapshotStore{dir: abs}, nil
+}
+
+// Put writes the snapshot to dir/<graph>-<seq>.json and returns its file://
+// URI. The file is written under a temporary name and renamed, so a crash
+// never leaves a partial snapshot behind a committed reference.
+func (s *FileSnapshotStore) Put(graphID string, seqNum int64, data []byte) (string, error) {
+	name := fmt.Sprintf("%s-%d.json", strings.NewReplacer("/", "_", "\\", "_").Replace(graphID), seqNum)
+	path := filepath.Join(s.dir, name)
+
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
+// Get reads a snapshot written by Put.
+func (s *FileSnapshotStore) Get(ref string) ([]byte, error) {
+	path, err := s.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes a snapshot written by Put. Deleting a missing snapshot is
+// not an error.
+func (s *FileSnapshotStore) Delete(ref string) error {
+	path, err := s.path(ref)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete snapshot file: %w", err)
+	}
+	return nil
+}
+
+// path resolves a reference to a file inside the store's directory.
+func (s *FileSnapshotStore) path(ref string) (string, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "file" {
+		return "", fmt.Errorf("invalid snapshot reference %q", ref)
+	}
+	path := filepath.FromSlash(u.Path)
+	if filepath.Dir(path) != s.dir {
+		return "", fmt.Errorf("snapshot reference %q is outside %s", ref, s.dir)
+	}
+	return path, nil
+}
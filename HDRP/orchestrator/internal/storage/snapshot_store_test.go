@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSQLiteStorage_ExternalSnapshots(t *testing.T) {
+	store := newTestStorage(t)
+	snapshotDir := filepath.Join(t.TempDir(), "snapshots")
+	snapshots, err := NewFileSnapshotStore(snapshotDir)
+	if err != nil {
+		t.Fatalf("NewFileSnapshotStore() error = %v", err)
+	}
+	store.SetSnapshotStore(snapshots, 64*1024)
+
+	graphID := "large-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	query := strings.Repeat("q", 200)
+	for i := 0; i < 500; i++ {
+		node := &NodeState{NodeID: fmt.Sprintf("node-%d", i), Type: "researcher", Status: "SUCCEEDED", Config: map[string]string{"query": query}}
+		if err := store.SaveNode(graphID, node); err != nil {
+			t.Fatalf("Failed to save node: %v", err)
+		}
+		store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: *node})
+	}
+
+	if err := store.CreateSnapshot(graphID); err != nil {
+		t.Fatalf("CreateSnapshot() error = %v", err)
+	}
+
+	// Only a reference is kept in the database
+	var inline string
+	var ref string
+	if err := store.db.QueryRow("SELECT snapshot_data, snapshot_ref FROM snapshots WHERE graph_id = ?", graphID).Scan(&inline, &ref); err != nil {
+		t.Fatalf("Failed to read snapshot row: %v", err)
+	}
+	if inline != "" || !strings.HasPrefix(ref, "file://") {
+		t.Fatalf("Expected an external snapshot, got %d inline bytes and ref %q", len(inline), ref)
+	}
+	files, _ := os.ReadDir(snapshotDir)
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 snapshot file, got %d", len(files))
+	}
+
+	// Recovery reads the snapshot back and replays what came after it
+	store.LogMutation(graphID, MutationUpdateNodeStatus, &UpdateNodeStatusPayload{NodeID: "node-0", OldStatus: "SUCCEEDED", NewStatus: "FAILED"})
+	recovered, err := store.RecoverGraph(graphID)
+	if err != nil {
+		t.Fatalf("RecoverGraph() error = %v", err)
+	}
+	if len(recovered.Nodes) != 500 {
+		t.Errorf("Expected 500 recovered nodes, got %d", len(recovered.Nodes))
+	}
+	if got := recovered.Nodes["node-0"].Status; got != "FAILED" {
+		t.Errorf("Expected node-0 FAILED after replay, got %s", got)
+	}
+	if got := recovered.Nodes["node-499"].Config["query"]; got != query {
+		t.Errorf("Node config not restored from snapshot: %q", got)
+	}
+
+	// Deleting the graph removes the external payload too
+	if err := store.DeleteGraph(graphID); err != nil {
+		t.Fatalf("DeleteGraph() error = %v", err)
+	}
+	if files, _ := os.ReadDir(snapshotDir); len(files) != 0 {
+		t.Errorf("Expected snapshot file to be removed, found %d files", len(files))
+	}
+}
+
+func TestSQLiteStorage_SmallSnapshotsStayInline(t *testing.T) {
+	store := newTestStorage(t)
+	snapshotDir := filepath.Join(t.TempDir(), "snapshots")
+	snapshots, err := NewFileSnapshotStore(snapshotDir)
+	if err != nil {
+		t.Fatalf("NewFileSnapshotStore() error = %v", err)
+	}
+	store.SetSnapshotStore(snapshots, 64*1024)
+
+	if err := store.SaveGraph(&GraphState{ID: "small", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := store.SaveSnapshot("small", 1, []byte(`{"small":true}`)); err != nil {
+		t.Fatalf("SaveSnapshot() error = %v", err)
+	}
+
+	snapshot, err := store.LoadSnapshot("small")
+	if err != nil || snapshot == nil || string(snapshot.Data) != `{"small":true}` {
+		t.Fatalf("LoadSnapshot() = %+v, %v", snapshot, err)
+	}
+	if files, _ := os.ReadDir(snapshotDir); len(files) != 0 {
+		t.Errorf("Expected small snapshot inline, found %d files", len(files))
+	}
+}
+
+func TestFileSnapshotStore_RejectsForeignRefs(t *testing.T) {
+	snapshots, err := NewFileSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSnapshotStore() error = %v", err)
+	}
+	for _, ref := range []string{"file:///etc/passwd", "s3://bucket/key", "not a url"} {
+		if _, err := snapshots.Get(ref); err == nil {
+			t.Errorf("Get(%q) should fail", ref)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -19,21 +21,36 @@ type Storage interface {
 	LoadGraph(graphID string) (*GraphState, error)
 	UpdateGraphStatus(graphID string, status string) error
 	DeleteGraph(graphID string) error
+	CleanupGraphsOlderThan(age time.Duration) (int, error)
+	ListGraphs(offset, limit int) ([]*GraphState, int, error)
 
 	// Node operations
 	SaveNode(graphID string, node *NodeState) error
 	LoadNodes(graphID string) ([]*NodeState, error)
 	UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error
+	UpdateNodePhase(graphID string, nodeID string, phase string) error
+	DeleteNode(graphID string, nodeID string) error
 
 	// Edge operations
 	SaveEdge(graphID string, from, to string) error
+	SaveConditionalEdge(graphID string, from, to, condition string) error
 	LoadEdges(graphID string) ([]*EdgeState, error)
+	DeleteEdge(graphID string, from, to string) error
+
+	// Node result operations
+	SaveNodeResult(graphID string, nodeID string, data []byte) error
+	LoadNodeResult(graphID string, nodeID string) ([]byte, error)
+
+	// Dead letter operations
+	AppendDeadLetter(graphID, nodeID, nodeType string, config map[string]string, lastError string) error
+	ListDeadLetters(graphID string) ([]*DeadLetter, error)
 
 	// WAL operations
 	AppendWAL(entry *WALEntry) error
 	GetUnreplayedWAL(graphID string) ([]*WALEntry, error)
 	MarkWALReplayed(graphID string, upToSeqNum int64) error
 	LogMutation(graphID string, mutationType MutationType, payload interface{}) error
+	LogMutations(graphID string, mutations []MutationRequest) error
 
 	// Snapshot operations
 	SaveSnapshot(graphID string, seqNum int64, data []byte) error
@@ -81,12 +98,14 @@ type NodeState struct {
 	Depth          int
 	RetryCount     int
 	LastError      string
+	Phase          string // Free-form sub-state for display, e.g. BACKOFF
 }
 
 // EdgeState represents persisted edge.
 type EdgeState struct {
-	From string
-	To   string
+	From      string
+	To        string
+	Condition string `json:",omitempty"` // Empty for unconditional edges
 }
 
 // Snapshot represents a state snapshot.
@@ -98,9 +117,17 @@ type Snapshot struct {
 
 // SQLiteStorage implements Storage using SQLite.
 type SQLiteStorage struct {
-	db         *sql.DB
-	mu         sync.RWMutex
-	seqNumbers map[string]int64 // graph_id -> next sequence number
+	db        *sql.DB
+	mu        sync.RWMutex      // Guards sequences
+	sequences SequenceAllocator // Hands out WAL sequence numbers
+	walLocks  sync.Map          // graph_id -> *sync.Mutex ordering sequence allocation with its WAL insert
+	claimMu   sync.Mutex        // Serializes ClaimResumableGraph
+	timeoutMu sync.RWMutex      // Guards opTimeout
+	opTimeout time.Duration     // Bound on each query or statement; <= 0 disables
+
+	snapshotMu       sync.RWMutex  // Guards snapshotStore and snapshotMinBytes
+	snapshotStore    SnapshotStore // External home for large snapshots; nil keeps all inline
+	snapshotMinBytes int           // Smallest snapshot moved to snapshotStore
 }
 
 // NewSQLiteStorage creates a new SQLite-backed storage.
@@ -139,48 +166,20 @@ func NewSQLiteStorage() (*SQLiteStorage, error) {
 	}
 
 	store := &SQLiteStorage{
-		db:         db,
-		seqNumbers: make(map[string]int64),
+		db:        db,
+		opTimeout: DefaultOpTimeout,
 	}
 
-	// Load current sequence numbers
-	if err := store.loadSequenceNumbers(); err != nil {
+	// Continue each graph's WAL sequence in process until told otherwise
+	if err := store.SetSequenceMode(SequencesMemory); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to load sequence numbers: %w", err)
+		return nil, err
 	}
 
 	log.Printf("[Storage] SQLite storage initialized at %s", dbPath)
 	return store, nil
 }
 
-func (s *SQLiteStorage) loadSequenceNumbers() error {
-	rows, err := s.db.Query("SELECT graph_id, MAX(sequence_num) FROM wal_log GROUP BY graph_id")
-	if err != nil {
-		return err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var graphID string
-		var maxSeq int64
-		if err := rows.Scan(&graphID, &maxSeq); err != nil {
-			return err
-		}
-		s.seqNumbers[graphID] = maxSeq + 1
-	}
-
-	return rows.Err()
-}
-
-func (s *SQLiteStorage) getNextSeqNum(graphID string) int64 {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	seq := s.seqNumbers[graphID]
-	s.seqNumbers[graphID] = seq + 1
-	return seq
-}
-
 // SaveGraph persists a graph's metadata.
 func (s *SQLiteStorage) SaveGraph(graph *GraphState) error {
 	metadataJSON, err := json.Marshal(graph.Metadata)
@@ -188,7 +187,7 @@ func (s *SQLiteStorage) SaveGraph(graph *GraphState) error {
 		return fmt.Errorf("failed to encode metadata: %w", err)
 	}
 
-	_, err = s.db.Exec(`
+	_, err = s.exec(`
 		INSERT INTO graphs (id, status, metadata)
 		VALUES (?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
@@ -205,7 +204,7 @@ func (s *SQLiteStorage) LoadGraph(graphID string) (*GraphState, error) {
 	var graph GraphState
 	var metadataJSON string
 
-	err := s.db.QueryRow(`
+	err := s.queryRow(`
 		SELECT id, status, metadata
 		FROM graphs
 		WHERE id = ?
@@ -222,9 +221,53 @@ func (s *SQLiteStorage) LoadGraph(graphID string) (*GraphState, error) {
 	return &graph, nil
 }
 
+// ListGraphs returns a page of graphs, most recently updated first, and the
+// total number of stored graphs. limit <= 0 returns every graph after offset.
+func (s *SQLiteStorage) ListGraphs(offset, limit int) ([]*GraphState, int, error) {
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = -1 // SQLite: no limit
+	}
+
+	var total int
+	if err := s.queryRow("SELECT COUNT(*) FROM graphs").Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count graphs: %w", err)
+	}
+
+	rows, err := s.query(`
+		SELECT id, status, metadata
+		FROM graphs
+		ORDER BY updated_at DESC, rowid DESC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list graphs: %w", err)
+	}
+	defer rows.Close()
+
+	var graphs []*GraphState
+	for rows.Next() {
+		var graph GraphState
+		var metadataJSON sql.NullString
+		if err := rows.Scan(&graph.ID, &graph.Status, &metadataJSON); err != nil {
+			return nil, 0, err
+		}
+		if metadataJSON.Valid && metadataJSON.String != "" {
+			if err := json.Unmarshal([]byte(metadataJSON.String), &graph.Metadata); err != nil {
+				return nil, 0, fmt.Errorf("failed to decode metadata for graph %s: %w", graph.ID, err)
+			}
+		}
+		graphs = append(graphs, &graph)
+	}
+
+	return graphs, total, rows.Err()
+}
+
 // UpdateGraphStatus updates only the graph's status.
 func (s *SQLiteStorage) UpdateGraphStatus(graphID string, status string) error {
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		UPDATE graphs
 		SET status = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE id = ?
@@ -234,8 +277,18 @@ func (s *SQLiteStorage) UpdateGraphStatus(graphID string, status string) error {
 
 // DeleteGraph removes a graph and all related data (cascading).
 func (s *SQLiteStorage) DeleteGraph(graphID string) error {
-	_, err := s.db.Exec("DELETE FROM graphs WHERE id = ?", graphID)
-	return err
+	// The snapshot row goes with the graph; its external payload doesn't
+	var ref sql.NullString
+	if err := s.queryRow("SELECT snapshot_ref FROM snapshots WHERE graph_id = ?", graphID).Scan(&ref); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up snapshot: %w", err)
+	}
+
+	if _, err := s.exec("DELETE FROM graphs WHERE id = ?", graphID); err != nil {
+		return err
+	}
+	s.deleteExternalSnapshot(ref.String)
+	s.forgetWAL(graphID)
+	return nil
 }
 
 // SaveNode persists a node's state.
@@ -245,9 +298,9 @@ func (s *SQLiteStorage) SaveNode(graphID string, node *NodeState) error {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
 
-	_, err = s.db.Exec(`
-		INSERT INTO nodes (graph_id, node_id, type, config, status, relevance_score, depth, retry_count, last_error)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	_, err = s.exec(`
+		INSERT INTO nodes (graph_id, node_id, type, config, status, relevance_score, depth, retry_count, last_error, phase)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(graph_id, node_id) DO UPDATE SET
 			type = excluded.type,
 			config = excluded.config,
@@ -256,17 +309,18 @@ func (s *SQLiteStorage) SaveNode(graphID string, node *NodeState) error {
 			depth = excluded.depth,
 			retry_count = excluded.retry_count,
 			last_error = excluded.last_error,
+			phase = excluded.phase,
 			updated_at = CURRENT_TIMESTAMP
 	`, graphID, node.NodeID, node.Type, string(configJSON), node.Status,
-		node.RelevanceScore, node.Depth, node.RetryCount, node.LastError)
+		node.RelevanceScore, node.Depth, node.RetryCount, node.LastError, node.Phase)
 
 	return err
 }
 
 // LoadNodes retrieves all nodes for a graph.
 func (s *SQLiteStorage) LoadNodes(graphID string) ([]*NodeState, error) {
-	rows, err := s.db.Query(`
-		SELECT node_id, type, config, status, relevance_score, depth, retry_count, last_error
+	rows, err := s.query(`
+		SELECT node_id, type, config, status, relevance_score, depth, retry_count, last_error, phase
 		FROM nodes
 		WHERE graph_id = ?
 		ORDER BY created_at
@@ -280,10 +334,10 @@ func (s *SQLiteStorage) LoadNodes(graphID string) ([]*NodeState, error) {
 	for rows.Next() {
 		var node NodeState
 		var configJSON string
-		var lastError sql.NullString
+		var lastError, phase sql.NullString
 
 		err := rows.Scan(&node.NodeID, &node.Type, &configJSON, &node.Status,
-			&node.RelevanceScore, &node.Depth, &node.RetryCount, &lastError)
+			&node.RelevanceScore, &node.Depth, &node.RetryCount, &lastError, &phase)
 		if err != nil {
 			return nil, err
 		}
@@ -295,6 +349,7 @@ func (s *SQLiteStorage) LoadNodes(graphID string) ([]*NodeState, error) {
 		if lastError.Valid {
 			node.LastError = lastError.String
 		}
+		node.Phase = phase.String
 
 		nodes = append(nodes, &node)
 	}
@@ -304,7 +359,7 @@ func (s *SQLiteStorage) LoadNodes(graphID string) ([]*NodeState, error) {
 
 // UpdateNodeStatus updates a node's status and retry information.
 func (s *SQLiteStorage) UpdateNodeStatus(graphID string, nodeID string, status string, retryCount int, lastError string) error {
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		UPDATE nodes
 		SET status = ?, retry_count = ?, last_error = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE graph_id = ? AND node_id = ?
@@ -312,19 +367,63 @@ func (s *SQLiteStorage) UpdateNodeStatus(graphID string, nodeID string, status s
 	return err
 }
 
+// UpdateNodePhase sets a node's phase, leaving its status untouched.
+func (s *SQLiteStorage) UpdateNodePhase(graphID string, nodeID string, phase string) error {
+	_, err := s.exec(`
+		UPDATE nodes
+		SET phase = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE graph_id = ? AND node_id = ?
+	`, phase, graphID, nodeID)
+	return err
+}
+
+// DeleteNode removes a node along with its incident edges and stored result,
+// in one transaction so a failure leaves all three in place.
+func (s *SQLiteStorage) DeleteNode(graphID string, nodeID string) error {
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"DELETE FROM edges WHERE graph_id = ? AND (from_node = ? OR to_node = ?)", []interface{}{graphID, nodeID, nodeID}},
+		{"DELETE FROM node_results WHERE graph_id = ? AND node_id = ?", []interface{}{graphID, nodeID}},
+		{"DELETE FROM nodes WHERE graph_id = ? AND node_id = ?", []interface{}{graphID, nodeID}},
+	}
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return timeoutError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			return fmt.Errorf("failed to delete node %s: %w", nodeID, timeoutError(ctx, err))
+		}
+	}
+	return timeoutError(ctx, tx.Commit())
+}
+
 // SaveEdge persists an edge.
 func (s *SQLiteStorage) SaveEdge(graphID string, from, to string) error {
-	_, err := s.db.Exec(`
-		INSERT OR IGNORE INTO edges (graph_id, from_node, to_node)
-		VALUES (?, ?, ?)
-	`, graphID, from, to)
+	return s.SaveConditionalEdge(graphID, from, to, "")
+}
+
+// SaveConditionalEdge persists an edge gated by condition. Saving an
+// existing edge is a no-op.
+func (s *SQLiteStorage) SaveConditionalEdge(graphID string, from, to, condition string) error {
+	_, err := s.exec(`
+		INSERT OR IGNORE INTO edges (graph_id, from_node, to_node, condition)
+		VALUES (?, ?, ?, ?)
+	`, graphID, from, to, condition)
 	return err
 }
 
 // LoadEdges retrieves all edges for a graph.
 func (s *SQLiteStorage) LoadEdges(graphID string) ([]*EdgeState, error) {
-	rows, err := s.db.Query(`
-		SELECT from_node, to_node
+	rows, err := s.query(`
+		SELECT from_node, to_node, COALESCE(condition, '')
 		FROM edges
 		WHERE graph_id = ?
 	`, graphID)
@@ -336,7 +435,7 @@ func (s *SQLiteStorage) LoadEdges(graphID string) ([]*EdgeState, error) {
 	var edges []*EdgeState
 	for rows.Next() {
 		var edge EdgeState
-		if err := rows.Scan(&edge.From, &edge.To); err != nil {
+		if err := rows.Scan(&edge.From, &edge.To, &edge.Condition); err != nil {
 			return nil, err
 		}
 		edges = append(edges, &edge)
@@ -345,13 +444,52 @@ func (s *SQLiteStorage) LoadEdges(graphID string) ([]*EdgeState, error) {
 	return edges, rows.Err()
 }
 
-// BeginTx starts a new transaction.
-func (s *SQLiteStorage) BeginTx() (Transaction, error) {
-	tx, err := s.db.Begin()
+// DeleteEdge removes an edge. Deleting a missing edge is a no-op.
+func (s *SQLiteStorage) DeleteEdge(graphID string, from, to string) error {
+	_, err := s.exec(`
+		DELETE FROM edges
+		WHERE graph_id = ? AND from_node = ? AND to_node = ?
+	`, graphID, from, to)
+	return err
+}
+
+// SaveNodeResult stores a node's serialized result, replacing any previous value.
+func (s *SQLiteStorage) SaveNodeResult(graphID string, nodeID string, data []byte) error {
+	_, err := s.exec(`
+		INSERT INTO node_results (graph_id, node_id, data)
+		VALUES (?, ?, ?)
+		ON CONFLICT(graph_id, node_id) DO UPDATE SET
+			data = excluded.data,
+			created_at = CURRENT_TIMESTAMP
+	`, graphID, nodeID, data)
+	return err
+}
+
+// LoadNodeResult retrieves a node's serialized result.
+// Returns sql.ErrNoRows if no result was stored.
+func (s *SQLiteStorage) LoadNodeResult(graphID string, nodeID string) ([]byte, error) {
+	var data []byte
+	err := s.queryRow(`
+		SELECT data
+		FROM node_results
+		WHERE graph_id = ? AND node_id = ?
+	`, graphID, nodeID).Scan(&data)
 	if err != nil {
 		return nil, err
 	}
-	return &sqliteTx{tx: tx, storage: s}, nil
+	return data, nil
+}
+
+// BeginTx starts a new transaction. The op timeout bounds the whole
+// transaction: once it passes the transaction is rolled back.
+func (s *SQLiteStorage) BeginTx() (Transaction, error) {
+	ctx, cancel := s.opContext()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, err)
+	}
+	return &sqliteTx{tx: tx, storage: s, ctx: ctx, cancel: cancel}, nil
 }
 
 // Close closes the database connection.
@@ -363,6 +501,8 @@ func (s *SQLiteStorage) Close() error {
 type sqliteTx struct {
 	tx      *sql.Tx
 	storage *SQLiteStorage
+	ctx     context.Context
+	cancel  context.CancelFunc
 }
 
 func (t *sqliteTx) SaveGraph(graph *GraphState) error {
@@ -371,7 +511,7 @@ func (t *sqliteTx) SaveGraph(graph *GraphState) error {
 		return fmt.Errorf("failed to encode metadata: %w", err)
 	}
 
-	_, err = t.tx.Exec(`
+	_, err = t.tx.ExecContext(t.ctx, `
 		INSERT INTO graphs (id, status, metadata)
 		VALUES (?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
@@ -380,7 +520,7 @@ func (t *sqliteTx) SaveGraph(graph *GraphState) error {
 			updated_at = CURRENT_TIMESTAMP
 	`, graph.ID, graph.Status, string(metadataJSON))
 
-	return err
+	return timeoutError(t.ctx, err)
 }
 
 func (t *sqliteTx) SaveNode(graphID string, node *NodeState) error {
@@ -389,9 +529,9 @@ func (t *sqliteTx) SaveNode(graphID string, node *NodeState) error {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
 
-	_, err = t.tx.Exec(`
-		INSERT INTO nodes (graph_id, node_id, type, config, status, relevance_score, depth, retry_count, last_error)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	_, err = t.tx.ExecContext(t.ctx, `
+		INSERT INTO nodes (graph_id, node_id, type, config, status, relevance_score, depth, retry_count, last_error, phase)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(graph_id, node_id) DO UPDATE SET
 			type = excluded.type,
 			config = excluded.config,
@@ -400,19 +540,20 @@ func (t *sqliteTx) SaveNode(graphID string, node *NodeState) error {
 			depth = excluded.depth,
 			retry_count = excluded.retry_count,
 			last_error = excluded.last_error,
+			phase = excluded.phase,
 			updated_at = CURRENT_TIMESTAMP
 	`, graphID, node.NodeID, node.Type, string(configJSON), node.Status,
-		node.RelevanceScore, node.Depth, node.RetryCount, node.LastError)
+		node.RelevanceScore, node.Depth, node.RetryCount, node.LastError, node.Phase)
 
-	return err
+	return timeoutError(t.ctx, err)
 }
 
 func (t *sqliteTx) SaveEdge(graphID string, from, to string) error {
-	_, err := t.tx.Exec(`
+	_, err := t.tx.ExecContext(t.ctx, `
 		INSERT OR IGNORE INTO edges (graph_id, from_node, to_node)
 		VALUES (?, ?, ?)
 	`, graphID, from, to)
-	return err
+	return timeoutError(t.ctx, err)
 }
 
 func (t *sqliteTx) AppendWAL(entry *WALEntry) error {
@@ -421,18 +562,20 @@ func (t *sqliteTx) AppendWAL(entry *WALEntry) error {
 		return fmt.Errorf("failed to encode WAL payload: %w", err)
 	}
 
-	_, err = t.tx.Exec(`
+	_, err = t.tx.ExecContext(t.ctx, `
 		INSERT INTO wal_log (graph_id, mutation_type, payload, sequence_num)
 		VALUES (?, ?, ?, ?)
 	`, entry.GraphID, entry.MutationType, string(payloadJSON), entry.SequenceNum)
 
-	return err
+	return timeoutError(t.ctx, err)
 }
 
 func (t *sqliteTx) Commit() error {
-	return t.tx.Commit()
+	defer t.cancel()
+	return timeoutError(t.ctx, t.tx.Commit())
 }
 
 func (t *sqliteTx) Rollback() error {
+	defer t.cancel()
 	return t.tx.Rollback()
 }
@@ -1,9 +1,13 @@
 package storage
 
 import (
+	"database/sql"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestSQLiteStorage_BasicOperations(t *testing.T) {
@@ -89,9 +93,22 @@ func TestSQLiteStorage_BasicOperations(t *testing.T) {
 		t.Fatalf("Expected 1 edge, got %d", len(edges))
 	}
 
-	if edges[0].From != "node-1" || edges[0].To != "node-2" {
+	if edges[0].From != "node-1" || edges[0].To != "node-2" || edges[0].Condition != "" {
 		t.Errorf("Edge mismatch: got %+v, want from=node-1 to=node-2", edges[0])
 	}
+
+	if err := store.SaveConditionalEdge(graphID, "node-2", "node-3", "rejected_count > 3"); err != nil {
+		t.Fatalf("Failed to save conditional edge: %v", err)
+	}
+	edges, err = store.LoadEdges(graphID)
+	if err != nil || len(edges) != 2 {
+		t.Fatalf("Expected 2 edges, got %d (%v)", len(edges), err)
+	}
+	for _, edge := range edges {
+		if edge.To == "node-3" && edge.Condition != "rejected_count > 3" {
+			t.Errorf("Conditional edge loaded with condition %q", edge.Condition)
+		}
+	}
 }
 
 func TestSQLiteStorage_WALOperations(t *testing.T) {
@@ -346,3 +363,310 @@ func TestSQLiteStorage_Transaction(t *testing.T) {
 		t.Error("Expected error loading rolled-back graph, got nil")
 	}
 }
+
+func TestSQLiteStorage_SnapshotLag(t *testing.T) {
+	store := newTestStorage(t)
+	graphID := "lag-test"
+
+	graph := &GraphState{ID: graphID, Status: "CREATED"}
+	if err := store.SaveGraph(graph); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	store.LogMutation(graphID, MutationCreateGraph, &CreateGraphPayload{Graph: *graph})
+	store.LogMutation(graphID, MutationUpdateGraphStatus, &UpdateGraphStatusPayload{OldStatus: "CREATED", NewStatus: "RUNNING"})
+
+	lag, err := store.SnapshotLag(graphID)
+	if err != nil || lag != 2 {
+		t.Fatalf("SnapshotLag() = %d, %v; want 2 before any snapshot", lag, err)
+	}
+
+	if err := store.CreateSnapshot(graphID); err != nil {
+		t.Fatalf("Failed to create snapshot: %v", err)
+	}
+	if lag, _ := store.SnapshotLag(graphID); lag != 0 {
+		t.Errorf("SnapshotLag() = %d after snapshot, want 0", lag)
+	}
+
+	store.LogMutation(graphID, MutationUpdateGraphStatus, &UpdateGraphStatusPayload{OldStatus: "RUNNING", NewStatus: "SUCCEEDED"})
+	if lag, _ := store.SnapshotLag(graphID); lag != 1 {
+		t.Errorf("SnapshotLag() = %d, want 1", lag)
+	}
+}
+
+func TestSQLiteStorage_RecoverGraphAtSequence(t *testing.T) {
+	store := newTestStorage(t)
+	graphID := "pit-test"
+
+	graph := &GraphState{ID: graphID, Status: "CREATED", Metadata: map[string]string{}}
+	node := &NodeState{NodeID: "node-1", Type: "researcher", Status: "CREATED"}
+	if err := store.SaveGraph(graph); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := store.SaveNode(graphID, node); err != nil {
+		t.Fatalf("Failed to save node: %v", err)
+	}
+
+	// Sequence numbers 0 through 5
+	store.LogMutation(graphID, MutationCreateGraph, &CreateGraphPayload{Graph: *graph})
+	store.LogMutation(graphID, MutationAddNode, &AddNodePayload{Node: *node})
+	store.LogMutation(graphID, MutationUpdateGraphStatus, &UpdateGraphStatusPayload{OldStatus: "CREATED", NewStatus: "RUNNING"})
+	store.LogMutation(graphID, MutationUpdateNodeStatus, &UpdateNodeStatusPayload{NodeID: "node-1", OldStatus: "CREATED", NewStatus: "RUNNING"})
+	store.LogMutation(graphID, MutationUpdateNodeStatus, &UpdateNodeStatusPayload{NodeID: "node-1", OldStatus: "RUNNING", NewStatus: "FAILED", RetryCount: 1, LastError: "boom"})
+	store.LogMutation(graphID, MutationUpdateGraphStatus, &UpdateGraphStatusPayload{OldStatus: "RUNNING", NewStatus: "FAILED"})
+
+	assertState := func(t *testing.T, state *RecoveredGraphState, graphStatus, nodeStatus string) {
+		t.Helper()
+		if state.Graph.Status != graphStatus {
+			t.Errorf("Graph status = %s, want %s", state.Graph.Status, graphStatus)
+		}
+		if got := state.Nodes["node-1"]; got == nil || got.Status != nodeStatus {
+			t.Errorf("Node status = %+v, want %s", got, nodeStatus)
+		}
+	}
+
+	// The node was running but had not failed yet at sequence 3
+	state, err := store.RecoverGraphAtSequence(graphID, 3)
+	if err != nil {
+		t.Fatalf("RecoverGraphAtSequence(3) failed: %v", err)
+	}
+	assertState(t, state, "RUNNING", "RUNNING")
+	if state.Nodes["node-1"].LastError != "" {
+		t.Errorf("Expected no error recorded at sequence 3, got %q", state.Nodes["node-1"].LastError)
+	}
+
+	// A snapshot taken later is not used for an earlier point in time
+	store.UpdateGraphStatus(graphID, "FAILED")
+	store.UpdateNodeStatus(graphID, "node-1", "FAILED", 1, "boom")
+	if err := store.CreateSnapshot(graphID); err != nil {
+		t.Fatalf("Failed to create snapshot: %v", err)
+	}
+	state, err = store.RecoverGraphAtSequence(graphID, 4)
+	if err != nil {
+		t.Fatalf("RecoverGraphAtSequence(4) failed: %v", err)
+	}
+	assertState(t, state, "RUNNING", "FAILED")
+
+	state, err = store.RecoverGraphAtSequence(graphID, 5)
+	if err != nil {
+		t.Fatalf("RecoverGraphAtSequence(5) failed: %v", err)
+	}
+	assertState(t, state, "FAILED", "FAILED")
+
+	if _, err := store.RecoverGraphAtSequence(graphID, 42); err == nil {
+		t.Error("Expected error for a sequence that was never logged")
+	}
+
+	// Point-in-time recovery leaves the WAL unreplayed for normal recovery
+	entries, err := store.GetUnreplayedWAL(graphID)
+	if err != nil || len(entries) != 6 {
+		t.Errorf("GetUnreplayedWAL() = %d entries, %v; want 6", len(entries), err)
+	}
+}
+
+func TestSQLiteStorage_ListGraphs(t *testing.T) {
+	store := newTestStorage(t)
+
+	for _, id := range []string{"g1", "g2", "g3"} {
+		if err := store.SaveGraph(&GraphState{ID: id, Status: "CREATED", Metadata: map[string]string{"goal": id}}); err != nil {
+			t.Fatalf("Failed to save graph: %v", err)
+		}
+	}
+
+	page, total, err := store.ListGraphs(0, 2)
+	if err != nil {
+		t.Fatalf("ListGraphs failed: %v", err)
+	}
+	if total != 3 {
+		t.Errorf("total = %d, want 3", total)
+	}
+	if len(page) != 2 || page[0].ID != "g3" || page[1].ID != "g2" {
+		t.Fatalf("First page = %v, want [g3 g2]", graphIDs(page))
+	}
+	if page[0].Metadata["goal"] != "g3" {
+		t.Errorf("Metadata not loaded: %v", page[0].Metadata)
+	}
+
+	page, _, err = store.ListGraphs(2, 2)
+	if err != nil || len(page) != 1 || page[0].ID != "g1" {
+		t.Errorf("Second page = %v, %v; want [g1]", graphIDs(page), err)
+	}
+
+	if page, _, _ := store.ListGraphs(0, 0); len(page) != 3 {
+		t.Errorf("limit 0 returned %d graphs, want all 3", len(page))
+	}
+}
+
+func TestSQLiteStorage_RunResults(t *testing.T) {
+	store := newTestStorage(t)
+
+	if _, err := store.LoadRunResult("run-1"); err != sql.ErrNoRows {
+		t.Fatalf("LoadRunResult(missing) error = %v, want sql.ErrNoRows", err)
+	}
+
+	completed := time.Now().UTC().Truncate(time.Second)
+	result := &RunResult{
+		RunID: "run-1", GraphID: "g1", Status: "FAILED", ErrorMessage: "boom",
+		SucceededNodes: 1, FailedNodes: 2, CompletedAt: completed,
+	}
+	if err := store.SaveRunResult(result); err != nil {
+		t.Fatalf("SaveRunResult failed: %v", err)
+	}
+
+	// A rerun under the same ID replaces the result
+	result.Status, result.Success, result.ErrorMessage = "SUCCEEDED", true, ""
+	result.FinalReport, result.ArtifactURI = "report", "file:///report.md"
+	if err := store.SaveRunResult(result); err != nil {
+		t.Fatalf("SaveRunResult failed: %v", err)
+	}
+
+	loaded, err := store.LoadRunResult("run-1")
+	if err != nil {
+		t.Fatalf("LoadRunResult failed: %v", err)
+	}
+	if !loaded.CompletedAt.Equal(completed) {
+		t.Errorf("CompletedAt = %v, want %v", loaded.CompletedAt, completed)
+	}
+	loaded.CompletedAt = result.CompletedAt
+	if *loaded != *result {
+		t.Errorf("Loaded %+v, want %+v", loaded, result)
+	}
+}
+
+func TestSQLiteStorage_GetRunStats(t *testing.T) {
+	store := newTestStorage(t)
+
+	for graphID, nodes := range map[string]int{"g1": 3, "g2": 1} {
+		if err := store.SaveGraph(&GraphState{ID: graphID, Status: "SUCCEEDED"}); err != nil {
+			t.Fatalf("Failed to save graph: %v", err)
+		}
+		for i := 0; i < nodes; i++ {
+			store.SaveNode(graphID, &NodeState{NodeID: fmt.Sprintf("n%d", i), Type: "task", Status: "SUCCEEDED"})
+		}
+	}
+
+	now := time.Now()
+	runs := []*RunResult{
+		{RunID: "r1", GraphID: "g1", Status: "SUCCEEDED", Success: true, StartedAt: now.Add(-10 * time.Second), CompletedAt: now.Add(-5 * time.Second)},
+		{RunID: "r2", GraphID: "g1", Status: "FAILED", StartedAt: now.Add(-4 * time.Second), CompletedAt: now.Add(-time.Second)},
+		// Partial success isn't a failure; no start time leaves it out of the average duration
+		{RunID: "r3", GraphID: "g2", Status: "SUCCEEDED", PartialSuccess: true, CompletedAt: now},
+		{RunID: "r4", GraphID: "g2", Status: "FAILED", StartedAt: now.Add(-48*time.Hour - 20*time.Second), CompletedAt: now.Add(-48 * time.Hour)},
+	}
+	for _, run := range runs {
+		if err := store.SaveRunResult(run); err != nil {
+			t.Fatalf("SaveRunResult failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		window      time.Duration
+		total       int
+		succeeded   int
+		failed      int
+		avgNodes    float64
+		avgDuration time.Duration
+	}{
+		{window: 24 * time.Hour, total: 3, succeeded: 2, failed: 1, avgNodes: 7.0 / 3, avgDuration: 4 * time.Second},
+		{window: 0, total: 4, succeeded: 2, failed: 2, avgNodes: 2, avgDuration: 28 * time.Second / 3},
+	}
+	for _, tt := range tests {
+		stats, err := store.GetRunStats(tt.window)
+		if err != nil {
+			t.Fatalf("GetRunStats(%v) failed: %v", tt.window, err)
+		}
+		if stats.Total != tt.total || stats.ByStatus["SUCCEEDED"] != tt.succeeded || stats.ByStatus["FAILED"] != tt.failed || stats.Failed != tt.failed {
+			t.Errorf("GetRunStats(%v) counts = %+v", tt.window, stats)
+		}
+		if want := float64(tt.failed) / float64(tt.total); math.Abs(stats.FailureRate-want) > 1e-9 {
+			t.Errorf("GetRunStats(%v) failure rate = %v, want %v", tt.window, stats.FailureRate, want)
+		}
+		if math.Abs(stats.AvgNodes-tt.avgNodes) > 1e-9 {
+			t.Errorf("GetRunStats(%v) avg nodes = %v, want %v", tt.window, stats.AvgNodes, tt.avgNodes)
+		}
+		if diff := stats.AvgDuration - tt.avgDuration; diff < -10*time.Millisecond || diff > 10*time.Millisecond {
+			t.Errorf("GetRunStats(%v) avg duration = %v, want %v", tt.window, stats.AvgDuration, tt.avgDuration)
+		}
+	}
+
+	loaded, _ := store.LoadRunResult("r1")
+	if !loaded.StartedAt.Equal(runs[0].StartedAt) {
+		t.Errorf("StartedAt = %v, want %v", loaded.StartedAt, runs[0].StartedAt)
+	}
+}
+
+func graphIDs(graphs []*GraphState) []string {
+	ids := make([]string, len(graphs))
+	for i, g := range graphs {
+		ids[i] = g.ID
+	}
+	return ids
+}
+
+func TestSQLiteStorage_NodePhase(t *testing.T) {
+	store := newTestStorage(t)
+
+	graphID := "phase-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	if err := store.SaveNode(graphID, &NodeState{NodeID: "node-1", Type: "researcher", Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save node: %v", err)
+	}
+
+	if err := store.UpdateNodePhase(graphID, "node-1", "BACKOFF"); err != nil {
+		t.Fatalf("UpdateNodePhase failed: %v", err)
+	}
+	nodes, err := store.LoadNodes(graphID)
+	if err != nil {
+		t.Fatalf("Failed to load nodes: %v", err)
+	}
+	if nodes[0].Phase != "BACKOFF" || nodes[0].Status != "RUNNING" {
+		t.Errorf("Loaded node = %+v, want phase BACKOFF with status unchanged", nodes[0])
+	}
+
+	// A status update keeps the phase
+	if err := store.UpdateNodeStatus(graphID, "node-1", "SUCCEEDED", 0, ""); err != nil {
+		t.Fatalf("UpdateNodeStatus failed: %v", err)
+	}
+	nodes, _ = store.LoadNodes(graphID)
+	if nodes[0].Phase != "BACKOFF" {
+		t.Errorf("Phase after status update = %q, want BACKOFF", nodes[0].Phase)
+	}
+}
+
+func TestSQLiteStorage_DeleteNodeAndEdge(t *testing.T) {
+	store := newTestStorage(t)
+
+	graphID := "delete-graph"
+	if err := store.SaveGraph(&GraphState{ID: graphID, Status: "RUNNING"}); err != nil {
+		t.Fatalf("Failed to save graph: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := store.SaveNode(graphID, &NodeState{NodeID: id, Type: "task", Status: "CREATED"}); err != nil {
+			t.Fatalf("Failed to save node: %v", err)
+		}
+	}
+	store.SaveEdge(graphID, "a", "b")
+	store.SaveEdge(graphID, "b", "c")
+	store.SaveEdge(graphID, "a", "c")
+	store.SaveNodeResult(graphID, "b", []byte("result"))
+
+	if err := store.DeleteNode(graphID, "b"); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+	nodes, _ := store.LoadNodes(graphID)
+	edges, _ := store.LoadEdges(graphID)
+	if len(nodes) != 2 || len(edges) != 1 {
+		t.Errorf("After DeleteNode: %d nodes and %d edges, want 2 and 1", len(nodes), len(edges))
+	}
+	if _, err := store.LoadNodeResult(graphID, "b"); err != sql.ErrNoRows {
+		t.Errorf("Expected deleted node's result to be gone, got %v", err)
+	}
+
+	if err := store.DeleteEdge(graphID, "a", "c"); err != nil {
+		t.Fatalf("DeleteEdge failed: %v", err)
+	}
+	if edges, _ := store.LoadEdges(graphID); len(edges) != 0 {
+		t.Errorf("After DeleteEdge: %d edges, want 0", len(edges))
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultOpTimeout bounds each storage operation unless overridden with
+// SetOpTimeout. A locked or wedged database then surfaces as an error
+// instead of hanging the caller. Waits on another connection's write lock
+// are governed by SQLite's busy timeout, which the driver cannot interrupt.
+const DefaultOpTimeout = 30 * time.Second
+
+// ErrOpTimeout is returned when a storage operation exceeds the op timeout.
+var ErrOpTimeout = errors.New("storage operation timed out")
+
+// SetOpTimeout sets how long a single query or statement may run. A
+// non-positive timeout disables the bound.
+func (s *SQLiteStorage) SetOpTimeout(timeout time.Duration) {
+	s.timeoutMu.Lock()
+	defer s.timeoutMu.Unlock()
+	s.opTimeout = timeout
+}
+
+// OpTimeout returns the current per-operation timeout.
+func (s *SQLiteStorage) OpTimeout() time.Duration {
+	s.timeoutMu.RLock()
+	defer s.timeoutMu.RUnlock()
+	return s.opTimeout
+}
+
+// opContext returns a context bounded by the op timeout.
+func (s *SQLiteStorage) opContext() (context.Context, context.CancelFunc) {
+	timeout := s.OpTimeout()
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// exec runs a statement under the op timeout.
+func (s *SQLiteStorage) exec(query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := s.opContext()
+	defer cancel()
+	res, err := s.db.ExecContext(ctx, query, args...)
+	return res, timeoutError(ctx, err)
+}
+
+// query runs a query under the op timeout. The timeout covers iterating the
+// rows and is released when they are closed.
+func (s *SQLiteStorage) query(query string, args ...interface{}) (*timedRows, error) {
+	ctx, cancel := s.opContext()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, err)
+	}
+	return &timedRows{Rows: rows, ctx: ctx, cancel: cancel}, nil
+}
+
+// queryRow runs a single-row query under the op timeout. The timeout is
+// released once the row is scanned.
+func (s *SQLiteStorage) queryRow(query string, args ...interface{}) *timedRow {
+	ctx, cancel := s.opContext()
+	return &timedRow{row: s.db.QueryRowContext(ctx, query, args...), ctx: ctx, cancel: cancel}
+}
+
+// timedRows releases its op timeout when closed.
+type timedRows struct {
+	*sql.Rows
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *timedRows) Close() error {
+	err := r.Rows.Close()
+	r.cancel()
+	return err
+}
+
+func (r *timedRows) Err() error {
+	return timeoutError(r.ctx, r.Rows.Err())
+}
+
+// timedRow releases its op timeout when scanned.
+type timedRow struct {
+	row    *sql.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *timedRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return timeoutError(r.ctx, r.row.Scan(dest...))
+}
+
+// timeoutError reports err as ErrOpTimeout when ctx expired, leaving other
+// errors (including sql.ErrNoRows) untouched.
+func timeoutError(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrOpTimeout, err)
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStorage_OpTimeout(t *testing.T) {
+	store := newTestStorage(t)
+	store.SetOpTimeout(100 * time.Millisecond)
+
+	// A runaway query is interrupted once the op timeout passes
+	start := time.Now()
+	var n int64
+	err := store.queryRow(`
+		WITH RECURSIVE spin(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM spin)
+		SELECT COUNT(*) FROM spin
+	`).Scan(&n)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrOpTimeout) {
+		t.Fatalf("Expected ErrOpTimeout, got %v", err)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Query ran for %v, expected it to stop near the 100ms timeout", elapsed)
+	}
+
+	// The store stays usable afterwards
+	if err := store.SaveGraph(&GraphState{ID: "graph-1", Status: "CREATED"}); err != nil {
+		t.Fatalf("SaveGraph after timeout failed: %v", err)
+	}
+	if _, err := store.LoadGraph("graph-1"); err != nil {
+		t.Fatalf("LoadGraph after timeout failed: %v", err)
+	}
+}
+
+func TestTimeoutError_KeepsDriverError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	driverErr := errors.New("interrupted")
+	err := timeoutError(ctx, driverErr)
+	if !errors.Is(err, ErrOpTimeout) {
+		t.Errorf("Expected ErrOpTimeout, got %v", err)
+	}
+	if !errors.Is(err, driverErr) {
+		t.Errorf("Expected the driver error to be wrapped, got %v", err)
+	}
+}
+
+func TestSQLiteStorage_TxOpTimeout(t *testing.T) {
+	store := newTestStorage(t)
+	store.SetOpTimeout(50 * time.Millisecond)
+
+	// A transaction that commits within the timeout is unaffected
+	tx, err := store.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if err := tx.SaveGraph(&GraphState{ID: "graph-1", Status: "CREATED"}); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+
+	// One held open past the timeout is rolled back
+	tx, err = store.BeginTx()
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := tx.SaveGraph(&GraphState{ID: "graph-2", Status: "CREATED"}); !errors.Is(err, ErrOpTimeout) {
+		t.Errorf("Expected ErrOpTimeout from expired transaction, got %v", err)
+	}
+	tx.Rollback()
+	if _, err := store.LoadGraph("graph-2"); err == nil {
+		t.Error("Expected graph-2 not to be saved")
+	}
+}
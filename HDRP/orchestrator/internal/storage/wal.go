@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sync"
+	"time"
 )
 
 // MutationType represents the type of mutation being logged.
@@ -17,6 +19,8 @@ const (
 	MutationUpdateNodeStatus MutationType = "UPDATE_NODE_STATUS"
 	MutationAddEdge          MutationType = "ADD_EDGE"
 	MutationSignalReceived   MutationType = "SIGNAL_RECEIVED"
+	MutationRemoveNode       MutationType = "REMOVE_NODE"
+	MutationRemoveEdge       MutationType = "REMOVE_EDGE"
 )
 
 // WALEntry represents a single write-ahead log entry.
@@ -49,9 +53,22 @@ type UpdateNodeStatusPayload struct {
 	NewStatus   string
 	RetryCount  int
 	LastError   string
+	Attempt     int       // Execution attempt (1-based) that made the transition; 0 if unknown
+	Timestamp   time.Time // When the transition happened; zero in older entries
 }
 
 type AddEdgePayload struct {
+	From      string
+	To        string
+	Condition string `json:",omitempty"`
+}
+
+// RemoveNodePayload records a node removal; its incident edges go with it.
+type RemoveNodePayload struct {
+	NodeID string
+}
+
+type RemoveEdgePayload struct {
 	From string
 	To   string
 }
@@ -62,6 +79,12 @@ type SignalReceivedPayload struct {
 	Payload    map[string]string
 }
 
+// MutationRequest is one mutation to log with LogMutations.
+type MutationRequest struct {
+	Type    MutationType
+	Payload interface{}
+}
+
 // AppendWAL adds a mutation entry to the write-ahead log.
 func (s *SQLiteStorage) AppendWAL(entry *WALEntry) error {
 	payloadJSON, err := json.Marshal(entry.Payload)
@@ -69,7 +92,7 @@ func (s *SQLiteStorage) AppendWAL(entry *WALEntry) error {
 		return fmt.Errorf("failed to encode WAL payload: %w", err)
 	}
 
-	result, err := s.db.Exec(`
+	result, err := s.exec(`
 		INSERT INTO wal_log (graph_id, mutation_type, payload, sequence_num)
 		VALUES (?, ?, ?, ?)
 	`, entry.GraphID, entry.MutationType, string(payloadJSON), entry.SequenceNum)
@@ -88,7 +111,7 @@ func (s *SQLiteStorage) AppendWAL(entry *WALEntry) error {
 
 // GetUnreplayedWAL retrieves all unreplayed WAL entries for a graph in sequence order.
 func (s *SQLiteStorage) GetUnreplayedWAL(graphID string) ([]*WALEntry, error) {
-	rows, err := s.db.Query(`
+	rows, err := s.query(`
 		SELECT id, graph_id, mutation_type, payload, sequence_num
 		FROM wal_log
 		WHERE graph_id = ? AND replayed = 0
@@ -120,9 +143,43 @@ func (s *SQLiteStorage) GetUnreplayedWAL(graphID string) ([]*WALEntry, error) {
 	return entries, rows.Err()
 }
 
+// getWALRange retrieves a graph's WAL entries with afterSeqNum < sequence_num
+// <= upToSeqNum in sequence order, whether or not they have been replayed.
+func (s *SQLiteStorage) getWALRange(graphID string, afterSeqNum, upToSeqNum int64) ([]*WALEntry, error) {
+	rows, err := s.query(`
+		SELECT id, graph_id, mutation_type, payload, sequence_num
+		FROM wal_log
+		WHERE graph_id = ? AND sequence_num > ? AND sequence_num <= ?
+		ORDER BY sequence_num
+	`, graphID, afterSeqNum, upToSeqNum)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*WALEntry
+	for rows.Next() {
+		var entry WALEntry
+		var payloadJSON string
+
+		if err := rows.Scan(&entry.ID, &entry.GraphID, &entry.MutationType, &payloadJSON, &entry.SequenceNum); err != nil {
+			return nil, err
+		}
+
+		entry.Payload, err = decodeWALPayload(entry.MutationType, payloadJSON)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode WAL entry %d: %w", entry.ID, err)
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
 // MarkWALReplayed marks WAL entries as replayed up to a sequence number.
 func (s *SQLiteStorage) MarkWALReplayed(graphID string, upToSeqNum int64) error {
-	_, err := s.db.Exec(`
+	_, err := s.exec(`
 		UPDATE wal_log
 		SET replayed = 1
 		WHERE graph_id = ? AND sequence_num <= ?
@@ -132,7 +189,7 @@ func (s *SQLiteStorage) MarkWALReplayed(graphID string, upToSeqNum int64) error
 
 // CleanupOldWAL removes replayed WAL entries before a sequence number.
 func (s *SQLiteStorage) CleanupOldWAL(graphID string, beforeSeqNum int64) error {
-	result, err := s.db.Exec(`
+	result, err := s.exec(`
 		DELETE FROM wal_log
 		WHERE graph_id = ? AND sequence_num < ? AND replayed = 1
 	`, graphID, beforeSeqNum)
@@ -149,38 +206,94 @@ func (s *SQLiteStorage) CleanupOldWAL(graphID string, beforeSeqNum int64) error
 	return nil
 }
 
-// SaveSnapshot creates a state snapshot for fast recovery.
+// SaveSnapshot creates a state snapshot for fast recovery. Payloads are
+// gzipped when that makes them smaller. With a snapshot store configured,
+// large payloads (by uncompressed size) are written there and only a
+// reference is kept in the database.
 func (s *SQLiteStorage) SaveSnapshot(graphID string, seqNum int64, data []byte) error {
-	_, err := s.db.Exec(`
-		INSERT INTO snapshots (graph_id, sequence_num, snapshot_data)
-		VALUES (?, ?, ?)
+	external := s.externalSnapshotStore(len(data))
+	data, err := compressSnapshot(data)
+	if err != nil {
+		return err
+	}
+
+	var previous sql.NullString
+	if err := s.queryRow("SELECT snapshot_ref FROM snapshots WHERE graph_id = ?", graphID).Scan(&previous); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up previous snapshot: %w", err)
+	}
+
+	inline := data
+	var ref sql.NullString
+	if external != nil {
+		stored, err := external.Put(graphID, seqNum, data)
+		if err != nil {
+			return fmt.Errorf("failed to store snapshot externally: %w", err)
+		}
+		inline = []byte{}
+		ref = sql.NullString{String: stored, Valid: true}
+	}
+
+	_, err = s.exec(`
+		INSERT INTO snapshots (graph_id, sequence_num, snapshot_data, snapshot_ref)
+		VALUES (?, ?, ?, ?)
 		ON CONFLICT(graph_id) DO UPDATE SET
 			sequence_num = excluded.sequence_num,
 			snapshot_data = excluded.snapshot_data,
+			snapshot_ref = excluded.snapshot_ref,
 			created_at = CURRENT_TIMESTAMP
-	`, graphID, seqNum, data)
+	`, graphID, seqNum, inline, ref)
+	if err != nil {
+		if ref.Valid && ref.String != previous.String {
+			s.deleteExternalSnapshot(ref.String)
+		}
+		return err
+	}
 
-	if err == nil {
+	if previous.Valid && previous.String != ref.String {
+		s.deleteExternalSnapshot(previous.String)
+	}
+	if ref.Valid {
+		log.Printf("[Storage] Saved snapshot for graph %s at sequence %d to %s", graphID, seqNum, ref.String)
+	} else {
 		log.Printf("[Storage] Saved snapshot for graph %s at sequence %d", graphID, seqNum)
 	}
-
-	return err
+	return nil
 }
 
-// LoadSnapshot retrieves the latest snapshot for a graph.
+// LoadSnapshot retrieves the latest snapshot for a graph, reading its
+// payload from the snapshot store if it was stored externally. Compressed
+// payloads are returned decompressed.
 func (s *SQLiteStorage) LoadSnapshot(graphID string) (*Snapshot, error) {
 	var snapshot Snapshot
-	err := s.db.QueryRow(`
-		SELECT graph_id, sequence_num, snapshot_data
+	var ref sql.NullString
+	err := s.queryRow(`
+		SELECT graph_id, sequence_num, snapshot_data, snapshot_ref
 		FROM snapshots
 		WHERE graph_id = ?
-	`, graphID).Scan(&snapshot.GraphID, &snapshot.SequenceNum, &snapshot.Data)
+	`, graphID).Scan(&snapshot.GraphID, &snapshot.SequenceNum, &snapshot.Data, &ref)
 
 	if err == sql.ErrNoRows {
 		return nil, nil // No snapshot exists
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	return &snapshot, err
+	if ref.Valid && ref.String != "" {
+		store, err := s.snapshotStoreForRef(ref.String)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Data, err = store.Get(ref.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load external snapshot: %w", err)
+		}
+	}
+	snapshot.Data, err = decompressSnapshot(snapshot.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot for graph %s: %w", graphID, err)
+	}
+	return &snapshot, nil
 }
 
 // decodeWALPayload decodes the JSON payload based on mutation type.
@@ -200,6 +313,10 @@ func decodeWALPayload(mutationType MutationType, payloadJSON string) (interface{
 		payload = &AddEdgePayload{}
 	case MutationSignalReceived:
 		payload = &SignalReceivedPayload{}
+	case MutationRemoveNode:
+		payload = &RemoveNodePayload{}
+	case MutationRemoveEdge:
+		payload = &RemoveEdgePayload{}
 	default:
 		return nil, fmt.Errorf("unknown mutation type: %s", mutationType)
 	}
@@ -212,15 +329,116 @@ func decodeWALPayload(mutationType MutationType, payloadJSON string) (interface{
 }
 
 // LogMutation is a convenience method to log a mutation with automatic sequence numbering.
+// The number is reserved in the transaction inserting the entry (see
+// LogMutations), so concurrent mutations of a graph are written in sequence
+// order.
 func (s *SQLiteStorage) LogMutation(graphID string, mutationType MutationType, payload interface{}) error {
-	seqNum := s.getNextSeqNum(graphID)
-	
-	entry := &WALEntry{
-		GraphID:      graphID,
-		MutationType: mutationType,
-		Payload:      payload,
-		SequenceNum:  seqNum,
+	return s.LogMutations(graphID, []MutationRequest{{Type: mutationType, Payload: payload}})
+}
+
+// LogMutations logs several mutations of a graph in one transaction, with
+// consecutive sequence numbers in the order given. Either every entry is
+// written or none is, and a batch costs a single commit rather than one per
+// entry.
+//
+// The numbers are reserved inside the transaction: with database allocation
+// they commit or roll back with the entries, and the graph's WAL lock keeps
+// in-process allocation and insert in step. In-process reservations are
+// handed back if the transaction doesn't commit, so the series stays
+// gap-free.
+func (s *SQLiteStorage) LogMutations(graphID string, mutations []MutationRequest) error {
+	if len(mutations) == 0 {
+		return nil
+	}
+	payloads := make([]string, len(mutations))
+	for i, m := range mutations {
+		payloadJSON, err := json.Marshal(m.Payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode WAL payload: %w", err)
+		}
+		payloads[i] = string(payloadJSON)
+	}
+
+	unlock := s.lockWAL(graphID)
+	defer unlock()
+
+	ctx, cancel := s.opContext()
+	defer cancel()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return timeoutError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	allocator := s.sequenceAllocator()
+	next := allocator.Next
+	committed := false
+	if txAllocator, ok := allocator.(txSequenceAllocator); ok {
+		next = func(graphID string) (int64, error) { return txAllocator.nextTx(ctx, tx, graphID) }
+	} else if rewinder, ok := allocator.(sequenceRewinder); ok {
+		last, err := allocator.Last(graphID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if !committed {
+				rewinder.rewind(graphID, last)
+			}
+		}()
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO wal_log (graph_id, mutation_type, payload, sequence_num)
+		VALUES (?, ?, ?, ?)
+	`)
+	if err != nil {
+		return timeoutError(ctx, err)
+	}
+	defer stmt.Close()
+
+	for i, m := range mutations {
+		seqNum, err := next(graphID)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, graphID, m.Type, payloads[i], seqNum); err != nil {
+			return fmt.Errorf("failed to append WAL entry %d: %w", seqNum, timeoutError(ctx, err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return timeoutError(ctx, err)
+	}
+	committed = true
+	return nil
+}
+
+// lockWAL locks a graph's WAL for appending and returns the unlock function.
+// Graphs have separate locks so they don't contend with each other; a
+// graph's lock is dropped with the graph (see forgetWAL).
+func (s *SQLiteStorage) lockWAL(graphID string) func() {
+	mu, _ := s.walLocks.LoadOrStore(graphID, &sync.Mutex{})
+	lock := mu.(*sync.Mutex)
+	lock.Lock()
+	return lock.Unlock
+}
+
+// forgetWAL drops the WAL locks of deleted graphs, so the lock map doesn't
+// grow with every graph ever logged.
+func (s *SQLiteStorage) forgetWAL(graphIDs ...string) {
+	for _, graphID := range graphIDs {
+		s.walLocks.Delete(graphID)
 	}
+}
 
-	return s.AppendWAL(entry)
+// forgetSequences drops the in-process sequence counters of graphs whose WAL
+// entries were deleted. Graphs whose entries remain keep their counters, so
+// a graph re-created with the same ID continues its series.
+func (s *SQLiteStorage) forgetSequences(graphIDs ...string) {
+	rewinder, ok := s.sequenceAllocator().(sequenceRewinder)
+	if !ok {
+		return
+	}
+	for _, graphID := range graphIDs {
+		rewinder.forget(graphID)
+	}
 }
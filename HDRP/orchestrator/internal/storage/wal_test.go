@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLogMutations(t *testing.T) {
+	stores := map[string]Storage{
+		"sqlite": newTestStorage(t),
+		"memory": NewInMemoryStorage(),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if err := store.LogMutation("batch", MutationCreateGraph, &CreateGraphPayload{Graph: GraphState{ID: "batch"}}); err != nil {
+				t.Fatalf("LogMutation failed: %v", err)
+			}
+			batch := []MutationRequest{
+				{Type: MutationAddNode, Payload: &AddNodePayload{Node: NodeState{NodeID: "a"}}},
+				{Type: MutationAddNode, Payload: &AddNodePayload{Node: NodeState{NodeID: "b"}}},
+				{Type: MutationAddEdge, Payload: &AddEdgePayload{From: "a", To: "b"}},
+			}
+			if err := store.LogMutations("batch", batch); err != nil {
+				t.Fatalf("LogMutations failed: %v", err)
+			}
+
+			// An unencodable payload fails the whole batch
+			bad := []MutationRequest{
+				{Type: MutationAddNode, Payload: &AddNodePayload{Node: NodeState{NodeID: "c"}}},
+				{Type: MutationSignalReceived, Payload: func() {}},
+			}
+			if err := store.LogMutations("batch", bad); err == nil {
+				t.Error("Expected an error for an unencodable payload")
+			}
+
+			entries, err := store.GetUnreplayedWAL("batch")
+			if err != nil {
+				t.Fatalf("GetUnreplayedWAL failed: %v", err)
+			}
+			if len(entries) != 4 {
+				t.Fatalf("Logged %d entries, want 4", len(entries))
+			}
+			for i, entry := range entries {
+				if entry.SequenceNum != int64(i) {
+					t.Errorf("Entry %d has sequence %d", i, entry.SequenceNum)
+				}
+			}
+			if edge, ok := entries[3].Payload.(*AddEdgePayload); !ok || edge.To != "b" {
+				t.Errorf("Last entry = %+v, want the batch's edge", entries[3].Payload)
+			}
+		})
+	}
+}
+
+// walBenchmarkBatch is the WAL of persisting a 100-node chain: the graph,
+// its nodes and its edges.
+func walBenchmarkBatch() []MutationRequest {
+	mutations := []MutationRequest{{Type: MutationCreateGraph, Payload: &CreateGraphPayload{Graph: GraphState{ID: "bench"}}}}
+	for i := 0; i < 100; i++ {
+		mutations = append(mutations, MutationRequest{Type: MutationAddNode, Payload: &AddNodePayload{Node: NodeState{NodeID: fmt.Sprintf("n%d", i)}}})
+	}
+	for i := 1; i < 100; i++ {
+		mutations = append(mutations, MutationRequest{Type: MutationAddEdge, Payload: &AddEdgePayload{From: fmt.Sprintf("n%d", i-1), To: fmt.Sprintf("n%d", i)}})
+	}
+	return mutations
+}
+
+func BenchmarkLogMutation_PerEntry(b *testing.B) {
+	store := newTestStorage(b)
+	mutations := walBenchmarkBatch()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		graphID := fmt.Sprintf("bench-%d", i)
+		for _, m := range mutations {
+			if err := store.LogMutation(graphID, m.Type, m.Payload); err != nil {
+				b.Fatalf("LogMutation failed: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkLogMutations_Batch(b *testing.B) {
+	store := newTestStorage(b)
+	mutations := walBenchmarkBatch()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := store.LogMutations(fmt.Sprintf("bench-%d", i), mutations); err != nil {
+			b.Fatalf("LogMutations failed: %v", err)
+		}
+	}
+}
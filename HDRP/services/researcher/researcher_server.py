@@ -45,25 +45,13 @@ class ResearcherServicer(hdrp_services_pb2_grpc.ResearcherServiceServicer):
         
         try:
             # Validate request
-            query = request.query.strip()
-            if not query:
-                context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
-                context.set_details('Query cannot be empty or whitespace only')
-                return hdrp_services_pb2.ResearchResponse(claims=[], total_sources=0)
-            
-            if len(query) > 500:
-                context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
-                context.set_details('Query exceeds maximum length of 500 characters')
+            query = self._validate_request(request, context)
+            if query is None:
                 return hdrp_services_pb2.ResearchResponse(claims=[], total_sources=0)
             
             source_node_id = request.source_node_id or "root"
             run_id = request.run_id
             
-            if not run_id:
-                context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
-                context.set_details('run_id is required')
-                return hdrp_services_pb2.ResearchResponse(claims=[], total_sources=0)
-            
             logger.info(f"Research request: query='{query}', node={source_node_id}, run_id={run_id}")
             
             # Create researcher service instance
@@ -76,18 +64,7 @@ class ResearcherServicer(hdrp_services_pb2_grpc.ResearcherServiceServicer):
             claims = researcher.research(query, source_node_id=source_node_id)
             
             # Convert AtomicClaim objects to protobuf messages
-            pb_claims = []
-            for claim in claims:
-                pb_claim = hdrp_services_pb2.AtomicClaim(
-                    statement=claim.statement,
-                    source_url=claim.source_url,
-                    support_text=claim.support_text,
-                    source_node_id=claim.source_node_id or "",
-                    timestamp=claim.timestamp,
-                    source_title=claim.source_title or "",
-                    source_rank=claim.source_rank or 0
-                )
-                pb_claims.append(pb_claim)
+            pb_claims = [self._to_pb_claim(claim) for claim in claims]
             
             logger.info(f"Research completed: {len(pb_claims)} claims extracted")
             
@@ -136,6 +113,90 @@ class ResearcherServicer(hdrp_services_pb2_grpc.ResearcherServiceServicer):
             # Return empty claims instead of failing
             return hdrp_services_pb2.ResearchResponse(claims=[], total_sources=0)
 
+    def ResearchStream(self, request, context):
+        """Executes research on a query, streaming claims as they are extracted.
+        
+        Args:
+            request: ResearchRequest with query, source_node_id, run_id.
+            context: gRPC context.
+            
+        Yields:
+            ResearchResponse with the claims of each search result, in order.
+        """
+        from HDRP.services.shared.errors import handle_rpc_error, ResearcherError
+        
+        query = self._validate_request(request, context)
+        if query is None:
+            return
+        
+        source_node_id = request.source_node_id or "root"
+        logger.info(f"Research stream request: query='{query}', node={source_node_id}, run_id={request.run_id}")
+        
+        researcher = ResearcherService(
+            search_provider=self.search_provider,
+            run_id=request.run_id
+        )
+        sources = set()
+        total_claims = 0
+        try:
+            for claims in researcher.research_batches(query, source_node_id=source_node_id):
+                sources.update(c.source_url for c in claims)
+                total_claims += len(claims)
+                yield hdrp_services_pb2.ResearchResponse(
+                    claims=[self._to_pb_claim(claim) for claim in claims],
+                    total_sources=len(sources)
+                )
+        except Exception as e:
+            # Claims already sent stay valid; the error ends the stream
+            logger.error(f"Research stream failed: {e}", exc_info=True)
+            if not isinstance(e, (ValueError, TimeoutError)):
+                e = ResearcherError(
+                    str(e),
+                    user_message="Unable to complete research. The search service may be unavailable."
+                )
+            handle_rpc_error(
+                e, context, run_id=request.run_id, service="researcher",
+                additional_context={"query": request.query, "source_node_id": request.source_node_id}
+            )
+            return
+        
+        logger.info(f"Research stream completed: {total_claims} claims extracted")
+    
+    @staticmethod
+    def _validate_request(request, context) -> Optional[str]:
+        """Returns the request's stripped query, or None after setting an
+        INVALID_ARGUMENT status if the request is invalid."""
+        query = request.query.strip()
+        if not query:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details('Query cannot be empty or whitespace only')
+            return None
+        
+        if len(query) > 500:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details('Query exceeds maximum length of 500 characters')
+            return None
+        
+        if not request.run_id:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details('run_id is required')
+            return None
+        
+        return query
+    
+    @staticmethod
+    def _to_pb_claim(claim):
+        """Converts an AtomicClaim to its protobuf message."""
+        return hdrp_services_pb2.AtomicClaim(
+            statement=claim.statement,
+            source_url=claim.source_url,
+            support_text=claim.support_text,
+            source_node_id=claim.source_node_id or "",
+            timestamp=claim.timestamp,
+            source_title=claim.source_title or "",
+            source_rank=claim.source_rank or 0
+        )
+
 
 if __name__ == '__main__':
     run_server_main(
@@ -1,4 +1,4 @@
-from typing import List, Optional
+from typing import Iterator, List, Optional
 import time
 import asyncio
 from concurrent.futures import ThreadPoolExecutor
@@ -31,6 +31,42 @@ class ResearcherService:
         Each claim will include the source URL and the support text where it was found.
         Optimized with concurrent claim extraction.
         """
+        search_response = self._search(query, source_node_id)
+        if search_response is None:
+            return []
+
+        # Concurrent claim extraction from all search results
+        if self.enable_profiling:
+            with profile_block(f"claim_extraction_{query[:30]}", "profiling_data"):
+                all_claims = self._extract_claims_concurrent(
+                    search_response.results, source_node_id
+                )
+        else:
+            all_claims = self._extract_claims_concurrent(
+                search_response.results, source_node_id
+            )
+            
+        return all_claims
+
+    def research_batches(self, query: str, source_node_id: Optional[str] = None) -> Iterator[List[AtomicClaim]]:
+        """Performs research like research(), yielding the claims of each search
+        result as soon as they are extracted, in search result order.
+        
+        Results without claims are skipped.
+        """
+        search_response = self._search(query, source_node_id)
+        if search_response is None:
+            return
+
+        for claims in self._iter_claims_concurrent(search_response.results, source_node_id):
+            if claims:
+                yield claims
+
+    def _search(self, query: str, source_node_id: Optional[str]):
+        """Searches for query with retries.
+        
+        Returns None if the search found nothing.
+        """
         max_retries = 2
         search_response = None
         
@@ -105,26 +141,24 @@ class ResearcherService:
                 "error": "No results found",
                 "type": "EmptyResults"
             })
-            return []
+            return None
 
-        # Concurrent claim extraction from all search results
-        if self.enable_profiling:
-            with profile_block(f"claim_extraction_{query[:30]}", "profiling_data"):
-                all_claims = self._extract_claims_concurrent(
-                    search_response.results, source_node_id
-                )
-        else:
-            all_claims = self._extract_claims_concurrent(
-                search_response.results, source_node_id
-            )
-            
-        return all_claims
+        return search_response
     
     def _extract_claims_concurrent(self, results, source_node_id: Optional[str]) -> List[AtomicClaim]:
         """Extract claims from search results concurrently.
         
         Uses ThreadPoolExecutor to process multiple search results in parallel.
         """
+        all_claims = []
+        for claims in self._iter_claims_concurrent(results, source_node_id):
+            all_claims.extend(claims)
+        return all_claims
+
+    def _iter_claims_concurrent(self, results, source_node_id: Optional[str]) -> Iterator[List[AtomicClaim]]:
+        """Yields the claims of each search result in order, extracting them
+        concurrently.
+        """
         def extract_from_result(idx_and_result):
             idx, result = idx_and_result
             try:
@@ -160,7 +194,6 @@ class ResearcherService:
         
         # Process results concurrently
         indexed_results = list(enumerate(results, 1))
-        all_claims = []
         
         # Use thread pool to parallelize claim extraction
         futures = [
@@ -170,15 +203,12 @@ class ResearcherService:
         
         for future in futures:
             try:
-                claims = future.result(timeout=10)
-                all_claims.extend(claims)
+                yield future.result(timeout=10)
             except Exception as e:
                 self.logger.log("extraction_error", {
                     "error": str(e),
                     "type": type(e).__name__
                 })
-        
-        return all_claims
     
     def __del__(self):
         """Cleanup thread pool on deletion."""
@@ -27,6 +27,16 @@ class TestResearcherService(unittest.TestCase):
             self.assertEqual(claim.statement, claim.support_text)
             # print(f"Claim: {claim.statement}")
 
+    def test_research_batches_match_research(self):
+        query = "quantum computing"
+        batches = list(self.researcher.research_batches(query))
+
+        self.assertGreater(len(batches), 0)
+        for batch in batches:
+            self.assertGreater(len(batch), 0)
+        streamed = [claim.statement for batch in batches for claim in batch]
+        self.assertEqual(streamed, [claim.statement for claim in self.researcher.research(query)])
+
     def test_research_failure_logging(self):
         # Mock the search provider to raise an exception
         self.search_provider.search = Mock(side_effect=Exception("Simulated API Error"))
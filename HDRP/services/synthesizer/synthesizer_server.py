@@ -18,6 +18,9 @@ from HDRP.services.shared.telemetry import trace_rpc, add_span_attributes
 logging.basicConfig(level=logging.INFO)
 logger = logging.getLogger(__name__)
 
+# Upper bound on the characters of report sent in one streamed response
+REPORT_CHUNK_CHARS = 16 * 1024
+
 
 class SynthesizerServicer(hdrp_services_pb2_grpc.SynthesizerServiceServicer):
     """Implements SynthesizerService gRPC interface."""
@@ -64,27 +67,7 @@ class SynthesizerServicer(hdrp_services_pb2_grpc.SynthesizerServiceServicer):
             logger.info(f"Synthesize request: run_id={run_id}, results={len(request.verification_results)}")
             
             # Convert protobuf verification results to CritiqueResult objects
-            critique_results = []
-            for pb_result in request.verification_results:
-                # Convert protobuf claim to AtomicClaim
-                claim = AtomicClaim(
-                    statement=pb_result.claim.statement,
-                    source_url=pb_result.claim.source_url,
-                    support_text=pb_result.claim.support_text,
-                    source_node_id=pb_result.claim.source_node_id or None,
-                    timestamp=pb_result.claim.timestamp,
-                    source_title=pb_result.claim.source_title or None,
-                    source_rank=pb_result.claim.source_rank if pb_result.claim.source_rank > 0 else None
-                )
-                
-                # Create CritiqueResult
-                result = CritiqueResult(
-                    claim=claim,
-                    is_valid=pb_result.is_valid,
-                    reason=pb_result.reasoning,
-                    entailment_score=getattr(pb_result, 'entailment_score', 0.0)
-                )
-                critique_results.append(result)
+            critique_results = self._to_critique_results(request)
             
             # Synthesize report with error handling (supports partial results)
             report = self.synthesizer.synthesize(
@@ -150,6 +133,93 @@ class SynthesizerServicer(hdrp_services_pb2_grpc.SynthesizerServiceServicer):
                 artifact_uri=""
             )
 
+    def SynthesizeStream(self, request, context):
+        """Synthesizes verified claims into a markdown report, streamed in chunks.
+        
+        Args:
+            request: SynthesizeRequest with verification results and context.
+            context: gRPC context.
+            
+        Yields:
+            SynthesizeResponse with the next chunk of the report.
+        """
+        from HDRP.services.shared.errors import handle_rpc_error, SynthesizerError
+        
+        if not request.verification_results:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details('At least one verification result is required')
+            return
+        if not request.run_id:
+            context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+            context.set_details('run_id is required')
+            return
+        
+        logger.info(f"Synthesize stream request: run_id={request.run_id}, results={len(request.verification_results)}")
+        try:
+            report = self.synthesizer.synthesize(
+                verification_results=self._to_critique_results(request),
+                context=dict(request.context) if request.context else {},
+                graph_data=None,
+                run_id=request.run_id
+            )
+        except Exception as e:
+            logger.error(f"Synthesis failed: {e}", exc_info=True)
+            if not isinstance(e, (ValueError, TimeoutError)):
+                e = SynthesizerError(
+                    str(e),
+                    user_message="Unable to generate complete report. Partial results may be available."
+                )
+            handle_rpc_error(
+                e, context, run_id=request.run_id, service="synthesizer",
+                additional_context={"result_count": len(request.verification_results)}
+            )
+            return
+        
+        for chunk in report_chunks(report, REPORT_CHUNK_CHARS):
+            yield hdrp_services_pb2.SynthesizeResponse(report=chunk)
+        logger.info(f"Synthesis stream completed: report length={len(report)} chars")
+    
+    @staticmethod
+    def _to_critique_results(request):
+        """Converts the request's verification results to CritiqueResult objects."""
+        critique_results = []
+        for pb_result in request.verification_results:
+            # Convert protobuf claim to AtomicClaim
+            claim = AtomicClaim(
+                statement=pb_result.claim.statement,
+                source_url=pb_result.claim.source_url,
+                support_text=pb_result.claim.support_text,
+                source_node_id=pb_result.claim.source_node_id or None,
+                timestamp=pb_result.claim.timestamp,
+                source_title=pb_result.claim.source_title or None,
+                source_rank=pb_result.claim.source_rank if pb_result.claim.source_rank > 0 else None
+            )
+            
+            # Create CritiqueResult
+            result = CritiqueResult(
+                claim=claim,
+                is_valid=pb_result.is_valid,
+                reason=pb_result.reasoning,
+                entailment_score=getattr(pb_result, 'entailment_score', 0.0)
+            )
+            critique_results.append(result)
+        return critique_results
+
+
+def report_chunks(report: str, max_chars: int):
+    """Splits report into chunks of at most max_chars, breaking after a
+    newline where one falls in the chunk.
+    """
+    start = 0
+    while start < len(report):
+        end = min(start + max_chars, len(report))
+        if end < len(report):
+            newline = report.rfind("\n", start, end)
+            if newline >= start:
+                end = newline + 1
+        yield report[start:end]
+        start = end
+
 
 if __name__ == '__main__':
     run_server_main(